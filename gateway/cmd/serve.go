@@ -11,6 +11,7 @@ import (
 	"github.com/subnova/slog-exporter/slogtrace"
 	"github.com/zynka-tech/zynka-csms/gateway/registry"
 	"github.com/zynka-tech/zynka-csms/gateway/server"
+	"github.com/zynka-tech/zynka-csms/gateway/transport/nats"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
@@ -27,18 +28,23 @@ import (
 )
 
 var (
-	mqttAddr          string
-	wsAddr            string
-	wssAddr           string
-	statusAddr        string
-	tlsServerCert     string
-	tlsServerKey      string
-	tlsTrustCert      []string
-	orgNames          []string
-	managerApiAddr    string
-	trustProxyHeaders bool
-	otelCollectorAddr string
-	logFormat         string
+	transportType      string
+	mqttAddr           string
+	natsAddr           string
+	mqttPoolSize       int
+	mqttClientIdPrefix string
+	wsAddr             string
+	wssAddr            string
+	statusAddr         string
+	tlsServerCert      string
+	tlsServerKey       string
+	tlsTrustCert       []string
+	orgNames           []string
+	managerApiAddr     string
+	trustProxyHeaders  bool
+	otelCollectorAddr  string
+	logFormat          string
+	gatewayId          string
 )
 
 // Initializes an OTLP exporter, and configures the corresponding trace and
@@ -130,22 +136,45 @@ var serveCmd = &cobra.Command{
 
 		tracer := otel.Tracer("gateway")
 
-		brokerUrl, err := url.Parse(mqttAddr)
-		if err != nil {
-			return fmt.Errorf("parsing mqtt broker url: %v", err)
-		}
-
 		remoteRegistry := registry.RemoteRegistry{
 			ManagerApiAddr: managerApiAddr,
 		}
-		statusServer := server.New("status", statusAddr, nil, server.NewStatusHandler())
-		websocketHandler := server.NewWebsocketHandler(
-			server.WithMqttBrokerUrl(brokerUrl),
-			server.WithMqttTopicPrefix("cs"),
+		websocketOpts := []server.WebsocketOpt{
 			server.WithDeviceRegistry(remoteRegistry),
 			server.WithOrgNames(orgNames),
 			server.WithTrustProxyHeaders(trustProxyHeaders),
-			server.WithOtelTracer(tracer))
+			server.WithOtelTracer(tracer),
+			server.WithGatewayId(gatewayId),
+		}
+
+		switch transportType {
+		case "mqtt":
+			brokerUrl, err := url.Parse(mqttAddr)
+			if err != nil {
+				return fmt.Errorf("parsing mqtt broker url: %v", err)
+			}
+			websocketOpts = append(websocketOpts,
+				server.WithMqttBrokerUrl(brokerUrl),
+				server.WithMqttTopicPrefix("cs"),
+				server.WithMqttPoolSize(mqttPoolSize),
+				server.WithMqttClientIdPrefix(mqttClientIdPrefix))
+		case "nats":
+			natsUrl, err := url.Parse(natsAddr)
+			if err != nil {
+				return fmt.Errorf("parsing nats server url: %v", err)
+			}
+			natsTransport := nats.NewTransport(
+				nats.WithNatsUrls([]*url.URL{natsUrl}),
+				nats.WithNatsPrefix("cs"),
+				nats.WithGatewayId(gatewayId),
+				nats.WithOtelTracer(tracer))
+			websocketOpts = append(websocketOpts, server.WithTransport(natsTransport, natsTransport))
+		default:
+			return fmt.Errorf("unknown transport type: %s", transportType)
+		}
+
+		statusServer := server.New("status", statusAddr, nil, server.NewStatusHandler())
+		websocketHandler := server.NewWebsocketHandler(websocketOpts...)
 		wsServer := server.New("ws", wsAddr, nil, websocketHandler)
 		var wssServer *server.Server
 
@@ -207,8 +236,16 @@ var serveCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
+	serveCmd.Flags().StringVar(&transportType, "transport", "mqtt",
+		"The transport used to exchange messages with the manager, one of [mqtt, nats]")
 	serveCmd.Flags().StringVarP(&mqttAddr, "mqtt-addr", "m", "mqtt://127.0.0.1:1883",
 		"The address of the MQTT broker, e.g. mqtt://127.0.0.1:1883")
+	serveCmd.Flags().IntVar(&mqttPoolSize, "mqtt-pool-size", 4,
+		"The number of MQTT connections shared by the connected charge stations")
+	serveCmd.Flags().StringVar(&mqttClientIdPrefix, "mqtt-client-id-prefix", "",
+		"The prefix of the MQTT client ids of the connection pool, which must be unique to each gateway (default: a random prefix)")
+	serveCmd.Flags().StringVar(&natsAddr, "nats-addr", "nats://127.0.0.1:4222",
+		"The address of the NATS server, e.g. nats://127.0.0.1:4222")
 	serveCmd.Flags().StringVarP(&wsAddr, "ws-addr", "a", "127.0.0.1:9310",
 		"The address that the insecure websocket server will listen on for connections, e.g. 127.0.0.1:9310")
 	serveCmd.Flags().StringVarP(&wssAddr, "wss-addr", "w", "",
@@ -231,4 +268,6 @@ func init() {
 		"The address of the open telemetry collector that will receive traces, e.g. localhost:4317")
 	serveCmd.Flags().StringVar(&logFormat, "log-format", "text",
 		"The format of the logs, one of [text, json]")
+	serveCmd.Flags().StringVar(&gatewayId, "gateway-id", "",
+		"The identifier of this gateway that is reported to the manager when charge stations connect (default: the hostname)")
 }
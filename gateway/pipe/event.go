@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipe
+
+import "time"
+
+type ConnectionEventType string
+
+const (
+	ConnectionEventConnected    ConnectionEventType = "Connected"
+	ConnectionEventDisconnected ConnectionEventType = "Disconnected"
+)
+
+// ConnectionEvent is sent to the CSMS when a charge station connects to, or
+// disconnects from, a gateway.
+type ConnectionEvent struct {
+	Type ConnectionEventType `json:"type"`
+	// GatewayId identifies the gateway instance that the charge station is
+	// connected to
+	GatewayId string `json:"gateway_id"`
+	// ConnectionId identifies the websocket connection: it is the same for the
+	// Connected and Disconnected events of a connection
+	ConnectionId    string    `json:"connection_id"`
+	Protocol        string    `json:"protocol"`
+	RemoteAddr      string    `json:"remote_addr,omitempty"`
+	SecurityProfile int       `json:"security_profile"`
+	CertificateHash string    `json:"certificate_hash,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
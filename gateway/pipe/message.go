@@ -18,4 +18,7 @@ type GatewayMessage struct {
 	ErrorCode        ocpp.ErrorCode   `json:"error_code,omitempty"`
 	ErrorDescription string           `json:"error_description,omitempty"`
 	State            json.RawMessage  `json:"state,omitempty"`
+	// Event is set, instead of the OCPP fields, when the message reports a
+	// change to the charge station's connection to the gateway
+	Event *ConnectionEvent `json:"event,omitempty"`
 }
@@ -4,29 +4,31 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
-	"github.com/eclipse/paho.golang/autopaho"
-	"github.com/eclipse/paho.golang/paho"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/zynka-tech/zynka-csms/gateway/ocpp"
 	"github.com/zynka-tech/zynka-csms/gateway/pipe"
 	"github.com/zynka-tech/zynka-csms/gateway/registry"
+	"github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/gateway/transport/mqtt"
 	"golang.org/x/exp/slices"
 	"golang.org/x/exp/slog"
 	"nhooyr.io/websocket"
@@ -38,11 +40,16 @@ type WebsocketHandler struct {
 	mqttConnectTimeout    time.Duration
 	mqttConnectRetryDelay time.Duration
 	mqttKeepAliveInterval uint16
+	mqttPoolSize          int
+	mqttClientIdPrefix    string
+	emitter               transport.Emitter
+	listener              transport.Listener
 	deviceRegistry        registry.DeviceRegistry
 	orgNames              []string
 	pipeOptions           []pipe.Opt
 	trustProxyHeaders     bool
 	tracer                trace.Tracer
+	gatewayId             string
 }
 
 type WebsocketOpt func(handler *WebsocketHandler)
@@ -83,6 +90,32 @@ func WithMqttConnectSettings(mqttConnectTimeout, mqttConnectRetryDelay, mqttKeep
 	}
 }
 
+// WithMqttPoolSize sets the number of MQTT connections that are shared by the
+// charge stations connected to the gateway.
+func WithMqttPoolSize(mqttPoolSize int) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.mqttPoolSize = mqttPoolSize
+	}
+}
+
+// WithMqttClientIdPrefix sets the prefix of the client ids of the MQTT
+// connections of the pool. The prefix must be unique to each gateway.
+func WithMqttClientIdPrefix(clientIdPrefix string) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.mqttClientIdPrefix = clientIdPrefix
+	}
+}
+
+// WithTransport sets the transport used to exchange messages with the manager:
+// the default is a pool of MQTT connections configured with the WithMqttXxx
+// options.
+func WithTransport(emitter transport.Emitter, listener transport.Listener) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.emitter = emitter
+		handler.listener = listener
+	}
+}
+
 func WithDeviceRegistry(deviceRegistry registry.DeviceRegistry) WebsocketOpt {
 	return func(handler *WebsocketHandler) {
 		handler.deviceRegistry = deviceRegistry
@@ -119,6 +152,14 @@ func WithPipeOptions(pipeOption []pipe.Opt) WebsocketOpt {
 	}
 }
 
+// WithGatewayId sets the identifier of the gateway instance that is reported
+// to the CSMS when charge stations connect: the default is the hostname.
+func WithGatewayId(gatewayId string) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.gatewayId = gatewayId
+	}
+}
+
 func WithOtelTracer(tracer trace.Tracer) WebsocketOpt {
 	return func(handler *WebsocketHandler) {
 		handler.tracer = tracer
@@ -172,6 +213,26 @@ func ensureDefaults(handler *WebsocketHandler) {
 	if handler.tracer == nil {
 		handler.tracer = trace.NewNoopTracerProvider().Tracer("")
 	}
+
+	if handler.gatewayId == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			panic(err)
+		}
+		handler.gatewayId = hostname
+	}
+
+	if handler.emitter == nil || handler.listener == nil {
+		pool := mqtt.NewPool(
+			mqtt.WithBrokerUrls(handler.mqttBrokerURLs),
+			mqtt.WithTopicPrefix(handler.mqttTopicPrefix),
+			mqtt.WithConnectSettings(handler.mqttConnectRetryDelay, handler.mqttKeepAliveInterval),
+			mqtt.WithPoolSize(handler.mqttPoolSize),
+			mqtt.WithClientIdPrefix(handler.mqttClientIdPrefix),
+			mqtt.WithOtelTracer(handler.tracer))
+		handler.emitter = pool
+		handler.listener = pool
+	}
 }
 
 func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -263,97 +324,45 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	mqttBrokerURLStrings := make([]string, len(s.mqttBrokerURLs))
-	for i, u := range s.mqttBrokerURLs {
-		mqttBrokerURLStrings[i] = u.String()
-	}
-	span.SetAttributes(attribute.StringSlice("mqtt.broker_urls", mqttBrokerURLStrings))
-
-	var mqttConn *autopaho.ConnectionManager
-	mqttConn, err = autopaho.NewConnection(ctx, autopaho.ClientConfig{
-		BrokerUrls:        s.mqttBrokerURLs,
-		KeepAlive:         s.mqttKeepAliveInterval,
-		ConnectRetryDelay: s.mqttConnectRetryDelay,
-		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
-			topicName := fmt.Sprintf("%s/out/%s/%s", s.mqttTopicPrefix, protocol, clientId)
-			span.SetAttributes(attribute.String("mqtt.topic", topicName))
-			_, err = manager.Subscribe(ctx, &paho.Subscribe{
-				Subscriptions: map[string]paho.SubscribeOptions{
-					topicName: {},
-				},
-			})
-			if err != nil {
-				span.SetStatus(codes.Error, "subscribing to mqtt topic failed")
-				span.RecordError(err)
-				span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
-
-				_ = wsConn.Close(websocket.StatusProtocolError, http.StatusText(http.StatusInternalServerError))
-				return
-			}
-		},
-		ClientConfig: paho.ClientConfig{
-			ClientID: clientId,
-			Router: paho.NewSingleHandlerRouter(func(mqttMsg *paho.Publish) {
-				// route requests from the CSMS
-				var msg pipe.GatewayMessage
-				err := json.Unmarshal(mqttMsg.Payload, &msg)
-				if err != nil {
-					slog.Error("unmarshalling CSMS message", "err", err)
-					return
-				}
-
-				correlationMap := make(map[string]string)
-				err = json.Unmarshal(mqttMsg.Properties.CorrelationData, &correlationMap)
-				if err != nil {
-					slog.Warn("unmarshalling correlation map", "err", err)
-				}
-				requestContext := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(correlationMap))
-
-				newCtx, span := s.tracer.Start(requestContext, fmt.Sprintf("%s/out/%s/# receive", s.mqttTopicPrefix, protocol),
-					trace.WithSpanKind(trace.SpanKindConsumer),
-					trace.WithAttributes(
-						semconv.MessagingSystem("mqtt"),
-						semconv.MessagingMessagePayloadSizeBytes(len(mqttMsg.Payload)),
-						semconv.MessagingMessageConversationID(msg.MessageId),
-						semconv.MessagingOperationKey.String("receive"),
-						attribute.String("csId", clientId),
-					))
-				defer span.End()
-
-				msg.Context = newCtx
-
-				p.CSMSRx <- &msg
-			}),
-			OnServerDisconnect: func(disconnect *paho.Disconnect) {
-				span.SetAttributes(attribute.String("mqtt.disconnect_reason", disconnect.Properties.ReasonString))
-			},
-		},
-	})
+	ocppVersion := transport.OcppVersion(protocol)
+	conn, err := s.listener.Connect(ctx, ocppVersion, clientId, transport.MessageHandlerFunc(func(_ context.Context, _ string, msg *pipe.GatewayMessage) {
+		// the transport may be shared with the other charge stations so it must
+		// not wait for a charge station that is not keeping up
+		select {
+		case p.CSMSRx <- msg:
+		default:
+			slog.Warn("discarding CSMS message: queue is full", "csId", clientId, "messageId", msg.MessageId)
+		}
+	}))
 	if err != nil {
-		span.SetStatus(codes.Error, "connecting to mqtt")
+		span.SetStatus(codes.Error, "connecting to transport")
 		span.RecordError(err)
-		span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
-		slog.Error("connecting to mqtt", "mqttBrokerURLs", s.mqttBrokerURLs, "err", err)
+		slog.Error("connecting to transport", "err", err)
 		_ = wsConn.Close(websocket.StatusProtocolError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 	defer func() {
-		err := mqttConn.Disconnect(context.Background())
+		err := conn.Disconnect(context.Background())
 		if err != nil {
-			slog.Error("disconnecting from mqtt", "err", err)
+			slog.Error("disconnecting from transport", "err", err)
 		}
 	}()
-	err = mqttConn.AwaitConnection(ctx)
-	if err != nil {
-		slog.Error("waiting for mqtt", "mqttBrokerURLs", s.mqttBrokerURLs, "err", err)
-		return
-	}
+
+	// let the CSMS know the charge station is connected through this gateway
+	event := s.newConnectionEvent(r, protocol, cs.SecurityProfile)
+	emitConnectionEvent(ctx, s.emitter, ocppVersion, clientId, event)
+	defer func() {
+		disconnected := *event
+		disconnected.Type = pipe.ConnectionEventDisconnected
+		disconnected.Timestamp = time.Now().UTC()
+		emitConnectionEvent(context.Background(), s.emitter, ocppVersion, clientId, &disconnected)
+	}()
 
 	// we've finished connecting... complete this span so we get to see the details in the trace
 	span.End()
 
 	// listen on the CSMS Tx channel and publish those messages on the inbound topic
-	goPublishToCSMS(ctx, s.tracer, p.CSMSTx, mqttConn, s.mqttTopicPrefix, protocol, clientId)
+	goPublishToCSMS(ctx, p.CSMSTx, s.emitter, ocppVersion, clientId)
 
 	// listen the CS Tx channel and write those messages to the websocket
 	goWriteToChargeStation(ctx, s.tracer, p.ChargeStationTx, wsConn, protocol, clientId)
@@ -362,6 +371,51 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	readFromChargeStation(ctx, s.tracer, wsConn, p.ChargeStationRx, p.ChargeStationTx, protocol, clientId)
 }
 
+// newConnectionEvent returns the event that reports the charge station is
+// connected using the request
+func (s *WebsocketHandler) newConnectionEvent(r *http.Request, protocol string, securityProfile registry.SecurityProfile) *pipe.ConnectionEvent {
+	connectionId := make([]byte, 8)
+	_, err := rand.Read(connectionId)
+	if err != nil {
+		panic(err)
+	}
+
+	remoteAddr := r.RemoteAddr
+	if s.trustProxyHeaders {
+		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			remoteAddr, _, _ = strings.Cut(forwardedFor, ",")
+			remoteAddr = strings.TrimSpace(remoteAddr)
+		}
+	}
+
+	var certificateHash string
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		hash := sha256.Sum256(r.TLS.PeerCertificates[0].Raw)
+		certificateHash = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+
+	return &pipe.ConnectionEvent{
+		Type:            pipe.ConnectionEventConnected,
+		GatewayId:       s.gatewayId,
+		ConnectionId:    hex.EncodeToString(connectionId),
+		Protocol:        protocol,
+		RemoteAddr:      remoteAddr,
+		SecurityProfile: int(securityProfile),
+		CertificateHash: certificateHash,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func emitConnectionEvent(ctx context.Context, emitter transport.Emitter, ocppVersion transport.OcppVersion, clientId string, event *pipe.ConnectionEvent) {
+	err := emitter.Emit(ctx, ocppVersion, clientId, &pipe.GatewayMessage{
+		Context: ctx,
+		Event:   event,
+	})
+	if err != nil {
+		slog.Warn("publishing connection event", "csId", clientId, "event", event.Type, "err", err)
+	}
+}
+
 func getScheme(r *http.Request) string {
 	if r.TLS != nil {
 		return "wss"
@@ -431,17 +485,12 @@ func checkCertificate(ctx context.Context, r *http.Request, orgNames []string, c
 	return foundOrg
 }
 
-func goPublishToCSMS(ctx context.Context, tracer trace.Tracer, csmsTx chan *pipe.GatewayMessage, mqttConn *autopaho.ConnectionManager, topicPrefix, protocol, clientId string) {
+func goPublishToCSMS(ctx context.Context, csmsTx chan *pipe.GatewayMessage, emitter transport.Emitter, ocppVersion transport.OcppVersion, clientId string) {
 	go func() {
 		for {
 			select {
 			case msg := <-csmsTx:
-				data, err := json.Marshal(msg)
-				if err != nil {
-					slog.Error("marshaling message for publication", "err", err)
-					continue
-				}
-				err = publish(msg.Context, tracer, mqttConn, topicPrefix, protocol, clientId, msg.MessageId, data)
+				err := emitter.Emit(msg.Context, ocppVersion, clientId, msg)
 				if err != nil {
 					slog.Error("publishing message", "err", err)
 				}
@@ -452,42 +501,6 @@ func goPublishToCSMS(ctx context.Context, tracer trace.Tracer, csmsTx chan *pipe
 	}()
 }
 
-func publish(ctx context.Context, tracer trace.Tracer, mqttConn *autopaho.ConnectionManager, topicPrefix, protocol, clientId, messageId string, data []byte) error {
-	topic := fmt.Sprintf("%s/in/%s/%s", topicPrefix, protocol, clientId)
-
-	newCtx, span := tracer.Start(ctx,
-		fmt.Sprintf("%s/in/%s/# publish", topicPrefix, protocol),
-		trace.WithSpanKind(trace.SpanKindProducer),
-		trace.WithAttributes(
-			semconv.MessagingSystem("mqtt"),
-			semconv.MessagingMessagePayloadSizeBytes(len(data)),
-			semconv.MessagingOperationKey.String("publish"),
-			semconv.MessagingMessageConversationID(messageId),
-			attribute.String("csId", clientId),
-		))
-	defer span.End()
-
-	correlationMap := make(map[string]string)
-	otel.GetTextMapPropagator().Inject(newCtx, propagation.MapCarrier(correlationMap))
-
-	correlationData, err := json.Marshal(correlationMap)
-	if err != nil {
-		slog.Warn("marshalling correlation map: %v", err)
-	}
-
-	_, err = mqttConn.Publish(newCtx, &paho.Publish{
-		Topic:   topic,
-		Payload: data,
-		Properties: &paho.PublishProperties{
-			ContentType:     "application/json",
-			ResponseTopic:   fmt.Sprintf("%s/out/%s/%s", topicPrefix, protocol, clientId),
-			CorrelationData: correlationData,
-		},
-	})
-
-	return err
-}
-
 func goWriteToChargeStation(ctx context.Context, tracer trace.Tracer, chargeStationTx chan *pipe.GatewayMessage, wsConn *websocket.Conn, protocol, clientId string) {
 	go func() {
 		for {
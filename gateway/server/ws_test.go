@@ -15,17 +15,23 @@ import (
 	"fmt"
 	"github.com/eclipse/paho.golang/autopaho"
 	"github.com/eclipse/paho.golang/paho"
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zynka-tech/zynka-csms/gateway/ocpp"
 	"github.com/zynka-tech/zynka-csms/gateway/pipe"
 	"github.com/zynka-tech/zynka-csms/gateway/registry"
 	"github.com/zynka-tech/zynka-csms/gateway/server"
+	"github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/gateway/transport/nats"
 	"math"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"nhooyr.io/websocket"
+	"sync"
 	"testing"
 	"time"
 )
@@ -67,6 +73,10 @@ func TestWebSocketHandler(t *testing.T) {
 				var reqMsg pipe.GatewayMessage
 				err := json.Unmarshal(publish.Payload, &reqMsg)
 				require.NoError(t, err)
+				if reqMsg.Event != nil {
+					// connection events do not have a response
+					return
+				}
 
 				respMsg := pipe.GatewayMessage{
 					MessageType:     ocpp.MessageTypeCallResult,
@@ -179,6 +189,350 @@ func TestWebSocketHandler(t *testing.T) {
 	}
 }
 
+func TestWebSocketHandlerSharesMqttConnections(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	// simulate manager connection: the response payload identifies the charge station
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					"cs/in/ocpp2.0.1/+": {},
+				},
+			})
+			require.NoError(t, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+			Router: paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+				var reqMsg pipe.GatewayMessage
+				err := json.Unmarshal(publish.Payload, &reqMsg)
+				require.NoError(t, err)
+				if reqMsg.Event != nil {
+					// connection events do not have a response
+					return
+				}
+
+				csId := publish.Topic[len("cs/in/ocpp2.0.1/"):]
+				respMsg := pipe.GatewayMessage{
+					MessageType:     ocpp.MessageTypeCallResult,
+					MessageId:       reqMsg.MessageId,
+					ResponsePayload: json.RawMessage(fmt.Sprintf(`"%s"`, csId)),
+				}
+
+				b, err := json.Marshal(respMsg)
+				require.NoError(t, err)
+				err = broker.Publish(publish.Properties.ResponseTopic, b, false, 0)
+				require.NoError(t, err)
+			}),
+		},
+	})
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	mockRegistry := registry.NewMockRegistry()
+	csIds := []string{"cs1", "cs2", "cs3"}
+	for _, csId := range csIds {
+		mockRegistry.ChargeStations[csId] = &registry.ChargeStation{
+			ClientId:             csId,
+			SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+			Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+		}
+	}
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithMqttBrokerUrl(addr),
+		server.WithMqttTopicPrefix("cs"),
+		server.WithMqttPoolSize(2),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithMqttConnectSettings(15*time.Second, 15*time.Second, 5*time.Second)))
+	defer srv.Close()
+
+	conns := make(map[string]*websocket.Conn)
+	for _, csId := range csIds {
+		authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", csId, "password")))
+		conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, csId), &websocket.DialOptions{
+			Subprotocols: []string{"ocpp2.0.1"},
+			HTTPHeader: http.Header{
+				"authorization": []string{authHeader},
+			},
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close(websocket.StatusNormalClosure, "OK")
+		}()
+		conns[csId] = conn
+	}
+
+	for _, csId := range csIds {
+		data, err := json.Marshal(ocpp.Message{
+			MessageTypeId: ocpp.MessageTypeCall,
+			MessageId:     "1",
+			Data: []json.RawMessage{
+				json.RawMessage(`"EchoRequest"`),
+				json.RawMessage(`"Payload"`),
+			},
+		})
+		require.NoError(t, err)
+		err = conns[csId].Write(ctx, websocket.MessageText, data)
+		require.NoError(t, err)
+	}
+
+	for _, csId := range csIds {
+		_, b, err := conns[csId].Read(ctx)
+		require.NoError(t, err)
+		var msg ocpp.Message
+		err = json.Unmarshal(b, &msg)
+		require.NoError(t, err)
+		require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageTypeId)
+		require.Equal(t, fmt.Sprintf(`"%s"`, csId), string(msg.Data[0]))
+	}
+
+	// the manager and the pool's connections
+	require.Eventually(t, func() bool {
+		return broker.Clients.Len() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketHandlerDeliversMqttMessagesInOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	// simulate manager connection: the response is followed by a number of calls
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					"cs/in/ocpp2.0.1/+": {},
+				},
+			})
+			require.NoError(t, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+			Router: paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+				var reqMsg pipe.GatewayMessage
+				err := json.Unmarshal(publish.Payload, &reqMsg)
+				require.NoError(t, err)
+				if reqMsg.Event != nil || reqMsg.MessageType != ocpp.MessageTypeCall {
+					return
+				}
+
+				msgs := []pipe.GatewayMessage{{
+					MessageType:     ocpp.MessageTypeCallResult,
+					MessageId:       reqMsg.MessageId,
+					ResponsePayload: json.RawMessage(`{}`),
+				}}
+				for i := 0; i < 5; i++ {
+					msgs = append(msgs, pipe.GatewayMessage{
+						MessageType:    ocpp.MessageTypeCall,
+						Action:         "Reset",
+						MessageId:      fmt.Sprintf("%d", i),
+						RequestPayload: json.RawMessage(`{}`),
+					})
+				}
+				for _, msg := range msgs {
+					b, err := json.Marshal(msg)
+					require.NoError(t, err)
+					err = broker.Publish(publish.Properties.ResponseTopic, b, false, 0)
+					require.NoError(t, err)
+				}
+			}),
+		},
+	})
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithMqttBrokerUrl(addr),
+		server.WithMqttTopicPrefix("cs"),
+		server.WithMqttPoolSize(4),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithMqttConnectSettings(15*time.Second, 15*time.Second, 5*time.Second)))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte("cs1:password"))
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	data, err := json.Marshal(ocpp.Message{
+		MessageTypeId: ocpp.MessageTypeCall,
+		MessageId:     "call",
+		Data: []json.RawMessage{
+			json.RawMessage(`"Heartbeat"`),
+			json.RawMessage(`{}`),
+		},
+	})
+	require.NoError(t, err)
+	err = conn.Write(ctx, websocket.MessageText, data)
+	require.NoError(t, err)
+
+	var messageIds []string
+	for len(messageIds) < 6 {
+		_, b, err := conn.Read(ctx)
+		require.NoError(t, err)
+		var msg ocpp.Message
+		err = json.Unmarshal(b, &msg)
+		require.NoError(t, err)
+		messageIds = append(messageIds, msg.MessageId)
+		if msg.MessageTypeId != ocpp.MessageTypeCall {
+			continue
+		}
+
+		data, err := json.Marshal(ocpp.Message{
+			MessageTypeId: ocpp.MessageTypeCallResult,
+			MessageId:     msg.MessageId,
+			Data: []json.RawMessage{
+				json.RawMessage(`{"status":"Accepted"}`),
+			},
+		})
+		require.NoError(t, err)
+		err = conn.Write(ctx, websocket.MessageText, data)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"call", "0", "1", "2", "3", "4"}, messageIds)
+}
+
+func TestWebSocketHandlerWithNatsTransport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	_, addr := nats.NewServer(t)
+
+	// simulate manager connection
+	nc, err := natsclient.Connect(addr.String())
+	require.NoError(t, err)
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	_, err = nc.Subscribe("cs.in.ocpp201.*", func(natsMsg *natsclient.Msg) {
+		var reqMsg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &reqMsg)
+		require.NoError(t, err)
+		if reqMsg.Event != nil {
+			// connection events do not have a response
+			return
+		}
+
+		respMsg := pipe.GatewayMessage{
+			MessageType:     ocpp.MessageTypeCallResult,
+			MessageId:       reqMsg.MessageId,
+			ResponsePayload: reqMsg.RequestPayload,
+		}
+
+		b, err := json.Marshal(respMsg)
+		require.NoError(t, err)
+		_, err = js.Publish(ctx, "cs.out.ocpp201.cs1", b)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	natsTransport := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}))
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithTransport(natsTransport, natsTransport),
+		server.WithDeviceRegistry(mockRegistry)))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte("cs1:password"))
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	data, err := json.Marshal(ocpp.Message{
+		MessageTypeId: ocpp.MessageTypeCall,
+		MessageId:     "1",
+		Data: []json.RawMessage{
+			json.RawMessage(`"EchoRequest"`),
+			json.RawMessage(`"Payload"`),
+		},
+	})
+	require.NoError(t, err)
+	err = conn.Write(ctx, websocket.MessageText, data)
+	require.NoError(t, err)
+
+	_, b, err := conn.Read(ctx)
+	require.NoError(t, err)
+	var msg ocpp.Message
+	err = json.Unmarshal(b, &msg)
+	require.NoError(t, err)
+	require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageTypeId)
+	require.Equal(t, `"Payload"`, string(msg.Data[0]))
+}
+
 func TestConnectionFromUnknownChargeStation(t *testing.T) {
 	//defer goleak.VerifyNone(t)
 
@@ -1001,3 +1355,90 @@ func createTestKeyPairs(t *testing.T, clientId string) (*x509.Certificate, *ecds
 
 	return caCert, caKeyPair, clientCert, clientKeyPair
 }
+
+type recordingTransport struct {
+	mu       sync.Mutex
+	messages []*pipe.GatewayMessage
+}
+
+func (r *recordingTransport) Emit(_ context.Context, _ transport.OcppVersion, _ string, message *pipe.GatewayMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *recordingTransport) Connect(context.Context, transport.OcppVersion, string, transport.MessageHandler) (transport.Connection, error) {
+	return r, nil
+}
+
+func (r *recordingTransport) Disconnect(context.Context) error {
+	return nil
+}
+
+func (r *recordingTransport) events() []*pipe.ConnectionEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []*pipe.ConnectionEvent
+	for _, msg := range r.messages {
+		if msg.Event != nil {
+			events = append(events, msg.Event)
+		}
+	}
+	return events
+}
+
+func TestWebSocketHandlerPublishesConnectionEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	recorder := new(recordingTransport)
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithTransport(recorder, recorder),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway-1")))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte("cs1:password"))
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), &websocket.DialOptions{
+		Subprotocols: []string{"ocpp1.6"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(recorder.events()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = conn.Close(websocket.StatusNormalClosure, "OK")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(recorder.events()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	events := recorder.events()
+	connected, disconnected := events[0], events[1]
+	assert.Equal(t, pipe.ConnectionEventConnected, connected.Type)
+	assert.Equal(t, "gateway-1", connected.GatewayId)
+	assert.NotEmpty(t, connected.ConnectionId)
+	assert.Equal(t, "ocpp1.6", connected.Protocol)
+	assert.NotEmpty(t, connected.RemoteAddr)
+	assert.Equal(t, int(registry.UnsecuredTransportWithBasicAuth), connected.SecurityProfile)
+	assert.Empty(t, connected.CertificateHash)
+	assert.WithinDuration(t, time.Now(), connected.Timestamp, 5*time.Second)
+
+	assert.Equal(t, pipe.ConnectionEventDisconnected, disconnected.Type)
+	assert.Equal(t, connected.ConnectionId, disconnected.ConnectionId)
+	assert.Equal(t, "gateway-1", disconnected.GatewayId)
+	assert.False(t, disconnected.Timestamp.Before(connected.Timestamp))
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transport provides the interfaces that are used
+// to communicate between the gateway and the manager. They
+// mirror the interfaces used by the manager to communicate
+// with the gateway.
+package transport
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"context"
+
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+)
+
+type MessageHandler interface {
+	// Handle a message produced by the manager for the charge station identified by the
+	// chargeStationId. The message's Context carries the trace of the message.
+	Handle(ctx context.Context, chargeStationId string, message *pipe.GatewayMessage)
+}
+
+type MessageHandlerFunc func(ctx context.Context, chargeStationId string, message *pipe.GatewayMessage)
+
+func (h MessageHandlerFunc) Handle(ctx context.Context, chargeStationId string, message *pipe.GatewayMessage) {
+	h(ctx, chargeStationId, message)
+}
+
+type Listener interface {
+	// Connect subscribes to receive the messages from the manager for a specific charge
+	// station using a specific OcppVersion. The messages are delivered to the provided
+	// MessageHandler.
+	//
+	// Returns either a Connection on success or an error.
+	Connect(ctx context.Context, ocppVersion OcppVersion, chargeStationId string, handler MessageHandler) (Connection, error)
+}
+
+type Connection interface {
+	// Disconnect stops the delivery of messages for the charge station.
+	Disconnect(ctx context.Context) error
+}
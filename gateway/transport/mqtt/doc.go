@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mqtt provides support for exchanging messages with
+// the manager using MQTT
+package mqtt
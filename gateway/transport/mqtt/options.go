@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Opt func(p *Pool)
+
+func WithBrokerUrls(brokerUrls []*url.URL) Opt {
+	return func(p *Pool) {
+		p.brokerURLs = append(p.brokerURLs, brokerUrls...)
+	}
+}
+
+func WithTopicPrefix(topicPrefix string) Opt {
+	return func(p *Pool) {
+		p.topicPrefix = topicPrefix
+	}
+}
+
+func WithConnectSettings(connectRetryDelay time.Duration, keepAliveInterval uint16) Opt {
+	return func(p *Pool) {
+		p.connectRetryDelay = connectRetryDelay
+		p.keepAliveInterval = keepAliveInterval
+	}
+}
+
+// WithPoolSize sets the number of MQTT connections that are shared by the
+// charge stations connected to the gateway.
+func WithPoolSize(size int) Opt {
+	return func(p *Pool) {
+		p.size = size
+	}
+}
+
+// WithClientIdPrefix sets the prefix of the client ids of the MQTT connections
+// of the pool. The prefix must be unique to each gateway.
+func WithClientIdPrefix(clientIdPrefix string) Opt {
+	return func(p *Pool) {
+		p.clientIdPrefix = clientIdPrefix
+	}
+}
+
+func WithOtelTracer(tracer trace.Tracer) Opt {
+	return func(p *Pool) {
+		p.tracer = tracer
+	}
+}
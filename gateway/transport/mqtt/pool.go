@@ -0,0 +1,394 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+	"github.com/zynka-tech/zynka-csms/gateway/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+// Pool is an implementation of transport.Emitter and transport.Listener that
+// uses a small pool of MQTT connections shared by all the charge stations
+// connected to the gateway.
+//
+// Each charge station is assigned to a connection of the pool by a hash of
+// its id, so that its messages are exchanged, in order, over a single
+// connection. The connection subscribes to the messages for the charge
+// station, <prefix>/out/<ocpp-version>/<cs-id>, and routes them to the
+// handler of the charge station. Messages from the charge station are
+// published on the topic <prefix>/in/<ocpp-version>/<cs-id> using the same
+// connection, or any other connection of the pool if it is down.
+type Pool struct {
+	brokerURLs        []*url.URL
+	topicPrefix       string
+	connectRetryDelay time.Duration
+	keepAliveInterval uint16
+	size              int
+	// clientIdPrefix is the prefix of the client ids of the connections: it
+	// must be unique to the gateway
+	clientIdPrefix string
+	tracer         trace.Tracer
+
+	startOnce sync.Once
+	startErr  error
+	conns     []*poolConnection
+
+	mu     sync.Mutex
+	routes map[string]*route
+}
+
+// poolConnection is a connection of the pool
+type poolConnection struct {
+	*autopaho.ConnectionManager
+	// subscriptionMu serialises the changes to the connection's subscriptions
+	subscriptionMu sync.Mutex
+}
+
+// route is the handler of a connected charge station
+type route struct {
+	handler transport.MessageHandler
+}
+
+func NewPool(opts ...Opt) *Pool {
+	p := new(Pool)
+	for _, opt := range opts {
+		opt(p)
+	}
+	ensureDefaults(p)
+	return p
+}
+
+func ensureDefaults(p *Pool) {
+	if p.brokerURLs == nil {
+		u, err := url.Parse("mqtt://127.0.0.1:1883/")
+		if err != nil {
+			panic(err)
+		}
+		p.brokerURLs = []*url.URL{u}
+	}
+	if p.topicPrefix == "" {
+		p.topicPrefix = "cs"
+	}
+	if p.connectRetryDelay == 0 {
+		p.connectRetryDelay = 1 * time.Second
+	}
+	if p.keepAliveInterval == 0 {
+		p.keepAliveInterval = 10
+	}
+	if p.size == 0 {
+		p.size = 4
+	}
+	if p.clientIdPrefix == "" {
+		b := make([]byte, 8)
+		_, err := rand.Read(b)
+		if err != nil {
+			panic(err)
+		}
+		p.clientIdPrefix = "gateway-" + hex.EncodeToString(b)
+	}
+	if p.tracer == nil {
+		p.tracer = trace.NewNoopTracerProvider().Tracer("")
+	}
+}
+
+// Connect routes the messages for the charge station to the handler once the
+// connection that the charge station is assigned to has subscribed to them. A
+// charge station that reconnects replaces the route of its previous
+// connection.
+func (p *Pool) Connect(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, handler transport.MessageHandler) (transport.Connection, error) {
+	key := string(ocppVersion) + "/" + chargeStationId
+	r := &route{handler: handler}
+
+	p.mu.Lock()
+	if p.routes == nil {
+		p.routes = make(map[string]*route)
+	}
+	p.routes[key] = r
+	p.mu.Unlock()
+
+	conn := &connection{pool: p, key: key, route: r}
+	err := p.subscribe(ctx, key)
+	if err != nil {
+		_ = conn.Disconnect(ctx)
+		return nil, err
+	}
+	return conn, nil
+}
+
+type connection struct {
+	pool  *Pool
+	key   string
+	route *route
+}
+
+// Disconnect stops routing the messages for the charge station and
+// unsubscribes from them, unless the charge station has reconnected.
+func (c *connection) Disconnect(ctx context.Context) error {
+	c.pool.mu.Lock()
+	removed := c.pool.routes[c.key] == c.route
+	if removed {
+		delete(c.pool.routes, c.key)
+	}
+	c.pool.mu.Unlock()
+
+	if removed && c.pool.startErr == nil {
+		c.pool.unsubscribe(ctx, c.key)
+	}
+	return nil
+}
+
+// connectionFor returns the index of the connection that the charge station
+// with the key <ocpp-version>/<cs-id> is assigned to.
+func (p *Pool) connectionFor(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(p.size))
+}
+
+// subscribe starts the pool, if it has not already been started, and
+// subscribes to the messages for the charge station on its connection once it
+// is up.
+func (p *Pool) subscribe(ctx context.Context, key string) error {
+	p.startOnce.Do(p.start)
+	if p.startErr != nil {
+		return p.startErr
+	}
+
+	conn := p.conns[p.connectionFor(key)]
+	err := conn.AwaitConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn.subscriptionMu.Lock()
+	defer conn.subscriptionMu.Unlock()
+	p.mu.Lock()
+	_, connected := p.routes[key]
+	p.mu.Unlock()
+	if !connected {
+		return nil
+	}
+	_, err = conn.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: map[string]paho.SubscribeOptions{
+			p.outTopic(key): {},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("subscribing to %s: %w", p.outTopic(key), err)
+	}
+	return nil
+}
+
+// unsubscribe unsubscribes from the messages for a charge station that has
+// disconnected: a connection that is down has no subscriptions to remove.
+func (p *Pool) unsubscribe(ctx context.Context, key string) {
+	conn := p.conns[p.connectionFor(key)]
+	conn.subscriptionMu.Lock()
+	defer conn.subscriptionMu.Unlock()
+	p.mu.Lock()
+	_, connected := p.routes[key]
+	p.mu.Unlock()
+	if connected {
+		return
+	}
+	_, err := conn.Unsubscribe(ctx, &paho.Unsubscribe{
+		Topics: []string{p.outTopic(key)},
+	})
+	if err != nil && !errors.Is(err, autopaho.ConnectionDownError) {
+		slog.Warn("unsubscribing from mqtt topic", "topic", p.outTopic(key), "err", err)
+	}
+}
+
+// resubscribe subscribes to the messages for the charge stations assigned to
+// a connection when the connection comes up.
+func (p *Pool) resubscribe(index int, conn *poolConnection, manager *autopaho.ConnectionManager) {
+	conn.subscriptionMu.Lock()
+	defer conn.subscriptionMu.Unlock()
+
+	subscriptions := make(map[string]paho.SubscribeOptions)
+	p.mu.Lock()
+	for key := range p.routes {
+		if p.connectionFor(key) == index {
+			subscriptions[p.outTopic(key)] = paho.SubscribeOptions{}
+		}
+	}
+	p.mu.Unlock()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	_, err := manager.Subscribe(context.Background(), &paho.Subscribe{
+		Subscriptions: subscriptions,
+	})
+	if err != nil {
+		slog.Error("subscribing to mqtt topics", "clientId", fmt.Sprintf("%s-%d", p.clientIdPrefix, index), "err", err)
+	}
+}
+
+func (p *Pool) outTopic(key string) string {
+	return fmt.Sprintf("%s/out/%s", p.topicPrefix, key)
+}
+
+// start connects the pool: the connections outlive any request.
+func (p *Pool) start() {
+	for i := 0; i < p.size; i++ {
+		index := i
+		clientId := fmt.Sprintf("%s-%d", p.clientIdPrefix, i)
+		conn := new(poolConnection)
+		manager, err := autopaho.NewConnection(context.Background(), autopaho.ClientConfig{
+			BrokerUrls:        p.brokerURLs,
+			KeepAlive:         p.keepAliveInterval,
+			ConnectRetryDelay: p.connectRetryDelay,
+			OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+				p.resubscribe(index, conn, manager)
+			},
+			ClientConfig: paho.ClientConfig{
+				ClientID: clientId,
+				Router:   paho.NewSingleHandlerRouter(p.route),
+				OnServerDisconnect: func(disconnect *paho.Disconnect) {
+					reason := ""
+					if disconnect.Properties != nil {
+						reason = disconnect.Properties.ReasonString
+					}
+					slog.Warn("disconnected from mqtt", "clientId", clientId, "reason", reason)
+				},
+			},
+		})
+		if err != nil {
+			slog.Error("connecting to mqtt", "mqttBrokerURLs", p.brokerURLs, "err", err)
+			p.startErr = err
+			return
+		}
+		conn.ConnectionManager = manager
+		p.conns = append(p.conns, conn)
+	}
+}
+
+// route sends a message from the CSMS to the handler of the charge station
+func (p *Pool) route(mqttMsg *paho.Publish) {
+	protocol, clientId, ok := strings.Cut(strings.TrimPrefix(mqttMsg.Topic, p.topicPrefix+"/out/"), "/")
+	if !ok {
+		slog.Warn("unexpected mqtt topic", "topic", mqttMsg.Topic)
+		return
+	}
+
+	p.mu.Lock()
+	r := p.routes[protocol+"/"+clientId]
+	p.mu.Unlock()
+	if r == nil {
+		// the charge station has disconnected
+		return
+	}
+
+	var msg pipe.GatewayMessage
+	err := json.Unmarshal(mqttMsg.Payload, &msg)
+	if err != nil {
+		slog.Error("unmarshalling CSMS message", "err", err)
+		return
+	}
+
+	correlationMap := make(map[string]string)
+	if mqttMsg.Properties != nil {
+		err = json.Unmarshal(mqttMsg.Properties.CorrelationData, &correlationMap)
+		if err != nil {
+			slog.Warn("unmarshalling correlation map", "err", err)
+		}
+	}
+	requestContext := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(correlationMap))
+
+	newCtx, span := p.tracer.Start(requestContext, fmt.Sprintf("%s/out/%s/# receive", p.topicPrefix, protocol),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("mqtt"),
+			semconv.MessagingMessagePayloadSizeBytes(len(mqttMsg.Payload)),
+			semconv.MessagingMessageConversationID(msg.MessageId),
+			semconv.MessagingOperationKey.String("receive"),
+			attribute.String("csId", clientId),
+		))
+	defer span.End()
+
+	msg.Context = newCtx
+
+	r.handler.Handle(newCtx, clientId, &msg)
+}
+
+// Emit publishes the message on the connection that the charge station is
+// assigned to or, if it is down, on another connection of the pool that is up
+func (p *Pool) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *pipe.GatewayMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshaling message for publication: %w", err)
+	}
+
+	newCtx, span := p.tracer.Start(ctx,
+		fmt.Sprintf("%s/in/%s/# publish", p.topicPrefix, ocppVersion),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("mqtt"),
+			semconv.MessagingMessagePayloadSizeBytes(len(data)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+		))
+	defer span.End()
+
+	correlationMap := make(map[string]string)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.MapCarrier(correlationMap))
+
+	correlationData, err := json.Marshal(correlationMap)
+	if err != nil {
+		slog.Warn("marshalling correlation map", "err", err)
+	}
+
+	return p.publish(newCtx, string(ocppVersion)+"/"+chargeStationId, &paho.Publish{
+		Topic:   fmt.Sprintf("%s/in/%s/%s", p.topicPrefix, ocppVersion, chargeStationId),
+		Payload: data,
+		Properties: &paho.PublishProperties{
+			ContentType:     "application/json",
+			ResponseTopic:   fmt.Sprintf("%s/out/%s/%s", p.topicPrefix, ocppVersion, chargeStationId),
+			CorrelationData: correlationData,
+		},
+	})
+}
+
+func (p *Pool) publish(ctx context.Context, key string, publish *paho.Publish) error {
+	p.startOnce.Do(p.start)
+	if len(p.conns) == 0 {
+		return autopaho.ConnectionDownError
+	}
+
+	start := p.connectionFor(key)
+	var errs []error
+	for i := 0; i < len(p.conns); i++ {
+		conn := p.conns[(start+i)%len(p.conns)]
+		_, err := conn.Publish(ctx, publish)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if !errors.Is(err, autopaho.ConnectionDownError) {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats provides support for exchanging messages with
+// the manager using NATS JetStream
+package nats
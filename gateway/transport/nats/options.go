@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Opt func(t *Transport)
+
+func WithNatsUrls(natsUrls []*url.URL) Opt {
+	return func(t *Transport) {
+		t.natsUrls = append(t.natsUrls, natsUrls...)
+	}
+}
+
+func WithNatsPrefix(natsPrefix string) Opt {
+	return func(t *Transport) {
+		t.natsPrefix = natsPrefix
+	}
+}
+
+func WithNatsConnectSettings(natsConnectTimeout, natsConnectRetryDelay time.Duration) Opt {
+	return func(t *Transport) {
+		t.natsConnectTimeout = natsConnectTimeout
+		t.natsConnectRetryDelay = natsConnectRetryDelay
+	}
+}
+
+// WithNatsStreamMaxAge sets how long messages are retained by the streams
+// that the Transport creates.
+func WithNatsStreamMaxAge(natsStreamMaxAge time.Duration) Opt {
+	return func(t *Transport) {
+		t.natsStreamMaxAge = natsStreamMaxAge
+	}
+}
+
+// WithGatewayId sets the identifier of the gateway instance, which names the
+// consumer of the messages for the charge stations. It defaults to the host
+// name.
+func WithGatewayId(gatewayId string) Opt {
+	return func(t *Transport) {
+		t.gatewayId = gatewayId
+	}
+}
+
+func WithOtelTracer(tracer trace.Tracer) Opt {
+	return func(t *Transport) {
+		t.tracer = tracer
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// NewServer starts a local NATS server, with JetStream enabled, that can be
+// used for testing. The server is shut down when the test completes.
+func NewServer(t *testing.T) (*server.Server, *url.URL) {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("creating nats server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("starting nats server: not ready for connections")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	addr, err := url.Parse(fmt.Sprintf("nats://%s", srv.Addr()))
+	if err != nil {
+		t.Fatalf("parsing server url: %v", err)
+	}
+
+	return srv, addr
+}
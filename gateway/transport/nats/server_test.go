@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"testing"
+
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/gateway/transport/nats"
+)
+
+func TestNewServer(t *testing.T) {
+	srv, addr := nats.NewServer(t)
+	assert.True(t, srv.JetStreamEnabled())
+
+	conn, err := natsclient.Connect(addr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.True(t, conn.IsConnected())
+}
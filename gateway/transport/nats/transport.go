@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+	"github.com/zynka-tech/zynka-csms/gateway/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+// Transport is an implementation of transport.Emitter and transport.Listener
+// that uses NATS JetStream. A single NATS connection is shared by all the
+// charge stations connected to the gateway.
+//
+// Messages from the charge stations are published on the subject
+// <prefix>.in.<ocpp-version>.<cs-id> and messages for the charge stations are
+// consumed from the subject <prefix>.out.<ocpp-version>.<cs-id>, where the
+// ocpp-version has its periods removed, e.g. ocpp201, and the cs-id is
+// escaped (see escapeSubjectToken). The messages are retained by the
+// <prefix>_in and <prefix>_out streams, which the Transport creates if they do
+// not exist.
+//
+// The messages for the charge stations are delivered by a single durable
+// consumer, named after the gateway id, that is filtered on the subjects of
+// the charge stations connected to the gateway: a charge station receives the
+// messages published from when it connected. The gateway id must therefore be
+// unique to each gateway instance.
+type Transport struct {
+	sync.Mutex
+	natsUrls              []*url.URL
+	natsPrefix            string
+	natsConnectTimeout    time.Duration
+	natsConnectRetryDelay time.Duration
+	natsStreamMaxAge      time.Duration
+	gatewayId             string
+	tracer                trace.Tracer
+
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	outStream jetstream.Stream
+
+	consumerMutex sync.Mutex
+	consumeCtx    jetstream.ConsumeContext
+	stations      map[string]*station // the connected charge stations keyed by subject
+}
+
+// station is a charge station that is connected to the gateway
+type station struct {
+	ocppVersion     transport.OcppVersion
+	chargeStationId string
+	handler         transport.MessageHandler
+}
+
+func NewTransport(opts ...Opt) *Transport {
+	t := new(Transport)
+	for _, opt := range opts {
+		opt(t)
+	}
+	ensureDefaults(t)
+	return t
+}
+
+func ensureDefaults(t *Transport) {
+	if t.natsUrls == nil {
+		u, err := url.Parse("nats://127.0.0.1:4222")
+		if err != nil {
+			panic(err)
+		}
+		t.natsUrls = []*url.URL{u}
+	}
+	if t.natsPrefix == "" {
+		t.natsPrefix = "cs"
+	}
+	if t.natsConnectTimeout == 0 {
+		t.natsConnectTimeout = 5 * time.Second
+	}
+	if t.natsConnectRetryDelay == 0 {
+		t.natsConnectRetryDelay = 1 * time.Second
+	}
+	if t.natsStreamMaxAge == 0 {
+		t.natsStreamMaxAge = 1 * time.Hour
+	}
+	if t.gatewayId == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			panic(err)
+		}
+		t.gatewayId = hostname
+	}
+	if t.stations == nil {
+		t.stations = make(map[string]*station)
+	}
+	if t.tracer == nil {
+		t.tracer = trace.NewNoopTracerProvider().Tracer("")
+	}
+}
+
+// Emit publishes the message from the charge station to the <prefix>_in stream
+func (t *Transport) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *pipe.GatewayMessage) error {
+	subj, err := subject(t.natsPrefix, "in", ocppVersion, chargeStationId)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshaling message for publication: %w", err)
+	}
+
+	newCtx, span := t.tracer.Start(ctx,
+		fmt.Sprintf("%s.in.%s.* publish", t.natsPrefix, subjectVersion(ocppVersion)),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(data)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+		))
+	defer span.End()
+
+	header := nats.Header{}
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(header))
+
+	js, _, err := t.ensureConnection(newCtx)
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %w", err)
+	}
+
+	_, err = js.PublishMsg(newCtx, &nats.Msg{
+		Subject: subj,
+		Data:    data,
+		Header:  header,
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %w", subj, err)
+	}
+	return nil
+}
+
+// Connect delivers the messages for the charge station, published from now on,
+// to the handler.
+func (t *Transport) Connect(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, handler transport.MessageHandler) (transport.Connection, error) {
+	subj, err := subject(t.natsPrefix, "out", ocppVersion, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.natsConnectTimeout)
+	defer cancel()
+
+	_, stream, err := t.ensureConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to NATS: %w", err)
+	}
+
+	s := &station{
+		ocppVersion:     ocppVersion,
+		chargeStationId: chargeStationId,
+		handler:         handler,
+	}
+	err = t.addStation(ctx, stream, subj, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connection{transport: t, subject: subj, station: s}, nil
+}
+
+type connection struct {
+	transport *Transport
+	subject   string
+	station   *station
+}
+
+// Disconnect stops the delivery of messages to the charge station. The
+// subject is left in the consumer's filter until another charge station
+// connects: any messages for it are acknowledged and discarded.
+func (c *connection) Disconnect(context.Context) error {
+	c.transport.consumerMutex.Lock()
+	defer c.transport.consumerMutex.Unlock()
+	// the charge station may have reconnected in the meantime
+	if c.transport.stations[c.subject] == c.station {
+		delete(c.transport.stations, c.subject)
+	}
+	return nil
+}
+
+// addStation registers the charge station and adds its subject to the
+// consumer's filter, creating the consumer and starting to consume messages if
+// this is the first charge station to connect.
+func (t *Transport) addStation(ctx context.Context, stream jetstream.Stream, subj string, s *station) error {
+	t.consumerMutex.Lock()
+	defer t.consumerMutex.Unlock()
+
+	previous, reconnected := t.stations[subj]
+	t.stations[subj] = s
+	if reconnected {
+		return nil
+	}
+
+	err := t.updateConsumer(ctx, stream)
+	if err != nil {
+		if previous == nil {
+			delete(t.stations, subj)
+		}
+		return err
+	}
+	return nil
+}
+
+// updateConsumer filters the consumer on the subjects of the connected charge
+// stations. The consumer left over from a previous run of the gateway is
+// deleted before the first charge station connects: the messages that it
+// holds are for calls that the CSMS has given up on.
+func (t *Transport) updateConsumer(ctx context.Context, stream jetstream.Stream) error {
+	name := consumerName(t.natsPrefix, t.gatewayId)
+	if t.consumeCtx == nil {
+		err := stream.DeleteConsumer(ctx, name)
+		if err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
+			return fmt.Errorf("deleting consumer %s: %w", name, err)
+		}
+	}
+
+	filterSubjects := make([]string, 0, len(t.stations))
+	for subj := range t.stations {
+		filterSubjects = append(filterSubjects, subj)
+	}
+	sort.Strings(filterSubjects)
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:           name,
+		FilterSubjects:    filterSubjects,
+		DeliverPolicy:     jetstream.DeliverNewPolicy,
+		AckPolicy:         jetstream.AckExplicitPolicy,
+		InactiveThreshold: t.natsStreamMaxAge,
+	})
+	if err != nil {
+		return fmt.Errorf("updating consumer %s: %w", name, err)
+	}
+
+	if t.consumeCtx == nil {
+		t.consumeCtx, err = consumer.Consume(t.consume)
+		if err != nil {
+			return fmt.Errorf("consuming %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// consume delivers a message from the consumer to the charge station that it
+// is for
+func (t *Transport) consume(natsMsg jetstream.Msg) {
+	t.consumerMutex.Lock()
+	s := t.stations[natsMsg.Subject()]
+	t.consumerMutex.Unlock()
+
+	if s != nil {
+		t.handle(s.ocppVersion, s.chargeStationId, natsMsg, s.handler)
+	}
+	err := natsMsg.Ack()
+	if err != nil {
+		slog.Warn("failed to acknowledge message", "subject", natsMsg.Subject(), "err", err)
+	}
+}
+
+// handle sends a message from the CSMS to the handler of the charge station
+func (t *Transport) handle(ocppVersion transport.OcppVersion, chargeStationId string, natsMsg jetstream.Msg, handler transport.MessageHandler) {
+	var msg pipe.GatewayMessage
+	err := json.Unmarshal(natsMsg.Data(), &msg)
+	if err != nil {
+		slog.Error("unmarshalling CSMS message", "err", err)
+		return
+	}
+
+	requestContext := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(natsMsg.Headers()))
+
+	newCtx, span := t.tracer.Start(requestContext,
+		fmt.Sprintf("%s.out.%s.* receive", t.natsPrefix, subjectVersion(ocppVersion)),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(natsMsg.Data())),
+			semconv.MessagingMessageConversationID(msg.MessageId),
+			semconv.MessagingOperationKey.String("receive"),
+			attribute.String("csId", chargeStationId),
+		))
+	defer span.End()
+
+	msg.Context = newCtx
+
+	handler.Handle(newCtx, chargeStationId, &msg)
+}
+
+// ensureConnection connects to NATS, if not already connected, and ensures
+// that the streams exist: it returns the JetStream context and the stream of
+// messages for the charge stations.
+func (t *Transport) ensureConnection(ctx context.Context) (jetstream.JetStream, jetstream.Stream, error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.conn == nil {
+		servers := make([]string, len(t.natsUrls))
+		for i, u := range t.natsUrls {
+			servers[i] = u.String()
+		}
+
+		conn, err := nats.Connect(strings.Join(servers, ","),
+			nats.Name("gateway"),
+			nats.Timeout(t.natsConnectTimeout),
+			nats.ReconnectWait(t.natsConnectRetryDelay),
+			nats.MaxReconnects(-1))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		js, err := jetstream.New(conn)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+
+		_, err = t.ensureStream(ctx, js, "in")
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		outStream, err := t.ensureStream(ctx, js, "out")
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+
+		t.conn = conn
+		t.js = js
+		t.outStream = outStream
+	}
+	return t.js, t.outStream, nil
+}
+
+// ensureStream creates the stream that holds the messages sent in a direction
+// (either "in" or "out") if it does not already exist. An existing stream is
+// left unchanged so its configuration can be managed outside the CSMS.
+func (t *Transport) ensureStream(ctx context.Context, js jetstream.JetStream, direction string) (jetstream.Stream, error) {
+	name := fmt.Sprintf("%s_%s", t.natsPrefix, direction)
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     name,
+		Subjects: []string{fmt.Sprintf("%s.%s.>", t.natsPrefix, direction)},
+		MaxAge:   t.natsStreamMaxAge,
+	})
+	if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		return js.Stream(ctx, name)
+	}
+	return stream, err
+}
+
+// subjectVersion returns the OCPP version as a subject token: subject tokens
+// are separated by periods so they are removed from the version.
+func subjectVersion(ocppVersion transport.OcppVersion) string {
+	return strings.ReplaceAll(string(ocppVersion), ".", "")
+}
+
+func subject(prefix, direction string, ocppVersion transport.OcppVersion, chargeStationId string) (string, error) {
+	if chargeStationId == "" {
+		return "", errors.New("charge station id cannot be empty")
+	}
+	return fmt.Sprintf("%s.%s.%s.%s", prefix, direction, subjectVersion(ocppVersion), escapeSubjectToken(chargeStationId)), nil
+}
+
+// subjectEscapedChars are the characters that cannot be used in a subject
+// token along with the escape character
+const subjectEscapedChars = "%.*> \t\r\n"
+
+// escapeSubjectToken escapes a charge station id for use as a subject token:
+// the characters that cannot be used in a token are percent encoded, e.g. a
+// charge station id of "cs.001" has a token of "cs%2E001".
+func escapeSubjectToken(chargeStationId string) string {
+	var b strings.Builder
+	for i := 0; i < len(chargeStationId); i++ {
+		c := chargeStationId[i]
+		if strings.IndexByte(subjectEscapedChars, c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// consumerName returns the name of the gateway's consumer: the characters that
+// cannot be used in a consumer name are replaced by underscores.
+func consumerName(prefix, gatewayId string) string {
+	return fmt.Sprintf("%s-gateway-%s", prefix, strings.Map(func(r rune) rune {
+		if strings.ContainsRune(".*>/\\", r) || r <= ' ' {
+			return '_'
+		}
+		return r
+	}, gatewayId))
+}
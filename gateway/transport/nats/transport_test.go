@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/gateway/ocpp"
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+	"github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/gateway/transport/nats"
+)
+
+func TestTransportEmitsMessageFromChargeStation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, addr := nats.NewServer(t)
+	natsTransport := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}))
+
+	err := natsTransport.Emit(ctx, transport.OcppVersion201, "cs001", &pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "Heartbeat",
+		MessageId:      "1234",
+		RequestPayload: json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	js := jetStream(t, addr)
+	consumer, err := js.OrderedConsumer(ctx, "cs_in", jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+	natsMsg, err := consumer.Next(jetstream.FetchMaxWait(5 * time.Second))
+	require.NoError(t, err)
+
+	assert.Equal(t, "cs.in.ocpp201.cs001", natsMsg.Subject())
+	var msg pipe.GatewayMessage
+	err = json.Unmarshal(natsMsg.Data(), &msg)
+	require.NoError(t, err)
+	assert.Equal(t, ocpp.MessageTypeCall, msg.MessageType)
+	assert.Equal(t, "Heartbeat", msg.Action)
+	assert.Equal(t, "1234", msg.MessageId)
+}
+
+func TestTransportDeliversMessagesForConnectedChargeStation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, addr := nats.NewServer(t)
+	natsTransport := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}))
+
+	rcvdCh := make(chan *pipe.GatewayMessage, 2)
+	conn, err := natsTransport.Connect(ctx, transport.OcppVersion16, "cs002",
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *pipe.GatewayMessage) {
+			assert.Equal(t, "cs002", chargeStationId)
+			assert.NotNil(t, msg.Context)
+			rcvdCh <- msg
+		}))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		assert.NoError(t, err)
+	}()
+
+	js := jetStream(t, addr)
+	for _, subject := range []string{"cs.out.ocpp16.cs001", "cs.out.ocpp16.cs002"} {
+		data, err := json.Marshal(pipe.GatewayMessage{
+			MessageType: ocpp.MessageTypeCall,
+			Action:      "Reset",
+			MessageId:   subject,
+		})
+		require.NoError(t, err)
+		_, err = js.Publish(ctx, subject, data)
+		require.NoError(t, err)
+	}
+
+	select {
+	case msg := <-rcvdCh:
+		assert.Equal(t, "cs.out.ocpp16.cs002", msg.MessageId)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestTransportDeliversMessagesForEachConnectedChargeStation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, addr := nats.NewServer(t)
+	gateway1 := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}), nats.WithGatewayId("gateway-1"))
+	gateway2 := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}), nats.WithGatewayId("gateway-2"))
+
+	rcvdCh := make(chan string, 10)
+	handler := transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *pipe.GatewayMessage) {
+		rcvdCh <- chargeStationId + ":" + msg.MessageId
+	})
+	conn1, err := gateway1.Connect(ctx, transport.OcppVersion16, "cs001", handler)
+	require.NoError(t, err)
+	conn2, err := gateway1.Connect(ctx, transport.OcppVersion201, "cs002", handler)
+	require.NoError(t, err)
+	conn3, err := gateway2.Connect(ctx, transport.OcppVersion16, "cs003", handler)
+	require.NoError(t, err)
+
+	js := jetStream(t, addr)
+	publish := func(subject, messageId string) {
+		data, err := json.Marshal(pipe.GatewayMessage{
+			MessageType: ocpp.MessageTypeCall,
+			Action:      "Reset",
+			MessageId:   messageId,
+		})
+		require.NoError(t, err)
+		_, err = js.Publish(ctx, subject, data)
+		require.NoError(t, err)
+	}
+	receive := func() []string {
+		var rcvd []string
+		for i := 0; i < 3; i++ {
+			select {
+			case msg := <-rcvdCh:
+				rcvd = append(rcvd, msg)
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for message")
+			}
+		}
+		return rcvd
+	}
+
+	publish("cs.out.ocpp16.cs001", "1")
+	publish("cs.out.ocpp201.cs002", "2")
+	publish("cs.out.ocpp16.cs003", "3")
+	assert.ElementsMatch(t, []string{"cs001:1", "cs002:2", "cs003:3"}, receive())
+
+	// the messages for a charge station that has disconnected are discarded
+	require.NoError(t, conn1.Disconnect(ctx))
+	publish("cs.out.ocpp16.cs001", "4")
+	publish("cs.out.ocpp201.cs002", "5")
+	publish("cs.out.ocpp16.cs003", "6")
+	require.NoError(t, conn2.Disconnect(ctx))
+	require.NoError(t, conn3.Disconnect(ctx))
+
+	conn1, err = gateway1.Connect(ctx, transport.OcppVersion16, "cs001", handler)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, conn1.Disconnect(ctx))
+	}()
+	publish("cs.out.ocpp16.cs001", "7")
+	assert.ElementsMatch(t, []string{"cs002:5", "cs003:6", "cs001:7"}, receive())
+}
+
+func TestTransportEscapesChargeStationIdInSubject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, addr := nats.NewServer(t)
+	natsTransport := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}))
+
+	rcvdCh := make(chan string, 1)
+	conn, err := natsTransport.Connect(ctx, transport.OcppVersion201, "cs.>%1",
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *pipe.GatewayMessage) {
+			rcvdCh <- chargeStationId
+		}))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, conn.Disconnect(ctx))
+	}()
+
+	err = natsTransport.Emit(ctx, transport.OcppVersion201, "cs.>%1", &pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "Heartbeat",
+		MessageId:      "1234",
+		RequestPayload: json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	js := jetStream(t, addr)
+	consumer, err := js.OrderedConsumer(ctx, "cs_in", jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+	natsMsg, err := consumer.Next(jetstream.FetchMaxWait(5 * time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "cs.in.ocpp201.cs%2E%3E%251", natsMsg.Subject())
+
+	data, err := json.Marshal(pipe.GatewayMessage{
+		MessageType: ocpp.MessageTypeCall,
+		Action:      "Reset",
+		MessageId:   "5678",
+	})
+	require.NoError(t, err)
+	_, err = js.Publish(ctx, "cs.out.ocpp201.cs%2E%3E%251", data)
+	require.NoError(t, err)
+	select {
+	case chargeStationId := <-rcvdCh:
+		assert.Equal(t, "cs.>%1", chargeStationId)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestTransportRejectsEmptyChargeStationId(t *testing.T) {
+	_, addr := nats.NewServer(t)
+	natsTransport := nats.NewTransport(nats.WithNatsUrls([]*url.URL{addr}))
+
+	_, err := natsTransport.Connect(context.Background(), transport.OcppVersion201, "",
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *pipe.GatewayMessage) {}))
+	assert.ErrorContains(t, err, "cannot be empty")
+}
+
+func jetStream(t *testing.T, addr *url.URL) jetstream.JetStream {
+	conn, err := natsclient.Connect(addr.String())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+	return js
+}
@@ -15,15 +15,28 @@ import (
 	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 )
 
+// Defines values for ChangeAvailabilityCommandOperationalStatus.
+const (
+	Inoperative ChangeAvailabilityCommandOperationalStatus = "Inoperative"
+	Operative   ChangeAvailabilityCommandOperationalStatus = "Operative"
+)
+
+// Defines values for ChargeStationConnectionStatus.
+const (
+	Offline ChargeStationConnectionStatus = "offline"
+	Online  ChargeStationConnectionStatus = "online"
+)
+
 // Defines values for ChargeStationInstallCertificatesCertificatesStatus.
 const (
-	Accepted ChargeStationInstallCertificatesCertificatesStatus = "Accepted"
-	Pending  ChargeStationInstallCertificatesCertificatesStatus = "Pending"
-	Rejected ChargeStationInstallCertificatesCertificatesStatus = "Rejected"
+	ChargeStationInstallCertificatesCertificatesStatusAccepted ChargeStationInstallCertificatesCertificatesStatus = "Accepted"
+	ChargeStationInstallCertificatesCertificatesStatusPending  ChargeStationInstallCertificatesCertificatesStatus = "Pending"
+	ChargeStationInstallCertificatesCertificatesStatusRejected ChargeStationInstallCertificatesCertificatesStatus = "Rejected"
 )
 
 // Defines values for ChargeStationInstallCertificatesCertificatesType.
@@ -43,6 +56,41 @@ const (
 	StatusNotification             ChargeStationTriggerTrigger = "StatusNotification"
 )
 
+// Defines values for ChargingProfileChargingProfileKind.
+const (
+	Absolute  ChargingProfileChargingProfileKind = "Absolute"
+	Recurring ChargingProfileChargingProfileKind = "Recurring"
+	Relative  ChargingProfileChargingProfileKind = "Relative"
+)
+
+// Defines values for ChargingProfileChargingProfilePurpose.
+const (
+	ChargePointMaxProfile ChargingProfileChargingProfilePurpose = "ChargePointMaxProfile"
+	TxDefaultProfile      ChargingProfileChargingProfilePurpose = "TxDefaultProfile"
+	TxProfile             ChargingProfileChargingProfilePurpose = "TxProfile"
+)
+
+// Defines values for ChargingProfileRecurrencyKind.
+const (
+	Daily  ChargingProfileRecurrencyKind = "Daily"
+	Weekly ChargingProfileRecurrencyKind = "Weekly"
+)
+
+// Defines values for ChargingProfileStatus.
+const (
+	ChargingProfileStatusAccepted     ChargingProfileStatus = "Accepted"
+	ChargingProfileStatusClearPending ChargingProfileStatus = "ClearPending"
+	ChargingProfileStatusNotSupported ChargingProfileStatus = "NotSupported"
+	ChargingProfileStatusPending      ChargingProfileStatus = "Pending"
+	ChargingProfileStatusRejected     ChargingProfileStatus = "Rejected"
+)
+
+// Defines values for ChargingScheduleChargingRateUnit.
+const (
+	A ChargingScheduleChargingRateUnit = "A"
+	W ChargingScheduleChargingRateUnit = "W"
+)
+
 // Defines values for ConnectorFormat.
 const (
 	CABLE  ConnectorFormat = "CABLE"
@@ -108,12 +156,81 @@ const (
 	UNDERGROUNDGARAGE LocationParkingType = "UNDERGROUND_GARAGE"
 )
 
+// Defines values for PartyRole.
+const (
+	PartyRoleCPO   PartyRole = "CPO"
+	PartyRoleEMSP  PartyRole = "EMSP"
+	PartyRoleHUB   PartyRole = "HUB"
+	PartyRoleNAP   PartyRole = "NAP"
+	PartyRoleNSP   PartyRole = "NSP"
+	PartyRoleOTHER PartyRole = "OTHER"
+	PartyRoleSCSP  PartyRole = "SCSP"
+)
+
+// Defines values for PartyEndpointRole.
+const (
+	RECEIVER PartyEndpointRole = "RECEIVER"
+	SENDER   PartyEndpointRole = "SENDER"
+)
+
+// Defines values for PriceComponentType.
+const (
+	ENERGY      PriceComponentType = "ENERGY"
+	FLAT        PriceComponentType = "FLAT"
+	PARKINGTIME PriceComponentType = "PARKING_TIME"
+	TIME        PriceComponentType = "TIME"
+)
+
+// Defines values for QueuedCallState.
+const (
+	InFlight QueuedCallState = "InFlight"
+	Pending  QueuedCallState = "Pending"
+)
+
 // Defines values for RegistrationStatus.
 const (
 	PENDING    RegistrationStatus = "PENDING"
 	REGISTERED RegistrationStatus = "REGISTERED"
 )
 
+// Defines values for ResetCommandType.
+const (
+	Hard ResetCommandType = "Hard"
+	Soft ResetCommandType = "Soft"
+)
+
+// Defines values for StartTransactionCommandTokenType.
+const (
+	Central         StartTransactionCommandTokenType = "Central"
+	EMAID           StartTransactionCommandTokenType = "eMAID"
+	ISO14443        StartTransactionCommandTokenType = "ISO14443"
+	ISO15693        StartTransactionCommandTokenType = "ISO15693"
+	KeyCode         StartTransactionCommandTokenType = "KeyCode"
+	Local           StartTransactionCommandTokenType = "Local"
+	MacAddress      StartTransactionCommandTokenType = "MacAddress"
+	NoAuthorization StartTransactionCommandTokenType = "NoAuthorization"
+)
+
+// Defines values for TariffType.
+const (
+	ADHOCPAYMENT TariffType = "AD_HOC_PAYMENT"
+	PROFILECHEAP TariffType = "PROFILE_CHEAP"
+	PROFILEFAST  TariffType = "PROFILE_FAST"
+	PROFILEGREEN TariffType = "PROFILE_GREEN"
+	REGULAR      TariffType = "REGULAR"
+)
+
+// Defines values for TariffRestrictionsDayOfWeek.
+const (
+	FRIDAY    TariffRestrictionsDayOfWeek = "FRIDAY"
+	MONDAY    TariffRestrictionsDayOfWeek = "MONDAY"
+	SATURDAY  TariffRestrictionsDayOfWeek = "SATURDAY"
+	SUNDAY    TariffRestrictionsDayOfWeek = "SUNDAY"
+	THURSDAY  TariffRestrictionsDayOfWeek = "THURSDAY"
+	TUESDAY   TariffRestrictionsDayOfWeek = "TUESDAY"
+	WEDNESDAY TariffRestrictionsDayOfWeek = "WEDNESDAY"
+)
+
 // Defines values for TokenCacheMode.
 const (
 	ALLOWED        TokenCacheMode = "ALLOWED"
@@ -124,10 +241,10 @@ const (
 
 // Defines values for TokenType.
 const (
-	ADHOCUSER TokenType = "AD_HOC_USER"
-	APPUSER   TokenType = "APP_USER"
-	OTHER     TokenType = "OTHER"
-	RFID      TokenType = "RFID"
+	TokenTypeADHOCUSER TokenType = "AD_HOC_USER"
+	TokenTypeAPPUSER   TokenType = "APP_USER"
+	TokenTypeOTHER     TokenType = "OTHER"
+	TokenTypeRFID      TokenType = "RFID"
 )
 
 // Certificate A client certificate
@@ -136,6 +253,21 @@ type Certificate struct {
 	Certificate string `json:"certificate"`
 }
 
+// ChangeAvailabilityCommand Request to change the availability of the charge station, an EVSE or a connector
+type ChangeAvailabilityCommand struct {
+	// ConnectorId The connector of the EVSE to change (OCPP 2.0.1 only)
+	ConnectorId *int `json:"connectorId,omitempty"`
+
+	// EvseId The EVSE to change (connector identifier for OCPP 1.6), the whole charge station if not set
+	EvseId *int `json:"evseId,omitempty"`
+
+	// OperationalStatus The availability to change to
+	OperationalStatus ChangeAvailabilityCommandOperationalStatus `json:"operationalStatus"`
+}
+
+// ChangeAvailabilityCommandOperationalStatus The availability to change to
+type ChangeAvailabilityCommandOperationalStatus string
+
 // ChargeStationAuth Connection details for a charge station
 type ChargeStationAuth struct {
 	// Base64SHA256Password The base64 encoded, SHA-256 hash of the charge station password
@@ -148,6 +280,45 @@ type ChargeStationAuth struct {
 	SecurityProfile int `json:"securityProfile"`
 }
 
+// ChargeStationConnection The state of a charge station's connection to the gateway
+type ChargeStationConnection struct {
+	// CertificateHash The base64url encoded SHA-256 hash of the client certificate presented by the charge station
+	CertificateHash *string `json:"certificateHash,omitempty"`
+
+	// ChargeStationId The charge station identifier
+	ChargeStationId string `json:"chargeStationId"`
+
+	// Connected Whether the gateway reports the charge station as connected
+	Connected bool `json:"connected"`
+
+	// ConnectedAt The time the charge station connected
+	ConnectedAt *time.Time `json:"connectedAt,omitempty"`
+
+	// DisconnectedAt The time the charge station disconnected
+	DisconnectedAt *time.Time `json:"disconnectedAt,omitempty"`
+
+	// GatewayId The gateway instance the charge station is, or was last, connected to
+	GatewayId *string `json:"gatewayId,omitempty"`
+
+	// LastSeen The time a message was last received from the charge station
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+
+	// Protocol The OCPP websocket subprotocol, e.g. ocpp1.6 or ocpp2.0.1
+	Protocol *string `json:"protocol,omitempty"`
+
+	// RemoteAddress The network address of the charge station
+	RemoteAddress *string `json:"remoteAddress,omitempty"`
+
+	// SecurityProfile The OCPP security profile the charge station connected with
+	SecurityProfile *int `json:"securityProfile,omitempty"`
+
+	// Status Online if the charge station is connected and has been seen recently, otherwise offline
+	Status ChargeStationConnectionStatus `json:"status"`
+}
+
+// ChargeStationConnectionStatus Online if the charge station is connected and has been seen recently, otherwise offline
+type ChargeStationConnectionStatus string
+
 // ChargeStationInstallCertificates The set of certificates to install on the charge station. The certificates will be sent
 // to the charge station asynchronously.
 type ChargeStationInstallCertificates struct {
@@ -178,6 +349,103 @@ type ChargeStationTrigger struct {
 // ChargeStationTriggerTrigger defines model for ChargeStationTrigger.Trigger.
 type ChargeStationTriggerTrigger string
 
+// ChargingProfile A charging profile to install on a charge station
+type ChargingProfile struct {
+	// ChargingProfileId The unique identifier of the charging profile
+	ChargingProfileId   int                                `json:"chargingProfileId"`
+	ChargingProfileKind ChargingProfileChargingProfileKind `json:"chargingProfileKind"`
+
+	// ChargingProfilePurpose The purpose of the charging profile, a ChargePointMaxProfile is sent as a ChargingStationMaxProfile to OCPP 2.0.1 charge stations
+	ChargingProfilePurpose ChargingProfileChargingProfilePurpose `json:"chargingProfilePurpose"`
+
+	// ChargingSchedule A charging schedule
+	ChargingSchedule ChargingSchedule `json:"chargingSchedule"`
+
+	// ConnectorId The connector (EVSE for OCPP 2.0.1) the charging profile applies to, 0 for the whole charge station
+	ConnectorId    int                            `json:"connectorId"`
+	RecurrencyKind *ChargingProfileRecurrencyKind `json:"recurrencyKind,omitempty"`
+
+	// StackLevel The level in the hierarchy stack of charging profiles, higher values have precedence
+	StackLevel int `json:"stackLevel"`
+
+	// Status The status of the charging profile on the charge station (ignored on create/update)
+	Status *ChargingProfileStatus `json:"status,omitempty"`
+
+	// TransactionId The transaction the charging profile applies to, only valid for a TxProfile. For an OCPP 1.6 charge station this must be the integer transaction id or the transaction id reported by the API
+	TransactionId *string    `json:"transactionId,omitempty"`
+	ValidFrom     *time.Time `json:"validFrom,omitempty"`
+	ValidTo       *time.Time `json:"validTo,omitempty"`
+}
+
+// ChargingProfileChargingProfileKind defines model for ChargingProfile.ChargingProfileKind.
+type ChargingProfileChargingProfileKind string
+
+// ChargingProfileChargingProfilePurpose The purpose of the charging profile, a ChargePointMaxProfile is sent as a ChargingStationMaxProfile to OCPP 2.0.1 charge stations
+type ChargingProfileChargingProfilePurpose string
+
+// ChargingProfileRecurrencyKind defines model for ChargingProfile.RecurrencyKind.
+type ChargingProfileRecurrencyKind string
+
+// ChargingProfileStatus The status of the charging profile on the charge station (ignored on create/update)
+type ChargingProfileStatus string
+
+// ChargingSchedule A charging schedule
+type ChargingSchedule struct {
+	ChargingRateUnit        ChargingScheduleChargingRateUnit `json:"chargingRateUnit"`
+	ChargingSchedulePeriods []ChargingSchedulePeriod         `json:"chargingSchedulePeriods"`
+
+	// Duration The duration of the schedule in seconds
+	Duration *int `json:"duration,omitempty"`
+
+	// MinChargingRate The minimum charging rate supported by the EV
+	MinChargingRate *float64   `json:"minChargingRate,omitempty"`
+	StartSchedule   *time.Time `json:"startSchedule,omitempty"`
+}
+
+// ChargingScheduleChargingRateUnit defines model for ChargingSchedule.ChargingRateUnit.
+type ChargingScheduleChargingRateUnit string
+
+// ChargingSchedulePeriod A period within a charging schedule
+type ChargingSchedulePeriod struct {
+	// Limit The charging rate limit in the charging rate unit of the schedule
+	Limit        float64 `json:"limit"`
+	NumberPhases *int    `json:"numberPhases,omitempty"`
+
+	// StartPeriod The start of the period in seconds from the start of the schedule
+	StartPeriod int `json:"startPeriod"`
+}
+
+// CommandResult The response from the charge station to a command
+type CommandResult struct {
+	// AdditionalInfo Additional information about the status (OCPP 2.0.1 only)
+	AdditionalInfo *string `json:"additionalInfo,omitempty"`
+
+	// ReasonCode The reason for the status (OCPP 2.0.1 only)
+	ReasonCode *string `json:"reasonCode,omitempty"`
+
+	// RemoteStartId The identifier that the charge station will use to report the started transaction (OCPP 2.0.1 only)
+	RemoteStartId *int `json:"remoteStartId,omitempty"`
+
+	// Status The status returned by the charge station, e.g. Accepted or Rejected
+	Status string `json:"status"`
+
+	// TransactionId The transaction identifier if a transaction was already started (OCPP 2.0.1 only)
+	TransactionId *string `json:"transactionId,omitempty"`
+}
+
+// CompositeSchedule The composite schedule reported by the charge station for a connector
+type CompositeSchedule struct {
+	// ChargingSchedule A charging schedule
+	ChargingSchedule *ChargingSchedule `json:"chargingSchedule,omitempty"`
+
+	// ConnectorId The connector identifier (EVSE identifier for OCPP 2.0.1), 0 for the whole charge station
+	ConnectorId int `json:"connectorId"`
+
+	// RetrievedAt The time the composite schedule was retrieved from the charge station
+	RetrievedAt   time.Time  `json:"retrievedAt"`
+	ScheduleStart *time.Time `json:"scheduleStart,omitempty"`
+}
+
 // Connector defines model for Connector.
 type Connector struct {
 	Format      ConnectorFormat    `json:"format"`
@@ -197,16 +465,75 @@ type ConnectorPowerType string
 // ConnectorStandard defines model for Connector.Standard.
 type ConnectorStandard string
 
+// ConnectorMapping Maps an OCPI connector to an OCPP connector
+type ConnectorMapping struct {
+	// ConnectorId The id of the OCPP connector on the EVSE
+	ConnectorId int `json:"connectorId"`
+
+	// Id The id of the OCPI connector
+	Id string `json:"id"`
+}
+
+// ConnectorStatus The status reported by the charge station for a connector
+type ConnectorStatus struct {
+	// ConnectorId The connector identifier, 0 for the whole charge station
+	ConnectorId int `json:"connectorId"`
+
+	// ErrorCode The error code reported by the charge station (OCPP 1.6 only)
+	ErrorCode *string `json:"errorCode,omitempty"`
+
+	// EvseId The EVSE identifier (connector identifier for OCPP 1.6), 0 for the whole charge station
+	EvseId int `json:"evseId"`
+
+	// Info Additional free format information related to the error (OCPP 1.6 only)
+	Info *string `json:"info,omitempty"`
+
+	// Status The OCPP connector status, e.g. Available, Charging (OCPP 1.6) or Occupied (OCPP 2.0.1)
+	Status string `json:"status"`
+
+	// Timestamp The time the status was reported
+	Timestamp time.Time `json:"timestamp"`
+
+	// VendorErrorCode The vendor-specific error code (OCPP 1.6 only)
+	VendorErrorCode *string `json:"vendorErrorCode,omitempty"`
+
+	// VendorId The vendor-specific implementation identifier (OCPP 1.6 only)
+	VendorId *string `json:"vendorId,omitempty"`
+}
+
 // Evse defines model for Evse.
 type Evse struct {
-	Connectors []Connector `json:"connectors"`
-	EvseId     *string     `json:"evse_id"`
+	// ChargeStationEvseId The id of the EVSE on the charge station (the connector id for OCPP 1.6).
+	// If not set, the EVSE is made up of the whole charge station.
+	ChargeStationEvseId *int `json:"charge_station_evse_id,omitempty"`
+
+	// ChargeStationId The id of the OCPP charge station that makes up the EVSE. If not set, the
+	// charge station is found by the EVSE mapping service.
+	ChargeStationId *string     `json:"charge_station_id,omitempty"`
+	Connectors      []Connector `json:"connectors"`
+	EvseId          *string     `json:"evse_id"`
 
 	// Uid Uniquely identifies the EVSE within the CPOs platform (and
 	// suboperator platforms).
 	Uid string `json:"uid"`
 }
 
+// EvseMapping Maps an OCPI EVSE to the EVSE of an OCPP charge station
+type EvseMapping struct {
+	// ChargeStationEvseId The id of the EVSE on the charge station (the connector id for OCPP 1.6). If not set, the EVSE
+	// is made up of the whole charge station.
+	ChargeStationEvseId *int `json:"chargeStationEvseId,omitempty"`
+
+	// ChargeStationId The id of the OCPP charge station that makes up the EVSE
+	ChargeStationId string `json:"chargeStationId"`
+
+	// Connectors Maps the OCPI connectors to OCPP connectors: unmapped connectors have the same id as the OCPP connector
+	Connectors *[]ConnectorMapping `json:"connectors,omitempty"`
+
+	// EvseId The eMI3 EVSE ID of the EVSE, e.g. GB*TWK*E12345*1
+	EvseId *string `json:"evseId,omitempty"`
+}
+
 // GeoLocation defines model for GeoLocation.
 type GeoLocation struct {
 	Latitude  string `json:"latitude"`
@@ -230,6 +557,117 @@ type Location struct {
 // LocationParkingType defines model for Location.ParkingType.
 type LocationParkingType string
 
+// MeterPublicKey The public key of the meter for an EVSE, used to verify signed meter values
+type MeterPublicKey struct {
+	// EvseId The EVSE identifier (connector identifier for OCPP 1.6)
+	EvseId int `json:"evseId"`
+
+	// PublicKey The hex encoded DER SubjectPublicKeyInfo of the meter's ECDSA public key (as used by OCMF)
+	PublicKey string `json:"publicKey"`
+}
+
+// Party An OCPI party that has registered with the CSMS
+type Party struct {
+	// CountryCode The ISO-3166 alpha-2 country code of the party
+	CountryCode string `json:"countryCode"`
+
+	// Endpoints The module endpoints of the party for the OCPI version used by the CSMS
+	Endpoints *[]PartyEndpoint `json:"endpoints,omitempty"`
+
+	// PartyId The id of the party
+	PartyId string `json:"partyId"`
+
+	// Role The role of the party
+	Role PartyRole `json:"role"`
+
+	// Url The URL of the party's versions endpoint
+	Url string `json:"url"`
+
+	// Versions The OCPI versions supported by the party
+	Versions *[]PartyVersion `json:"versions,omitempty"`
+}
+
+// PartyRole The role of the party
+type PartyRole string
+
+// PartyEndpoint A module endpoint of a party
+type PartyEndpoint struct {
+	// Identifier The OCPI module identifier, e.g. locations
+	Identifier string `json:"identifier"`
+
+	// Role The interface role of the endpoint
+	Role PartyEndpointRole `json:"role"`
+
+	// Url The URL of the endpoint
+	Url string `json:"url"`
+}
+
+// PartyEndpointRole The interface role of the endpoint
+type PartyEndpointRole string
+
+// PartyVersion An OCPI version supported by a party
+type PartyVersion struct {
+	// Url The URL of the party's version details endpoint
+	Url string `json:"url"`
+
+	// Version The OCPI version, e.g. 2.2
+	Version string `json:"version"`
+}
+
+// PriceComponent defines model for PriceComponent.
+type PriceComponent struct {
+	// Price The price excluding VAT per kWh for energy, per hour for times or per transaction for flat fees
+	Price float64 `json:"price"`
+
+	// StepSize The minimum amount billed in Wh for energy and in seconds for times
+	StepSize int `json:"stepSize"`
+
+	// Type The dimension that is priced
+	Type PriceComponentType `json:"type"`
+
+	// Vat The VAT percentage that applies to the price
+	Vat *float64 `json:"vat,omitempty"`
+}
+
+// PriceComponentType The dimension that is priced
+type PriceComponentType string
+
+// QueuedCall A call made by the CSMS that is queued for delivery to the charge station
+type QueuedCall struct {
+	// Action The OCPP action, e.g. Reset
+	Action string `json:"action"`
+
+	// Attempts The number of times the call has been sent to the charge station
+	Attempts int `json:"attempts"`
+
+	// ExpiresAt The time the call will be discarded if it has not been answered
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// LastAttemptAt The time the call was last sent to the charge station
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+
+	// MessageId The OCPP message identifier of the call
+	MessageId string `json:"messageId"`
+
+	// OcppVersion The OCPP version of the call, e.g. ocpp1.6 or ocpp2.0.1
+	OcppVersion string `json:"ocppVersion"`
+
+	// Priority Calls with a higher priority are delivered first
+	Priority int `json:"priority"`
+
+	// QueuedAt The time the call was queued
+	QueuedAt time.Time `json:"queuedAt"`
+
+	// Request The OCPP request payload
+	Request map[string]interface{} `json:"request"`
+
+	// State Pending if the call is waiting to be sent, InFlight if it has been sent and is waiting for a response
+	State QueuedCallState `json:"state"`
+}
+
+// QueuedCallState Pending if the call is waiting to be sent, InFlight if it has been sent and is waiting for a response
+type QueuedCallState string
+
 // Registration Defines the initial connection details for the OCPI registration process
 type Registration struct {
 	// Status The status of the registration request. If the request is marked as `REGISTERED` then the token will be allowed to
@@ -251,6 +689,33 @@ type Registration struct {
 // endpoints.
 type RegistrationStatus string
 
+// ResetCommand Request to reset the charge station
+type ResetCommand struct {
+	// EvseId The EVSE to reset instead of the whole charge station (OCPP 2.0.1 only)
+	EvseId *int `json:"evseId,omitempty"`
+
+	// Type The type of reset: Hard resets immediately, Soft resets once no transactions are in progress
+	Type ResetCommandType `json:"type"`
+}
+
+// ResetCommandType The type of reset: Hard resets immediately, Soft resets once no transactions are in progress
+type ResetCommandType string
+
+// StartTransactionCommand Request to start a transaction
+type StartTransactionCommand struct {
+	// EvseId The EVSE to start the transaction on (connector identifier for OCPP 1.6)
+	EvseId *int `json:"evseId,omitempty"`
+
+	// IdToken The token to start the transaction for
+	IdToken string `json:"idToken"`
+
+	// TokenType The type of the token (OCPP 2.0.1 only)
+	TokenType *StartTransactionCommandTokenType `json:"tokenType,omitempty"`
+}
+
+// StartTransactionCommandTokenType The type of the token (OCPP 2.0.1 only)
+type StartTransactionCommandTokenType string
+
 // Status HTTP status
 type Status struct {
 	// Error The error details
@@ -260,6 +725,104 @@ type Status struct {
 	Status string `json:"status"`
 }
 
+// StopTransactionCommand Request to stop a transaction
+type StopTransactionCommand struct {
+	// TransactionId The transaction identifier
+	TransactionId string `json:"transactionId"`
+}
+
+// Tariff An OCPI tariff
+type Tariff struct {
+	// CountryCode The country code of the CPO that owns the tariff
+	CountryCode string `json:"countryCode"`
+
+	// Currency The ISO 4217 code of the currency of the tariff
+	Currency string `json:"currency"`
+
+	// Elements The elements of the tariff: the first element that applies prices each dimension
+	Elements []TariffElement `json:"elements"`
+
+	// EndDateTime The time until which the tariff is valid
+	EndDateTime *time.Time `json:"endDateTime,omitempty"`
+
+	// EvseUids The EVSEs that the tariff applies to
+	EvseUids *[]string `json:"evseUids,omitempty"`
+
+	// LastUpdated The date the record was last updated (ignored on create/update)
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+
+	// LocationIds The locations that the tariff applies to
+	LocationIds *[]string `json:"locationIds,omitempty"`
+
+	// MaxPrice The maximum cost of a transaction excluding VAT
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+
+	// MinPrice The minimum cost of a transaction excluding VAT
+	MinPrice *float64 `json:"minPrice,omitempty"`
+
+	// PartyId The party id of the CPO that owns the tariff
+	PartyId string `json:"partyId"`
+
+	// StartDateTime The time from which the tariff is valid
+	StartDateTime *time.Time `json:"startDateTime,omitempty"`
+
+	// TokenGroupIds The token groups that the tariff applies to
+	TokenGroupIds *[]string `json:"tokenGroupIds,omitempty"`
+
+	// Type The type of tariff
+	Type *TariffType `json:"type,omitempty"`
+}
+
+// TariffType The type of tariff
+type TariffType string
+
+// TariffElement defines model for TariffElement.
+type TariffElement struct {
+	PriceComponents []PriceComponent `json:"priceComponents"`
+
+	// Restrictions Restrictions that limit when a tariff element applies
+	Restrictions *TariffRestrictions `json:"restrictions,omitempty"`
+}
+
+// TariffRestrictions Restrictions that limit when a tariff element applies
+type TariffRestrictions struct {
+	// DayOfWeek The days of the week on which the element applies
+	DayOfWeek *[]TariffRestrictionsDayOfWeek `json:"dayOfWeek,omitempty"`
+
+	// EndDate The date until which the element applies, exclusive
+	EndDate *openapi_types.Date `json:"endDate,omitempty"`
+
+	// EndTime The time of day (UTC) until which the element applies
+	EndTime *string `json:"endTime,omitempty"`
+
+	// MaxDuration The duration of the transaction in seconds until which the element applies
+	MaxDuration *int `json:"maxDuration,omitempty"`
+
+	// MaxKwh The energy delivered in kWh until which the element applies
+	MaxKwh *float64 `json:"maxKwh,omitempty"`
+
+	// MaxPower The power in kW until which the element applies
+	MaxPower *float64 `json:"maxPower,omitempty"`
+
+	// MinDuration The duration of the transaction in seconds from which the element applies
+	MinDuration *int `json:"minDuration,omitempty"`
+
+	// MinKwh The energy delivered in kWh from which the element applies
+	MinKwh *float64 `json:"minKwh,omitempty"`
+
+	// MinPower The power in kW from which the element applies
+	MinPower *float64 `json:"minPower,omitempty"`
+
+	// StartDate The date from which the element applies
+	StartDate *openapi_types.Date `json:"startDate,omitempty"`
+
+	// StartTime The time of day (UTC) from which the element applies
+	StartTime *string `json:"startTime,omitempty"`
+}
+
+// TariffRestrictionsDayOfWeek defines model for TariffRestrictions.DayOfWeek.
+type TariffRestrictionsDayOfWeek string
+
 // Token An authorization token
 type Token struct {
 	// CacheMode Indicates what type of token caching is allowed
@@ -305,6 +868,27 @@ type TokenCacheMode string
 // TokenType The type of token
 type TokenType string
 
+// UnlockConnectorCommand Request to unlock a connector
+type UnlockConnectorCommand struct {
+	// ConnectorId The connector identifier
+	ConnectorId int `json:"connectorId"`
+
+	// EvseId The EVSE of the connector (required for OCPP 2.0.1)
+	EvseId *int `json:"evseId,omitempty"`
+}
+
+// ListChargeStationConnectionsParams defines parameters for ListChargeStationConnections.
+type ListChargeStationConnectionsParams struct {
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// LookupCompositeScheduleParams defines parameters for LookupCompositeSchedule.
+type LookupCompositeScheduleParams struct {
+	// ConnectorId The connector identifier (EVSE identifier for OCPP 2.0.1), 0 for the whole charge station
+	ConnectorId int `form:"connectorId" json:"connectorId"`
+}
+
 // ListTokensParams defines parameters for ListTokens.
 type ListTokensParams struct {
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
@@ -320,6 +904,27 @@ type RegisterChargeStationJSONRequestBody = ChargeStationAuth
 // InstallChargeStationCertificatesJSONRequestBody defines body for InstallChargeStationCertificates for application/json ContentType.
 type InstallChargeStationCertificatesJSONRequestBody = ChargeStationInstallCertificates
 
+// SetChargingProfileJSONRequestBody defines body for SetChargingProfile for application/json ContentType.
+type SetChargingProfileJSONRequestBody = ChargingProfile
+
+// ChangeAvailabilityJSONRequestBody defines body for ChangeAvailability for application/json ContentType.
+type ChangeAvailabilityJSONRequestBody = ChangeAvailabilityCommand
+
+// ResetChargeStationJSONRequestBody defines body for ResetChargeStation for application/json ContentType.
+type ResetChargeStationJSONRequestBody = ResetCommand
+
+// StartTransactionJSONRequestBody defines body for StartTransaction for application/json ContentType.
+type StartTransactionJSONRequestBody = StartTransactionCommand
+
+// StopTransactionJSONRequestBody defines body for StopTransaction for application/json ContentType.
+type StopTransactionJSONRequestBody = StopTransactionCommand
+
+// UnlockConnectorJSONRequestBody defines body for UnlockConnector for application/json ContentType.
+type UnlockConnectorJSONRequestBody = UnlockConnectorCommand
+
+// SetMeterPublicKeyJSONRequestBody defines body for SetMeterPublicKey for application/json ContentType.
+type SetMeterPublicKeyJSONRequestBody = MeterPublicKey
+
 // ReconfigureChargeStationJSONRequestBody defines body for ReconfigureChargeStation for application/json ContentType.
 type ReconfigureChargeStationJSONRequestBody = ChargeStationSettings
 
@@ -329,9 +934,15 @@ type TriggerChargeStationJSONRequestBody = ChargeStationTrigger
 // RegisterLocationJSONRequestBody defines body for RegisterLocation for application/json ContentType.
 type RegisterLocationJSONRequestBody = Location
 
+// RegisterEvseMappingJSONRequestBody defines body for RegisterEvseMapping for application/json ContentType.
+type RegisterEvseMappingJSONRequestBody = EvseMapping
+
 // RegisterPartyJSONRequestBody defines body for RegisterParty for application/json ContentType.
 type RegisterPartyJSONRequestBody = Registration
 
+// RegisterTariffJSONRequestBody defines body for RegisterTariff for application/json ContentType.
+type RegisterTariffJSONRequestBody = Tariff
+
 // SetTokenJSONRequestBody defines body for SetToken for application/json ContentType.
 type SetTokenJSONRequestBody = Token
 
@@ -346,6 +957,9 @@ type ServerInterface interface {
 	// Lookup a certificate
 	// (GET /certificate/{certificateHash})
 	LookupCertificate(w http.ResponseWriter, r *http.Request, certificateHash string)
+	// List charge station connections
+	// (GET /connection)
+	ListChargeStationConnections(w http.ResponseWriter, r *http.Request, params ListChargeStationConnectionsParams)
 	// Register a new charge station
 	// (POST /cs/{csId})
 	RegisterChargeStation(w http.ResponseWriter, r *http.Request, csId string)
@@ -355,18 +969,99 @@ type ServerInterface interface {
 	// Install certificates on the charge station
 	// (POST /cs/{csId}/certificates)
 	InstallChargeStationCertificates(w http.ResponseWriter, r *http.Request, csId string)
+	// List the charging profiles for the charge station
+	// (GET /cs/{csId}/charging-profiles)
+	ListChargingProfiles(w http.ResponseWriter, r *http.Request, csId string)
+	// Install a charging profile on the charge station
+	// (POST /cs/{csId}/charging-profiles)
+	SetChargingProfile(w http.ResponseWriter, r *http.Request, csId string)
+	// Clear a charging profile from the charge station
+	// (DELETE /cs/{csId}/charging-profiles/{chargingProfileId})
+	ClearChargingProfile(w http.ResponseWriter, r *http.Request, csId string, chargingProfileId int)
+	// Change the availability of the charge station
+	// (POST /cs/{csId}/commands/change-availability)
+	ChangeAvailability(w http.ResponseWriter, r *http.Request, csId string)
+	// Clear the authorization cache of the charge station
+	// (POST /cs/{csId}/commands/clear-cache)
+	ClearCache(w http.ResponseWriter, r *http.Request, csId string)
+	// Reset the charge station
+	// (POST /cs/{csId}/commands/reset)
+	ResetChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// Start a transaction on the charge station
+	// (POST /cs/{csId}/commands/start-transaction)
+	StartTransaction(w http.ResponseWriter, r *http.Request, csId string)
+	// Stop a transaction on the charge station
+	// (POST /cs/{csId}/commands/stop-transaction)
+	StopTransaction(w http.ResponseWriter, r *http.Request, csId string)
+	// Unlock a connector of the charge station
+	// (POST /cs/{csId}/commands/unlock-connector)
+	UnlockConnector(w http.ResponseWriter, r *http.Request, csId string)
+	// Returns the composite schedule for a connector
+	// (GET /cs/{csId}/composite-schedule)
+	LookupCompositeSchedule(w http.ResponseWriter, r *http.Request, csId string, params LookupCompositeScheduleParams)
+	// Returns the connection state of the charge station
+	// (GET /cs/{csId}/connection)
+	LookupChargeStationConnection(w http.ResponseWriter, r *http.Request, csId string)
+	// List the meter public keys for the charge station
+	// (GET /cs/{csId}/meter-public-keys)
+	ListMeterPublicKeys(w http.ResponseWriter, r *http.Request, csId string)
+	// Register a meter public key for the charge station
+	// (POST /cs/{csId}/meter-public-keys)
+	SetMeterPublicKey(w http.ResponseWriter, r *http.Request, csId string)
+	// Delete a meter public key
+	// (DELETE /cs/{csId}/meter-public-keys/{evseId})
+	DeleteMeterPublicKey(w http.ResponseWriter, r *http.Request, csId string, evseId int)
+	// List the calls queued for the charge station
+	// (GET /cs/{csId}/queue)
+	ListQueuedCalls(w http.ResponseWriter, r *http.Request, csId string)
+	// Cancel a queued call
+	// (DELETE /cs/{csId}/queue/{messageId})
+	CancelQueuedCall(w http.ResponseWriter, r *http.Request, csId string, messageId string)
 	// Reconfigure the charge station
 	// (POST /cs/{csId}/reconfigure)
 	ReconfigureChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// List the connector statuses for the charge station
+	// (GET /cs/{csId}/status)
+	ListConnectorStatuses(w http.ResponseWriter, r *http.Request, csId string)
+	// Returns the status of a connector
+	// (GET /cs/{csId}/status/{evseId}/{connectorId})
+	LookupConnectorStatus(w http.ResponseWriter, r *http.Request, csId string, evseId int, connectorId int)
 
 	// (POST /cs/{csId}/trigger)
 	TriggerChargeStation(w http.ResponseWriter, r *http.Request, csId string)
 	// Registers a location with the CSMS
 	// (POST /location/{locationId})
 	RegisterLocation(w http.ResponseWriter, r *http.Request, locationId string)
+	// Delete an EVSE mapping
+	// (DELETE /location/{locationId}/evse/{evseUid}/mapping)
+	DeleteEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string)
+	// Lookup an EVSE mapping
+	// (GET /location/{locationId}/evse/{evseUid}/mapping)
+	LookupEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string)
+	// Maps an EVSE to a charge station
+	// (POST /location/{locationId}/evse/{evseUid}/mapping)
+	RegisterEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string)
+	// List OCPI parties
+	// (GET /party)
+	ListParties(w http.ResponseWriter, r *http.Request)
+	// Deregister an OCPI party
+	// (DELETE /party/{countryCode}/{partyId})
+	DeregisterParty(w http.ResponseWriter, r *http.Request, countryCode string, partyId string)
+	// Rotate the token issued to an OCPI party
+	// (POST /party/{countryCode}/{partyId}/rotate-token)
+	RotatePartyToken(w http.ResponseWriter, r *http.Request, countryCode string, partyId string)
 	// Registers an OCPI party with the CSMS
 	// (POST /register)
 	RegisterParty(w http.ResponseWriter, r *http.Request)
+	// Delete a tariff
+	// (DELETE /tariff/{tariffId})
+	DeleteTariff(w http.ResponseWriter, r *http.Request, tariffId string)
+	// Lookup a tariff
+	// (GET /tariff/{tariffId})
+	LookupTariff(w http.ResponseWriter, r *http.Request, tariffId string)
+	// Registers a tariff with the CSMS
+	// (POST /tariff/{tariffId})
+	RegisterTariff(w http.ResponseWriter, r *http.Request, tariffId string)
 	// List authorization tokens
 	// (GET /token)
 	ListTokens(w http.ResponseWriter, r *http.Request, params ListTokensParams)
@@ -454,6 +1149,42 @@ func (siw *ServerInterfaceWrapper) LookupCertificate(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// ListChargeStationConnections operation middleware
+func (siw *ServerInterfaceWrapper) ListChargeStationConnections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListChargeStationConnectionsParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListChargeStationConnections(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // RegisterChargeStation operation middleware
 func (siw *ServerInterfaceWrapper) RegisterChargeStation(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -532,8 +1263,8 @@ func (siw *ServerInterfaceWrapper) InstallChargeStationCertificates(w http.Respo
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ReconfigureChargeStation operation middleware
-func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request) {
+// ListChargingProfiles operation middleware
+func (siw *ServerInterfaceWrapper) ListChargingProfiles(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -548,7 +1279,7 @@ func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWrite
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ReconfigureChargeStation(w, r, csId)
+		siw.Handler.ListChargingProfiles(w, r, csId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -558,8 +1289,8 @@ func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWrite
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// TriggerChargeStation operation middleware
-func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r *http.Request) {
+// SetChargingProfile operation middleware
+func (siw *ServerInterfaceWrapper) SetChargingProfile(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -574,7 +1305,7 @@ func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.TriggerChargeStation(w, r, csId)
+		siw.Handler.SetChargingProfile(w, r, csId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -584,8 +1315,565 @@ func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// RegisterLocation operation middleware
-func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *http.Request) {
+// ClearChargingProfile operation middleware
+func (siw *ServerInterfaceWrapper) ClearChargingProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "chargingProfileId" -------------
+	var chargingProfileId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "chargingProfileId", runtime.ParamLocationPath, chi.URLParam(r, "chargingProfileId"), &chargingProfileId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chargingProfileId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearChargingProfile(w, r, csId, chargingProfileId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ChangeAvailability operation middleware
+func (siw *ServerInterfaceWrapper) ChangeAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ChangeAvailability(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ClearCache operation middleware
+func (siw *ServerInterfaceWrapper) ClearCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearCache(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ResetChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) ResetChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResetChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StartTransaction operation middleware
+func (siw *ServerInterfaceWrapper) StartTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartTransaction(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StopTransaction operation middleware
+func (siw *ServerInterfaceWrapper) StopTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StopTransaction(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UnlockConnector operation middleware
+func (siw *ServerInterfaceWrapper) UnlockConnector(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnlockConnector(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupCompositeSchedule operation middleware
+func (siw *ServerInterfaceWrapper) LookupCompositeSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LookupCompositeScheduleParams
+
+	// ------------- Required query parameter "connectorId" -------------
+
+	if paramValue := r.URL.Query().Get("connectorId"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "connectorId"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "connectorId", r.URL.Query(), &params.ConnectorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "connectorId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupCompositeSchedule(w, r, csId, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupChargeStationConnection operation middleware
+func (siw *ServerInterfaceWrapper) LookupChargeStationConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupChargeStationConnection(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListMeterPublicKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListMeterPublicKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListMeterPublicKeys(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetMeterPublicKey operation middleware
+func (siw *ServerInterfaceWrapper) SetMeterPublicKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetMeterPublicKey(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteMeterPublicKey operation middleware
+func (siw *ServerInterfaceWrapper) DeleteMeterPublicKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "evseId" -------------
+	var evseId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "evseId", runtime.ParamLocationPath, chi.URLParam(r, "evseId"), &evseId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "evseId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteMeterPublicKey(w, r, csId, evseId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListQueuedCalls operation middleware
+func (siw *ServerInterfaceWrapper) ListQueuedCalls(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListQueuedCalls(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CancelQueuedCall operation middleware
+func (siw *ServerInterfaceWrapper) CancelQueuedCall(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "messageId" -------------
+	var messageId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "messageId", runtime.ParamLocationPath, chi.URLParam(r, "messageId"), &messageId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "messageId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelQueuedCall(w, r, csId, messageId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ReconfigureChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReconfigureChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListConnectorStatuses operation middleware
+func (siw *ServerInterfaceWrapper) ListConnectorStatuses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListConnectorStatuses(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupConnectorStatus operation middleware
+func (siw *ServerInterfaceWrapper) LookupConnectorStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "evseId" -------------
+	var evseId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "evseId", runtime.ParamLocationPath, chi.URLParam(r, "evseId"), &evseId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "evseId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "connectorId" -------------
+	var connectorId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "connectorId", runtime.ParamLocationPath, chi.URLParam(r, "connectorId"), &connectorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "connectorId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupConnectorStatus(w, r, csId, evseId, connectorId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// TriggerChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.TriggerChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RegisterLocation operation middleware
+func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "locationId" -------------
+	var locationId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "locationId", runtime.ParamLocationPath, chi.URLParam(r, "locationId"), &locationId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locationId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterLocation(w, r, locationId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteEvseMapping operation middleware
+func (siw *ServerInterfaceWrapper) DeleteEvseMapping(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -599,8 +1887,172 @@ func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *ht
 		return
 	}
 
+	// ------------- Path parameter "evseUid" -------------
+	var evseUid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "evseUid", runtime.ParamLocationPath, chi.URLParam(r, "evseUid"), &evseUid)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "evseUid", Err: err})
+		return
+	}
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.RegisterLocation(w, r, locationId)
+		siw.Handler.DeleteEvseMapping(w, r, locationId, evseUid)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupEvseMapping operation middleware
+func (siw *ServerInterfaceWrapper) LookupEvseMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "locationId" -------------
+	var locationId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "locationId", runtime.ParamLocationPath, chi.URLParam(r, "locationId"), &locationId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locationId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "evseUid" -------------
+	var evseUid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "evseUid", runtime.ParamLocationPath, chi.URLParam(r, "evseUid"), &evseUid)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "evseUid", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupEvseMapping(w, r, locationId, evseUid)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RegisterEvseMapping operation middleware
+func (siw *ServerInterfaceWrapper) RegisterEvseMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "locationId" -------------
+	var locationId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "locationId", runtime.ParamLocationPath, chi.URLParam(r, "locationId"), &locationId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locationId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "evseUid" -------------
+	var evseUid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "evseUid", runtime.ParamLocationPath, chi.URLParam(r, "evseUid"), &evseUid)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "evseUid", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterEvseMapping(w, r, locationId, evseUid)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListParties operation middleware
+func (siw *ServerInterfaceWrapper) ListParties(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListParties(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeregisterParty operation middleware
+func (siw *ServerInterfaceWrapper) DeregisterParty(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "countryCode" -------------
+	var countryCode string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "countryCode", runtime.ParamLocationPath, chi.URLParam(r, "countryCode"), &countryCode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "countryCode", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "partyId" -------------
+	var partyId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "partyId", runtime.ParamLocationPath, chi.URLParam(r, "partyId"), &partyId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "partyId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeregisterParty(w, r, countryCode, partyId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RotatePartyToken operation middleware
+func (siw *ServerInterfaceWrapper) RotatePartyToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "countryCode" -------------
+	var countryCode string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "countryCode", runtime.ParamLocationPath, chi.URLParam(r, "countryCode"), &countryCode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "countryCode", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "partyId" -------------
+	var partyId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "partyId", runtime.ParamLocationPath, chi.URLParam(r, "partyId"), &partyId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "partyId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RotatePartyToken(w, r, countryCode, partyId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -625,6 +2077,84 @@ func (siw *ServerInterfaceWrapper) RegisterParty(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// DeleteTariff operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTariff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "tariffId" -------------
+	var tariffId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "tariffId", runtime.ParamLocationPath, chi.URLParam(r, "tariffId"), &tariffId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tariffId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTariff(w, r, tariffId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupTariff operation middleware
+func (siw *ServerInterfaceWrapper) LookupTariff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "tariffId" -------------
+	var tariffId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "tariffId", runtime.ParamLocationPath, chi.URLParam(r, "tariffId"), &tariffId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tariffId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupTariff(w, r, tariffId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RegisterTariff operation middleware
+func (siw *ServerInterfaceWrapper) RegisterTariff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "tariffId" -------------
+	var tariffId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "tariffId", runtime.ParamLocationPath, chi.URLParam(r, "tariffId"), &tariffId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tariffId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterTariff(w, r, tariffId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // ListTokens operation middleware
 func (siw *ServerInterfaceWrapper) ListTokens(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -824,6 +2354,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/certificate/{certificateHash}", wrapper.LookupCertificate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/connection", wrapper.ListChargeStationConnections)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}", wrapper.RegisterChargeStation)
 	})
@@ -833,18 +2366,99 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/certificates", wrapper.InstallChargeStationCertificates)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/charging-profiles", wrapper.ListChargingProfiles)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/charging-profiles", wrapper.SetChargingProfile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cs/{csId}/charging-profiles/{chargingProfileId}", wrapper.ClearChargingProfile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/change-availability", wrapper.ChangeAvailability)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/clear-cache", wrapper.ClearCache)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/reset", wrapper.ResetChargeStation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/start-transaction", wrapper.StartTransaction)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/stop-transaction", wrapper.StopTransaction)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/commands/unlock-connector", wrapper.UnlockConnector)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/composite-schedule", wrapper.LookupCompositeSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/connection", wrapper.LookupChargeStationConnection)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/meter-public-keys", wrapper.ListMeterPublicKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/meter-public-keys", wrapper.SetMeterPublicKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cs/{csId}/meter-public-keys/{evseId}", wrapper.DeleteMeterPublicKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/queue", wrapper.ListQueuedCalls)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cs/{csId}/queue/{messageId}", wrapper.CancelQueuedCall)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/reconfigure", wrapper.ReconfigureChargeStation)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/status", wrapper.ListConnectorStatuses)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/status/{evseId}/{connectorId}", wrapper.LookupConnectorStatus)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/trigger", wrapper.TriggerChargeStation)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/location/{locationId}", wrapper.RegisterLocation)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/location/{locationId}/evse/{evseUid}/mapping", wrapper.DeleteEvseMapping)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/location/{locationId}/evse/{evseUid}/mapping", wrapper.LookupEvseMapping)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/location/{locationId}/evse/{evseUid}/mapping", wrapper.RegisterEvseMapping)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/party", wrapper.ListParties)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/party/{countryCode}/{partyId}", wrapper.DeregisterParty)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/party/{countryCode}/{partyId}/rotate-token", wrapper.RotatePartyToken)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/register", wrapper.RegisterParty)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/tariff/{tariffId}", wrapper.DeleteTariff)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tariff/{tariffId}", wrapper.LookupTariff)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/tariff/{tariffId}", wrapper.RegisterTariff)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/token", wrapper.ListTokens)
 	})
@@ -861,68 +2475,162 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xbX3MaO5b/KqrefUi22gbbiWvjlykCxGZiAwU4t2YuKSy6D6Bxt9RXUtthXP7uW0dq",
-	"QTctjOfOZCebuy8J+tPSkc7v/NXxUxCJNBMcuFbBxVOgohWk1Pxsg9RswSKqAZsxqEiyTDPBg4ugRaKE",
-	"AdckKs0Kg0yKDDvArBC9tMJkBWTYvSHAIxFDXF6IPDK9IhweE8ZBEQlZQiOIyXxN7qZTfheEgV5nEFwE",
-	"SkvGl8HzcxhI+C1nEuLg4tfKxl83k8X8bxDp4DkM2isqlzDWFGlp5XpVJ68tOIcIGyQGTVmiyEJIQklk",
-	"viXKflw785wqOH83vmqdvj8fUqUehYz9h7cz3flDMr5qHZ2+PycrqlZELIhewc5mJHMLhkFKv10DXyLp",
-	"5+9q9xEGjD/QhMW3CiSnKbSSRDyCh5LegijQRAuiZQ64KSeUk+Jzkhffk0eWJIQLTTIJD8h4D3lRcWd8",
-	"ueXQXIgEKEeSFES5ZHo9lGLBkj2QcJNIZmchZbkCc/n1LS/If5G75h05Ijk3X0JMtKRcZUJqC6M5VSwi",
-	"NNcrnHuCcyfXY9/YaWWsju8p3x6LcQ1LkDXk7Z7xIPp6XGmaJCVhU/suRiMqSvQovBtmvyeCe67nmOCX",
-	"lU8MH+e4HNdTjmyv85GqNY9WUnCRq2R9POUvSbZpMw3p76L736gzwgDPm+8j24yFJIYFzRNtaB4Cjy24",
-	"gecpsrsVRZBpQIEcAfLX/HTzvnr2tB1PmxW+nF4GYXAzwH8+BWHQHt+MPR/uwMyMhgf1XNFBpaTrl5Sk",
-	"OozTMWgUbHNbNI4Z9tFkWOFd/RbvYU2YMhgzWqRQa8oudkw+CUkG7eGQnB43j0+289RK5ElMVvTBqCSy",
-	"EKi/GF+SjGoNkl9M+TRvNs+ijfkyTWjY3gcqGZ0nYDsLMXAz7RaR0XJRkseACk9k9kSlaQaiPCpIojwm",
-	"8KCAsHjKFWRUUm3xpSBlR5FIBFd2J7f7yxttZtX3oVpLNs9R5SBXyMvbpfQbS/OUJMYeGE1p7vTk+Bwv",
-	"/32zaQScRhqkstJcsh4nzWbTg9MqLx3399nAl7EzkWyJyrIOETtQW5HQyGtc9XYhJz8fhdB9UQDZfjM2",
-	"orvbyZb8y+llu+KuYKehlPFlQatngkjnjEPc9grbPgEtKPXKlTWTwpyjesCFkCnV5fONB+3P3QkqhtbH",
-	"665XpTBj1mvdKf02o2kGki6hvDbarrNTjymznzyIRL/+i0w8gpztKrVWe3YyG161xt0gxMbZptFpe4+A",
-	"AhBT6ym5RdpXrU7XKMb2VWvw5x5+Pbjpjie99qxVbnwsN9rlRqfc6JYbn8qNy3LjqtyobPrncuNzuXEd",
-	"hMHlx8ms1S5+dPBHr9uenTfPmh9mpzPF+DKB2cn5Tr9eSdjbbe683n3+znWfnnw4n01Odpqz9uDm46Da",
-	"ebrT9M05a+208RD97k1r9n522nS/z2dnpd/vN79PmqWBk2Z55F155J0dGbb6k8HlqDW8mn0cTCaDm9nt",
-	"sNo9GQxnncEv/SAMJt3xdWs22vwaB2Fw2//cx9GDolig2MjJjlRUEV9BcwmTPhnuPiioi2/kJLvqFP2n",
-	"hEVwEfxHYxtrNYpAq7FVBjV7HQZob2ZWvHmeJGgtggv00z0ilDOPd3/L2W85JGvCYuCovcAa4+6Xcdd4",
-	"TMy6je3hQJEsoRovi7yhHG1cPsezUS3kZki9PT4YfOXmngvfKizfie8iL0Fci0JL1+4zoZrpPAavfksE",
-	"X+4b3SFps075Kx81ZVJqAW/VRCUi8keANI4lKOWlOWJ67R8QQsaMO3/6JcSUb8x8mXMt961qxmboLXsn",
-	"IMBej1UD+udwHxY3sEU/5lWYzai8Z3xZtx/Xg/7l7GYwGYx+af3FqIXR517/cnbZGrUuu6WO6wHaxkF/",
-	"1hn1vnTt5EF/Np6MusZq3vY73dHlaHDb77iPv4avIkyvZ3sMayYwdNlc6oHFdqDo0FFgYcu/HW5VIVGi",
-	"yAfbESyZ0nIPdDuwMDERCjrjTDPj5XrTGzhl0B72iCytiKF4ZGmuIv1w/OQc/spyeB2g9DHpuUHTRl81",
-	"pfIeYkIVuRt1L3vjSXfU7dzZrARO1eIe+CaGpTapQbSY8jmQXJnfhEZILY4S4HEmGNeK0AfBMCizMQZA",
-	"fPi8LxM45XfDbr/T61/66RM8WVeJdIThxLuGiDLWeACpmODqLnQ9p8endyYG2LYbkQSjvmmi7qZ8cybr",
-	"yjuZKYjBSHRzc/4IFGn0M82SX8q4RCJNc268aL60ITZSDzfjIXnTHnU73f6k17oezyaDz93+rGXMw6HU",
-	"VC4T//a3o2sHGLODu50NGw1HMikeGEb/xnCNb8b2vmmkkS02vOQxSLfUZhWHuyDcera5ZAcNmr0wn9yN",
-	"9wjA1WQyJBsLWBUakFJI//nNkJPH35ewIOWBQwcrlvOdbOIHSYubbJmQ7O9WVOzd1JI4NFrBTaEfd/KN",
-	"PHaZqBXVxIS3yCiDPPwOgcaUE5tyruX6l9Zf0PNrXV8Pful2tr9mg0+frnv9rvExv3RHXthHgmuMgXt7",
-	"0rFunPQ65A3ctHqdt4QqJSJmAu8N9i2lb0zbkzQoQnUh1VujtU22IrgI3vzaOvorPfr716fT57dvjv70",
-	"dttxVu1oHn34+vSh3vf2T0G418S3vZdtz2UmELQqTiSYUjneM0pZVWBPwyBlvNSqbbiUIs/8l8gUYTEx",
-	"ExTG9CLPki13TQotpfdA9KMgQpJUSHBDj0Leo/gKDlWCzs59Ea9SuS+f0CvOheygfB2SVCjtDq1ZCvVU",
-	"VDGVZBKj2tjlUUefeh0SURmHJunNATU3lSxZb9STjxsJ5cucLmE/OzIJC5ASYuLmOn3rkppUkd54QM7P",
-	"PhydbCcVTsE/xKqEKn2bxYhfPzE4VFi4SMiYPFJF8COS26/IG7bkQtpriSRQDQ079LasRLHjCK832OdK",
-	"7RM6M4igOQjMs8ppz17IrHrMWlnJlDVKZ3Y1aM9ux10MLVvDofs5mFyZ/xEFXmXiDbZwq9wEXIWSYPEr",
-	"sGzeWXxQJhoFyq5kJ/keVR6YymnSz9M57LEqdkZDAo1tUtLMbbiIMHI+zwb/lG/hf/ipraR/tswOXYba",
-	"BoMl3bsRXnfysGQt6pbo2TxlLUQRXWsameyYjTCCv675PSUaaFpPWfY4al6akNawZx8cNBj9vtHk9mv0",
-	"IEIC34rZ9tlHuQw0CiY6iOg0JCwCbsP+Yv9WhqST0+OmASDTyZYqk8gPg8L7CC6C5nHTzhMZcJqx4CI4",
-	"M13GTKyM4WzsPH9gqOGJ6rNE0Nio2NojFSmedHB7mx42LlJEuTmLrr4HGa80R/dVF09cnuegXKFIprnO",
-	"aWLfx5y7iw2bIzAOFpVA5oCTxWKBJBZuL8HfR3OaUB6BtG7r5jNUDcWJqrnXwl37KOK14z5wcxs0y5IC",
-	"t42/KRvy2Bj1YLaltMNzFcoYvJkOlQleRMWnzRPPy7DRg7FFnHkc+peRV/iThrLdRA58y8z7kvUSjRyq",
-	"PE0phv7F/SEgKgcMK4BqPJUaV1Stnu3hEvC9tXVM/z6Qoe+2oorMATjJsy2zN065RQ3deeauvHJPeaH2",
-	"O90Rma81KB82LCFVbKCPlYIGqYKLX58ChgSjEAUu+RDsHDXYZXVYYsnLAcvz1xoq3tWvqy+Ig8BzGLyz",
-	"U74zKPpCk4XI+Y+FRcuvXSyGwRI8quxaiPs8+/eDzNLxQ4Gs+f203o5CK925Cz7/4BjewrKmT1XjKVK9",
-	"+Hm/ebbZOJCoOzk8emsy1FppSIvMhVJ5WsC9bn6nHEUAg5A1aCsKJgOCXgVGCzy2q5h6B181B+PGBme2",
-	"KMF0w5QrQZg2boFZMhJ8wZamfsZYd6ZNFgWPMBcYAJVeU33y485cefety5AnPN0h1r1ToIPokzhlfEiv",
-	"WJ3+9x6x+g5+RK2A7GfyJhwzvfjdEYMGLcrnvOp9BDqX3EbdLs/sLonM11tFvqQaHukalXuMcEkZB7IS",
-	"j69xUPer8xqXfhBAfi8970flDuB2Ki1yvSKOov89tX/L77l45DVs/VBSsMVuCYKlJ5NdUditinPmoYpN",
-	"V/FXZlal/O+PoTV9hY+vUqLNupoZfP6hkFMcrVrz6C3Q3EWQhI0h3u9fjHM8EiirnIv5RToefYgidTEH",
-	"Ys6+dZnrjghThBnnYsoZ3ljhX7vHNIr2fwkcJE1qtcjOCTHJAYhWlDOVhug54KrFalO+EJIIbtOvuAZf",
-	"QskGxLk0j3KgbEFga4F2Z3sNZq/Q6xa5V0AJ6KFATJQoHvd2bwW9HE3vgcBiAZEmbGFq7mRuOKiF36HZ",
-	"cOKP6NNsyj1/EpEssfMVYliqM/Tr8KJw8Y+IDFfM+X8YGIbbrn6n8eR+vTqocx9sU8km2/pCWHRdKhY6",
-	"hJHN6ofQsaU7+EcTDf96jGxLkn7CQGg/063mkMW8V8GH24oT+/RVRRDpgAvTnbtQMWT+0ocpB6ZXIIvi",
-	"HpN7q9SzbDaxexZ1L46AJCGPlOlNPYztNy8mxXJTvm/BQ7gf4lrfKZdfKXr6SVG3HysWeJtaHn9ylSlt",
-	"66DcAzy6hzbns62WKuo5YFOF74unmdKmJETtyYv+loOpYStUk1gsFOiqWmKcpXkaXDR9f0TlXyZhKdO7",
-	"ys2uctJshts1Tzxr/rOR9qtKIm2dTP1PbWo8xxvclkL8WMlOJM1T1qNcseM+sVGoSmw9AGrIonrs92Js",
-	"DBZi30ldFJz6ifREu1yQgbrCV5q1VRONJ/PfLbNuzsvPMf8kL+06jp2HH1IcZa99QfFUUnzXzFoJPDsF",
-	"cfUr//83lOobyj5cmr8Ilg8vO8IJieEBEpGltrgO5wdFCWmw0jq7aBhPPlkJpS8+vDtpNmjGGg/N4Pnr",
-	"8/8EAAD//5j/13ndPgAA",
+	"H4sIAAAAAAAC/+x9a3PbOrLgX0Fxb9VJpuR34r3HX24pspLoxra0kpzU3OOsApOQhAkF8ACgHU3W/30L",
+	"LxIkQZGynTOexF8SiwTxaHQ3uhv9+B6EdJVQgojgwcn3gIdLtILqzx5iAs9xCAWSPyPEQ4YTgSkJToIu",
+	"CGOMiACh06oTJIwm8gFSPYSbepguERj1zwEiIY1Q5HYEbrFYAoJuY0wQBwwlMQxRBK7X4MvVFfkSdAKx",
+	"TlBwEnDBMFkEd3edgKE/U8xQFJz8URj4c9aYXv8DhSK46wS9JSQL1L2BOIbXOMZi3aOrFSRRdZpj9GeK",
+	"uACCglB9BcQSAeh8CuhcPQuXkC0Q4ALKTzsAEtD/OOkDygAEISUEhYKyKpDsm0HkB1LWwA6kes3n82LY",
+	"G43A4e7+7gGgJF6/DDrBChO8SlfByUG2ekwEWiAml49uOKobrdx5PjqOEJFQRQzMKQNq1IPd45cdNanb",
+	"JY3LMAB4DggVgCPhzmnfNycJEvURjCcCipT7p1cAvLMnNOgEiMje/wiGuqsbFHSCAaHZr89NaFOdQw3y",
+	"sAWa6CV2U7GszrSngSZBECEBccwVyGAJPhVcuIYcHb+avO8evj4eQc5vKavZJt3SEk8HTN53dw5fH4Ml",
+	"5Es/QoLEdtgJVvDbGSILOfXjVxWodAJMbmCMo0uOGIEr1I1jeos8MxnM5d7KfRAsVZRBJNqbz0Fqvge3",
+	"OI4VJiQM3SAifNMziCZnkM3omtIYQSKnxFGYMizWI0bnOK7hJ7YRSHQrObOUIwX86pAn4G/gy/4XsANS",
+	"or5EERAMEp5QJjQPuoYchwCmYinbHsi207OJ791h4V2VOV6RoIr2Jfwrr7ER+3I8q4GHkNyUziuY9xvP",
+	"4E2J2r8lAgso0C1cb2Lj7yFfbkLIlMUZQ/eiZAUuEiU4IkKz9+omBR70DF0Y1LLNEivKuJe3Rw0MH45/",
+	"WiKxRMyFkDyRKBPch8YwgyyKvIicve0K/8QFXqENBKK6nVO2giI4CSIo0I78wreqCPN7j+Z+23pAA5+6",
+	"LTGvASZcQBJ6x8W8I0/MW8hBDLno5MvWTL4ypmw1QYhsWB4EK8Q5XKCsW8BQiPANisCc0ZUf7dotOWFU",
+	"0JDG/uHVIXmLrjkNvyIBeHpt23cA2l3sAhomycHusVyy/FOd4r5hGFpRgbpRxBCvORoJEreUfQVQN/Kf",
+	"Ar6+W7FWtZIqf92ApooTBr6zntcc8EMiBT4pNXgRw+kakkjyFXCNEAFc/iM3lIh43QFUkust5pLxzWWH",
+	"jmhAiXlgXzXKBGVek83eZRqNjHogMT6OHZGa151gQm6cwyC5ZM9Yfw8o8YBmF8gvC5+oA/dadkfEFRHU",
+	"8xWAfE3CJaOEpjxe716RTYxf/cYCre4173+hZlCPbfaATHkHRGgO01ioOY8QibQUYrGmG4Yo0XxwjP5h",
+	"WaJt99kzpn7wPevh4+G7oBOcD+U/b4NO0JucT5pxT73tNGoz5gFkDK43qUItxNkJElICU9CCUYS1MDwq",
+	"7F0Vil/RWpKnxDEl7hnOw3Vnu+CtVRe0kpK140uaxpKQbzQnmVMpaGKyAAkUAjFyckWu0v39ozBTUtVP",
+	"tKef3kCG4XWM9ENDBralHiJU4mgYpxECkACa6BU5zbLDSLWXjEWqRwBHV4SjBDJoRBOOVngnpDElXI9k",
+	"R988UNaqOg4UguHrVMqGclfA5uFW8JtUn0CsBPeCCiaB/3p/XxE4DAViXFOzI+Yf7O/v+ySEwl7a3a9T",
+	"VjbjzpThhWTwVRTRLyo9Ahh6tSCRd2Tp5w2l4oIaRNbfaA2t/BAvyMfDd72CUUI+VDPFZGEF52oDurrG",
+	"BEU9L7HVEaiZaS1dYbKoPVW7GhwK3XN1xeH0jepiWByiTuxKCf4zRa7+7ooGzvjew7o0yAdMIndnutec",
+	"xqkC41hKB0yzzjGK65TuSpejlCWU1xwEiX5ZN+UOgEBj4YhiIs7hN9OppAl5+kmBHILS9jvNBHVZUxHg",
+	"3DkDvIMEnWD67VSfHe6jqgJXXfwkXKIo1YjxHwzNg5Pgf+3lxrg9Y4nb65Xb33W2sRm9UAadeYEDv/SC",
+	"EsAkibE6tztgP9OZfYadRmsOU6iASLgu48spxPE66ASfEPoar70Q4gKGX8/QDaoRqmP5CmAtCi0xYpCF",
+	"yzVQnykhpLQw3gFLvFgiBm5gnCKuD5yEoRBFiISocTXNEkQdevoFNvACLwhlKJKvQ4agQHtpItWMlw7G",
+	"5XKIX/64oGKSJlIRVT97MYJso0zCIOGa5dahjdOkGUWkrRFoQ48+LzK81yc+JPkBVVq/WGIOVikXUkaV",
+	"AxlgFyaAI2BQsPRUa9+5waA7GvjkPjW1t4yu5FrbKXPqkylt+4FPVSiw4yKlFjC7lgv6Oa6Hb2w6dFzm",
+	"UnvqcNuo7lwZQ4EuCRYFfi9ptxVjGyGGaVRUHLZhdPp72fMKk4Hu4aAs7XaCKNU2Wz9G27eZTGo6B5hI",
+	"bZaSiHtPvRUmPQcK/s4N18ghyqBAgKdJET/7HwvWBJpeu0ctSVfXGZthwt25B+BgtnX1+9IGf3RbHxYl",
+	"6o3Sx3AmqmzEqhivsNhgq8sAqBpa/l58lRIsylvZDrj6r9EScj0bI04HJ0dNtyVqW+oAYY4Als3KwCVH",
+	"r9zCVGjnzH7T4VO2EDtz6RiIevdRX2eNEU/jGpgzxBNKOMrnV2bSVN1bqY4qm5krhwMypx4Eyd4DTPT2",
+	"yD7hNU0FEPm56bu88hi/IJfG7gjVLUW+zySW7bqWdrWJBGvdoejIzWIJvXcXytwiLxoENadTvuEoKpxf",
+	"G2a1ncTBkEgZqTObG/OilRzkSeqIDg+VDRyQYHm54L6TJlYYMwSjdQaAFltRRfTa+zd5dHAsUP0hlxkC",
+	"ZLOc55cFh9Iuzptuav9ayd0BshbifRewWp5vIa/7ZHTBMLppczVQBaXc5ayDBxvRbbeKDu978hUkLXdx",
+	"fiQyreVoxW22Y+cyz2TY+9CfShm7++as7xV+sNrOyuMV/DaDqwQxuCie6JiIo0O/5AG/zW5oLNp/kdBb",
+	"xGZlk2O3NzuYjd53J32pQfRmR9mP016d2kUiyAraWu9997SvzJa9993hf0s5+3R43p9MB71Z1/3xxv3R",
+	"c3+cuj/67o+37o937o/37o/CoP/t/vjg/jgLOsG7N9NZt2f+OJV/DPq92fH+0f7vs8MZx2QRo9nBcem5",
+	"WDJU+/jo0Pv4+JV9fHjw+/FselD6OesNz98Miw8PSz99bY66pd9yERf98+7s9exw3/59PDty/n6d/X2w",
+	"77w42HffvHLfvNJvRt2L6fDduDt6P3sznE6H57PLUfHxdDianQ4/XQSdYNqfnHVn4+yvSdAJLi8+XMi3",
+	"jYYyg8WKTkpUUcT4AjY7OLmRhs9hkshRK1zsHCbc6KEDh7MKah6OHuKXgyMryxV7sjq/5NmNrji4TeeD",
+	"wjQdq+7RcRPgcVkJ3QjISRux42Fn6H3Ov3sdb4gxyuplRvUayAumpiW9yKwYtVJko0OVe5a38ai6x3px",
+	"kxw+Z0h5wqygKMjkTJpq1fU6EBlgWix6k4xaogh7zaZlUu3CJW23VkLKh3sJJCDCME1wUW70TkEKBlzA",
+	"VdIgwBjc1UJLZjFraRNCJKKsvxmbdKMdnqBQXh642NUCkvrrOgQq941XSYxWiFQcW5qHKjEHg7YeK5W+",
+	"4M7B6+Ma/Rtts/cIyWhmMHUmh5g1MzntKOm3lYoSVyjSyu4VGWRehp28M2lghBECaWLH8FHSbqPtt7Qe",
+	"3PIwKNs7oQAr+BVxOR87x11QmvkVqfo8zGlKHEvSpA9W+rQDHLEbHKLdDf5MlG1hfrOfVK+UNYMzaydp",
+	"rKg3OBEsRZ6xUx+MLtUdVLzO0ZXnKzJGJPm7NxpykMRQSOIELyCR17DptfbMpCx7xV/uNqJ3Wjz8eC0W",
+	"t5MfrGdsjq/zXIxocVFn70n7Gw6LR6EI4COIK/K4FNHgfXcfeijKNq/+sxGvPZtVlZh4dsWXPzoBKZFk",
+	"hCK3XeaHwOUNPY4A5B7xLuhsSU8Wu2rIqg6C6HxwpLFgcOoihTlF37352/TTh7/1Dw6PXr3+20Ej7Brc",
+	"mnyk8Q7RMxpmxvWSNRcKLFJ9HlZ98ihZ1L0tTSTrx/3KNxt3Kt4rjRy9Yhr66RDm/nNV1MJi7X9BKYsw",
+	"sU5Qm/bchZj6MiWC1fWq3s1CWgNDiRvtubc6jO86ddw5wzgCV6gVF08g+4rJompWOBtevJudD6fD8afu",
+	"35W2OP4wuHg3e9cdd9/1nQdnw2nQCYYXs9Px4GNfNx5ezCbTcV8ZUy4vTvvjd+Ph5cWp/fhzp9XExHpW",
+	"Y29JqPSeyIDa0FkJFS12GFzI96+0W0WUcGbkQ9tzJBAbpdcxDj+gdZ2Pg3yt3KgMoa/kV1qZIobqU65l",
+	"9BvE8HwNOF4QFJmG+l67gu6PrJY0ngzJ5mUu0bfMp++0PwaTVEEpA468SSgA4DcO+r3TSdcF0AvINSiu",
+	"12DYO3+7hZSbT8+3USO5ix7mYk5/tcn64FoqNWKBuUDMuLlq2UU69VUVXoU59arDYDLcOTo4PgYwTpZw",
+	"5xCYL7T2YG+V1OQKPP5Q7Ybzq8pBSJRQbAK7qgOvqLLkZq0KY2X6p1r8DWJcMlYLeGe1rbiTgm3fDOQ7",
+	"CNWYzbKEBwxHBTAcecDAaN0VgXxT7jqzfI6kZa5/PpEWsfeX0r550ZV/X6gnw+n7vrSFTXqTkdeamrIa",
+	"75XL8VlhzN+4BS/P9sJVTlOG/Tqj/qZW/x7k3VZupO1i22/eR91Xo7upAnangPX57mqo1BJfhiCeE76E",
+	"rDqmxS6jSHE5+9oAG9Ofa2NSYpUVHXiwFSZhIhCbw7CIU852ZlcJfXnqSUeefq8/+Ngf3xt5WuNKxSLo",
+	"BMKY7dq4LXbra1mj5Q4FNKvbnXvQRRZDtz15NFOH2ffD3cNGyNk+N8CL4RD1LAlVheZEvq+RAeQrgL5J",
+	"D2Kp3X/sTkGCGPj6Sfv6IoLYYt1Rz5Y01UezMtAAqRaXfKfky3kMBZgjxF1wbfI+QckE/7PB1QWuJGWD",
+	"axzHSHk5FKan3Jpd1wc7x0bRwYqZ1ZEjvEKEZ1oj5hpUkUNU/Yv++J0UL9+edaeOBDodnEuBVP332evu",
+	"VXPzaYAfIiIvJfTAueubxlG1lS0g6/fpt59nUPeh0/9JUYqingyg8Ck90k1YafTOiZwB6U/1rdqBCMX4",
+	"BrE18MaBVJWkDQGFShbUDQzhjJGO7q0AFwqBVkmd8KGhowhe4bCal1yQE9VDRO2MqwiEviWYId58ly0H",
+	"seExMsgNMimP4jnAWrIjVOgJQMJvpYDX2lIsA8u6etUtp2Gj0Tautd3gJsJtEG3YONPG5wmug3QqvcqY",
+	"tI8NrHSU8Wmnsy3D2xKGKcM++VtiP9cyNrRuvLY1gAxZ7Ja4jhkXXuTQxNB+U3T71rBnOkfABgiZFiCB",
+	"65jCyBdLITfcwwKNR28WFCeniOVVBhbysaA2zKsDBuRtjBdL4SBzTkmKM+ff6Xs66wPm9Tq23TXf7ua4",
+	"V8SYTpBFeFgQOVttl+ywCmenXIr28caxUsDqXD9P0VwFiGnfYiywCvnxBuVnSg5zegQJo6G2BRSZY3tX",
+	"8EJ3ZvnKNKtfqt/6loJ9RcrW+GXcfzeYTPvj/ukXoGLpZVNBvyKScSyoQ/GBoFfkGmVGARjK2cq3jj4H",
+	"byhWuCNUdKg5DTaud/MEr8iXUf/idHDxzj8/5RFemKSdmGz4ZY+GCd6zWsmXjn1yuHv4ReFn/nsvZEgx",
+	"KRjzL1ckW5OOa8pwVU9GSdMWcn7XdznHGuJX03fyBEiPx5SokCKyyJV7dD4ZgRe9cf+0fzEddM8ms+nw",
+	"Q/9i1n25W1RIvQkVWgn0coSKKqh2JGH0BstjKjvmFbxhKKyNmiMS5ew868Xi3bZKggaYn+44Em2ypTCk",
+	"UkM0yxttspHozjDhAsFo0/XFPbKh1Iue8o0cTI1+At5DFum/OcCrFYowFEhGHE/oXNgXVIX4UVca5+qc",
+	"worIFsbCaFFY9inNCHQuWsaD+jZF+c1N8xHb7I/2Ri74bt5ra3Q/5TANuRPbWRRr3GOmLUjXP4V5C28Z",
+	"wxqmGQqoUK7gJBhMhgevXr06Cjob8CJnfz6syyxJiAgGY/nkvDuQ7nBO5/LP18e/yz8/IGsvkXcIsv05",
+	"DLuZTfqCylwzlOF/ajL63KzoT2upuM7R5/10OgLZ/X8JGRijbJMzjTlV7xeDDdwXD/AOngiabE0JNGkg",
+	"hHv7R2/rr1UcyLfAKWR4Pq+3xgj9fms7tM/s3BsNtUJJb4k+Z7Let7JE22DAWgs4eHV48L8LQ9tP7G/f",
+	"wM22X6Q9ZWowz74tjnGi/lbKhG1RtAAo3Z0DBMNlbp1oa03V29fX/TYGNyESnUKBpniFNuguKRE4BrdL",
+	"HC6ddQDMdXheaz1GsvtLHPF6hs/zEAgzSG4WcSFQkxAhX5hUey9VyGMNOclXRhINKYtyXVkHSkabYyhb",
+	"KuzG2DuoW7Jt8GjLXsnQ4VoboA3xDyk3Fm6XqRRsg+1seitMNg2HyeMOt/EWR7107nLa8ZZmEleHfwsi",
+	"UTEKD6cRdd6/YzRNarFGNQEL2ebREKdZSM2gl93Un87eD3uzUffv5/0LZRYdD98Ozvqz3vt+d+T8ftud",
+	"uK/fjfv9C61TXZ51xy1S5NRc9mRc3+HD9SeaZYl+o3mvkKWy3b1V4btGVsuQXJ4W2Nsx8bH7RRko5VnX",
+	"L3xcGrgsp+RvNTLp8MhblWvPYpU9pwx2VU7/CK6HcxlyX8ds19kheIvQV8lTc1Kpdp7B3+La+fDiVDl4",
+	"TC/7E/3Xp/7phf17+v5ybP58Ox7oPybd6eXY/Hmpvq5PpFM5EDecGeXDsDT7jmZsXKeHLNB84L9Db+Ar",
+	"dC7BB15cTnsvmwZX5CEEYrKH//vij/2dg89/7O/8/vn/Hf6xv3P0+eXJH/s7r/Wj//BaeeG3063inQtS",
+	"aX4x0zxPb2DSh9ua7H/m+ie3xWKibq6ax2lzisFvIxkUUnOuyFd6vEcaDpNHgXHpwGkFYky2BnHjMC3l",
+	"hHYQfozRshN7AxVvMU4N5apRtqHdxiEfQrp3PvbvN210CYCupq/liapCB8MlOveqcwMS2TxwSvqwEoLs",
+	"B8jv1IUCt3ZaV2Y4+9T9+yToBN2zs+Gn/mn+12z49u3Z4KKvYsjq/BZCSgSDodgQ3KPeS5fWF8oa8hJA",
+	"zmmIoXA9qYxRRf32pOwyibIo4y8L2/Lij+7O/8Cdf37+fnj38sXOf73MHxwVH8hd+v579dnL//J7099D",
+	"d8acpxLOSLsQbaUvL7R86RsMcylDG+ESypGTON9ddT0tHauBuKWAMrCiDNlXOjskB5SgFoYxOX8fRxiY",
+	"dcntgGTdASujPghDVdVEcKYpSBhWiV6NW/v47eAUyPvXjrp0JUheFUCG43VmD/dfs5JFCheofjsShuaI",
+	"SR5p21oDv3U/hFxZHY6Pft85yBsZ786ttupJKLJbKV71iNmsbLVQQwyzKmkhlxPl9dQdjeyf1oNOYoHf",
+	"CQpvzmemRgI4aoHLWsXzoLLORKR7snpgNWXvDeYpjC/0AeaP0VIt9hiCkU4JqNruWTtgaC/ZMvyHJEf/",
+	"4L4Klvqqk8e5WN6bEa9decc5LXyKyCWJafg1i1hoYzRN1SePH+v58OT1GdPJUqBZWJbTJjSMtSHBQBWI",
+	"d04IptwKGCpVVvvbB/+zJl8hEAiuqlkXB0QgJg+47migMwAKpA7J7DjUX8t7P6m9mNY6lTa3STQld5P2",
+	"312VFyZEhCNn/G4i918uW1ExFnE+K+PIm3nMBfu7+7odTRCBCQ5OgiP1SJ21S7W3e6UMrgn1+T9cJtLb",
+	"QZ1T1cTfxt9FDq8zXMq/VB5NuRaxROXWUiqSaKLTqXsCo1Iu+doqFSmMdS52e0ktf2TZ/fV93DWSjWUe",
+	"YAojc1kN5N871zCGJERMXzZnnw2ibEXF9JHmkvUNjdZ2940hQ8mOmvj3/sG1PqFtCI3RQ84Id0VMFCxF",
+	"6oH23VDbcbh/4HGdUYdJpDHOXGw90vTM/ZGaWWnLCfqW6CTN+sJINuHpagXZOoOfRIjCAjsFhNr7Xko5",
+	"f6cXFyOfznCqntch2RI6njBpkm92dpWusQaWSioU0tdfEcNUZNjC9Vog7sMNPZEibkhBVcUy8ODkj+8B",
+	"lhOWRBTYUJxKdv3yVnecLdnsZnD3uYIVr6rguqDAosBdJ3ilm/xgpLigQgeyPilc1PtVxsVOsEAeVnZG",
+	"6dc0+dcjmZ7Hk0Ky/R/H9UoMLX+dXTb/4jico2WFnxZqg/hxGnNbxCJrnNcMQcpn2Be5u3SLXOiD05R2",
+	"6ADKImWdul5Xo9kzIc+L2JiLmuImvAbH/0wRW+dITudz7Yvs4PLG1Hr+bnRWvRJFGCFRJtHeKDI+lDra",
+	"Z8qsgskTnFNBJwnlLEdutfiOusl4Sugtp7tprgrT+d73kA+iu3pBdGzC9aSUQNCtt4ACX3OBVsazjvN0",
+	"hery/V0R6yi+RsZZXHnoSfnZFKdQvajiBJ7vASaKaBLj2ysfoyvCKcBCCcCqy5CSOV6oqkRKjsXKT1xJ",
+	"09eUCjl+plr6CMquuYAtVUrarnKO72zhOmuI7wA5/M+aA+QHSMyVslw/k9xsN9OLvyUy2IOmKJmX6Y9V",
+	"3kjN9q0fdKYgu5EkhqVL/h5JdFlhgsCS3rZRxeoFl8ouPRGE/FESjR8rSwhXXJ8Ebu6a/5cJOJfkK6G3",
+	"pIJbT4oKctx1UNBx6S+TQrmEjT0eirhpy/MUzlX3y1+Da/qqFLViovueWk4fnhTmmKUVCxR50+tUMMjk",
+	"KNux1QTaiNPlCgRWbr5B+mw3W+mEZJTQB8pkKMoCqNlrq+x7G8XqPKE8/wl47lYZ5c26t5aP3R18emKx",
+	"H9H8+GRzo1SRdiIo08azUlcaZY11+RrZwjT59Z23CFmpkyviViIDrQqRAU9hnMwUblMzmW6viC0Btgt6",
+	"pY84eEFN5UZI8uIU/KUy/zK0ozZOyutzgbxEyJAUtL1mmAkqU9VPfka4ZPQTydX2XIAtS7c0ng573ytl",
+	"QDZar82VmjkhPNPAkuuv6IY025r0zM7nlNGa4pTC6uMmeZy0mnykgpu8Ny86JY5EgAhFPnpR1WmeHsV0",
+	"NlajyLagaVRP4Zd8Ci1sRM9m+g0kqlDHRxk15FAhUX2bzfd0rfAdt4T4JouRS5fVUUzlcX6vcvBXJL+d",
+	"lsTHHL2mUqjZ4oqXrCqF7H/qY6imaH97HeVxJlIoruJB6DblVTRV7/8F5PUGRvZweArGhNf7h3/B+FOf",
+	"NGfOJ5tNQqaW6GuGI6f16l8zrQhHypRspgcwUV50T4sFK9Jryerq+a9k5DvKE+khfFf2wgEWvOQmqzpu",
+	"ZKdXRAO6hp8qMUXN8Ce3Sz7zsGce9mvxMCVGWstxmW1sx8lUrocH8DCTK6K96Ae6TvYJlco7q21LwMDm",
+	"otCvVbfQSUphmg3JIIptm41lb/23iRyJX+QqsZDj5Fm4fGbMz4z5h17q1SQKquO+KrJrx03XcX9OrPri",
+	"pdD3F+O8KuS0lFXT5q7pXBHTeX0zU2z7QRp+ufefl+vWJTF6ZsDPDPiZAf9ABjypZgJrefmRc2SaPBpD",
+	"pkktP6aJl8+W2DFNfiA3pskvwoy9abSeefEzL37mxT+UF9PkQaxYx2XuhIX6xvdjxbor7sZ4Pox7lsJM",
+	"f17uWRNP+8w9n7nnM/f8kdGllaD01oZdXeB+hzu1/Rvd5z118VUyDIZCRES83rJY8u4Vmfp7xW6xfRkM",
+	"ImOzapyFHD89pi/L/B45xh/fDjaxC3+qTjm+ZMIvylXMSsJ+i0LKvgiwYjncDZGSm6LLfvDtXWnXfFEF",
+	"FTz61T2JGmi3RI5VJtEYUOkOkMVResWkvDPromfDbawznmSuAGY1O2zaGHl/HSK80R2w60Rn1sVvZmVO",
+	"OEIkq8B7i3XA2hVZIsjENVK1wgViNzDWXMgwNMhV6gRM0G67WB8nTPEXivhxVl13ypYib59p1KXRalRy",
+	"82GuEGtHF3vc+YrWbQI21DdOmclCgUe/S31dsEWx4OcvE2tRXPY2oRYV2D/NUIsqimwbapEHIYt2RV/9",
+	"+L4LJtXar1ckY81+WRMypEvHqoiHBcTErMtBdGdx8hDJf14Rp5VdtgrFMPXbtZt4FovhD5goochPq/2X",
+	"SeHh4RL/EgX8KcY+l4mwlgYbjoS97zqNWKvEQj7qL58Pmym2NlnQkyOJzuPVivbMJivFfG8dqjFS4ull",
+	"FyqjThk5VcmzNkGlqhKev+Cj5O7FknB5nmBBOyrowJQzBNfrjj8nhY7sUQlcZIO1G/6j+zJBfmoixdp7",
+	"lCAA1SWiVFtulzjmXpsy5lnsA4o6uqAfF3lFP1V2o060yuth/jJiVb7kbUQqU/xTbdQTDVxVOOQUKW1m",
+	"4qrx3ves2GBDWJ0MmVN3FzK8r0ZV/s1MQanMsl2mHsOYIRitm0qRXpEVXAMuDKGYKnrbxGf3IAlR7Gzz",
+	"Ez0D2hUQ9YzuFof0TuHouA1tPYfIbfJtVkgEoEv4ZfJhKEsoVH8nOElNeRKVZMa0dyxHeTi4DZ72koU8",
+	"JzAHWJmZrgiWsDEZ8WzRSkljYIEIYjAufZ0nU1LpPFG4hATzVQdgoQ3curcrIvmGPHjUmWPj8bJcNlEq",
+	"kQkIxOWxuAu6Ksw7B4OJy/MRda7QyABwFAGuV1mFSggJEDLfNprPUahLrxIuWGpSo/ldqbOd+BVzM02Q",
+	"kBvy06QWcbazxSmWV8JrEPd0w+0vlFRZMjpXAVKZnC5d+q1sXvqI67QkhJoCXwbVyzI/5qWsyoVsftXy",
+	"t97inPJkVR1LI7evK7Bfm8PEttb79AslMSkufKskJhmEuQXa0xQGK/NsKRLq5pkyv/fdubm7a31Lcx8q",
+	"K17b1l6uFrfuWb2vJOBsnRTet/jCNe2TuJYtkarvUraI6s/XPRVaVJUIa29hBcOLBWL1udymusGvKFmZ",
+	"pf87C1Zqt23Zzb3veYXOlsld7Qd5xihVX2BDetQzGrbGkaz3JuzI5x1sm1r78XEkW+HPmBC1ftODelza",
+	"kyeVFhsusbwfgEkit6DtZYBuXsr77rkis1cC+RTrrwL6Nxydm3n8MFS8d9b3OpvQAKR5ZSG53HrJ4BK3",
+	"nsGzSejRrh/MvZTF8IbyBj8Au3XPz9i9LXY/nljqwt6DSC5+PJdVKJZVqFKPXwQ5hwmvoQlrIc3eEK1G",
+	"lfODaecJuQeQrAH6hpXZ0g6tjZ22H30PZ14BzLVJ1WirCm9XVLo0cmNo0bEh6oc0VnKQMBSiCJEQAXqj",
+	"r/sq5Rocis/O1WxRsiv7pZupvoBMpjIj3yR+PTOGFozh8WXCCk/4ScRCRYiWbFWRBZ8FSZXQa2GDVTst",
+	"G+NCCmcfZUiJ8wRAU21xqVOuIFUVtGyVZTSuqe2DuRjp0YK/wrQ4UmDYwqBoQPH0rIjuRjlbvPfdKZ14",
+	"t/fdlE5s8PphuWpB8p7X+SVyXrJOv1CXUrJSJ4qAWDKaLpba/M6Q4lIw5gCRKKGYCOvffUXMUZuX2pF4",
+	"YXmK7ti0tQVcVVXH7MrNNGHI5of16xZ2NXqzW7DYwWS4c3RwfAxgnCzhzqG3um1ievOa5dxqla0YX1OZ",
+	"VT8fxlGL2eTVMtux4IYSqM9qyLZqCMu891xiakGke4wKVfTWlsn2i16yHjGydX1UW8X33cE0rebvbfos",
+	"l5B+85NrJ29hMwdG3Abm5T1a4Ux7ZOlawyXiTRi6wTrf8j3oeKxgoWh4asuBPxPyMyH/dfY2hYA5QjtI",
+	"7CFsS/StjLbu5yW7LThFtkgWJQVdw/EUUZ4l5gvls3hFEFaZ/zHBQiXGg0ZmYyU7oR6TMueH7ED5UoJ5",
+	"4bmgeXdXpK7DJmuzPYV/TNq6fEY/q623Hlc04gnI8Hy+913/39LBGwLdvN7kVcJKVdddfqxdWkGS8mXO",
+	"0GVhda0uXJGN+oKpCqrKNtfahadqam34vVlEk2ZsQfNsjP3rfcE17JuLzG6Hj7VW138T3Hk8U6dZsC/W",
+	"Ui/w2b5ZLBubI2Tz3apuW8S9WrtlubEp3KN5p3kpgwGU5VJQkDATeuzmvsm8ybTIsWA0TXjHOKpJ+dpa",
+	"+bjxDYWc69g4QU9kmQVnFjQVaoa6yUrC0Tq1mW0x0ypN8QcxdwvYp0uijy+guNT5E15D++jDSCVWhd1g",
+	"b1ThB1pVVGxf14EFlj5s0mqUmTRrDIhKPXyumFzCPLUBW1g69U48PUNnMXd5Pks/99YUwwFlIE0iaGRd",
+	"bSO5L45NkLAWiB/CI/RO/UQsQs9pT28AgMS3hw6b2Puu/pN+KfUcIzu+H7aXRkr0G5R8Z4uZ2ZMV/3Lk",
+	"KQKsWwX5syhYvur246VsjNjN5ivZGEToBsU0kXIV0O2DTpCyODgJlkIkJ3vKEyteUi5Ofn91sL8HE7x3",
+	"sx/cfb77/wMA9sRv8ITqAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
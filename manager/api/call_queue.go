@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Server) ListQueuedCalls(w http.ResponseWriter, r *http.Request, csId string) {
+	calls, err := s.store.ListQueuedCalls(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(calls))
+	for i, call := range calls {
+		resp[i], err = newQueuedCall(call)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) CancelQueuedCall(w http.ResponseWriter, r *http.Request, csId string, messageId string) {
+	call, err := s.store.LookupQueuedCall(r.Context(), csId, messageId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if call == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	if s.callQueue != nil {
+		err = s.callQueue.Cancel(r.Context(), csId, messageId)
+	} else {
+		err = s.store.DeleteQueuedCall(r.Context(), csId, messageId)
+	}
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newQueuedCall(call *store.QueuedCall) (*QueuedCall, error) {
+	var request map[string]interface{}
+	err := json.Unmarshal([]byte(call.RequestPayload), &request)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling %s request payload: %w", call.Action, err)
+	}
+
+	return &QueuedCall{
+		MessageId:     call.MessageId,
+		OcppVersion:   call.OcppVersion,
+		Action:        call.Action,
+		Request:       request,
+		Priority:      call.Priority,
+		State:         QueuedCallState(call.State),
+		Attempts:      call.Attempts,
+		QueuedAt:      call.QueuedAt,
+		ExpiresAt:     call.ExpiresAt,
+		LastAttemptAt: call.LastAttemptAt,
+	}, nil
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/api"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestListQueuedCalls(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	queuedAt := clock.Now().UTC().Truncate(time.Second)
+	expiresAt := queuedAt.Add(24 * time.Hour)
+	lastAttemptAt := queuedAt.Add(time.Second)
+
+	err := engine.SetQueuedCall(ctx, &store.QueuedCall{
+		ChargeStationId: "cs001",
+		MessageId:       "msg-1",
+		OcppVersion:     "ocpp1.6",
+		Action:          "TriggerMessage",
+		RequestPayload:  `{"requestedMessage":"StatusNotification"}`,
+		State:           store.QueuedCallStateInFlight,
+		Attempts:        1,
+		QueuedAt:        queuedAt,
+		ExpiresAt:       expiresAt,
+		LastAttemptAt:   &lastAttemptAt,
+	})
+	require.NoError(t, err)
+	err = engine.SetQueuedCall(ctx, &store.QueuedCall{
+		ChargeStationId: "cs001",
+		MessageId:       "msg-2",
+		OcppVersion:     "ocpp1.6",
+		Action:          "Reset",
+		RequestPayload:  `{"type":"Soft"}`,
+		Priority:        10,
+		State:           store.QueuedCallStatePending,
+		QueuedAt:        queuedAt,
+		ExpiresAt:       expiresAt,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/queue", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.QueuedCall
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+
+	want := []api.QueuedCall{
+		{
+			MessageId:   "msg-2",
+			OcppVersion: "ocpp1.6",
+			Action:      "Reset",
+			Request:     map[string]interface{}{"type": "Soft"},
+			Priority:    10,
+			State:       api.Pending,
+			QueuedAt:    queuedAt,
+			ExpiresAt:   expiresAt,
+		},
+		{
+			MessageId:     "msg-1",
+			OcppVersion:   "ocpp1.6",
+			Action:        "TriggerMessage",
+			Request:       map[string]interface{}{"requestedMessage": "StatusNotification"},
+			State:         api.InFlight,
+			Attempts:      1,
+			QueuedAt:      queuedAt,
+			ExpiresAt:     expiresAt,
+			LastAttemptAt: &lastAttemptAt,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCancelQueuedCall(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	err := engine.SetQueuedCall(ctx, &store.QueuedCall{
+		ChargeStationId: "cs001",
+		MessageId:       "msg-1",
+		OcppVersion:     "ocpp2.0.1",
+		Action:          "Reset",
+		RequestPayload:  `{"type":"Immediate"}`,
+		State:           store.QueuedCallStatePending,
+		QueuedAt:        clock.Now(),
+		ExpiresAt:       clock.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001/queue/msg-1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	call, err := engine.LookupQueuedCall(ctx, "cs001", "msg-1")
+	require.NoError(t, err)
+	assert.Nil(t, call)
+
+	req = httptest.NewRequest(http.MethodDelete, "/cs/cs001/queue/msg-1", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
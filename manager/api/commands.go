@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	handlers16 "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+)
+
+// command is the OCPP call that is made to a charge station for an API command
+type command struct {
+	request  ocpp.Request
+	response ocpp.Response
+	// result converts the response into the API result
+	result func() *CommandResult
+}
+
+// newCommandFunc returns the command to send to a charge station that uses the
+// given OCPP version. It returns an error if the API request cannot be sent to
+// the charge station.
+type newCommandFunc func(ocppVersion string) (*command, error)
+
+// runCommand sends the command to the charge station and renders the charge
+// station's response.
+func (s *Server) runCommand(w http.ResponseWriter, r *http.Request, csId string, newCommand newCommandFunc) {
+	details, err := s.store.LookupChargeStationRuntimeDetails(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if details == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	var callMaker handlers.SyncCallMaker
+	switch details.OcppVersion {
+	case "1.6":
+		callMaker = s.v16CallMaker
+	case "2.0.1":
+		callMaker = s.v201CallMaker
+	}
+	if callMaker == nil {
+		_ = render.Render(w, r, ErrInternalError(fmt.Errorf("unsupported OCPP version: %s", details.OcppVersion)))
+		return
+	}
+
+	cmd, err := newCommand(details.OcppVersion)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.commandTimeout)
+	defer cancel()
+	err = callMaker.Call(ctx, csId, cmd.request, cmd.response)
+	if err != nil {
+		var callErr *transport.Error
+		switch {
+		case errors.As(err, &callErr):
+			_ = render.Render(w, r, ErrBadGateway(err))
+		case errors.Is(err, context.DeadlineExceeded):
+			_ = render.Render(w, r, ErrGatewayTimeout(err))
+		default:
+			_ = render.Render(w, r, ErrInternalError(err))
+		}
+		return
+	}
+
+	_ = render.Render(w, r, cmd.result())
+}
+
+func newCommandResult(status string, statusInfo *ocpp201.StatusInfoType) *CommandResult {
+	result := &CommandResult{
+		Status: status,
+	}
+	if statusInfo != nil {
+		result.ReasonCode = &statusInfo.ReasonCode
+		result.AdditionalInfo = statusInfo.AdditionalInfo
+	}
+	return result
+}
+
+func (s *Server) StartTransaction(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(StartTransactionCommand)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			resp := new(ocpp16.RemoteStartTransactionResponseJson)
+			return &command{
+				request: &ocpp16.RemoteStartTransactionJson{
+					ConnectorId: req.EvseId,
+					IdTag:       req.IdToken,
+				},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		tokenType := ocpp201.IdTokenEnumTypeISO14443
+		if req.TokenType != nil {
+			tokenType = ocpp201.IdTokenEnumType(*req.TokenType)
+		}
+		remoteStartId := int(rand.Int31())
+		resp := new(ocpp201.RequestStartTransactionResponseJson)
+		return &command{
+			request: &ocpp201.RequestStartTransactionRequestJson{
+				EvseId: req.EvseId,
+				IdToken: ocpp201.IdTokenType{
+					IdToken: req.IdToken,
+					Type:    tokenType,
+				},
+				RemoteStartId: remoteStartId,
+			},
+			response: resp,
+			result: func() *CommandResult {
+				result := newCommandResult(string(resp.Status), resp.StatusInfo)
+				result.TransactionId = resp.TransactionId
+				result.RemoteStartId = &remoteStartId
+				return result
+			},
+		}, nil
+	})
+}
+
+func (s *Server) StopTransaction(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(StopTransactionCommand)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			transactionId, err := handlers16.ConvertFromUUID(req.TransactionId)
+			if err != nil {
+				return nil, err
+			}
+			resp := new(ocpp16.RemoteStopTransactionResponseJson)
+			return &command{
+				request: &ocpp16.RemoteStopTransactionJson{
+					TransactionId: transactionId,
+				},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		resp := new(ocpp201.RequestStopTransactionResponseJson)
+		return &command{
+			request: &ocpp201.RequestStopTransactionRequestJson{
+				TransactionId: req.TransactionId,
+			},
+			response: resp,
+			result: func() *CommandResult {
+				return newCommandResult(string(resp.Status), resp.StatusInfo)
+			},
+		}, nil
+	})
+}
+
+func (s *Server) ResetChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(ResetCommand)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			if req.EvseId != nil {
+				return nil, errors.New("an OCPP 1.6 charge station cannot reset an EVSE")
+			}
+			resp := new(ocpp16.ResetResponseJson)
+			return &command{
+				request: &ocpp16.ResetJson{
+					Type: ocpp16.ResetJsonType(req.Type),
+				},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		resetType := ocpp201.ResetEnumTypeOnIdle
+		if req.Type == Hard {
+			resetType = ocpp201.ResetEnumTypeImmediate
+		}
+		resp := new(ocpp201.ResetResponseJson)
+		return &command{
+			request: &ocpp201.ResetRequestJson{
+				EvseId: req.EvseId,
+				Type:   resetType,
+			},
+			response: resp,
+			result: func() *CommandResult {
+				return newCommandResult(string(resp.Status), resp.StatusInfo)
+			},
+		}, nil
+	})
+}
+
+func (s *Server) UnlockConnector(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(UnlockConnectorCommand)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			resp := new(ocpp16.UnlockConnectorResponseJson)
+			return &command{
+				request: &ocpp16.UnlockConnectorJson{
+					ConnectorId: req.ConnectorId,
+				},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		if req.EvseId == nil {
+			return nil, errors.New("an OCPP 2.0.1 charge station requires an EVSE id")
+		}
+		resp := new(ocpp201.UnlockConnectorResponseJson)
+		return &command{
+			request: &ocpp201.UnlockConnectorRequestJson{
+				EvseId:      *req.EvseId,
+				ConnectorId: req.ConnectorId,
+			},
+			response: resp,
+			result: func() *CommandResult {
+				return newCommandResult(string(resp.Status), resp.StatusInfo)
+			},
+		}, nil
+	})
+}
+
+func (s *Server) ChangeAvailability(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(ChangeAvailabilityCommand)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if req.ConnectorId != nil && req.EvseId == nil {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("a connector id requires an EVSE id")))
+		return
+	}
+
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			if req.ConnectorId != nil {
+				return nil, errors.New("an OCPP 1.6 charge station identifies connectors by EVSE id")
+			}
+			// connector 0 is the whole charge station
+			connectorId := 0
+			if req.EvseId != nil {
+				connectorId = *req.EvseId
+			}
+			resp := new(ocpp16.ChangeAvailabilityResponseJson)
+			return &command{
+				request: &ocpp16.ChangeAvailabilityJson{
+					ConnectorId: connectorId,
+					Type:        ocpp16.ChangeAvailabilityJsonType(req.OperationalStatus),
+				},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		var evse *ocpp201.EVSEType
+		if req.EvseId != nil && *req.EvseId != 0 {
+			evse = &ocpp201.EVSEType{
+				Id:          *req.EvseId,
+				ConnectorId: req.ConnectorId,
+			}
+		}
+		resp := new(ocpp201.ChangeAvailabilityResponseJson)
+		return &command{
+			request: &ocpp201.ChangeAvailabilityRequestJson{
+				Evse:              evse,
+				OperationalStatus: ocpp201.OperationalStatusEnumType(req.OperationalStatus),
+			},
+			response: resp,
+			result: func() *CommandResult {
+				return newCommandResult(string(resp.Status), resp.StatusInfo)
+			},
+		}, nil
+	})
+}
+
+func (s *Server) ClearCache(w http.ResponseWriter, r *http.Request, csId string) {
+	s.runCommand(w, r, csId, func(ocppVersion string) (*command, error) {
+		if ocppVersion == "1.6" {
+			resp := new(ocpp16.ClearCacheResponseJson)
+			return &command{
+				request:  &ocpp16.ClearCacheJson{},
+				response: resp,
+				result: func() *CommandResult {
+					return newCommandResult(string(resp.Status), nil)
+				},
+			}, nil
+		}
+
+		resp := new(ocpp201.ClearCacheResponseJson)
+		return &command{
+			request:  &ocpp201.ClearCacheRequestJson{},
+			response: resp,
+			result: func() *CommandResult {
+				return newCommandResult(string(resp.Status), resp.StatusInfo)
+			},
+		}, nil
+	})
+}
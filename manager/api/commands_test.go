@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/api"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+type fakeSyncCallMaker struct {
+	chargeStationId string
+	request         ocpp.Request
+	response        string
+	err             error
+}
+
+func (f *fakeSyncCallMaker) Call(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response) error {
+	f.chargeStationId = chargeStationId
+	f.request = request
+	if f.err != nil {
+		return f.err
+	}
+	return json.Unmarshal([]byte(f.response), response)
+}
+
+func setupCommandServer(t *testing.T, ocppVersion string, callMaker *fakeSyncCallMaker) *chi.Mux {
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetChargeStationRuntimeDetails(context.Background(), "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: ocppVersion,
+	})
+	require.NoError(t, err)
+
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, callMaker, callMaker)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+	return r
+}
+
+func postCommand(t *testing.T, r http.Handler, path, body string) (int, *api.CommandResult) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Result().StatusCode != http.StatusOK {
+		return rr.Result().StatusCode, nil
+	}
+	result := new(api.CommandResult)
+	err := json.NewDecoder(rr.Result().Body).Decode(result)
+	require.NoError(t, err)
+	return rr.Result().StatusCode, result
+}
+
+func TestStartTransactionCommand(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		r := setupCommandServer(t, "1.6", callMaker)
+
+		status, result := postCommand(t, r, "/cs/cs001/commands/start-transaction", `{"idToken":"DEADBEEF","evseId":1}`)
+		require.Equal(t, http.StatusOK, status)
+
+		connectorId := 1
+		assert.Equal(t, "cs001", callMaker.chargeStationId)
+		assert.Equal(t, &ocpp16.RemoteStartTransactionJson{
+			ConnectorId: &connectorId,
+			IdTag:       "DEADBEEF",
+		}, callMaker.request)
+		assert.Equal(t, &api.CommandResult{Status: "Accepted"}, result)
+	})
+
+	t.Run("ocpp 2.0.1", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Rejected","statusInfo":{"reasonCode":"InvalidIdToken"}}`}
+		r := setupCommandServer(t, "2.0.1", callMaker)
+
+		status, result := postCommand(t, r, "/cs/cs001/commands/start-transaction", `{"idToken":"DEADBEEF"}`)
+		require.Equal(t, http.StatusOK, status)
+
+		req, ok := callMaker.request.(*ocpp201.RequestStartTransactionRequestJson)
+		require.True(t, ok)
+		assert.Equal(t, ocpp201.IdTokenType{IdToken: "DEADBEEF", Type: ocpp201.IdTokenEnumTypeISO14443}, req.IdToken)
+		assert.Nil(t, req.EvseId)
+
+		reasonCode := "InvalidIdToken"
+		assert.Equal(t, &api.CommandResult{
+			Status:        "Rejected",
+			ReasonCode:    &reasonCode,
+			RemoteStartId: &req.RemoteStartId,
+		}, result)
+	})
+}
+
+func TestStopTransactionCommand(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		r := setupCommandServer(t, "1.6", callMaker)
+
+		status, _ := postCommand(t, r, "/cs/cs001/commands/stop-transaction", `{"transactionId":"00000000-0000-0000-0000-00000000007b"}`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, &ocpp16.RemoteStopTransactionJson{TransactionId: 123}, callMaker.request)
+	})
+
+	t.Run("ocpp 1.6 invalid transaction id", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		r := setupCommandServer(t, "1.6", callMaker)
+
+		status, _ := postCommand(t, r, "/cs/cs001/commands/stop-transaction", `{"transactionId":"not-a-transaction"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Nil(t, callMaker.request)
+	})
+
+	t.Run("ocpp 2.0.1", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		r := setupCommandServer(t, "2.0.1", callMaker)
+
+		status, _ := postCommand(t, r, "/cs/cs001/commands/stop-transaction", `{"transactionId":"tx001"}`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, &ocpp201.RequestStopTransactionRequestJson{TransactionId: "tx001"}, callMaker.request)
+	})
+}
+
+func TestResetCommand(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Scheduled"}`}
+	r := setupCommandServer(t, "2.0.1", callMaker)
+
+	status, result := postCommand(t, r, "/cs/cs001/commands/reset", `{"type":"Hard"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, &ocpp201.ResetRequestJson{Type: ocpp201.ResetEnumTypeImmediate}, callMaker.request)
+	assert.Equal(t, "Scheduled", result.Status)
+}
+
+func TestUnlockConnectorCommandRequiresEvseForOcpp201(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Unlocked"}`}
+	r := setupCommandServer(t, "2.0.1", callMaker)
+
+	status, _ := postCommand(t, r, "/cs/cs001/commands/unlock-connector", `{"connectorId":1}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Nil(t, callMaker.request)
+}
+
+func TestChangeAvailabilityCommand(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+	r := setupCommandServer(t, "1.6", callMaker)
+
+	status, _ := postCommand(t, r, "/cs/cs001/commands/change-availability", `{"operationalStatus":"Inoperative"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, &ocpp16.ChangeAvailabilityJson{
+		ConnectorId: 0,
+		Type:        ocpp16.ChangeAvailabilityJsonTypeInoperative,
+	}, callMaker.request)
+}
+
+func TestCommandErrors(t *testing.T) {
+	tests := map[string]struct {
+		chargeStationId string
+		err             error
+		want            int
+	}{
+		"unknown charge station": {
+			chargeStationId: "unknown",
+			want:            http.StatusNotFound,
+		},
+		"call error": {
+			chargeStationId: "cs001",
+			err:             transport.NewError(transport.ErrorNotImplemented, errors.New("not supported")),
+			want:            http.StatusBadGateway,
+		},
+		"timeout": {
+			chargeStationId: "cs001",
+			err:             fmt.Errorf("waiting for ClearCache response: %w", context.DeadlineExceeded),
+			want:            http.StatusGatewayTimeout,
+		},
+		"other error": {
+			chargeStationId: "cs001",
+			err:             errors.New("something went wrong"),
+			want:            http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			callMaker := &fakeSyncCallMaker{err: tc.err}
+			r := setupCommandServer(t, "1.6", callMaker)
+
+			status, _ := postCommand(t, r, fmt.Sprintf("/cs/%s/commands/clear-cache", tc.chargeStationId), `{}`)
+			assert.Equal(t, tc.want, status)
+		})
+	}
+}
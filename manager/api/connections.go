@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Server) ListChargeStationConnections(w http.ResponseWriter, r *http.Request, params ListChargeStationConnectionsParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	conns, err := s.store.ListChargeStationConnections(r.Context(), offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	now := s.clock.Now()
+	var resp = make([]render.Renderer, len(conns))
+	for i, conn := range conns {
+		resp[i] = s.newChargeStationConnection(conn, now)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) LookupChargeStationConnection(w http.ResponseWriter, r *http.Request, csId string) {
+	conn, err := s.store.LookupChargeStationConnection(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if conn == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, s.newChargeStationConnection(conn, s.clock.Now()))
+}
+
+func (s *Server) newChargeStationConnection(conn *store.ChargeStationConnection, now time.Time) *ChargeStationConnection {
+	status := Offline
+	if conn.Connected && now.Sub(conn.LastSeen) <= s.staleAfter {
+		status = Online
+	}
+
+	resp := &ChargeStationConnection{
+		ChargeStationId: conn.ChargeStationId,
+		Status:          status,
+		Connected:       conn.Connected,
+		DisconnectedAt:  conn.DisconnectedAt,
+	}
+	if conn.GatewayId != "" {
+		resp.GatewayId = &conn.GatewayId
+	}
+	if conn.Protocol != "" {
+		resp.Protocol = &conn.Protocol
+	}
+	if conn.RemoteAddr != "" {
+		resp.RemoteAddress = &conn.RemoteAddr
+	}
+	if conn.CertificateHash != "" {
+		resp.CertificateHash = &conn.CertificateHash
+	}
+	// a charge station only seen by its messages has no connection details
+	if conn.ConnectionId != "" {
+		securityProfile := int(conn.SecurityProfile)
+		resp.SecurityProfile = &securityProfile
+	}
+	if !conn.ConnectedAt.IsZero() {
+		resp.ConnectedAt = &conn.ConnectedAt
+	}
+	if !conn.LastSeen.IsZero() {
+		resp.LastSeen = &conn.LastSeen
+	}
+	return resp
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/api"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestListChargeStationConnections(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	now := clock.Now()
+	connectedAt := now.Add(-time.Hour).UTC().Truncate(time.Second)
+	lastSeen := now.Add(-time.Minute).UTC().Truncate(time.Second)
+	staleLastSeen := now.Add(-time.Hour).UTC().Truncate(time.Second)
+	disconnectedAt := now.Add(-5 * time.Minute).UTC().Truncate(time.Second)
+
+	err := engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+		ChargeStationId: "cs001",
+		Connected:       true,
+		GatewayId:       "gateway-1",
+		ConnectionId:    "conn-1",
+		Protocol:        "ocpp1.6",
+		RemoteAddr:      "10.0.0.1:4567",
+		SecurityProfile: store.TLSWithBasicAuth,
+		ConnectedAt:     connectedAt,
+		LastSeen:        lastSeen,
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnection(ctx, "cs002", &store.ChargeStationConnection{
+		ChargeStationId: "cs002",
+		Connected:       true,
+		GatewayId:       "gateway-1",
+		ConnectionId:    "conn-2",
+		Protocol:        "ocpp2.0.1",
+		SecurityProfile: store.TLSWithClientSideCertificates,
+		CertificateHash: "abc",
+		ConnectedAt:     connectedAt,
+		LastSeen:        staleLastSeen,
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnection(ctx, "cs003", &store.ChargeStationConnection{
+		ChargeStationId: "cs003",
+		Connected:       false,
+		GatewayId:       "gateway-2",
+		ConnectionId:    "conn-3",
+		Protocol:        "ocpp1.6",
+		SecurityProfile: store.UnsecuredTransportWithBasicAuth,
+		ConnectedAt:     connectedAt,
+		DisconnectedAt:  &disconnectedAt,
+		LastSeen:        disconnectedAt,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.ChargeStationConnection
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+
+	want := []api.ChargeStationConnection{
+		{
+			ChargeStationId: "cs001",
+			Status:          api.Online,
+			Connected:       true,
+			GatewayId:       makePtr("gateway-1"),
+			Protocol:        makePtr("ocpp1.6"),
+			RemoteAddress:   makePtr("10.0.0.1:4567"),
+			SecurityProfile: makePtr(1),
+			ConnectedAt:     &connectedAt,
+			LastSeen:        &lastSeen,
+		},
+		{
+			ChargeStationId: "cs002",
+			Status:          api.Offline,
+			Connected:       true,
+			GatewayId:       makePtr("gateway-1"),
+			Protocol:        makePtr("ocpp2.0.1"),
+			SecurityProfile: makePtr(2),
+			CertificateHash: makePtr("abc"),
+			ConnectedAt:     &connectedAt,
+			LastSeen:        &staleLastSeen,
+		},
+		{
+			ChargeStationId: "cs003",
+			Status:          api.Offline,
+			Connected:       false,
+			GatewayId:       makePtr("gateway-2"),
+			Protocol:        makePtr("ocpp1.6"),
+			SecurityProfile: makePtr(0),
+			ConnectedAt:     &connectedAt,
+			DisconnectedAt:  &disconnectedAt,
+			LastSeen:        &disconnectedAt,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestLookupChargeStationConnection(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	lastSeen := clock.Now().UTC().Truncate(time.Second)
+	err := engine.SetChargeStationLastSeen(context.Background(), "cs001", lastSeen)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/connection", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got api.ChargeStationConnection
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+
+	want := api.ChargeStationConnection{
+		ChargeStationId: "cs001",
+		Status:          api.Offline,
+		Connected:       false,
+		LastSeen:        &lastSeen,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestLookupChargeStationConnectionNotFound(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/connection", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
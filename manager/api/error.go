@@ -43,3 +43,21 @@ var ErrNotFound = &ErrResponse{
 	HTTPStatusCode: http.StatusNotFound,
 	StatusText:     http.StatusText(http.StatusNotFound),
 }
+
+func ErrBadGateway(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusBadGateway,
+		StatusText:     http.StatusText(http.StatusBadGateway),
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrGatewayTimeout(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusGatewayTimeout,
+		StatusText:     http.StatusText(http.StatusGatewayTimeout),
+		ErrorText:      err.Error(),
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Server) RegisterEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string) {
+	req := new(EvseMapping)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	mapping := &store.EvseMapping{
+		LocationId:      locationId,
+		EvseUid:         evseUid,
+		EvseId:          req.EvseId,
+		ChargeStationId: req.ChargeStationId,
+	}
+	if req.ChargeStationEvseId != nil {
+		mapping.ChargeStationEvseId = *req.ChargeStationEvseId
+	}
+	if req.Connectors != nil {
+		for _, connector := range *req.Connectors {
+			mapping.Connectors = append(mapping.Connectors, store.ConnectorMapping{
+				Id:          connector.Id,
+				ConnectorId: connector.ConnectorId,
+			})
+		}
+	}
+
+	err := s.store.SetEvseMapping(r.Context(), mapping)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) LookupEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string) {
+	mapping, err := s.store.LookupEvseMapping(r.Context(), locationId, evseUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if mapping == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := EvseMapping{
+		EvseId:          mapping.EvseId,
+		ChargeStationId: mapping.ChargeStationId,
+	}
+	if mapping.ChargeStationEvseId != 0 {
+		resp.ChargeStationEvseId = &mapping.ChargeStationEvseId
+	}
+	if len(mapping.Connectors) > 0 {
+		connectors := make([]ConnectorMapping, len(mapping.Connectors))
+		for i, connector := range mapping.Connectors {
+			connectors[i] = ConnectorMapping{
+				Id:          connector.Id,
+				ConnectorId: connector.ConnectorId,
+			}
+		}
+		resp.Connectors = &connectors
+	}
+
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) DeleteEvseMapping(w http.ResponseWriter, r *http.Request, locationId string, evseUid string) {
+	mapping, err := s.store.LookupEvseMapping(r.Context(), locationId, evseUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if mapping == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	err = s.store.DeleteEvseMapping(r.Context(), locationId, evseUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
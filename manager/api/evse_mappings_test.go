@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/api"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestRegisterEvseMapping(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/location/loc001/evse/evse001/mapping", strings.NewReader(`{
+  "evseId": "GB*TWK*E12345*1",
+  "chargeStationId": "cs001",
+  "chargeStationEvseId": 1,
+  "connectors": [{"id": "A", "connectorId": 1}]
+}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	want := &store.EvseMapping{
+		LocationId:          "loc001",
+		EvseUid:             "evse001",
+		EvseId:              makePtr("GB*TWK*E12345*1"),
+		ChargeStationId:     "cs001",
+		ChargeStationEvseId: 1,
+		Connectors:          []store.ConnectorMapping{{Id: "A", ConnectorId: 1}},
+	}
+	got, err := engine.LookupEvseMapping(context.Background(), "loc001", "evse001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRegisterEvseMappingWithoutChargeStation(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/location/loc001/evse/evse001/mapping", strings.NewReader(`{"chargeStationEvseId": 1}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestLookupAndDeleteEvseMapping(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetEvseMapping(context.Background(), &store.EvseMapping{
+		LocationId:      "loc001",
+		EvseUid:         "evse001",
+		ChargeStationId: "cs001",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/location/loc001/evse/evse001/mapping", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got api.EvseMapping
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, api.EvseMapping{ChargeStationId: "cs001"}, got)
+
+	req = httptest.NewRequest(http.MethodDelete, "/location/loc001/evse/evse001/mapping", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/location/loc001/evse/evse001/mapping", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/location/loc001/evse/evse001/mapping", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
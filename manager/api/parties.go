@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+func (s *Server) ListParties(w http.ResponseWriter, r *http.Request) {
+	if s.ocpi == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	parties, err := s.store.ListPartyDetails(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	resp := make([]render.Renderer, len(parties))
+	for i, party := range parties {
+		versions := make([]PartyVersion, len(party.Versions))
+		for j, version := range party.Versions {
+			versions[j] = PartyVersion{
+				Version: version.Version,
+				Url:     version.Url,
+			}
+		}
+		endpoints := make([]PartyEndpoint, len(party.Endpoints))
+		for j, endpoint := range party.Endpoints {
+			endpoints[j] = PartyEndpoint{
+				Identifier: endpoint.Identifier,
+				Role:       PartyEndpointRole(endpoint.Role),
+				Url:        endpoint.Url,
+			}
+		}
+		resp[i] = Party{
+			Role:        PartyRole(party.Role),
+			CountryCode: party.CountryCode,
+			PartyId:     party.PartyId,
+			Url:         party.Url,
+			Versions:    &versions,
+			Endpoints:   &endpoints,
+		}
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) DeregisterParty(w http.ResponseWriter, r *http.Request, countryCode string, partyId string) {
+	if !s.partyExists(w, r, countryCode, partyId) {
+		return
+	}
+
+	err := s.ocpi.DeregisterParty(r.Context(), countryCode, partyId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) RotatePartyToken(w http.ResponseWriter, r *http.Request, countryCode string, partyId string) {
+	if !s.partyExists(w, r, countryCode, partyId) {
+		return
+	}
+
+	err := s.ocpi.RotateToken(r.Context(), countryCode, partyId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// partyExists renders a not found response if the party has not registered with
+// the CSMS in any role.
+func (s *Server) partyExists(w http.ResponseWriter, r *http.Request, countryCode, partyId string) bool {
+	if s.ocpi == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return false
+	}
+
+	parties, err := s.store.ListPartyDetails(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return false
+	}
+	for _, party := range parties {
+		if party.CountryCode == countryCode && party.PartyId == partyId {
+			return true
+		}
+	}
+
+	_ = render.Render(w, r, ErrNotFound)
+	return false
+}
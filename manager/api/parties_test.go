@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/api"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestListParties(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetPartyDetails(context.Background(), &store.OcpiParty{
+		Role:        "EMSP",
+		CountryCode: "GB",
+		PartyId:     "ABC",
+		Url:         "https://example.com/ocpi/versions",
+		Token:       "abcdef123456",
+		Versions: []store.OcpiVersion{
+			{Version: "2.2", Url: "https://example.com/ocpi/2.2"},
+		},
+		Endpoints: []store.OcpiEndpoint{
+			{Identifier: "credentials", Role: "RECEIVER", Url: "https://example.com/ocpi/2.2/credentials"},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/party", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.Party
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+
+	want := []api.Party{
+		{
+			Role:        api.PartyRoleEMSP,
+			CountryCode: "GB",
+			PartyId:     "ABC",
+			Url:         "https://example.com/ocpi/versions",
+			Versions: &[]api.PartyVersion{
+				{Version: "2.2", Url: "https://example.com/ocpi/2.2"},
+			},
+			Endpoints: &[]api.PartyEndpoint{
+				{Identifier: "credentials", Role: api.RECEIVER, Url: "https://example.com/ocpi/2.2/credentials"},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+	assert.NotContains(t, rr.Body.String(), "abcdef123456")
+}
+
+func TestDeregisterUnknownParty(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodDelete, "/party/GB/ABC", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestRotateUnknownPartyToken(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/party/GB/ABC/rotate-token", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
@@ -47,3 +47,79 @@ func (r Registration) Bind(req *http.Request) error {
 func (r Location) Bind(req *http.Request) error {
 	return nil
 }
+
+func (c ChargingProfile) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c ChargingProfile) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c CompositeSchedule) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStationConnection) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c ConnectorStatus) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (m MeterPublicKey) Bind(r *http.Request) error {
+	return nil
+}
+
+func (m MeterPublicKey) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c StartTransactionCommand) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c StopTransactionCommand) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c ResetCommand) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c UnlockConnectorCommand) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c ChangeAvailabilityCommand) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c CommandResult) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (t Tariff) Bind(r *http.Request) error {
+	return nil
+}
+
+func (t Tariff) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (m EvseMapping) Bind(r *http.Request) error {
+	return nil
+}
+
+func (m EvseMapping) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c QueuedCall) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (p Party) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
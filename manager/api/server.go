@@ -3,39 +3,67 @@
 package api
 
 import (
+	"errors"
 	"fmt"
-	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	handlers16 "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	handlers201 "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
 	"github.com/zynka-tech/zynka-csms/manager/ocpi"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/render"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"k8s.io/utils/clock"
 )
 
 type Server struct {
-	store   store.Engine
-	clock   clock.PassiveClock
-	swagger *openapi3.T
-	ocpi    ocpi.Api
+	store          store.Engine
+	clock          clock.PassiveClock
+	swagger        *openapi3.T
+	ocpi           ocpi.Api
+	v16CallMaker   handlers.SyncCallMaker
+	v201CallMaker  handlers.SyncCallMaker
+	commandTimeout time.Duration
+	// staleAfter is how long after a charge station was last seen that it is
+	// reported as offline
+	staleAfter time.Duration
+	callQueue  *handlers.CallQueue
 }
 
-func NewServer(engine store.Engine, clock clock.PassiveClock, ocpi ocpi.Api) (*Server, error) {
+func NewServer(engine store.Engine, clock clock.PassiveClock, ocpi ocpi.Api, v16CallMaker, v201CallMaker handlers.SyncCallMaker) (*Server, error) {
 	swagger, err := GetSwagger()
 	if err != nil {
 		return nil, err
 	}
 	return &Server{
-		store:   engine,
-		clock:   clock,
-		ocpi:    ocpi,
-		swagger: swagger,
+		store:          engine,
+		clock:          clock,
+		ocpi:           ocpi,
+		swagger:        swagger,
+		v16CallMaker:   v16CallMaker,
+		v201CallMaker:  v201CallMaker,
+		commandTimeout: 30 * time.Second,
+		staleAfter:     10 * time.Minute,
 	}, nil
 }
 
+// SetCallQueue sets the queue that calls are cancelled through, so that the
+// next call is delivered when the call in flight is cancelled.
+func (s *Server) SetCallQueue(callQueue *handlers.CallQueue) {
+	s.callQueue = callQueue
+}
+
+// SetConnectionStaleAfter sets how long after a charge station was last seen
+// that it is reported as offline.
+func (s *Server) SetConnectionStaleAfter(staleAfter time.Duration) {
+	s.staleAfter = staleAfter
+}
+
 func (s *Server) RegisterChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
 	req := new(ChargeStationAuth)
 	if err := render.Bind(r, req); err != nil {
@@ -97,7 +125,7 @@ func (s *Server) InstallChargeStationCertificates(w http.ResponseWriter, r *http
 
 	var certs []*store.ChargeStationInstallCertificate
 	for _, cert := range req.Certificates {
-		certId, err := handlers.GetCertificateId(cert.Certificate)
+		certId, err := handlers201.GetCertificateId(cert.Certificate)
 		if err != nil {
 			_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid certificate: %w", err)))
 			return
@@ -167,6 +195,302 @@ func (s *Server) TriggerChargeStation(w http.ResponseWriter, r *http.Request, cs
 	w.WriteHeader(http.StatusCreated)
 }
 
+func (s *Server) SetChargingProfile(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(ChargingProfile)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if req.ChargingProfilePurpose == TxProfile && req.ConnectorId == 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("a TxProfile must apply to a connector")))
+		return
+	}
+	if req.ChargingProfilePurpose != TxProfile && req.TransactionId != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("transaction id is only valid for a TxProfile")))
+		return
+	}
+	if req.ChargingProfileKind == Recurring && req.RecurrencyKind == nil {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("a recurring charging profile requires a recurrency kind")))
+		return
+	}
+
+	transactionId := req.TransactionId
+	if transactionId != nil {
+		details, err := s.store.LookupChargeStationRuntimeDetails(r.Context(), csId)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		// OCPP 1.6 transaction ids are integers: accept the id reported by the
+		// charge station or the id the transaction is stored with
+		if details != nil && details.OcppVersion == "1.6" {
+			id, err := handlers16.ConvertFromUUID(*transactionId)
+			if err != nil {
+				_ = render.Render(w, r, ErrInvalidRequest(err))
+				return
+			}
+			v16TransactionId := strconv.Itoa(id)
+			transactionId = &v16TransactionId
+		}
+	}
+
+	var recurrencyKind *store.RecurrencyKind
+	if req.RecurrencyKind != nil {
+		kind := store.RecurrencyKind(*req.RecurrencyKind)
+		recurrencyKind = &kind
+	}
+
+	err := s.store.UpdateChargeStationChargingProfiles(r.Context(), csId, &store.ChargeStationChargingProfiles{
+		ChargeStationId: csId,
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      req.ChargingProfileId,
+				ConnectorId:            req.ConnectorId,
+				TransactionId:          transactionId,
+				StackLevel:             req.StackLevel,
+				ChargingProfilePurpose: store.ChargingProfilePurpose(req.ChargingProfilePurpose),
+				ChargingProfileKind:    store.ChargingProfileKind(req.ChargingProfileKind),
+				RecurrencyKind:         recurrencyKind,
+				ValidFrom:              req.ValidFrom,
+				ValidTo:                req.ValidTo,
+				ChargingSchedule:       toStoreChargingSchedule(req.ChargingSchedule),
+				Status:                 store.ChargingProfileStatusPending,
+			},
+		},
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func toStoreChargingSchedule(schedule ChargingSchedule) store.ChargingSchedule {
+	periods := make([]store.ChargingSchedulePeriod, len(schedule.ChargingSchedulePeriods))
+	for i, period := range schedule.ChargingSchedulePeriods {
+		periods[i] = store.ChargingSchedulePeriod{
+			StartPeriod:  period.StartPeriod,
+			Limit:        period.Limit,
+			NumberPhases: period.NumberPhases,
+		}
+	}
+	return store.ChargingSchedule{
+		Duration:                schedule.Duration,
+		StartSchedule:           schedule.StartSchedule,
+		ChargingRateUnit:        store.ChargingRateUnit(schedule.ChargingRateUnit),
+		ChargingSchedulePeriods: periods,
+		MinChargingRate:         schedule.MinChargingRate,
+	}
+}
+
+func newChargingSchedule(schedule store.ChargingSchedule) ChargingSchedule {
+	periods := make([]ChargingSchedulePeriod, len(schedule.ChargingSchedulePeriods))
+	for i, period := range schedule.ChargingSchedulePeriods {
+		periods[i] = ChargingSchedulePeriod{
+			StartPeriod:  period.StartPeriod,
+			Limit:        period.Limit,
+			NumberPhases: period.NumberPhases,
+		}
+	}
+	return ChargingSchedule{
+		Duration:                schedule.Duration,
+		StartSchedule:           schedule.StartSchedule,
+		ChargingRateUnit:        ChargingScheduleChargingRateUnit(schedule.ChargingRateUnit),
+		ChargingSchedulePeriods: periods,
+		MinChargingRate:         schedule.MinChargingRate,
+	}
+}
+
+func newChargingProfile(profile *store.ChargingProfile) *ChargingProfile {
+	status := ChargingProfileStatus(profile.Status)
+	resp := &ChargingProfile{
+		ChargingProfileId:      profile.ChargingProfileId,
+		ConnectorId:            profile.ConnectorId,
+		TransactionId:          profile.TransactionId,
+		StackLevel:             profile.StackLevel,
+		ChargingProfilePurpose: ChargingProfileChargingProfilePurpose(profile.ChargingProfilePurpose),
+		ChargingProfileKind:    ChargingProfileChargingProfileKind(profile.ChargingProfileKind),
+		ValidFrom:              profile.ValidFrom,
+		ValidTo:                profile.ValidTo,
+		ChargingSchedule:       newChargingSchedule(profile.ChargingSchedule),
+		Status:                 &status,
+	}
+	if profile.RecurrencyKind != nil {
+		recurrencyKind := ChargingProfileRecurrencyKind(*profile.RecurrencyKind)
+		resp.RecurrencyKind = &recurrencyKind
+	}
+	return resp
+}
+
+func (s *Server) ListChargingProfiles(w http.ResponseWriter, r *http.Request, csId string) {
+	profiles, err := s.store.LookupChargeStationChargingProfiles(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, 0)
+	if profiles != nil {
+		for _, profile := range profiles.ChargingProfiles {
+			resp = append(resp, newChargingProfile(profile))
+		}
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) ClearChargingProfile(w http.ResponseWriter, r *http.Request, csId string, chargingProfileId int) {
+	profiles, err := s.store.LookupChargeStationChargingProfiles(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var profile *store.ChargingProfile
+	if profiles != nil {
+		for _, p := range profiles.ChargingProfiles {
+			if p.ChargingProfileId == chargingProfileId {
+				profile = p
+				break
+			}
+		}
+	}
+	if profile == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	profile.Status = store.ChargingProfileStatusClearPending
+	profile.SendAfter = time.Time{}
+	err = s.store.UpdateChargeStationChargingProfiles(r.Context(), csId, &store.ChargeStationChargingProfiles{
+		ChargeStationId:  csId,
+		ChargingProfiles: []*store.ChargingProfile{profile},
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) LookupCompositeSchedule(w http.ResponseWriter, r *http.Request, csId string, params LookupCompositeScheduleParams) {
+	schedule, err := s.store.LookupChargeStationCompositeSchedule(r.Context(), csId, params.ConnectorId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if schedule == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := &CompositeSchedule{
+		ConnectorId:   schedule.ConnectorId,
+		ScheduleStart: schedule.ScheduleStart,
+		RetrievedAt:   schedule.RetrievedAt,
+	}
+	if schedule.ChargingSchedule != nil {
+		chargingSchedule := newChargingSchedule(*schedule.ChargingSchedule)
+		resp.ChargingSchedule = &chargingSchedule
+	}
+
+	_ = render.Render(w, r, resp)
+}
+
+func newConnectorStatus(status *store.ConnectorStatus) *ConnectorStatus {
+	return &ConnectorStatus{
+		EvseId:          status.EvseId,
+		ConnectorId:     status.ConnectorId,
+		Status:          status.Status,
+		ErrorCode:       status.ErrorCode,
+		Info:            status.Info,
+		VendorId:        status.VendorId,
+		VendorErrorCode: status.VendorErrorCode,
+		Timestamp:       status.Timestamp,
+	}
+}
+
+func (s *Server) ListConnectorStatuses(w http.ResponseWriter, r *http.Request, csId string) {
+	statuses, err := s.store.ListChargeStationConnectorStatuses(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, 0)
+	for _, status := range statuses {
+		resp = append(resp, newConnectorStatus(status))
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) LookupConnectorStatus(w http.ResponseWriter, r *http.Request, csId string, evseId int, connectorId int) {
+	status, err := s.store.LookupChargeStationConnectorStatus(r.Context(), csId, evseId, connectorId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if status == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, newConnectorStatus(status))
+}
+
+func (s *Server) SetMeterPublicKey(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(MeterPublicKey)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if _, err := services.ParseMeterPublicKey(req.PublicKey); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	err := s.store.SetChargeStationMeterPublicKey(r.Context(), csId, &store.MeterPublicKey{
+		EvseId:    req.EvseId,
+		PublicKey: req.PublicKey,
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) ListMeterPublicKeys(w http.ResponseWriter, r *http.Request, csId string) {
+	keys, err := s.store.ListChargeStationMeterPublicKeys(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, 0)
+	for _, key := range keys {
+		resp = append(resp, MeterPublicKey{
+			EvseId:    key.EvseId,
+			PublicKey: key.PublicKey,
+		})
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) DeleteMeterPublicKey(w http.ResponseWriter, r *http.Request, csId string, evseId int) {
+	err := s.store.DeleteChargeStationMeterPublicKey(r.Context(), csId, evseId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Server) SetToken(w http.ResponseWriter, r *http.Request) {
 	req := new(Token)
 	if err := render.Bind(r, req); err != nil {
@@ -387,17 +711,23 @@ func (s *Server) RegisterLocation(w http.ResponseWriter, r *http.Request, locati
 					MaxAmperage: reqConnector.MaxAmperage,
 					LastUpdated: now.Format(time.RFC3339),
 				}
-				storeEvses[i] = store.Evse{
-					Connectors:  storeConnectors,
-					EvseId:      reqEvse.EvseId,
-					Status:      string(ocpi.EvseStatusUNKNOWN),
-					Uid:         reqEvse.Uid,
-					LastUpdated: now.Format(time.RFC3339),
-				}
+			}
+			storeEvses[i] = store.Evse{
+				Connectors:  storeConnectors,
+				EvseId:      reqEvse.EvseId,
+				Status:      string(ocpi.EvseStatusUNKNOWN),
+				Uid:         reqEvse.Uid,
+				LastUpdated: now.Format(time.RFC3339),
+			}
+			if reqEvse.ChargeStationId != nil {
+				storeEvses[i].ChargeStationId = *reqEvse.ChargeStationId
+			}
+			if reqEvse.ChargeStationEvseId != nil {
+				storeEvses[i].ChargeStationEvseId = *reqEvse.ChargeStationEvseId
 			}
 		}
 	}
-	err := s.store.SetLocation(r.Context(), &store.Location{
+	location := &store.Location{
 		Address: req.Address,
 		City:    req.City,
 		Coordinates: store.GeoLocation{
@@ -405,59 +735,32 @@ func (s *Server) RegisterLocation(w http.ResponseWriter, r *http.Request, locati
 			Longitude: req.Coordinates.Longitude,
 		},
 		Country:     req.Country,
+		CountryCode: req.CountryCode,
 		Evses:       &storeEvses,
 		Id:          locationId,
-		Name:        *req.Name,
-		ParkingType: string(*req.ParkingType),
-		PostalCode:  *req.PostalCode,
-	})
+		PartyId:     req.PartyId,
+	}
+	if req.Name != nil {
+		location.Name = *req.Name
+	}
+	if req.ParkingType != nil {
+		location.ParkingType = string(*req.ParkingType)
+	}
+	if req.PostalCode != nil {
+		location.PostalCode = *req.PostalCode
+	}
+	err := s.store.SetLocation(r.Context(), location)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
 
-	ocpiEvses := make([]ocpi.Evse, numEvses)
-	if numEvses != 0 {
-		for i, reqEvse := range *req.Evses {
-			ocpiConnectors := make([]ocpi.Connector, len(reqEvse.Connectors))
-			for j, reqConnector := range reqEvse.Connectors {
-				ocpiConnectors[j] = ocpi.Connector{
-					Id:          reqConnector.Id,
-					Format:      ocpi.ConnectorFormat(reqConnector.Format),
-					PowerType:   ocpi.ConnectorPowerType(reqConnector.PowerType),
-					Standard:    ocpi.ConnectorStandard(reqConnector.Standard),
-					MaxVoltage:  reqConnector.MaxVoltage,
-					MaxAmperage: reqConnector.MaxAmperage,
-					LastUpdated: now.Format(time.RFC3339),
-				}
-				ocpiEvses[i] = ocpi.Evse{
-					Connectors:  ocpiConnectors,
-					EvseId:      reqEvse.EvseId,
-					Status:      ocpi.EvseStatusUNKNOWN,
-					Uid:         reqEvse.Uid,
-					LastUpdated: now.Format(time.RFC3339),
-				}
-			}
-		}
+	location, err = s.store.LookupLocation(r.Context(), locationId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
 	}
-	err = s.ocpi.PushLocation(r.Context(), ocpi.Location{
-		Address: req.Address,
-		City:    req.City,
-		Coordinates: ocpi.GeoLocation{
-			Latitude:  req.Coordinates.Latitude,
-			Longitude: req.Coordinates.Longitude,
-		},
-		Country:     req.Country,
-		CountryCode: req.CountryCode,
-		Evses:       &ocpiEvses,
-		Id:          locationId,
-		LastUpdated: now.Format(time.RFC3339),
-		Name:        req.Name,
-		ParkingType: (*ocpi.LocationParkingType)(req.ParkingType),
-		PartyId:     req.PartyId,
-		PostalCode:  req.PostalCode,
-		Publish:     true,
-	})
+	err = s.ocpi.PushLocation(r.Context(), location)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
@@ -12,6 +12,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -91,6 +92,457 @@ func TestLookupChargeStationAuthThatDoesNotExist(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
 }
 
+func TestSetChargingProfile(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/charging-profiles", strings.NewReader(`{
+		"chargingProfileId": 1,
+		"connectorId": 0,
+		"stackLevel": 0,
+		"chargingProfilePurpose": "ChargePointMaxProfile",
+		"chargingProfileKind": "Absolute",
+		"chargingSchedule": {
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 32, "numberPhases": 3}]
+		}
+	}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	got, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	numberPhases := 3
+	want := []*store.ChargingProfile{
+		{
+			ChargingProfileId:      1,
+			ConnectorId:            0,
+			StackLevel:             0,
+			ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+			ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+			ChargingSchedule: store.ChargingSchedule{
+				ChargingRateUnit: store.ChargingRateUnitA,
+				ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+					{StartPeriod: 0, Limit: 32, NumberPhases: &numberPhases},
+				},
+			},
+			Status: store.ChargingProfileStatusPending,
+		},
+	}
+	assert.Equal(t, want, got.ChargingProfiles)
+}
+
+func TestSetChargingProfileRejectsTxProfileWithoutConnector(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/charging-profiles", strings.NewReader(`{
+		"chargingProfileId": 1,
+		"connectorId": 0,
+		"stackLevel": 0,
+		"chargingProfilePurpose": "TxProfile",
+		"chargingProfileKind": "Absolute",
+		"chargingSchedule": {
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 32}]
+		}
+	}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestSetChargingProfileConvertsV16TransactionId(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationRuntimeDetails(context.Background(), "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/charging-profiles", strings.NewReader(`{
+		"chargingProfileId": 1,
+		"connectorId": 1,
+		"transactionId": "00000000-0000-0000-0000-00000000002a",
+		"stackLevel": 0,
+		"chargingProfilePurpose": "TxProfile",
+		"chargingProfileKind": "Absolute",
+		"chargingSchedule": {
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 16}]
+		}
+	}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	got, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Len(t, got.ChargingProfiles, 1)
+	require.NotNil(t, got.ChargingProfiles[0].TransactionId)
+	assert.Equal(t, "42", *got.ChargingProfiles[0].TransactionId)
+}
+
+func TestSetChargingProfileRejectsInvalidV16TransactionId(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationRuntimeDetails(context.Background(), "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/charging-profiles", strings.NewReader(`{
+		"chargingProfileId": 1,
+		"connectorId": 1,
+		"transactionId": "not-a-transaction",
+		"stackLevel": 0,
+		"chargingProfilePurpose": "TxProfile",
+		"chargingProfileKind": "Absolute",
+		"chargingSchedule": {
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 16}]
+		}
+	}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+
+	got, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestListChargingProfiles(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.UpdateChargeStationChargingProfiles(context.Background(), "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ConnectorId:            1,
+				StackLevel:             1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindRecurring,
+				RecurrencyKind:         &store.RecurrencyKindDaily,
+				ChargingSchedule: store.ChargingSchedule{
+					ChargingRateUnit: store.ChargingRateUnitW,
+					ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+						{StartPeriod: 0, Limit: 7400},
+					},
+				},
+				Status: store.ChargingProfileStatusAccepted,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/charging-profiles", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"chargingProfileId": 1,
+		"connectorId": 1,
+		"stackLevel": 1,
+		"chargingProfilePurpose": "TxDefaultProfile",
+		"chargingProfileKind": "Recurring",
+		"recurrencyKind": "Daily",
+		"chargingSchedule": {
+			"chargingRateUnit": "W",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 7400}]
+		},
+		"status": "Accepted"
+	}]`, string(b))
+}
+
+func TestClearChargingProfile(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.UpdateChargeStationChargingProfiles(context.Background(), "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusAccepted,
+				SendAfter:              time.Now(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001/charging-profiles/1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	got, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.Len(t, got.ChargingProfiles, 1)
+	assert.Equal(t, store.ChargingProfileStatusClearPending, got.ChargingProfiles[0].Status)
+	assert.True(t, got.ChargingProfiles[0].SendAfter.IsZero())
+}
+
+func TestClearChargingProfileThatDoesNotExist(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001/charging-profiles/1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestLookupCompositeSchedule(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	retrievedAt := time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+	err := engine.SetChargeStationCompositeSchedule(context.Background(), "cs001", &store.CompositeSchedule{
+		ConnectorId:   1,
+		ScheduleStart: &retrievedAt,
+		ChargingSchedule: &store.ChargingSchedule{
+			ChargingRateUnit: store.ChargingRateUnitA,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 16},
+			},
+		},
+		RetrievedAt: retrievedAt,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/composite-schedule?connectorId=1", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"connectorId": 1,
+		"scheduleStart": "2023-06-15T15:05:00Z",
+		"chargingSchedule": {
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriods": [{"startPeriod": 0, "limit": 16}]
+		},
+		"retrievedAt": "2023-06-15T15:05:00Z"
+	}`, string(b))
+}
+
+func TestLookupCompositeScheduleThatDoesNotExist(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/composite-schedule?connectorId=1", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestListConnectorStatuses(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	timestamp := time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+	errorCode := "GroundFailure"
+	err := engine.SetChargeStationConnectorStatus(context.Background(), "cs001", &store.ConnectorStatus{
+		EvseId:      2,
+		ConnectorId: 2,
+		Status:      "Faulted",
+		ErrorCode:   &errorCode,
+		Timestamp:   timestamp,
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnectorStatus(context.Background(), "cs001", &store.ConnectorStatus{
+		EvseId:      1,
+		ConnectorId: 1,
+		Status:      "Charging",
+		Timestamp:   timestamp,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/status", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"evseId": 1, "connectorId": 1, "status": "Charging", "timestamp": "2023-06-15T15:05:00Z"},
+		{"evseId": 2, "connectorId": 2, "status": "Faulted", "errorCode": "GroundFailure", "timestamp": "2023-06-15T15:05:00Z"}
+	]`, string(b))
+}
+
+func TestListConnectorStatusesWithNoStatuses(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/status", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(b))
+}
+
+func TestLookupConnectorStatus(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationConnectorStatus(context.Background(), "cs001", &store.ConnectorStatus{
+		EvseId:      1,
+		ConnectorId: 2,
+		Status:      "Occupied",
+		Timestamp:   time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/status/1/2", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"evseId": 1, "connectorId": 2, "status": "Occupied", "timestamp": "2023-06-15T15:05:00Z"}`, string(b))
+}
+
+func TestLookupConnectorStatusThatDoesNotExist(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/status/1/1", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestSetMeterPublicKey(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := hex.EncodeToString(der)
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/meter-public-keys",
+		strings.NewReader(fmt.Sprintf(`{"evseId":1,"publicKey":"%s"}`, publicKey)))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	keys, err := engine.ListChargeStationMeterPublicKeys(context.Background(), "cs001")
+	require.NoError(t, err)
+	want := []*store.MeterPublicKey{
+		{
+			EvseId:    1,
+			PublicKey: publicKey,
+		},
+	}
+	assert.Equal(t, want, keys)
+}
+
+func TestSetMeterPublicKeyWithInvalidKey(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/meter-public-keys",
+		strings.NewReader(`{"evseId":1,"publicKey":"DEADBEEF"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+
+	keys, err := engine.ListChargeStationMeterPublicKeys(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestListMeterPublicKeys(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	for _, evseId := range []int{2, 1} {
+		err := engine.SetChargeStationMeterPublicKey(context.Background(), "cs001", &store.MeterPublicKey{
+			EvseId:    evseId,
+			PublicKey: fmt.Sprintf("%04d", evseId),
+		})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/meter-public-keys", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"evseId": 1, "publicKey": "0001"},
+		{"evseId": 2, "publicKey": "0002"}
+	]`, string(b))
+}
+
+func TestDeleteMeterPublicKey(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationMeterPublicKey(context.Background(), "cs001", &store.MeterPublicKey{
+		EvseId:    1,
+		PublicKey: "0001",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001/meter-public-keys/1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	keys, err := engine.ListChargeStationMeterPublicKeys(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
 func TestSetToken(t *testing.T) {
 	server, r, engine, _ := setupServer(t)
 	defer server.Close()
@@ -342,6 +794,8 @@ func TestRegisterLocation(t *testing.T) {
 			Longitude: "3.729944",
 		},
 		Country:     "BEL",
+		CountryCode: "BEL",
+		PartyId:     "TWK",
 		Evses:       &[]store.Evse{},
 		Id:          "loc001",
 		Name:        "Gent Zuid",
@@ -350,16 +804,73 @@ func TestRegisterLocation(t *testing.T) {
 	}
 	got, err := engine.LookupLocation(context.Background(), "loc001")
 	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`, got.LastUpdated)
+	got.LastUpdated = ""
 	assert.Equal(t, want, got)
 }
 
+func TestRegisterLocationWithChargeStationEvses(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/location/loc001", strings.NewReader(`{
+  "address": "F.Rooseveltlaan 3A",
+  "city": "Gent",
+  "party_id": "TWK",
+  "country": "BEL",
+  "country_code": "BEL",
+  "coordinates": {
+    "latitude": "51.047599",
+    "longitude": "3.729944"
+  },
+  "evses": [
+    {
+      "uid": "BELTWKEcs001",
+      "evse_id": "BE*TWK*E001*1",
+      "charge_station_id": "cs001",
+      "charge_station_evse_id": 1,
+      "connectors": [
+        {
+          "id": "1",
+          "format": "CABLE",
+          "power_type": "AC_3_PHASE",
+          "standard": "IEC_62196_T2",
+          "max_voltage": 400,
+          "max_amperage": 32
+        }
+      ]
+    }
+  ]
+}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	got, err := engine.LookupLocation(context.Background(), "loc001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.NotNil(t, got.Evses)
+	require.Len(t, *got.Evses, 1)
+	evse := (*got.Evses)[0]
+	assert.Equal(t, "BELTWKEcs001", evse.Uid)
+	assert.Equal(t, "cs001", evse.ChargeStationId)
+	assert.Equal(t, 1, evse.ChargeStationEvseId)
+	assert.Equal(t, "UNKNOWN", evse.Status)
+	require.Len(t, evse.Connectors, 1)
+	assert.Equal(t, "IEC_62196_T2", evse.Connectors[0].Standard)
+	assert.Equal(t, int32(32), evse.Connectors[0].MaxAmperage)
+}
+
 func setupServer(t *testing.T) (*httptest.Server, *chi.Mux, store.Engine, clock.PassiveClock) {
 	engine := inmemory.NewStore(clock.RealClock{})
 	ocpiApi := ocpi.NewOCPI(engine, nil, "GB", "TWK")
 
 	now := time.Now().UTC()
 	c := clockTest.NewFakePassiveClock(now)
-	srv, err := api.NewServer(engine, c, ocpiApi)
+	srv, err := api.NewServer(engine, c, ocpiApi, nil, nil)
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/go-chi/render"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Server) RegisterTariff(w http.ResponseWriter, r *http.Request, tariffId string) {
+	req := new(Tariff)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	tariff := toStoreTariff(tariffId, req, s.clock.Now().UTC())
+	err := s.store.SetTariff(r.Context(), tariff)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	if s.ocpi != nil {
+		err = s.ocpi.PushTariff(r.Context(), tariff)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) LookupTariff(w http.ResponseWriter, r *http.Request, tariffId string) {
+	tariff, err := s.store.LookupTariff(r.Context(), tariffId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if tariff == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, newTariff(tariff))
+}
+
+func (s *Server) DeleteTariff(w http.ResponseWriter, r *http.Request, tariffId string) {
+	tariff, err := s.store.LookupTariff(r.Context(), tariffId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if tariff == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	err = s.store.DeleteTariff(r.Context(), tariffId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	if s.ocpi != nil {
+		err = s.ocpi.PushTariffDeletion(r.Context(), tariffId)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toStoreTariff(tariffId string, req *Tariff, now time.Time) *store.Tariff {
+	tariff := &store.Tariff{
+		Id:            tariffId,
+		CountryCode:   req.CountryCode,
+		PartyId:       req.PartyId,
+		Currency:      req.Currency,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		StartDateTime: req.StartDateTime,
+		EndDateTime:   req.EndDateTime,
+		LastUpdated:   now,
+	}
+	if req.Type != nil {
+		tariff.Type = string(*req.Type)
+	}
+	if req.LocationIds != nil {
+		tariff.LocationIds = *req.LocationIds
+	}
+	if req.EvseUids != nil {
+		tariff.EvseUids = *req.EvseUids
+	}
+	if req.TokenGroupIds != nil {
+		tariff.TokenGroupIds = *req.TokenGroupIds
+	}
+	for _, element := range req.Elements {
+		tariffElement := store.TariffElement{}
+		for _, component := range element.PriceComponents {
+			tariffElement.PriceComponents = append(tariffElement.PriceComponents, store.PriceComponent{
+				Type:     string(component.Type),
+				Price:    component.Price,
+				Vat:      component.Vat,
+				StepSize: component.StepSize,
+			})
+		}
+		if element.Restrictions != nil {
+			tariffElement.Restrictions = toStoreTariffRestrictions(element.Restrictions)
+		}
+		tariff.Elements = append(tariff.Elements, tariffElement)
+	}
+	return tariff
+}
+
+func toStoreTariffRestrictions(restrictions *TariffRestrictions) *store.TariffRestrictions {
+	result := &store.TariffRestrictions{
+		MinKwh:      restrictions.MinKwh,
+		MaxKwh:      restrictions.MaxKwh,
+		MinPower:    restrictions.MinPower,
+		MaxPower:    restrictions.MaxPower,
+		MinDuration: restrictions.MinDuration,
+		MaxDuration: restrictions.MaxDuration,
+	}
+	if restrictions.StartTime != nil {
+		result.StartTime = *restrictions.StartTime
+	}
+	if restrictions.EndTime != nil {
+		result.EndTime = *restrictions.EndTime
+	}
+	if restrictions.StartDate != nil {
+		result.StartDate = restrictions.StartDate.String()
+	}
+	if restrictions.EndDate != nil {
+		result.EndDate = restrictions.EndDate.String()
+	}
+	if restrictions.DayOfWeek != nil {
+		for _, day := range *restrictions.DayOfWeek {
+			result.DayOfWeek = append(result.DayOfWeek, string(day))
+		}
+	}
+	return result
+}
+
+func newTariff(tariff *store.Tariff) *Tariff {
+	lastUpdated := tariff.LastUpdated
+	resp := &Tariff{
+		CountryCode:   tariff.CountryCode,
+		PartyId:       tariff.PartyId,
+		Currency:      tariff.Currency,
+		Elements:      []TariffElement{},
+		MinPrice:      tariff.MinPrice,
+		MaxPrice:      tariff.MaxPrice,
+		StartDateTime: tariff.StartDateTime,
+		EndDateTime:   tariff.EndDateTime,
+		LastUpdated:   &lastUpdated,
+	}
+	if tariff.Type != "" {
+		tariffType := TariffType(tariff.Type)
+		resp.Type = &tariffType
+	}
+	if len(tariff.LocationIds) > 0 {
+		resp.LocationIds = &tariff.LocationIds
+	}
+	if len(tariff.EvseUids) > 0 {
+		resp.EvseUids = &tariff.EvseUids
+	}
+	if len(tariff.TokenGroupIds) > 0 {
+		resp.TokenGroupIds = &tariff.TokenGroupIds
+	}
+	for _, element := range tariff.Elements {
+		tariffElement := TariffElement{
+			PriceComponents: []PriceComponent{},
+		}
+		for _, component := range element.PriceComponents {
+			tariffElement.PriceComponents = append(tariffElement.PriceComponents, PriceComponent{
+				Type:     PriceComponentType(component.Type),
+				Price:    component.Price,
+				Vat:      component.Vat,
+				StepSize: component.StepSize,
+			})
+		}
+		if element.Restrictions != nil {
+			tariffElement.Restrictions = newTariffRestrictions(element.Restrictions)
+		}
+		resp.Elements = append(resp.Elements, tariffElement)
+	}
+	return resp
+}
+
+func newTariffRestrictions(restrictions *store.TariffRestrictions) *TariffRestrictions {
+	optionalString := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	optionalDate := func(s string) *openapi_types.Date {
+		date, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return nil
+		}
+		return &openapi_types.Date{Time: date}
+	}
+
+	result := &TariffRestrictions{
+		StartTime:   optionalString(restrictions.StartTime),
+		EndTime:     optionalString(restrictions.EndTime),
+		StartDate:   optionalDate(restrictions.StartDate),
+		EndDate:     optionalDate(restrictions.EndDate),
+		MinKwh:      restrictions.MinKwh,
+		MaxKwh:      restrictions.MaxKwh,
+		MinPower:    restrictions.MinPower,
+		MaxPower:    restrictions.MaxPower,
+		MinDuration: restrictions.MinDuration,
+		MaxDuration: restrictions.MaxDuration,
+	}
+	if len(restrictions.DayOfWeek) > 0 {
+		days := make([]TariffRestrictionsDayOfWeek, 0, len(restrictions.DayOfWeek))
+		for _, day := range restrictions.DayOfWeek {
+			days = append(days, TariffRestrictionsDayOfWeek(day))
+		}
+		result.DayOfWeek = &days
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func makePtr[T any](t T) *T {
+	v := t
+	return &v
+}
+
+func TestRegisterTariff(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tariff/tariff001", strings.NewReader(`{
+  "countryCode": "GB",
+  "partyId": "TWK",
+  "currency": "EUR",
+  "type": "REGULAR",
+  "elements": [
+    {
+      "priceComponents": [{"type": "ENERGY", "price": 0.25, "vat": 20, "stepSize": 1}],
+      "restrictions": {
+        "startTime": "07:00",
+        "endTime": "19:00",
+        "startDate": "2024-01-01",
+        "maxKwh": 20,
+        "dayOfWeek": ["MONDAY", "TUESDAY"]
+      }
+    },
+    {
+      "priceComponents": [{"type": "PARKING_TIME", "price": 2, "stepSize": 300}]
+    }
+  ],
+  "maxPrice": 50,
+  "startDateTime": "2024-01-01T00:00:00Z",
+  "locationIds": ["loc001"],
+  "tokenGroupIds": ["fleet"]
+}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	startDateTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Type:        "REGULAR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.25, Vat: makePtr(20.0), StepSize: 1},
+				},
+				Restrictions: &store.TariffRestrictions{
+					StartTime: "07:00",
+					EndTime:   "19:00",
+					StartDate: "2024-01-01",
+					MaxKwh:    makePtr(20.0),
+					DayOfWeek: []string{"MONDAY", "TUESDAY"},
+				},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "PARKING_TIME", Price: 2, StepSize: 300},
+				},
+			},
+		},
+		MaxPrice:      makePtr(50.0),
+		StartDateTime: &startDateTime,
+		LocationIds:   []string{"loc001"},
+		TokenGroupIds: []string{"fleet"},
+		LastUpdated:   clock.Now().UTC(),
+	}
+	got, err := engine.LookupTariff(context.Background(), "tariff001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRegisterTariffWithInvalidTime(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tariff/tariff001", strings.NewReader(`{
+  "countryCode": "GB",
+  "partyId": "TWK",
+  "currency": "EUR",
+  "elements": [
+    {
+      "priceComponents": [{"type": "ENERGY", "price": 0.25, "stepSize": 1}],
+      "restrictions": {"startTime": "7am"}
+    }
+  ]
+}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestLookupTariff(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetTariff(context.Background(), &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "TIME", Price: 2, StepSize: 60},
+				},
+				Restrictions: &store.TariffRestrictions{
+					EndDate:     "2025-01-01",
+					MinDuration: makePtr(600),
+				},
+			},
+		},
+		EvseUids:    []string{"GBTWKEcs001"},
+		LastUpdated: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/tariff/tariff001", nil)
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "countryCode": "GB",
+  "partyId": "TWK",
+  "currency": "EUR",
+  "elements": [
+    {
+      "priceComponents": [{"type": "TIME", "price": 2, "stepSize": 60}],
+      "restrictions": {"endDate": "2025-01-01", "minDuration": 600}
+    }
+  ],
+  "evseUids": ["GBTWKEcs001"],
+  "lastUpdated": "2024-01-01T12:00:00Z"
+}`, string(b))
+}
+
+func TestLookupUnknownTariff(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/tariff/unknown", nil)
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestDeleteTariff(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	err := engine.SetTariff(context.Background(), &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/tariff/tariff001", nil)
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+	got, err := engine.LookupTariff(context.Background(), "tariff001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
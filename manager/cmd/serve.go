@@ -5,7 +5,9 @@ package cmd
 import (
 	"context"
 	"github.com/spf13/cobra"
+	gwserver "github.com/zynka-tech/zynka-csms/gateway/server"
 	"github.com/zynka-tech/zynka-csms/manager/config"
+	"github.com/zynka-tech/zynka-csms/manager/gateway"
 	"github.com/zynka-tech/zynka-csms/manager/server"
 	"github.com/zynka-tech/zynka-csms/manager/sync"
 	"github.com/zynka-tech/zynka-csms/manager/transport"
@@ -14,7 +16,10 @@ import (
 )
 
 var (
-	configFile string
+	configFile                string
+	embeddedGateway           bool
+	embeddedGatewayWsAddr     string
+	embeddedGatewayTrustProxy bool
 )
 
 // serveCmd represents the serve command
@@ -31,6 +36,9 @@ the gateway and send appropriate responses.`,
 				return err
 			}
 		}
+		if embeddedGateway {
+			cfg.Transport = config.TransportConfig{Type: "in_process"}
+		}
 
 		settings, err := config.Configure(context.Background(), &cfg)
 		if err != nil {
@@ -44,9 +52,11 @@ the gateway and send appropriate responses.`,
 		}()
 
 		apiServer := server.New("api", cfg.Api.Addr, nil,
-			server.NewApiHandler(settings.Api, settings.Storage, settings.OcpiApi, settings.ChargeStationCertProviderService))
+			server.NewApiHandler(settings.Api, settings.Storage, settings.OcpiApi, settings.ChargeStationCertProviderService,
+				settings.MsgEmitter, settings.PendingCalls, settings.CallQueue))
 
-		sync.Sync(settings.Storage, clock.RealClock{}, settings.Tracer, settings.MsgEmitter)
+		sync.Sync(settings.Storage, clock.RealClock{}, settings.Tracer, settings.MsgEmitter, settings.OcpiApi,
+			settings.OcpiTokensSyncInterval)
 
 		errCh := make(chan error, 1)
 		apiServer.Start(errCh)
@@ -66,8 +76,34 @@ the gateway and send appropriate responses.`,
 			}
 		}
 
+		// receive the responses to calls made by this instance that were
+		// received by other manager instances
+		var broadcastConnections []transport.Connection
+		if broadcastListener, ok := settings.MsgListener.(transport.BroadcastListener); ok {
+			for _, ocppVersion := range []transport.OcppVersion{transport.OcppVersion16, transport.OcppVersion201} {
+				conn, err := broadcastListener.ConnectBroadcast(context.Background(), ocppVersion, settings.PendingCalls)
+				if err != nil {
+					errCh <- err
+					break
+				}
+				broadcastConnections = append(broadcastConnections, conn)
+			}
+		}
+
+		if settings.InProcessTransport != nil {
+			gatewayTransport := settings.InProcessTransport.Gateway()
+			websocketHandler := gwserver.NewWebsocketHandler(
+				gwserver.WithTransport(gatewayTransport, gatewayTransport),
+				gwserver.WithDeviceRegistry(gateway.NewStoreRegistry(settings.Storage)),
+				gwserver.WithOrgName(cfg.Api.OrgName),
+				gwserver.WithTrustProxyHeaders(embeddedGatewayTrustProxy),
+				gwserver.WithOtelTracer(settings.Tracer))
+			wsServer := server.New("ws", embeddedGatewayWsAddr, nil, websocketHandler)
+			wsServer.Start(errCh)
+		}
+
 		if settings.OcpiApi != nil {
-			ocpiServer := server.New("ocpi", cfg.Ocpi.Addr, nil, server.NewOcpiHandler(settings.Storage, clock.RealClock{}, settings.OcpiApi, settings.MsgEmitter))
+			ocpiServer := server.New("ocpi", cfg.Ocpi.Addr, nil, server.NewOcpiHandler(settings.Storage, clock.RealClock{}, settings.OcpiApi))
 			ocpiServer.Start(errCh)
 		}
 
@@ -86,6 +122,13 @@ the gateway and send appropriate responses.`,
 			}
 		}
 
+		for _, conn := range broadcastConnections {
+			err := conn.Disconnect(context.Background())
+			if err != nil {
+				slog.Warn("disconnecting from broker", "err", err)
+			}
+		}
+
 		return err
 	},
 }
@@ -95,4 +138,10 @@ func init() {
 
 	serveCmd.Flags().StringVarP(&configFile, "config-file", "c", "/config/config.toml",
 		"The config file to use")
+	serveCmd.Flags().BoolVar(&embeddedGateway, "embedded-gateway", false,
+		"Run the gateway's websocket server in the manager, exchanging messages in memory rather than through a broker")
+	serveCmd.Flags().StringVar(&embeddedGatewayWsAddr, "embedded-gateway-ws-addr", "127.0.0.1:9310",
+		"The address that the embedded gateway's websocket server will listen on for connections, e.g. 127.0.0.1:9310")
+	serveCmd.Flags().BoolVar(&embeddedGatewayTrustProxy, "embedded-gateway-trust-proxy", false,
+		"Trust proxy headers when determining the client's TLS status in the embedded gateway")
 }
@@ -23,6 +23,7 @@ type BaseConfig struct {
 	ChargeStationCertProvider ChargeStationCertProviderConfig `mapstructure:"charge_station_cert_provider" toml:"charge_station_cert_provider" validate:"required"`
 	TariffService             TariffServiceConfig             `mapstructure:"tariff_service" toml:"tariff_service" validate:"required"`
 	Ocpi                      *OcpiConfig                     `mapstructure:"ocpi,omitempty" toml:"ocpi,omitempty"`
+	LoadBalancing             *LoadBalancingConfig            `mapstructure:"load_balancing,omitempty" toml:"load_balancing,omitempty"`
 }
 
 // DefaultConfig provides the default configuration. The configuration
@@ -7,16 +7,22 @@ import (
 	"crypto/tls"
 	"fmt"
 	"github.com/subnova/slog-exporter/slogtrace"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
 	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
 	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
 	"github.com/zynka-tech/zynka-csms/manager/schemas"
 	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/bolt"
 	"github.com/zynka-tech/zynka-csms/manager/store/firestore"
 	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/store/postgres"
 	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/inprocess"
 	mqtt2 "github.com/zynka-tech/zynka-csms/manager/transport/mqtt"
+	"github.com/zynka-tech/zynka-csms/manager/transport/nats"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -33,6 +39,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"time"
 )
 
@@ -42,6 +49,9 @@ type ApiSettings struct {
 	WsPort  int
 	WssPort int
 	OrgName string
+	// ConnectionStaleAfter is how long after a charge station was last seen
+	// that it is reported as offline
+	ConnectionStaleAfter time.Duration
 }
 
 type Config struct {
@@ -51,13 +61,21 @@ type Config struct {
 	Storage                          store.Engine
 	MsgEmitter                       transport.Emitter
 	MsgListener                      transport.Listener
+	InProcessTransport               *inprocess.Transport
 	Ocpp16Handler                    transport.MessageHandler
 	Ocpp201Handler                   transport.MessageHandler
 	ContractCertValidationService    services.CertificateValidationService
 	ContractCertProviderService      services.ContractCertificateProvider
 	ChargeStationCertProviderService services.ChargeStationCertificateProvider
 	TariffService                    services.TariffService
+	TokenAuthService                 services.TokenAuthService
+	LoadBalancer                     services.LoadBalancer
+	PendingCalls                     *handlers.PendingCalls
+	CallQueue                        *handlers.CallQueue
 	OcpiApi                          ocpi.Api
+	OcpiTokensSyncInterval           time.Duration
+	SessionPublisher                 services.SessionPublisher
+	EvseStatusPublisher              services.EvseStatusPublisher
 }
 
 func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
@@ -78,6 +96,9 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 			WsPort:  cfg.Api.WsPort,
 			WssPort: cfg.Api.WssPort,
 			OrgName: cfg.Api.OrgName,
+			// a connected charge station sends a heartbeat at least every
+			// heartbeat interval
+			ConnectionStaleAfter: 2 * heartbeatInterval,
 		},
 	}
 
@@ -122,17 +143,88 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 		return nil, err
 	}
 
-	c.TariffService, err = getTariffService(&cfg.TariffService)
+	evseMapping, err := getEvseMappingService(cfg.Ocpi, c.Storage)
 	if err != nil {
 		return nil, err
 	}
 
-	c.MsgEmitter, err = getMsgEmitter(&cfg.Transport, c.Tracer)
+	c.TariffService, err = getTariffService(&cfg.TariffService, c.Storage, evseMapping)
 	if err != nil {
 		return nil, err
 	}
 
-	c.MsgListener, err = getMsgListener(&cfg.Transport, c.Tracer)
+	if cfg.Transport.Type == "in_process" {
+		// the emitter and listener must be the same transport so that the
+		// embedded gateway can be connected to both
+		c.InProcessTransport = inprocess.NewTransport(inprocess.WithOtelTracer(c.Tracer))
+		c.MsgEmitter = c.InProcessTransport
+		c.MsgListener = c.InProcessTransport
+	} else {
+		c.MsgEmitter, err = getMsgEmitter(&cfg.Transport, c.Tracer)
+		if err != nil {
+			return nil, err
+		}
+
+		c.MsgListener, err = getMsgListener(&cfg.Transport, c.Tracer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the response to a call may be received by another manager instance so
+	// the responses are broadcast if the transport supports it
+	if broadcaster, ok := c.MsgEmitter.(transport.Broadcaster); ok {
+		c.PendingCalls = handlers.NewBroadcastPendingCalls(broadcaster)
+	} else {
+		c.PendingCalls = handlers.NewPendingCalls()
+	}
+
+	c.CallQueue, err = getCallQueue(&cfg.Ocpp, c.MsgEmitter, c.Storage)
+	if err != nil {
+		return nil, err
+	}
+	c.CallQueue.PendingCalls = c.PendingCalls
+	// all the calls made by the CSMS are queued until the charge station can
+	// process them
+	c.MsgEmitter = c.CallQueue
+
+	if cfg.LoadBalancing != nil {
+		c.LoadBalancer, err = getLoadBalancer(cfg.LoadBalancing, c.Storage, c.MsgEmitter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Ocpi != nil {
+		c.OcpiApi, err = getOcpiApi(cfg.Ocpi, c.Storage, httpClient, c.TariffService, evseMapping, c.MsgEmitter, c.PendingCalls)
+		if err != nil {
+			return nil, err
+		}
+
+		c.OcpiTokensSyncInterval = time.Hour
+		if cfg.Ocpi.TokensSyncInterval != "" {
+			c.OcpiTokensSyncInterval, err = time.ParseDuration(cfg.Ocpi.TokensSyncInterval)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse ocpi tokens sync interval: %s", err)
+			}
+		}
+
+		pushTimeout := 10 * time.Second
+		if cfg.Ocpi.PushTimeout != "" {
+			pushTimeout, err = time.ParseDuration(cfg.Ocpi.PushTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse ocpi push timeout: %s", err)
+			}
+		}
+		// the sessions and EVSE statuses are pushed to the eMSPs in the
+		// background so that handling the charge station's messages does not
+		// wait for them
+		publishQueue := services.NewPublishQueue(4, 1000, pushTimeout)
+		c.SessionPublisher = services.AsyncSessionPublisher{Publisher: c.OcpiApi, Queue: publishQueue}
+		c.EvseStatusPublisher = services.AsyncEvseStatusPublisher{Publisher: c.OcpiApi, Queue: publishQueue}
+	}
+
+	c.TokenAuthService, err = getTokenAuthService(cfg.Ocpi, c.Storage, c.OcpiApi)
 	if err != nil {
 		return nil, err
 	}
@@ -141,40 +233,127 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 		c.Ocpp16Handler = ocpp16.NewRouter(c.MsgEmitter,
 			clock.RealClock{},
 			c.Storage,
+			c.TokenAuthService,
 			c.ContractCertValidationService,
 			c.ChargeStationCertProviderService,
 			c.ContractCertProviderService,
+			c.LoadBalancer,
+			c.PendingCalls,
+			c.SessionPublisher,
+			c.EvseStatusPublisher,
 			heartbeatInterval,
 			schemas.OcppSchemas)
+		c.Ocpp16Handler = handlers.CallQueueHandler{
+			Next:  handlers.NewPresenceHandler(c.Ocpp16Handler, c.Storage, clock.RealClock{}),
+			Queue: c.CallQueue,
+		}
 	}
 	if cfg.Ocpp.Ocpp201Enabled {
 		c.Ocpp201Handler = ocpp201.NewRouter(c.MsgEmitter,
 			clock.RealClock{},
 			c.Storage,
+			c.TokenAuthService,
 			c.TariffService,
 			c.ContractCertValidationService,
 			c.ChargeStationCertProviderService,
 			c.ContractCertProviderService,
+			c.LoadBalancer,
+			c.PendingCalls,
+			c.SessionPublisher,
+			c.EvseStatusPublisher,
 			heartbeatInterval,
 			schemas.OcppSchemas)
+		c.Ocpp201Handler = handlers.CallQueueHandler{
+			Next:  handlers.NewPresenceHandler(c.Ocpp201Handler, c.Storage, clock.RealClock{}),
+			Queue: c.CallQueue,
+		}
 	}
 
-	if cfg.Ocpi != nil {
-		c.OcpiApi, err = getOcpiApi(cfg.Ocpi, c.Storage, httpClient)
+	return
+}
+
+func getCallQueue(cfg *OcppSettingsConfig, emitter transport.Emitter, engine store.Engine) (*handlers.CallQueue, error) {
+	queue := handlers.NewCallQueue(emitter, engine, clock.RealClock{})
+	var err error
+	if cfg.CallExpiry != "" {
+		queue.Expiry, err = time.ParseDuration(cfg.CallExpiry)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to parse call expiry: %s", err)
 		}
 	}
-
-	return
+	if cfg.CallResponseTimeout != "" {
+		queue.ResponseTimeout, err = time.ParseDuration(cfg.CallResponseTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse call response timeout: %s", err)
+		}
+	}
+	if cfg.CallMaxAttempts != 0 {
+		queue.MaxAttempts = cfg.CallMaxAttempts
+	}
+	return queue, nil
 }
 
-func getOcpiApi(o *OcpiConfig, engine store.Engine, httpClient *http.Client) (ocpi.Api, error) {
+func getOcpiApi(o *OcpiConfig, engine store.Engine, httpClient *http.Client, tariffService services.TariffService,
+	evseMapping services.EvseMappingService, emitter transport.Emitter, pendingCalls *handlers.PendingCalls) (ocpi.Api, error) {
 	api := ocpi.NewOCPI(engine, httpClient, o.CountryCode, o.PartyId)
 	api.SetExternalUrl(o.ExternalURL)
+	api.SetTariffService(tariffService)
+	api.SetEvseMappingService(evseMapping)
+	api.SetAuthorizeWithAllEmsps(o.AuthorizeWithAllEmsps)
+	v16CallMaker := ocpp16.NewCallMaker(emitter)
+	v16CallMaker.PendingCalls = pendingCalls
+	v201CallMaker := ocpp201.NewCallMaker(emitter)
+	v201CallMaker.PendingCalls = pendingCalls
+	api.SetCallMakers(v16CallMaker, v201CallMaker)
 	return api, nil
 }
 
+// getTokenAuthService returns a service that authorizes tokens with the eMSPs
+// in real-time if OCPI is enabled
+func getTokenAuthService(o *OcpiConfig, engine store.Engine, ocpiApi ocpi.Api) (services.TokenAuthService, error) {
+	var tokenAuthService services.TokenAuthService = &services.OcppTokenAuthService{
+		Clock:      clock.RealClock{},
+		TokenStore: engine,
+	}
+	if ocpiApi != nil {
+		realTimeTokenAuthService := &services.RealTimeTokenAuthService{
+			TokenAuthService: tokenAuthService,
+			TokenStore:       engine,
+			TokenAuthorizer:  ocpiApi,
+			Clock:            clock.RealClock{},
+		}
+		if o.AuthorizeTimeout != "" {
+			timeout, err := time.ParseDuration(o.AuthorizeTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse ocpi authorize timeout: %s", err)
+			}
+			realTimeTokenAuthService.Timeout = timeout
+		}
+		if o.AuthorizeFallbackStatus != "" {
+			status := types.AuthorizationStatusEnumType(o.AuthorizeFallbackStatus)
+			if !slices.Contains(authorizationStatuses, status) {
+				return nil, fmt.Errorf("unknown ocpi authorize fallback status: %s", o.AuthorizeFallbackStatus)
+			}
+			realTimeTokenAuthService.FallbackStatus = status
+		}
+		tokenAuthService = realTimeTokenAuthService
+	}
+	return tokenAuthService, nil
+}
+
+var authorizationStatuses = []types.AuthorizationStatusEnumType{
+	types.AuthorizationStatusEnumTypeAccepted,
+	types.AuthorizationStatusEnumTypeBlocked,
+	types.AuthorizationStatusEnumTypeConcurrentTx,
+	types.AuthorizationStatusEnumTypeExpired,
+	types.AuthorizationStatusEnumTypeInvalid,
+	types.AuthorizationStatusEnumTypeNoCredit,
+	types.AuthorizationStatusEnumTypeNotAllowedTypeEVSE,
+	types.AuthorizationStatusEnumTypeNotAtThisLocation,
+	types.AuthorizationStatusEnumTypeNotAtThisTime,
+	types.AuthorizationStatusEnumTypeUnknown,
+}
+
 func getHttpClient(keylogFile string) (*http.Client, error) {
 	var httpTransport http.RoundTripper
 
@@ -198,7 +377,8 @@ func getHttpClient(keylogFile string) (*http.Client, error) {
 		httpTransport = otelhttp.NewTransport(http.DefaultTransport)
 	}
 
-	return &http.Client{Transport: httpTransport}, nil
+	// a server that does not respond must not hold up the caller indefinitely
+	return &http.Client{Transport: httpTransport, Timeout: 30 * time.Second}, nil
 }
 
 func getStorage(ctx context.Context, cfg *StorageConfig) (engine store.Engine, err error) {
@@ -210,6 +390,16 @@ func getStorage(ctx context.Context, cfg *StorageConfig) (engine store.Engine, e
 		}
 	case "in_memory":
 		engine = inmemory.NewStore(clock.RealClock{})
+	case "postgres":
+		engine, err = postgres.NewStore(ctx, cfg.PostgresStorage.Url, clock.RealClock{})
+		if err != nil {
+			return nil, fmt.Errorf("create postgres storage: %w", err)
+		}
+	case "bolt":
+		engine, err = bolt.NewStore(cfg.BoltStorage.Path, clock.RealClock{})
+		if err != nil {
+			return nil, fmt.Errorf("create bolt storage: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unknown storage type: %s", cfg.Type)
 	}
@@ -412,10 +602,20 @@ func getLocalSource(cfg *LocalSourceConfig) (source services.LocalSource, err er
 	return
 }
 
-func getTariffService(cfg *TariffServiceConfig) (tariffService services.TariffService, err error) {
+func getEvseMappingService(cfg *OcpiConfig, engine store.Engine) (services.EvseMappingService, error) {
+	var patterns []string
+	if cfg != nil {
+		patterns = cfg.EvseMappingPatterns
+	}
+	return services.NewPatternEvseMappingService(engine, patterns)
+}
+
+func getTariffService(cfg *TariffServiceConfig, engine store.Engine, evseMapping services.EvseMappingService) (tariffService services.TariffService, err error) {
 	switch cfg.Type {
 	case "kwh":
 		tariffService = services.BasicKwhTariffService{}
+	case "ocpi":
+		tariffService = services.OcpiTariffService{Store: engine, EvseMapping: evseMapping}
 	default:
 		return nil, fmt.Errorf("unknown tariff service type: %s", cfg.Type)
 	}
@@ -423,6 +623,42 @@ func getTariffService(cfg *TariffServiceConfig) (tariffService services.TariffSe
 	return
 }
 
+func getLoadBalancer(cfg *LoadBalancingConfig, engine store.Engine, emitter transport.Emitter) (services.LoadBalancer, error) {
+	var strategy services.AllocationStrategy
+	switch cfg.Type {
+	case "equal_share":
+		strategy = services.EqualShareAllocationStrategy{}
+	case "priority":
+		strategy = services.PriorityAllocationStrategy{}
+	case "first_come":
+		strategy = services.FirstComeAllocationStrategy{}
+	default:
+		return nil, fmt.Errorf("unknown load balancing type: %s", cfg.Type)
+	}
+
+	sites := make([]*services.Site, len(cfg.Sites))
+	for index, siteCfg := range cfg.Sites {
+		sites[index] = &services.Site{
+			Id:                    siteCfg.Id,
+			MaxCurrent:            siteCfg.MaxCurrent,
+			MinTransactionCurrent: siteCfg.MinTransactionCurrent,
+			MaxTransactionCurrent: siteCfg.MaxTransactionCurrent,
+			ChargeStationIds:      siteCfg.ChargeStations,
+			Priorities:            siteCfg.Priorities,
+		}
+	}
+
+	return &services.SiteLoadBalancer{
+		Store:         engine,
+		Clock:         clock.RealClock{},
+		Strategy:      strategy,
+		Sites:         sites,
+		V16CallMaker:  ocpp16.NewCallMaker(emitter),
+		V201CallMaker: ocpp201.NewCallMaker(emitter),
+		RetryAfter:    2 * time.Minute,
+	}, nil
+}
+
 func getMsgEmitter(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Emitter, error) {
 	switch cfg.Type {
 	case "mqtt":
@@ -457,6 +693,18 @@ func getMsgEmitter(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Emi
 			mqtt2.WithOtelTracer[mqtt2.Emitter](tracer))
 
 		return mqttEmitter, nil
+	case "nats":
+		natsSettings, err := getNatsSettings(cfg.Nats)
+		if err != nil {
+			return nil, err
+		}
+
+		return nats.NewEmitter(
+			nats.WithNatsUrls[nats.Emitter](natsSettings.urls),
+			nats.WithNatsPrefix[nats.Emitter](cfg.Nats.Prefix),
+			nats.WithNatsConnectSettings[nats.Emitter](natsSettings.connectTimeout, natsSettings.connectRetryDelay),
+			nats.WithNatsStreamMaxAge[nats.Emitter](natsSettings.streamMaxAge),
+			nats.WithOtelTracer[nats.Emitter](tracer)), nil
 	default:
 		return nil, fmt.Errorf("unknown transport type: %s", cfg.Type)
 	}
@@ -498,11 +746,65 @@ func getMsgListener(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Li
 		}
 
 		return mqtt2.NewListener(opts...), nil
+	case "nats":
+		natsSettings, err := getNatsSettings(cfg.Nats)
+		if err != nil {
+			return nil, err
+		}
+
+		return nats.NewListener(
+			nats.WithNatsUrls[nats.Listener](natsSettings.urls),
+			nats.WithNatsPrefix[nats.Listener](cfg.Nats.Prefix),
+			nats.WithNatsConnectSettings[nats.Listener](natsSettings.connectTimeout, natsSettings.connectRetryDelay),
+			nats.WithNatsStreamMaxAge[nats.Listener](natsSettings.streamMaxAge),
+			nats.WithNatsGroup[nats.Listener](cfg.Nats.Group),
+			nats.WithOtelTracer[nats.Listener](tracer)), nil
 	default:
 		return nil, fmt.Errorf("unknown transport type: %s", cfg.Type)
 	}
 }
 
+// natsConnectionSettings are the parsed NATS settings: a duration that is not
+// configured is zero so the transport's default is used.
+type natsConnectionSettings struct {
+	urls              []*url.URL
+	connectTimeout    time.Duration
+	connectRetryDelay time.Duration
+	streamMaxAge      time.Duration
+}
+
+func getNatsSettings(cfg *NatsSettingsConfig) (*natsConnectionSettings, error) {
+	settings := new(natsConnectionSettings)
+	for _, urlStr := range cfg.Urls {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse nats url: %w", err)
+		}
+		settings.urls = append(settings.urls, u)
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"connect timeout", cfg.ConnectTimeout, &settings.connectTimeout},
+		{"connect retry delay", cfg.ConnectRetryDelay, &settings.connectRetryDelay},
+		{"stream max age", cfg.StreamMaxAge, &settings.streamMaxAge},
+	} {
+		if d.value == "" {
+			continue
+		}
+		var err error
+		*d.dest, err = time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse nats %s: %w", d.name, err)
+		}
+	}
+
+	return settings, nil
+}
+
 func getTracerProvider(ctx context.Context, collectorAddr string) (*trace.TracerProvider, error) {
 	var err error
 	var res *resource.Resource
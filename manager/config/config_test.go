@@ -8,8 +8,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zynka-tech/zynka-csms/manager/config"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConfigure(t *testing.T) {
@@ -20,11 +23,12 @@ func TestConfigure(t *testing.T) {
 	require.NoError(t, err)
 
 	wantApiSettings := config.ApiSettings{
-		Addr:    "localhost:9410",
-		Host:    "localhost",
-		WsPort:  80,
-		WssPort: 443,
-		OrgName: "Zynka-tech",
+		Addr:                 "localhost:9410",
+		Host:                 "localhost",
+		WsPort:               80,
+		WssPort:              443,
+		OrgName:              "Zynka-tech",
+		ConnectionStaleAfter: 10 * time.Minute,
 	}
 
 	assert.Equal(t, wantApiSettings, settings.Api)
@@ -66,6 +70,19 @@ func TestConfigureInMemoryStorage(t *testing.T) {
 	require.NotNil(t, settings.Storage)
 }
 
+func TestConfigureBoltStorage(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Storage.Type = "bolt"
+	cfg.Storage.BoltStorage = &config.BoltStorageConfig{
+		Path: filepath.Join(t.TempDir(), "csms.db"),
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.Storage)
+}
+
 func TestConfigureOcspContractCertValidator(t *testing.T) {
 	cfg := clone.Clone(&config.DefaultConfig)
 	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
@@ -256,3 +273,152 @@ func TestConfigureKwHTariffService(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, settings.ContractCertProviderService)
 }
+
+func TestConfigureOcpiTariffService(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.TariffService.Type = "ocpi"
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.IsType(t, services.OcpiTariffService{}, settings.TariffService)
+}
+
+func TestConfigureOcpiEvseMappingPatterns(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Ocpi = &config.OcpiConfig{
+		Addr:                "localhost:9411",
+		ExternalURL:         "http://localhost:9411",
+		CountryCode:         "GB",
+		PartyId:             "TWK",
+		EvseMappingPatterns: []string{`^EVSE-(?P<charge_station_id>[a-z0-9]+)-(?P<evse_id>[0-9]+)$`},
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, settings.OcpiApi)
+
+	cfg.Ocpi.EvseMappingPatterns = []string{`^EVSE-([a-z0-9]+)$`}
+	_, err = config.Configure(context.TODO(), cfg)
+	assert.ErrorContains(t, err, "has no charge_station_id group")
+}
+
+func TestConfigureOcpiTokensSyncInterval(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Ocpi = &config.OcpiConfig{
+		Addr:        "localhost:9411",
+		ExternalURL: "http://localhost:9411",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, settings.OcpiTokensSyncInterval)
+
+	cfg.Ocpi.TokensSyncInterval = "15m"
+	settings, err = config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, settings.OcpiTokensSyncInterval)
+
+	cfg.Ocpi.TokensSyncInterval = "often"
+	_, err = config.Configure(context.TODO(), cfg)
+	assert.ErrorContains(t, err, "failed to parse ocpi tokens sync interval")
+}
+
+func TestConfigureCallQueue(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, settings.CallQueue.Expiry)
+	assert.Equal(t, time.Minute, settings.CallQueue.ResponseTimeout)
+	assert.Equal(t, 3, settings.CallQueue.MaxAttempts)
+
+	cfg.Ocpp.CallExpiry = "1h"
+	cfg.Ocpp.CallResponseTimeout = "30s"
+	cfg.Ocpp.CallMaxAttempts = 5
+	settings, err = config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, settings.CallQueue.Expiry)
+	assert.Equal(t, 30*time.Second, settings.CallQueue.ResponseTimeout)
+	assert.Equal(t, 5, settings.CallQueue.MaxAttempts)
+
+	cfg.Ocpp.CallResponseTimeout = "soon"
+	_, err = config.Configure(context.TODO(), cfg)
+	assert.ErrorContains(t, err, "failed to parse call response timeout")
+}
+
+func TestConfigureLoadBalancing(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.LoadBalancing = &config.LoadBalancingConfig{
+		Type: "priority",
+		Sites: []config.SiteConfig{
+			{
+				Id:                    "depot",
+				MaxCurrent:            100,
+				MinTransactionCurrent: 6,
+				MaxTransactionCurrent: 32,
+				ChargeStations:        []string{"cs001", "cs002"},
+				Priorities:            map[string]int{"cs001": 1},
+			},
+		},
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.LoadBalancer)
+}
+
+func TestConfigureLoadBalancingRequiresSiteCapacity(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.LoadBalancing = &config.LoadBalancingConfig{
+		Type: "equal_share",
+		Sites: []config.SiteConfig{
+			{
+				Id:                    "depot",
+				MaxTransactionCurrent: 32,
+				ChargeStations:        []string{"cs001"},
+			},
+		},
+	}
+
+	_, err := config.Configure(context.TODO(), cfg)
+	require.Error(t, err)
+}
+
+func TestConfigureNatsTransport(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Transport.Type = "nats"
+	cfg.Transport.Nats = &config.NatsSettingsConfig{
+		Urls:         []string{"nats://localhost:4222"},
+		Prefix:       "cs",
+		Group:        "manager",
+		StreamMaxAge: "24h",
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.MsgEmitter)
+	require.NotNil(t, settings.MsgListener)
+}
+
+func TestConfigureInProcessTransport(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Transport.Type = "in_process"
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.InProcessTransport)
+	require.NotNil(t, settings.CallQueue)
+	assert.Same(t, settings.CallQueue, settings.MsgEmitter)
+	assert.Same(t, settings.InProcessTransport, settings.CallQueue.Emitter)
+	assert.Same(t, settings.InProcessTransport, settings.MsgListener)
+}
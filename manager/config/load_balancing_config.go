@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type LoadBalancingConfig struct {
+	Type  string       `mapstructure:"type" toml:"type" validate:"required,oneof=equal_share priority first_come"`
+	Sites []SiteConfig `mapstructure:"sites" toml:"sites" validate:"required,dive"`
+}
+
+type SiteConfig struct {
+	Id                    string         `mapstructure:"id" toml:"id" validate:"required"`
+	MaxCurrent            float64        `mapstructure:"max_current" toml:"max_current" validate:"required,gt=0"`
+	MinTransactionCurrent float64        `mapstructure:"min_transaction_current,omitempty" toml:"min_transaction_current,omitempty" validate:"gte=0"`
+	MaxTransactionCurrent float64        `mapstructure:"max_transaction_current" toml:"max_transaction_current" validate:"required,gt=0"`
+	ChargeStations        []string       `mapstructure:"charge_stations" toml:"charge_stations" validate:"required,min=1"`
+	Priorities            map[string]int `mapstructure:"priorities,omitempty" toml:"priorities,omitempty"`
+}
@@ -7,4 +7,22 @@ type OcpiConfig struct {
 	ExternalURL string `mapstructure:"external_url" toml:"external_url" validate:"required"`
 	CountryCode string `mapstructure:"country_code" toml:"country_code" validate:"required"`
 	PartyId     string `mapstructure:"party_id" toml:"party_id" validate:"required"`
+	// EvseMappingPatterns are the regular expressions used to map OCPI EVSE uids
+	// and EVSE IDs to charge stations, see services.PatternEvseMappingService
+	EvseMappingPatterns []string `mapstructure:"evse_mapping_patterns" toml:"evse_mapping_patterns,omitempty"`
+	// TokensSyncInterval is how often the tokens are pulled from the eMSPs,
+	// defaults to 1h
+	TokensSyncInterval string `mapstructure:"tokens_sync_interval" toml:"tokens_sync_interval,omitempty"`
+	// PushTimeout bounds each push to the eMSPs, defaults to 10s
+	PushTimeout string `mapstructure:"push_timeout" toml:"push_timeout,omitempty"`
+	// AuthorizeTimeout bounds the real-time authorization of a token by the eMSP
+	// that issued it, defaults to 5s
+	AuthorizeTimeout string `mapstructure:"authorize_timeout" toml:"authorize_timeout,omitempty"`
+	// AuthorizeFallbackStatus is the OCPP 2.0.1 authorization status of a token
+	// that the eMSP did not authorize within the AuthorizeTimeout, defaults to
+	// Unknown
+	AuthorizeFallbackStatus string `mapstructure:"authorize_fallback_status" toml:"authorize_fallback_status,omitempty"`
+	// AuthorizeWithAllEmsps asks every eMSP to authorize a token whose issuer is
+	// not known, defaults to false
+	AuthorizeWithAllEmsps bool `mapstructure:"authorize_with_all_emsps" toml:"authorize_with_all_emsps,omitempty"`
 }
@@ -14,6 +14,12 @@ type OcppSettingsConfig struct {
 	HeartbeatInterval string `mapstructure:"heartbeat_interval" toml:"heartbeat_interval" validate:"required"`
 	Ocpp16Enabled     bool   `mapstructure:"ocpp16_enabled" toml:"ocpp16_enabled" validate:"required_without=Ocpp201Enabled"`
 	Ocpp201Enabled    bool   `mapstructure:"ocpp201_enabled" toml:"ocpp201_enabled" validate:"required_without=Ocpp16Enabled"`
+	// CallExpiry is how long a call made by the CSMS is queued for delivery to the charge station
+	CallExpiry string `mapstructure:"call_expiry" toml:"call_expiry"`
+	// CallResponseTimeout is how long to wait for a response before sending a queued call again
+	CallResponseTimeout string `mapstructure:"call_response_timeout" toml:"call_response_timeout"`
+	// CallMaxAttempts is the number of times a queued call is sent before it is discarded
+	CallMaxAttempts int `mapstructure:"call_max_attempts" toml:"call_max_attempts" validate:"omitempty,min=1"`
 }
 
 type ObservabilitySettingsConfig struct {
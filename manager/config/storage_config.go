@@ -8,8 +8,18 @@ type FirestoreStorageConfig struct {
 	ProjectId string `mapstructure:"project_id" toml:"project_id" validate:"required"`
 }
 
+type BoltStorageConfig struct {
+	Path string `mapstructure:"path" toml:"path" validate:"required"`
+}
+
+type PostgresStorageConfig struct {
+	Url string `mapstructure:"url" toml:"url" validate:"required"`
+}
+
 type StorageConfig struct {
-	Type             string                  `mapstructure:"type" toml:"type" validate:"required,oneof=firestore in_memory"`
+	Type             string                  `mapstructure:"type" toml:"type" validate:"required,oneof=firestore in_memory postgres bolt"`
 	FirestoreStorage *FirestoreStorageConfig `mapstructure:"firestore,omitempty" toml:"firestore,omitempty" validate:"required_if=Type firestore"`
 	InMemoryStorage  *InMemoryStorageConfig  `mapstructure:"in_memory,omitempty" toml:"in_memory,omitempty"`
+	PostgresStorage  *PostgresStorageConfig  `mapstructure:"postgres,omitempty" toml:"postgres,omitempty" validate:"required_if=Type postgres"`
+	BoltStorage      *BoltStorageConfig      `mapstructure:"bolt,omitempty" toml:"bolt,omitempty" validate:"required_if=Type bolt"`
 }
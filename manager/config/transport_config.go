@@ -11,7 +11,17 @@ type MqttSettingsConfig struct {
 	KeepAliveInterval string   `mapstructure:"keep_alive_interval" toml:"keep_alive_interval" validate:"required"`
 }
 
+type NatsSettingsConfig struct {
+	Urls              []string `mapstructure:"urls" toml:"urls" validate:"required,dive,required"`
+	Prefix            string   `mapstructure:"prefix" toml:"prefix" validate:"required"`
+	Group             string   `mapstructure:"group" toml:"group" validate:"required"`
+	ConnectTimeout    string   `mapstructure:"connect_timeout" toml:"connect_timeout"`
+	ConnectRetryDelay string   `mapstructure:"connect_retry_delay" toml:"connect_retry_delay"`
+	StreamMaxAge      string   `mapstructure:"stream_max_age" toml:"stream_max_age"`
+}
+
 type TransportConfig struct {
-	Type string              `mapstructure:"type" toml:"type" validate:"required,oneof=mqtt"`
+	Type string              `mapstructure:"type" toml:"type" validate:"required,oneof=mqtt nats in_process"`
 	Mqtt *MqttSettingsConfig `mapstructure:"mqtt,omitempty" toml:"mqtt,omitempty" validate:"required_if=Type mqtt"`
+	Nats *NatsSettingsConfig `mapstructure:"nats,omitempty" toml:"nats,omitempty" validate:"required_if=Type nats"`
 }
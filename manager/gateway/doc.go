@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gateway provides the support needed to run the gateway's
+// websocket server within the manager process
+package gateway
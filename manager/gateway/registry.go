@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/gateway/registry"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"strings"
+)
+
+// StoreRegistry is an implementation of the gateway's registry.DeviceRegistry
+// that looks up the charge stations and certificates in the manager's store
+// rather than calling the manager's API.
+type StoreRegistry struct {
+	engine store.Engine
+}
+
+func NewStoreRegistry(engine store.Engine) *StoreRegistry {
+	return &StoreRegistry{engine: engine}
+}
+
+func (r *StoreRegistry) LookupChargeStation(clientId string) (*registry.ChargeStation, error) {
+	auth, err := r.engine.LookupChargeStationAuth(context.Background(), clientId)
+	if err != nil {
+		return nil, fmt.Errorf("looking up charge station auth: %w", err)
+	}
+	if auth == nil {
+		return nil, nil
+	}
+
+	return &registry.ChargeStation{
+		ClientId:               clientId,
+		SecurityProfile:        registry.SecurityProfile(auth.SecurityProfile),
+		Base64SHA256Password:   auth.Base64SHA256Password,
+		InvalidUsernameAllowed: auth.InvalidUsernameAllowed,
+	}, nil
+}
+
+func (r *StoreRegistry) LookupCertificate(certHash string) (*x509.Certificate, error) {
+	// the store uses the URL-safe base64 encoding of the hash
+	certHash = strings.Replace(certHash, "/", "_", -1)
+	certHash = strings.Replace(certHash, "+", "-", -1)
+
+	pemCertificate, err := r.engine.LookupCertificate(context.Background(), certHash)
+	if err != nil {
+		return nil, fmt.Errorf("looking up certificate: %w", err)
+	}
+	if pemCertificate == "" {
+		return nil, nil
+	}
+
+	block, _ := pem.Decode([]byte(pemCertificate))
+	if block == nil {
+		return nil, fmt.Errorf("no pem data found")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
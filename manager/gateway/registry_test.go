@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/gateway/registry"
+	"github.com/zynka-tech/zynka-csms/manager/gateway"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestStoreRegistryLookupChargeStation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetChargeStationAuth(context.Background(), "cs001", &store.ChargeStationAuth{
+		SecurityProfile:        store.TLSWithBasicAuth,
+		Base64SHA256Password:   "DEADBEEF",
+		InvalidUsernameAllowed: true,
+	})
+	require.NoError(t, err)
+
+	cs, err := gateway.NewStoreRegistry(engine).LookupChargeStation("cs001")
+	require.NoError(t, err)
+
+	want := &registry.ChargeStation{
+		ClientId:               "cs001",
+		SecurityProfile:        registry.TLSWithBasicAuth,
+		Base64SHA256Password:   "DEADBEEF",
+		InvalidUsernameAllowed: true,
+	}
+	assert.Equal(t, want, cs)
+}
+
+func TestStoreRegistryLookupUnknownChargeStation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	cs, err := gateway.NewStoreRegistry(engine).LookupChargeStation("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, cs)
+}
+
+func TestStoreRegistryLookupCertificate(t *testing.T) {
+	cert := generateCertificate(t)
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetCertificate(context.Background(), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(cert.Raw)
+	got, err := gateway.NewStoreRegistry(engine).LookupCertificate(base64.RawStdEncoding.EncodeToString(hash[:]))
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, cert.Raw, got.Raw)
+}
+
+func TestStoreRegistryLookupUnknownCertificate(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	got, err := gateway.NewStoreRegistry(engine).LookupCertificate("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func generateCertificate(t *testing.T) *x509.Certificate {
+	keyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	notBefore := time.Now()
+	notAfter := notBefore.Add(24 * time.Hour)
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Zynka-tech"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &keyPair.PublicKey, keyPair)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(derBytes)
+	require.NoError(t, err)
+
+	return cert
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+)
+
+type afterResponseKey struct{}
+
+// afterResponse holds the functions registered with AfterResponse while a call is handled
+type afterResponse struct {
+	fns []func(ctx context.Context)
+}
+
+// AfterResponse registers a function that the Router runs once the response to the
+// call that is being handled has been sent to the charge station. It is used for
+// calls to the charge station that must not arrive before the response, e.g. a
+// charging profile for a transaction that the charge station has not yet been given
+// the id of. The function is not run if the response could not be sent. When the
+// call is not being handled by a Router the function is run immediately.
+func AfterResponse(ctx context.Context, fn func(ctx context.Context)) {
+	hooks, ok := ctx.Value(afterResponseKey{}).(*afterResponse)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	hooks.fns = append(hooks.fns, fn)
+}
+
+func withAfterResponse(ctx context.Context) (context.Context, *afterResponse) {
+	hooks := new(afterResponse)
+	return context.WithValue(ctx, afterResponseKey{}, hooks), hooks
+}
+
+func (a *afterResponse) run(ctx context.Context) {
+	for _, fn := range a.fns {
+		fn(ctx)
+	}
+}
@@ -5,6 +5,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
@@ -15,29 +16,72 @@ import (
 
 // OcppCallMaker is an implementation of the CallMaker interface for a specific set of OCPP messages.
 type OcppCallMaker struct {
-	Emitter     transport.Emitter       // used to send the message to the charge station
-	OcppVersion transport.OcppVersion   // identifies the OCPP version that the messages are for
-	Actions     map[reflect.Type]string // the OCPP Action associated with a specific ocpp.Request object
+	Emitter      transport.Emitter       // used to send the message to the charge station
+	OcppVersion  transport.OcppVersion   // identifies the OCPP version that the messages are for
+	Actions      map[reflect.Type]string // the OCPP Action associated with a specific ocpp.Request object
+	PendingCalls *PendingCalls           // used to wait for the response to a call (only required by Call)
 }
 
 func (b OcppCallMaker) Send(ctx context.Context, chargeStationId string, request ocpp.Request) error {
+	msg, err := b.newCallMessage(request)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("sending message", "action", msg.Action, "chargeStationId", chargeStationId)
+	return b.Emitter.Emit(ctx, b.OcppVersion, chargeStationId, msg)
+}
+
+func (b OcppCallMaker) Call(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response) error {
+	if b.PendingCalls == nil {
+		return errors.New("call maker cannot wait for responses")
+	}
+
+	msg, err := b.newCallMessage(request)
+	if err != nil {
+		return err
+	}
+	msg.MessageId = b.PendingCalls.newMessageId()
+
+	resultCh := b.PendingCalls.add(chargeStationId, msg.MessageId)
+	defer b.PendingCalls.remove(chargeStationId, msg.MessageId)
+
+	slog.Info("sending message", "action", msg.Action, "chargeStationId", chargeStationId)
+	err = b.Emitter.Emit(ctx, b.OcppVersion, chargeStationId, msg)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.MessageType == transport.MessageTypeCallError {
+			return transport.NewError(result.ErrorCode, errors.New(result.ErrorDescription))
+		}
+		err = json.Unmarshal(result.ResponsePayload, response)
+		if err != nil {
+			return fmt.Errorf("unmarshalling %s response payload: %w", msg.Action, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for %s response: %w", msg.Action, ctx.Err())
+	}
+}
+
+func (b OcppCallMaker) newCallMessage(request ocpp.Request) (*transport.Message, error) {
 	action, ok := b.Actions[reflect.TypeOf(request)]
 	if !ok {
-		return fmt.Errorf("unknown request type: %T", request)
+		return nil, fmt.Errorf("unknown request type: %T", request)
 	}
 
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	msg := &transport.Message{
+	return &transport.Message{
 		MessageType:    transport.MessageTypeCall,
 		MessageId:      uuid.New().String(),
 		Action:         action,
 		RequestPayload: requestBytes,
-	}
-
-	slog.Info("sending message", "action", msg.Action, "chargeStationId", chargeStationId)
-	return b.Emitter.Emit(ctx, b.OcppVersion, chargeStationId, msg)
+	}, nil
 }
@@ -4,13 +4,16 @@ package handlers_test
 
 import (
 	"context"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/zynka-tech/zynka-csms/manager/handlers"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
 	"github.com/zynka-tech/zynka-csms/manager/transport"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 type FakeEmitter struct {
@@ -67,3 +70,148 @@ func TestCallMakerWithUnknownMessageType(t *testing.T) {
 	assert.ErrorContains(t, err, "unknown request type")
 	assert.Nil(t, emitter.msg)
 }
+
+// RespondingEmitter responds to each call it emits by routing the response
+// through the router
+type RespondingEmitter struct {
+	router   handlers.Router
+	response func(msg *transport.Message) *transport.Message
+}
+
+func (e *RespondingEmitter) Emit(ctx context.Context, _ transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	if response := e.response(message); response != nil {
+		go e.router.Handle(ctx, chargeStationId, response)
+	}
+	return nil
+}
+
+func newSyncCallMaker(response func(msg *transport.Message) *transport.Message) *handlers.OcppCallMaker {
+	pendingCalls := handlers.NewPendingCalls()
+	emitter := &RespondingEmitter{
+		router: handlers.Router{
+			OcppVersion:  transport.OcppVersion201,
+			PendingCalls: pendingCalls,
+		},
+		response: response,
+	}
+	return &handlers.OcppCallMaker{
+		Emitter:     emitter,
+		OcppVersion: transport.OcppVersion201,
+		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}): "ClearCache",
+		},
+		PendingCalls: pendingCalls,
+	}
+}
+
+func TestCallMakerCallReturnsResponse(t *testing.T) {
+	callMaker := newSyncCallMaker(func(msg *transport.Message) *transport.Message {
+		return &transport.Message{
+			MessageType:     transport.MessageTypeCallResult,
+			Action:          msg.Action,
+			MessageId:       msg.MessageId,
+			RequestPayload:  msg.RequestPayload,
+			ResponsePayload: []byte(`{"status":"Accepted"}`),
+		}
+	})
+
+	resp := new(ocpp201.ClearCacheResponseJson)
+	err := callMaker.Call(context.Background(), "cs001", &ocpp201.ClearCacheRequestJson{}, resp)
+	require.NoError(t, err)
+	assert.Equal(t, ocpp201.ClearCacheStatusEnumTypeAccepted, resp.Status)
+}
+
+func TestCallMakerCallReturnsCallError(t *testing.T) {
+	callMaker := newSyncCallMaker(func(msg *transport.Message) *transport.Message {
+		return transport.NewErrorMessage(msg.Action, msg.MessageId, transport.ErrorNotImplemented, errors.New("not supported"))
+	})
+
+	err := callMaker.Call(context.Background(), "cs001", &ocpp201.ClearCacheRequestJson{}, new(ocpp201.ClearCacheResponseJson))
+	var callErr *transport.Error
+	require.ErrorAs(t, err, &callErr)
+	assert.Equal(t, transport.ErrorNotImplemented, callErr.ErrorCode)
+}
+
+func TestCallMakerCallTimesOut(t *testing.T) {
+	callMaker := newSyncCallMaker(func(msg *transport.Message) *transport.Message {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := callMaker.Call(ctx, "cs001", &ocpp201.ClearCacheRequestJson{}, new(ocpp201.ClearCacheResponseJson))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPendingCallsIgnoresUnknownResponse(t *testing.T) {
+	pendingCalls := handlers.NewPendingCalls()
+	resolved := pendingCalls.Resolve("cs001", &transport.Message{
+		MessageType: transport.MessageTypeCallResult,
+		MessageId:   "unknown",
+	})
+	assert.False(t, resolved)
+}
+
+func TestCallMakerCallTagsMessageIdWithInstance(t *testing.T) {
+	emitter := &FakeEmitter{}
+	callMaker := &handlers.OcppCallMaker{
+		Emitter:     emitter,
+		OcppVersion: transport.OcppVersion201,
+		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}): "ClearCache",
+		},
+		PendingCalls: handlers.NewPendingCalls(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := callMaker.Call(ctx, "cs001", &ocpp201.ClearCacheRequestJson{}, new(ocpp201.ClearCacheResponseJson))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Regexp(t, `^[0-9a-f]{8}:[0-9a-f]{24}$`, emitter.msg.MessageId)
+}
+
+type recordingBroadcaster struct {
+	messageIds []string
+}
+
+func (r *recordingBroadcaster) Broadcast(_ context.Context, _ transport.OcppVersion, _ string, message *transport.Message) error {
+	r.messageIds = append(r.messageIds, message.MessageId)
+	return nil
+}
+
+func TestPendingCallsOnlyBroadcastsResponsesToOtherInstances(t *testing.T) {
+	ctx := context.Background()
+
+	callMessageId := func(pendingCalls *handlers.PendingCalls) string {
+		emitter := &FakeEmitter{}
+		callMaker := &handlers.OcppCallMaker{
+			Emitter:     emitter,
+			OcppVersion: transport.OcppVersion201,
+			Actions: map[reflect.Type]string{
+				reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}): "ClearCache",
+			},
+			PendingCalls: pendingCalls,
+		}
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_ = callMaker.Call(ctx, "cs001", &ocpp201.ClearCacheRequestJson{}, new(ocpp201.ClearCacheResponseJson))
+		return emitter.msg.MessageId
+	}
+
+	broadcaster := &recordingBroadcaster{}
+	pendingCalls := handlers.NewBroadcastPendingCalls(broadcaster)
+	otherInstanceMessageId := callMessageId(handlers.NewPendingCalls())
+	thisInstanceMessageId := callMessageId(pendingCalls)
+
+	for _, messageId := range []string{otherInstanceMessageId, thisInstanceMessageId, "7b1e1a6e-3e5b-4b8c-9a3e-2f1c7d9e0a12"} {
+		pendingCalls.Deliver(ctx, transport.OcppVersion201, "cs001", &transport.Message{
+			MessageType:     transport.MessageTypeCallResult,
+			Action:          "ClearCache",
+			MessageId:       messageId,
+			ResponsePayload: []byte(`{"status":"Accepted"}`),
+		})
+	}
+
+	assert.Equal(t, []string{otherInstanceMessageId}, broadcaster.messageIds)
+}
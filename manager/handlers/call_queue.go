@@ -0,0 +1,411 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// DefaultCallPriorities delivers calls that affect a transaction or the
+// availability of a charge station before any other queued calls.
+var DefaultCallPriorities = map[string]int{
+	"RemoteStartTransaction":  20,
+	"RemoteStopTransaction":   20,
+	"RequestStartTransaction": 20,
+	"RequestStopTransaction":  20,
+	"UnlockConnector":         20,
+	"ChangeAvailability":      10,
+	"Reset":                   10,
+	"SetChargingProfile":      10,
+	"ClearChargingProfile":    10,
+}
+
+// CallQueue is a transport.Emitter that stores the calls made by the CSMS in a
+// queue for each charge station rather than emitting them straight away. The
+// calls for a charge station are delivered one at a time, in priority order,
+// whilst the charge station is connected: the next call is sent when the
+// charge station responds to the previous one. A call that has no response
+// within the ResponseTimeout is sent again, until it has been sent MaxAttempts
+// times. Calls that have not been answered by their expiry time are discarded.
+//
+// Messages that are not calls (i.e. the responses to calls made by the charge
+// station) are emitted immediately.
+//
+// The one call at a time delivery is coordinated within a manager instance:
+// the store does not lock a queue against other manager instances.
+type CallQueue struct {
+	Emitter         transport.Emitter // used to deliver the calls to the charge station
+	Store           store.CallQueueStore
+	ConnectionStore store.ChargeStationConnectionStore
+	Clock           clock.PassiveClock
+	Priorities      map[string]int // the priority of each action, unlisted actions have priority 0
+	Expiry          time.Duration  // how long a call is kept in the queue (unless the context has an earlier deadline)
+	ResponseTimeout time.Duration  // how long to wait for a response before sending the call again
+	MaxAttempts     int            // the number of times a call is sent before it is discarded
+	PendingCalls    *PendingCalls  // used to deliver the response to a queued call to the callers of the same call (optional)
+
+	mu     sync.Mutex
+	locks  map[string]*queueLock
+	timers map[string]*time.Timer
+}
+
+// queueLock serializes the operations on a charge station's queue. It is
+// removed from the CallQueue once the queue is empty and nothing is waiting
+// for it.
+type queueLock struct {
+	sync.Mutex
+	refs  int  // the number of holders and waiters, guarded by CallQueue.mu
+	empty bool // set whilst held if the queue has been found to be empty
+}
+
+func NewCallQueue(emitter transport.Emitter, engine store.Engine, clock clock.PassiveClock) *CallQueue {
+	return &CallQueue{
+		Emitter:         emitter,
+		Store:           engine,
+		ConnectionStore: engine,
+		Clock:           clock,
+		Priorities:      DefaultCallPriorities,
+		Expiry:          24 * time.Hour,
+		ResponseTimeout: time.Minute,
+		MaxAttempts:     3,
+	}
+}
+
+func (q *CallQueue) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	if message.MessageType != transport.MessageTypeCall {
+		return q.Emitter.Emit(ctx, ocppVersion, chargeStationId, message)
+	}
+
+	now := q.Clock.Now()
+	call := &store.QueuedCall{
+		ChargeStationId: chargeStationId,
+		MessageId:       message.MessageId,
+		OcppVersion:     string(ocppVersion),
+		Action:          message.Action,
+		RequestPayload:  string(message.RequestPayload),
+		Priority:        q.Priorities[message.Action],
+		State:           store.QueuedCallStatePending,
+		QueuedAt:        now,
+		ExpiresAt:       now.Add(q.Expiry),
+	}
+	// there is no point delivering a call once the caller has stopped waiting for it
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(call.ExpiresAt) {
+		call.ExpiresAt = deadline
+	}
+
+	unlock := q.lock(chargeStationId)
+	defer unlock()
+
+	calls, err := q.Store.ListQueuedCalls(ctx, chargeStationId)
+	if err != nil {
+		return fmt.Errorf("queueing %s call: %w", message.Action, err)
+	}
+	// a call that is identical to one still waiting to be sent is not queued
+	// so that calls that are repeated whilst the charge station is offline are
+	// only delivered once: the queued call keeps its message id and the caller
+	// of the repeated call is given the response to the queued call
+	for _, queued := range calls {
+		if queued.State == store.QueuedCallStatePending && queued.OcppVersion == call.OcppVersion &&
+			queued.Action == call.Action && queued.RequestPayload == call.RequestPayload {
+			if call.ExpiresAt.After(queued.ExpiresAt) {
+				queued.ExpiresAt = call.ExpiresAt
+				err = q.Store.SetQueuedCall(ctx, queued)
+				if err != nil {
+					return fmt.Errorf("queueing %s call: %w", message.Action, err)
+				}
+			}
+			if q.PendingCalls != nil {
+				q.PendingCalls.addDuplicate(chargeStationId, call.MessageId, queued.MessageId)
+			}
+			slog.Info("call already queued", "action", call.Action, "chargeStationId", chargeStationId,
+				"messageId", call.MessageId, "queuedMessageId", queued.MessageId)
+
+			q.dispatch(ctx, chargeStationId)
+			return nil
+		}
+	}
+
+	err = q.Store.SetQueuedCall(ctx, call)
+	if err != nil {
+		return fmt.Errorf("queueing %s call: %w", message.Action, err)
+	}
+	slog.Info("queued call", "action", call.Action, "chargeStationId", chargeStationId, "messageId", call.MessageId)
+
+	q.dispatch(ctx, chargeStationId)
+	return nil
+}
+
+// Dispatch sends the next call in the charge station's queue, if the charge
+// station is connected and is not already processing a call.
+func (q *CallQueue) Dispatch(ctx context.Context, chargeStationId string) {
+	unlock := q.lock(chargeStationId)
+	defer unlock()
+	q.dispatch(ctx, chargeStationId)
+}
+
+// Replay resends the call that was in flight to the charge station, and
+// then the rest of the queue. It is used when the charge station has
+// reconnected or rebooted and so will not respond to the call it was sent.
+func (q *CallQueue) Replay(ctx context.Context, chargeStationId string) {
+	unlock := q.lock(chargeStationId)
+	defer unlock()
+
+	calls, err := q.Store.ListQueuedCalls(ctx, chargeStationId)
+	if err != nil {
+		slog.Error("unable to list queued calls", "chargeStationId", chargeStationId, "err", err)
+		return
+	}
+	for _, call := range calls {
+		if call.State == store.QueuedCallStateInFlight {
+			call.State = store.QueuedCallStatePending
+			err = q.Store.SetQueuedCall(ctx, call)
+			if err != nil {
+				slog.Error("unable to update queued call", "chargeStationId", chargeStationId, "messageId", call.MessageId, "err", err)
+				return
+			}
+		}
+	}
+
+	q.dispatch(ctx, chargeStationId)
+}
+
+// Complete removes the call that the charge station has responded to from the
+// queue and sends the next call.
+func (q *CallQueue) Complete(ctx context.Context, chargeStationId, messageId string) {
+	unlock := q.lock(chargeStationId)
+	defer unlock()
+
+	call, err := q.Store.LookupQueuedCall(ctx, chargeStationId, messageId)
+	if err != nil {
+		slog.Error("unable to lookup queued call", "chargeStationId", chargeStationId, "messageId", messageId, "err", err)
+		return
+	}
+	if call == nil {
+		return
+	}
+	err = q.Store.DeleteQueuedCall(ctx, chargeStationId, messageId)
+	if err != nil {
+		slog.Error("unable to delete queued call", "chargeStationId", chargeStationId, "messageId", messageId, "err", err)
+		return
+	}
+
+	q.dispatch(ctx, chargeStationId)
+}
+
+// Cancel removes a call from the charge station's queue. A call that has
+// already been sent may still be processed by the charge station.
+func (q *CallQueue) Cancel(ctx context.Context, chargeStationId, messageId string) error {
+	unlock := q.lock(chargeStationId)
+	defer unlock()
+
+	err := q.Store.DeleteQueuedCall(ctx, chargeStationId, messageId)
+	if err != nil {
+		return err
+	}
+
+	q.dispatch(ctx, chargeStationId)
+	return nil
+}
+
+// dispatch must be called with the charge station's lock held
+func (q *CallQueue) dispatch(ctx context.Context, chargeStationId string) {
+	calls, err := q.Store.ListQueuedCalls(ctx, chargeStationId)
+	if err != nil {
+		slog.Error("unable to list queued calls", "chargeStationId", chargeStationId, "err", err)
+		return
+	}
+
+	now := q.Clock.Now()
+	var next *store.QueuedCall
+	for _, call := range calls {
+		if !now.Before(call.ExpiresAt) {
+			slog.Warn("discarding expired call", "action", call.Action, "chargeStationId", chargeStationId,
+				"messageId", call.MessageId, "attempts", call.Attempts)
+			q.delete(ctx, call)
+			continue
+		}
+		if call.State == store.QueuedCallStateInFlight {
+			var waited time.Duration
+			if call.LastAttemptAt != nil {
+				waited = now.Sub(*call.LastAttemptAt)
+			}
+			if waited < q.ResponseTimeout {
+				q.schedule(chargeStationId, q.ResponseTimeout-waited)
+				return
+			}
+			if call.Attempts >= q.MaxAttempts {
+				slog.Warn("discarding call with no response", "action", call.Action, "chargeStationId", chargeStationId,
+					"messageId", call.MessageId, "attempts", call.Attempts)
+				q.delete(ctx, call)
+				continue
+			}
+			// the call has timed out: it is no longer in flight, so that a
+			// call that comes before it in the queue is not sent whilst it is
+			// in flight, and is sent again in its turn
+			call.State = store.QueuedCallStatePending
+			err = q.Store.SetQueuedCall(ctx, call)
+			if err != nil {
+				slog.Error("unable to update queued call", "chargeStationId", chargeStationId, "messageId", call.MessageId, "err", err)
+				return
+			}
+		}
+		if next == nil {
+			next = call
+		}
+	}
+	if next == nil {
+		q.forget(chargeStationId)
+		return
+	}
+
+	// calls are not sent to a charge station that the gateway has reported as
+	// disconnected: they will be sent when it reconnects
+	conn, err := q.ConnectionStore.LookupChargeStationConnection(ctx, chargeStationId)
+	if err != nil {
+		slog.Error("unable to lookup charge station connection", "chargeStationId", chargeStationId, "err", err)
+		return
+	}
+	if isDisconnected(conn) {
+		return
+	}
+
+	next.State = store.QueuedCallStateInFlight
+	next.Attempts++
+	next.LastAttemptAt = &now
+	err = q.Store.SetQueuedCall(ctx, next)
+	if err != nil {
+		slog.Error("unable to update queued call", "chargeStationId", chargeStationId, "messageId", next.MessageId, "err", err)
+		return
+	}
+	q.schedule(chargeStationId, q.ResponseTimeout)
+
+	slog.Info("sending queued call", "action", next.Action, "chargeStationId", chargeStationId,
+		"messageId", next.MessageId, "attempt", next.Attempts)
+	err = q.Emitter.Emit(ctx, transport.OcppVersion(next.OcppVersion), chargeStationId, &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         next.Action,
+		MessageId:      next.MessageId,
+		RequestPayload: []byte(next.RequestPayload),
+	})
+	if err != nil {
+		// the call will be sent again once the response timeout has passed
+		slog.Error("unable to send queued call", "action", next.Action, "chargeStationId", chargeStationId,
+			"messageId", next.MessageId, "err", err)
+	}
+}
+
+// isDisconnected returns true if the gateway has reported the charge station
+// as disconnected and no message has been received from it since
+func isDisconnected(conn *store.ChargeStationConnection) bool {
+	if conn == nil || conn.Connected || conn.DisconnectedAt == nil {
+		return false
+	}
+	return !conn.LastSeen.After(*conn.DisconnectedAt)
+}
+
+func (q *CallQueue) delete(ctx context.Context, call *store.QueuedCall) {
+	err := q.Store.DeleteQueuedCall(ctx, call.ChargeStationId, call.MessageId)
+	if err != nil {
+		slog.Error("unable to delete queued call", "chargeStationId", call.ChargeStationId, "messageId", call.MessageId, "err", err)
+	}
+	if q.PendingCalls != nil {
+		q.PendingCalls.removeDuplicates(call.ChargeStationId, call.MessageId)
+	}
+}
+
+// schedule dispatches the charge station's queue again after d, replacing any
+// previously scheduled dispatch
+func (q *CallQueue) schedule(chargeStationId string, d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.timers == nil {
+		q.timers = make(map[string]*time.Timer)
+	}
+	if timer, ok := q.timers[chargeStationId]; ok {
+		timer.Stop()
+	}
+	q.timers[chargeStationId] = time.AfterFunc(d, func() {
+		q.Dispatch(context.Background(), chargeStationId)
+	})
+}
+
+// forget stops any scheduled dispatch of the charge station's queue and marks
+// its lock for removal. It must be called with the charge station's lock held
+// once the queue is empty.
+func (q *CallQueue) forget(chargeStationId string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if timer, ok := q.timers[chargeStationId]; ok {
+		timer.Stop()
+		delete(q.timers, chargeStationId)
+	}
+	if l, ok := q.locks[chargeStationId]; ok {
+		l.empty = true
+	}
+}
+
+// lock serializes the operations on a charge station's queue
+func (q *CallQueue) lock(chargeStationId string) func() {
+	q.mu.Lock()
+	if q.locks == nil {
+		q.locks = make(map[string]*queueLock)
+	}
+	l, ok := q.locks[chargeStationId]
+	if !ok {
+		l = new(queueLock)
+		q.locks[chargeStationId] = l
+	}
+	l.refs++
+	q.mu.Unlock()
+
+	l.Lock()
+	l.empty = false
+	return func() {
+		empty := l.empty
+		l.Unlock()
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		l.refs--
+		// a lock that another operation is waiting for is kept so that the
+		// operations that follow it use the same lock
+		if l.refs == 0 && empty {
+			delete(q.locks, chargeStationId)
+		}
+	}
+}
+
+// CallQueueHandler keeps the CallQueue moving as messages are received from
+// charge stations: the queue is replayed when a charge station connects or
+// sends a BootNotification, the next call is sent when a charge station
+// responds to a call and on each Heartbeat. All messages are passed to the
+// next handler first.
+type CallQueueHandler struct {
+	Next  transport.MessageHandler
+	Queue *CallQueue
+}
+
+func (h CallQueueHandler) Handle(ctx context.Context, chargeStationId string, msg *transport.Message) {
+	h.Next.Handle(ctx, chargeStationId, msg)
+
+	switch {
+	case msg.Event != nil:
+		if msg.Event.Type == transport.ConnectionEventConnected {
+			h.Queue.Replay(ctx, chargeStationId)
+		}
+	case msg.MessageType == transport.MessageTypeCall && msg.Action == "BootNotification":
+		h.Queue.Replay(ctx, chargeStationId)
+	case msg.MessageType == transport.MessageTypeCall && msg.Action == "Heartbeat":
+		h.Queue.Dispatch(ctx, chargeStationId)
+	case msg.MessageType == transport.MessageTypeCallResult || msg.MessageType == transport.MessageTypeCallError:
+		h.Queue.Complete(ctx, chargeStationId, msg.MessageId)
+	}
+}
@@ -0,0 +1,354 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	clockTest "k8s.io/utils/clock/testing"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingEmitter struct {
+	sync.Mutex
+	messages []*transport.Message
+}
+
+func (e *recordingEmitter) Emit(_ context.Context, _ transport.OcppVersion, _ string, message *transport.Message) error {
+	e.Lock()
+	defer e.Unlock()
+	e.messages = append(e.messages, message)
+	return nil
+}
+
+func (e *recordingEmitter) messageIds() []string {
+	e.Lock()
+	defer e.Unlock()
+	var ids []string
+	for _, msg := range e.messages {
+		ids = append(ids, msg.MessageId)
+	}
+	return ids
+}
+
+func newCall(action, messageId string) *transport.Message {
+	return &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         action,
+		MessageId:      messageId,
+		RequestPayload: []byte(`{"id":"` + messageId + `"}`),
+	}
+}
+
+func setupCallQueue(t *testing.T) (*handlers.CallQueue, *recordingEmitter, store.Engine, *clockTest.FakeClock) {
+	clock := clockTest.NewFakeClock(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+	engine := inmemory.NewStore(clock)
+	emitter := &recordingEmitter{}
+	queue := handlers.NewCallQueue(emitter, engine, clock)
+	// the tests move the clock on and dispatch the queue themselves, rather
+	// than waiting for the response timeout to pass
+	queue.ResponseTimeout = time.Hour
+	return queue, emitter, engine, clock
+}
+
+func TestCallQueueDeliversOneCallAtATimeInPriorityOrder(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, _ := setupCallQueue(t)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("TriggerMessage", "2")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("Reset", "3")))
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, queued, 3)
+	// the Reset has a higher priority than the call in flight
+	assert.Equal(t, "3", queued[0].MessageId)
+	assert.Equal(t, store.QueuedCallStatePending, queued[0].State)
+	assert.Equal(t, "1", queued[1].MessageId)
+	assert.Equal(t, store.QueuedCallStateInFlight, queued[1].State)
+	assert.Equal(t, 1, queued[1].Attempts)
+
+	queue.Complete(ctx, "cs001", "1")
+	assert.Equal(t, []string{"1", "3"}, emitter.messageIds())
+
+	queue.Complete(ctx, "cs001", "3")
+	assert.Equal(t, []string{"1", "3", "2"}, emitter.messageIds())
+
+	queue.Complete(ctx, "cs001", "2")
+	queued, err = engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+}
+
+func TestCallQueueEmitsResponsesImmediately(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, _ := setupCallQueue(t)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:     transport.MessageTypeCallResult,
+		Action:          "Heartbeat",
+		MessageId:       "2",
+		ResponsePayload: []byte(`{}`),
+	}))
+	assert.Equal(t, []string{"1", "2"}, emitter.messageIds())
+
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Len(t, queued, 1)
+}
+
+func TestCallQueueHoldsCallsForDisconnectedChargeStation(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, clock := setupCallQueue(t)
+
+	disconnectedAt := clock.Now()
+	err := engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+		ConnectionId:   "conn-1",
+		DisconnectedAt: &disconnectedAt,
+		LastSeen:       disconnectedAt,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion201, "cs001", newCall("GetVariables", "1")))
+	assert.Empty(t, emitter.messageIds())
+
+	clock.Step(time.Minute)
+	err = engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+		Connected:    true,
+		ConnectionId: "conn-2",
+		ConnectedAt:  clock.Now(),
+		LastSeen:     clock.Now(),
+	})
+	require.NoError(t, err)
+	queue.Replay(ctx, "cs001")
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+}
+
+func TestCallQueueReplaysInFlightCall(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, _ := setupCallQueue(t)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("TriggerMessage", "2")))
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+
+	queue.Replay(ctx, "cs001")
+	assert.Equal(t, []string{"1", "1"}, emitter.messageIds())
+
+	call, err := engine.LookupQueuedCall(ctx, "cs001", "1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, call.Attempts)
+}
+
+func TestCallQueueRetriesCallsWithoutResponse(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, clock := setupCallQueue(t)
+	queue.MaxAttempts = 2
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("TriggerMessage", "2")))
+
+	// still waiting for the response
+	clock.Step(queue.ResponseTimeout / 2)
+	queue.Dispatch(ctx, "cs001")
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+
+	clock.Step(queue.ResponseTimeout / 2)
+	queue.Dispatch(ctx, "cs001")
+	assert.Equal(t, []string{"1", "1"}, emitter.messageIds())
+
+	// the call is discarded after the last attempt
+	clock.Step(queue.ResponseTimeout)
+	queue.Dispatch(ctx, "cs001")
+	assert.Equal(t, []string{"1", "1", "2"}, emitter.messageIds())
+
+	call, err := engine.LookupQueuedCall(ctx, "cs001", "1")
+	require.NoError(t, err)
+	assert.Nil(t, call)
+}
+
+func TestCallQueueDiscardsExpiredCalls(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, clock := setupCallQueue(t)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+
+	waitCtx, cancel := context.WithDeadline(ctx, clock.Now().Add(time.Minute))
+	defer cancel()
+	require.NoError(t, queue.Emit(waitCtx, transport.OcppVersion16, "cs001", newCall("Reset", "2")))
+
+	call, err := engine.LookupQueuedCall(ctx, "cs001", "2")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Minute), call.ExpiresAt)
+
+	clock.Step(2 * time.Minute)
+	queue.Complete(ctx, "cs001", "1")
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+}
+
+func TestCallQueueDoesNotQueueIdenticalPendingCall(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, clock := setupCallQueue(t)
+	queue.PendingCalls = handlers.NewPendingCalls()
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	queuedAt := clock.Now()
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "2",
+		RequestPayload: []byte(`{"requestedMessage":"StatusNotification"}`),
+	}))
+	clock.Step(time.Minute)
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "3",
+		RequestPayload: []byte(`{"requestedMessage":"StatusNotification"}`),
+	}))
+
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	assert.Equal(t, "2", queued[1].MessageId)
+	assert.Equal(t, queuedAt, queued[1].QueuedAt)
+	assert.Equal(t, clock.Now().Add(queue.Expiry), queued[1].ExpiresAt)
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+}
+
+func TestCallQueueDeliversResponseToCallersOfIdenticalCall(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, _, _ := setupCallQueue(t)
+	queue.PendingCalls = handlers.NewPendingCalls()
+	callMaker := handlers.OcppCallMaker{
+		Emitter:     queue,
+		OcppVersion: transport.OcppVersion16,
+		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp16.TriggerMessageJson{}): "TriggerMessage",
+		},
+		PendingCalls: queue.PendingCalls,
+	}
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			var response ocpp16.TriggerMessageResponseJson
+			err := callMaker.Call(ctx, "cs001", &ocpp16.TriggerMessageJson{
+				RequestedMessage: ocpp16.TriggerMessageJsonRequestedMessageStatusNotification,
+			}, &response)
+			if err == nil && response.Status != ocpp16.TriggerMessageResponseJsonStatusAccepted {
+				err = fmt.Errorf("unexpected status: %s", response.Status)
+			}
+			results <- err
+		}()
+	}
+	require.Eventually(t, func() bool {
+		queued, err := queue.Store.ListQueuedCalls(ctx, "cs001")
+		return err == nil && len(queued) == 2
+	}, time.Second, time.Millisecond)
+
+	queue.Complete(ctx, "cs001", "1")
+	messageIds := emitter.messageIds()
+	require.Len(t, messageIds, 2)
+
+	response := &transport.Message{
+		MessageType:     transport.MessageTypeCallResult,
+		MessageId:       messageIds[1],
+		ResponsePayload: []byte(`{"status":"Accepted"}`),
+	}
+	require.True(t, queue.PendingCalls.Resolve("cs001", response))
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for call response")
+		}
+	}
+}
+
+func TestCallQueueDoesNotSendNextCallWhilstTimedOutCallIsInFlight(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, clock := setupCallQueue(t)
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("Reset", "2")))
+	assert.Equal(t, []string{"1"}, emitter.messageIds())
+
+	// the Reset has a higher priority so is sent once the call in flight has
+	// timed out: the timed out call is no longer in flight
+	clock.Step(queue.ResponseTimeout)
+	queue.Dispatch(ctx, "cs001")
+	assert.Equal(t, []string{"1", "2"}, emitter.messageIds())
+
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	assert.Equal(t, "2", queued[0].MessageId)
+	assert.Equal(t, store.QueuedCallStateInFlight, queued[0].State)
+	assert.Equal(t, "1", queued[1].MessageId)
+	assert.Equal(t, store.QueuedCallStatePending, queued[1].State)
+	assert.Equal(t, 1, queued[1].Attempts)
+
+	queue.Complete(ctx, "cs001", "2")
+	assert.Equal(t, []string{"1", "2", "1"}, emitter.messageIds())
+}
+
+func TestCallQueueHandler(t *testing.T) {
+	ctx := context.Background()
+	queue, emitter, engine, _ := setupCallQueue(t)
+	next := &recordingHandler{}
+	handler := handlers.CallQueueHandler{Next: next, Queue: queue}
+
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("GetConfiguration", "1")))
+	require.NoError(t, queue.Emit(ctx, transport.OcppVersion16, "cs001", newCall("TriggerMessage", "2")))
+
+	// the charge station reboots
+	handler.Handle(ctx, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "BootNotification",
+		MessageId:      "boot",
+		RequestPayload: []byte(`{}`),
+	})
+	assert.Equal(t, []string{"1", "1"}, emitter.messageIds())
+
+	handler.Handle(ctx, "cs001", &transport.Message{
+		MessageType:     transport.MessageTypeCallResult,
+		Action:          "GetConfiguration",
+		MessageId:       "1",
+		ResponsePayload: []byte(`{}`),
+	})
+	assert.Equal(t, []string{"1", "1", "2"}, emitter.messageIds())
+
+	handler.Handle(ctx, "cs001", &transport.Message{
+		MessageType:      transport.MessageTypeCallError,
+		Action:           "TriggerMessage",
+		MessageId:        "2",
+		ErrorCode:        transport.ErrorNotImplemented,
+		ErrorDescription: "not implemented",
+	})
+	queued, err := engine.ListQueuedCalls(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+
+	assert.Len(t, next.messages, 3)
+}
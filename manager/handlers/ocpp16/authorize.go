@@ -9,11 +9,12 @@ import (
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
-	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 )
 
 type AuthorizeHandler struct {
-	TokenStore store.TokenStore
+	TokenAuthService services.TokenAuthService
 }
 
 func (a AuthorizeHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -21,14 +22,8 @@ func (a AuthorizeHandler) HandleCall(ctx context.Context, chargeStationId string
 
 	req := request.(*types.AuthorizeJson)
 
-	status := types.AuthorizeResponseJsonIdTagInfoStatusInvalid
-	tok, err := a.TokenStore.LookupToken(ctx, req.IdTag)
-	if err != nil {
-		return nil, err
-	}
-	if tok != nil && tok.Valid {
-		status = types.AuthorizeResponseJsonIdTagInfoStatusAccepted
-	}
+	idTokenInfo := a.TokenAuthService.Authorize(ctx, chargeStationId, idTagToken(req.IdTag))
+	status := types.AuthorizeResponseJsonIdTagInfoStatus(idTagStatus(idTokenInfo.Status))
 
 	span.SetAttributes(
 		attribute.String("request.status", string(status)),
@@ -40,3 +35,29 @@ func (a AuthorizeHandler) HandleCall(ctx context.Context, chargeStationId string
 		},
 	}, nil
 }
+
+// idTagToken returns the OCPP 2.0.1 token for an id tag: OCPP 1.6 does not
+// have token types and id tags are usually RFID cards
+func idTagToken(idTag string) ocpp201.IdTokenType {
+	return ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeISO14443,
+		IdToken: idTag,
+	}
+}
+
+// idTagStatus returns the OCPP 1.6 id tag status for an OCPP 2.0.1
+// authorization status
+func idTagStatus(status ocpp201.AuthorizationStatusEnumType) string {
+	switch status {
+	case ocpp201.AuthorizationStatusEnumTypeAccepted:
+		return "Accepted"
+	case ocpp201.AuthorizationStatusEnumTypeBlocked:
+		return "Blocked"
+	case ocpp201.AuthorizationStatusEnumTypeExpired:
+		return "Expired"
+	case ocpp201.AuthorizationStatusEnumTypeConcurrentTx:
+		return "ConcurrentTx"
+	default:
+		return "Invalid"
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
 	"k8s.io/utils/clock"
@@ -31,7 +32,10 @@ func TestAuthorizeKnownRfidCard(t *testing.T) {
 	require.NoError(t, err)
 
 	ah := handlers.AuthorizeHandler{
-		TokenStore: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 	}
 
 	req := &types.AuthorizeJson{
@@ -54,7 +58,10 @@ func TestAuthorizeWithUnknownRfidCard(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
 
 	ah := handlers.AuthorizeHandler{
-		TokenStore: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 	}
 
 	req := &types.AuthorizeJson{
@@ -89,7 +96,10 @@ func TestAuthorizeWithInvalidToken(t *testing.T) {
 	require.NoError(t, err)
 
 	ah := handlers.AuthorizeHandler{
-		TokenStore: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 	}
 
 	req := &types.AuthorizeJson{
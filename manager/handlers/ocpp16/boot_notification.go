@@ -15,10 +15,11 @@ import (
 )
 
 type BootNotificationHandler struct {
-	Clock               clock.PassiveClock
-	RuntimeDetailsStore store.ChargeStationRuntimeDetailsStore
-	SettingsStore       store.ChargeStationSettingsStore
-	HeartbeatInterval   int
+	Clock                clock.PassiveClock
+	RuntimeDetailsStore  store.ChargeStationRuntimeDetailsStore
+	SettingsStore        store.ChargeStationSettingsStore
+	ChargingProfileStore store.ChargeStationChargingProfilesStore
+	HeartbeatInterval    int
 }
 
 func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -68,9 +69,52 @@ func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId
 		}
 	}
 
+	err = b.reapplyChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.BootNotificationResponseJson{
 		CurrentTime: b.Clock.Now().Format(time.RFC3339),
 		Interval:    b.HeartbeatInterval,
 		Status:      types.BootNotificationResponseJsonStatusAccepted,
 	}, nil
 }
+
+// reapplyChargingProfiles ensures that the charging profiles are reinstalled after
+// the charge station reboots: a charge station is not required to persist charging
+// profiles. TxProfiles are removed as they only apply to transactions that will
+// not survive the reboot.
+func (b BootNotificationHandler) reapplyChargingProfiles(ctx context.Context, chargeStationId string) error {
+	profiles, err := b.ChargingProfileStore.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+	if profiles == nil {
+		return nil
+	}
+
+	var updated []*store.ChargingProfile
+	for _, profile := range profiles.ChargingProfiles {
+		if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile {
+			err = b.ChargingProfileStore.DeleteChargeStationChargingProfile(ctx, chargeStationId, profile.ChargingProfileId)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if profile.Status == store.ChargingProfileStatusAccepted {
+			profile.Status = store.ChargingProfileStatusPending
+			profile.SendAfter = time.Time{}
+			updated = append(updated, profile)
+		}
+	}
+
+	if len(updated) > 0 {
+		return b.ChargingProfileStore.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: updated,
+		})
+	}
+
+	return nil
+}
@@ -31,10 +31,11 @@ func TestBootNotificationHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	handler := handlers.BootNotificationHandler{
-		Clock:               clockTest.NewFakePassiveClock(now),
-		RuntimeDetailsStore: engine,
-		SettingsStore:       engine,
-		HeartbeatInterval:   10,
+		Clock:                clockTest.NewFakePassiveClock(now),
+		RuntimeDetailsStore:  engine,
+		SettingsStore:        engine,
+		ChargingProfileStore: engine,
+		HeartbeatInterval:    10,
 	}
 
 	serialNumber := "cs001-1234"
@@ -65,3 +66,61 @@ func TestBootNotificationHandler(t *testing.T) {
 		assert.NotEqual(t, store.ChargeStationSettingStatusRebootRequired, v.Status)
 	}
 }
+
+func TestBootNotificationHandlerReappliesChargingProfiles(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2023-06-15T15:05:00+01:00")
+	require.NoError(t, err)
+
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err = engine.UpdateChargeStationChargingProfiles(context.Background(), "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusAccepted,
+				SendAfter:              now,
+			},
+			{
+				ChargingProfileId:      2,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusRejected,
+				SendAfter:              now,
+			},
+			{
+				ChargingProfileId:      3,
+				ConnectorId:            1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusAccepted,
+				SendAfter:              now,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := handlers.BootNotificationHandler{
+		Clock:                clockTest.NewFakePassiveClock(now),
+		RuntimeDetailsStore:  engine,
+		SettingsStore:        engine,
+		ChargingProfileStore: engine,
+		HeartbeatInterval:    10,
+	}
+
+	_, err = handler.HandleCall(context.Background(), "cs001", &types.BootNotificationJson{})
+	require.NoError(t, err)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, profiles)
+	require.Len(t, profiles.ChargingProfiles, 2)
+
+	assert.Equal(t, 1, profiles.ChargingProfiles[0].ChargingProfileId)
+	assert.Equal(t, store.ChargingProfileStatusPending, profiles.ChargingProfiles[0].Status)
+	assert.True(t, profiles.ChargingProfiles[0].SendAfter.IsZero())
+
+	assert.Equal(t, 2, profiles.ChargingProfiles[1].ChargingProfileId)
+	assert.Equal(t, store.ChargingProfileStatusRejected, profiles.ChargingProfiles[1].Status)
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ClearChargingProfileResultHandler struct {
+	Store     store.ChargeStationChargingProfilesStore
+	CallMaker handlers.CallMaker
+}
+
+func (h ClearChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.ClearChargingProfileJson)
+	resp := response.(*types.ClearChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("clear_charging_profile.status", string(resp.Status)))
+	if req.Id != nil {
+		span.SetAttributes(attribute.Int("clear_charging_profile.charging_profile_id", *req.Id))
+	}
+
+	connectorId := 0
+	if req.ConnectorId != nil {
+		connectorId = *req.ConnectorId
+	}
+
+	if req.Id != nil {
+		profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+		if err != nil {
+			return err
+		}
+		if profiles != nil {
+			for _, profile := range profiles.ChargingProfiles {
+				if profile.ChargingProfileId == *req.Id {
+					connectorId = profile.ConnectorId
+					break
+				}
+			}
+		}
+
+		// Unknown means the charge station doesn't have the profile: either way
+		// it is no longer installed so it can be removed
+		err = h.Store.DeleteChargeStationChargingProfile(ctx, chargeStationId, *req.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resp.Status == types.ClearChargingProfileResponseJsonStatusAccepted {
+		return h.CallMaker.Send(ctx, chargeStationId, &types.GetCompositeScheduleJson{
+			ConnectorId: connectorId,
+			Duration:    compositeScheduleDuration,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestClearChargingProfileResultHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        types.ClearChargingProfileResponseJsonStatus
+		expectRefresh bool
+	}{
+		{
+			name:          "Accepted",
+			status:        types.ClearChargingProfileResponseJsonStatusAccepted,
+			expectRefresh: true,
+		},
+		{
+			name:   "Unknown",
+			status: types.ClearChargingProfileResponseJsonStatusUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			engine := inmemory.NewStore(clock.RealClock{})
+
+			err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+				ChargingProfiles: []*store.ChargingProfile{
+					{
+						ChargingProfileId:      3,
+						ConnectorId:            2,
+						ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+						ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+						Status:                 store.ChargingProfileStatusClearPending,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			callMaker := &mockCallMaker{}
+			handler := handlers.ClearChargingProfileResultHandler{
+				Store:     engine,
+				CallMaker: callMaker,
+			}
+
+			tracer, exporter := testutil.GetTracer()
+
+			func() {
+				ctx, span := tracer.Start(ctx, "test")
+				defer span.End()
+
+				id := 3
+				req := &types.ClearChargingProfileJson{
+					Id: &id,
+				}
+				resp := &types.ClearChargingProfileResponseJson{
+					Status: tc.status,
+				}
+
+				err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+				require.NoError(t, err)
+			}()
+
+			testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+				"clear_charging_profile.charging_profile_id": 3,
+				"clear_charging_profile.status":              string(tc.status),
+			})
+
+			profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+			require.NoError(t, err)
+			assert.Nil(t, profiles)
+
+			if tc.expectRefresh {
+				require.Len(t, callMaker.calls, 1)
+				assert.Equal(t, &types.GetCompositeScheduleJson{
+					ConnectorId: 2,
+					Duration:    86400,
+				}, callMaker.calls[0].request)
+			} else {
+				assert.Len(t, callMaker.calls, 0)
+			}
+		})
+	}
+}
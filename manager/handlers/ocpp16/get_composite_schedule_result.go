@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+type GetCompositeScheduleResultHandler struct {
+	Store store.ChargeStationChargingProfilesStore
+	Clock clock.PassiveClock
+}
+
+func (h GetCompositeScheduleResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.GetCompositeScheduleJson)
+	resp := response.(*types.GetCompositeScheduleResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("get_composite_schedule.connector_id", req.ConnectorId),
+		attribute.String("get_composite_schedule.status", string(resp.Status)))
+
+	if resp.Status != types.GetCompositeScheduleResponseJsonStatusAccepted {
+		return nil
+	}
+
+	connectorId := req.ConnectorId
+	if resp.ConnectorId != nil {
+		connectorId = *resp.ConnectorId
+	}
+
+	scheduleStart, err := parseOptionalTime(resp.ScheduleStart)
+	if err != nil {
+		return fmt.Errorf("parsing schedule start: %w", err)
+	}
+
+	var chargingSchedule *store.ChargingSchedule
+	if resp.ChargingSchedule != nil {
+		startSchedule, err := parseOptionalTime(resp.ChargingSchedule.StartSchedule)
+		if err != nil {
+			return fmt.Errorf("parsing charging schedule start: %w", err)
+		}
+		var periods []store.ChargingSchedulePeriod
+		for _, period := range resp.ChargingSchedule.ChargingSchedulePeriod {
+			periods = append(periods, store.ChargingSchedulePeriod{
+				StartPeriod:  period.StartPeriod,
+				Limit:        period.Limit,
+				NumberPhases: period.NumberPhases,
+			})
+		}
+		chargingSchedule = &store.ChargingSchedule{
+			Duration:                resp.ChargingSchedule.Duration,
+			StartSchedule:           startSchedule,
+			ChargingRateUnit:        store.ChargingRateUnit(resp.ChargingSchedule.ChargingRateUnit),
+			ChargingSchedulePeriods: periods,
+			MinChargingRate:         resp.ChargingSchedule.MinChargingRate,
+		}
+	}
+
+	return h.Store.SetChargeStationCompositeSchedule(ctx, chargeStationId, &store.CompositeSchedule{
+		ConnectorId:      connectorId,
+		ScheduleStart:    scheduleStart,
+		ChargingSchedule: chargingSchedule,
+		RetrievedAt:      h.Clock.Now(),
+	})
+}
+
+func parseOptionalTime(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestGetCompositeScheduleResultHandler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.GetCompositeScheduleResultHandler{
+		Store: engine,
+		Clock: clockTest.NewFakePassiveClock(now),
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		connectorId := 1
+		scheduleStart := "2023-06-15T15:00:00Z"
+		duration := 3600
+		phases := 3
+		req := &types.GetCompositeScheduleJson{
+			ConnectorId: 1,
+			Duration:    86400,
+		}
+		resp := &types.GetCompositeScheduleResponseJson{
+			ConnectorId:   &connectorId,
+			ScheduleStart: &scheduleStart,
+			ChargingSchedule: &types.GetCompositeScheduleResponseJsonChargingSchedule{
+				ChargingRateUnit: types.GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnitA,
+				ChargingSchedulePeriod: []types.GetCompositeScheduleResponseJsonChargingScheduleChargingSchedulePeriodElem{
+					{StartPeriod: 0, Limit: 16, NumberPhases: &phases},
+					{StartPeriod: 1800, Limit: 8},
+				},
+				Duration: &duration,
+			},
+			Status: types.GetCompositeScheduleResponseJsonStatusAccepted,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"get_composite_schedule.connector_id": 1,
+		"get_composite_schedule.status":       "Accepted",
+	})
+
+	got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+	require.NoError(t, err)
+
+	scheduleStart := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	duration := 3600
+	phases := 3
+	want := &store.CompositeSchedule{
+		ConnectorId:   1,
+		ScheduleStart: &scheduleStart,
+		ChargingSchedule: &store.ChargingSchedule{
+			Duration:         &duration,
+			ChargingRateUnit: store.ChargingRateUnitA,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 16, NumberPhases: &phases},
+				{StartPeriod: 1800, Limit: 8},
+			},
+		},
+		RetrievedAt: now,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestGetCompositeScheduleResultHandlerWhenRejected(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.GetCompositeScheduleResultHandler{
+		Store: engine,
+		Clock: clock.RealClock{},
+	}
+
+	req := &types.GetCompositeScheduleJson{
+		ConnectorId: 1,
+		Duration:    86400,
+	}
+	resp := &types.GetCompositeScheduleResponseJson{
+		Status: types.GetCompositeScheduleResponseJsonStatusRejected,
+	}
+
+	err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -4,18 +4,143 @@ package ocpp16
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
 )
 
 type MeterValuesHandler struct {
-	TransactionStore store.TransactionStore
+	Clock             clock.PassiveClock
+	TransactionStore  store.TransactionStore
+	MeterReadingStore store.ChargeStationMeterReadingStore
+	LoadBalancer      services.LoadBalancer
+	// SignedMeterValueService verifies signed meter values (optional)
+	SignedMeterValueService services.SignedMeterValueService
+	// SessionPublisher informs roaming partners of the transaction (optional)
+	SessionPublisher services.SessionPublisher
 }
 
 func (m MeterValuesHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (response ocpp.Response, err error) {
-	// TODO: store in transaction store
+	span := trace.SpanFromContext(ctx)
+
+	req := request.(*types.MeterValuesJson)
+
+	span.SetAttributes(attribute.Int("meter_values.connector_id", req.ConnectorId))
+	if req.TransactionId != nil {
+		span.SetAttributes(attribute.Int("meter_values.transaction_id", *req.TransactionId))
+	}
+
+	meterValues, err := convertMeterValuesElems(req.MeterValue)
+	if err != nil {
+		return nil, err
+	}
+	if m.SignedMeterValueService != nil {
+		err = m.SignedMeterValueService.VerifyMeterValues(ctx, chargeStationId, meterValues)
+		if err != nil {
+			return nil, fmt.Errorf("verifying signed meter values: %w", err)
+		}
+	}
+
+	if len(meterValues) > 0 {
+		// readings for the main meter (connector 0) or taken outside a transaction
+		// are kept separately from the transactions
+		if req.TransactionId != nil && req.ConnectorId != 0 {
+			transactionId := ConvertToUUID(*req.TransactionId)
+			err = m.TransactionStore.UpdateTransaction(ctx, chargeStationId, transactionId, meterValues)
+			if err != nil {
+				return nil, fmt.Errorf("updating transaction: %w", err)
+			}
+			if m.SessionPublisher != nil {
+				publishSession(ctx, m.TransactionStore, chargeStationId, transactionId, m.SessionPublisher.PushSessionUpdate)
+			}
+		} else {
+			err = m.MeterReadingStore.AddChargeStationMeterReadings(ctx, chargeStationId, m.toMeterReadings(req.ConnectorId, meterValues))
+			if err != nil {
+				return nil, fmt.Errorf("adding meter readings: %w", err)
+			}
+		}
+	}
+
+	if m.LoadBalancer != nil {
+		err = m.LoadBalancer.Rebalance(ctx, chargeStationId)
+		if err != nil {
+			slog.Error("load balancing meter values", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId))
+		}
+	}
 
 	return &types.MeterValuesResponseJson{}, nil
 }
+
+func (m MeterValuesHandler) toMeterReadings(connectorId int, meterValues []store.MeterValue) []*store.MeterReading {
+	var readings []*store.MeterReading
+	for _, meterValue := range meterValues {
+		timestamp, err := time.Parse(time.RFC3339, meterValue.Timestamp)
+		if err != nil {
+			timestamp = m.Clock.Now()
+		}
+		readings = append(readings, &store.MeterReading{
+			ConnectorId:   connectorId,
+			Timestamp:     timestamp.UTC(),
+			SampledValues: meterValue.SampledValues,
+		})
+	}
+	return readings
+}
+
+func convertMeterValuesElems(meterValues []types.MeterValuesJsonMeterValueElem) ([]store.MeterValue, error) {
+	var converted []store.MeterValue
+	for _, meterValue := range meterValues {
+		var sampledValues []store.SampledValue
+		for _, sampledValue := range meterValue.SampledValue {
+			convertedSampledValue, err := convertMeterValuesSampledValue(sampledValue)
+			if err != nil {
+				return nil, err
+			}
+			sampledValues = append(sampledValues, convertedSampledValue)
+		}
+		converted = append(converted, store.MeterValue{
+			SampledValues: mergeSignedDataOnly(sampledValues),
+			Timestamp:     meterValue.Timestamp,
+		})
+	}
+	return converted, nil
+}
+
+func convertMeterValuesSampledValue(sampledValue types.MeterValuesJsonMeterValueElemSampledValueElem) (store.SampledValue, error) {
+	var unitOfMeasure *store.UnitOfMeasure
+	if sampledValue.Unit != nil {
+		unitOfMeasure = &store.UnitOfMeasure{
+			Unit:      string(*sampledValue.Unit),
+			Multipler: 0,
+		}
+	}
+
+	converted := store.SampledValue{
+		Context:       (*string)(sampledValue.Context),
+		Location:      (*string)(sampledValue.Location),
+		Measurand:     (*string)(sampledValue.Measurand),
+		Phase:         (*string)(sampledValue.Phase),
+		UnitOfMeasure: unitOfMeasure,
+	}
+	if sampledValue.Format != nil && *sampledValue.Format == types.MeterValuesJsonMeterValueElemSampledValueElemFormatSignedData {
+		convertSignedSampledValue(&converted, sampledValue.Value)
+		return converted, nil
+	}
+
+	value, err := strconv.ParseFloat(sampledValue.Value, 64)
+	if err != nil {
+		return store.SampledValue{}, err
+	}
+	converted.Value = value
+	return converted, nil
+}
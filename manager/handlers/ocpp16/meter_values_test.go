@@ -1,3 +1,323 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package ocpp16_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+func newMeterValuesRequest(connectorId int, transactionId *int) *types.MeterValuesJson {
+	periodic := types.MeterValuesJsonMeterValueElemSampledValueElemContextSamplePeriodic
+	measurand := types.MeterValuesJsonMeterValueElemSampledValueElemMeasurandEnergyActiveImportRegister
+	unit := types.MeterValuesJsonMeterValueElemSampledValueElemUnitWh
+	return &types.MeterValuesJson{
+		ConnectorId:   connectorId,
+		TransactionId: transactionId,
+		MeterValue: []types.MeterValuesJsonMeterValueElem{
+			{
+				Timestamp: "2023-06-15T15:05:00+01:00",
+				SampledValue: []types.MeterValuesJsonMeterValueElemSampledValueElem{
+					{
+						Context:   &periodic,
+						Measurand: &measurand,
+						Unit:      &unit,
+						Value:     "1234.5",
+					},
+				},
+			},
+		},
+	}
+}
+
+func expectedSampledValues() []store.SampledValue {
+	periodic := "Sample.Periodic"
+	measurand := "Energy.Active.Import.Register"
+	return []store.SampledValue{
+		{
+			Context:   &periodic,
+			Measurand: &measurand,
+			UnitOfMeasure: &store.UnitOfMeasure{
+				Unit: "Wh",
+			},
+			Value: 1234.5,
+		},
+	}
+}
+
+func TestMeterValuesHandlerAddsMeterValuesToTransaction(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	transactionId := 42
+	err := engine.CreateTransaction(ctx, "cs001", handlers.ConvertToUUID(transactionId), 0, "ABCD", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+
+	handler := handlers.MeterValuesHandler{
+		Clock:             clock,
+		TransactionStore:  engine,
+		MeterReadingStore: engine,
+	}
+
+	got, err := handler.HandleCall(ctx, "cs001", newMeterValuesRequest(1, &transactionId))
+	require.NoError(t, err)
+	assert.Equal(t, &types.MeterValuesResponseJson{}, got)
+
+	transaction, err := engine.FindTransaction(ctx, "cs001", handlers.ConvertToUUID(transactionId))
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	assert.Equal(t, []store.MeterValue{
+		{
+			Timestamp:     "2023-06-15T15:05:00+01:00",
+			SampledValues: expectedSampledValues(),
+		},
+	}, transaction.MeterValues)
+	assert.Equal(t, 1, transaction.UpdatedSeqNoCount)
+
+	readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, readings)
+}
+
+func TestMeterValuesHandlerAddsMeterReadingsOutsideTransaction(t *testing.T) {
+	transactionId := 42
+
+	tests := map[string]struct {
+		connectorId   int
+		transactionId *int
+	}{
+		"main meter":     {connectorId: 0, transactionId: &transactionId},
+		"no transaction": {connectorId: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := clockTest.NewFakePassiveClock(time.Now())
+			engine := inmemory.NewStore(clock)
+
+			handler := handlers.MeterValuesHandler{
+				Clock:             clock,
+				TransactionStore:  engine,
+				MeterReadingStore: engine,
+			}
+
+			_, err := handler.HandleCall(ctx, "cs001", newMeterValuesRequest(tc.connectorId, tc.transactionId))
+			require.NoError(t, err)
+
+			readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, []*store.MeterReading{
+				{
+					ConnectorId:   tc.connectorId,
+					Timestamp:     time.Date(2023, 6, 15, 14, 5, 0, 0, time.UTC),
+					SampledValues: expectedSampledValues(),
+				},
+			}, readings)
+
+			transactions, err := engine.Transactions(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, transactions)
+		})
+	}
+}
+
+func TestMeterValuesHandlerVerifiesSignedData(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{
+		EvseId:    1,
+		PublicKey: hex.EncodeToString(der),
+	})
+	require.NoError(t, err)
+
+	payload := `{"FV":"1.0","MS":"ABC123","RD":[{"TM":"2023-06-15T15:05:00,000+0100 S","TX":"B","RV":1.2345,"RI":"1-b:1.8.0","RU":"kWh","ST":"G"}]}`
+	digest := sha256.Sum256([]byte(payload))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	ocmf := fmt.Sprintf(`OCMF|%s|{"SA":"ECDSA-secp256r1-SHA256","SD":"%s"}`, payload, hex.EncodeToString(signature))
+
+	handler := handlers.MeterValuesHandler{
+		Clock:                   clock,
+		TransactionStore:        engine,
+		MeterReadingStore:       engine,
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	req := newMeterValuesRequest(1, nil)
+	signedData := types.MeterValuesJsonMeterValueElemSampledValueElemFormatSignedData
+	req.MeterValue[0].SampledValue[0].Format = &signedData
+	req.MeterValue[0].SampledValue[0].Value = ocmf
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	want := expectedSampledValues()
+	want[0].Value = 1234.5
+	want[0].SignedMeterValue = &store.SignedMeterValue{
+		SignedMeterData: ocmf,
+		EncodingMethod:  "OCMF",
+		SigningMethod:   "ECDSA-secp256r1-SHA256",
+		Status:          store.SignedMeterValueStatusValid,
+	}
+	assert.Equal(t, want, readings[0].SampledValues)
+}
+
+func TestMeterValuesHandlerDecodesEdlSignedData(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	key, err := ecdsa.GenerateKey(services.P192(), rand.Reader)
+	require.NoError(t, err)
+	publicKey := elliptic.Marshal(key.Curve, key.X, key.Y)
+	err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{
+		EvseId:    1,
+		PublicKey: hex.EncodeToString(publicKey),
+	})
+	require.NoError(t, err)
+
+	// a reading of 12345 x 10^-1 Wh
+	encoded := make([]byte, 39)
+	copy(encoded[0:10], "0901454D48")
+	binary.LittleEndian.PutUint32(encoded[10:14], 1686837900)
+	encoded[29] = 30
+	encoded[30] = 0xff
+	binary.LittleEndian.PutUint64(encoded[31:39], 12345)
+	digest := sha256.Sum256(encoded)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := make([]byte, 48)
+	r.FillBytes(signature[:24])
+	s.FillBytes(signature[24:])
+	edl := fmt.Sprintf(`<signedMeterValue><publicKey encoding="base64">%s</publicKey>`+
+		`<meterValueSignature encoding="base64">%s</meterValueSignature>`+
+		`<signatureMethod>ECDSA192SHA256</signatureMethod><encodingMethod>EDL</encodingMethod>`+
+		`<encodedMeterValue encoding="base64">%s</encodedMeterValue></signedMeterValue>`,
+		base64.StdEncoding.EncodeToString(publicKey),
+		base64.StdEncoding.EncodeToString(signature),
+		base64.StdEncoding.EncodeToString(encoded))
+
+	handler := handlers.MeterValuesHandler{
+		Clock:                   clock,
+		TransactionStore:        engine,
+		MeterReadingStore:       engine,
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	req := newMeterValuesRequest(1, nil)
+	signedData := types.MeterValuesJsonMeterValueElemSampledValueElemFormatSignedData
+	req.MeterValue[0].SampledValue[0].Format = &signedData
+	req.MeterValue[0].SampledValue[0].Value = edl
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	want := expectedSampledValues()
+	want[0].SignedMeterValue = &store.SignedMeterValue{
+		SignedMeterData: edl,
+		EncodingMethod:  "EDL",
+		SigningMethod:   "ECDSA192SHA256",
+		Status:          store.SignedMeterValueStatusValid,
+	}
+	assert.Equal(t, want, readings[0].SampledValues)
+}
+
+func TestMeterValuesHandlerKeepsPlainValueForUndecodableSignedData(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := handlers.MeterValuesHandler{
+		Clock:                   clock,
+		TransactionStore:        engine,
+		MeterReadingStore:       engine,
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	req := newMeterValuesRequest(1, nil)
+	signedData := types.MeterValuesJsonMeterValueElemSampledValueElemFormatSignedData
+	signed := req.MeterValue[0].SampledValue[0]
+	signed.Format = &signedData
+	signed.Value = "DEADBEEF"
+	req.MeterValue[0].SampledValue = append(req.MeterValue[0].SampledValue, signed)
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	want := expectedSampledValues()
+	want[0].SignedMeterValue = &store.SignedMeterValue{
+		SignedMeterData: "DEADBEEF",
+		Status:          store.SignedMeterValueStatusUnsupported,
+	}
+	assert.Equal(t, want, readings[0].SampledValues)
+}
+
+func TestMeterValuesHandlerRecordsUndecodableSignedDataWithoutReading(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := handlers.MeterValuesHandler{
+		Clock:                   clock,
+		TransactionStore:        engine,
+		MeterReadingStore:       engine,
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	req := newMeterValuesRequest(1, nil)
+	signedData := types.MeterValuesJsonMeterValueElemSampledValueElemFormatSignedData
+	req.MeterValue[0].SampledValue[0].Format = &signedData
+	req.MeterValue[0].SampledValue[0].Value = "OCMF|not json|{}"
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	readings, err := engine.ListChargeStationMeterReadings(ctx, "cs001", time.Time{}, clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	want := expectedSampledValues()
+	want[0].Value = 0
+	want[0].SignedDataOnly = true
+	want[0].SignedMeterValue = &store.SignedMeterValue{
+		SignedMeterData: "OCMF|not json|{}",
+		EncodingMethod:  "OCMF",
+		Status:          store.SignedMeterValueStatusInvalid,
+	}
+	assert.Equal(t, want, readings[0].SampledValues)
+}
@@ -26,28 +26,36 @@ import (
 func NewRouter(emitter transport.Emitter,
 	clk clock.PassiveClock,
 	engine store.Engine,
+	tokenAuthService services.TokenAuthService,
 	certValidationService services.CertificateValidationService,
 	chargeStationCertProvider services.ChargeStationCertificateProvider,
 	contractCertProvider services.ContractCertificateProvider,
+	loadBalancer services.LoadBalancer,
+	pendingCalls *handlers.PendingCalls,
+	sessionPublisher services.SessionPublisher,
+	evseStatusPublisher services.EvseStatusPublisher,
 	heartbeatInterval time.Duration,
 	schemaFS fs.FS) transport.MessageHandler {
 
 	standardCallMaker := NewCallMaker(emitter)
+	signedMeterValueService := services.BasicSignedMeterValueService{Store: engine}
 
 	return &handlers.Router{
-		Emitter:     emitter,
-		SchemaFS:    schemaFS,
-		OcppVersion: transport.OcppVersion16,
+		Emitter:      emitter,
+		SchemaFS:     schemaFS,
+		OcppVersion:  transport.OcppVersion16,
+		PendingCalls: pendingCalls,
 		CallRoutes: map[string]handlers.CallRoute{
 			"BootNotification": {
 				NewRequest:     func() ocpp.Request { return new(ocpp16.BootNotificationJson) },
 				RequestSchema:  "ocpp16/BootNotification.json",
 				ResponseSchema: "ocpp16/BootNotificationResponse.json",
 				Handler: BootNotificationHandler{
-					Clock:               clk,
-					RuntimeDetailsStore: engine,
-					SettingsStore:       engine,
-					HeartbeatInterval:   int(heartbeatInterval.Seconds()),
+					Clock:                clk,
+					RuntimeDetailsStore:  engine,
+					SettingsStore:        engine,
+					ChargingProfileStore: engine,
+					HeartbeatInterval:    int(heartbeatInterval.Seconds()),
 				},
 			},
 			"Heartbeat": {
@@ -62,14 +70,18 @@ func NewRouter(emitter transport.Emitter,
 				NewRequest:     func() ocpp.Request { return new(ocpp16.StatusNotificationJson) },
 				RequestSchema:  "ocpp16/StatusNotification.json",
 				ResponseSchema: "ocpp16/StatusNotificationResponse.json",
-				Handler:        handlers.CallHandlerFunc(StatusNotificationHandler),
+				Handler: StatusNotificationHandler{
+					Store:               engine,
+					Clock:               clk,
+					EvseStatusPublisher: evseStatusPublisher,
+				},
 			},
 			"Authorize": {
 				NewRequest:     func() ocpp.Request { return new(ocpp16.AuthorizeJson) },
 				RequestSchema:  "ocpp16/Authorize.json",
 				ResponseSchema: "ocpp16/AuthorizeResponse.json",
 				Handler: AuthorizeHandler{
-					TokenStore: engine,
+					TokenAuthService: tokenAuthService,
 				},
 			},
 			"StartTransaction": {
@@ -78,8 +90,10 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp16/StartTransactionResponse.json",
 				Handler: StartTransactionHandler{
 					Clock:            clk,
-					TokenStore:       engine,
+					TokenAuthService: tokenAuthService,
 					TransactionStore: engine,
+					LoadBalancer:     loadBalancer,
+					SessionPublisher: sessionPublisher,
 				},
 			},
 			"StopTransaction": {
@@ -90,6 +104,9 @@ func NewRouter(emitter transport.Emitter,
 					Clock:            clk,
 					TokenStore:       engine,
 					TransactionStore: engine,
+					LoadBalancer:     loadBalancer,
+					SignedMeterValueService: signedMeterValueService,
+					SessionPublisher: sessionPublisher,
 				},
 			},
 			"MeterValues": {
@@ -97,7 +114,12 @@ func NewRouter(emitter transport.Emitter,
 				RequestSchema:  "ocpp16/MeterValues.json",
 				ResponseSchema: "ocpp16/MeterValuesResponse.json",
 				Handler: MeterValuesHandler{
-					TransactionStore: engine,
+					Clock:             clk,
+					TransactionStore:  engine,
+					MeterReadingStore: engine,
+					LoadBalancer:      loadBalancer,
+					SignedMeterValueService: signedMeterValueService,
+					SessionPublisher: sessionPublisher,
 				},
 			},
 			"SecurityEventNotification": {
@@ -131,10 +153,7 @@ func NewRouter(emitter transport.Emitter,
 								RequestSchema:  "ocpp201/AuthorizeRequest.json",
 								ResponseSchema: "ocpp201/AuthorizeResponse.json",
 								Handler: handlers201.AuthorizeHandler{
-									TokenAuthService: &services.OcppTokenAuthService{
-										Clock:      clk,
-										TokenStore: engine,
-									},
+									TokenAuthService:             tokenAuthService,
 									CertificateValidationService: certValidationService,
 								},
 							},
@@ -171,10 +190,7 @@ func NewRouter(emitter transport.Emitter,
 								ResponseSchema: "has2be/AuthorizeResponse.json",
 								Handler: handlersHasToBe.AuthorizeHandler{
 									Handler201: handlers201.AuthorizeHandler{
-										TokenAuthService: &services.OcppTokenAuthService{
-											Clock:      clk,
-											TokenStore: engine,
-										},
+										TokenAuthService:             tokenAuthService,
 										CertificateValidationService: certValidationService,
 									},
 								},
@@ -342,6 +358,36 @@ func NewRouter(emitter transport.Emitter,
 					CallMaker: standardCallMaker,
 				},
 			},
+			"SetChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.SetChargingProfileJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.SetChargingProfileResponseJson) },
+				RequestSchema:  "ocpp16/SetChargingProfile.json",
+				ResponseSchema: "ocpp16/SetChargingProfileResponse.json",
+				Handler: SetChargingProfileResultHandler{
+					Store:     engine,
+					CallMaker: standardCallMaker,
+				},
+			},
+			"ClearChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.ClearChargingProfileJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.ClearChargingProfileResponseJson) },
+				RequestSchema:  "ocpp16/ClearChargingProfile.json",
+				ResponseSchema: "ocpp16/ClearChargingProfileResponse.json",
+				Handler: ClearChargingProfileResultHandler{
+					Store:     engine,
+					CallMaker: standardCallMaker,
+				},
+			},
+			"GetCompositeSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.GetCompositeScheduleJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.GetCompositeScheduleResponseJson) },
+				RequestSchema:  "ocpp16/GetCompositeSchedule.json",
+				ResponseSchema: "ocpp16/GetCompositeScheduleResponse.json",
+				Handler: GetCompositeScheduleResultHandler{
+					Store: engine,
+					Clock: clk,
+				},
+			},
 			"UnlockConnector": {
 				NewRequest:     func() ocpp.Request { return new(ocpp16.UnlockConnectorJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp16.UnlockConnectorResponseJson) },
@@ -369,6 +415,9 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 			reflect.TypeOf(&ocpp16.RemoteStopTransactionJson{}):  "RemoteStopTransaction",
 			reflect.TypeOf(&ocpp16.ResetJson{}):                  "Reset",
 			reflect.TypeOf(&ocpp16.UnlockConnectorJson{}):        "UnlockConnector",
+			reflect.TypeOf(&ocpp16.SetChargingProfileJson{}):     "SetChargingProfile",
+			reflect.TypeOf(&ocpp16.ClearChargingProfileJson{}):   "ClearChargingProfile",
+			reflect.TypeOf(&ocpp16.GetCompositeScheduleJson{}):   "GetCompositeSchedule",
 		},
 	}
 }
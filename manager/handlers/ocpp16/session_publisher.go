@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+// publishSession sends the session for the transaction to roaming partners using
+// the publish function. Errors are only logged: a roaming partner that cannot be
+// reached must not stop the charge station from charging.
+func publishSession(ctx context.Context, transactionStore store.TransactionStore, chargeStationId, transactionId string,
+	publish func(context.Context, *store.Transaction) error) {
+	transaction, err := transactionStore.FindTransaction(ctx, chargeStationId, transactionId)
+	if err == nil && transaction != nil {
+		err = publish(ctx, transaction)
+	}
+	if err != nil {
+		slog.Error("publishing session", slog.String("err", err.Error()),
+			slog.String("chargeStationId", chargeStationId), slog.String("transactionId", transactionId))
+	}
+}
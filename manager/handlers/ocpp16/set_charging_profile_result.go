@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// compositeScheduleDuration is the period (in seconds) requested when
+// refreshing the composite schedule of a connector
+const compositeScheduleDuration = 86400
+
+type SetChargingProfileResultHandler struct {
+	Store     store.ChargeStationChargingProfilesStore
+	CallMaker handlers.CallMaker
+}
+
+func (h SetChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.SetChargingProfileJson)
+	resp := response.(*types.SetChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("set_charging_profile.connector_id", req.ConnectorId),
+		attribute.Int("set_charging_profile.charging_profile_id", req.CsChargingProfiles.ChargingProfileId),
+		attribute.String("set_charging_profile.purpose", string(req.CsChargingProfiles.ChargingProfilePurpose)),
+		attribute.String("set_charging_profile.status", string(resp.Status)))
+
+	profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+
+	var profile *store.ChargingProfile
+	if profiles != nil {
+		for _, p := range profiles.ChargingProfiles {
+			if p.ChargingProfileId == req.CsChargingProfiles.ChargingProfileId {
+				profile = p
+				break
+			}
+		}
+	}
+
+	// a profile that has been removed from the store (or was never stored)
+	// is not something we are managing so there is nothing to update
+	if profile != nil && profile.Status == store.ChargingProfileStatusPending {
+		switch resp.Status {
+		case types.SetChargingProfileResponseJsonStatusAccepted:
+			profile.Status = store.ChargingProfileStatusAccepted
+		case types.SetChargingProfileResponseJsonStatusRejected:
+			profile.Status = store.ChargingProfileStatusRejected
+		case types.SetChargingProfileResponseJsonStatusNotSupported:
+			profile.Status = store.ChargingProfileStatusNotSupported
+		}
+
+		err = h.Store.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{profile},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if resp.Status == types.SetChargingProfileResponseJsonStatusAccepted {
+		return h.CallMaker.Send(ctx, chargeStationId, &types.GetCompositeScheduleJson{
+			ConnectorId: req.ConnectorId,
+			Duration:    compositeScheduleDuration,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestSetChargingProfileResultHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         types.SetChargingProfileResponseJsonStatus
+		expectedStatus store.ChargingProfileStatus
+		expectRefresh  bool
+	}{
+		{
+			name:           "Accepted",
+			status:         types.SetChargingProfileResponseJsonStatusAccepted,
+			expectedStatus: store.ChargingProfileStatusAccepted,
+			expectRefresh:  true,
+		},
+		{
+			name:           "Rejected",
+			status:         types.SetChargingProfileResponseJsonStatusRejected,
+			expectedStatus: store.ChargingProfileStatusRejected,
+		},
+		{
+			name:           "NotSupported",
+			status:         types.SetChargingProfileResponseJsonStatusNotSupported,
+			expectedStatus: store.ChargingProfileStatusNotSupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			engine := inmemory.NewStore(clock.RealClock{})
+
+			err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+				ChargingProfiles: []*store.ChargingProfile{
+					{
+						ChargingProfileId:      5,
+						ConnectorId:            1,
+						ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+						ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+						Status:                 store.ChargingProfileStatusPending,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			callMaker := &mockCallMaker{}
+			handler := handlers.SetChargingProfileResultHandler{
+				Store:     engine,
+				CallMaker: callMaker,
+			}
+
+			tracer, exporter := testutil.GetTracer()
+
+			func() {
+				ctx, span := tracer.Start(ctx, "test")
+				defer span.End()
+
+				req := &types.SetChargingProfileJson{
+					ConnectorId: 1,
+					CsChargingProfiles: types.SetChargingProfileJsonCsChargingProfiles{
+						ChargingProfileId:      5,
+						ChargingProfileKind:    types.SetChargingProfileJsonCsChargingProfilesChargingProfileKindAbsolute,
+						ChargingProfilePurpose: types.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxDefaultProfile,
+					},
+				}
+				resp := &types.SetChargingProfileResponseJson{
+					Status: tc.status,
+				}
+
+				err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+				require.NoError(t, err)
+			}()
+
+			testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+				"set_charging_profile.connector_id":        1,
+				"set_charging_profile.charging_profile_id": 5,
+				"set_charging_profile.purpose":             "TxDefaultProfile",
+				"set_charging_profile.status":              string(tc.status),
+			})
+
+			profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+			require.NoError(t, err)
+			require.NotNil(t, profiles)
+			require.Len(t, profiles.ChargingProfiles, 1)
+			assert.Equal(t, tc.expectedStatus, profiles.ChargingProfiles[0].Status)
+
+			if tc.expectRefresh {
+				require.Len(t, callMaker.calls, 1)
+				assert.Equal(t, "cs001", callMaker.calls[0].chargeStationId)
+				assert.Equal(t, &types.GetCompositeScheduleJson{
+					ConnectorId: 1,
+					Duration:    86400,
+				}, callMaker.calls[0].request)
+			} else {
+				assert.Len(t, callMaker.calls, 0)
+			}
+		})
+	}
+}
+
+func TestSetChargingProfileResultHandlerIgnoresUnknownProfile(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	callMaker := &mockCallMaker{}
+	handler := handlers.SetChargingProfileResultHandler{
+		Store:     engine,
+		CallMaker: callMaker,
+	}
+
+	req := &types.SetChargingProfileJson{
+		ConnectorId: 0,
+		CsChargingProfiles: types.SetChargingProfileJsonCsChargingProfiles{
+			ChargingProfileId: 7,
+		},
+	}
+	resp := &types.SetChargingProfileResponseJson{
+		Status: types.SetChargingProfileResponseJsonStatusAccepted,
+	}
+
+	err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, profiles)
+	assert.Len(t, callMaker.calls, 1)
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"strings"
+
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// convertSignedData records the signed data reported in an OCPP 1.6 sampled
+// value. OCPP 1.6 does not define the format of the signed data: data starting
+// with "OCMF|" is OCMF and an XML signedMeterValue document is EDL. The reading
+// is decoded from the signed data (from the last reading of an OCMF payload)
+// and is returned in Wh. It returns false if there isn't a reading that can be
+// decoded, in which case the status of data that can't be parsed is Invalid and
+// the status of data in an unknown format is Unsupported.
+func convertSignedData(data string) (float64, bool, *store.SignedMeterValue) {
+	signed := &store.SignedMeterValue{
+		SignedMeterData: data,
+		Status:          store.SignedMeterValueStatusUnverified,
+	}
+
+	switch {
+	case strings.HasPrefix(data, "OCMF|"):
+		signed.EncodingMethod = services.EncodingMethodOcmf
+		ocmf, err := services.ParseOcmf(data)
+		if err != nil {
+			signed.Status = store.SignedMeterValueStatusInvalid
+			return 0, false, signed
+		}
+		signed.SigningMethod = ocmf.Signature.Algorithm
+		if len(ocmf.Payload.Readings) == 0 {
+			return 0, false, signed
+		}
+		value, ok := ocmf.Payload.Readings[len(ocmf.Payload.Readings)-1].WattHours()
+		return value, ok, signed
+	case services.IsEdl(data):
+		signed.EncodingMethod = services.EncodingMethodEdl
+		edl, err := services.ParseEdl(data)
+		if err != nil {
+			signed.Status = store.SignedMeterValueStatusInvalid
+			return 0, false, signed
+		}
+		signed.SigningMethod = edl.SignatureMethod
+		value, ok := edl.MeterValue.WattHours()
+		return value, ok, signed
+	default:
+		signed.Status = store.SignedMeterValueStatusUnsupported
+		return 0, false, signed
+	}
+}
+
+// convertSignedSampledValue sets the value of a sampled value reported as
+// signed data. A value that can't be decoded from the signed data is marked as
+// SignedDataOnly rather than being recorded as a reading of 0.
+func convertSignedSampledValue(sampledValue *store.SampledValue, data string) {
+	value, ok, signed := convertSignedData(data)
+	sampledValue.SignedMeterValue = signed
+	if !ok {
+		sampledValue.SignedDataOnly = true
+		return
+	}
+	sampledValue.Value = value
+	sampledValue.UnitOfMeasure = &store.UnitOfMeasure{Unit: "Wh"}
+}
+
+// mergeSignedDataOnly attaches the signed data that could not be decoded to the
+// plain sampled value for the same reading, if there is one, so that the plain
+// value is kept along with the signed data.
+func mergeSignedDataOnly(sampledValues []store.SampledValue) []store.SampledValue {
+	merged := make(map[int]bool)
+	for i, sampledValue := range sampledValues {
+		if !sampledValue.SignedDataOnly {
+			continue
+		}
+		if plain := findPlainSampledValue(sampledValues, sampledValue); plain >= 0 {
+			sampledValues[plain].SignedMeterValue = sampledValue.SignedMeterValue
+			merged[i] = true
+		}
+	}
+	if len(merged) == 0 {
+		return sampledValues
+	}
+
+	var result []store.SampledValue
+	for i, sampledValue := range sampledValues {
+		if !merged[i] {
+			result = append(result, sampledValue)
+		}
+	}
+	return result
+}
+
+func findPlainSampledValue(sampledValues []store.SampledValue, signed store.SampledValue) int {
+	for i, sampledValue := range sampledValues {
+		if sampledValue.SignedMeterValue == nil && !sampledValue.SignedDataOnly && sameReading(sampledValue, signed) {
+			return i
+		}
+	}
+	return -1
+}
+
+func sameReading(a, b store.SampledValue) bool {
+	return equalOptional(a.Context, b.Context) && equalOptional(a.Measurand, b.Measurand) &&
+		equalOptional(a.Phase, b.Phase) && equalOptional(a.Location, b.Location)
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -4,12 +4,17 @@ package ocpp16
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"golang.org/x/exp/slog"
 	"k8s.io/utils/clock"
@@ -17,8 +22,11 @@ import (
 
 type StartTransactionHandler struct {
 	Clock            clock.PassiveClock
-	TokenStore       store.TokenStore
+	TokenAuthService services.TokenAuthService
 	TransactionStore store.TransactionStore
+	LoadBalancer     services.LoadBalancer
+	// SessionPublisher informs roaming partners of the transaction (optional)
+	SessionPublisher services.SessionPublisher
 }
 
 func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -26,23 +34,18 @@ func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 
 	slog.Info("starting transaction", slog.Any("request", req))
 
-	status := types.StartTransactionResponseJsonIdTagInfoStatusInvalid
-	tok, err := t.TokenStore.LookupToken(ctx, req.IdTag)
-	if err != nil {
-		return nil, err
-	}
-	if tok != nil && tok.Valid {
-		status = types.StartTransactionResponseJsonIdTagInfoStatusAccepted
-	}
+	idTokenInfo := t.TokenAuthService.Authorize(ctx, chargeStationId, idTagToken(req.IdTag))
+	status := types.StartTransactionResponseJsonIdTagInfoStatus(idTagStatus(idTokenInfo.Status))
 
 	var transactionId int
+	var err error
 	if status == types.StartTransactionResponseJsonIdTagInfoStatusAccepted {
 		//#nosec G404 - transaction id does not require secure random number generator
 		transactionId = int(rand.Int31())
 		contextTransactionBegin := types.MeterValuesJsonMeterValueElemSampledValueElemContextTransactionBegin
 		meterValueMeasurand := "MeterValue"
 		transactionUuid := ConvertToUUID(transactionId)
-		err = t.TransactionStore.CreateTransaction(ctx, chargeStationId, transactionUuid, req.IdTag, "ISO14443",
+		err = t.TransactionStore.CreateTransaction(ctx, chargeStationId, transactionUuid, req.ConnectorId, req.IdTag, "ISO14443",
 			[]store.MeterValue{
 				{
 					Timestamp: t.Clock.Now().Format(time.RFC3339),
@@ -62,6 +65,22 @@ func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 		if err != nil {
 			return nil, err
 		}
+
+		if t.LoadBalancer != nil {
+			// the TxProfile for the transaction refers to the transaction id so the
+			// charge station must have received it before the profile is sent
+			handlers.AfterResponse(ctx, func(ctx context.Context) {
+				err := t.LoadBalancer.TransactionStarted(ctx, chargeStationId, req.ConnectorId, strconv.Itoa(transactionId))
+				if err != nil {
+					slog.Error("load balancing started transaction", slog.String("err", err.Error()),
+						slog.String("chargeStationId", chargeStationId))
+				}
+			})
+		}
+
+		if t.SessionPublisher != nil {
+			publishSession(ctx, t.TransactionStore, chargeStationId, transactionUuid, t.SessionPublisher.PushSession)
+		}
 	}
 
 	response := &types.StartTransactionResponseJson{
@@ -89,3 +108,22 @@ func ConvertToUUID(transactionId int) string {
 	}
 	return uuid.Must(uuid.FromBytes(uuidBytes)).String()
 }
+
+// ConvertFromUUID converts a transaction id created by ConvertToUUID back to the
+// transaction id used by the charge station. A transaction id that is already an
+// integer is returned unchanged.
+func ConvertFromUUID(transactionId string) (int, error) {
+	if id, err := strconv.Atoi(transactionId); err == nil {
+		return id, nil
+	}
+	uuidValue, err := uuid.Parse(transactionId)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction id: %s", transactionId)
+	}
+	for _, b := range uuidValue[:12] {
+		if b != 0 {
+			return 0, fmt.Errorf("not an OCPP 1.6 transaction id: %s", transactionId)
+		}
+	}
+	return int(int32(binary.BigEndian.Uint32(uuidValue[12:]))), nil
+}
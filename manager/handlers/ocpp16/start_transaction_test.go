@@ -4,6 +4,8 @@ package ocpp16_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"k8s.io/utils/clock"
 	"testing"
 	"time"
@@ -12,8 +14,11 @@ import (
 	"github.com/stretchr/testify/require"
 	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/schemas"
 	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
 	clockTest "k8s.io/utils/clock/testing"
 )
 
@@ -33,14 +38,17 @@ func TestStartTransaction(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
-
 	now, err := time.Parse(time.RFC3339, "2023-06-15T15:05:00+01:00")
 	require.NoError(t, err)
 
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(now))
+
 	handler := handlers.StartTransactionHandler{
-		Clock:            clockTest.NewFakePassiveClock(now),
-		TokenStore:       engine,
+		Clock: clockTest.NewFakePassiveClock(now),
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 		TransactionStore: transactionStore,
 	}
 
@@ -100,6 +108,8 @@ func TestStartTransaction(t *testing.T) {
 		EndedSeqNo:        0,
 		UpdatedSeqNoCount: 0,
 		Offline:           false,
+		LastUpdated:       now.UTC(),
+		EvseId:            1,
 	}
 
 	assert.Equal(t, expected, found)
@@ -127,8 +137,11 @@ func TestStartTransactionWithInvalidRFID(t *testing.T) {
 	require.NoError(t, err)
 
 	handler := handlers.StartTransactionHandler{
-		Clock:            clockTest.NewFakePassiveClock(now),
-		TokenStore:       engine,
+		Clock: clockTest.NewFakePassiveClock(now),
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 		TransactionStore: transactionStore,
 	}
 
@@ -179,8 +192,11 @@ func TestStartTransactionWithInvalidToken(t *testing.T) {
 	require.NoError(t, err)
 
 	handler := handlers.StartTransactionHandler{
-		Clock:            clockTest.NewFakePassiveClock(now),
-		TokenStore:       engine,
+		Clock: clockTest.NewFakePassiveClock(now),
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
 		TransactionStore: transactionStore,
 	}
 
@@ -208,3 +224,150 @@ func TestStartTransactionWithInvalidToken(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, transactions, "No transaction should be created when token is invalid")
 }
+
+type recordingLoadBalancer struct {
+	started []string
+	ended   []string
+}
+
+func (r *recordingLoadBalancer) TransactionStarted(_ context.Context, chargeStationId string, evseId int, transactionId string) error {
+	r.started = append(r.started, fmt.Sprintf("%s %d %s", chargeStationId, evseId, transactionId))
+	return nil
+}
+
+func (r *recordingLoadBalancer) TransactionEnded(_ context.Context, chargeStationId, transactionId string) error {
+	r.ended = append(r.ended, fmt.Sprintf("%s %s", chargeStationId, transactionId))
+	return nil
+}
+
+func (r *recordingLoadBalancer) Rebalance(context.Context, string) error {
+	return nil
+}
+
+func TestStartTransactionNotifiesLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetToken(ctx, &store.Token{
+		Uid:   "MYRFIDTAG",
+		Valid: true,
+	})
+	require.NoError(t, err)
+
+	loadBalancer := &recordingLoadBalancer{}
+	handler := handlers.StartTransactionHandler{
+		Clock: clock.RealClock{},
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
+		TransactionStore: engine,
+		LoadBalancer:     loadBalancer,
+	}
+
+	req := &types.StartTransactionJson{
+		ConnectorId: 2,
+		IdTag:       "MYRFIDTAG",
+		MeterStart:  100,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	resp, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+	got := resp.(*types.StartTransactionResponseJson)
+	require.NotNil(t, got.TransactionId)
+
+	assert.Equal(t, []string{fmt.Sprintf("cs001 2 %d", *got.TransactionId)}, loadBalancer.started)
+}
+
+type recordingEmitter struct {
+	messages []*transport.Message
+}
+
+func (r *recordingEmitter) Emit(_ context.Context, _ transport.OcppVersion, _ string, message *transport.Message) error {
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func TestStartTransactionSendsTxProfileAfterResponse(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetToken(ctx, &store.Token{
+		Uid:   "MYRFIDTAG",
+		Valid: true,
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{OcppVersion: "1.6"})
+	require.NoError(t, err)
+
+	emitter := &recordingEmitter{}
+	loadBalancer := &services.SiteLoadBalancer{
+		Store:    engine,
+		Clock:    clock.RealClock{},
+		Strategy: services.EqualShareAllocationStrategy{},
+		Sites: []*services.Site{
+			{
+				Id:                    "depot",
+				MaxCurrent:            32,
+				MinTransactionCurrent: 6,
+				MaxTransactionCurrent: 32,
+				ChargeStationIds:      []string{"cs001"},
+			},
+		},
+		V16CallMaker: handlers.NewCallMaker(emitter),
+		RetryAfter:   time.Minute,
+	}
+	tokenAuthService := &services.OcppTokenAuthService{
+		Clock:      clock.RealClock{},
+		TokenStore: engine,
+	}
+	router := handlers.NewRouter(emitter, clock.RealClock{}, engine, tokenAuthService, nil, nil, nil,
+		loadBalancer, nil, nil, nil, time.Minute, schemas.OcppSchemas)
+
+	payload, err := json.Marshal(&types.StartTransactionJson{
+		ConnectorId: 1,
+		IdTag:       "MYRFIDTAG",
+		MeterStart:  100,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	router.Handle(ctx, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "StartTransaction",
+		MessageId:      "1234",
+		RequestPayload: payload,
+	})
+
+	require.Len(t, emitter.messages, 2)
+	assert.Equal(t, transport.MessageTypeCallResult, emitter.messages[0].MessageType)
+	assert.Equal(t, "StartTransaction", emitter.messages[0].Action)
+	assert.Equal(t, transport.MessageTypeCall, emitter.messages[1].MessageType)
+	assert.Equal(t, "SetChargingProfile", emitter.messages[1].Action)
+
+	var resp types.StartTransactionResponseJson
+	err = json.Unmarshal(emitter.messages[0].ResponsePayload, &resp)
+	require.NoError(t, err)
+	var req types.SetChargingProfileJson
+	err = json.Unmarshal(emitter.messages[1].RequestPayload, &req)
+	require.NoError(t, err)
+	require.NotNil(t, resp.TransactionId)
+	assert.Equal(t, resp.TransactionId, req.CsChargingProfiles.TransactionId)
+}
+
+func TestConvertFromUUID(t *testing.T) {
+	for _, transactionId := range []int{0, 123, 1<<31 - 1, -5} {
+		got, err := handlers.ConvertFromUUID(handlers.ConvertToUUID(transactionId))
+		require.NoError(t, err)
+		assert.Equal(t, transactionId, got)
+	}
+
+	got, err := handlers.ConvertFromUUID("42")
+	require.NoError(t, err)
+	assert.Equal(t, 42, got)
+
+	_, err = handlers.ConvertFromUUID("1b1e1a6e-3e5b-4b8c-9a3e-2f1c7d9e0a12")
+	assert.Error(t, err)
+	_, err = handlers.ConvertFromUUID("not-a-transaction")
+	assert.Error(t, err)
+}
@@ -4,21 +4,67 @@ package ocpp16
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
 )
 
-func StatusNotificationHandler(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+type StatusNotificationHandler struct {
+	Store               store.ChargeStationConnectorStatusStore
+	Clock               clock.PassiveClock
+	EvseStatusPublisher services.EvseStatusPublisher
+}
+
+func (h StatusNotificationHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
 	span := trace.SpanFromContext(ctx)
 
 	req := request.(*types.StatusNotificationJson)
 
 	span.SetAttributes(
 		attribute.Int("status.connector_id", req.ConnectorId),
-		attribute.String("status.connector_status", string(req.Status)))
+		attribute.String("status.connector_status", string(req.Status)),
+		attribute.String("status.error_code", string(req.ErrorCode)))
+
+	timestamp := h.Clock.Now()
+	if req.Timestamp != nil {
+		reported, err := time.Parse(time.RFC3339, *req.Timestamp)
+		if err == nil {
+			timestamp = reported
+		}
+	}
+
+	// OCPP 1.6 has no EVSEs: each connector is treated as an EVSE with a single connector
+	errorCode := string(req.ErrorCode)
+	err := h.Store.SetChargeStationConnectorStatus(ctx, chargeStationId, &store.ConnectorStatus{
+		EvseId:          req.ConnectorId,
+		ConnectorId:     req.ConnectorId,
+		Status:          string(req.Status),
+		ErrorCode:       &errorCode,
+		Info:            req.Info,
+		VendorId:        req.VendorId,
+		VendorErrorCode: req.VendorErrorCode,
+		Timestamp:       timestamp.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("setting connector status: %w", err)
+	}
+
+	if h.EvseStatusPublisher != nil {
+		err = h.EvseStatusPublisher.PushEvseStatus(ctx, chargeStationId)
+		if err != nil {
+			slog.Error("publishing evse status", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId))
+		}
+	}
 
 	return &types.StatusNotificationResponseJson{}, nil
 }
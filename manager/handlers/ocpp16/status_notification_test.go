@@ -4,25 +4,114 @@ package ocpp16_test
 
 import (
 	"context"
+	"testing"
+	"time"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
-	"testing"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	clockTest "k8s.io/utils/clock/testing"
 )
 
 func TestStatusNotificationHandler(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := handlers.StatusNotificationHandler{
+		Store: engine,
+		Clock: clock,
+	}
+
 	timestamp := "2023-05-01T01:00:00+01:00"
+	info := "ground fault"
 	req := &types.StatusNotificationJson{
 		Timestamp:   &timestamp,
 		ConnectorId: 2,
-		ErrorCode:   types.StatusNotificationJsonErrorCodeNoError,
-		Status:      types.StatusNotificationJsonStatusPreparing,
+		ErrorCode:   types.StatusNotificationJsonErrorCodeGroundFailure,
+		Info:        &info,
+		Status:      types.StatusNotificationJsonStatusFaulted,
 	}
 
-	got, err := handlers.StatusNotificationHandler(context.Background(), "cs001", req)
+	got, err := handler.HandleCall(ctx, "cs001", req)
 	assert.NoError(t, err)
 
 	want := &types.StatusNotificationResponseJson{}
 
 	assert.Equal(t, want, got)
+
+	status, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 2, 2)
+	require.NoError(t, err)
+	errorCode := "GroundFailure"
+	assert.Equal(t, &store.ConnectorStatus{
+		EvseId:      2,
+		ConnectorId: 2,
+		Status:      "Faulted",
+		ErrorCode:   &errorCode,
+		Info:        &info,
+		Timestamp:   time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+	}, status)
+}
+
+func TestStatusNotificationHandlerWithoutTimestamp(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	handler := handlers.StatusNotificationHandler{
+		Store: engine,
+		Clock: clock,
+	}
+
+	req := &types.StatusNotificationJson{
+		ConnectorId: 0,
+		ErrorCode:   types.StatusNotificationJsonErrorCodeNoError,
+		Status:      types.StatusNotificationJsonStatusAvailable,
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	status, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 0, 0)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, "Available", status.Status)
+	assert.Equal(t, now, status.Timestamp)
+}
+
+type recordingEvseStatusPublisher struct {
+	pushed []string
+}
+
+func (r *recordingEvseStatusPublisher) PushEvseStatus(_ context.Context, chargeStationId string) error {
+	r.pushed = append(r.pushed, chargeStationId)
+	return nil
+}
+
+func TestStatusNotificationHandlerPublishesEvseStatus(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	evseStatusPublisher := &recordingEvseStatusPublisher{}
+	handler := handlers.StatusNotificationHandler{
+		Store:               engine,
+		Clock:               clock,
+		EvseStatusPublisher: evseStatusPublisher,
+	}
+
+	req := &types.StatusNotificationJson{
+		ConnectorId: 1,
+		ErrorCode:   types.StatusNotificationJsonErrorCodeNoError,
+		Status:      types.StatusNotificationJsonStatusCharging,
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cs001"}, evseStatusPublisher.pushed)
 }
@@ -4,12 +4,13 @@ package ocpp16
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"golang.org/x/exp/slog"
 	"k8s.io/utils/clock"
@@ -19,6 +20,11 @@ type StopTransactionHandler struct {
 	Clock            clock.PassiveClock
 	TokenStore       store.TokenStore
 	TransactionStore store.TransactionStore
+	LoadBalancer     services.LoadBalancer
+	// SignedMeterValueService verifies signed meter values (optional)
+	SignedMeterValueService services.SignedMeterValueService
+	// SessionPublisher informs roaming partners of the transaction (optional)
+	SessionPublisher services.SessionPublisher
 }
 
 func (s StopTransactionHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (response ocpp.Response, err error) {
@@ -65,6 +71,12 @@ func (s StopTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 	if err != nil {
 		return nil, err
 	}
+	if s.SignedMeterValueService != nil {
+		err = s.SignedMeterValueService.VerifyMeterValues(ctx, chargeStationId, meterValues)
+		if err != nil {
+			return nil, fmt.Errorf("verifying signed meter values: %w", err)
+		}
+	}
 
 	var previousMeterValues []store.MeterValue
 	if transaction != nil {
@@ -77,6 +89,19 @@ func (s StopTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 		return nil, err
 	}
 
+	if s.LoadBalancer != nil {
+		err = s.LoadBalancer.TransactionEnded(ctx, chargeStationId, strconv.Itoa(req.TransactionId))
+		if err != nil {
+			slog.Error("load balancing ended transaction", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId))
+		}
+	}
+
+	if s.SessionPublisher != nil {
+		publishSession(ctx, s.TransactionStore, chargeStationId, transactionId, s.SessionPublisher.PushSession)
+		publishSession(ctx, s.TransactionStore, chargeStationId, transactionId, s.SessionPublisher.PushCdr)
+	}
+
 	return &types.StopTransactionResponseJson{
 		IdTagInfo: idTagInfo,
 	}, nil
@@ -114,7 +139,7 @@ func findOutletEnergyReading(values []store.MeterValue) bool {
 		for _, sv := range value.SampledValues {
 			if sv.Context != nil && *sv.Context == "Transaction.End" &&
 				sv.Measurand != nil && *sv.Measurand == "Energy.Active.Import.Register" &&
-				sv.Location != nil && *sv.Location == "Outlet" {
+				sv.Location != nil && *sv.Location == "Outlet" && !sv.SignedDataOnly {
 				return true
 			}
 		}
@@ -154,7 +179,7 @@ func convertMeterValue(meterValue types.StopTransactionJsonTransactionDataElem)
 		return store.MeterValue{}, err
 	}
 	return store.MeterValue{
-		SampledValues: sampledValues,
+		SampledValues: mergeSignedDataOnly(sampledValues),
 		Timestamp:     meterValue.Timestamp,
 	}, nil
 }
@@ -172,26 +197,24 @@ func convertSampledValues(sampledValues []types.StopTransactionJsonTransactionDa
 }
 
 func convertSampleValue(sampleValue types.StopTransactionJsonTransactionDataElemSampledValueElem) (store.SampledValue, error) {
-	value, err := convertValue(sampleValue.Format, sampleValue.Value)
-	if err != nil {
-		return store.SampledValue{}, err
-	}
-	return store.SampledValue{
+	converted := store.SampledValue{
 		Context:       (*string)(sampleValue.Context),
 		Location:      (*string)(sampleValue.Location),
 		Measurand:     (*string)(sampleValue.Measurand),
 		Phase:         (*string)(sampleValue.Phase),
 		UnitOfMeasure: convertUnitOfMeasure(sampleValue.Unit),
-		Value:         value,
-	}, nil
-}
-
-func convertValue(format *types.StopTransactionJsonTransactionDataElemSampledValueElemFormat, value string) (float64, error) {
-	if format != nil && *format != types.StopTransactionJsonTransactionDataElemSampledValueElemFormatRaw {
-		return 0, errors.New("conversion from signed data not implemented")
+	}
+	if sampleValue.Format != nil && *sampleValue.Format == types.StopTransactionJsonTransactionDataElemSampledValueElemFormatSignedData {
+		convertSignedSampledValue(&converted, sampleValue.Value)
+		return converted, nil
 	}
 
-	return strconv.ParseFloat(value, 64)
+	value, err := strconv.ParseFloat(sampleValue.Value, 64)
+	if err != nil {
+		return store.SampledValue{}, err
+	}
+	converted.Value = value
+	return converted, nil
 }
 
 func convertUnitOfMeasure(unit *types.StopTransactionJsonTransactionDataElemSampledValueElemUnit) *store.UnitOfMeasure {
@@ -39,12 +39,12 @@ func TestStopTransactionHandler(t *testing.T) {
 	now, err := time.Parse(time.RFC3339, "2023-06-15T15:06:00+01:00")
 	require.NoError(t, err)
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(now))
 
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -154,6 +154,7 @@ func TestStopTransactionHandler(t *testing.T) {
 		EndedSeqNo:        1,
 		UpdatedSeqNoCount: 0,
 		Offline:           false,
+		LastUpdated:       now.UTC(),
 	}
 
 	assert.Equal(t, expected, found)
@@ -184,7 +185,7 @@ func TestStopTransactionWithInvalidToken(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -228,6 +229,18 @@ func TestStopTransactionWithInvalidToken(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func signedMeterValues(transaction *store.Transaction) []*store.SignedMeterValue {
+	var signed []*store.SignedMeterValue
+	for _, meterValue := range transaction.MeterValues {
+		for _, sampledValue := range meterValue.SampledValues {
+			if sampledValue.SignedMeterValue != nil {
+				signed = append(signed, sampledValue.SignedMeterValue)
+			}
+		}
+	}
+	return signed
+}
+
 func TestStopTransactionWithSignedMeterData(t *testing.T) {
 	chargingStationId := fmt.Sprintf("cs%03d", rand.Intn(1000))
 	engine := inmemory.NewStore(clock.RealClock{})
@@ -253,7 +266,7 @@ func TestStopTransactionWithSignedMeterData(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -305,8 +318,17 @@ func TestStopTransactionWithSignedMeterData(t *testing.T) {
 	}
 
 	_, err = handler.HandleCall(context.Background(), chargingStationId, req)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "conversion from signed data not implemented")
+	require.NoError(t, err)
+
+	transaction, err := transactionStore.FindTransaction(context.Background(), chargingStationId, handlers.ConvertToUUID(42))
+	require.NoError(t, err)
+	want := []*store.SignedMeterValue{
+		{
+			SignedMeterData: "DEADBEEF",
+			Status:          store.SignedMeterValueStatusUnsupported,
+		},
+	}
+	assert.Equal(t, want, signedMeterValues(transaction))
 }
 
 func TestStopTransactionWithInvalidHexSignedData(t *testing.T) {
@@ -334,7 +356,7 @@ func TestStopTransactionWithInvalidHexSignedData(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -386,8 +408,17 @@ func TestStopTransactionWithInvalidHexSignedData(t *testing.T) {
 	}
 
 	_, err = handler.HandleCall(context.Background(), chargingStationId, req)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "conversion from signed data not implemented")
+	require.NoError(t, err)
+
+	transaction, err := transactionStore.FindTransaction(context.Background(), chargingStationId, handlers.ConvertToUUID(42))
+	require.NoError(t, err)
+	want := []*store.SignedMeterValue{
+		{
+			SignedMeterData: "INVALID_HEX",
+			Status:          store.SignedMeterValueStatusUnsupported,
+		},
+	}
+	assert.Equal(t, want, signedMeterValues(transaction))
 }
 
 func TestStopTransactionWithMultipleSignedMeterValues(t *testing.T) {
@@ -415,7 +446,7 @@ func TestStopTransactionWithMultipleSignedMeterValues(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -474,8 +505,21 @@ func TestStopTransactionWithMultipleSignedMeterValues(t *testing.T) {
 	}
 
 	_, err = handler.HandleCall(context.Background(), chargingStationId, req)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "conversion from signed data not implemented")
+	require.NoError(t, err)
+
+	transaction, err := transactionStore.FindTransaction(context.Background(), chargingStationId, handlers.ConvertToUUID(42))
+	require.NoError(t, err)
+	want := []*store.SignedMeterValue{
+		{
+			SignedMeterData: "ABCD1234",
+			Status:          store.SignedMeterValueStatusUnsupported,
+		},
+		{
+			SignedMeterData: "EF567890",
+			Status:          store.SignedMeterValueStatusUnsupported,
+		},
+	}
+	assert.Equal(t, want, signedMeterValues(transaction))
 }
 
 func TestStopTransactionWithRawFormat(t *testing.T) {
@@ -503,7 +547,7 @@ func TestStopTransactionWithRawFormat(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -578,7 +622,7 @@ func TestStopTransactionWithoutIdTag(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -644,7 +688,7 @@ func TestStopTransactionWithNilUnitOfMeasure(t *testing.T) {
 	startContext := "Transaction.Begin"
 	startMeasurand := "MeterValue"
 	startLocation := "Outlet"
-	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), "MYRFIDTAG", "ISO14443",
+	err = transactionStore.CreateTransaction(context.TODO(), chargingStationId, handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443",
 		[]store.MeterValue{
 			{
 				SampledValues: []store.SampledValue{
@@ -773,3 +817,80 @@ func TestStopTransactionWithNonExistentTransaction(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, found)
 }
+
+func TestStopTransactionNotifiesLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.CreateTransaction(ctx, "cs001", handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+
+	loadBalancer := &recordingLoadBalancer{}
+	handler := handlers.StopTransactionHandler{
+		Clock:            clock.RealClock{},
+		TokenStore:       engine,
+		TransactionStore: engine,
+		LoadBalancer:     loadBalancer,
+	}
+
+	req := &types.StopTransactionJson{
+		MeterStop:     200,
+		Timestamp:     time.Now().Format(time.RFC3339),
+		TransactionId: 42,
+	}
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cs001 42"}, loadBalancer.ended)
+}
+
+type recordingSessionPublisher struct {
+	pushed  []string
+	updated []string
+	cdrs    []string
+}
+
+func (r *recordingSessionPublisher) PushSession(_ context.Context, transaction *store.Transaction) error {
+	r.pushed = append(r.pushed, fmt.Sprintf("%s %s %t", transaction.ChargeStationId, transaction.TransactionId, transaction.EndedSeqNo != 0))
+	return nil
+}
+
+func (r *recordingSessionPublisher) PushSessionUpdate(_ context.Context, transaction *store.Transaction) error {
+	r.updated = append(r.updated, fmt.Sprintf("%s %s", transaction.ChargeStationId, transaction.TransactionId))
+	return nil
+}
+
+func (r *recordingSessionPublisher) PushCdr(_ context.Context, transaction *store.Transaction) error {
+	r.cdrs = append(r.cdrs, fmt.Sprintf("%s %s", transaction.ChargeStationId, transaction.TransactionId))
+	return nil
+}
+
+func TestStopTransactionPublishesSession(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.CreateTransaction(ctx, "cs001", handlers.ConvertToUUID(42), 0, "MYRFIDTAG", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+
+	sessionPublisher := &recordingSessionPublisher{}
+	handler := handlers.StopTransactionHandler{
+		Clock:            clock.RealClock{},
+		TokenStore:       engine,
+		TransactionStore: engine,
+		SessionPublisher: sessionPublisher,
+	}
+
+	req := &types.StopTransactionJson{
+		MeterStop:     200,
+		Timestamp:     time.Now().Format(time.RFC3339),
+		TransactionId: 42,
+	}
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cs001 " + handlers.ConvertToUUID(42) + " true"}, sessionPublisher.pushed)
+	assert.Empty(t, sessionPublisher.updated)
+	assert.Equal(t, []string{"cs001 " + handlers.ConvertToUUID(42)}, sessionPublisher.cdrs)
+}
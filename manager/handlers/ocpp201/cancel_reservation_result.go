@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type CancelReservationResultHandler struct{}
+
+func (h CancelReservationResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.CancelReservationRequestJson)
+	resp := response.(*ocpp201.CancelReservationResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("cancel_reservation.reservation_id", req.ReservationId),
+		attribute.String("cancel_reservation.status", string(resp.Status)))
+
+	return nil
+}
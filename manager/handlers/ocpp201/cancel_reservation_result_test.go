@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"testing"
+)
+
+func TestCancelReservationResult(t *testing.T) {
+	handler := ocpp201.CancelReservationResultHandler{}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.TODO()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.CancelReservationRequestJson{
+			ReservationId: 42,
+		}
+
+		resp := &types.CancelReservationResponseJson{
+			Status: types.CancelReservationStatusEnumTypeRejected,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"cancel_reservation.reservation_id": 42,
+		"cancel_reservation.status":         "Rejected",
+	})
+}
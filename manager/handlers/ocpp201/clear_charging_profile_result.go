@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ClearChargingProfileResultHandler struct {
+	Store     store.ChargeStationChargingProfilesStore
+	CallMaker handlers.CallMaker
+}
+
+func (h ClearChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.ClearChargingProfileRequestJson)
+	resp := response.(*types.ClearChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("clear_charging_profile.status", string(resp.Status)))
+	if req.ChargingProfileId != nil {
+		span.SetAttributes(attribute.Int("clear_charging_profile.charging_profile_id", *req.ChargingProfileId))
+	}
+
+	evseId := 0
+	if req.ChargingProfileCriteria != nil && req.ChargingProfileCriteria.EvseId != nil {
+		evseId = *req.ChargingProfileCriteria.EvseId
+	}
+
+	if req.ChargingProfileId != nil {
+		profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+		if err != nil {
+			return err
+		}
+		if profiles != nil {
+			for _, profile := range profiles.ChargingProfiles {
+				if profile.ChargingProfileId == *req.ChargingProfileId {
+					evseId = profile.ConnectorId
+					break
+				}
+			}
+		}
+
+		// Unknown means the charge station doesn't have the profile: either way
+		// it is no longer installed so it can be removed
+		err = h.Store.DeleteChargeStationChargingProfile(ctx, chargeStationId, *req.ChargingProfileId)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resp.Status == types.ClearChargingProfileStatusEnumTypeAccepted {
+		return h.CallMaker.Send(ctx, chargeStationId, &types.GetCompositeScheduleRequestJson{
+			EvseId:   evseId,
+			Duration: compositeScheduleDuration,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// GetChargingProfilesResultHandler handles the response to the GetChargingProfiles
+// sent by the charging profile sync to find out whether a profile that was not
+// responded to was installed. The installed profiles are reported with a
+// ReportChargingProfiles: if there are none then the requested profiles that are
+// still pending are sent again.
+type GetChargingProfilesResultHandler struct {
+	Store store.ChargeStationChargingProfilesStore
+}
+
+func (h GetChargingProfilesResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.GetChargingProfilesRequestJson)
+	resp := response.(*types.GetChargingProfilesResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("get_charging_profiles.request_id", req.RequestId),
+		attribute.String("get_charging_profiles.status", string(resp.Status)))
+	if req.EvseId != nil {
+		span.SetAttributes(attribute.Int("get_charging_profiles.evse_id", *req.EvseId))
+	}
+
+	if resp.Status != types.GetChargingProfileStatusEnumTypeNoProfiles || len(req.ChargingProfile.ChargingProfileId) == 0 {
+		return nil
+	}
+
+	profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil || profiles == nil {
+		return err
+	}
+
+	requested := make(map[int]bool)
+	for _, id := range req.ChargingProfile.ChargingProfileId {
+		requested[id] = true
+	}
+
+	var updated []*store.ChargingProfile
+	for _, profile := range profiles.ChargingProfiles {
+		if requested[profile.ChargingProfileId] && profile.Status == store.ChargingProfileStatusPending {
+			profile.SendAfter = time.Time{}
+			updated = append(updated, profile)
+		}
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+
+	return h.Store.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+		ChargingProfiles: updated,
+	})
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestGetChargingProfilesResultHandler(t *testing.T) {
+	handler := ocpp201.GetChargingProfilesResultHandler{}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.Background()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.GetChargingProfilesRequestJson{
+			RequestId: 42,
+			EvseId:    makePtr(1),
+		}
+		resp := &types.GetChargingProfilesResponseJson{
+			Status: types.GetChargingProfileStatusEnumTypeNoProfiles,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"get_charging_profiles.request_id": 42,
+		"get_charging_profiles.evse_id":    1,
+		"get_charging_profiles.status":     "NoProfiles",
+	})
+}
+
+func TestGetChargingProfilesResultHandlerResendsProfilesNotInstalled(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	sendAfter := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+				SendAfter:              sendAfter,
+			},
+			{
+				ChargingProfileId:      2,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+				SendAfter:              sendAfter,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := ocpp201.GetChargingProfilesResultHandler{
+		Store: engine,
+	}
+
+	req := &types.GetChargingProfilesRequestJson{
+		RequestId: 1,
+		ChargingProfile: types.ChargingProfileCriterionType{
+			ChargingProfileId: []int{1},
+		},
+	}
+	resp := &types.GetChargingProfilesResponseJson{
+		Status: types.GetChargingProfileStatusEnumTypeNoProfiles,
+	}
+
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, profiles.ChargingProfiles, 2)
+	assert.Equal(t, store.ChargingProfileStatusPending, profiles.ChargingProfiles[0].Status)
+	assert.True(t, profiles.ChargingProfiles[0].SendAfter.IsZero())
+	assert.Equal(t, sendAfter, profiles.ChargingProfiles[1].SendAfter)
+}
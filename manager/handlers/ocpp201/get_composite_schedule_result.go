@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+type GetCompositeScheduleResultHandler struct {
+	Store store.ChargeStationChargingProfilesStore
+	Clock clock.PassiveClock
+}
+
+func (h GetCompositeScheduleResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.GetCompositeScheduleRequestJson)
+	resp := response.(*types.GetCompositeScheduleResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("get_composite_schedule.evse_id", req.EvseId),
+		attribute.String("get_composite_schedule.status", string(resp.Status)))
+
+	if resp.Status != types.GenericStatusEnumTypeAccepted {
+		return nil
+	}
+
+	compositeSchedule := &store.CompositeSchedule{
+		ConnectorId: req.EvseId,
+		RetrievedAt: h.Clock.Now(),
+	}
+
+	if resp.Schedule != nil {
+		scheduleStart, err := time.Parse(time.RFC3339, resp.Schedule.ScheduleStart)
+		if err != nil {
+			return fmt.Errorf("parsing schedule start: %w", err)
+		}
+		var periods []store.ChargingSchedulePeriod
+		for _, period := range resp.Schedule.ChargingSchedulePeriod {
+			periods = append(periods, store.ChargingSchedulePeriod{
+				StartPeriod:  period.StartPeriod,
+				Limit:        period.Limit,
+				NumberPhases: period.NumberPhases,
+			})
+		}
+		duration := resp.Schedule.Duration
+		compositeSchedule.ConnectorId = resp.Schedule.EvseId
+		compositeSchedule.ScheduleStart = &scheduleStart
+		compositeSchedule.ChargingSchedule = &store.ChargingSchedule{
+			Duration:                &duration,
+			StartSchedule:           &scheduleStart,
+			ChargingRateUnit:        store.ChargingRateUnit(resp.Schedule.ChargingRateUnit),
+			ChargingSchedulePeriods: periods,
+		}
+	}
+
+	return h.Store.SetChargeStationCompositeSchedule(ctx, chargeStationId, compositeSchedule)
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestGetCompositeScheduleResultHandler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	handler := ocpp201.GetCompositeScheduleResultHandler{
+		Store: engine,
+		Clock: clock,
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.GetCompositeScheduleRequestJson{
+			EvseId:   1,
+			Duration: 86400,
+		}
+		resp := &types.GetCompositeScheduleResponseJson{
+			Status: types.GenericStatusEnumTypeAccepted,
+			Schedule: &types.CompositeScheduleType{
+				ChargingRateUnit: types.ChargingRateUnitEnumTypeA,
+				ChargingSchedulePeriod: []types.ChargingSchedulePeriodType{
+					{
+						StartPeriod: 0,
+						Limit:       16.0,
+					},
+				},
+				Duration:      86400,
+				EvseId:        1,
+				ScheduleStart: "2024-03-18T12:00:00Z",
+			},
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"get_composite_schedule.evse_id": 1,
+		"get_composite_schedule.status":  "Accepted",
+	})
+
+	got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+	require.NoError(t, err)
+
+	duration := 86400
+	want := &store.CompositeSchedule{
+		ConnectorId:   1,
+		ScheduleStart: &now,
+		ChargingSchedule: &store.ChargingSchedule{
+			Duration:         &duration,
+			StartSchedule:    &now,
+			ChargingRateUnit: store.ChargingRateUnitA,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{
+					StartPeriod: 0,
+					Limit:       16.0,
+				},
+			},
+		},
+		RetrievedAt: now,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestGetCompositeScheduleResultHandlerIgnoresRejected(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := ocpp201.GetCompositeScheduleResultHandler{
+		Store: engine,
+		Clock: clock,
+	}
+
+	req := &types.GetCompositeScheduleRequestJson{
+		EvseId:   1,
+		Duration: 86400,
+	}
+	resp := &types.GetCompositeScheduleResponseJson{
+		Status: types.GenericStatusEnumTypeRejected,
+	}
+
+	err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -6,11 +6,15 @@ import (
 	"context"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
 )
 
-type MeterValuesHandler struct{}
+type MeterValuesHandler struct {
+	LoadBalancer services.LoadBalancer
+}
 
 func (h MeterValuesHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (response ocpp.Response, err error) {
 	req := request.(*ocpp201.MeterValuesRequestJson)
@@ -19,5 +23,13 @@ func (h MeterValuesHandler) HandleCall(ctx context.Context, chargeStationId stri
 
 	span.SetAttributes(attribute.Int("meter_values.evse_id", req.EvseId))
 
+	if h.LoadBalancer != nil {
+		err = h.LoadBalancer.Rebalance(ctx, chargeStationId)
+		if err != nil {
+			slog.Error("load balancing meter values", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId))
+		}
+	}
+
 	return &ocpp201.MeterValuesResponseJson{}, nil
 }
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type NotifyChargingLimitHandler struct{}
+
+func (h NotifyChargingLimitHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req := request.(*types.NotifyChargingLimitRequestJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("notify_charging_limit.source", string(req.ChargingLimit.ChargingLimitSource)),
+		attribute.Int("notify_charging_limit.schedules", len(req.ChargingSchedule)))
+	if req.ChargingLimit.IsGridCritical != nil {
+		span.SetAttributes(attribute.Bool("notify_charging_limit.grid_critical", *req.ChargingLimit.IsGridCritical))
+	}
+	if req.EvseId != nil {
+		span.SetAttributes(attribute.Int("notify_charging_limit.evse_id", *req.EvseId))
+	}
+
+	return &types.NotifyChargingLimitResponseJson{}, nil
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"testing"
+)
+
+func TestNotifyChargingLimit(t *testing.T) {
+	handler := ocpp201.NotifyChargingLimitHandler{}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.Background()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.NotifyChargingLimitRequestJson{
+			ChargingLimit: types.ChargingLimitType{
+				ChargingLimitSource: types.ChargingLimitSourceEnumTypeEMS,
+				IsGridCritical:      makePtr(true),
+			},
+			ChargingSchedule: []types.ChargingScheduleType{
+				{
+					ChargingRateUnit: types.ChargingRateUnitEnumTypeW,
+					ChargingSchedulePeriod: []types.ChargingSchedulePeriodType{
+						{
+							StartPeriod: 0,
+							Limit:       11000,
+						},
+					},
+					Id: 1,
+				},
+			},
+			EvseId: makePtr(1),
+		}
+
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+
+		assert.Equal(t, &types.NotifyChargingLimitResponseJson{}, resp)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"notify_charging_limit.source":        "EMS",
+		"notify_charging_limit.schedules":     1,
+		"notify_charging_limit.grid_critical": true,
+		"notify_charging_limit.evse_id":       1,
+	})
+}
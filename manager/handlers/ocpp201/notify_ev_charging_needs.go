@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// NotifyEVChargingNeedsHandler builds a charging schedule for the EV from the
+// charging needs it reported over ISO 15118. The schedule is stored as a pending
+// TxProfile for the ongoing transaction, which is then sent to the charge station
+// with a SetChargingProfile by the charging profile sync.
+type NotifyEVChargingNeedsHandler struct {
+	ChargingProfileStore store.ChargeStationChargingProfilesStore
+	TransactionStore     store.TransactionStore
+	Clock                clock.PassiveClock
+}
+
+func (h NotifyEVChargingNeedsHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req := request.(*types.NotifyEVChargingNeedsRequestJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("notify_ev_charging_needs.evse_id", req.EvseId),
+		attribute.String("notify_ev_charging_needs.requested_energy_transfer", string(req.ChargingNeeds.RequestedEnergyTransfer)))
+
+	schedule, reason := h.chargingSchedule(req)
+	if schedule == nil {
+		return rejectChargingNeeds(span, reason), nil
+	}
+
+	transactionId, err := h.ongoingTransactionId(ctx, chargeStationId, req.EvseId)
+	if err != nil {
+		return nil, err
+	}
+	if transactionId == nil {
+		return rejectChargingNeeds(span, "NoTransaction"), nil
+	}
+	span.SetAttributes(attribute.String("notify_ev_charging_needs.transaction_id", *transactionId))
+
+	profiles, err := h.ChargingProfileStore.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []*store.ChargingProfile
+	if profiles != nil {
+		existing = profiles.ChargingProfiles
+	}
+
+	schedule = capChargingSchedule(schedule, existing, req.EvseId)
+
+	profile := findTxProfile(existing, *transactionId)
+	if profile == nil {
+		profile = &store.ChargingProfile{
+			ChargingProfileId:      nextChargingProfileId(existing),
+			ConnectorId:            req.EvseId,
+			TransactionId:          transactionId,
+			ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+			ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+		}
+	}
+	profile.ChargingSchedule = *schedule
+	profile.Status = store.ChargingProfileStatusPending
+	profile.SendAfter = time.Time{}
+
+	err = h.ChargingProfileStore.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{profile},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storing charging profile: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("notify_ev_charging_needs.charging_profile_id", profile.ChargingProfileId),
+		attribute.String("notify_ev_charging_needs.status", string(types.NotifyEVChargingNeedsStatusEnumTypeAccepted)))
+
+	return &types.NotifyEVChargingNeedsResponseJson{
+		Status: types.NotifyEVChargingNeedsStatusEnumTypeAccepted,
+	}, nil
+}
+
+// chargingSchedule returns a schedule that allows the EV to charge at the maximum
+// rate it supports until its departure time, or the reason why no schedule could
+// be created
+func (h NotifyEVChargingNeedsHandler) chargingSchedule(req *types.NotifyEVChargingNeedsRequestJson) (*store.ChargingSchedule, string) {
+	needs := req.ChargingNeeds
+	now := h.Clock.Now().UTC()
+
+	schedule := &store.ChargingSchedule{
+		StartSchedule: &now,
+	}
+
+	var period store.ChargingSchedulePeriod
+	switch needs.RequestedEnergyTransfer {
+	case types.EnergyTransferModeEnumTypeDC:
+		if needs.DcChargingParameters == nil {
+			return nil, "MissingDCChargingParameters"
+		}
+		schedule.ChargingRateUnit = store.ChargingRateUnitW
+		if needs.DcChargingParameters.EvMaxPower != nil {
+			period.Limit = float64(*needs.DcChargingParameters.EvMaxPower)
+		} else {
+			period.Limit = float64(needs.DcChargingParameters.EvMaxCurrent * needs.DcChargingParameters.EvMaxVoltage)
+		}
+	case types.EnergyTransferModeEnumTypeACSinglePhase,
+		types.EnergyTransferModeEnumTypeACTwoPhase,
+		types.EnergyTransferModeEnumTypeACThreePhase:
+		if needs.AcChargingParameters == nil {
+			return nil, "MissingACChargingParameters"
+		}
+		schedule.ChargingRateUnit = store.ChargingRateUnitA
+		period.Limit = float64(needs.AcChargingParameters.EvMaxCurrent)
+		numberPhases := numberPhasesForEnergyTransfer(needs.RequestedEnergyTransfer)
+		period.NumberPhases = &numberPhases
+	default:
+		return nil, "UnsupportedEnergyTransfer"
+	}
+	schedule.ChargingSchedulePeriods = []store.ChargingSchedulePeriod{period}
+
+	if needs.DepartureTime != nil {
+		departureTime, err := time.Parse(time.RFC3339, *needs.DepartureTime)
+		if err != nil {
+			return nil, "InvalidDepartureTime"
+		}
+		duration := int(departureTime.Sub(now).Seconds())
+		if duration > 0 {
+			schedule.Duration = &duration
+		}
+	}
+
+	return schedule, ""
+}
+
+// ongoingTransactionId returns the id of the transaction in progress on the EVSE
+// that the EV is connected to, or nil if there isn't one
+func (h NotifyEVChargingNeedsHandler) ongoingTransactionId(ctx context.Context, chargeStationId string, evseId int) (*string, error) {
+	transaction, err := h.TransactionStore.FindOngoingTransaction(ctx, chargeStationId, evseId)
+	if err != nil {
+		return nil, fmt.Errorf("finding ongoing transaction: %w", err)
+	}
+	if transaction == nil {
+		return nil, nil
+	}
+	return &transaction.TransactionId, nil
+}
+
+// capChargingSchedule limits the schedule to the lowest limit set by the accepted
+// ChargePointMax and TxDefault profiles for the EVSE (or the whole charge station)
+// that use the same charging rate unit
+func capChargingSchedule(schedule *store.ChargingSchedule, profiles []*store.ChargingProfile, evseId int) *store.ChargingSchedule {
+	for _, profile := range profiles {
+		if profile.Status != store.ChargingProfileStatusAccepted {
+			continue
+		}
+		if profile.ChargingProfilePurpose != store.ChargingProfilePurposeChargePointMaxProfile &&
+			profile.ChargingProfilePurpose != store.ChargingProfilePurposeTxDefaultProfile {
+			continue
+		}
+		if profile.ConnectorId != 0 && profile.ConnectorId != evseId {
+			continue
+		}
+		if profile.ChargingSchedule.ChargingRateUnit != schedule.ChargingRateUnit {
+			continue
+		}
+		for _, limit := range profile.ChargingSchedule.ChargingSchedulePeriods {
+			for i := range schedule.ChargingSchedulePeriods {
+				if limit.Limit < schedule.ChargingSchedulePeriods[i].Limit {
+					schedule.ChargingSchedulePeriods[i].Limit = limit.Limit
+				}
+			}
+		}
+	}
+	return schedule
+}
+
+func findTxProfile(profiles []*store.ChargingProfile, transactionId string) *store.ChargingProfile {
+	for _, profile := range profiles {
+		if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile &&
+			profile.TransactionId != nil && *profile.TransactionId == transactionId {
+			return profile
+		}
+	}
+	return nil
+}
+
+func nextChargingProfileId(profiles []*store.ChargingProfile) int {
+	id := 1
+	for _, profile := range profiles {
+		if profile.ChargingProfileId >= id {
+			id = profile.ChargingProfileId + 1
+		}
+	}
+	return id
+}
+
+func numberPhasesForEnergyTransfer(mode types.EnergyTransferModeEnumType) int {
+	switch mode {
+	case types.EnergyTransferModeEnumTypeACTwoPhase:
+		return 2
+	case types.EnergyTransferModeEnumTypeACThreePhase:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func rejectChargingNeeds(span trace.Span, reason string) *types.NotifyEVChargingNeedsResponseJson {
+	span.SetAttributes(
+		attribute.String("notify_ev_charging_needs.status", string(types.NotifyEVChargingNeedsStatusEnumTypeRejected)),
+		attribute.String("notify_ev_charging_needs.reason", reason))
+
+	return &types.NotifyEVChargingNeedsResponseJson{
+		Status: types.NotifyEVChargingNeedsStatusEnumTypeRejected,
+		StatusInfo: &types.StatusInfoType{
+			ReasonCode: reason,
+		},
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestNotifyEVChargingNeedsHandler(t *testing.T) {
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	duration := 7200
+
+	testCases := []struct {
+		name             string
+		needs            types.ChargingNeedsType
+		existingProfiles []*store.ChargingProfile
+		expectedId       int
+		expectedSchedule store.ChargingSchedule
+	}{
+		{
+			name: "AC three phase",
+			needs: types.ChargingNeedsType{
+				RequestedEnergyTransfer: types.EnergyTransferModeEnumTypeACThreePhase,
+				DepartureTime:           makePtr("2024-03-18T14:00:00Z"),
+				AcChargingParameters: &types.ACChargingParametersType{
+					EnergyAmount: 20000,
+					EvMaxCurrent: 32,
+					EvMaxVoltage: 400,
+					EvMinCurrent: 6,
+				},
+			},
+			expectedId: 1,
+			expectedSchedule: store.ChargingSchedule{
+				Duration:         &duration,
+				StartSchedule:    &now,
+				ChargingRateUnit: store.ChargingRateUnitA,
+				ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+					{
+						StartPeriod:  0,
+						Limit:        32,
+						NumberPhases: makePtr(3),
+					},
+				},
+			},
+		},
+		{
+			name: "DC capped by charge station max profile",
+			needs: types.ChargingNeedsType{
+				RequestedEnergyTransfer: types.EnergyTransferModeEnumTypeDC,
+				DcChargingParameters: &types.DCChargingParametersType{
+					EvMaxCurrent: 200,
+					EvMaxVoltage: 500,
+				},
+			},
+			existingProfiles: []*store.ChargingProfile{
+				{
+					ChargingProfileId:      4,
+					ConnectorId:            0,
+					ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+					ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+					ChargingSchedule: store.ChargingSchedule{
+						ChargingRateUnit: store.ChargingRateUnitW,
+						ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+							{
+								StartPeriod: 0,
+								Limit:       50000,
+							},
+						},
+					},
+					Status: store.ChargingProfileStatusAccepted,
+				},
+			},
+			expectedId: 5,
+			expectedSchedule: store.ChargingSchedule{
+				StartSchedule:    &now,
+				ChargingRateUnit: store.ChargingRateUnitW,
+				ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+					{
+						StartPeriod: 0,
+						Limit:       50000,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := clockTest.NewFakePassiveClock(now)
+			engine := inmemory.NewStore(clock)
+
+			err := engine.CreateTransaction(ctx, "cs001", "tx001", 1, "MYRFIDTAG", "ISO14443", nil, 0, false)
+			require.NoError(t, err)
+			if tc.existingProfiles != nil {
+				err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+					ChargingProfiles: tc.existingProfiles,
+				})
+				require.NoError(t, err)
+			}
+
+			handler := ocpp201.NotifyEVChargingNeedsHandler{
+				ChargingProfileStore: engine,
+				TransactionStore:     engine,
+				Clock:                clock,
+			}
+
+			req := &types.NotifyEVChargingNeedsRequestJson{
+				EvseId:        1,
+				ChargingNeeds: tc.needs,
+			}
+
+			resp, err := handler.HandleCall(ctx, "cs001", req)
+			require.NoError(t, err)
+			assert.Equal(t, &types.NotifyEVChargingNeedsResponseJson{
+				Status: types.NotifyEVChargingNeedsStatusEnumTypeAccepted,
+			}, resp)
+
+			profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+			require.NoError(t, err)
+			require.NotNil(t, profiles)
+
+			var txProfile *store.ChargingProfile
+			for _, profile := range profiles.ChargingProfiles {
+				if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile {
+					txProfile = profile
+				}
+			}
+			require.NotNil(t, txProfile)
+
+			assert.Equal(t, tc.expectedId, txProfile.ChargingProfileId)
+			assert.Equal(t, 1, txProfile.ConnectorId)
+			assert.Equal(t, makePtr("tx001"), txProfile.TransactionId)
+			assert.Equal(t, store.ChargingProfileKindAbsolute, txProfile.ChargingProfileKind)
+			assert.Equal(t, store.ChargingProfileStatusPending, txProfile.Status)
+			assert.Equal(t, tc.expectedSchedule, txProfile.ChargingSchedule)
+		})
+	}
+}
+
+func TestNotifyEVChargingNeedsHandlerRejectsWithoutTransaction(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := ocpp201.NotifyEVChargingNeedsHandler{
+		ChargingProfileStore: engine,
+		TransactionStore:     engine,
+		Clock:                clock,
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.NotifyEVChargingNeedsRequestJson{
+			EvseId: 1,
+			ChargingNeeds: types.ChargingNeedsType{
+				RequestedEnergyTransfer: types.EnergyTransferModeEnumTypeACSinglePhase,
+				AcChargingParameters: &types.ACChargingParametersType{
+					EnergyAmount: 10000,
+					EvMaxCurrent: 16,
+					EvMaxVoltage: 230,
+					EvMinCurrent: 6,
+				},
+			},
+		}
+
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+		assert.Equal(t, &types.NotifyEVChargingNeedsResponseJson{
+			Status: types.NotifyEVChargingNeedsStatusEnumTypeRejected,
+			StatusInfo: &types.StatusInfoType{
+				ReasonCode: "NoTransaction",
+			},
+		}, resp)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"notify_ev_charging_needs.evse_id":                   1,
+		"notify_ev_charging_needs.requested_energy_transfer": "AC_single_phase",
+		"notify_ev_charging_needs.status":                    "Rejected",
+		"notify_ev_charging_needs.reason":                    "NoTransaction",
+	})
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, profiles)
+}
+
+func TestNotifyEVChargingNeedsHandlerUsesTransactionOnEvse(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	err := engine.CreateTransaction(ctx, "cs001", "tx001", 1, "MYRFIDTAG", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+	err = engine.CreateTransaction(ctx, "cs001", "tx002", 2, "OTHERTAG", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+
+	handler := ocpp201.NotifyEVChargingNeedsHandler{
+		ChargingProfileStore: engine,
+		TransactionStore:     engine,
+		Clock:                clock,
+	}
+
+	for _, evseId := range []int{2, 1} {
+		req := &types.NotifyEVChargingNeedsRequestJson{
+			EvseId: evseId,
+			ChargingNeeds: types.ChargingNeedsType{
+				RequestedEnergyTransfer: types.EnergyTransferModeEnumTypeACSinglePhase,
+				AcChargingParameters: &types.ACChargingParametersType{
+					EnergyAmount: 10000,
+					EvMaxCurrent: 16,
+					EvMaxVoltage: 230,
+					EvMinCurrent: 6,
+				},
+			},
+		}
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+		assert.Equal(t, &types.NotifyEVChargingNeedsResponseJson{
+			Status: types.NotifyEVChargingNeedsStatusEnumTypeAccepted,
+		}, resp)
+	}
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, profiles)
+
+	txProfiles := make(map[string]*store.ChargingProfile)
+	for _, profile := range profiles.ChargingProfiles {
+		if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile {
+			txProfiles[*profile.TransactionId] = profile
+		}
+	}
+	require.Len(t, txProfiles, 2)
+	assert.Equal(t, 1, txProfiles["tx001"].ConnectorId)
+	assert.Equal(t, 2, txProfiles["tx002"].ConnectorId)
+	assert.NotEqual(t, txProfiles["tx001"].ChargingProfileId, txProfiles["tx002"].ChargingProfileId)
+}
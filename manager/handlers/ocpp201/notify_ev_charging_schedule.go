@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type NotifyEVChargingScheduleHandler struct{}
+
+func (h NotifyEVChargingScheduleHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req := request.(*types.NotifyEVChargingScheduleRequestJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("notify_ev_charging_schedule.evse_id", req.EvseId),
+		attribute.String("notify_ev_charging_schedule.time_base", req.TimeBase),
+		attribute.String("notify_ev_charging_schedule.charging_rate_unit", string(req.ChargingSchedule.ChargingRateUnit)),
+		attribute.Int("notify_ev_charging_schedule.periods", len(req.ChargingSchedule.ChargingSchedulePeriod)))
+
+	return &types.NotifyEVChargingScheduleResponseJson{
+		Status: types.GenericStatusEnumTypeAccepted,
+	}, nil
+}
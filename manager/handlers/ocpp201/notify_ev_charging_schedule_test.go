@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"testing"
+)
+
+func TestNotifyEVChargingSchedule(t *testing.T) {
+	handler := ocpp201.NotifyEVChargingScheduleHandler{}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.Background()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.NotifyEVChargingScheduleRequestJson{
+			ChargingSchedule: types.ChargingScheduleType{
+				ChargingRateUnit: types.ChargingRateUnitEnumTypeA,
+				ChargingSchedulePeriod: []types.ChargingSchedulePeriodType{
+					{
+						StartPeriod: 0,
+						Limit:       32,
+					},
+					{
+						StartPeriod: 3600,
+						Limit:       16,
+					},
+				},
+				Id: 1,
+			},
+			EvseId:   1,
+			TimeBase: "2024-03-18T12:00:00Z",
+		}
+
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+
+		assert.Equal(t, &types.NotifyEVChargingScheduleResponseJson{
+			Status: types.GenericStatusEnumTypeAccepted,
+		}, resp)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"notify_ev_charging_schedule.evse_id":            1,
+		"notify_ev_charging_schedule.time_base":          "2024-03-18T12:00:00Z",
+		"notify_ev_charging_schedule.charging_rate_unit": "A",
+		"notify_ev_charging_schedule.periods":            2,
+	})
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReportChargingProfilesHandler handles the profiles reported by the charge station
+// in response to the GetChargingProfiles sent by the charging profile sync. A
+// reported profile that was set by the CSMS is installed, so any stored profile
+// with the same id that is still pending is marked as accepted.
+type ReportChargingProfilesHandler struct {
+	Store store.ChargeStationChargingProfilesStore
+}
+
+func (h ReportChargingProfilesHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req := request.(*types.ReportChargingProfilesRequestJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("report_charging_profiles.request_id", req.RequestId),
+		attribute.Int("report_charging_profiles.evse_id", req.EvseId),
+		attribute.String("report_charging_profiles.source", string(req.ChargingLimitSource)),
+		attribute.Int("report_charging_profiles.count", len(req.ChargingProfile)))
+	if req.Tbc != nil {
+		span.SetAttributes(attribute.Bool("report_charging_profiles.tbc", *req.Tbc))
+	}
+
+	if req.ChargingLimitSource != types.ChargingLimitSourceEnumTypeCSO {
+		return &types.ReportChargingProfilesResponseJson{}, nil
+	}
+
+	profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if profiles == nil {
+		return &types.ReportChargingProfilesResponseJson{}, nil
+	}
+
+	reported := make(map[int]bool)
+	for _, profile := range req.ChargingProfile {
+		reported[profile.Id] = true
+	}
+
+	var updated []*store.ChargingProfile
+	for _, profile := range profiles.ChargingProfiles {
+		if reported[profile.ChargingProfileId] && profile.Status == store.ChargingProfileStatusPending {
+			profile.Status = store.ChargingProfileStatusAccepted
+			updated = append(updated, profile)
+		}
+	}
+
+	if len(updated) > 0 {
+		err = h.Store.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: updated,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.ReportChargingProfilesResponseJson{}, nil
+}
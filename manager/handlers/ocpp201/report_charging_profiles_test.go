@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestReportChargingProfilesHandler(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ConnectorId:            1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+			},
+			{
+				ChargingProfileId:      2,
+				ConnectorId:            1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := ocpp201.ReportChargingProfilesHandler{
+		Store: engine,
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.ReportChargingProfilesRequestJson{
+			ChargingLimitSource: types.ChargingLimitSourceEnumTypeCSO,
+			ChargingProfile: []types.ChargingProfileType{
+				{
+					Id:                     1,
+					ChargingProfileKind:    types.ChargingProfileKindEnumTypeAbsolute,
+					ChargingProfilePurpose: types.ChargingProfilePurposeEnumTypeTxDefaultProfile,
+				},
+			},
+			EvseId:    1,
+			RequestId: 42,
+		}
+
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+
+		assert.Equal(t, &types.ReportChargingProfilesResponseJson{}, resp)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"report_charging_profiles.request_id": 42,
+		"report_charging_profiles.evse_id":    1,
+		"report_charging_profiles.source":     "CSO",
+		"report_charging_profiles.count":      1,
+	})
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, profiles)
+	require.Len(t, profiles.ChargingProfiles, 2)
+	assert.Equal(t, store.ChargingProfileStatusAccepted, profiles.ChargingProfiles[0].Status)
+	assert.Equal(t, store.ChargingProfileStatusPending, profiles.ChargingProfiles[1].Status)
+}
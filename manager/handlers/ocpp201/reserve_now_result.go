@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ReserveNowResultHandler struct{}
+
+func (h ReserveNowResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.ReserveNowRequestJson)
+	resp := response.(*ocpp201.ReserveNowResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("reserve_now.reservation_id", req.Id),
+		attribute.String("reserve_now.status", string(resp.Status)))
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"testing"
+)
+
+func TestReserveNowResult(t *testing.T) {
+	handler := ocpp201.ReserveNowResultHandler{}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.TODO()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.ReserveNowRequestJson{
+			Id:             42,
+			ExpiryDateTime: "2024-01-01T12:00:00Z",
+			IdToken: types.IdTokenType{
+				IdToken: "DEADBEEF",
+				Type:    types.IdTokenEnumTypeISO14443,
+			},
+		}
+
+		resp := &types.ReserveNowResponseJson{
+			Status: types.ReserveNowStatusEnumTypeAccepted,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"reserve_now.reservation_id": 42,
+		"reserve_now.status":         "Accepted",
+	})
+}
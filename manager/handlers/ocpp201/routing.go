@@ -18,28 +18,33 @@ import (
 func NewRouter(emitter transport.Emitter,
 	clk clock.PassiveClock,
 	engine store.Engine,
+	tokenAuthService services.TokenAuthService,
 	tariffService services.TariffService,
 	certValidationService services.CertificateValidationService,
 	chargeStationCertProvider services.ChargeStationCertificateProvider,
 	contractCertProvider services.ContractCertificateProvider,
+	loadBalancer services.LoadBalancer,
+	pendingCalls *handlers.PendingCalls,
+	sessionPublisher services.SessionPublisher,
+	evseStatusPublisher services.EvseStatusPublisher,
 	heartbeatInterval time.Duration,
 	schemaFS fs.FS) transport.MessageHandler {
 
+	standardCallMaker := NewCallMaker(emitter)
+	signedMeterValueService := services.BasicSignedMeterValueService{Store: engine}
+
 	return &handlers.Router{
-		Emitter:     emitter,
-		SchemaFS:    schemaFS,
-		OcppVersion: transport.OcppVersion201,
+		Emitter:      emitter,
+		SchemaFS:     schemaFS,
+		OcppVersion:  transport.OcppVersion201,
+		PendingCalls: pendingCalls,
 		CallRoutes: map[string]handlers.CallRoute{
 			"Authorize": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.AuthorizeRequestJson) },
 				RequestSchema:  "ocpp201/AuthorizeRequest.json",
 				ResponseSchema: "ocpp201/AuthorizeResponse.json",
 				Handler: AuthorizeHandler{
-					// PENDING: inject token auth service
-					TokenAuthService: &services.OcppTokenAuthService{
-						Clock:      clk,
-						TokenStore: engine,
-					},
+					TokenAuthService:             tokenAuthService,
 					CertificateValidationService: certValidationService,
 				},
 			},
@@ -53,6 +58,12 @@ func NewRouter(emitter transport.Emitter,
 					RuntimeDetailsStore: engine,
 				},
 			},
+			"ClearedChargingLimit": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ClearedChargingLimitRequestJson) },
+				RequestSchema:  "ocpp201/ClearedChargingLimitRequest.json",
+				ResponseSchema: "ocpp201/ClearedChargingLimitResponse.json",
+				Handler:        ClearedChargingLimitHandler{},
+			},
 			"FirmwareStatusNotification": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.FirmwareStatusNotificationRequestJson) },
 				RequestSchema:  "ocpp201/FirmwareStatusNotificationRequest.json",
@@ -93,7 +104,31 @@ func NewRouter(emitter transport.Emitter,
 				NewRequest:     func() ocpp.Request { return new(ocpp201.MeterValuesRequestJson) },
 				RequestSchema:  "ocpp201/MeterValuesRequest.json",
 				ResponseSchema: "ocpp201/MeterValuesResponse.json",
-				Handler:        MeterValuesHandler{},
+				Handler: MeterValuesHandler{
+					LoadBalancer: loadBalancer,
+				},
+			},
+			"NotifyChargingLimit": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.NotifyChargingLimitRequestJson) },
+				RequestSchema:  "ocpp201/NotifyChargingLimitRequest.json",
+				ResponseSchema: "ocpp201/NotifyChargingLimitResponse.json",
+				Handler:        NotifyChargingLimitHandler{},
+			},
+			"NotifyEVChargingNeeds": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.NotifyEVChargingNeedsRequestJson) },
+				RequestSchema:  "ocpp201/NotifyEVChargingNeedsRequest.json",
+				ResponseSchema: "ocpp201/NotifyEVChargingNeedsResponse.json",
+				Handler: NotifyEVChargingNeedsHandler{
+					ChargingProfileStore: engine,
+					TransactionStore:     engine,
+					Clock:                clk,
+				},
+			},
+			"NotifyEVChargingSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.NotifyEVChargingScheduleRequestJson) },
+				RequestSchema:  "ocpp201/NotifyEVChargingScheduleRequest.json",
+				ResponseSchema: "ocpp201/NotifyEVChargingScheduleResponse.json",
+				Handler:        NotifyEVChargingScheduleHandler{},
 			},
 			"NotifyReport": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.NotifyReportRequestJson) },
@@ -101,11 +136,23 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/NotifyReportResponse.json",
 				Handler:        NotifyReportHandler{},
 			},
+			"ReportChargingProfiles": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ReportChargingProfilesRequestJson) },
+				RequestSchema:  "ocpp201/ReportChargingProfilesRequest.json",
+				ResponseSchema: "ocpp201/ReportChargingProfilesResponse.json",
+				Handler: ReportChargingProfilesHandler{
+					Store: engine,
+				},
+			},
 			"StatusNotification": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.StatusNotificationRequestJson) },
 				RequestSchema:  "ocpp201/StatusNotificationRequest.json",
 				ResponseSchema: "ocpp201/StatusNotificationResponse.json",
-				Handler:        handlers.CallHandlerFunc(StatusNotificationHandler),
+				Handler: StatusNotificationHandler{
+					Store:               engine,
+					Clock:               clk,
+					EvseStatusPublisher: evseStatusPublisher,
+				},
 			},
 			"SignCertificate": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.SignCertificateRequestJson) },
@@ -128,15 +175,22 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/TransactionEventResponse.json",
 				Handler: TransactionEventHandler{
 					Store: engine,
-					TokenAuthService: &services.OcppTokenAuthService{
-						Clock:      clk,
-						TokenStore: engine,
-					},
+					TokenAuthService: tokenAuthService,
 					TariffService: tariffService,
+					LoadBalancer:  loadBalancer,
+					SignedMeterValueService: signedMeterValueService,
+					SessionPublisher: sessionPublisher,
 				},
 			},
 		},
 		CallResultRoutes: map[string]handlers.CallResultRoute{
+			"CancelReservation": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.CancelReservationRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.CancelReservationResponseJson) },
+				RequestSchema:  "ocpp201/CancelReservationRequest.json",
+				ResponseSchema: "ocpp201/CancelReservationResponse.json",
+				Handler:        CancelReservationResultHandler{},
+			},
 			"CertificateSigned": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.CertificateSignedRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.CertificateSignedResponseJson) },
@@ -160,6 +214,16 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/ClearCacheResponse.json",
 				Handler:        ClearCacheResultHandler{},
 			},
+			"ClearChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ClearChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ClearChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/ClearChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/ClearChargingProfileResponse.json",
+				Handler: ClearChargingProfileResultHandler{
+					Store:     engine,
+					CallMaker: standardCallMaker,
+				},
+			},
 			"DeleteCertificate": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.DeleteCertificateRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.DeleteCertificateResponseJson) },
@@ -174,6 +238,25 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/GetBaseReportResponse.json",
 				Handler:        GetBaseReportResultHandler{},
 			},
+			"GetChargingProfiles": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetChargingProfilesRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetChargingProfilesResponseJson) },
+				RequestSchema:  "ocpp201/GetChargingProfilesRequest.json",
+				ResponseSchema: "ocpp201/GetChargingProfilesResponse.json",
+				Handler: GetChargingProfilesResultHandler{
+					Store: engine,
+				},
+			},
+			"GetCompositeSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetCompositeScheduleRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetCompositeScheduleResponseJson) },
+				RequestSchema:  "ocpp201/GetCompositeScheduleRequest.json",
+				ResponseSchema: "ocpp201/GetCompositeScheduleResponse.json",
+				Handler: GetCompositeScheduleResultHandler{
+					Store: engine,
+					Clock: clk,
+				},
+			},
 			"GetInstalledCertificateIds": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.GetInstalledCertificateIdsRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.GetInstalledCertificateIdsResponseJson) },
@@ -232,6 +315,13 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/RequestStopTransactionResponse.json",
 				Handler:        RequestStopTransactionResultHandler{},
 			},
+			"ReserveNow": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ReserveNowRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ReserveNowResponseJson) },
+				RequestSchema:  "ocpp201/ReserveNowRequest.json",
+				ResponseSchema: "ocpp201/ReserveNowResponse.json",
+				Handler:        ReserveNowResultHandler{},
+			},
 			"Reset": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.ResetRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.ResetResponseJson) },
@@ -246,6 +336,16 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/SendLocalListResponse.json",
 				Handler:        SendLocalListResultHandler{},
 			},
+			"SetChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.SetChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SetChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/SetChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/SetChargingProfileResponse.json",
+				Handler: SetChargingProfileResultHandler{
+					Store:     engine,
+					CallMaker: standardCallMaker,
+				},
+			},
 			"SetNetworkProfile": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.SetNetworkProfileRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.SetNetworkProfileResponseJson) },
@@ -287,11 +387,15 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 		Emitter:     e,
 		OcppVersion: transport.OcppVersion201,
 		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp201.CancelReservationRequestJson{}):          "CancelReservation",
 			reflect.TypeOf(&ocpp201.CertificateSignedRequestJson{}):          "CertificateSigned",
 			reflect.TypeOf(&ocpp201.ChangeAvailabilityRequestJson{}):         "ChangeAvailability",
 			reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}):                 "ClearCache",
+			reflect.TypeOf(&ocpp201.ClearChargingProfileRequestJson{}):       "ClearChargingProfile",
 			reflect.TypeOf(&ocpp201.DeleteCertificateRequestJson{}):          "DeleteCertificate",
 			reflect.TypeOf(&ocpp201.GetBaseReportRequestJson{}):              "GetBaseReport",
+			reflect.TypeOf(&ocpp201.GetChargingProfilesRequestJson{}):        "GetChargingProfiles",
+			reflect.TypeOf(&ocpp201.GetCompositeScheduleRequestJson{}):       "GetCompositeSchedule",
 			reflect.TypeOf(&ocpp201.GetInstalledCertificateIdsRequestJson{}): "GetInstalledCertificateIds",
 			reflect.TypeOf(&ocpp201.GetLocalListVersionRequestJson{}):        "GetLocalListVersion",
 			reflect.TypeOf(&ocpp201.GetReportRequestJson{}):                  "GetReport",
@@ -300,8 +404,10 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 			reflect.TypeOf(&ocpp201.InstallCertificateRequestJson{}):         "InstallCertificate",
 			reflect.TypeOf(&ocpp201.RequestStartTransactionRequestJson{}):    "RequestStartTransaction",
 			reflect.TypeOf(&ocpp201.RequestStopTransactionRequestJson{}):     "RequestStopTransaction",
+			reflect.TypeOf(&ocpp201.ReserveNowRequestJson{}):                 "ReserveNow",
 			reflect.TypeOf(&ocpp201.ResetRequestJson{}):                      "Reset",
 			reflect.TypeOf(&ocpp201.SendLocalListRequestJson{}):              "SendLocalList",
+			reflect.TypeOf(&ocpp201.SetChargingProfileRequestJson{}):         "SetChargingProfile",
 			reflect.TypeOf(&ocpp201.SetNetworkProfileRequestJson{}):          "SetNetworkProfile",
 			reflect.TypeOf(&ocpp201.SetVariablesRequestJson{}):               "SetVariables",
 			reflect.TypeOf(&ocpp201.TriggerMessageRequestJson{}):             "TriggerMessage",
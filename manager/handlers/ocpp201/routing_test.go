@@ -41,7 +41,7 @@ func (f *fakeEmitter) Emit(ctx context.Context, ocppVersion transport.OcppVersio
 
 type fakeTariffService struct{}
 
-func (f fakeTariffService) CalculateCost(transaction *store.Transaction) (float64, error) {
+func (f fakeTariffService) CalculateCost(ctx context.Context, transaction *store.Transaction) (float64, error) {
 	return 42.0, nil
 }
 
@@ -80,10 +80,18 @@ func TestRoutingCalls(t *testing.T) {
 	router := ocpp201.NewRouter(&fakeEmitter{},
 		clock,
 		engine,
+		&services.OcppTokenAuthService{
+			Clock:      clock,
+			TokenStore: engine,
+		},
 		&fakeTariffService{},
 		&fakeCertValidationService{},
 		&fakeChargeStationCertProvider{},
 		&fakeContractCertProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
 		5*time.Minute,
 		schemas.OcppSchemas,
 	)
@@ -229,10 +237,18 @@ func TestRoutingCallResults(t *testing.T) {
 	router := ocpp201.NewRouter(&fakeEmitter{},
 		clock,
 		engine,
+		&services.OcppTokenAuthService{
+			Clock:      clock,
+			TokenStore: engine,
+		},
 		&fakeTariffService{},
 		&fakeCertValidationService{},
 		&fakeChargeStationCertProvider{},
 		&fakeContractCertProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
 		5*time.Minute,
 		schemas.OcppSchemas,
 	)
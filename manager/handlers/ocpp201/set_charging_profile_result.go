@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// compositeScheduleDuration is the period (in seconds) requested when
+// refreshing the composite schedule of an EVSE
+const compositeScheduleDuration = 86400
+
+type SetChargingProfileResultHandler struct {
+	Store     store.ChargeStationChargingProfilesStore
+	CallMaker handlers.CallMaker
+}
+
+func (h SetChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*types.SetChargingProfileRequestJson)
+	resp := response.(*types.SetChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("set_charging_profile.evse_id", req.EvseId),
+		attribute.Int("set_charging_profile.charging_profile_id", req.ChargingProfile.Id),
+		attribute.String("set_charging_profile.purpose", string(req.ChargingProfile.ChargingProfilePurpose)),
+		attribute.String("set_charging_profile.status", string(resp.Status)))
+	if resp.StatusInfo != nil {
+		span.SetAttributes(attribute.String("set_charging_profile.reason_code", resp.StatusInfo.ReasonCode))
+	}
+
+	profiles, err := h.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+
+	var profile *store.ChargingProfile
+	if profiles != nil {
+		for _, p := range profiles.ChargingProfiles {
+			if p.ChargingProfileId == req.ChargingProfile.Id {
+				profile = p
+				break
+			}
+		}
+	}
+
+	// a profile that has been removed from the store (or was never stored)
+	// is not something we are managing so there is nothing to update
+	if profile != nil && profile.Status == store.ChargingProfileStatusPending {
+		switch resp.Status {
+		case types.ChargingProfileStatusEnumTypeAccepted:
+			profile.Status = store.ChargingProfileStatusAccepted
+		case types.ChargingProfileStatusEnumTypeRejected:
+			profile.Status = store.ChargingProfileStatusRejected
+		}
+
+		err = h.Store.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{profile},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if resp.Status == types.ChargingProfileStatusEnumTypeAccepted {
+		return h.CallMaker.Send(ctx, chargeStationId, &types.GetCompositeScheduleRequestJson{
+			EvseId:   req.EvseId,
+			Duration: compositeScheduleDuration,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+type mockCallMaker struct {
+	calls []struct {
+		chargeStationId string
+		request         ocpp.Request
+	}
+}
+
+func (m *mockCallMaker) Send(ctx context.Context, chargeStationId string, request ocpp.Request) error {
+	m.calls = append(m.calls, struct {
+		chargeStationId string
+		request         ocpp.Request
+	}{chargeStationId: chargeStationId, request: request})
+	return nil
+}
+
+func TestSetChargingProfileResultHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         types.ChargingProfileStatusEnumType
+		expectedStatus store.ChargingProfileStatus
+		expectRefresh  bool
+	}{
+		{
+			name:           "Accepted",
+			status:         types.ChargingProfileStatusEnumTypeAccepted,
+			expectedStatus: store.ChargingProfileStatusAccepted,
+			expectRefresh:  true,
+		},
+		{
+			name:           "Rejected",
+			status:         types.ChargingProfileStatusEnumTypeRejected,
+			expectedStatus: store.ChargingProfileStatusRejected,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			engine := inmemory.NewStore(clock.RealClock{})
+
+			err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+				ChargingProfiles: []*store.ChargingProfile{
+					{
+						ChargingProfileId:      5,
+						ConnectorId:            2,
+						ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+						ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+						Status:                 store.ChargingProfileStatusPending,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			callMaker := &mockCallMaker{}
+			handler := ocpp201.SetChargingProfileResultHandler{
+				Store:     engine,
+				CallMaker: callMaker,
+			}
+
+			tracer, exporter := testutil.GetTracer()
+
+			func() {
+				ctx, span := tracer.Start(ctx, "test")
+				defer span.End()
+
+				req := &types.SetChargingProfileRequestJson{
+					EvseId: 2,
+					ChargingProfile: types.ChargingProfileType{
+						Id:                     5,
+						ChargingProfileKind:    types.ChargingProfileKindEnumTypeAbsolute,
+						ChargingProfilePurpose: types.ChargingProfilePurposeEnumTypeTxDefaultProfile,
+					},
+				}
+				resp := &types.SetChargingProfileResponseJson{
+					Status: tc.status,
+				}
+
+				err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+				require.NoError(t, err)
+			}()
+
+			testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+				"set_charging_profile.evse_id":             2,
+				"set_charging_profile.charging_profile_id": 5,
+				"set_charging_profile.purpose":             "TxDefaultProfile",
+				"set_charging_profile.status":              string(tc.status),
+			})
+
+			profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+			require.NoError(t, err)
+			require.NotNil(t, profiles)
+			require.Len(t, profiles.ChargingProfiles, 1)
+			assert.Equal(t, tc.expectedStatus, profiles.ChargingProfiles[0].Status)
+
+			if tc.expectRefresh {
+				require.Len(t, callMaker.calls, 1)
+				assert.Equal(t, "cs001", callMaker.calls[0].chargeStationId)
+				assert.Equal(t, &types.GetCompositeScheduleRequestJson{
+					EvseId:   2,
+					Duration: 86400,
+				}, callMaker.calls[0].request)
+			} else {
+				assert.Len(t, callMaker.calls, 0)
+			}
+		})
+	}
+}
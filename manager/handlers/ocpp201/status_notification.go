@@ -4,14 +4,27 @@ package ocpp201
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
 )
 
-func StatusNotificationHandler(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+type StatusNotificationHandler struct {
+	Store               store.ChargeStationConnectorStatusStore
+	Clock               clock.PassiveClock
+	EvseStatusPublisher services.EvseStatusPublisher
+}
+
+func (h StatusNotificationHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
 	span := trace.SpanFromContext(ctx)
 
 	req := request.(*types.StatusNotificationRequestJson)
@@ -21,5 +34,28 @@ func StatusNotificationHandler(ctx context.Context, chargeStationId string, requ
 		attribute.Int("status.connector_id", req.ConnectorId),
 		attribute.String("status.connector_status", string(req.ConnectorStatus)))
 
+	timestamp, err := time.Parse(time.RFC3339, req.Timestamp)
+	if err != nil {
+		timestamp = h.Clock.Now()
+	}
+
+	err = h.Store.SetChargeStationConnectorStatus(ctx, chargeStationId, &store.ConnectorStatus{
+		EvseId:      req.EvseId,
+		ConnectorId: req.ConnectorId,
+		Status:      string(req.ConnectorStatus),
+		Timestamp:   timestamp.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("setting connector status: %w", err)
+	}
+
+	if h.EvseStatusPublisher != nil {
+		err = h.EvseStatusPublisher.PushEvseStatus(ctx, chargeStationId)
+		if err != nil {
+			slog.Error("publishing evse status", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId))
+		}
+	}
+
 	return &types.StatusNotificationResponseJson{}, nil
 }
@@ -4,13 +4,28 @@ package ocpp201_test
 
 import (
 	"context"
+	"testing"
+	"time"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	handlers "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
-	"testing"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	clockTest "k8s.io/utils/clock/testing"
 )
 
 func TestStatusNotificationHandler(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	handler := handlers.StatusNotificationHandler{
+		Store: engine,
+		Clock: clock,
+	}
+
 	req := &types.StatusNotificationRequestJson{
 		Timestamp:       "2023-05-01T01:00:00+01:00",
 		EvseId:          1,
@@ -18,10 +33,53 @@ func TestStatusNotificationHandler(t *testing.T) {
 		ConnectorStatus: types.ConnectorStatusEnumTypeOccupied,
 	}
 
-	got, err := handlers.StatusNotificationHandler(context.Background(), "cs001", req)
+	got, err := handler.HandleCall(ctx, "cs001", req)
 	assert.NoError(t, err)
 
 	want := &types.StatusNotificationResponseJson{}
 
 	assert.Equal(t, want, got)
+
+	status, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, &store.ConnectorStatus{
+		EvseId:      1,
+		ConnectorId: 2,
+		Status:      "Occupied",
+		Timestamp:   time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+	}, status)
+}
+
+type recordingEvseStatusPublisher struct {
+	pushed []string
+}
+
+func (r *recordingEvseStatusPublisher) PushEvseStatus(_ context.Context, chargeStationId string) error {
+	r.pushed = append(r.pushed, chargeStationId)
+	return nil
+}
+
+func TestStatusNotificationHandlerPublishesEvseStatus(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	evseStatusPublisher := &recordingEvseStatusPublisher{}
+	handler := handlers.StatusNotificationHandler{
+		Store:               engine,
+		Clock:               clock,
+		EvseStatusPublisher: evseStatusPublisher,
+	}
+
+	req := &types.StatusNotificationRequestJson{
+		Timestamp:       "2023-05-01T01:00:00+01:00",
+		EvseId:          1,
+		ConnectorId:     1,
+		ConnectorStatus: types.ConnectorStatusEnumTypeAvailable,
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cs001"}, evseStatusPublisher.pushed)
 }
@@ -4,6 +4,8 @@ package ocpp201
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/zynka-tech/zynka-csms/manager/ocpp"
 	types "github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
@@ -16,6 +18,11 @@ type TransactionEventHandler struct {
 	Store            store.Engine
 	TokenAuthService services.TokenAuthService
 	TariffService    services.TariffService
+	LoadBalancer     services.LoadBalancer
+	// SignedMeterValueService verifies signed meter values (optional)
+	SignedMeterValueService services.SignedMeterValueService
+	// SessionPublisher informs roaming partners of the transaction (optional)
+	SessionPublisher services.SessionPublisher
 }
 
 func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -32,20 +39,33 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 	if req.IdToken != nil {
 		idToken = req.IdToken.IdToken
 		tokenType = string(req.IdToken.Type)
-		idTokenInfo := t.TokenAuthService.Authorize(ctx, *req.IdToken)
+		idTokenInfo := t.TokenAuthService.Authorize(ctx, chargeStationId, *req.IdToken)
 		response.IdTokenInfo = &idTokenInfo
 	}
 
+	meterValues := convertMeterValues(req.MeterValue)
+	if t.SignedMeterValueService != nil {
+		err := t.SignedMeterValueService.VerifyMeterValues(ctx, chargeStationId, meterValues)
+		if err != nil {
+			return nil, fmt.Errorf("verifying signed meter values: %w", err)
+		}
+	}
+
 	var err error
 	switch req.EventType {
 	case types.TransactionEventEnumTypeStarted:
+		var evseId int
+		if req.Evse != nil {
+			evseId = req.Evse.Id
+		}
 		err = t.Store.CreateTransaction(
 			ctx,
 			chargeStationId,
 			req.TransactionInfo.TransactionId,
+			evseId,
 			idToken,
 			tokenType,
-			convertMeterValues(req.MeterValue),
+			meterValues,
 			req.SeqNo,
 			req.Offline)
 	case types.TransactionEventEnumTypeUpdated:
@@ -53,7 +73,7 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 			ctx,
 			chargeStationId,
 			req.TransactionInfo.TransactionId,
-			convertMeterValues(req.MeterValue))
+			meterValues)
 	case types.TransactionEventEnumTypeEnded:
 		err = t.Store.EndTransaction(
 			ctx,
@@ -61,7 +81,7 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 			req.TransactionInfo.TransactionId,
 			idToken,
 			tokenType,
-			convertMeterValues(req.MeterValue),
+			meterValues,
 			req.SeqNo)
 	}
 
@@ -69,12 +89,25 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 		return nil, err
 	}
 
+	if t.LoadBalancer != nil {
+		err = t.updateLoadBalancer(ctx, chargeStationId, req)
+		if err != nil {
+			slog.Error("load balancing transaction event", slog.String("err", err.Error()),
+				slog.String("chargeStationId", chargeStationId),
+				slog.String("transactionId", req.TransactionInfo.TransactionId))
+		}
+	}
+
+	if t.SessionPublisher != nil {
+		t.publishSession(ctx, chargeStationId, req)
+	}
+
 	if req.EventType == types.TransactionEventEnumTypeEnded {
 		transaction, err := t.Store.FindTransaction(ctx, chargeStationId, req.TransactionInfo.TransactionId)
 		if err != nil {
 			return nil, err
 		}
-		cost, err := t.TariffService.CalculateCost(transaction)
+		cost, err := t.TariffService.CalculateCost(ctx, transaction)
 		if err != nil {
 			slog.Error("error calculating tariff", "err", err)
 		} else {
@@ -86,6 +119,20 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 	return response, nil
 }
 
+// updateLoadBalancer informs the load balancer of the transaction event: a
+// transaction is only added once the EVSE is known as the profile that limits it
+// must be installed on that EVSE
+func (t TransactionEventHandler) updateLoadBalancer(ctx context.Context, chargeStationId string, req *types.TransactionEventRequestJson) error {
+	transactionId := req.TransactionInfo.TransactionId
+	if req.EventType == types.TransactionEventEnumTypeEnded {
+		return t.LoadBalancer.TransactionEnded(ctx, chargeStationId, transactionId)
+	}
+	if req.Evse != nil {
+		return t.LoadBalancer.TransactionStarted(ctx, chargeStationId, req.Evse.Id, transactionId)
+	}
+	return t.LoadBalancer.Rebalance(ctx, chargeStationId)
+}
+
 func convertMeterValues(meterValues []types.MeterValueType) []store.MeterValue {
 	var converted []store.MeterValue
 	for _, meterValue := range meterValues {
@@ -111,12 +158,29 @@ func convertSampledValues(sampledValues []types.SampledValueType) []store.Sample
 
 func convertSampledValue(sampledValue types.SampledValueType) store.SampledValue {
 	return store.SampledValue{
-		Context:       (*string)(sampledValue.Context),
-		Location:      (*string)(sampledValue.Location),
-		Measurand:     (*string)(sampledValue.Measurand),
-		Phase:         (*string)(sampledValue.Phase),
-		UnitOfMeasure: convertUnitOfMeasure(sampledValue.UnitOfMeasure),
-		Value:         sampledValue.Value,
+		Context:          (*string)(sampledValue.Context),
+		Location:         (*string)(sampledValue.Location),
+		Measurand:        (*string)(sampledValue.Measurand),
+		Phase:            (*string)(sampledValue.Phase),
+		UnitOfMeasure:    convertUnitOfMeasure(sampledValue.UnitOfMeasure),
+		Value:            sampledValue.Value,
+		SignedMeterValue: convertSignedMeterValue(sampledValue.SignedMeterValue),
+	}
+}
+
+// convertSignedMeterValue records the signed data exactly as reported. The public
+// key the charge station may include is ignored: signed meter values are only
+// verified against the meter public keys registered for the charge station.
+func convertSignedMeterValue(signedMeterValue *types.SignedMeterValueType) *store.SignedMeterValue {
+	if signedMeterValue == nil {
+		return nil
+	}
+
+	return &store.SignedMeterValue{
+		SignedMeterData: signedMeterValue.SignedMeterData,
+		EncodingMethod:  signedMeterValue.EncodingMethod,
+		SigningMethod:   signedMeterValue.SigningMethod,
+		Status:          store.SignedMeterValueStatusUnverified,
 	}
 }
 
@@ -130,3 +194,28 @@ func convertUnitOfMeasure(unitOfMeasure *types.UnitOfMeasureType) *store.UnitOfM
 		Multipler: unitOfMeasure.Multiplier,
 	}
 }
+
+// publishSession sends the session for the transaction to roaming partners: the
+// complete session when the transaction starts or ends and only the changes for
+// other events. The CDR is sent once the transaction has ended. Errors are only
+// logged: a roaming partner that cannot be reached must not stop the charge
+// station from charging.
+func (t TransactionEventHandler) publishSession(ctx context.Context, chargeStationId string, req *types.TransactionEventRequestJson) {
+	transactionId := req.TransactionInfo.TransactionId
+	transaction, err := t.Store.FindTransaction(ctx, chargeStationId, transactionId)
+	if err == nil && transaction != nil {
+		switch req.EventType {
+		case types.TransactionEventEnumTypeUpdated:
+			err = t.SessionPublisher.PushSessionUpdate(ctx, transaction)
+		case types.TransactionEventEnumTypeEnded:
+			err = errors.Join(t.SessionPublisher.PushSession(ctx, transaction),
+				t.SessionPublisher.PushCdr(ctx, transaction))
+		default:
+			err = t.SessionPublisher.PushSession(ctx, transaction)
+		}
+	}
+	if err != nil {
+		slog.Error("publishing session", slog.String("err", err.Error()),
+			slog.String("chargeStationId", chargeStationId), slog.String("transactionId", transactionId))
+	}
+}
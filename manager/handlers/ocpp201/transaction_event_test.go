@@ -4,6 +4,8 @@ package ocpp201_test
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"k8s.io/utils/clock"
 	"testing"
@@ -240,3 +242,256 @@ func TestTransactionEventHandlerWithEndedEvent(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, transaction)
 }
+
+type recordingLoadBalancer struct {
+	events []string
+}
+
+func (r *recordingLoadBalancer) TransactionStarted(_ context.Context, chargeStationId string, evseId int, transactionId string) error {
+	r.events = append(r.events, fmt.Sprintf("started %s %d %s", chargeStationId, evseId, transactionId))
+	return nil
+}
+
+func (r *recordingLoadBalancer) TransactionEnded(_ context.Context, chargeStationId, transactionId string) error {
+	r.events = append(r.events, fmt.Sprintf("ended %s %s", chargeStationId, transactionId))
+	return nil
+}
+
+func (r *recordingLoadBalancer) Rebalance(_ context.Context, chargeStationId string) error {
+	r.events = append(r.events, fmt.Sprintf("rebalance %s", chargeStationId))
+	return nil
+}
+
+func TestTransactionEventHandlerUpdatesLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	loadBalancer := &recordingLoadBalancer{}
+
+	handler := handlers.TransactionEventHandler{
+		Store: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
+		TariffService: services.BasicKwhTariffService{},
+		LoadBalancer:  loadBalancer,
+	}
+
+	events := []*types.TransactionEventRequestJson{
+		{
+			EventType:     types.TransactionEventEnumTypeStarted,
+			TriggerReason: types.TriggerReasonEnumTypeCablePluggedIn,
+			Timestamp:     "2023-05-05T12:00:00+01:00",
+			SeqNo:         0,
+			TransactionInfo: types.TransactionType{
+				TransactionId: "5555",
+			},
+		},
+		{
+			EventType:     types.TransactionEventEnumTypeUpdated,
+			TriggerReason: types.TriggerReasonEnumTypeChargingStateChanged,
+			Timestamp:     "2023-05-05T12:01:00+01:00",
+			SeqNo:         1,
+			Evse: &types.EVSEType{
+				Id: 2,
+			},
+			TransactionInfo: types.TransactionType{
+				TransactionId: "5555",
+			},
+		},
+		{
+			EventType:     types.TransactionEventEnumTypeEnded,
+			TriggerReason: types.TriggerReasonEnumTypeEVDeparted,
+			Timestamp:     "2023-05-05T13:00:00+01:00",
+			SeqNo:         2,
+			TransactionInfo: types.TransactionType{
+				TransactionId: "5555",
+			},
+		},
+	}
+
+	for _, event := range events {
+		_, err := handler.HandleCall(ctx, "cs001", event)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"rebalance cs001",
+		"started cs001 2 5555",
+		"ended cs001 5555",
+	}, loadBalancer.events)
+}
+
+func TestTransactionEventHandlerRecordsSignedMeterValues(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.TransactionEventHandler{
+		Store: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
+		TariffService:           services.BasicKwhTariffService{},
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	signedMeterData := base64.StdEncoding.EncodeToString([]byte(`OCMF|{"FV":"1.0","RD":[{"RV":0.1,"RU":"kWh"}]}|{"SD":"00"}`))
+	req := &types.TransactionEventRequestJson{
+		EventType:     types.TransactionEventEnumTypeStarted,
+		TriggerReason: types.TriggerReasonEnumTypeCablePluggedIn,
+		Timestamp:     "2023-05-05T12:00:00+01:00",
+		MeterValue: []types.MeterValueType{
+			{
+				Timestamp: "2023-05-05T12:00:00+01:00",
+				SampledValue: []types.SampledValueType{
+					{
+						Measurand: makePtr(types.MeasurandEnumTypeEnergyActiveImportRegister),
+						Location:  makePtr(types.LocationEnumTypeOutlet),
+						Value:     100,
+						SignedMeterValue: &types.SignedMeterValueType{
+							EncodingMethod:  "OCMF",
+							SigningMethod:   "ECDSA-secp256r1-SHA256",
+							SignedMeterData: signedMeterData,
+						},
+					},
+				},
+			},
+		},
+		SeqNo: 0,
+		TransactionInfo: types.TransactionType{
+			TransactionId: "5555",
+		},
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	transaction, err := engine.FindTransaction(ctx, "cs001", "5555")
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	want := &store.SignedMeterValue{
+		SignedMeterData: signedMeterData,
+		EncodingMethod:  "OCMF",
+		SigningMethod:   "ECDSA-secp256r1-SHA256",
+		Status:          store.SignedMeterValueStatusNoPublicKey,
+	}
+	assert.Equal(t, want, transaction.MeterValues[0].SampledValues[0].SignedMeterValue)
+}
+
+func TestTransactionEventHandlerRecordsSignedMeterValuesForUpdatedEvent(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.TransactionEventHandler{
+		Store: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
+		TariffService:           services.BasicKwhTariffService{},
+		SignedMeterValueService: services.BasicSignedMeterValueService{Store: engine},
+	}
+
+	signedMeterData := base64.StdEncoding.EncodeToString([]byte(`OCMF|{"FV":"1.0","RD":[{"RV":0.2,"RU":"kWh"}]}|{"SD":"00"}`))
+	req := &types.TransactionEventRequestJson{
+		EventType:     types.TransactionEventEnumTypeUpdated,
+		TriggerReason: types.TriggerReasonEnumTypeMeterValuePeriodic,
+		Timestamp:     "2023-05-05T12:05:00+01:00",
+		MeterValue: []types.MeterValueType{
+			{
+				Timestamp: "2023-05-05T12:05:00+01:00",
+				SampledValue: []types.SampledValueType{
+					{
+						Measurand: makePtr(types.MeasurandEnumTypeEnergyActiveImportRegister),
+						Location:  makePtr(types.LocationEnumTypeOutlet),
+						Value:     200,
+						SignedMeterValue: &types.SignedMeterValueType{
+							EncodingMethod:  "OCMF",
+							SigningMethod:   "ECDSA-secp256r1-SHA256",
+							SignedMeterData: signedMeterData,
+						},
+					},
+				},
+			},
+		},
+		SeqNo: 1,
+		TransactionInfo: types.TransactionType{
+			TransactionId: "5555",
+		},
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	transaction, err := engine.FindTransaction(ctx, "cs001", "5555")
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	require.Len(t, transaction.MeterValues, 1)
+	want := &store.SignedMeterValue{
+		SignedMeterData: signedMeterData,
+		EncodingMethod:  "OCMF",
+		SigningMethod:   "ECDSA-secp256r1-SHA256",
+		Status:          store.SignedMeterValueStatusNoPublicKey,
+	}
+	assert.Equal(t, want, transaction.MeterValues[0].SampledValues[0].SignedMeterValue)
+}
+
+type recordingSessionPublisher struct {
+	events []string
+}
+
+func (r *recordingSessionPublisher) PushSession(_ context.Context, transaction *store.Transaction) error {
+	r.events = append(r.events, fmt.Sprintf("push %s %s", transaction.ChargeStationId, transaction.TransactionId))
+	return nil
+}
+
+func (r *recordingSessionPublisher) PushSessionUpdate(_ context.Context, transaction *store.Transaction) error {
+	r.events = append(r.events, fmt.Sprintf("update %s %s", transaction.ChargeStationId, transaction.TransactionId))
+	return nil
+}
+
+func (r *recordingSessionPublisher) PushCdr(_ context.Context, transaction *store.Transaction) error {
+	r.events = append(r.events, fmt.Sprintf("cdr %s %s", transaction.ChargeStationId, transaction.TransactionId))
+	return nil
+}
+
+func TestTransactionEventHandlerPublishesSession(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	sessionPublisher := &recordingSessionPublisher{}
+
+	handler := handlers.TransactionEventHandler{
+		Store: engine,
+		TokenAuthService: &services.OcppTokenAuthService{
+			Clock:      clock.RealClock{},
+			TokenStore: engine,
+		},
+		TariffService:    services.BasicKwhTariffService{},
+		SessionPublisher: sessionPublisher,
+	}
+
+	for seqNo, eventType := range []types.TransactionEventEnumType{
+		types.TransactionEventEnumTypeStarted,
+		types.TransactionEventEnumTypeUpdated,
+		types.TransactionEventEnumTypeEnded,
+	} {
+		_, err := handler.HandleCall(ctx, "cs001", &types.TransactionEventRequestJson{
+			EventType:     eventType,
+			TriggerReason: types.TriggerReasonEnumTypeMeterValuePeriodic,
+			Timestamp:     "2023-05-05T12:00:00+01:00",
+			SeqNo:         seqNo,
+			TransactionInfo: types.TransactionType{
+				TransactionId: "5555",
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"push cs001 5555",
+		"update cs001 5555",
+		"push cs001 5555",
+		"cdr cs001 5555",
+	}, sessionPublisher.events)
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"golang.org/x/exp/slog"
+)
+
+type pendingCallKey struct {
+	chargeStationId string
+	messageId       string
+}
+
+// PendingCalls correlates the CallResult (or CallError) received from a charge
+// station with the call that was made by the CSMS so that the caller can wait
+// for the outcome of the call. The same PendingCalls must be used by the
+// OcppCallMaker that makes the call and the Router that receives the response.
+//
+// When the messages from the gateway are shared between several manager
+// instances the response may be received by an instance other than the one
+// that made the call. The message id of each call that waits for a response
+// is tagged with the id of the instance that made it and the Broadcaster is
+// then used to send the responses to the calls of other instances to all the
+// instances: each instance must connect the PendingCalls to a
+// transport.BroadcastListener so that it receives them.
+type PendingCalls struct {
+	instanceId string
+	mu         sync.Mutex
+	pending    map[pendingCallKey]chan *transport.Message
+	// duplicates are the message ids of the calls that the CallQueue did not
+	// queue as they are the same as a queued call, indexed by the queued call
+	duplicates  map[pendingCallKey][]string
+	broadcaster transport.Broadcaster
+}
+
+func NewPendingCalls() *PendingCalls {
+	return &PendingCalls{
+		instanceId: strings.ReplaceAll(uuid.New().String(), "-", "")[:8],
+		pending:    make(map[pendingCallKey]chan *transport.Message),
+		duplicates: make(map[pendingCallKey][]string),
+	}
+}
+
+// NewBroadcastPendingCalls creates a PendingCalls for a manager instance that
+// shares the messages from the gateway with other instances.
+func NewBroadcastPendingCalls(broadcaster transport.Broadcaster) *PendingCalls {
+	p := NewPendingCalls()
+	p.broadcaster = broadcaster
+	return p
+}
+
+// newMessageId returns the message id for a call that waits for a response in
+// the form <instanceId>:<random>. OCPP limits message ids to 36 characters so
+// only part of a uuid is used.
+func (p *PendingCalls) newMessageId() string {
+	id := uuid.New()
+	return p.instanceId + ":" + hex.EncodeToString(id[:12])
+}
+
+// add registers a call that is waiting for a response. The returned channel
+// receives the response message.
+func (p *PendingCalls) add(chargeStationId, messageId string) <-chan *transport.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch := make(chan *transport.Message, 1)
+	p.pending[pendingCallKey{chargeStationId, messageId}] = ch
+	return ch
+}
+
+// remove stops waiting for a response.
+func (p *PendingCalls) remove(chargeStationId, messageId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, pendingCallKey{chargeStationId, messageId})
+}
+
+// addDuplicate registers a call that is waiting for the response to another
+// call with the same request: the response to the queued call is delivered to
+// both calls.
+func (p *PendingCalls) addDuplicate(chargeStationId, messageId, queuedMessageId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := pendingCallKey{chargeStationId, queuedMessageId}
+	p.duplicates[key] = append(p.duplicates[key], messageId)
+}
+
+// removeDuplicates stops waiting for the response to a call that will not be
+// answered on behalf of the calls that are the same as it.
+func (p *PendingCalls) removeDuplicates(chargeStationId, queuedMessageId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.duplicates, pendingCallKey{chargeStationId, queuedMessageId})
+}
+
+// Resolve delivers a CallResult or CallError message to the call waiting for
+// it and to any calls that are the same as it. It returns false if no call is
+// waiting for the message.
+func (p *PendingCalls) Resolve(chargeStationId string, msg *transport.Message) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := pendingCallKey{chargeStationId, msg.MessageId}
+	resolved := p.resolve(key, msg)
+	for _, messageId := range p.duplicates[key] {
+		duplicate := *msg
+		duplicate.MessageId = messageId
+		if p.resolve(pendingCallKey{chargeStationId, messageId}, &duplicate) {
+			resolved = true
+		}
+	}
+	delete(p.duplicates, key)
+	return resolved
+}
+
+// resolve must be called with the lock held
+func (p *PendingCalls) resolve(key pendingCallKey, msg *transport.Message) bool {
+	ch, ok := p.pending[key]
+	if !ok {
+		return false
+	}
+	delete(p.pending, key)
+	ch <- msg
+	return true
+}
+
+// Deliver delivers a CallResult or CallError message received from the gateway
+// to the call waiting for it. If the message is the response to a call made by
+// another manager instance then it is broadcast to the other instances: any
+// other message that no call is waiting for is dropped.
+func (p *PendingCalls) Deliver(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, msg *transport.Message) {
+	if p.Resolve(chargeStationId, msg) || p.broadcaster == nil {
+		return
+	}
+	instanceId, _, tagged := strings.Cut(msg.MessageId, ":")
+	if !tagged || instanceId == p.instanceId {
+		return
+	}
+	err := p.broadcaster.Broadcast(ctx, ocppVersion, chargeStationId, msg)
+	if err != nil {
+		slog.Warn("unable to broadcast call response", "chargeStationId", chargeStationId,
+			"messageId", msg.MessageId, "err", err)
+	}
+}
+
+// Handle resolves a CallResult or CallError message broadcast by another
+// manager instance: it allows PendingCalls to be used as the handler of a
+// transport.BroadcastListener.
+func (p *PendingCalls) Handle(_ context.Context, chargeStationId string, msg *transport.Message) {
+	if msg.MessageType != transport.MessageTypeCall {
+		p.Resolve(chargeStationId, msg)
+	}
+}
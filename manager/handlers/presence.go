@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// PresenceHandler tracks the connection state of charge stations. It records
+// the connection events published by the gateway and updates the time that a
+// charge station was last seen whenever a message is received from it. All
+// other messages are passed to the next handler.
+type PresenceHandler struct {
+	Next             transport.MessageHandler
+	Store            store.ChargeStationConnectionStore
+	Clock            clock.PassiveClock
+	LastSeenInterval time.Duration // the minimum interval between last seen updates for a charge station
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+func NewPresenceHandler(next transport.MessageHandler, engine store.ChargeStationConnectionStore, clock clock.PassiveClock) *PresenceHandler {
+	return &PresenceHandler{
+		Next:             next,
+		Store:            engine,
+		Clock:            clock,
+		LastSeenInterval: 30 * time.Second,
+		lastSeen:         make(map[string]time.Time),
+	}
+}
+
+func (h *PresenceHandler) Handle(ctx context.Context, chargeStationId string, msg *transport.Message) {
+	if msg.Event != nil {
+		err := h.handleEvent(ctx, chargeStationId, msg.Event)
+		if err != nil {
+			slog.Error("unable to record connection event", slog.String("chargeStationId", chargeStationId),
+				slog.String("event", string(msg.Event.Type)), "err", err)
+		}
+		return
+	}
+
+	now := h.Clock.Now()
+	if h.shouldUpdateLastSeen(chargeStationId, now) {
+		err := h.Store.SetChargeStationLastSeen(ctx, chargeStationId, now)
+		if err != nil {
+			slog.Error("unable to update last seen", slog.String("chargeStationId", chargeStationId), "err", err)
+		}
+	}
+
+	h.Next.Handle(ctx, chargeStationId, msg)
+}
+
+func (h *PresenceHandler) handleEvent(ctx context.Context, chargeStationId string, event *transport.ConnectionEvent) error {
+	switch event.Type {
+	case transport.ConnectionEventConnected:
+		h.forgetLastSeen(chargeStationId)
+		return h.Store.SetChargeStationConnection(ctx, chargeStationId, &store.ChargeStationConnection{
+			ChargeStationId: chargeStationId,
+			Connected:       true,
+			GatewayId:       event.GatewayId,
+			ConnectionId:    event.ConnectionId,
+			Protocol:        event.Protocol,
+			RemoteAddr:      event.RemoteAddr,
+			SecurityProfile: store.SecurityProfile(event.SecurityProfile),
+			CertificateHash: event.CertificateHash,
+			ConnectedAt:     event.Timestamp,
+			LastSeen:        event.Timestamp,
+		})
+	case transport.ConnectionEventDisconnected:
+		// the charge station may already have reconnected: the disconnection
+		// only applies to the connection it was reported for
+		disconnected, err := h.Store.SetChargeStationDisconnected(ctx, chargeStationId, event.ConnectionId, event.Timestamp)
+		if err != nil {
+			return err
+		}
+		if !disconnected {
+			slog.Info("ignoring disconnection of previous connection", slog.String("chargeStationId", chargeStationId),
+				slog.String("connectionId", event.ConnectionId))
+			return nil
+		}
+		h.forgetLastSeen(chargeStationId)
+		return nil
+	default:
+		slog.Warn("unknown connection event type", slog.String("chargeStationId", chargeStationId), slog.String("event", string(event.Type)))
+		return nil
+	}
+}
+
+// shouldUpdateLastSeen returns true if the last seen time of the charge station
+// was not updated by this handler within the LastSeenInterval
+func (h *PresenceHandler) shouldUpdateLastSeen(chargeStationId string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastSeen == nil {
+		h.lastSeen = make(map[string]time.Time)
+	}
+	last, ok := h.lastSeen[chargeStationId]
+	if ok && now.Sub(last) < h.LastSeenInterval {
+		return false
+	}
+	h.lastSeen[chargeStationId] = now
+	return true
+}
+
+func (h *PresenceHandler) forgetLastSeen(chargeStationId string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.lastSeen, chargeStationId)
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+type recordingHandler struct {
+	messages []*transport.Message
+}
+
+func (r *recordingHandler) Handle(_ context.Context, _ string, msg *transport.Message) {
+	r.messages = append(r.messages, msg)
+}
+
+func TestPresenceHandlerRecordsConnectionEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+	next := &recordingHandler{}
+	handler := handlers.NewPresenceHandler(next, engine, clock)
+
+	connectedAt := now.Add(-time.Minute)
+	handler.Handle(ctx, "cs001", &transport.Message{
+		Event: &transport.ConnectionEvent{
+			Type:            transport.ConnectionEventConnected,
+			GatewayId:       "gateway-1",
+			ConnectionId:    "conn-1",
+			Protocol:        "ocpp1.6",
+			RemoteAddr:      "10.0.0.1:4567",
+			SecurityProfile: 2,
+			CertificateHash: "abc",
+			Timestamp:       connectedAt,
+		},
+	})
+
+	conn, err := engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, &store.ChargeStationConnection{
+		ChargeStationId: "cs001",
+		Connected:       true,
+		GatewayId:       "gateway-1",
+		ConnectionId:    "conn-1",
+		Protocol:        "ocpp1.6",
+		RemoteAddr:      "10.0.0.1:4567",
+		SecurityProfile: store.TLSWithClientSideCertificates,
+		CertificateHash: "abc",
+		ConnectedAt:     connectedAt,
+		LastSeen:        connectedAt,
+	}, conn)
+
+	// a disconnection of a previous connection is ignored
+	handler.Handle(ctx, "cs001", &transport.Message{
+		Event: &transport.ConnectionEvent{
+			Type:         transport.ConnectionEventDisconnected,
+			ConnectionId: "conn-0",
+			Timestamp:    now,
+		},
+	})
+	conn, err = engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.True(t, conn.Connected)
+
+	handler.Handle(ctx, "cs001", &transport.Message{
+		Event: &transport.ConnectionEvent{
+			Type:         transport.ConnectionEventDisconnected,
+			ConnectionId: "conn-1",
+			Timestamp:    now,
+		},
+	})
+	conn, err = engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.False(t, conn.Connected)
+	require.NotNil(t, conn.DisconnectedAt)
+	assert.Equal(t, now, *conn.DisconnectedAt)
+	assert.Equal(t, now, conn.LastSeen)
+
+	assert.Empty(t, next.messages)
+}
+
+func TestPresenceHandlerUpdatesLastSeen(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakeClock(now)
+	engine := inmemory.NewStore(clock)
+	next := &recordingHandler{}
+	handler := handlers.NewPresenceHandler(next, engine, clock)
+
+	msg := &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Heartbeat",
+		MessageId:   "1",
+	}
+
+	handler.Handle(ctx, "cs001", msg)
+	conn, err := engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, now, conn.LastSeen)
+	assert.False(t, conn.Connected)
+
+	// updates within the interval are skipped
+	clock.Step(10 * time.Second)
+	handler.Handle(ctx, "cs001", msg)
+	conn, err = engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, now, conn.LastSeen)
+
+	clock.Step(handler.LastSeenInterval)
+	handler.Handle(ctx, "cs001", msg)
+	conn, err = engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), conn.LastSeen)
+
+	assert.Len(t, next.messages, 3)
+}
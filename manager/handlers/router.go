@@ -23,6 +23,7 @@ type Router struct {
 	OcppVersion      transport.OcppVersion      // the OCPP version that this router supports
 	CallRoutes       map[string]CallRoute       // the set of routes for incoming calls (indexed by action)
 	CallResultRoutes map[string]CallResultRoute // the set of routes for call results (indexed by action)
+	PendingCalls     *PendingCalls              // calls that are waiting for a call result or call error (optional)
 }
 
 func (r Router) Handle(ctx context.Context, chargeStationId string, msg *transport.Message) {
@@ -51,6 +52,11 @@ func (r Router) Handle(ctx context.Context, chargeStationId string, msg *transpo
 	} else {
 		span.SetStatus(codes.Ok, "ok")
 	}
+
+	// let any caller waiting for the response know the outcome of its call
+	if msg.MessageType != transport.MessageTypeCall && r.PendingCalls != nil {
+		r.PendingCalls.Deliver(ctx, r.OcppVersion, chargeStationId, msg)
+	}
 }
 
 func (r Router) route(ctx context.Context, chargeStationId string, message *transport.Message) error {
@@ -78,6 +84,7 @@ func (r Router) route(ctx context.Context, chargeStationId string, message *tran
 			}
 			return fmt.Errorf("unmarshalling %s request payload: %w", message.Action, err)
 		}
+		ctx, afterResponse := withAfterResponse(ctx)
 		resp, err := route.Handler.HandleCall(ctx, chargeStationId, req)
 		if err != nil {
 			return err
@@ -104,6 +111,7 @@ func (r Router) route(ctx context.Context, chargeStationId string, message *tran
 		if err != nil {
 			return fmt.Errorf("sending call response: %w", err)
 		}
+		afterResponse.run(ctx)
 	case transport.MessageTypeCallResult:
 		route, ok := r.CallResultRoutes[message.Action]
 		if !ok {
@@ -136,8 +144,9 @@ func (r Router) route(ctx context.Context, chargeStationId string, message *tran
 			return err
 		}
 	case transport.MessageTypeCallError:
-		// TODO: what do we want to do with errors?
-		return errors.New("we shouldn't get here at the moment")
+		slog.Warn("call error received", slog.String("chargeStationId", chargeStationId),
+			slog.String("action", message.Action), slog.String("errorCode", string(message.ErrorCode)),
+			slog.String("errorDescription", message.ErrorDescription))
 	}
 
 	return nil
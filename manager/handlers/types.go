@@ -60,3 +60,12 @@ type CallMaker interface {
 	// Send receives the charge station id and the request to send. It may return an error.
 	Send(ctx context.Context, chargeStationId string, request ocpp.Request) error
 }
+
+// SyncCallMaker is the interface used by parts of the system that need to wait for the
+// charge station to respond to an OCPP call initiated by the CSMS.
+type SyncCallMaker interface {
+	// Call receives the charge station id, the request to send and the response to
+	// populate. It waits for the charge station to respond until the context is done. A
+	// CallError returned by the charge station is reported as a *transport.Error.
+	Call(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response) error
+}
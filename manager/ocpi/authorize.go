@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// AuthorizeToken asks the eMSP that issued the token whether the token can be
+// used at the charge station's location. If the issuer cannot be identified,
+// each eMSP is asked until one of them knows the token when that has been
+// enabled with SetAuthorizeWithAllEmsps: otherwise nil is returned.
+func (o *OCPI) AuthorizeToken(ctx context.Context, chargeStationId string, token ocpp201.IdTokenType, knownToken *store.Token) (*services.TokenAuthorization, error) {
+	parties, err := o.tokenIssuers(ctx, token, knownToken)
+	if err != nil {
+		return nil, err
+	}
+
+	locationReferences, err := o.locationReferences(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, party := range parties {
+		tokensUrl, err := o.getSenderEndpointUrl(ctx, party, "tokens")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		authorizeUrl := fmt.Sprintf("%s/%s/authorize?type=%s", strings.TrimSuffix(tokensUrl, "/"), url.PathEscape(token.IdToken), toOcpiTokenType(token.Type))
+		authorizationInfo, err := o.postAuthorization(ctx, authorizeUrl, party, locationReferences)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("authorizing token with %s/%s: %w", party.CountryCode, party.PartyId, err))
+			continue
+		}
+		if authorizationInfo == nil {
+			continue
+		}
+		return &services.TokenAuthorization{
+			Status: toAuthorizationStatus(authorizationInfo.Allowed),
+			Token:  fromOcpiToken(authorizationInfo.Token),
+		}, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// tokenIssuers returns the eMSPs that may have issued the token: the issuer of
+// a token that has been received from an eMSP is that eMSP and the issuer of an
+// eMAID is the eMSP identified by its country code and provider id, if that eMSP
+// is registered. Otherwise, any eMSP may have issued the token but they are only
+// returned if every eMSP is to be asked.
+func (o *OCPI) tokenIssuers(ctx context.Context, token ocpp201.IdTokenType, knownToken *store.Token) ([]*store.OcpiParty, error) {
+	if knownToken != nil {
+		party, err := o.store.GetPartyDetails(ctx, "EMSP", knownToken.CountryCode, knownToken.PartyId)
+		if err != nil {
+			return nil, err
+		}
+		if party == nil {
+			return nil, fmt.Errorf("unknown party %s:%s", knownToken.CountryCode, knownToken.PartyId)
+		}
+		return []*store.OcpiParty{party}, nil
+	}
+
+	if countryCode, partyId, ok := emaidIssuer(token); ok {
+		party, err := o.store.GetPartyDetails(ctx, "EMSP", countryCode, partyId)
+		if err != nil {
+			return nil, err
+		}
+		if party != nil {
+			return []*store.OcpiParty{party}, nil
+		}
+	}
+
+	if !o.authorizeWithAllEmsps {
+		return nil, nil
+	}
+	return o.store.ListPartyDetailsForRole(ctx, "EMSP")
+}
+
+// emaidIssuer returns the country code and provider id of an eMAID, such as
+// GB-TWK-C12345678-V, which identify the eMSP that issued it.
+func emaidIssuer(token ocpp201.IdTokenType) (string, string, bool) {
+	if token.Type != ocpp201.IdTokenEnumTypeEMAID {
+		return "", "", false
+	}
+	emaid := strings.ToUpper(strings.ReplaceAll(token.IdToken, "-", ""))
+	if len(emaid) < 5 {
+		return "", "", false
+	}
+	return emaid[:2], emaid[2:5], true
+}
+
+// locationReferences returns the location and EVSEs that are made up of the
+// charge station: the EVSEs that are mapped to the charge station in the store
+// or, if there are none, the EVSEs of the charge station at the location that
+// its sessions take place at.
+func (o *OCPI) locationReferences(ctx context.Context, chargeStationId string) (*LocationReferences, error) {
+	mappings, err := o.store.ListEvseMappingsForChargeStation(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) > 0 {
+		references := &LocationReferences{
+			LocationId: mappings[0].LocationId,
+			EvseUids:   &[]string{},
+		}
+		for _, mapping := range mappings {
+			if mapping.LocationId == references.LocationId {
+				*references.EvseUids = append(*references.EvseUids, mapping.EvseUid)
+			}
+		}
+		return references, nil
+	}
+
+	evse, err := o.lookupChargeStationEvse(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	evseUids := evse.evseUids
+	if evseUids == nil {
+		evseUids = []string{evse.evseUid}
+	}
+	return &LocationReferences{
+		LocationId: evse.locationId,
+		EvseUids:   &evseUids,
+	}, nil
+}
+
+// postAuthorization sends a real-time authorization request to the party: nil
+// is returned if the party does not know the token.
+func (o *OCPI) postAuthorization(ctx context.Context, url string, party *store.OcpiParty, locationReferences *LocationReferences) (*AuthorizationInfo, error) {
+	b, err := json.Marshal(locationReferences)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	o.setRequestHeaders(ctx, req, party.Token, party.CountryCode, party.PartyId)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	b, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var authorizationResponse OcpiResponseAuthorizationInfo
+	err = json.Unmarshal(b, &authorizationResponse)
+	if err != nil {
+		return nil, err
+	}
+	if authorizationResponse.StatusCode == StatusUnknownToken {
+		return nil, nil
+	}
+	if authorizationResponse.StatusCode != StatusSuccess {
+		return nil, fmt.Errorf("status code: %d", authorizationResponse.StatusCode)
+	}
+	if authorizationResponse.Data == nil {
+		return nil, fmt.Errorf("no authorization info in response")
+	}
+	return authorizationResponse.Data, nil
+}
+
+func toOcpiTokenType(tokenType ocpp201.IdTokenEnumType) PostRealTimeTokenAuthorizationParamsType {
+	switch tokenType {
+	case ocpp201.IdTokenEnumTypeISO14443, ocpp201.IdTokenEnumTypeISO15693:
+		return RFID
+	case ocpp201.IdTokenEnumTypeEMAID:
+		return APPUSER
+	default:
+		return OTHER
+	}
+}
+
+func toAuthorizationStatus(allowed AuthorizationInfoAllowed) ocpp201.AuthorizationStatusEnumType {
+	switch allowed {
+	case AuthorizationInfoAllowedALLOWED:
+		return ocpp201.AuthorizationStatusEnumTypeAccepted
+	case AuthorizationInfoAllowedBLOCKED:
+		return ocpp201.AuthorizationStatusEnumTypeBlocked
+	case AuthorizationInfoAllowedEXPIRED:
+		return ocpp201.AuthorizationStatusEnumTypeExpired
+	case AuthorizationInfoAllowedNOCREDIT:
+		return ocpp201.AuthorizationStatusEnumTypeNoCredit
+	default:
+		return ocpp201.AuthorizationStatusEnumTypeNotAtThisLocation
+	}
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// newSender starts an eMSP that has a sender interface for the module handled
+// by handler.
+func newSender(module string, handler http.HandlerFunc) (*httptest.Server, func()) {
+	mux := http.NewServeMux()
+	senderServer := httptest.NewServer(mux)
+	mux.HandleFunc("/ocpi/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[{"version":"2.2","url":"%s/ocpi/2.2"}], "status_code":1000}`, senderServer.URL)))
+	})
+	mux.HandleFunc("/ocpi/2.2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{
+				"version":"2.2",
+				"endpoints":[{"identifier":"%s","role":"SENDER","url":"%s/ocpi/sender/2.2/%s"}]},
+				"status_code":1000}`,
+			module, senderServer.URL, module)))
+	})
+	mux.HandleFunc(fmt.Sprintf("/ocpi/sender/2.2/%s", module), handler)
+	mux.HandleFunc(fmt.Sprintf("/ocpi/sender/2.2/%s/", module), handler)
+	return senderServer, senderServer.Close
+}
+
+// newTokensSender starts an eMSP that handles real-time authorization requests
+// with authorize: the eMSP has no tokens to be pulled.
+func newTokensSender(authorize http.HandlerFunc) (*httptest.Server, func()) {
+	return newSender("tokens", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":[],"status_code":1000}`))
+			return
+		}
+		authorize(w, r)
+	})
+}
+
+func TestAuthorizeToken(t *testing.T) {
+	var got ocpi.LocationReferences
+	senderServer, closeServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/ocpi/sender/2.2/tokens/DEADBEEF/authorize", r.URL.Path)
+		assert.Equal(t, "RFID", r.URL.Query().Get("type"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{
+				"allowed":"ALLOWED",
+				"token":{"country_code":"GB","party_id":"TWK","uid":"DEADBEEF","type":"RFID","contract_id":"GBTWK012345678V",
+					"issuer":"Zynka-tech","valid":true,"whitelist":"NEVER","last_updated":"2024-01-01T12:00:00Z"}},
+				"status_code":1000}`))
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, senderServer.URL)
+	ctx := context.Background()
+	err := engine.SetLocation(ctx, chargeStationLocation())
+	require.NoError(t, err)
+
+	authorization, err := ocpiApi.AuthorizeToken(ctx, "cs001", ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeISO14443,
+		IdToken: "DEADBEEF",
+	}, &store.Token{CountryCode: "GB", PartyId: "TWK", Uid: "DEADBEEF"})
+	require.NoError(t, err)
+	require.NotNil(t, authorization)
+
+	assert.Equal(t, ocpi.LocationReferences{
+		LocationId: "loc001",
+		EvseUids:   &[]string{"GBTWKEcs001-1", "GBTWKEcs001-2"},
+	}, got)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeAccepted, authorization.Status)
+	assert.Equal(t, &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "DEADBEEF",
+		ContractId:  "GBTWK012345678V",
+		Issuer:      "Zynka-tech",
+		Valid:       true,
+		CacheMode:   "NEVER",
+	}, authorization.Token)
+}
+
+func TestAuthorizeTokenUnknownToEmsp(t *testing.T) {
+	senderServer, closeServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status_code":2004,"status_message":"Unknown token"}`))
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, senderServer.URL)
+
+	authorization, err := ocpiApi.AuthorizeToken(context.Background(), "cs001", ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeEMAID,
+		IdToken: "GBTWK012345678V",
+	}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, authorization)
+}
+
+func TestAuthorizeTokenWithFailingEmsp(t *testing.T) {
+	senderServer, closeServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, senderServer.URL)
+
+	authorization, err := ocpiApi.AuthorizeToken(context.Background(), "cs001", ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeISO14443,
+		IdToken: "DEADBEEF",
+	}, &store.Token{CountryCode: "GB", PartyId: "TWK", Uid: "DEADBEEF"})
+	assert.ErrorContains(t, err, "status code: 500")
+	assert.Nil(t, authorization)
+}
+
+func TestAuthorizeTokenOnlyAsksTheIssuerOfAnEmaid(t *testing.T) {
+	otherServer, closeOtherServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "unexpected authorization request to an eMSP that did not issue the token")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status_code":2004,"status_message":"Unknown token"}`))
+	})
+	defer closeOtherServer()
+	issuerServer, closeIssuerServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ocpi/sender/2.2/tokens/NL-ABC-C12345678-X/authorize", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"allowed":"BLOCKED"},"status_code":1000}`))
+	})
+	defer closeIssuerServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, otherServer.URL)
+	err := ocpiApi.SetCredentials(context.Background(), "some-token-789", ocpi.Credentials{
+		Roles: []ocpi.CredentialsRole{
+			{
+				CountryCode: "NL",
+				PartyId:     "ABC",
+				Role:        ocpi.CredentialsRoleRoleEMSP,
+			},
+		},
+		Token: "some-token-abc",
+		Url:   issuerServer.URL + "/ocpi/versions",
+	})
+	require.NoError(t, err)
+
+	authorization, err := ocpiApi.AuthorizeToken(context.Background(), "cs001", ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeEMAID,
+		IdToken: "NL-ABC-C12345678-X",
+	}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, authorization)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeBlocked, authorization.Status)
+}
+
+func TestAuthorizeTokenWithEvseMappings(t *testing.T) {
+	var got ocpi.LocationReferences
+	senderServer, closeServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"allowed":"ALLOWED"},"status_code":1000}`))
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, senderServer.URL)
+	ctx := context.Background()
+	for _, mapping := range []*store.EvseMapping{
+		{LocationId: "loc002", EvseUid: "evse002", ChargeStationId: "cs001", ChargeStationEvseId: 2},
+		{LocationId: "loc002", EvseUid: "evse001", ChargeStationId: "cs001", ChargeStationEvseId: 1},
+		{LocationId: "loc002", EvseUid: "evse003", ChargeStationId: "cs002", ChargeStationEvseId: 1},
+	} {
+		err := engine.SetEvseMapping(ctx, mapping)
+		require.NoError(t, err)
+	}
+
+	authorization, err := ocpiApi.AuthorizeToken(ctx, "cs001", ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeISO14443,
+		IdToken: "DEADBEEF",
+	}, &store.Token{CountryCode: "GB", PartyId: "TWK", Uid: "DEADBEEF"})
+	require.NoError(t, err)
+	require.NotNil(t, authorization)
+
+	assert.Equal(t, ocpi.LocationReferences{
+		LocationId: "loc002",
+		EvseUids:   &[]string{"evse001", "evse002"},
+	}, got)
+}
+
+func TestAuthorizeTokenWithUnknownIssuer(t *testing.T) {
+	var requests int
+	senderServer, closeServer := newTokensSender(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"allowed":"ALLOWED"},"status_code":1000}`))
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, senderServer.URL)
+	token := ocpp201.IdTokenType{
+		Type:    ocpp201.IdTokenEnumTypeISO14443,
+		IdToken: "DEADBEEF",
+	}
+
+	authorization, err := ocpiApi.AuthorizeToken(context.Background(), "cs001", token, nil)
+	require.NoError(t, err)
+	assert.Nil(t, authorization)
+	assert.Equal(t, 0, requests)
+
+	ocpiApi.(*ocpi.OCPI).SetAuthorizeWithAllEmsps(true)
+	authorization, err = ocpiApi.AuthorizeToken(context.Background(), "cs001", token, nil)
+	require.NoError(t, err)
+	require.NotNil(t, authorization)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeAccepted, authorization.Status)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// PushCdr creates the CDR for a transaction that has ended and sends it to the
+// eMSP that owns the token used for the transaction. The CDR is only created
+// once: if it already exists nothing is sent as the eMSP can always retrieve
+// the CDRs it missed from the CDRs sender interface.
+func (o *OCPI) PushCdr(ctx context.Context, transaction *store.Transaction) error {
+	if transaction.EndedSeqNo == 0 {
+		return fmt.Errorf("transaction %s has not ended", transaction.TransactionId)
+	}
+
+	cdrId := newCdrId(transaction)
+	existing, err := o.store.LookupCdr(ctx, cdrId)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	cdr, err := o.newCdr(ctx, cdrId, transaction)
+	if err != nil {
+		return err
+	}
+	err = o.store.CreateCdr(ctx, cdr)
+	if err != nil {
+		return err
+	}
+
+	return o.postCdrToTokenOwner(ctx, transaction, cdr)
+}
+
+func (o *OCPI) ListCdrs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]CDR, int, error) {
+	cdrs, total, err := o.store.ListCdrs(ctx, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]CDR, 0, len(cdrs))
+	for _, cdr := range cdrs {
+		result = append(result, toOcpiCdr(cdr))
+	}
+	return result, total, nil
+}
+
+// newCdrId returns the id of the CDR for the transaction. The id is derived from
+// the charge station and transaction ids as the transaction ids of different
+// charge stations may be the same.
+func newCdrId(transaction *store.Transaction) string {
+	name := fmt.Sprintf("urn:cdr:%s:%s", transaction.ChargeStationId, transaction.TransactionId)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+func (o *OCPI) newCdr(ctx context.Context, cdrId string, transaction *store.Transaction) (*store.Cdr, error) {
+	evse, err := o.lookupChargeStationEvse(ctx, transaction.ChargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	cdrToken, authMethod, err := o.cdrToken(ctx, transaction, nil)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := o.cdrSignedData(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	price, err := o.priceTransaction(ctx, transaction)
+	if err != nil {
+		return nil, fmt.Errorf("pricing transaction %s: %w", transaction.TransactionId, err)
+	}
+
+	usage := newEnergyUsage(transaction.MeterValues)
+	now := o.clock.Now().UTC()
+	startDateTime, endDateTime := now, now
+	if !usage.start.IsZero() {
+		startDateTime, endDateTime = usage.start, usage.end
+	}
+
+	var chargingPeriods []store.CdrChargingPeriod
+	for _, period := range usage.periods() {
+		chargingPeriods = append(chargingPeriods, store.CdrChargingPeriod{
+			StartDateTime: period.start,
+			Dimensions: []store.CdrDimension{
+				{
+					Type:   string(CdrDimensionTypeENERGY),
+					Volume: period.wh / 1000,
+				},
+			},
+		})
+	}
+
+	return &store.Cdr{
+		Id:              cdrId,
+		CountryCode:     o.countryCode,
+		PartyId:         o.partyId,
+		ChargeStationId: transaction.ChargeStationId,
+		TransactionId:   transaction.TransactionId,
+		StartDateTime:   startDateTime,
+		EndDateTime:     endDateTime,
+		AuthMethod:      string(authMethod),
+		Token: store.CdrToken{
+			Uid:        cdrToken.Uid,
+			Type:       string(cdrToken.Type),
+			ContractId: cdrToken.ContractId,
+		},
+		Location:         cdrLocation(evse),
+		Currency:         price.Currency,
+		ChargingPeriods:  chargingPeriods,
+		SignedData:       signedData,
+		TotalCost:        price.ExclVat,
+		TotalCostInclVat: price.InclVat,
+		TotalEnergy:      usage.totalWh / 1000,
+		TotalTime:        endDateTime.Sub(startDateTime).Hours(),
+		LastUpdated:      now,
+	}, nil
+}
+
+// transactionPricer is implemented by tariff services that price transactions
+// in the currency of a tariff
+type transactionPricer interface {
+	PriceTransaction(ctx context.Context, transaction *store.Transaction) (*services.TransactionPrice, error)
+}
+
+// priceTransaction prices the transaction with the tariff service. A tariff
+// service that only calculates a cost does not distinguish VAT and is assumed
+// to use the session currency.
+func (o *OCPI) priceTransaction(ctx context.Context, transaction *store.Transaction) (*services.TransactionPrice, error) {
+	if pricer, ok := o.tariffService.(transactionPricer); ok {
+		return pricer.PriceTransaction(ctx, transaction)
+	}
+	cost, err := o.tariffService.CalculateCost(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	return &services.TransactionPrice{
+		Currency: sessionCurrency,
+		ExclVat:  cost,
+		InclVat:  cost,
+	}, nil
+}
+
+func cdrLocation(evse evseLocation) store.CdrLocation {
+	location := store.CdrLocation{
+		Id:          evse.locationId,
+		EvseUid:     evse.evseUid,
+		EvseId:      evse.evseUid,
+		ConnectorId: evse.connectorId,
+	}
+	if evse.location != nil {
+		location.Name = evse.location.Name
+		location.Address = evse.location.Address
+		location.City = evse.location.City
+		location.PostalCode = evse.location.PostalCode
+		location.Country = evse.location.Country
+		location.Coordinates = evse.location.Coordinates
+	}
+	if evse.evse != nil && evse.evse.EvseId != nil {
+		location.EvseId = *evse.evse.EvseId
+	}
+	if evse.connector != nil {
+		location.ConnectorStandard = evse.connector.Standard
+		location.ConnectorFormat = evse.connector.Format
+		location.ConnectorPowerType = evse.connector.PowerType
+	}
+	return location
+}
+
+// cdrSignedData returns the signed meter values of the transaction: it returns
+// nil if the meter did not sign any values. Only the values signed with the
+// encoding method of the first signed value are included. The public key of the
+// meter is only known if the charge station has a single meter.
+func (o *OCPI) cdrSignedData(ctx context.Context, transaction *store.Transaction) (*store.CdrSignedData, error) {
+	type signedReading struct {
+		timestamp    time.Time
+		sampledValue store.SampledValue
+	}
+	var readings []signedReading
+	for _, meterValue := range transaction.MeterValues {
+		timestamp, err := time.Parse(time.RFC3339, meterValue.Timestamp)
+		if err != nil {
+			continue
+		}
+		for _, sampledValue := range meterValue.SampledValues {
+			if sampledValue.SignedMeterValue == nil {
+				continue
+			}
+			readings = append(readings, signedReading{
+				timestamp:    timestamp,
+				sampledValue: sampledValue,
+			})
+		}
+	}
+	if len(readings) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].timestamp.Before(readings[j].timestamp)
+	})
+
+	signedData := &store.CdrSignedData{
+		EncodingMethod: readings[0].sampledValue.SignedMeterValue.EncodingMethod,
+	}
+	for _, reading := range readings {
+		signedMeterValue := reading.sampledValue.SignedMeterValue
+		if signedMeterValue.EncodingMethod != signedData.EncodingMethod {
+			continue
+		}
+		var plainData string
+		if !reading.sampledValue.SignedDataOnly {
+			plainData = strconv.FormatFloat(toWattHours(reading.sampledValue), 'f', -1, 64)
+		}
+		signedData.SignedValues = append(signedData.SignedValues, store.CdrSignedValue{
+			Nature:     signedValueNature(reading.sampledValue),
+			PlainData:  plainData,
+			SignedData: signedMeterValue.SignedMeterData,
+		})
+	}
+
+	keys, err := o.store.ListChargeStationMeterPublicKeys(ctx, transaction.ChargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 1 {
+		signedData.PublicKey = keys[0].PublicKey
+	}
+
+	return signedData, nil
+}
+
+// signedValueNature returns the nature of a signed value from the context in
+// which the value was sampled
+func signedValueNature(sampledValue store.SampledValue) string {
+	if sampledValue.Context != nil {
+		switch *sampledValue.Context {
+		case "Transaction.Begin":
+			return "Start"
+		case "Transaction.End":
+			return "End"
+		}
+	}
+	return "Intermediate"
+}
+
+// postCdrToTokenOwner sends the CDR to the eMSP that issued the token used for
+// the transaction. Tokens that were not received from an eMSP are owned by the
+// CPO so there is no one to send the CDR to.
+func (o *OCPI) postCdrToTokenOwner(ctx context.Context, transaction *store.Transaction, cdr *store.Cdr) error {
+	if transaction.IdToken == "" {
+		return nil
+	}
+	tok, err := o.store.LookupToken(ctx, transaction.IdToken)
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	party, err := o.store.GetPartyDetails(ctx, "EMSP", tok.CountryCode, tok.PartyId)
+	if err != nil {
+		return err
+	}
+	if party == nil {
+		return nil
+	}
+
+	cdrsUrl, err := o.getReceiverEndpointUrl(ctx, party, "cdrs")
+	if err != nil {
+		return err
+	}
+	err = o.sendToParty(ctx, http.MethodPost, cdrsUrl, party, toOcpiCdr(cdr))
+	if err != nil {
+		return fmt.Errorf("posting cdr to %s/%s: %w", party.CountryCode, party.PartyId, err)
+	}
+	return nil
+}
+
+func toOcpiCdr(cdr *store.Cdr) CDR {
+	sessionId := newSessionId(cdr.ChargeStationId, cdr.TransactionId)
+	totalCostInclVat := float32(cdr.TotalCostInclVat)
+	result := CDR{
+		Id:            cdr.Id,
+		CountryCode:   cdr.CountryCode,
+		PartyId:       cdr.PartyId,
+		StartDateTime: cdr.StartDateTime.Format(time.RFC3339),
+		EndDateTime:   cdr.EndDateTime.Format(time.RFC3339),
+		SessionId:     &sessionId,
+		CdrToken: CdrToken{
+			ContractId: cdr.Token.ContractId,
+			Type:       CdrTokenType(cdr.Token.Type),
+			Uid:        cdr.Token.Uid,
+		},
+		AuthMethod: CDRAuthMethod(cdr.AuthMethod),
+		CdrLocation: CdrLocation{
+			Id:                 cdr.Location.Id,
+			Address:            cdr.Location.Address,
+			City:               cdr.Location.City,
+			PostalCode:         cdr.Location.PostalCode,
+			Country:            cdr.Location.Country,
+			Coordinates:        GeoLocation{Latitude: cdr.Location.Coordinates.Latitude, Longitude: cdr.Location.Coordinates.Longitude},
+			EvseUid:            cdr.Location.EvseUid,
+			EvseId:             cdr.Location.EvseId,
+			ConnectorId:        cdr.Location.ConnectorId,
+			ConnectorStandard:  CdrLocationConnectorStandard(cdr.Location.ConnectorStandard),
+			ConnectorFormat:    CdrLocationConnectorFormat(cdr.Location.ConnectorFormat),
+			ConnectorPowerType: CdrLocationConnectorPowerType(cdr.Location.ConnectorPowerType),
+		},
+		Currency:        cdr.Currency,
+		ChargingPeriods: []ChargingPeriod{},
+		TotalCost: Price{
+			ExclVat: float32(cdr.TotalCost),
+			InclVat: &totalCostInclVat,
+		},
+		TotalEnergy: float32(cdr.TotalEnergy),
+		TotalTime:   float32(cdr.TotalTime),
+		LastUpdated: cdr.LastUpdated.Format(time.RFC3339),
+	}
+	if cdr.Location.Name != "" {
+		name := cdr.Location.Name
+		result.CdrLocation.Name = &name
+	}
+	for _, period := range cdr.ChargingPeriods {
+		chargingPeriod := ChargingPeriod{
+			StartDateTime: period.StartDateTime.Format(time.RFC3339),
+			Dimensions:    []CdrDimension{},
+		}
+		for _, dimension := range period.Dimensions {
+			chargingPeriod.Dimensions = append(chargingPeriod.Dimensions, CdrDimension{
+				Type:   CdrDimensionType(dimension.Type),
+				Volume: float32(dimension.Volume),
+			})
+		}
+		result.ChargingPeriods = append(result.ChargingPeriods, chargingPeriod)
+	}
+	if cdr.SignedData != nil {
+		signedData := &SignedData{
+			EncodingMethod: cdr.SignedData.EncodingMethod,
+			SignedValues:   []SignedValue{},
+		}
+		if cdr.SignedData.PublicKey != "" {
+			publicKey := cdr.SignedData.PublicKey
+			signedData.PublicKey = &publicKey
+		}
+		for _, value := range cdr.SignedData.SignedValues {
+			signedData.SignedValues = append(signedData.SignedValues, SignedValue{
+				Nature:     value.Nature,
+				PlainData:  value.PlainData,
+				SignedData: value.SignedData,
+			})
+		}
+		result.SignedData = signedData
+	}
+	return result
+}
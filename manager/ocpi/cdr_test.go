@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+)
+
+func signedEnergyMeterValue(timestamp, context, measurand string, wh float64, signedData string) store.MeterValue {
+	return store.MeterValue{
+		Timestamp: timestamp,
+		SampledValues: []store.SampledValue{
+			{
+				Context:       makePtr(context),
+				Measurand:     makePtr(measurand),
+				Location:      makePtr("Outlet"),
+				UnitOfMeasure: &store.UnitOfMeasure{Unit: "Wh"},
+				Value:         wh,
+				SignedMeterValue: &store.SignedMeterValue{
+					SignedMeterData: signedData,
+					EncodingMethod:  "OCMF",
+					Status:          store.SignedMeterValueStatusValid,
+				},
+			},
+		},
+	}
+}
+
+func endedTransaction(idToken string) *store.Transaction {
+	return &store.Transaction{
+		ChargeStationId: "cs001",
+		TransactionId:   "tx001",
+		IdToken:         idToken,
+		TokenType:       "ISO14443",
+		MeterValues: []store.MeterValue{
+			signedEnergyMeterValue("2024-01-01T10:00:00Z", "Transaction.Begin", "MeterValue", 1000, "OCMF|start"),
+			signedEnergyMeterValue("2024-01-01T11:00:00Z", "Transaction.End", "Energy.Active.Import.Register", 7500, "OCMF|end"),
+		},
+		EndedSeqNo: 1,
+	}
+}
+
+func TestPushCdr(t *testing.T) {
+	var got []ocpi.CDR
+	receiverServer, closeServer := newReceiver("cdrs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/cdrs", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var cdr ocpi.CDR
+		require.NoError(t, json.Unmarshal(b, &cdr))
+		got = append(got, cdr)
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, receiverServer.URL)
+	ctx := context.Background()
+
+	err := engine.SetToken(ctx, &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "DEADBEEF",
+		ContractId:  "GBTWKTWTW000018",
+		Issuer:      "Zynka-tech",
+		Valid:       true,
+		CacheMode:   "ALWAYS",
+	})
+	require.NoError(t, err)
+	err = engine.SetLocation(ctx, &store.Location{
+		Id:          "loc001",
+		Name:        "Gent Zuid",
+		Address:     "F.Rooseveltlaan 3A",
+		City:        "Gent",
+		PostalCode:  "9000",
+		Country:     "BEL",
+		Coordinates: store.GeoLocation{Latitude: "51.047599", Longitude: "3.729944"},
+		Evses: &[]store.Evse{
+			{
+				Uid:    "GBTWKEcs001",
+				EvseId: makePtr("GB*TWK*Ecs001"),
+				Connectors: []store.Connector{
+					{
+						Id:        "1",
+						Standard:  "IEC_62196_T2",
+						Format:    "SOCKET",
+						PowerType: "AC_3_PHASE",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 1, PublicKey: "beef"})
+	require.NoError(t, err)
+
+	err = ocpiApi.PushCdr(ctx, endedTransaction("DEADBEEF"))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	cdr := got[0]
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`, cdr.Id)
+	assert.NotEmpty(t, cdr.LastUpdated)
+	cdr.Id = ""
+	cdr.LastUpdated = ""
+
+	want := ocpi.CDR{
+		CountryCode:   "GB",
+		PartyId:       "TWK",
+		StartDateTime: "2024-01-01T10:00:00Z",
+		EndDateTime:   "2024-01-01T11:00:00Z",
+		SessionId:     makePtr("c6e5ec42-7207-58c8-ab65-39f9f30c48a6"),
+		CdrToken: ocpi.CdrToken{
+			ContractId: "GBTWKTWTW000018",
+			Type:       ocpi.CdrTokenTypeRFID,
+			Uid:        "DEADBEEF",
+		},
+		AuthMethod: ocpi.CDRAuthMethodWHITELIST,
+		CdrLocation: ocpi.CdrLocation{
+			Id:                 "loc001",
+			Name:               makePtr("Gent Zuid"),
+			Address:            "F.Rooseveltlaan 3A",
+			City:               "Gent",
+			PostalCode:         "9000",
+			Country:            "BEL",
+			Coordinates:        ocpi.GeoLocation{Latitude: "51.047599", Longitude: "3.729944"},
+			EvseUid:            "GBTWKEcs001",
+			EvseId:             "GB*TWK*Ecs001",
+			ConnectorId:        "1",
+			ConnectorStandard:  ocpi.CdrLocationConnectorStandardIEC62196T2,
+			ConnectorFormat:    ocpi.CdrLocationConnectorFormatSOCKET,
+			ConnectorPowerType: ocpi.CdrLocationConnectorPowerTypeAC3PHASE,
+		},
+		Currency: "EUR",
+		ChargingPeriods: []ocpi.ChargingPeriod{
+			{
+				StartDateTime: "2024-01-01T10:00:00Z",
+				Dimensions: []ocpi.CdrDimension{
+					{
+						Type:   ocpi.CdrDimensionTypeENERGY,
+						Volume: 7.5,
+					},
+				},
+			},
+		},
+		SignedData: &ocpi.SignedData{
+			EncodingMethod: "OCMF",
+			PublicKey:      makePtr("beef"),
+			SignedValues: []ocpi.SignedValue{
+				{Nature: "Start", PlainData: "1000", SignedData: "OCMF|start"},
+				{Nature: "End", PlainData: "7500", SignedData: "OCMF|end"},
+			},
+		},
+		TotalCost:   ocpi.Price{ExclVat: 4.125, InclVat: makePtr[float32](4.125)},
+		TotalEnergy: 7.5,
+		TotalTime:   1,
+	}
+	assert.Equal(t, want, cdr)
+
+	err = ocpiApi.PushCdr(ctx, endedTransaction("DEADBEEF"))
+	require.NoError(t, err)
+	assert.Len(t, got, 1, "the cdr must only be sent once")
+
+	cdrs, total, err := ocpiApi.ListCdrs(ctx, nil, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, cdrs, 1)
+	assert.Equal(t, got[0], cdrs[0])
+}
+
+func TestPushCdrForTokenNotOwnedByEmsp(t *testing.T) {
+	receiverServer, closeServer := newReceiver("cdrs", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+	ctx := context.Background()
+
+	err := ocpiApi.PushCdr(ctx, endedTransaction("CAFEBABE"))
+	require.NoError(t, err)
+
+	cdrs, total, err := ocpiApi.ListCdrs(ctx, nil, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, cdrs, 1)
+	assert.Equal(t, ocpi.CDRAuthMethodAUTHREQUEST, cdrs[0].AuthMethod)
+	assert.Equal(t, "cs001", cdrs[0].CdrLocation.Id)
+	assert.Equal(t, "GBTWKEcs001", cdrs[0].CdrLocation.EvseUid)
+}
+
+func TestPushCdrForTransactionInProgress(t *testing.T) {
+	receiverServer, closeServer := newReceiver("cdrs", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+	transaction := endedTransaction("DEADBEEF")
+	transaction.EndedSeqNo = 0
+
+	err := ocpiApi.PushCdr(context.Background(), transaction)
+	assert.Error(t, err)
+}
+
+func TestPushCdrPricedWithOcpiTariff(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	ocpiApi.SetTariffService(services.OcpiTariffService{Store: engine})
+	ctx := context.Background()
+
+	err := engine.SetTariff(ctx, &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "GBP",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.40, Vat: makePtr(20.0)},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	err = ocpiApi.PushCdr(ctx, endedTransaction("CAFEBABE"))
+	require.NoError(t, err)
+
+	cdrs, _, err := ocpiApi.ListCdrs(ctx, nil, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, cdrs, 1)
+	assert.Equal(t, "GBP", cdrs[0].Currency)
+	assert.InDelta(t, 3.0, cdrs[0].TotalCost.ExclVat, 0.0001)
+	require.NotNil(t, cdrs[0].TotalCost.InclVat)
+	assert.InDelta(t, 3.6, *cdrs[0].TotalCost.InclVat, 0.0001)
+}
@@ -0,0 +1,462 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	handlers16 "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+// chargingProfileCommand is the OCPP call that is made to a charge station for
+// an OCPI charging profile request
+type chargingProfileCommand struct {
+	request  ocpp.Request
+	response ocpp.Response
+	// result converts the response into the OCPI charging profile result
+	result func() GenericChargingProfileResult
+}
+
+// SetChargingProfile sets the charging profile of the session's transaction
+// with a TxProfile. A transaction has a single TxProfile so the profile replaces
+// any TxProfile that the transaction already has.
+func (o *OCPI) SetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, setChargingProfile SetChargingProfile) (*ChargingProfileResponse, error) {
+	transaction, target, err := o.sessionCommandTarget(ctx, sessionId)
+	if err != nil || target == nil {
+		return chargingProfileResponse(transaction), err
+	}
+	kind, schedule, err := toChargingSchedule(setChargingProfile.ChargingProfile)
+	if err != nil {
+		slog.Warn("invalid ocpi charging profile", "sessionId", sessionId, "err", err)
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+	profile, _, err := o.transactionProfile(ctx, target, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ConnectorId == 0 {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+	profile.ChargingProfileKind = kind
+	profile.ChargingSchedule = schedule
+	profile.Status = store.ChargingProfileStatusAccepted
+	// the eMSP's profile takes over from the load balancer
+	profile.LoadBalanced = false
+
+	req, err := services.NewChargingProfileRequest(target.ocppVersion, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *chargingProfileCommand
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.SetChargingProfileResponseJson)
+		cmd = &chargingProfileCommand{
+			request:  req,
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				return chargingProfileResult(resp.Status == ocpp16.SetChargingProfileResponseJsonStatusAccepted)
+			},
+		}
+	} else {
+		resp := new(ocpp201.SetChargingProfileResponseJson)
+		cmd = &chargingProfileCommand{
+			request:  req,
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				return chargingProfileResult(resp.Status == ocpp201.ChargingProfileStatusEnumTypeAccepted)
+			},
+		}
+	}
+
+	// the profile is only stored once the charge station has accepted it so
+	// that the charging profile sync doesn't send it again
+	return o.runChargingProfileCommand(ctx, countryCode, partyId, setChargingProfile.ResponseUrl, target, cmd, func(ctx context.Context, result GenericChargingProfileResult) error {
+		if result.Result != GenericChargingProfileResultResultACCEPTED {
+			return nil
+		}
+		return o.store.UpdateChargeStationChargingProfiles(ctx, target.chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{profile},
+		})
+	})
+}
+
+// GetActiveChargingProfile asks the charge station for the schedule that it
+// will apply to the session's transaction over the duration (in seconds).
+func (o *OCPI) GetActiveChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, duration int, responseUrl string) (*ChargingProfileResponse, error) {
+	transaction, target, err := o.sessionCommandTarget(ctx, sessionId)
+	if err != nil || target == nil {
+		return chargingProfileResponse(transaction), err
+	}
+	profile, _, err := o.transactionProfile(ctx, target, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ConnectorId == 0 {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+
+	var cmd *chargingProfileCommand
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.GetCompositeScheduleResponseJson)
+		cmd = &chargingProfileCommand{
+			request: &ocpp16.GetCompositeScheduleJson{
+				ConnectorId: profile.ConnectorId,
+				Duration:    duration,
+			},
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				if resp.Status != ocpp16.GetCompositeScheduleResponseJsonStatusAccepted || resp.ChargingSchedule == nil {
+					return chargingProfileResult(false)
+				}
+				return activeChargingProfileResult(fromCompositeSchedule16(resp, o.clock.Now()))
+			},
+		}
+	} else {
+		resp := new(ocpp201.GetCompositeScheduleResponseJson)
+		cmd = &chargingProfileCommand{
+			request: &ocpp201.GetCompositeScheduleRequestJson{
+				EvseId:   profile.ConnectorId,
+				Duration: duration,
+			},
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				if resp.Status != ocpp201.GenericStatusEnumTypeAccepted || resp.Schedule == nil {
+					return chargingProfileResult(false)
+				}
+				return activeChargingProfileResult(fromCompositeSchedule201(resp.Schedule))
+			},
+		}
+	}
+
+	return o.runChargingProfileCommand(ctx, countryCode, partyId, responseUrl, target, cmd, nil)
+}
+
+// ClearChargingProfile removes the TxProfile of the session's transaction from
+// the charge station.
+func (o *OCPI) ClearChargingProfile(ctx context.Context, countryCode, partyId, sessionId, responseUrl string) (*ChargingProfileResponse, error) {
+	transaction, target, err := o.sessionCommandTarget(ctx, sessionId)
+	if err != nil || target == nil {
+		return chargingProfileResponse(transaction), err
+	}
+	profile, found, err := o.transactionProfile(ctx, target, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+
+	// the charge station's response is handled like any other response to a
+	// ClearChargingProfile: the stored profile is removed
+	profile.Status = store.ChargingProfileStatusClearPending
+	req, err := services.NewChargingProfileRequest(target.ocppVersion, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *chargingProfileCommand
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.ClearChargingProfileResponseJson)
+		cmd = &chargingProfileCommand{
+			request:  req,
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				return clearChargingProfileResult(resp.Status == ocpp16.ClearChargingProfileResponseJsonStatusAccepted)
+			},
+		}
+	} else {
+		resp := new(ocpp201.ClearChargingProfileResponseJson)
+		cmd = &chargingProfileCommand{
+			request:  req,
+			response: resp,
+			result: func() GenericChargingProfileResult {
+				return clearChargingProfileResult(resp.Status == ocpp201.ClearChargingProfileStatusEnumTypeAccepted)
+			},
+		}
+	}
+
+	return o.runChargingProfileCommand(ctx, countryCode, partyId, responseUrl, target, cmd, nil)
+}
+
+// runChargingProfileCommand sends the command to the charge station in the
+// background and posts the result to the eMSP's response URL once the charge
+// station responds, or once the command times out. The onResult function, if
+// any, is called with the result before it is posted.
+func (o *OCPI) runChargingProfileCommand(ctx context.Context, countryCode, partyId, responseUrl string, target *commandTarget,
+	cmd *chargingProfileCommand, onResult func(ctx context.Context, result GenericChargingProfileResult) error) (*ChargingProfileResponse, error) {
+	party, err := o.store.GetPartyDetails(ctx, "EMSP", countryCode, partyId)
+	if err != nil {
+		return nil, err
+	}
+	if party == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+
+	callMaker := o.v16CallMaker
+	if target.ocppVersion == "2.0.1" {
+		callMaker = o.v201CallMaker
+	}
+	if callMaker == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultNOTSUPPORTED}, nil
+	}
+
+	// the command outlives the request that sent it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		callCtx, cancel := context.WithTimeout(ctx, o.commandTimeout)
+		defer cancel()
+
+		var result GenericChargingProfileResult
+		err := callMaker.Call(callCtx, target.chargeStationId, cmd.request, cmd.response)
+		if err != nil {
+			slog.Warn("ocpi charging profile command failed", "chargeStationId", target.chargeStationId, "err", err)
+			result = chargingProfileResult(false)
+		} else {
+			result = cmd.result()
+		}
+
+		if onResult != nil {
+			if err := onResult(ctx, result); err != nil {
+				slog.Error("handling ocpi charging profile result", "chargeStationId", target.chargeStationId, "err", err)
+			}
+		}
+		err = o.sendToParty(ctx, http.MethodPost, responseUrl, party, result)
+		if err != nil {
+			slog.Error("posting ocpi charging profile result", "responseUrl", responseUrl, "err", err)
+		}
+	}()
+
+	return &ChargingProfileResponse{
+		Result:  ChargingProfileResponseResultACCEPTED,
+		Timeout: int32(o.commandTimeout / time.Second),
+	}, nil
+}
+
+// sessionCommandTarget returns the transaction of the session and the charge
+// station that it takes place on. The target is nil if the session is not
+// active or the charge station has never connected.
+func (o *OCPI) sessionCommandTarget(ctx context.Context, sessionId string) (*store.Transaction, *commandTarget, error) {
+	transaction, err := o.findTransaction(ctx, sessionId)
+	if err != nil || transaction == nil || transaction.EndedSeqNo != 0 {
+		return nil, nil, err
+	}
+	target, err := o.chargeStationCommandTarget(ctx, transaction.ChargeStationId, 0)
+	return transaction, target, err
+}
+
+// chargingProfileResponse is the response to a charging profile request that
+// cannot be sent to the charge station.
+func chargingProfileResponse(transaction *store.Transaction) *ChargingProfileResponse {
+	if transaction == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultUNKNOWNSESSION}
+	}
+	return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}
+}
+
+// transactionProfile returns the stored TxProfile of the transaction and true.
+// If there isn't one, a new profile is returned and false: its ConnectorId is 0
+// if the EVSE of the transaction is not known.
+func (o *OCPI) transactionProfile(ctx context.Context, target *commandTarget, transaction *store.Transaction) (*store.ChargingProfile, bool, error) {
+	transactionId := transaction.TransactionId
+	if target.ocppVersion == "1.6" {
+		id, err := handlers16.ConvertFromUUID(transaction.TransactionId)
+		if err != nil {
+			return nil, false, err
+		}
+		transactionId = strconv.Itoa(id)
+	}
+
+	profiles, err := o.store.LookupChargeStationChargingProfiles(ctx, target.chargeStationId)
+	if err != nil {
+		return nil, false, err
+	}
+	var existing []*store.ChargingProfile
+	if profiles != nil {
+		existing = profiles.ChargingProfiles
+	}
+	for _, profile := range existing {
+		if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile &&
+			profile.TransactionId != nil && *profile.TransactionId == transactionId {
+			return profile, true, nil
+		}
+	}
+
+	evseId, err := o.transactionEvseId(ctx, target.chargeStationId)
+	if err != nil {
+		return nil, false, err
+	}
+	return &store.ChargingProfile{
+		ChargingProfileId:      nextChargingProfileId(existing),
+		ConnectorId:            evseId,
+		TransactionId:          &transactionId,
+		ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+	}, false, nil
+}
+
+// transactionEvseId returns the EVSE (the connector for OCPP 1.6) that a
+// transaction of the charge station takes place on. Transactions do not record
+// their EVSE so this is the EVSE that is in use, or 0 if there isn't exactly
+// one such EVSE.
+func (o *OCPI) transactionEvseId(ctx context.Context, chargeStationId string) (int, error) {
+	statuses, err := o.store.ListChargeStationConnectorStatuses(ctx, chargeStationId)
+	if err != nil {
+		return 0, err
+	}
+	evseId := 0
+	for _, status := range statuses {
+		if status.EvseId == 0 || ToEvseStatus(status.Status) != EvseStatusCHARGING {
+			continue
+		}
+		if evseId != 0 && evseId != status.EvseId {
+			return 0, nil
+		}
+		evseId = status.EvseId
+	}
+	return evseId, nil
+}
+
+func nextChargingProfileId(profiles []*store.ChargingProfile) int {
+	id := 1
+	for _, profile := range profiles {
+		if profile.ChargingProfileId >= id {
+			id = profile.ChargingProfileId + 1
+		}
+	}
+	return id
+}
+
+// toChargingSchedule converts the OCPI charging profile into an OCPP charging
+// schedule: a profile without a start time is relative to the start of the
+// transaction.
+func toChargingSchedule(profile ChargingProfile) (store.ChargingProfileKind, store.ChargingSchedule, error) {
+	if profile.ChargingProfilePeriod == nil || len(*profile.ChargingProfilePeriod) == 0 {
+		return "", store.ChargingSchedule{}, errors.New("charging profile has no periods")
+	}
+
+	schedule := store.ChargingSchedule{
+		ChargingRateUnit: store.ChargingRateUnit(profile.ChargingRateUnit),
+	}
+	for _, period := range *profile.ChargingProfilePeriod {
+		schedule.ChargingSchedulePeriods = append(schedule.ChargingSchedulePeriods, store.ChargingSchedulePeriod{
+			StartPeriod: int(period.StartPeriod),
+			Limit:       toOcppDecimal(period.Limit),
+		})
+	}
+	if profile.Duration != nil {
+		duration := int(*profile.Duration)
+		schedule.Duration = &duration
+	}
+	if profile.MinChargingRate != nil {
+		minChargingRate := toOcppDecimal(*profile.MinChargingRate)
+		schedule.MinChargingRate = &minChargingRate
+	}
+
+	if profile.StartDateTime == nil {
+		return store.ChargingProfileKindRelative, schedule, nil
+	}
+	startSchedule, err := time.Parse(time.RFC3339, *profile.StartDateTime)
+	if err != nil {
+		return "", store.ChargingSchedule{}, err
+	}
+	startSchedule = startSchedule.UTC()
+	schedule.StartSchedule = &startSchedule
+	return store.ChargingProfileKindAbsolute, schedule, nil
+}
+
+// toOcppDecimal converts an OCPI rate to an OCPP decimal: OCPP only accepts a
+// single digit fraction.
+func toOcppDecimal(f float32) float64 {
+	return math.Round(float64(f)*10) / 10
+}
+
+func fromCompositeSchedule16(resp *ocpp16.GetCompositeScheduleResponseJson, now time.Time) ActiveChargingProfile {
+	chargingSchedule := resp.ChargingSchedule
+	startDateTime := now.UTC().Format(time.RFC3339)
+	if resp.ScheduleStart != nil {
+		startDateTime = *resp.ScheduleStart
+	} else if chargingSchedule.StartSchedule != nil {
+		startDateTime = *chargingSchedule.StartSchedule
+	}
+
+	periods := make([]ChargingProfilePeriod, 0, len(chargingSchedule.ChargingSchedulePeriod))
+	for _, period := range chargingSchedule.ChargingSchedulePeriod {
+		periods = append(periods, ChargingProfilePeriod{
+			StartPeriod: int32(period.StartPeriod),
+			Limit:       float32(period.Limit),
+		})
+	}
+	profile := ChargingProfile{
+		ChargingRateUnit:      ChargingProfileChargingRateUnit(chargingSchedule.ChargingRateUnit),
+		ChargingProfilePeriod: &periods,
+		StartDateTime:         &startDateTime,
+	}
+	if chargingSchedule.Duration != nil {
+		duration := int32(*chargingSchedule.Duration)
+		profile.Duration = &duration
+	}
+	if chargingSchedule.MinChargingRate != nil {
+		minChargingRate := float32(*chargingSchedule.MinChargingRate)
+		profile.MinChargingRate = &minChargingRate
+	}
+
+	return ActiveChargingProfile{
+		ChargingProfile: profile,
+		StartDateTime:   startDateTime,
+	}
+}
+
+func fromCompositeSchedule201(schedule *ocpp201.CompositeScheduleType) ActiveChargingProfile {
+	periods := make([]ChargingProfilePeriod, 0, len(schedule.ChargingSchedulePeriod))
+	for _, period := range schedule.ChargingSchedulePeriod {
+		periods = append(periods, ChargingProfilePeriod{
+			StartPeriod: int32(period.StartPeriod),
+			Limit:       float32(period.Limit),
+		})
+	}
+	duration := int32(schedule.Duration)
+
+	return ActiveChargingProfile{
+		ChargingProfile: ChargingProfile{
+			ChargingRateUnit:      ChargingProfileChargingRateUnit(schedule.ChargingRateUnit),
+			ChargingProfilePeriod: &periods,
+			Duration:              &duration,
+			StartDateTime:         &schedule.ScheduleStart,
+		},
+		StartDateTime: schedule.ScheduleStart,
+	}
+}
+
+func chargingProfileResult(accepted bool) GenericChargingProfileResult {
+	if accepted {
+		return GenericChargingProfileResult{Result: GenericChargingProfileResultResultACCEPTED}
+	}
+	return GenericChargingProfileResult{Result: GenericChargingProfileResultResultREJECTED}
+}
+
+func activeChargingProfileResult(profile ActiveChargingProfile) GenericChargingProfileResult {
+	return GenericChargingProfileResult{
+		Result:  GenericChargingProfileResultResultACCEPTED,
+		Profile: &profile,
+	}
+}
+
+// clearChargingProfileResult maps the response to a ClearChargingProfile: the
+// charge station responds Unknown if it doesn't have the profile.
+func clearChargingProfileResult(accepted bool) GenericChargingProfileResult {
+	if accepted {
+		return GenericChargingProfileResult{Result: GenericChargingProfileResultResultACCEPTED}
+	}
+	return GenericChargingProfileResult{Result: GenericChargingProfileResultResultUNKNOWN}
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// setupChargingProfileOcpi creates an OCPI instance that sends charging profile
+// requests to a charge station with the OCPP version and a transaction that is
+// in progress on the charge station's second EVSE. The eMSP receives the
+// charging profile results.
+func setupChargingProfileOcpi(t *testing.T, ocppVersion, transactionId string, callMaker *fakeSyncCallMaker) (*ocpi.OCPI, store.Engine, string, <-chan ocpi.GenericChargingProfileResult) {
+	ocpiApi, engine, _, _ := setupCommandOcpi(t, ocppVersion, callMaker)
+
+	results := make(chan ocpi.GenericChargingProfileResult, 1)
+	resultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/ocpi/2.2/sender/chargingprofiles/result/12345", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var result ocpi.GenericChargingProfileResult
+		require.NoError(t, json.Unmarshal(b, &result))
+		results <- result
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(resultServer.Close)
+
+	ctx := context.Background()
+	err := engine.CreateTransaction(ctx, "cs001", transactionId, 0, "DEADBEEF", "ISO14443",
+		[]store.MeterValue{{Timestamp: "2024-01-01T10:00:00Z"}}, 0, false)
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{
+		EvseId:      2,
+		ConnectorId: 1,
+		Status:      "Charging",
+		Timestamp:   time.Now(),
+	})
+	require.NoError(t, err)
+
+	return ocpiApi, engine, resultServer.URL + "/ocpi/2.2/sender/chargingprofiles/result/12345", results
+}
+
+func waitForChargingProfileResult(t *testing.T, results <-chan ocpi.GenericChargingProfileResult) ocpi.GenericChargingProfileResult {
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for charging profile result")
+		return ocpi.GenericChargingProfileResult{}
+	}
+}
+
+func TestSetChargingProfile(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, engine, responseUrl, results := setupChargingProfileOcpi(t, "1.6", "00000000-0000-0000-0000-00000000007b", callMaker)
+
+		resp, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "TWK", "ebd34292-5273-57a9-8e6e-378ff355ef78", ocpi.SetChargingProfile{
+			ChargingProfile: ocpi.ChargingProfile{
+				ChargingRateUnit: ocpi.W,
+				ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+					{StartPeriod: 0, Limit: 7400},
+					{StartPeriod: 3600, Limit: 3700},
+				},
+			},
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &ocpi.ChargingProfileResponse{Result: ocpi.ChargingProfileResponseResultACCEPTED, Timeout: 30}, resp)
+
+		result := waitForChargingProfileResult(t, results)
+		assert.Equal(t, ocpi.GenericChargingProfileResultResultACCEPTED, result.Result)
+		assert.Equal(t, &ocpp16.SetChargingProfileJson{
+			ConnectorId: 2,
+			CsChargingProfiles: ocpp16.SetChargingProfileJsonCsChargingProfiles{
+				ChargingProfileId:      1,
+				ChargingProfileKind:    ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKindRelative,
+				ChargingProfilePurpose: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile,
+				ChargingSchedule: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingSchedule{
+					ChargingRateUnit: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnitW,
+					ChargingSchedulePeriod: []ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+						{StartPeriod: 0, Limit: 7400},
+						{StartPeriod: 3600, Limit: 3700},
+					},
+				},
+				TransactionId: makePtr(123),
+			},
+		}, callMaker.lastRequest())
+
+		profiles, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, profiles)
+		require.Len(t, profiles.ChargingProfiles, 1)
+		assert.Equal(t, store.ChargingProfileStatusAccepted, profiles.ChargingProfiles[0].Status)
+		assert.Equal(t, makePtr("123"), profiles.ChargingProfiles[0].TransactionId)
+	})
+
+	t.Run("ocpp 2.0.1", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Rejected"}`}
+		ocpiApi, engine, responseUrl, results := setupChargingProfileOcpi(t, "2.0.1", "tx001", callMaker)
+
+		resp, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "TWK", "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", ocpi.SetChargingProfile{
+			ChargingProfile: ocpi.ChargingProfile{
+				ChargingRateUnit: ocpi.A,
+				ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+					{StartPeriod: 0, Limit: 16.3},
+				},
+				StartDateTime: makePtr("2024-01-01T12:00:00Z"),
+			},
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, resp.Result)
+
+		result := waitForChargingProfileResult(t, results)
+		assert.Equal(t, ocpi.GenericChargingProfileResultResultREJECTED, result.Result)
+		req, ok := callMaker.lastRequest().(*ocpp201.SetChargingProfileRequestJson)
+		require.True(t, ok)
+		assert.Equal(t, 2, req.EvseId)
+		assert.Equal(t, makePtr("tx001"), req.ChargingProfile.TransactionId)
+		assert.Equal(t, ocpp201.ChargingProfileKindEnumTypeAbsolute, req.ChargingProfile.ChargingProfileKind)
+		require.Len(t, req.ChargingProfile.ChargingSchedule, 1)
+		assert.Equal(t, makePtr("2024-01-01T12:00:00Z"), req.ChargingProfile.ChargingSchedule[0].StartSchedule)
+		assert.Equal(t, 16.3, req.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod[0].Limit)
+
+		profiles, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+		require.NoError(t, err)
+		assert.Nil(t, profiles)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, _, responseUrl, _ := setupChargingProfileOcpi(t, "1.6", "00000000-0000-0000-0000-00000000007b", callMaker)
+
+		resp, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "TWK", "unknown", ocpi.SetChargingProfile{
+			ChargingProfile: ocpi.ChargingProfile{
+				ChargingRateUnit:      ocpi.A,
+				ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{{StartPeriod: 0, Limit: 16}},
+			},
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.ChargingProfileResponseResultUNKNOWNSESSION, resp.Result)
+		assert.Nil(t, callMaker.lastRequest())
+	})
+
+	t.Run("unknown evse", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, engine, responseUrl, _ := setupChargingProfileOcpi(t, "2.0.1", "tx001", callMaker)
+		err := engine.SetChargeStationConnectorStatus(context.Background(), "cs001", &store.ConnectorStatus{
+			EvseId:      1,
+			ConnectorId: 1,
+			Status:      "Occupied",
+			Timestamp:   time.Now(),
+		})
+		require.NoError(t, err)
+
+		resp, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "TWK", "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", ocpi.SetChargingProfile{
+			ChargingProfile: ocpi.ChargingProfile{
+				ChargingRateUnit:      ocpi.A,
+				ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{{StartPeriod: 0, Limit: 16}},
+			},
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.ChargingProfileResponseResultREJECTED, resp.Result)
+		assert.Nil(t, callMaker.lastRequest())
+	})
+}
+
+func TestGetActiveChargingProfile(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{
+		"status":"Accepted",
+		"schedule":{
+			"evseId":2,
+			"duration":3600,
+			"scheduleStart":"2024-01-01T12:00:00Z",
+			"chargingRateUnit":"A",
+			"chargingSchedulePeriod":[{"startPeriod":0,"limit":16},{"startPeriod":1800,"limit":8}]
+		}
+	}`}
+	ocpiApi, _, responseUrl, results := setupChargingProfileOcpi(t, "2.0.1", "tx001", callMaker)
+
+	resp, err := ocpiApi.GetActiveChargingProfile(context.Background(), "GB", "TWK", "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", 3600, responseUrl)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, resp.Result)
+
+	result := waitForChargingProfileResult(t, results)
+	assert.Equal(t, ocpi.GenericChargingProfileResult{
+		Result: ocpi.GenericChargingProfileResultResultACCEPTED,
+		Profile: &ocpi.ActiveChargingProfile{
+			StartDateTime: "2024-01-01T12:00:00Z",
+			ChargingProfile: ocpi.ChargingProfile{
+				ChargingRateUnit: ocpi.A,
+				ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+					{StartPeriod: 0, Limit: 16},
+					{StartPeriod: 1800, Limit: 8},
+				},
+				Duration:      makePtr[int32](3600),
+				StartDateTime: makePtr("2024-01-01T12:00:00Z"),
+			},
+		},
+	}, result)
+	assert.Equal(t, &ocpp201.GetCompositeScheduleRequestJson{
+		EvseId:   2,
+		Duration: 3600,
+	}, callMaker.lastRequest())
+}
+
+func TestClearChargingProfile(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, engine, responseUrl, results := setupChargingProfileOcpi(t, "1.6", "00000000-0000-0000-0000-00000000007b", callMaker)
+		err := engine.UpdateChargeStationChargingProfiles(context.Background(), "cs001", &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{
+				{
+					ChargingProfileId:      5,
+					ConnectorId:            2,
+					TransactionId:          makePtr("123"),
+					ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+					ChargingProfileKind:    store.ChargingProfileKindRelative,
+					ChargingSchedule: store.ChargingSchedule{
+						ChargingRateUnit:        store.ChargingRateUnitA,
+						ChargingSchedulePeriods: []store.ChargingSchedulePeriod{{StartPeriod: 0, Limit: 16}},
+					},
+					Status: store.ChargingProfileStatusAccepted,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		resp, err := ocpiApi.ClearChargingProfile(context.Background(), "GB", "TWK", "ebd34292-5273-57a9-8e6e-378ff355ef78", responseUrl)
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, resp.Result)
+
+		result := waitForChargingProfileResult(t, results)
+		assert.Equal(t, ocpi.GenericChargingProfileResultResultACCEPTED, result.Result)
+		assert.Equal(t, &ocpp16.ClearChargingProfileJson{
+			Id: makePtr(5),
+		}, callMaker.lastRequest())
+	})
+
+	t.Run("no charging profile", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, _, responseUrl, _ := setupChargingProfileOcpi(t, "2.0.1", "tx001", callMaker)
+
+		resp, err := ocpiApi.ClearChargingProfile(context.Background(), "GB", "TWK", "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", responseUrl)
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.ChargingProfileResponseResultREJECTED, resp.Result)
+		assert.Nil(t, callMaker.lastRequest())
+	})
+}
@@ -0,0 +1,521 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	handlers16 "github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"golang.org/x/exp/slog"
+)
+
+// defaultCommandTimeout is how long the CSMS waits for a charge station to
+// respond to a command before reporting a TIMEOUT result to the eMSP
+const defaultCommandTimeout = 30 * time.Second
+
+// command is the OCPP call that is made to a charge station for an OCPI command
+type command struct {
+	request  ocpp.Request
+	response ocpp.Response
+	// result converts the response into the OCPI command result
+	result func() CommandResultResult
+}
+
+// commandTarget is the charge station that an OCPI command is sent to
+type commandTarget struct {
+	chargeStationId string
+	ocppVersion     string
+	// evseId is the id of the EVSE on the charge station, 0 if the OCPI EVSE
+	// is the whole charge station
+	evseId int
+	// mapping is the mapping of the OCPI EVSE, nil if the command is not for
+	// an EVSE
+	mapping *store.EvseMapping
+}
+
+// SetCallMakers sets the call makers used to send commands to OCPP 1.6 and
+// OCPP 2.0.1 charge stations
+func (o *OCPI) SetCallMakers(v16CallMaker, v201CallMaker handlers.SyncCallMaker) {
+	o.v16CallMaker = v16CallMaker
+	o.v201CallMaker = v201CallMaker
+}
+
+// SetCommandTimeout sets how long to wait for a charge station to respond to a
+// command
+func (o *OCPI) SetCommandTimeout(timeout time.Duration) {
+	o.commandTimeout = timeout
+}
+
+func (o *OCPI) StartSession(ctx context.Context, countryCode, partyId string, startSession StartSession) (*CommandResponse, error) {
+	if startSession.EvseUid == nil {
+		return rejectedCommand("evse_uid is required"), nil
+	}
+	target, err := o.evseCommandTarget(ctx, startSession.LocationId, *startSession.EvseUid)
+	if err != nil || target == nil {
+		return rejectedCommand("unknown evse"), err
+	}
+	connectorId := target.evseId
+	if connectorId == 0 && startSession.ConnectorId != nil {
+		connectorId, err = services.OcppConnectorId(target.mapping, *startSession.ConnectorId)
+		if err != nil {
+			return rejectedCommand("unknown connector"), nil
+		}
+	}
+
+	// the charge station will authorize the token when the transaction starts
+	err = o.SetToken(ctx, startSession.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *command
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.RemoteStartTransactionResponseJson)
+		cmd = &command{
+			request: &ocpp16.RemoteStartTransactionJson{
+				ConnectorId: &connectorId,
+				IdTag:       startSession.Token.Uid,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return acceptedOrRejected(resp.Status == ocpp16.RemoteStartTransactionResponseJsonStatusAccepted)
+			},
+		}
+	} else {
+		var evseId *int
+		if target.evseId != 0 {
+			evseId = &target.evseId
+		}
+		resp := new(ocpp201.RequestStartTransactionResponseJson)
+		cmd = &command{
+			request: &ocpp201.RequestStartTransactionRequestJson{
+				EvseId: evseId,
+				IdToken: ocpp201.IdTokenType{
+					IdToken: startSession.Token.Uid,
+					Type:    idTokenType(startSession.Token.Type),
+				},
+				RemoteStartId: int(rand.Int31()),
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return acceptedOrRejected(resp.Status == ocpp201.RequestStartStopStatusEnumTypeAccepted)
+			},
+		}
+	}
+
+	return o.runCommand(ctx, countryCode, partyId, startSession.ResponseUrl, target, cmd, nil)
+}
+
+func (o *OCPI) StopSession(ctx context.Context, countryCode, partyId string, stopSession StopSession) (*CommandResponse, error) {
+	transaction, err := o.findTransaction(ctx, stopSession.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil || transaction.EndedSeqNo != 0 {
+		return &CommandResponse{Result: CommandResponseResultUNKNOWNSESSION}, nil
+	}
+	target, err := o.chargeStationCommandTarget(ctx, transaction.ChargeStationId, 0)
+	if err != nil || target == nil {
+		return rejectedCommand("charge station is not connected"), err
+	}
+
+	var cmd *command
+	if target.ocppVersion == "1.6" {
+		transactionId, err := handlers16.ConvertFromUUID(transaction.TransactionId)
+		if err != nil {
+			return nil, err
+		}
+		resp := new(ocpp16.RemoteStopTransactionResponseJson)
+		cmd = &command{
+			request: &ocpp16.RemoteStopTransactionJson{
+				TransactionId: transactionId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return acceptedOrRejected(resp.Status == ocpp16.RemoteStopTransactionResponseJsonStatusAccepted)
+			},
+		}
+	} else {
+		resp := new(ocpp201.RequestStopTransactionResponseJson)
+		cmd = &command{
+			request: &ocpp201.RequestStopTransactionRequestJson{
+				TransactionId: transaction.TransactionId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return acceptedOrRejected(resp.Status == ocpp201.RequestStartStopStatusEnumTypeAccepted)
+			},
+		}
+	}
+
+	return o.runCommand(ctx, countryCode, partyId, stopSession.ResponseUrl, target, cmd, nil)
+}
+
+func (o *OCPI) ReserveNow(ctx context.Context, countryCode, partyId string, reserveNow ReserveNow) (*CommandResponse, error) {
+	if reserveNow.EvseUid == nil {
+		return rejectedCommand("evse_uid is required"), nil
+	}
+	expiryDate, err := time.Parse(time.RFC3339, reserveNow.ExpiryDate)
+	if err != nil {
+		return rejectedCommand("invalid expiry_date"), nil
+	}
+	target, err := o.evseCommandTarget(ctx, reserveNow.LocationId, *reserveNow.EvseUid)
+	if err != nil || target == nil {
+		return rejectedCommand("unknown evse"), err
+	}
+
+	// a reservation with the same id replaces the existing reservation
+	existing, err := o.store.LookupReservation(ctx, countryCode, partyId, reserveNow.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	reservation := &store.Reservation{
+		CountryCode:       countryCode,
+		PartyId:           partyId,
+		Id:                reserveNow.ReservationId,
+		ChargeStationId:   target.chargeStationId,
+		OcppReservationId: int(rand.Int31()),
+		ExpiryDate:        expiryDate.UTC(),
+	}
+	if existing != nil {
+		reservation.OcppReservationId = existing.OcppReservationId
+	}
+
+	var cmd *command
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.ReserveNowResponseJson)
+		cmd = &command{
+			request: &ocpp16.ReserveNowJson{
+				ConnectorId:   target.evseId,
+				ExpiryDate:    reservation.ExpiryDate.Format(time.RFC3339),
+				IdTag:         reserveNow.Token.Uid,
+				ReservationId: reservation.OcppReservationId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return reserveNowResult(string(resp.Status))
+			},
+		}
+	} else {
+		var evseId *int
+		if target.evseId != 0 {
+			evseId = &target.evseId
+		}
+		resp := new(ocpp201.ReserveNowResponseJson)
+		cmd = &command{
+			request: &ocpp201.ReserveNowRequestJson{
+				Id:             reservation.OcppReservationId,
+				ExpiryDateTime: reservation.ExpiryDate.Format(time.RFC3339),
+				EvseId:         evseId,
+				IdToken: ocpp201.IdTokenType{
+					IdToken: reserveNow.Token.Uid,
+					Type:    idTokenType(reserveNow.Token.Type),
+				},
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return reserveNowResult(string(resp.Status))
+			},
+		}
+	}
+
+	err = o.store.SetReservation(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.runCommand(ctx, countryCode, partyId, reserveNow.ResponseUrl, target, cmd, func(ctx context.Context, result CommandResultResult) error {
+		if result == CommandResultResultACCEPTED || existing != nil {
+			return nil
+		}
+		return o.store.DeleteReservation(ctx, countryCode, partyId, reservation.Id)
+	})
+}
+
+func (o *OCPI) CancelReservation(ctx context.Context, countryCode, partyId string, cancelReservation CancelReservation) (*CommandResponse, error) {
+	reservation, err := o.store.LookupReservation(ctx, countryCode, partyId, cancelReservation.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return rejectedCommand("unknown reservation"), nil
+	}
+	target, err := o.chargeStationCommandTarget(ctx, reservation.ChargeStationId, 0)
+	if err != nil || target == nil {
+		return rejectedCommand("charge station is not connected"), err
+	}
+
+	var cmd *command
+	if target.ocppVersion == "1.6" {
+		resp := new(ocpp16.CancelReservationResponseJson)
+		cmd = &command{
+			request: &ocpp16.CancelReservationJson{
+				ReservationId: reservation.OcppReservationId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return cancelReservationResult(resp.Status == ocpp16.CancelReservationResponseJsonStatusAccepted)
+			},
+		}
+	} else {
+		resp := new(ocpp201.CancelReservationResponseJson)
+		cmd = &command{
+			request: &ocpp201.CancelReservationRequestJson{
+				ReservationId: reservation.OcppReservationId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				return cancelReservationResult(resp.Status == ocpp201.CancelReservationStatusEnumTypeAccepted)
+			},
+		}
+	}
+
+	return o.runCommand(ctx, countryCode, partyId, cancelReservation.ResponseUrl, target, cmd, func(ctx context.Context, result CommandResultResult) error {
+		if result != CommandResultResultACCEPTED && result != CommandResultResultUNKNOWNRESERVATION {
+			return nil
+		}
+		return o.store.DeleteReservation(ctx, countryCode, partyId, reservation.Id)
+	})
+}
+
+func (o *OCPI) UnlockConnector(ctx context.Context, countryCode, partyId string, unlockConnector UnlockConnector) (*CommandResponse, error) {
+	target, err := o.evseCommandTarget(ctx, unlockConnector.LocationId, unlockConnector.EvseUid)
+	if err != nil || target == nil {
+		return rejectedCommand("unknown evse"), err
+	}
+
+	var cmd *command
+	if target.ocppVersion == "1.6" {
+		// an OCPP 1.6 connector is an OCPI EVSE
+		connectorId := target.evseId
+		if connectorId == 0 {
+			connectorId, err = services.OcppConnectorId(target.mapping, unlockConnector.ConnectorId)
+			if err != nil {
+				return rejectedCommand("unknown connector"), nil
+			}
+		}
+		resp := new(ocpp16.UnlockConnectorResponseJson)
+		cmd = &command{
+			request: &ocpp16.UnlockConnectorJson{
+				ConnectorId: connectorId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				switch resp.Status {
+				case ocpp16.UnlockConnectorResponseJsonStatusUnlocked:
+					return CommandResultResultACCEPTED
+				case ocpp16.UnlockConnectorResponseJsonStatusNotSupported:
+					return CommandResultResultNOTSUPPORTED
+				default:
+					return CommandResultResultFAILED
+				}
+			},
+		}
+	} else {
+		if target.evseId == 0 {
+			return rejectedCommand("evse is not linked to an evse of the charge station"), nil
+		}
+		connectorId, err := services.OcppConnectorId(target.mapping, unlockConnector.ConnectorId)
+		if err != nil {
+			return rejectedCommand("unknown connector"), nil
+		}
+		resp := new(ocpp201.UnlockConnectorResponseJson)
+		cmd = &command{
+			request: &ocpp201.UnlockConnectorRequestJson{
+				EvseId:      target.evseId,
+				ConnectorId: connectorId,
+			},
+			response: resp,
+			result: func() CommandResultResult {
+				switch resp.Status {
+				case ocpp201.UnlockStatusEnumTypeUnlocked:
+					return CommandResultResultACCEPTED
+				case ocpp201.UnlockStatusEnumTypeUnlockFailed:
+					return CommandResultResultFAILED
+				default:
+					return CommandResultResultREJECTED
+				}
+			},
+		}
+	}
+
+	return o.runCommand(ctx, countryCode, partyId, unlockConnector.ResponseUrl, target, cmd, nil)
+}
+
+// runCommand sends the command to the charge station in the background and
+// posts the command result to the eMSP's response URL once the charge station
+// responds, or once the command times out. The onResult function, if any, is
+// called with the result before it is posted.
+func (o *OCPI) runCommand(ctx context.Context, countryCode, partyId, responseUrl string, target *commandTarget,
+	cmd *command, onResult func(ctx context.Context, result CommandResultResult) error) (*CommandResponse, error) {
+	party, err := o.store.GetPartyDetails(ctx, "EMSP", countryCode, partyId)
+	if err != nil {
+		return nil, err
+	}
+	if party == nil {
+		return rejectedCommand("unknown party"), nil
+	}
+
+	callMaker := o.v16CallMaker
+	if target.ocppVersion == "2.0.1" {
+		callMaker = o.v201CallMaker
+	}
+	if callMaker == nil {
+		return &CommandResponse{Result: CommandResponseResultNOTSUPPORTED}, nil
+	}
+
+	// the command outlives the request that sent it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		callCtx, cancel := context.WithTimeout(ctx, o.commandTimeout)
+		defer cancel()
+
+		var result CommandResultResult
+		err := callMaker.Call(callCtx, target.chargeStationId, cmd.request, cmd.response)
+		if err != nil {
+			slog.Warn("ocpi command failed", "chargeStationId", target.chargeStationId, "err", err)
+			result = commandErrorResult(err)
+		} else {
+			result = cmd.result()
+		}
+
+		if onResult != nil {
+			if err := onResult(ctx, result); err != nil {
+				slog.Error("handling ocpi command result", "chargeStationId", target.chargeStationId, "err", err)
+			}
+		}
+		err = o.sendToParty(ctx, http.MethodPost, responseUrl, party, CommandResult{Result: result})
+		if err != nil {
+			slog.Error("posting ocpi command result", "responseUrl", responseUrl, "err", err)
+		}
+	}()
+
+	return &CommandResponse{
+		Result:  CommandResponseResultACCEPTED,
+		Timeout: int32(o.commandTimeout / time.Second),
+	}, nil
+}
+
+// evseCommandTarget returns the charge station that makes up the EVSE of the
+// location or nil if there is no such EVSE or its charge station has never
+// connected.
+func (o *OCPI) evseCommandTarget(ctx context.Context, locationId, evseUid string) (*commandTarget, error) {
+	location, err := o.store.LookupLocation(ctx, locationId)
+	if err != nil || location == nil || location.Evses == nil {
+		return nil, err
+	}
+	for _, evse := range *location.Evses {
+		if evse.Uid != evseUid {
+			continue
+		}
+		mapping, err := o.evseMapping.MapEvse(ctx, locationId, &evse)
+		if err != nil || mapping == nil {
+			return nil, err
+		}
+		target, err := o.chargeStationCommandTarget(ctx, mapping.ChargeStationId, mapping.ChargeStationEvseId)
+		if err != nil || target == nil {
+			return nil, err
+		}
+		target.mapping = mapping
+		return target, nil
+	}
+	return nil, nil
+}
+
+// chargeStationCommandTarget returns the charge station or nil if the charge
+// station has never connected.
+func (o *OCPI) chargeStationCommandTarget(ctx context.Context, chargeStationId string, evseId int) (*commandTarget, error) {
+	details, err := o.store.LookupChargeStationRuntimeDetails(ctx, chargeStationId)
+	if err != nil || details == nil {
+		return nil, err
+	}
+	return &commandTarget{
+		chargeStationId: chargeStationId,
+		ocppVersion:     details.OcppVersion,
+		evseId:          evseId,
+	}, nil
+}
+
+// findTransaction returns the transaction for the OCPI session or nil if there
+// is no such transaction.
+func (o *OCPI) findTransaction(ctx context.Context, sessionId string) (*store.Transaction, error) {
+	transactions, err := o.store.Transactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		if newSessionId(transaction.ChargeStationId, transaction.TransactionId) == sessionId {
+			return transaction, nil
+		}
+	}
+	return nil, nil
+}
+
+func rejectedCommand(message string) *CommandResponse {
+	return &CommandResponse{
+		Result:  CommandResponseResultREJECTED,
+		Message: &DisplayText{Language: "en", Text: message},
+	}
+}
+
+// commandErrorResult is the result of a command that the charge station did not
+// respond to successfully.
+func commandErrorResult(err error) CommandResultResult {
+	var callErr *transport.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CommandResultResultTIMEOUT
+	case errors.As(err, &callErr) && (callErr.ErrorCode == transport.ErrorNotImplemented || callErr.ErrorCode == transport.ErrorNotSupported):
+		return CommandResultResultNOTSUPPORTED
+	default:
+		return CommandResultResultFAILED
+	}
+}
+
+func acceptedOrRejected(accepted bool) CommandResultResult {
+	if accepted {
+		return CommandResultResultACCEPTED
+	}
+	return CommandResultResultREJECTED
+}
+
+func cancelReservationResult(accepted bool) CommandResultResult {
+	if accepted {
+		return CommandResultResultACCEPTED
+	}
+	return CommandResultResultUNKNOWNRESERVATION
+}
+
+// reserveNowResult maps the OCPP 1.6 or 2.0.1 ReserveNow status to the command
+// result: both versions use the same statuses.
+func reserveNowResult(status string) CommandResultResult {
+	switch status {
+	case "Accepted":
+		return CommandResultResultACCEPTED
+	case "Occupied":
+		return CommandResultResultEVSEOCCUPIED
+	case "Faulted", "Unavailable":
+		return CommandResultResultEVSEINOPERATIVE
+	default:
+		return CommandResultResultREJECTED
+	}
+}
+
+// idTokenType maps the OCPI token type to the OCPP 2.0.1 id token type.
+func idTokenType(tokenType TokenType) ocpp201.IdTokenEnumType {
+	if tokenType == TokenTypeRFID {
+		return ocpp201.IdTokenEnumTypeISO14443
+	}
+	return ocpp201.IdTokenEnumTypeCentral
+}
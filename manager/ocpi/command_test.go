@@ -0,0 +1,377 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"k8s.io/utils/clock"
+)
+
+// fakeSyncCallMaker responds to calls with the response or the error. If there
+// is neither it waits until the call times out.
+type fakeSyncCallMaker struct {
+	mu              sync.Mutex
+	chargeStationId string
+	request         ocpp.Request
+	response        string
+	err             error
+}
+
+func (f *fakeSyncCallMaker) Call(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chargeStationId = chargeStationId
+	f.request = request
+	if f.err != nil {
+		return f.err
+	}
+	if f.response == "" {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return json.Unmarshal([]byte(f.response), response)
+}
+
+func (f *fakeSyncCallMaker) lastRequest() ocpp.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.request
+}
+
+// setupCommandOcpi creates an OCPI instance that sends commands to a charge
+// station with the OCPP version and an eMSP that receives the command results.
+func setupCommandOcpi(t *testing.T, ocppVersion string, callMaker *fakeSyncCallMaker) (*ocpi.OCPI, store.Engine, string, <-chan ocpi.CommandResult) {
+	results := make(chan ocpi.CommandResult, 1)
+	receiverServer, closeServer := newReceiver("commands", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/commands/12345", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var result ocpi.CommandResult
+		require.NoError(t, json.Unmarshal(b, &result))
+		results <- result
+		w.WriteHeader(http.StatusOK)
+	})
+	t.Cleanup(closeServer)
+
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	ocpiApi.SetCallMakers(callMaker, callMaker)
+	ctx := context.Background()
+	err := ocpiApi.SetCredentials(ctx, "some-token-123", ocpi.Credentials{
+		Roles: []ocpi.CredentialsRole{
+			{
+				CountryCode: "GB",
+				PartyId:     "TWK",
+				Role:        ocpi.CredentialsRoleRoleEMSP,
+			},
+		},
+		Token: "some-token-456",
+		Url:   receiverServer.URL + "/ocpi/versions",
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{OcppVersion: ocppVersion})
+	require.NoError(t, err)
+	err = engine.SetLocation(ctx, chargeStationLocation())
+	require.NoError(t, err)
+
+	return ocpiApi, engine, receiverServer.URL + "/ocpi/receiver/2.2/commands/12345", results
+}
+
+func waitForCommandResult(t *testing.T, results <-chan ocpi.CommandResult) ocpi.CommandResult {
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for command result")
+		return ocpi.CommandResult{}
+	}
+}
+
+func commandToken() ocpi.Token {
+	return ocpi.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        ocpi.TokenTypeRFID,
+		Uid:         "DEADBEEF",
+		ContractId:  "GBTWKTWTW000018",
+		Issuer:      "Zynka-tech",
+		Valid:       true,
+		Whitelist:   ocpi.ALWAYS,
+	}
+}
+
+func TestStartSessionCommand(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, engine, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+
+		resp, err := ocpiApi.StartSession(context.Background(), "GB", "TWK", ocpi.StartSession{
+			EvseUid:     makePtr("GBTWKEcs001-2"),
+			ConnectorId: makePtr("1"),
+			LocationId:  "loc001",
+			ResponseUrl: responseUrl,
+			Token:       commandToken(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &ocpi.CommandResponse{Result: ocpi.CommandResponseResultACCEPTED, Timeout: 30}, resp)
+
+		result := waitForCommandResult(t, results)
+		assert.Equal(t, ocpi.CommandResultResultACCEPTED, result.Result)
+		assert.Equal(t, &ocpp16.RemoteStartTransactionJson{
+			ConnectorId: makePtr(2),
+			IdTag:       "DEADBEEF",
+		}, callMaker.lastRequest())
+
+		token, err := engine.LookupToken(context.Background(), "DEADBEEF")
+		require.NoError(t, err)
+		assert.NotNil(t, token)
+	})
+
+	t.Run("ocpp 2.0.1", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Rejected"}`}
+		ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "2.0.1", callMaker)
+
+		resp, err := ocpiApi.StartSession(context.Background(), "GB", "TWK", ocpi.StartSession{
+			EvseUid:     makePtr("GBTWKEcs001-1"),
+			LocationId:  "loc001",
+			ResponseUrl: responseUrl,
+			Token:       commandToken(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResponseResultACCEPTED, resp.Result)
+
+		result := waitForCommandResult(t, results)
+		assert.Equal(t, ocpi.CommandResultResultREJECTED, result.Result)
+		req, ok := callMaker.lastRequest().(*ocpp201.RequestStartTransactionRequestJson)
+		require.True(t, ok)
+		assert.Equal(t, makePtr(1), req.EvseId)
+		assert.Equal(t, ocpp201.IdTokenType{IdToken: "DEADBEEF", Type: ocpp201.IdTokenEnumTypeISO14443}, req.IdToken)
+	})
+
+	t.Run("unknown evse", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, _, responseUrl, _ := setupCommandOcpi(t, "1.6", callMaker)
+
+		resp, err := ocpiApi.StartSession(context.Background(), "GB", "TWK", ocpi.StartSession{
+			EvseUid:     makePtr("GBTWKEcs999-1"),
+			LocationId:  "loc001",
+			ResponseUrl: responseUrl,
+			Token:       commandToken(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResponseResultREJECTED, resp.Result)
+		assert.Nil(t, callMaker.lastRequest())
+	})
+}
+
+func TestStopSessionCommand(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, engine, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+		err := engine.CreateTransaction(context.Background(), "cs001", "00000000-0000-0000-0000-00000000007b", 0, "DEADBEEF", "ISO14443",
+			[]store.MeterValue{{Timestamp: "2024-01-01T10:00:00Z"}}, 0, false)
+		require.NoError(t, err)
+
+		resp, err := ocpiApi.StopSession(context.Background(), "GB", "TWK", ocpi.StopSession{
+			SessionId:   "ebd34292-5273-57a9-8e6e-378ff355ef78",
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResponseResultACCEPTED, resp.Result)
+
+		result := waitForCommandResult(t, results)
+		assert.Equal(t, ocpi.CommandResultResultACCEPTED, result.Result)
+		assert.Equal(t, &ocpp16.RemoteStopTransactionJson{TransactionId: 123}, callMaker.lastRequest())
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+		ocpiApi, _, responseUrl, _ := setupCommandOcpi(t, "2.0.1", callMaker)
+
+		resp, err := ocpiApi.StopSession(context.Background(), "GB", "TWK", ocpi.StopSession{
+			SessionId:   "tx001",
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResponseResultUNKNOWNSESSION, resp.Result)
+		assert.Nil(t, callMaker.lastRequest())
+	})
+}
+
+func TestReserveNowAndCancelReservationCommands(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+	ocpiApi, engine, responseUrl, results := setupCommandOcpi(t, "2.0.1", callMaker)
+	ctx := context.Background()
+
+	resp, err := ocpiApi.ReserveNow(ctx, "GB", "TWK", ocpi.ReserveNow{
+		EvseUid:       makePtr("GBTWKEcs001-2"),
+		ExpiryDate:    "2024-01-01T12:00:00Z",
+		LocationId:    "loc001",
+		ReservationId: "res001",
+		ResponseUrl:   responseUrl,
+		Token:         commandToken(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, resp.Result)
+	assert.Equal(t, ocpi.CommandResultResultACCEPTED, waitForCommandResult(t, results).Result)
+
+	reserveNowReq, ok := callMaker.lastRequest().(*ocpp201.ReserveNowRequestJson)
+	require.True(t, ok)
+	assert.Equal(t, makePtr(2), reserveNowReq.EvseId)
+	assert.Equal(t, "2024-01-01T12:00:00Z", reserveNowReq.ExpiryDateTime)
+
+	reservation, err := engine.LookupReservation(ctx, "GB", "TWK", "res001")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, "cs001", reservation.ChargeStationId)
+	assert.Equal(t, reserveNowReq.Id, reservation.OcppReservationId)
+
+	resp, err = ocpiApi.CancelReservation(ctx, "GB", "TWK", ocpi.CancelReservation{
+		ReservationId: "res001",
+		ResponseUrl:   responseUrl,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, resp.Result)
+	assert.Equal(t, ocpi.CommandResultResultACCEPTED, waitForCommandResult(t, results).Result)
+	assert.Equal(t, &ocpp201.CancelReservationRequestJson{ReservationId: reservation.OcppReservationId}, callMaker.lastRequest())
+
+	reservation, err = engine.LookupReservation(ctx, "GB", "TWK", "res001")
+	require.NoError(t, err)
+	assert.Nil(t, reservation)
+}
+
+func TestReserveNowCommandForOccupiedEvse(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Occupied"}`}
+	ocpiApi, engine, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+	ctx := context.Background()
+
+	_, err := ocpiApi.ReserveNow(ctx, "GB", "TWK", ocpi.ReserveNow{
+		EvseUid:       makePtr("GBTWKEcs001-1"),
+		ExpiryDate:    "2024-01-01T12:00:00Z",
+		LocationId:    "loc001",
+		ReservationId: "res001",
+		ResponseUrl:   responseUrl,
+		Token:         commandToken(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResultResultEVSEOCCUPIED, waitForCommandResult(t, results).Result)
+
+	req, ok := callMaker.lastRequest().(*ocpp16.ReserveNowJson)
+	require.True(t, ok)
+	assert.Equal(t, 1, req.ConnectorId)
+	assert.Equal(t, "DEADBEEF", req.IdTag)
+
+	reservation, err := engine.LookupReservation(ctx, "GB", "TWK", "res001")
+	require.NoError(t, err)
+	assert.Nil(t, reservation, "a rejected reservation must not be kept")
+}
+
+func TestUnlockConnectorCommand(t *testing.T) {
+	t.Run("ocpp 1.6", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"NotSupported"}`}
+		ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+
+		_, err := ocpiApi.UnlockConnector(context.Background(), "GB", "TWK", ocpi.UnlockConnector{
+			ConnectorId: "1",
+			EvseUid:     "GBTWKEcs001-2",
+			LocationId:  "loc001",
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResultResultNOTSUPPORTED, waitForCommandResult(t, results).Result)
+		assert.Equal(t, &ocpp16.UnlockConnectorJson{ConnectorId: 2}, callMaker.lastRequest())
+	})
+
+	t.Run("ocpp 2.0.1", func(t *testing.T) {
+		callMaker := &fakeSyncCallMaker{response: `{"status":"Unlocked"}`}
+		ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "2.0.1", callMaker)
+
+		_, err := ocpiApi.UnlockConnector(context.Background(), "GB", "TWK", ocpi.UnlockConnector{
+			ConnectorId: "1",
+			EvseUid:     "GBTWKEcs001-2",
+			LocationId:  "loc001",
+			ResponseUrl: responseUrl,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ocpi.CommandResultResultACCEPTED, waitForCommandResult(t, results).Result)
+		assert.Equal(t, &ocpp201.UnlockConnectorRequestJson{EvseId: 2, ConnectorId: 1}, callMaker.lastRequest())
+	})
+}
+
+func TestCommandTimeout(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{}
+	ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "2.0.1", callMaker)
+	ocpiApi.SetCommandTimeout(10 * time.Millisecond)
+
+	_, err := ocpiApi.UnlockConnector(context.Background(), "GB", "TWK", ocpi.UnlockConnector{
+		ConnectorId: "1",
+		EvseUid:     "GBTWKEcs001-1",
+		LocationId:  "loc001",
+		ResponseUrl: responseUrl,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResultResultTIMEOUT, waitForCommandResult(t, results).Result)
+}
+
+func TestCommandNotImplementedByChargeStation(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{err: &transport.Error{ErrorCode: transport.ErrorNotImplemented}}
+	ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+
+	_, err := ocpiApi.UnlockConnector(context.Background(), "GB", "TWK", ocpi.UnlockConnector{
+		ConnectorId: "1",
+		EvseUid:     "GBTWKEcs001-1",
+		LocationId:  "loc001",
+		ResponseUrl: responseUrl,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResultResultNOTSUPPORTED, waitForCommandResult(t, results).Result)
+}
+
+func TestUnlockConnectorCommandWithEvseMapping(t *testing.T) {
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Unlocked"}`}
+	ocpiApi, engine, responseUrl, results := setupCommandOcpi(t, "2.0.1", callMaker)
+	ctx := context.Background()
+
+	location := chargeStationLocation()
+	*location.Evses = append(*location.Evses, store.Evse{
+		Uid:        "evse003",
+		Connectors: []store.Connector{{Id: "A", Standard: "IEC_62196_T2", Format: "SOCKET", PowerType: "AC_3_PHASE"}},
+	})
+	err := engine.SetLocation(ctx, location)
+	require.NoError(t, err)
+	err = engine.SetEvseMapping(ctx, &store.EvseMapping{
+		LocationId:          "loc001",
+		EvseUid:             "evse003",
+		ChargeStationId:     "cs001",
+		ChargeStationEvseId: 3,
+		Connectors:          []store.ConnectorMapping{{Id: "A", ConnectorId: 2}},
+	})
+	require.NoError(t, err)
+
+	_, err = ocpiApi.UnlockConnector(ctx, "GB", "TWK", ocpi.UnlockConnector{
+		ConnectorId: "A",
+		EvseUid:     "evse003",
+		LocationId:  "loc001",
+		ResponseUrl: responseUrl,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResultResultACCEPTED, waitForCommandResult(t, results).Result)
+	assert.Equal(t, &ocpp201.UnlockConnectorRequestJson{EvseId: 3, ConnectorId: 2}, callMaker.lastRequest())
+}
@@ -0,0 +1,406 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/http"
+)
+
+func (o *OCPI) SetCredentials(ctx context.Context, token string, credentials Credentials) error {
+	reg, err := o.store.GetRegistrationDetails(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	versions, endpoints, err := o.discoverParty(ctx, credentials.Url, credentials.Token)
+	if err != nil {
+		return err
+	}
+	err = o.setParties(ctx, credentials, versions, endpoints)
+	if err != nil {
+		return err
+	}
+
+	if reg != nil && reg.Status == store.OcpiRegistrationStatusPending {
+		// register new party
+		err = o.RegisterNewParty(ctx, credentials.Url, credentials.Token)
+		if err != nil {
+			return err
+		}
+		// delete old token
+		err := o.store.DeleteRegistrationDetails(ctx, token)
+		if err != nil {
+			return err
+		}
+	}
+
+	// store new token
+	err = o.store.SetRegistrationDetails(ctx, credentials.Token, &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusRegistered,
+		Url:    credentials.Url,
+	})
+	if err != nil {
+		return err
+	}
+
+	o.syncNewPartyTokens(credentials)
+	return nil
+}
+
+// GetCredentials returns the credentials that the party holding the token uses
+// to access the CSMS.
+func (o *OCPI) GetCredentials(ctx context.Context, token string) (*Credentials, error) {
+	reg, err := o.store.GetRegistrationDetails(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		return nil, fmt.Errorf("unknown token")
+	}
+	credentials := o.credentials(token)
+	return &credentials, nil
+}
+
+// UpdateCredentials replaces the credentials of the party holding the token:
+// the party's endpoints are discovered again and a new token is issued to the
+// party in place of the tokens it has been issued before.
+func (o *OCPI) UpdateCredentials(ctx context.Context, token string, credentials Credentials) (*Credentials, error) {
+	reg, err := o.store.GetRegistrationDetails(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		return nil, fmt.Errorf("unknown token")
+	}
+
+	versions, endpoints, err := o.discoverParty(ctx, credentials.Url, credentials.Token)
+	if err != nil {
+		return nil, err
+	}
+	if reg.Url != "" {
+		err = o.deleteParties(ctx, reg.Url)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = o.setParties(ctx, credentials, versions, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, err := generateRandomString()
+	if err != nil {
+		return nil, err
+	}
+	err = o.replaceRegistration(ctx, token, reg.Url, newToken, credentials.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	o.syncNewPartyTokens(credentials)
+	newCredentials := o.credentials(newToken)
+	return &newCredentials, nil
+}
+
+// DeleteCredentials unregisters the party holding the token: the party's
+// details and every token issued to the party are removed.
+func (o *OCPI) DeleteCredentials(ctx context.Context, token string) error {
+	reg, err := o.store.GetRegistrationDetails(ctx, token)
+	if err != nil {
+		return err
+	}
+	if reg == nil {
+		return fmt.Errorf("unknown token")
+	}
+
+	if reg.Url != "" {
+		err = o.deleteParties(ctx, reg.Url)
+		if err != nil {
+			return err
+		}
+		err = o.store.DeleteRegistrationDetailsForUrl(ctx, reg.Url)
+		if err != nil {
+			return err
+		}
+	}
+	return o.store.DeleteRegistrationDetails(ctx, token)
+}
+
+// RotateToken issues a new token to the party: the new token is sent to the
+// party's credentials endpoint and the party responds with the new token that
+// the CSMS uses to access the party.
+func (o *OCPI) RotateToken(ctx context.Context, countryCode, partyId string) error {
+	party, err := o.findParty(ctx, countryCode, partyId)
+	if err != nil {
+		return err
+	}
+
+	credentialsUrl, err := o.getReceiverEndpointUrl(ctx, party, "credentials")
+	if err != nil {
+		return err
+	}
+
+	// the party may use the new token as soon as it receives it, e.g. to discover
+	// the CSMS's endpoints, so it is accepted before it is sent
+	newToken, err := generateRandomString()
+	if err != nil {
+		return err
+	}
+	err = o.store.SetRegistrationDetails(ctx, newToken, &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusRegistered,
+	})
+	if err != nil {
+		return err
+	}
+
+	credentials, err := o.putCredentials(ctx, credentialsUrl, party.Token, newToken)
+	if err != nil {
+		_ = o.store.DeleteRegistrationDetails(ctx, newToken)
+		return err
+	}
+
+	versions, endpoints, err := o.discoverParty(ctx, credentials.Url, credentials.Token)
+	if err != nil {
+		return err
+	}
+	err = o.deleteParties(ctx, party.Url)
+	if err != nil {
+		return err
+	}
+	err = o.setParties(ctx, *credentials, versions, endpoints)
+	if err != nil {
+		return err
+	}
+
+	err = o.store.DeleteRegistrationDetailsForUrl(ctx, party.Url)
+	if err != nil {
+		return err
+	}
+	return o.store.SetRegistrationDetails(ctx, newToken, &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusRegistered,
+		Url:    credentials.Url,
+	})
+}
+
+// DeregisterParty unregisters the party: the party is informed through its
+// credentials endpoint and the party's details and every token issued to the
+// party are removed.
+func (o *OCPI) DeregisterParty(ctx context.Context, countryCode, partyId string) error {
+	party, err := o.findParty(ctx, countryCode, partyId)
+	if err != nil {
+		return err
+	}
+
+	// the party is removed even if it cannot be informed: it will no longer be
+	// able to access the CSMS
+	credentialsUrl, err := o.getReceiverEndpointUrl(ctx, party, "credentials")
+	if err == nil {
+		err = o.deleteCredentials(ctx, credentialsUrl, party.Token)
+	}
+	if err != nil {
+		slog.Warn("unable to unregister from ocpi party", "country_code", countryCode, "party_id", partyId, "err", err)
+	}
+
+	err = o.deleteParties(ctx, party.Url)
+	if err != nil {
+		return err
+	}
+	return o.store.DeleteRegistrationDetailsForUrl(ctx, party.Url)
+}
+
+// credentials returns the credentials used by a party to access the CSMS with
+// the token.
+func (o *OCPI) credentials(token string) Credentials {
+	return Credentials{
+		Roles: []CredentialsRole{
+			{
+				CountryCode: o.countryCode,
+				PartyId:     o.partyId,
+				Role:        "CPO",
+			},
+		},
+		Token: token,
+		Url:   o.externalUrl + "/ocpi/versions",
+	}
+}
+
+// findParty returns one of the party's roles: every role of a party shares the
+// same URL and token.
+func (o *OCPI) findParty(ctx context.Context, countryCode, partyId string) (*store.OcpiParty, error) {
+	parties, err := o.store.ListPartyDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, party := range parties {
+		if party.CountryCode == countryCode && party.PartyId == partyId {
+			return party, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown party %s:%s", countryCode, partyId)
+}
+
+// setParties stores a party for each role of the credentials along with the
+// versions and endpoints discovered using the credentials.
+func (o *OCPI) setParties(ctx context.Context, credentials Credentials, versions []store.OcpiVersion, endpoints []store.OcpiEndpoint) error {
+	for _, role := range credentials.Roles {
+		err := o.store.SetPartyDetails(ctx, &store.OcpiParty{
+			Role:        string(role.Role),
+			CountryCode: role.CountryCode,
+			PartyId:     role.PartyId,
+			Url:         credentials.Url,
+			Token:       credentials.Token,
+			Versions:    versions,
+			Endpoints:   endpoints,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteParties removes every role of the party with the versions URL.
+func (o *OCPI) deleteParties(ctx context.Context, url string) error {
+	parties, err := o.store.ListPartyDetails(ctx)
+	if err != nil {
+		return err
+	}
+	for _, party := range parties {
+		if party.Url != url {
+			continue
+		}
+		err = o.store.DeletePartyDetails(ctx, party.Role, party.CountryCode, party.PartyId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceRegistration removes the token and the other tokens issued to the party
+// with the old versions URL and registers the new token for the party with the
+// new versions URL.
+func (o *OCPI) replaceRegistration(ctx context.Context, token, oldUrl, newToken, newUrl string) error {
+	err := o.store.DeleteRegistrationDetails(ctx, token)
+	if err != nil {
+		return err
+	}
+	if oldUrl != "" {
+		err = o.store.DeleteRegistrationDetailsForUrl(ctx, oldUrl)
+		if err != nil {
+			return err
+		}
+	}
+	return o.store.SetRegistrationDetails(ctx, newToken, &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusRegistered,
+		Url:    newUrl,
+	})
+}
+
+// discoverParty returns the versions supported by the party with the versions
+// URL and the module endpoints of the version used by the CSMS.
+func (o *OCPI) discoverParty(ctx context.Context, url, token string) ([]store.OcpiVersion, []store.OcpiEndpoint, error) {
+	versions, err := o.getVersions(ctx, url, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpointUrl, err := getEndpointUrl(versions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoints, err := o.getEndpoints(ctx, endpointUrl, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storeVersions := make([]store.OcpiVersion, len(versions))
+	for i, version := range versions {
+		storeVersions[i] = store.OcpiVersion{
+			Version: version.Version,
+			Url:     version.Url,
+		}
+	}
+	storeEndpoints := make([]store.OcpiEndpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		storeEndpoints[i] = store.OcpiEndpoint{
+			Identifier: endpoint.Identifier,
+			Role:       string(endpoint.Role),
+			Url:        endpoint.Url,
+		}
+	}
+	return storeVersions, storeEndpoints, nil
+}
+
+func (o *OCPI) putCredentials(ctx context.Context, url, token, newToken string) (*Credentials, error) {
+	b, err := json.Marshal(o.credentials(newToken))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", token))
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	b, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentialsResponse OcpiResponseCredentials
+	err = json.Unmarshal(b, &credentialsResponse)
+	if err != nil {
+		return nil, err
+	}
+	if credentialsResponse.StatusCode != StatusSuccess {
+		return nil, fmt.Errorf("status code: %d", credentialsResponse.StatusCode)
+	}
+	if credentialsResponse.Data == nil {
+		return nil, fmt.Errorf("no credentials in response")
+	}
+	return credentialsResponse.Data, nil
+}
+
+func (o *OCPI) deleteCredentials(ctx context.Context, url, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", token))
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/server"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+)
+
+type registeredParty struct {
+	ocpi   *ocpi.OCPI
+	store  store.Engine
+	server *httptest.Server
+}
+
+// setupRegisteredParties registers a sender (GB*TWK) with a receiver (GB*TWS).
+func setupRegisteredParties(t *testing.T) (sender, receiver registeredParty) {
+	tokenA := "abcdef123456"
+
+	sender.store = inmemory.NewStore(clock.RealClock{})
+	sender.ocpi = ocpi.NewOCPI(sender.store, http.DefaultClient, "GB", "TWK")
+	sender.server = httptest.NewServer(server.NewOcpiHandler(sender.store, clock.RealClock{}, sender.ocpi))
+	sender.ocpi.SetExternalUrl(sender.server.URL)
+	t.Cleanup(sender.server.Close)
+
+	receiver.store = inmemory.NewStore(clock.RealClock{})
+	err := receiver.store.SetRegistrationDetails(context.Background(), tokenA, &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusPending,
+	})
+	require.NoError(t, err)
+	receiver.ocpi = ocpi.NewOCPI(receiver.store, http.DefaultClient, "GB", "TWS")
+	receiver.server = httptest.NewServer(server.NewOcpiHandler(receiver.store, clock.RealClock{}, receiver.ocpi))
+	receiver.ocpi.SetExternalUrl(receiver.server.URL)
+	t.Cleanup(receiver.server.Close)
+
+	err = sender.ocpi.RegisterNewParty(context.Background(), receiver.server.URL+"/ocpi/versions", tokenA)
+	require.NoError(t, err)
+
+	return sender, receiver
+}
+
+func lookupParty(t *testing.T, engine store.Engine, countryCode, partyId string) *store.OcpiParty {
+	party, err := engine.GetPartyDetails(context.Background(), "CPO", countryCode, partyId)
+	require.NoError(t, err)
+	return party
+}
+
+func assertTokenRegistered(t *testing.T, engine store.Engine, token string, registered bool) {
+	reg, err := engine.GetRegistrationDetails(context.Background(), token)
+	require.NoError(t, err)
+	if registered {
+		require.NotNil(t, reg)
+		assert.Equal(t, store.OcpiRegistrationStatusRegistered, reg.Status)
+	} else {
+		assert.Nil(t, reg)
+	}
+}
+
+func TestRegistrationStoresPartyEndpoints(t *testing.T) {
+	sender, receiver := setupRegisteredParties(t)
+
+	party := lookupParty(t, sender.store, "GB", "TWS")
+	require.NotNil(t, party)
+	assert.Equal(t, []store.OcpiVersion{
+		{Version: "2.2", Url: receiver.server.URL + "/ocpi/2.2"},
+	}, party.Versions)
+	assert.Contains(t, party.Endpoints, store.OcpiEndpoint{
+		Identifier: "credentials",
+		Role:       "RECEIVER",
+		Url:        receiver.server.URL + "/ocpi/2.2/credentials",
+	})
+
+	party = lookupParty(t, receiver.store, "GB", "TWK")
+	require.NotNil(t, party)
+	assert.Contains(t, party.Endpoints, store.OcpiEndpoint{
+		Identifier: "locations",
+		Role:       "SENDER",
+		Url:        sender.server.URL + "/ocpi/sender/2.2/locations",
+	})
+}
+
+func TestGetCredentials(t *testing.T) {
+	sender, receiver := setupRegisteredParties(t)
+	party := lookupParty(t, sender.store, "GB", "TWS")
+
+	req, err := http.NewRequest(http.MethodGet, receiver.server.URL+"/ocpi/2.2/credentials", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Token "+party.Token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got ocpi.OcpiResponseCredentials
+	err = json.NewDecoder(resp.Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	assert.Equal(t, party.Token, got.Data.Token)
+	assert.Equal(t, receiver.server.URL+"/ocpi/versions", got.Data.Url)
+	require.Len(t, got.Data.Roles, 1)
+	assert.Equal(t, "GB", got.Data.Roles[0].CountryCode)
+	assert.Equal(t, "TWS", got.Data.Roles[0].PartyId)
+}
+
+func TestRotateToken(t *testing.T) {
+	sender, receiver := setupRegisteredParties(t)
+	oldSenderToken := lookupParty(t, receiver.store, "GB", "TWK").Token
+	oldReceiverToken := lookupParty(t, sender.store, "GB", "TWS").Token
+
+	err := sender.ocpi.RotateToken(context.Background(), "GB", "TWS")
+	require.NoError(t, err)
+
+	// the receiver uses the new token issued by the sender
+	newSenderToken := lookupParty(t, receiver.store, "GB", "TWK").Token
+	assert.NotEqual(t, oldSenderToken, newSenderToken)
+	assertTokenRegistered(t, sender.store, newSenderToken, true)
+	assertTokenRegistered(t, sender.store, oldSenderToken, false)
+	assertTokenRegistered(t, sender.store, oldReceiverToken, false)
+
+	// the sender uses the new token issued by the receiver
+	newReceiverToken := lookupParty(t, sender.store, "GB", "TWS").Token
+	assert.NotEqual(t, oldReceiverToken, newReceiverToken)
+	assertTokenRegistered(t, receiver.store, newReceiverToken, true)
+	assertTokenRegistered(t, receiver.store, oldReceiverToken, false)
+	assertTokenRegistered(t, receiver.store, oldSenderToken, false)
+
+	creds, err := receiver.ocpi.GetCredentials(context.Background(), newReceiverToken)
+	require.NoError(t, err)
+	assert.Equal(t, newReceiverToken, creds.Token)
+}
+
+func TestDeregisterParty(t *testing.T) {
+	sender, receiver := setupRegisteredParties(t)
+	senderToken := lookupParty(t, receiver.store, "GB", "TWK").Token
+	receiverToken := lookupParty(t, sender.store, "GB", "TWS").Token
+
+	err := sender.ocpi.DeregisterParty(context.Background(), "GB", "TWS")
+	require.NoError(t, err)
+
+	assert.Nil(t, lookupParty(t, sender.store, "GB", "TWS"))
+	assert.Nil(t, lookupParty(t, receiver.store, "GB", "TWK"))
+	for _, engine := range []store.Engine{sender.store, receiver.store} {
+		assertTokenRegistered(t, engine, senderToken, false)
+		assertTokenRegistered(t, engine, receiverToken, false)
+	}
+}
+
+func TestDeregisterUnknownParty(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+
+	err := ocpiApi.DeregisterParty(context.Background(), "GB", "XXX")
+	assert.ErrorContains(t, err, "unknown party")
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import "github.com/zynka-tech/zynka-csms/manager/store"
+
+// evseStatusRank orders the OCPI EVSE statuses so that the status of an EVSE
+// with several connectors is that of its most available connector.
+var evseStatusRank = map[EvseStatus]int{
+	EvseStatusUNKNOWN:     0,
+	EvseStatusOUTOFORDER:  1,
+	EvseStatusINOPERATIVE: 2,
+	EvseStatusAVAILABLE:   3,
+	EvseStatusRESERVED:    4,
+	EvseStatusCHARGING:    5,
+}
+
+// ToEvseStatus maps an OCPP 1.6 or 2.0.1 connector status to an OCPI EVSE status.
+func ToEvseStatus(connectorStatus string) EvseStatus {
+	switch connectorStatus {
+	case "Available":
+		return EvseStatusAVAILABLE
+	case "Occupied", "Preparing", "Charging", "SuspendedEV", "SuspendedEVSE", "Finishing":
+		return EvseStatusCHARGING
+	case "Reserved":
+		return EvseStatusRESERVED
+	case "Unavailable":
+		return EvseStatusINOPERATIVE
+	case "Faulted":
+		return EvseStatusOUTOFORDER
+	default:
+		return EvseStatusUNKNOWN
+	}
+}
+
+// EvseStatusFromConnectorStatuses determines the OCPI status of an EVSE from the
+// statuses reported for its connectors. A status for the whole charge station
+// (EVSE id 0) that makes the charge station unusable takes precedence over the
+// connector statuses.
+func EvseStatusFromConnectorStatuses(statuses []*store.ConnectorStatus) EvseStatus {
+	result := EvseStatusUNKNOWN
+	for _, status := range statuses {
+		evseStatus := ToEvseStatus(status.Status)
+		if status.EvseId == 0 {
+			if evseStatus == EvseStatusINOPERATIVE || evseStatus == EvseStatusOUTOFORDER {
+				return evseStatus
+			}
+			continue
+		}
+		if evseStatusRank[evseStatus] > evseStatusRank[result] {
+			result = evseStatus
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestToEvseStatus(t *testing.T) {
+	tests := map[string]ocpi.EvseStatus{
+		"Available":     ocpi.EvseStatusAVAILABLE,
+		"Preparing":     ocpi.EvseStatusCHARGING,
+		"Charging":      ocpi.EvseStatusCHARGING,
+		"SuspendedEV":   ocpi.EvseStatusCHARGING,
+		"SuspendedEVSE": ocpi.EvseStatusCHARGING,
+		"Finishing":     ocpi.EvseStatusCHARGING,
+		"Occupied":      ocpi.EvseStatusCHARGING,
+		"Reserved":      ocpi.EvseStatusRESERVED,
+		"Unavailable":   ocpi.EvseStatusINOPERATIVE,
+		"Faulted":       ocpi.EvseStatusOUTOFORDER,
+		"Unexpected":    ocpi.EvseStatusUNKNOWN,
+	}
+
+	for connectorStatus, want := range tests {
+		t.Run(connectorStatus, func(t *testing.T) {
+			assert.Equal(t, want, ocpi.ToEvseStatus(connectorStatus))
+		})
+	}
+}
+
+func TestEvseStatusFromConnectorStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []*store.ConnectorStatus
+		want     ocpi.EvseStatus
+	}{
+		{
+			name: "no statuses",
+			want: ocpi.EvseStatusUNKNOWN,
+		},
+		{
+			name: "most available connector",
+			statuses: []*store.ConnectorStatus{
+				{EvseId: 1, ConnectorId: 1, Status: "Faulted"},
+				{EvseId: 1, ConnectorId: 2, Status: "Available"},
+			},
+			want: ocpi.EvseStatusAVAILABLE,
+		},
+		{
+			name: "in use connector",
+			statuses: []*store.ConnectorStatus{
+				{EvseId: 1, ConnectorId: 1, Status: "Available"},
+				{EvseId: 1, ConnectorId: 2, Status: "Occupied"},
+			},
+			want: ocpi.EvseStatusCHARGING,
+		},
+		{
+			name: "available charge station",
+			statuses: []*store.ConnectorStatus{
+				{EvseId: 0, ConnectorId: 0, Status: "Available"},
+				{EvseId: 1, ConnectorId: 1, Status: "Reserved"},
+			},
+			want: ocpi.EvseStatusRESERVED,
+		},
+		{
+			name: "unavailable charge station",
+			statuses: []*store.ConnectorStatus{
+				{EvseId: 0, ConnectorId: 0, Status: "Unavailable"},
+				{EvseId: 1, ConnectorId: 1, Status: "Available"},
+			},
+			want: ocpi.EvseStatusINOPERATIVE,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ocpi.EvseStatusFromConnectorStatuses(tc.statuses))
+		})
+	}
+}
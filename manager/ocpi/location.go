@@ -0,0 +1,398 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// PushLocation updates the status of the location's EVSEs from the statuses
+// reported by their charge stations and sends the location to every eMSP that
+// has a locations receiver interface. The cached EVSEs of the locations are
+// cleared as the location may have changed.
+func (o *OCPI) PushLocation(ctx context.Context, location *store.Location) error {
+	o.clearEvseLocations()
+	location, err := o.refreshEvseStatuses(ctx, location)
+	if err != nil {
+		return err
+	}
+
+	return o.pushLocationToParties(ctx, http.MethodPut, location.Id, o.toOcpiLocation(location))
+}
+
+// PushEvseStatus updates the status of the EVSEs that are made up of the charge
+// station and sends the statuses that have changed to the eMSPs.
+func (o *OCPI) PushEvseStatus(ctx context.Context, chargeStationId string) error {
+	statuses, err := o.store.ListChargeStationConnectorStatuses(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	err = o.forEachLocation(ctx, func(location *store.Location) error {
+		if location.Evses == nil {
+			return nil
+		}
+		now := o.clock.Now().UTC().Format(time.RFC3339)
+		var changed []store.Evse
+		for i, evse := range *location.Evses {
+			mapping, err := o.evseMapping.MapEvse(ctx, location.Id, &evse)
+			if err != nil {
+				return err
+			}
+			if mapping == nil || mapping.ChargeStationId != chargeStationId {
+				continue
+			}
+			status := string(evseStatus(mapping.ChargeStationEvseId, statuses))
+			if status == evse.Status {
+				continue
+			}
+			evse.Status = status
+			evse.LastUpdated = now
+			(*location.Evses)[i] = evse
+			changed = append(changed, evse)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if err := o.store.SetLocation(ctx, location); err != nil {
+			return err
+		}
+		for _, evse := range changed {
+			err := o.pushLocationToParties(ctx, http.MethodPatch, fmt.Sprintf("%s/%s", location.Id, evse.Uid), map[string]any{
+				"status":       evse.Status,
+				"last_updated": evse.LastUpdated,
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o *OCPI) ListLocations(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Location, int, error) {
+	locations, total, err := o.store.ListLocations(ctx, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]Location, 0, len(locations))
+	for _, location := range locations {
+		result = append(result, o.toOcpiLocation(location))
+	}
+	return result, total, nil
+}
+
+// GetLocation returns the CPO's location with the id or nil if there is no
+// such location.
+func (o *OCPI) GetLocation(ctx context.Context, locationId string) (*Location, error) {
+	location, err := o.store.LookupLocation(ctx, locationId)
+	if err != nil || location == nil {
+		return nil, err
+	}
+	result := o.toOcpiLocation(location)
+	return &result, nil
+}
+
+// GetClientOwnedLocation returns a location that was received from another CPO
+// or nil if there is no such location.
+func (o *OCPI) GetClientOwnedLocation(ctx context.Context, countryCode, partyId, locationId string) (*Location, error) {
+	location, err := o.store.LookupClientOwnedLocation(ctx, countryCode, partyId, locationId)
+	if err != nil || location == nil {
+		return nil, err
+	}
+	result := o.toOcpiLocation(location)
+	return &result, nil
+}
+
+// SetClientOwnedLocation stores a location that was received from another CPO.
+func (o *OCPI) SetClientOwnedLocation(ctx context.Context, location Location) error {
+	return o.store.SetClientOwnedLocation(ctx, fromOcpiLocation(location))
+}
+
+// refreshEvseStatuses determines the status of the location's EVSEs from the
+// statuses reported by their charge stations and stores the location if any of
+// them has changed.
+func (o *OCPI) refreshEvseStatuses(ctx context.Context, location *store.Location) (*store.Location, error) {
+	if location.Evses == nil {
+		return location, nil
+	}
+
+	now := o.clock.Now().UTC().Format(time.RFC3339)
+	evses := make([]store.Evse, len(*location.Evses))
+	var changed bool
+	for i, evse := range *location.Evses {
+		mapping, err := o.evseMapping.MapEvse(ctx, location.Id, &evse)
+		if err != nil {
+			return nil, err
+		}
+		if mapping != nil {
+			statuses, err := o.store.ListChargeStationConnectorStatuses(ctx, mapping.ChargeStationId)
+			if err != nil {
+				return nil, err
+			}
+			if status := string(evseStatus(mapping.ChargeStationEvseId, statuses)); status != evse.Status {
+				evse.Status = status
+				evse.LastUpdated = now
+				changed = true
+			}
+		}
+		evses[i] = evse
+	}
+	if !changed {
+		return location, nil
+	}
+
+	updated := *location
+	updated.Evses = &evses
+	if err := o.store.SetLocation(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return o.store.LookupLocation(ctx, location.Id)
+}
+
+// forEachLocation calls fn for each of the CPO's locations.
+func (o *OCPI) forEachLocation(ctx context.Context, fn func(location *store.Location) error) error {
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		locations, total, err := o.store.ListLocations(ctx, nil, nil, offset, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, location := range locations {
+			if err := fn(location); err != nil {
+				return err
+			}
+		}
+		if offset+pageSize >= total {
+			return nil
+		}
+	}
+}
+
+func (o *OCPI) pushLocationToParties(ctx context.Context, method, path string, body any) error {
+	parties, err := o.store.ListPartyDetailsForRole(ctx, "EMSP")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, party := range parties {
+		locationsUrl, err := o.getReceiverUrl(ctx, party, "locations")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = o.sendToParty(ctx, method, fmt.Sprintf("%s/%s", locationsUrl, path), party, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pushing location to %s/%s: %w", party.CountryCode, party.PartyId, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// evseStatus determines the status of the EVSE with the id from the statuses
+// reported by its charge station.
+func evseStatus(evseId int, statuses []*store.ConnectorStatus) EvseStatus {
+	if evseId == 0 {
+		return EvseStatusFromConnectorStatuses(statuses)
+	}
+	var evseStatuses []*store.ConnectorStatus
+	for _, status := range statuses {
+		if status.EvseId == evseId || status.EvseId == 0 {
+			evseStatuses = append(evseStatuses, status)
+		}
+	}
+	return EvseStatusFromConnectorStatuses(evseStatuses)
+}
+
+func (o *OCPI) toOcpiLocation(location *store.Location) Location {
+	result := Location{
+		Address: location.Address,
+		City:    location.City,
+		Coordinates: GeoLocation{
+			Latitude:  location.Coordinates.Latitude,
+			Longitude: location.Coordinates.Longitude,
+		},
+		Country:     location.Country,
+		CountryCode: location.CountryCode,
+		Id:          location.Id,
+		LastUpdated: location.LastUpdated,
+		PartyId:     location.PartyId,
+		Publish:     true,
+	}
+	if result.CountryCode == "" {
+		result.CountryCode = o.countryCode
+	}
+	if result.PartyId == "" {
+		result.PartyId = o.partyId
+	}
+	if location.Name != "" {
+		result.Name = &location.Name
+	}
+	if location.ParkingType != "" {
+		parkingType := LocationParkingType(location.ParkingType)
+		result.ParkingType = &parkingType
+	}
+	if location.PostalCode != "" {
+		result.PostalCode = &location.PostalCode
+	}
+	if location.Evses != nil {
+		evses := make([]Evse, 0, len(*location.Evses))
+		for _, evse := range *location.Evses {
+			evses = append(evses, toOcpiEvse(evse, location.LastUpdated))
+		}
+		result.Evses = &evses
+	}
+	return result
+}
+
+// toOcpiEvse converts the EVSE: an EVSE or connector that has never been
+// updated on its own was last updated with its location.
+func toOcpiEvse(evse store.Evse, locationLastUpdated string) Evse {
+	result := Evse{
+		Connectors:  make([]Connector, 0, len(evse.Connectors)),
+		EvseId:      evse.EvseId,
+		LastUpdated: evse.LastUpdated,
+		Status:      EvseStatus(evse.Status),
+		Uid:         evse.Uid,
+	}
+	if result.LastUpdated == "" {
+		result.LastUpdated = locationLastUpdated
+	}
+	if result.Status == "" {
+		result.Status = EvseStatusUNKNOWN
+	}
+	for _, connector := range evse.Connectors {
+		c := Connector{
+			Format:      ConnectorFormat(connector.Format),
+			Id:          connector.Id,
+			LastUpdated: connector.LastUpdated,
+			MaxAmperage: connector.MaxAmperage,
+			MaxVoltage:  connector.MaxVoltage,
+			PowerType:   ConnectorPowerType(connector.PowerType),
+			Standard:    ConnectorStandard(connector.Standard),
+		}
+		if c.LastUpdated == "" {
+			c.LastUpdated = result.LastUpdated
+		}
+		result.Connectors = append(result.Connectors, c)
+	}
+	return result
+}
+
+func fromOcpiLocation(location Location) *store.Location {
+	result := &store.Location{
+		Address: location.Address,
+		City:    location.City,
+		Coordinates: store.GeoLocation{
+			Latitude:  location.Coordinates.Latitude,
+			Longitude: location.Coordinates.Longitude,
+		},
+		Country:     location.Country,
+		CountryCode: location.CountryCode,
+		Id:          location.Id,
+		LastUpdated: location.LastUpdated,
+		PartyId:     location.PartyId,
+	}
+	if location.Name != nil {
+		result.Name = *location.Name
+	}
+	if location.ParkingType != nil {
+		result.ParkingType = string(*location.ParkingType)
+	}
+	if location.PostalCode != nil {
+		result.PostalCode = *location.PostalCode
+	}
+	if location.Evses != nil {
+		evses := make([]store.Evse, 0, len(*location.Evses))
+		for _, evse := range *location.Evses {
+			evses = append(evses, fromOcpiEvse(evse))
+		}
+		result.Evses = &evses
+	}
+	return result
+}
+
+func fromOcpiEvse(evse Evse) store.Evse {
+	result := store.Evse{
+		Connectors:  make([]store.Connector, 0, len(evse.Connectors)),
+		EvseId:      evse.EvseId,
+		LastUpdated: evse.LastUpdated,
+		Status:      string(evse.Status),
+		Uid:         evse.Uid,
+	}
+	for _, connector := range evse.Connectors {
+		result.Connectors = append(result.Connectors, store.Connector{
+			Format:      string(connector.Format),
+			Id:          connector.Id,
+			LastUpdated: connector.LastUpdated,
+			MaxAmperage: connector.MaxAmperage,
+			MaxVoltage:  connector.MaxVoltage,
+			PowerType:   string(connector.PowerType),
+			Standard:    string(connector.Standard),
+		})
+	}
+	return result
+}
+
+// findEvse returns the EVSE of the location with the uid or nil if there is no
+// such EVSE.
+func findEvse(location *Location, evseUid string) *Evse {
+	if location.Evses == nil {
+		return nil
+	}
+	for i := range *location.Evses {
+		if (*location.Evses)[i].Uid == evseUid {
+			return &(*location.Evses)[i]
+		}
+	}
+	return nil
+}
+
+// findConnector returns the connector of the EVSE with the id or nil if there
+// is no such connector.
+func findConnector(evse *Evse, connectorId string) *Connector {
+	for i := range evse.Connectors {
+		if evse.Connectors[i].Id == connectorId {
+			return &evse.Connectors[i]
+		}
+	}
+	return nil
+}
+
+// applyPatch sets the fields of the object that are present in the patch. The
+// patch uses the JSON names of the fields.
+func applyPatch(object any, patch map[string]any) error {
+	b, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	for k, v := range patch {
+		fields[k] = v
+	}
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, object)
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func chargeStationLocation() *store.Location {
+	return &store.Location{
+		Id:          "loc001",
+		Name:        "Gent Zuid",
+		Address:     "F.Rooseveltlaan 3A",
+		City:        "Gent",
+		PostalCode:  "9000",
+		Country:     "BEL",
+		Coordinates: store.GeoLocation{Latitude: "51.047599", Longitude: "3.729944"},
+		Evses: &[]store.Evse{
+			{
+				Uid:                 "GBTWKEcs001-1",
+				ChargeStationId:     "cs001",
+				ChargeStationEvseId: 1,
+				Connectors: []store.Connector{
+					{Id: "1", Standard: "IEC_62196_T2", Format: "SOCKET", PowerType: "AC_3_PHASE"},
+				},
+			},
+			{
+				Uid:                 "GBTWKEcs001-2",
+				ChargeStationId:     "cs001",
+				ChargeStationEvseId: 2,
+				Connectors: []store.Connector{
+					{Id: "1", Standard: "IEC_62196_T2", Format: "SOCKET", PowerType: "AC_3_PHASE"},
+				},
+			},
+		},
+	}
+}
+
+func TestPushLocationWithEvseStatuses(t *testing.T) {
+	var got ocpi.Location
+	receiverServer, closeServer := newReceiver("locations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/locations/GB/TWK/loc001", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, receiverServer.URL)
+	ctx := context.Background()
+
+	err := engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{EvseId: 1, ConnectorId: 1, Status: "Occupied"})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{EvseId: 2, ConnectorId: 1, Status: "Available"})
+	require.NoError(t, err)
+
+	err = ocpiApi.PushLocation(ctx, chargeStationLocation())
+	require.NoError(t, err)
+
+	assert.Equal(t, "loc001", got.Id)
+	assert.Equal(t, "GB", got.CountryCode)
+	assert.Equal(t, "TWK", got.PartyId)
+	require.NotNil(t, got.Evses)
+	require.Len(t, *got.Evses, 2)
+	assert.Equal(t, ocpi.EvseStatusCHARGING, (*got.Evses)[0].Status)
+	assert.Equal(t, ocpi.EvseStatusAVAILABLE, (*got.Evses)[1].Status)
+
+	stored, err := engine.LookupLocation(ctx, "loc001")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "CHARGING", (*stored.Evses)[0].Status)
+}
+
+func TestPushEvseStatus(t *testing.T) {
+	var got []map[string]any
+	receiverServer, closeServer := newReceiver("locations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/locations/GB/TWK/loc001/GBTWKEcs001-2", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var patch map[string]any
+		require.NoError(t, json.Unmarshal(b, &patch))
+		got = append(got, patch)
+		w.WriteHeader(http.StatusOK)
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, receiverServer.URL)
+	ctx := context.Background()
+
+	location := chargeStationLocation()
+	(*location.Evses)[0].Status = "AVAILABLE"
+	(*location.Evses)[1].Status = "AVAILABLE"
+	err := engine.SetLocation(ctx, location)
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{EvseId: 1, ConnectorId: 1, Status: "Available"})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{EvseId: 2, ConnectorId: 1, Status: "Faulted"})
+	require.NoError(t, err)
+
+	err = ocpiApi.PushEvseStatus(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "OUTOFORDER", got[0]["status"])
+	assert.NotEmpty(t, got[0]["last_updated"])
+
+	err = ocpiApi.PushEvseStatus(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Len(t, got, 1, "an unchanged status must not be sent again")
+
+	err = ocpiApi.PushEvseStatus(ctx, "cs002")
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
@@ -1040,8 +1040,8 @@ type OcpiResponseVersionDetail struct {
 
 // Price defines model for Price.
 type Price struct {
-	ExclVat float32 `json:"excl_vat"`
-	InclVat float32 `json:"incl_vat"`
+	ExclVat float32  `json:"excl_vat"`
+	InclVat *float32 `json:"incl_vat,omitempty"`
 }
 
 // PriceComponent defines model for PriceComponent.
@@ -12424,119 +12424,119 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9b3OjOBL3V6H8PC/urpzxzOze1l3eMZgk3DjGD8bJTO1OUQootm4weIVwJjeV7/6U",
-	"hMACxB87yUyc1ZsEDBKtlvqn7lar9X3gx+tNHMGIJIPT74PEX8E1YJe6T9AWGiuAlyhaznB8i0JIH2xw",
-	"vIGYIMhe8/kL3mb3xv/F8HZwOvg/o13lI17zqFrhw3CQEICJFwACPYLWrAZyv4GD00FCMIqWg4eH4QDD",
-	"P1OEYTA4/b3+zXodX4Z5HfHNf6FP6Hf0IEAExREIz2E8iX1Ab+otCgFBJA1kdAwHYRwtm59GYN3JgDFK",
-	"NiG4d+E3UmtY8WnxQ9KmpGQVY/Q/1gQruo3rzQBhGN/Rar8PYJSuafX6ZGJfm+PBcPBhYhsf2ZX5aWY5",
-	"7Gpqe4Zjji2XXbte/vbu+7uGAvH7Hoa3EMPIlzMFcfJ6M4U2ftc5bcXyTnRyAhJamsRfYWdRl71U7YCc",
-	"aXklMt5/SBMUwSQZQwJQmEgGULzsbLC1BksoDpka2+7gTYJID2lgNcgINcaOZFikZOWtIVnF5aGxcC88",
-	"x/x/C3NO+9+wLy/1KR0V1xeWa06sufvoceAH2OvbsUaACwHlRXv1qhFg3rFDASUgRnHAWo8IXCe9IYqV",
-	"YyMqawvAGNyzquM0Ivje8+MGIPAxDBARHt3EcQhBtHu2Y5aHAnkdKabP76UPYRS0IuZw0FArirYx8mH3",
-	"50OQEC/d0I/IX1hDAnFT6Q3A5L7pIYZrgL9KHyUwSehQaiiZoGUEWctBVy/O2atj+mavOWY4IACj29v+",
-	"48Rl78vGB4kJCD0/TkhXHTOMfLgrAiOIl6zHb2O8BmRwOrgNY0AGxTeidH0DcbXEId+6Rd9gcEjBDcBf",
-	"qVw9omjeB31biWEC8TZDmQM+u+/n6Pv7fKc6iwgYW8E9EcskEFWBFgEEqiLPBLwipYLY1Ud8aVRWxluJ",
-	"T9KpBEQ+DJ1dL9QnFrGLGuU+2cRRAr0Uh93zWqXCSnEplQEeozWMEimB2eu7Kc9YOI45pbOdOTWd88/F",
-	"hWd+mtnO7oFnXfL7S/2Ttyt2aU3FO/2TN7OvTYc/ya9nuvPRmp57rnVp0lv+s2POTedKdy17mj+au7pr",
-	"evaZZ1zozjn9hT2QzbvbOEz7DekKU9nTonwDE5t1YxAEGCaJfL5CRD5X+XEUQZ/E2MuJ3XXCnOqhTOPQ",
-	"P0zkbd0Vb5ooixc28R3EXrWjdcN7580u9DllqW54vxQ3Y6PjiwkBUQBwSVUyLvSxeWnT4valOXctw9PF",
-	"mw/ijSHejMUbU7w5E2/OxZsL8cYSb/4j3nwUbyaD4cAyDe+3t7+8/bf33ktQtAyh9+63yu9khWHjz7+8",
-	"l/7826/5z+/f/fs3z31XufUM+/KDXf7xfeVW9s4veuXeYLIzde1zR59deB9s17UvvcWs/LNrz7yxfT2l",
-	"wmLOJ7rnFFfzhr6NcYAiQGDn/C5aiTulT66ObZNGRYo9S5vUsaDVkKzrVXGSgXjQwzLIpZXLpkQSK9LV",
-	"IEtSiSizcseeHTOEpvPZSqS+AXvcXM2vuBniiGDgkyYm12R+7F3YhreYM7DVZ7P80nYvMvw9s+Smrbyn",
-	"qh4IgZxhDqq0pLRVZXui1rYgn7P2MFHEmU6igPZXd70+zRUo7Odq2Xl5dpZ5vd2QqispbqExQAnTkaAn",
-	"uDPqJhVXgiOYvdCt5nHHUW2qMC5MnSLMmc7M4HPHNKdssj5fTHRHMl4qjCrV286Ynv40rhrub7xmxVts",
-	"2PxDmPZkGqHSvHxNpUYqH0GKC+WgYDSKCJsv+NsoInCZcXqNIq/0qX4ddLhPcNecHvxvEskQrTN+9KV0",
-	"10udPKn6/NinKvX0IN3h2rBUE09DUtaADHPmci+f680XM6rQsnvH/I9pZJeubXv2mctG/GL6cWpfT725",
-	"OZ9b9lQ6EmjHxCk5pNGcxF0d0gbH6zWIguaGrmGSgCXc07lYZ08LTwTOvSyW8CY8D0OEVhv61DAn5tgT",
-	"DBZqGV3NTc82jMXMytzI9N6a2jPT0V3rymQYak06h5x1adoLV+Cu+JlOuOWUyznFNZY6lw6yQg72koFv",
-	"HlhvIOb90gcywTcPhtAnGPmZGrZHwW0ckv6feiKDqd1MMi50+z/WC7eXzj+4nm7wi7HxWiyoqXmpe//0",
-	"3r/Nr3/zfhGu/1lcv3srPHj3Vnzyq/jk1+zJIy2zQt7lKJprpmWFuEHpF9yuEK8TD0SB58dRttaXdGsO",
-	"hSkkc6iVxLcsYSXpEWRAikYYBjAiCMjWi3Acwj1U/11VTpwtoNbdz9yGqhs3vRxvjJ68mqxQR6MYJbWG",
-	"3fCVMi/YLZW1Na26stZnpaV9oYGTVWDSjMqMeTmnY/ViQZFnyvT9Kfsltw3nxnzWPfnUWldz3greWEaJ",
-	"jIniXCxZio6WKUfz+ujnRdqpLKrgBWQ0mFGwiVEkIQCxDr5F2RTUyd+5OR1z76ZhWlemI7ew+wxC4cv8",
-	"O80D0WTmn8Ft8v2dB0m62YQIYq/B7VIhrfx6M0GX6FudFm6qbnAcpD7xGh09/L0kTrG/BzpkX56zUjJo",
-	"gNEW4Tjy0HrDWdWzWlZsDSMCQisrK6kdJd4SQxgJ61h1Y31PblfrbOY3b3WN5RuIfUr4sq/tWdRTGAgL",
-	"Y2LqdGCfm1PT0SfemT2fWxO2Uq4z9UGfC09zv8HcnrBS11a2lq67Zh8nwo7eghh5q+udUvfYrykm9Wu4",
-	"DwhcxpmLs9J071qfuybTkJ0P9tQbW/Yna2x2N4Z/X6hc2pStzL7zwQbcoBAVaJQPVUHHdM6t6bk3c+wz",
-	"a2J6hj5jGvxQfGSemY45Ncx56bE18wzdGeeWCevKqasb7sScz2uPWGBM9utM/8wrGZsf6j/OzLHnms6l",
-	"NWUDwzEvbdf05q7uuN6cKkU7Iritw2/OLGpk6RmAuvZHc+qdO/ZCLLCYTmzjY/GDVHeqhSpwO2gPDaMw",
-	"naT1Heo/DxCGPtnLzVkxVGtgtvO6R2kYghs6GxGcQglfbsM4xl4ItzCUO+LXYLkHzhbxO1WaOg3CfAkc",
-	"Q/qbhCH54DavPHs6+UyH1GRxfs7s5bE1pz3PDPPF3LUvTYfizqXt2o7x2ZiY816DYrO6T5DP1tXbInYS",
-	"Akhaokm/0q0JH4y7GLJc1qj9UXIB2AvXPrOdbEjPJvp0yh0Al/YVv6ISILpXmuxLkiYeZX6QZipHr16a",
-	"s3LzvJiEE9zbH8DEx2iT+TUHiwj9mcLwXiuUkEQjK6iZV3NTu0NkhSJ2b8zsRNuEgFBo1f4GouCPKElv",
-	"KIQBEuPiUfL3N4MunEzzRXvK8ZLYVsaUFD6/+XCThTY2eTQzx6J3A5dIbhrwF2DUYz2gVFmpqIy6czpz",
-	"I7/uv5T6sHrGkcrDU7u8WYLrqXm89XcyPU8c6aFBoRdxiiXmJdwNDs8P4wRFy259rzagKNwKFcUbGB1c",
-	"EYbLNATYW+UEt9XgZC9njaMSfAcjcn9Lb+G2ZOYWOmY15qFSQsa7DM8lGkhdI8qiMxwWHuI6+tSgSDex",
-	"jdw1OjXda9v5SOGPQaEtLjva19MGq2gF0XIl9xw3TG6CH4+s0vVNBFBYKp9i1Fy6vnbaZKhV6qtHqqKA",
-	"rA4ivIqBOBQVRf6+rLsOjFDJ14juVjBistAwR7eEsjxH9ECnk+MZVKfMvFxnJmq3RUltWa5x7WGMUq1e",
-	"8vFb4Ldp9Re2a2aq89zVF44+zTzzZyaLsprQR/PFzHQudSfz2s+5lu6YhmNmIVW6Y+rMu+MuHFZuMTcX",
-	"l1RrsT6a3vxCdzJ95cNizrRyqnLrnyyqpzNTzXX0y/zBpek6Nn3CBdx1dGsq3OuWwwnIY70mdkayM7Pt",
-	"icd/HQwHZwtzIhS8ts6sXvpaU/DIj9JZc0u9E0r4tMDWbDvpKSA9V5gO8A/GdxE8pFwRiFpd+pjY03OP",
-	"6dPX+mehS891R2cxeeU+pqawY12Z2cs2HRaOaWYLWmPToQbcdJwX/tIDjFv9mZXon+7K0psQJSu5H4Y/",
-	"zCMrPBL3HkmzrCiL1XFpxZJBhWFIx1MR+tp/nMr36cijXEg/Rgha+QGDhYUD/y+O+oQhNHqAswAo3iFD",
-	"WWxWHj9VjqvqVP4lm2BkHtAiymvPRZU8hmvPYnm394ovEl+WNdH2N6g5DCDfDVArxs3GXFx6rIfyEsI6",
-	"unSJPyFgvenhKBa+LxbsamKP3V19dkDUqzlinkg3M/XhAi143O2eoIQ0t72fSy9jggxAj5Ip9fDCbniQ",
-	"uAPG5kx3qIbItr7xfZA86H9qsqCX4uep7Xozez63uJuXe5zdzzPTK0e4tHiwjpjTHUFnvUSxobJj5k9X",
-	"jFovvlQqOWp+NIZd9eOEsPRwtDxoC/boxQWhgiPmg3xRrw8DcsfBsbaczthXEMv3oe01cee1vKLJu9l3",
-	"12doiPbgsXPgCRS7LvP4GNkzz/aAHzY+8sLH3/4nGB0CM17L4OCb7g8aG7sN+8fd+icYGc25C46WNfKN",
-	"g73GRZ4y5Kjb/hSjImfEaxkUC76tbf8xwUoeccu54ph5sQ9jQbmKI+NFlplDFgwRelvQMyASRf3frtBa",
-	"fEiopZFOI2e+LByGt6PPTkS48RL0v76dU137KtJgnE10t565ojkZxUEMyhomUt2y3l9bcKrxaYnjdOOh",
-	"QJOvlCZJ2hDD/tw7x4eDLUpSEHqcD9JRXmtwKerliYO5hoM7CL8G4P6QbYrNoV+7ar9IG5RAvIXT+E6e",
-	"k6xvFrHWbArw2wbhe7ZtuCHqyu9KUPPIHDaPyj4nkl8mdtiRDactX12jOfVzU8G9rHxunSlmuhK+PS5Z",
-	"2yE5Qr7erfrNC53hHV1icXimtz55KCRRxgaPHzbsy9nEzNZqrOmVPrHGLMJ+Os7iado3Cx+Qh60zkVeR",
-	"uauaNqUxa1clBQrttdouw7Kotybx4tySCzr5Eela98viJcnV2pnHS0jdJ9k/5ccBrW8HXBJ5K73jbXeu",
-	"2D57oqmu4Xtf4X1bCsItCNM9Yq+yJl3RQtIw9D6srDa9SkvzBjnx6zWORoCkuGFzZQhQ5FXiKxqzMXal",
-	"KWWfKVVarkJKORWA1hms95zThfGtGNxDc3g2taCmCPSc98ubH55ciXwhu0Pa9dM2uCTxpnFkdfZna6LS",
-	"+g4CscuEojK6mjycj9RBQrjOs3zv4SE0s2LyEOJOreawIOPHpNoozOVe2UHXKNqzxKO1HZ7bAITEyzdw",
-	"P0U8t1BtIwLJDd2Z/vkyy2BZbKLkSbDye54MK7/dOylWi3KUD8qObKbNYpKPT7nfxCtnt+8X21p2yEgj",
-	"W8s797qlyBFLSB0hIqXNrXUqX6769O69+NajVrg0rv7Sno5ZmLK7MOfZ1bU5nubX7sXC4ZdnjpVdzHV3",
-	"4fDLBSvdJ1w9R4ZGY6hROqgIZ+Ojp4eOFtg3Ixn45vW2nRim1FLwNL+Ooj3pp6rQ/hnV+tNPEa4//bic",
-	"4TcfOeXcT2Lq2uz4gHlTzBuHwxa0bM7uVpeBw1JDdu+ugbcgDYn3ZEkBi5nPF1JSdE9/RQKLh2HhztzX",
-	"m9maMaTP3uTm2e0HOEpBiBoyPHb5UIeDuxUiMORrYLvtHNf65zklrzj6gl959tnZxJqabMPeldlrGhNz",
-	"flYmNd4lbQm5hSyheVNFqmWony9flR8MB99O4Dew3oT5mga7Pnk3OP3+8MCKhbH/tSUU76faQnW2ip4U",
-	"wV/SYvfImNUYcNWkEAlugY692Uxtz19v+XbTahvkCXb2yePCU/JIptfedO8+20J922gSCf+9nA0o/2ae",
-	"BGiX+4exuyDpi0Dvjs4HZkH5KUbknlqofLdYYSQzNjAIgAAzueJErwjZDFjx/FiZ8tZ9dwU1ewMjjTmu",
-	"oDaj1GtWRCC+BT7U/mYbM+vvGozATQgTDWiJD9jWoaEGUhKvqdhq5pWGY7BG0VJLIEk32g0kd7Baqc03",
-	"FCUaiAINXsYsVcm9Nod4i3yozXC8RQHEyRvNIlqSbjYxJolW8lkMtSy9rca4rNE20SkaxZEGv/krEC2h",
-	"9jcU+WEaUGpCtIVaZtJqGcawj/9BgSlKgJ8V3NKR9Pei6iw9lYahH+OgqHaoYbiOCSwT4GeRyFmTyAru",
-	"qIhvtWQNMDnJHXoa3+D1RyRSnXOK4h6CyZs/oj8id4USLdlAH92iTKK1IPZTqrdrKNFijJYoAmF4r92A",
-	"BAZaHGm0m5PT0egGkZvU/wrJmxgvR8kKYAiiICN5FPvRSRQHcJRgf7QGCYF4lI/kUbyBEdigE/rdN/9N",
-	"4kjTftc3wF9BbYJ8GCVwqHGp1d6/efvljcaGjjGzKqQiksDwlhIaZuUCDSSagSEgtDeMeL2Oo0TTCcHo",
-	"JqVFTqbxGGK0ZS8k2q9v3mYDMALZrraidT6vxM/qYG3kH0lGN/cnUTD69c3bUQiXIOTzDH+c+e2YhDyG",
-	"kkJaD6aILeATBgA9xO79m/cDEQ/oPd81CjZocDr45c3bN+/YtElWDBFGsb9BI/re6ffBErLZMNvJh+LI",
-	"Cgang3NYhNqy6Raw1QoOWPQjKwgCBiGcYaVtUQMRMbPNgxkEy9D1y24WYsS9f/s2V0C5wv+P0T8EDK3j",
-	"6TZ3we42pTwLwFZiNd7WIzF21QsBGAJAi3xom6iaY05YJWV0tj9mmj1Y0vbmdCcnTLWKwxDiwRf6QtHr",
-	"dECKsfQBDGFmTpQHwZj9LsbNv5ixsD8be3BOYEuZecNGIXklzCntjXgMnzZ8VbDMqFmc/HhO/ZnChHyI",
-	"g/sKk8BmE/JpaESnsP4cq3GpTNHDMfRPKuueVPXOC+idEkRj6EO0hXiUa4bcj5KMvvOVDit46AZvh1dT",
-	"XXF+7j4eNtT46cTJuv6EBR08RYVGjJnSTDWzJ6qU6lUntzhen3CHxAlXFJ+yaubEOOFW+JNUS+JnopfE",
-	"j6OWKp67Kovxe1Bdf6aQ5XPglVVWAV/GbNq0I7cbG3jJk1za++ohSs6VnL9qOS+Wc9rq6hF2q1CkRQtU",
-	"KKJQ5HWgyDPZGJJg0B9sajwXKJSMjwRGgcz0yJK2jr6nKLM9mq391uy0Ck4UnPwsOEnRiwCSVvn4sZCS",
-	"7cn8IfhRcV00qSFzVlwpIQo1lBLSP0n7MYNG4e1kq1MB353ZvJYQIhgR+y6CgRHgnsgAFDIoZHgEMjzX",
-	"ssXYeaGyG+De4jr67gfYGj+0xRMooVVC+0KmczZYX4yfLweAp5FGHms2yo4YLp382z6ngsiHoSOEqisJ",
-	"VRJ6vNNqbTj/YEddNftst4Rzyd1LyvlmTm9qX7eLt5ApRMm1kuujlWthHL9KgeZHWprzeeeMXdrFr4Ra",
-	"CfXRCnVpJL9SsbZnfaV6l0BBCbUS6iMW6t1AfpUynZ8bbU+npuHaTrtcV7ePKtlWsn20sl0dzK9Jvouj",
-	"9UbfeV8acQAfRt9ZB1jjh9H3/JX+Hu/iRAEl9krsf5rbezecn6A2Lg5PUNNOnF6KT353AEgntBRwUQuy",
-	"BcRfSXQB+rNCBoUMChmeFxkO03pAcUzurJRspJacpPLDS1zBb4ampl2gCpYULClYeoGw1O+0siMCoaew",
-	"vUbf4TaBi/5WGDsBUQGaAjQFaE8CaPLKuFC+FFsuO/b02e04hS0KWxS2vFhsUdbgY6xBBW4K3BS4vTJw",
-	"6z4r/i9rT9LX+drmHrta1Nq+QkqFlC8VKZvYVcj5i9kQVA6reFarVWGWwiyFWX8lzFKG8GMMYYWXCi8V",
-	"Xiq8fNTW1J8UNvscBjZPjNNiX+epc3qb0WrziwJYBbDteahejK1a2uDTCis5UhxoqCpUUKigUOHJUUFZ",
-	"g4241G0MKkxSmKQw6YVgUnva7p+xDflRACQ3t7ID6VusrewFbmx1nOu2A7LsHHSFYwrHFI5VasoF6qUY",
-	"XD1BhQNF72MMFRYoLFBYcGRYwEX1YDToYeIoNFBooNDgidHg6Q0cEQlemn0jBZ8G8yb+CtvWktjzPfb7",
-	"ufR9BV4KvBR4VcGLS9JTHM3IXhPLBfAWpCEZnA6cM/YJGKVrCgX62LuwDW8xZyfu67NZfmm7F+w/e//L",
-	"8IcqUQwjesAYw6YDV68UECkgUkD0WoBILZo1gGEPe1IBoQJCBYR/aSBsNWR32tgxYF5hxvITL8Xj6xrt",
-	"0wAnZzhejwEBFBNVKLmCwx8Jh9WD9QGBHuXB4NDCJD6kaHx7m0AyeJLj+0O0RvtW9bwnek1Q8sgz9iqI",
-	"MtqAJdydvN0CLjOwhApfFL4c8XnbRyKW+UEA3/nVQ5+T8fXkPvKL1OVKNJVo/jxLiI3a1ppyO0FymuRw",
-	"IB4+NxyUT66i98KRN8NB7bQMiVHxoo/s35068HLP6G8/5kDArmKTV5sykScWpaipNAoFW8piea0Wiyjp",
-	"T7BFVAY0PQ2YnBJlxSjMUVbMTxLVvgcc5bTYbKlKiasS15chrq/xQKH+EtsvLb65TaCSWyW3r0Bu/wJp",
-	"6A+R/j2SmObvKUBQgKAA4S+aNFSGMfnm5Db04LurVVCDgg3lIny1LkIu5T3dDh1JDSQI09M/mION8g8q",
-	"wFH+wZ8hp0JGx5G/AniJoqW3wfAWYhj5WXsaI8H5+zPhdSW3Sm5/lty+qCxFMuH4sWEGEgqKSKWnxBK+",
-	"Jbhtns92NCubQkGFsilerU2RCXlPVaU9j0AdXXoaFBxolD2hwEbZEz9ORtlmqla5ZG8okVQiqeb/Vzv/",
-	"UxnvCy09t1/yHEI9J3/2spr7FdCouf/Hyucul9coFyPYvnHKgSB00TojSq+InhJXJa4/R1xV3gaKGnl8",
-	"o1NyXT6jq7IEAFZ0Gz8WorYQVyMdypW5K5RoMAo2MYqIFqKEJBoIQ42soAa2AIXgJoQaHWFaXpcGooA9",
-	"96lUU8IDFC21hTNJNBJrdyuIYf6ylmygj26RrwWQABQmWpL6Kw0krIIk3WxiTGBQUJBoPoi0G6jdxmkU",
-	"vBkM69rNVd6kfvioH4yPzxk4i5K8HT16OGd8pY8fhoME+ilG5J41nw2EwenvXyjlCcTbnC/lys9hRDkK",
-	"Ay17R0txOBgO6N/TwYqQzemIBeGFqzghp/96+6+3g4cvD/8/AAD//+fsnaD+OwEA",
+	"H4sIAAAAAAAC/+x9b3OjOBL3V6H8PC/urpxxZnZv6y7vGEwSbhzjB+NkpnanKAVkWzcYvEJkJjeV7/6U",
+	"hMACxB87yUyc1ZsEjCRaLfVP3a1W833gx5ttHMGIJIOz74PEX8MNYJe6T9AdNNYAr1C0muF4iUJIH2xx",
+	"vIWYIMiK+byAt92V+L8YLgdng/8z2jU+4i2Pqg0+DAcJAZh4ASDQI2jDWiD3Wzg4GyQEo2g1eHgYDjD8",
+	"M0UYBoOz3+vvrLfxeZi3Ed/+F/qEvkcPAkRQHIHwAsaT2Af0pt6jEBBE0kBGx3AQxtGq+WkENp0MGKNk",
+	"G4J7F34jtY4VrxZfJO1KStYxRv9jXbCiZVzvBgjD+Ctt9vsARumGNq9PJvaNOR4MB+8ntvGBXZkfZ5bD",
+	"rqa2Zzjm2HLZtevlpT8P6x0F4vs9DJcQw8iXMwVx8nozhXZ+Nzht1fJBdHICElqbxF9gZ1WXFaoOQM60",
+	"vBEZ79+nCYpgkowhAShMJBMoXnV22NqAFRSnTI1tX+FtgkgPaWAtyAg1xo5kWqRk7W0gWcflqbFwLz3H",
+	"/H8Lc07H37CvrvQpnRU3l5ZrTqy5++h54AfY6zuwRoALAeVVe42qEWA+sEMBJSBGccB6jwjcJL0hitVj",
+	"MyrrC8AY3LOm4zQi+N7z4wYg8DEMEBEe3cZxCEG0e7ZjlocCeRspps/vpQ9hFLQi5nDQ0CqK7mLkw+7X",
+	"hyAhXrqlL5EX2EACcVPtLcDkvukhhhuAv0gfJTBJ6FRqqJmgVQRZz0HXKM5Z0TEt2WuNGQ4IwGi57D9P",
+	"XFZeNj9ITEDo+XFCutqYYeTDXRUYQbxiI76M8QaQwdlgGcaADIp3ROnmFuJqjUPetUTfYHBIxS3AX6hc",
+	"PaJqPgZ9e4lhAvFdhjIHvHbf19Hy+7ynuooIGFvBPRHLJBBVgRYBBKoizwS8IqWC2NVnfGlWVuZbiU/S",
+	"pQREPgyd3SjUFxZxiBrlPtnGUQK9FIfd61qlwUp1KZUBHqMNjBIpgVnx3ZJnLBzHnNLVzpyazsWn4sIz",
+	"P85sZ/fAs674/ZX+0dtVu7Km4p3+0ZvZN6bDn+TXM935YE0vPNe6Mukt/9kx56ZzrbuWPc0fzV3dNT37",
+	"3DMudeeC/sIeyNbduzhM+03pClPZ06J+AxObdWMQBBgmiXy9QkS+VvlxFEGfxNjLid0NwpzqoUzj0N9P",
+	"5H3dVW9aKIsC2/grxF51oHXDe+vNLvU5ZalueL8UN2Oj440JAVEAcElVMi71sXll0+r2lTl3LcPTxZv3",
+	"4o0h3ozFG1O8ORdvLsSbS/HGEm/+I958EG8mg+HAMg3vt9NfTv/tvfMSFK1C6L39rfI7WWPY+PMv76Q/",
+	"//Zr/vO7t//+zXPfVm49w756b5d/fFe5lZX5Ra/cG0x2pq594eizS++97br2lbeYlX927Zk3tm+mVFjM",
+	"+UT3nOJq3jC2MQ5QBAjsXN9FK3Gn9MnVsbukUZFiz9KGhw0/N1oF2zjJQDzoYRnk0splUyKJFelqkCWp",
+	"RJRZuWPPjhlC1/lqJVLfgD1uruZX3AxxRDDwSROTazI/9i5tw1vMGdjqs1l+abuX7L9zbslNW/lIVT0Q",
+	"AjnDHFRpTWmvyvZErW9BvmbtYaKIK51EAe2v7np9uitQ2M/VUnQZ7yzzer8hVVdS3EJjgBKmI0FPcGfU",
+	"TSquBEcwK9Ct5nHHUW2pMC5NnSLMuc7M4AvHNKdssb5YTHRHMl8qjCq1286Ynv40rhrub7xm1Vts2PxF",
+	"mI5kGqHSunxDpUYqH0GKC+WgYDSKCFsveGkUEbjKOL1BkVd6Vb8BOtwnuOtOD/43iWSINojsQ+lulDp5",
+	"UvX5sVdV2ulBusO1YakmnoakrAEZ5szlXj7Xmy9mVKFl9475H9PILl3b9uxzl834xfTD1L6ZenNzPrfs",
+	"qXQm0IGJU3JIpzmJuzakHY43GxAFzR3dwCQBK7inc7HOnhaeCJx7WSzhXXgehgi9NvSpYU7MsScYLNQy",
+	"up6bnm0Yi5lljvN7a2rPTEd3rWuTYag16Zxy1pVpL1yBu+JrOuGWUy7nFNdY6lw6yAo52EsGvnlgs4WY",
+	"j0sfyATfPBhCn2DkZ2rYHhXv4pD0f9UTGUztZpJxqdv/sV64vXTx3vV0g1+MjddiQU3NK937p/fuNL/+",
+	"zftFuP5ncf32VHjw9lR88qv45NfsySMts0Le5Siaa6ZlhbhB6RfcrhBvEg9E1KsZZXt9SbfmUJhCModa",
+	"SXzLElaSHkEGpGiEYQAjgoBsvwjHIdxD9d815cTZBmrd/cxtqLpx08vxxujJm8kqdXSKUVLr2C3fKfOC",
+	"3VZZW9eqO2t9dlraNxo4WQUmzajMmFdzOlcvFxR5pkzfn85ngm04N+az7sWn1rua81bwxjJKZEwU12LJ",
+	"VnS0Sjma12c/r9JOZdEEryCjwYyCbYwiCQGIDfASZUtQJ3/n5nTMvZuGaV2bjtzC7jMJhTfz9zRPRJOZ",
+	"fwa3yfd3HiTpdhsiiL0Gt0uFtHLxZoKu0Lc6LdxU3eI4SH3iNTp6eLkkTrG/Bzpkb56zWjJogNEdwtSh",
+	"vtlyVvVsllXbwIiA0MrqSlpHibfCEEbCPlbdWN+T29U2m/nNe11j+RZinxK+6mt7Fu0UBsLCmJg6ndgX",
+	"dEtAn3jn9nxuTdhOuU7/Xehz4WnuN5jbE1brxsr20nXX7ONE2NFbECPvdX1Qap0HG4pJ/TruAwJXMb6X",
+	"dN270eeuyTRk57099caW/dEam92d4e8XGpd25U5m3/lgC25RiPL7YqoKOqZzQTdXZo59bk1Mz9BnTIMf",
+	"io/Mc9Mxp4Y5Lz22Zp6hO+PcMmFDOXV1w52Y83ntEQuMyX6d6Z94I2Pzff3HmTn2XNO5sqZsYjjmle2a",
+	"3tzVHdebU6VoRwS3dfjNuUWNLD0DUNf+YE69C8deiBUWUxq3U/wg1Z1qoQrcDtpDw8iryNs71H8eIAx9",
+	"spebs2Ko1sBs53WP0jAEt3Q1IjiFEr4swzjGXgjvYCh3xNOAnP6UFfE7VZo6DcJ8CxxD+puEIfnkNq89",
+	"ezqh25KzyeLigtnLY2tOR55eGou5a1+ZDsWdK9u1HeOTMTHnvSbFdn2fIJ/tq7dF7CQEkLREk36tWxM+",
+	"GXcxZLmsUfuj5AKwF659bjvZlJ5N9OmUOwCu7GtzXEiA6F5psi9JmniU+UEawhK7WmNAWL15Xk3CCe7t",
+	"D2DiY7TN/JqDRYT+TGF4rxVKSKKRNdSok0P7isgaRezemNmJtg0BodCq/Q1EwR9Rkt5SCAMkxsWj5O9v",
+	"Bl04meab9pTjJbGtzCkpfH7z4TYLbWzyaGaORe8WrpDcNOAFYNRjP6DUWKmqjLoLunIjv+6/lPqwesaR",
+	"ysNTu7xZguupeb71dzI9TxzpoUGhl3GKJeYl3E0Ozw9j6sro1vdqE4rCrdBQvIXRwQ1huEpDgL11TnBb",
+	"C05WOOscleCvMCL3S3oL72Ak0zErDKzWkPEuw3OJBlLXiLLoDIeFh7gOdY8OhoOJbeSu0anp3tgOdTBl",
+	"UGiL2472zbTBKlpDtFrLPccNi5vgxyPrdHMbARSW6qcYNdeu7502GWqV9uqRqigg64MIr2IgDkVFkZeX",
+	"DdeBESr5HtHXNYyYLDSs0S2hLM8RPdDp5HgG1SkzLzfoW1dDO1uWa1x7GKNUq5e8fAn8Nq3+0nbNTHWe",
+	"u/rC0aeZZ/7cZFFWE/povpiZzpXuZF77OdfSHdNwzCykSndMnXl33IXD6i3m5uKKai3WB9ObX+pOpq+8",
+	"X8yZVk5Vbv2jRfV0Zqq5jn6VP7gyXcemT7iAu45uTYV73XI4AXms18TOSHZmtj3x+K90S2RhToSKN9a5",
+	"1Utfawoe+VE6a26pd0IJXxbYnm0nPQWk5wrTAf7B+GsED6lXBKJWtz4m9vTCY/r0jf5JGNIL3dEvTOGH",
+	"bIypKexY12ZW2KbTwjHNbENrbDrUgJuO88qfe4Bxqz+zEv3T3Vh6G6JkLffD8Id5ZIVH4t4zaZZVZbE6",
+	"7v1WOqkwDOl8KkJf+89T+TkdeZQL6ccIQSs/YLKwcOD/xVGfMIRGDzD7kw/IUBablcdPleOqOpV/ySEY",
+	"mQe0iPLac1Mlj+Has1o+7L3ii8TCsi7a/hY1hwHkpwFq1bjZmItLj/1QXkPYR5du8ScEbLbd3RLfL1bs",
+	"6mKP0119TkDUmzlinkgPM/XhAq143P2eoIQ0972fSy9jggxAj5Ip9fDCbniQuAPG5kx3qIbIjr7xc5A8",
+	"6H9qmmPxZxrFMqNef+7m5R5n99PM9MoRLi0erCPmdEfQWS9RbGjsmPnTFaPWiy+VRo6aH41hV/04IWw9",
+	"HC0P2oI9enFBaOCI+SDf1OvDgNxxcKw9pyv2NcTyc2h7Ldx5K69o8W723fWZGqI9eOwceALFrss8Pkb2",
+	"zLMz4IfNj7zy8ff/CWaHwIzXMjn4ofuD5sbuwP5x9/4JZkZz7oKjZY384GCveZGnDDnqvj/FrMgZ8Vom",
+	"xSJCLTxpYwWrecQ954pj5sU+jAXlJo6MF1lmDlkwROjdgZ4BkSjqX7pCa/GiRuKMnOOyGBhOfJ/jh3Dr",
+	"Jeh/fUekuuFV5L44n+huPV1FcwaKg7iSdUykumWTv7bLVOPTCsfp1kOBJt8eTZK0IXD9uY+LDwd3KElB",
+	"6HE+SKd2rcOlUJcnjuCiCcXglwDcH3I2sTnea9fsZ2mHaAoXOI2/yhOR9U0d1ppCAX7bInzPzgo3hFr5",
+	"XVlpHpm45lEp50Tyy8QOO1LgtCWpa7Shfm7+t5eVxK0zr0xXlrfHZWg7JDHIl6/rfutCZ0xHl1gcnt6t",
+	"T/IJSWixwYOGDftqNjGzDRpreq1PrDELq5+OsyCa9hPCByRf68zeVaTrquZKaUzVVcl7QketdrSwLOqt",
+	"mbs4t+SCTn5Ejtb9UnfVXtgjeZeQr09yaMqPA9reDrgk8lYq493t/K99DkJTXcP3vsD7tryDdyBM9wi4",
+	"yrp0TStJY8/7sLLa9SotzafixLfXOBoBkuKGE5UhQJFXCapoTMHYlZuUvabUaLkJKeVUAFpXsN5rThfG",
+	"t2JwD83h2dSCmiLQc90vn3h4ciXyhRwJaddP2+CSxNvGmdU5nq3ZSevHBsQhE6rK6Gpyaz5SBwnhJk/t",
+	"vYdb0MyqyeOGO7WawyKLH5NfozCXe6UEpRmJ9qvxaG2HJzQAIfHyU9tPEcQtNNuIQHJDd6Z/usrSVhYn",
+	"J3nmq/yeZ8DKb/fOhNWiHOWTsiOFabOY5PNT7jfxyint+wW0lh0y0nDW8nG9bilyxBpSR4hIaXNvncqb",
+	"q468ey9eetQKlwbTX9nTMYtNdhfmPLu6McfT/Nq9XDj88tyxsos5DZrnlwtWu0+Meo4MjcZQo3RQEc7m",
+	"R0+3HK2wbxoy8M3rbTvRwvW8O83FUbQn/Sjam34U7UE/ivahH5fT+uYzp5zwSbjzsm8GzJsC3TgctqBl",
+	"c0q3ugwclg+y+0gNXII0JN6TZQIsVj5fyEPRvfwVWSsehoU7c19vZmuakD4HkptXtx/gKAUhakjr2OVD",
+	"HQ6+rhGBId/42p3huNE/zSl5xfcu+JVnn59PrKnJTuldm72WMTHRZ2VR40PSloVbSA2ad1WkWob6+Z5V",
+	"+cFw8O0EfgObbZhvZLDrk7eDs+8PD6xaGPtfWuLvfqotVGer6EkR/CUtdo+MWY1RVk0KkeAWaCcxU9vz",
+	"4i3vbtpigzyrzj7JW7IasuW1N92717ZQ3zabRMJ/L6cAyt+ZZ/7ZJfxh7C5I+izQu6PzgVlQfooRuacW",
+	"Kj8iVhjJjA0MAiDATK440WtCtoOHB+FbMuXz+u4aavYWRhpzXEFtRqnXrIhAvAQ+1P5mGzPr7xqM6Bmh",
+	"RANa4gN2XmiogZTEGyq2mnmt4RhsULTSEkjSrXYLyVdYbdTmp4gSDUSBBq9ilp/kXptDfId8qM1wfIcC",
+	"iJM3mkW0JN1uY0wSreSzGGpZTluNcVmjfaJLNIojDX7z1yBaQe1vdOsxpY4fLUR3UMtMWi3DGPbyPygw",
+	"RQnws4p3dCb9vWg6y0mlYejHOCiaHWoYbmICywT4Wfhx1iWa16CgIl5qyQZgcpI79DR+quuPSKQ65xTF",
+	"PQSTN39Ef0TuGiVasoU+WqJMorUg9lOqt2so0WKMVigCYXiv3YIEBlocaXSYk7PR6BaR29T/AsmbGK9G",
+	"yRpgCKIgI3kU+9FJFAdwlGB/tAEJgXiUz+RRvIUR2KIT+t43/03iSNN+17fAX0NtgnwYJXCocanV3r05",
+	"/fxGY1PHmFkVUhFJYLikhIZZvUADiWZgCGjWAY0GbMdRoumEYHSb0ion03gMMbpjBRLt1zen2QSMQHaU",
+	"reidzxvxszZYH/lLktHt/UkUjH59czoK4QqEfJ3hjzO/HZOQx1BSSOvBFLFde8IAoIfYvXvzbiDiAb3n",
+	"R0XBFg3OBr+8OX3zli2bZM0QYRT7WzSi5c6+D1aQrYbZ8T16kCkYnA0uYBFfS+thwHYrOGDRl6whCBiE",
+	"cIaVzkINRMTMTgxmECxD18+7VYgR9+70NFdAucL/j9E/BAyt4+ld7oLdnUR5FoCtBGic1sMvds0LURcC",
+	"QIt8aFuomgNNWCNldLY/ZJo9WNH+5nQnJ0y1isMQ4sFnWqAYdTohxQD6AIaQwPokGLPfxWD5FzMX9mdj",
+	"D84JbCkzb9goJK+EOWI3HsWnbZxIGDWLkx/PqT9TmJD3cXBfYRKg2fGyZWhEl7D+HKtxqUzRwzGMTyob",
+	"nlSNzgsYnRJEY+hDdAfxKNcMuR8lGX3nOx1W8NAN3g5vprrj/NxjPGxo8eOJkw39iTV+ogaNGDOlmWpm",
+	"T9Qo1atOljjenHCHxAlXFJ+yaebEOEHB0zVL4meil8SPo5Yqnrsmi/l7UFt/phDf7xqr7AK+jNW06Rhu",
+	"Nzbwmie5tPfVQ5ScKzl/1XJebOe0tdUj7FahSIsWqFBEocjrQJFnsjEkwaA/2NR4LlAoGR8JjAKZ6ZFl",
+	"ah19T1FmezRb+60paRWcKDj5WXCSohcBJK3y8WMhJTuI+UPwo+K6aFJD5qy6UkIUaiglpH9m9mMGjcLb",
+	"yXanApy0axdGiGBE7K8RDIwA90QGoJBBIcMjkOG5ti1o5sgXKbsB7i2uo+9+gK3xQ1s8gRJaJbQvZDln",
+	"k/XF+PnGzlNKI481G2XfFS597rd9TQWRD0NHCFVXEqok9HiX1dp0/sGOumrK2W4Jz2rsJ+X8MKc3tW/a",
+	"xVvIFKLkWsn10cq1MI9fpUDz71ia83nnil06xa+EWgn10Qp1aSa/UrG2Z32lOt4qoVZC/QqEejeRX6VM",
+	"5x+LtqdT06BfoWyV6+rxUSXbSraPVrark/k1yXfxPb3Rdz6WRhzAh9F3NgDW+GH0PS/S3+NdfEZAib0S",
+	"+5/m9t5N5ydojYvDE7S0E6eX4pMvxLUbWgq4qAXZAuKvJboA/Vkhg0IGhQzPiwyHaT2g+DburJRspJac",
+	"pPLDS9zBb4amplOgCpYULClYeoGw1O8TZUcEQk9he42+07xWi/5WGPvsoQI0BWgK0J4E0OSNcaF8KbYc",
+	"E/rnt+MUtihsUdjyYrFFWYOPsQYVuClwU+D2ysCt+wPxf1l7khbne5t7nGpRe/sKKRVSvlSkbGJXIecv",
+	"5kBQOaziWa1WhVkKsxRm/ZUwSxnCjzGEFV4qvFR4qfDyUUdTf1LY7HMY2DwxTot9zUv0N6PV4RcFsApg",
+	"2/NQvRhbtXTApxVWcqQ40FBVqKBQQaHCk6OCsgYbcanbGFSYpDBJYdILwaT2tN0/4xjyowBIbm5lH6Rv",
+	"sbayAta4x6eBBCDLvoOucEzhmMKxSku5QL0Ug6snqHCg6P0ZQ4UFCgsUFhwZFnBRPRgNepg4Cg0UGig0",
+	"eGI0eHoDR0SCl2bfSMGnwbyJv8C2vST2fI/zfi4tr8BLgZcCr0pLuSQ9xacZWTGxXgCXIA3J4GzgnLNX",
+	"wCjdUCjQx96lbXiLOfvivj6b5Ze2e8n+s/Kfhz9UiWIY0QPGaLlDd68UECkgUkD0WoBIbZo1gGEPe1IB",
+	"oQJCBYR/aSBsNWR32tgxYF5hxvIvXoqfr2u0TwOcnON4MwYEUExUoeQKDn8kHFY/rA8I9CgPBodWJvEh",
+	"VePlMoFk8CSf7w/RBu3b1PN+0WuCkkd+Y6+CKKMtWMHdl7dbwGUGVlDhi8KXI/7e9pGIZf4hgO/86qHP",
+	"l/H15D7yc5KUaCrR/ImWEJu1rS3ldoLka5LDgfjxueGg/OWq4aD0yZvhoPa1jM/DnnS+kE/277468HK/",
+	"0d/+mQMBu4pDXm3KRJ5YlKKm0igUbCmL5bVaLKKkP8ERURnQ9DRgckqUFaMwR1kxP0lU+37gKKfFZltV",
+	"SlyVuL4McX2NHxTqL7H90uLTDI9KbpXcvgK5/QukoT9E+vdIYpqXU4CgAEEBwl80aagMY/LDyW3owU9X",
+	"q6AGBRvKRfhqXYRcynu6HTqSGkgQpqd/MAcb5R9UgKP8gz9DToWMjiN/DfAKRStvi+ESYhj5WX8aI8F5",
+	"+ZlQXMmtktufJbcvKkuRTDh+bJiBhIL80ZNiCT8S3LbOZyealU2hoELZFK/WpsiEvKeq0p5HoI4uPQ0K",
+	"DjTKnlBgo+yJHyej7DBVq1yyEkoklUiq9f/Vrv9UxvtCS8/jl1m5vos/K6zWfgU0au3/sfK5y+U1ysUI",
+	"th+cciAIXbTJiNIroqfEVYnrzxFXlbeBokYe3+iUXJfP6KosAYAVLePHQtQdxNVIh3Jj7holGoyCbYwi",
+	"ooUoIYkGwlAja6iBO4BCcBtCjc4wLW9LA1HAnvtUqinhAYpW2sKZJBqJta9riGFeWEu20EdL5GsBJACF",
+	"iZak/loDCWsgSbfbGBMYFBQkmg8i7RZqyziNgjeDYV27uc671A8f9YPx8TkDZ1GS96PHCOeMr4zxw3CQ",
+	"QD/FiNyz7rOJMDj7/TOlPIH4LudLufELGFGOwkDLymgpDgfDAf17NlgTsj0bsSC8cB0n5Oxfp/86HTx8",
+	"fvj/AwA9Dsa08zsBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -3,14 +3,18 @@
 package ocpi
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 //go:generate oapi-codegen -config cfg.yaml ocpi22-spec.yaml
@@ -21,25 +25,74 @@ type Api interface {
 	GetVersions(ctx context.Context) ([]Version, error)
 	GetVersion(ctx context.Context) (VersionDetail, error)
 	SetCredentials(ctx context.Context, token string, credentials Credentials) error
+	GetCredentials(ctx context.Context, token string) (*Credentials, error)
+	UpdateCredentials(ctx context.Context, token string, credentials Credentials) (*Credentials, error)
+	DeleteCredentials(ctx context.Context, token string) error
+	RotateToken(ctx context.Context, countryCode, partyId string) error
+	DeregisterParty(ctx context.Context, countryCode, partyId string) error
 	SetToken(ctx context.Context, token Token) error
 	GetToken(ctx context.Context, countryCode string, partyID string, tokenUID string) (*Token, error)
-	PushLocation(ctx context.Context, location Location) error
+	PushLocation(ctx context.Context, location *store.Location) error
+	PushEvseStatus(ctx context.Context, chargeStationId string) error
+	ListLocations(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Location, int, error)
+	GetLocation(ctx context.Context, locationId string) (*Location, error)
+	GetClientOwnedLocation(ctx context.Context, countryCode, partyId, locationId string) (*Location, error)
+	SetClientOwnedLocation(ctx context.Context, location Location) error
+	PushSession(ctx context.Context, transaction *store.Transaction) error
+	PushSessionUpdate(ctx context.Context, transaction *store.Transaction) error
+	ListSessions(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Session, int, error)
+	PushCdr(ctx context.Context, transaction *store.Transaction) error
+	ListCdrs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]CDR, int, error)
+	PushTariff(ctx context.Context, tariff *store.Tariff) error
+	PushTariffDeletion(ctx context.Context, tariffId string) error
+	ListTariffs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Tariff, int, error)
+	StartSession(ctx context.Context, countryCode, partyId string, startSession StartSession) (*CommandResponse, error)
+	StopSession(ctx context.Context, countryCode, partyId string, stopSession StopSession) (*CommandResponse, error)
+	ReserveNow(ctx context.Context, countryCode, partyId string, reserveNow ReserveNow) (*CommandResponse, error)
+	CancelReservation(ctx context.Context, countryCode, partyId string, cancelReservation CancelReservation) (*CommandResponse, error)
+	UnlockConnector(ctx context.Context, countryCode, partyId string, unlockConnector UnlockConnector) (*CommandResponse, error)
+	SetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, setChargingProfile SetChargingProfile) (*ChargingProfileResponse, error)
+	GetActiveChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, duration int, responseUrl string) (*ChargingProfileResponse, error)
+	ClearChargingProfile(ctx context.Context, countryCode, partyId, sessionId, responseUrl string) (*ChargingProfileResponse, error)
+	AuthorizeToken(ctx context.Context, chargeStationId string, token ocpp201.IdTokenType, knownToken *store.Token) (*services.TokenAuthorization, error)
+	SyncTokens(ctx context.Context) error
 }
 
 type OCPI struct {
-	store       store.Engine
-	httpClient  *http.Client
-	externalUrl string
-	countryCode string
-	partyId     string
+	store         store.Engine
+	clock         clock.PassiveClock
+	httpClient    *http.Client
+	tariffService services.TariffService
+	evseMapping   services.EvseMappingService
+	externalUrl   string
+	countryCode   string
+	partyId       string
+	// v16CallMaker and v201CallMaker send commands to the charge stations and
+	// wait for the responses: the responses are only received if the charge
+	// station is connected to this instance of the manager
+	v16CallMaker   handlers.SyncCallMaker
+	v201CallMaker  handlers.SyncCallMaker
+	commandTimeout time.Duration
+	// authorizeWithAllEmsps is set if every eMSP is asked to authorize a token
+	// whose issuer is not known
+	authorizeWithAllEmsps bool
+	// evseLocations caches the EVSEs of the registered locations indexed by
+	// charge station id until evseLocationsExpiry
+	evseLocationsMutex  sync.Mutex
+	evseLocations       map[string]evseLocation
+	evseLocationsExpiry time.Time
 }
 
 func NewOCPI(store store.Engine, httpClient *http.Client, countryCode, partyId string) *OCPI {
 	return &OCPI{
-		store:       store,
-		httpClient:  httpClient,
-		countryCode: countryCode,
-		partyId:     partyId,
+		store:          store,
+		clock:          clock.RealClock{},
+		httpClient:     httpClient,
+		tariffService:  services.BasicKwhTariffService{},
+		evseMapping:    services.NewDefaultEvseMappingService(store),
+		countryCode:    countryCode,
+		partyId:        partyId,
+		commandTimeout: defaultCommandTimeout,
 	}
 }
 
@@ -47,6 +100,23 @@ func (o *OCPI) SetExternalUrl(externalUrl string) {
 	o.externalUrl = externalUrl
 }
 
+// SetTariffService sets the tariff service used to price CDRs
+func (o *OCPI) SetTariffService(tariffService services.TariffService) {
+	o.tariffService = tariffService
+}
+
+// SetEvseMappingService sets the service used to map OCPI EVSEs to the EVSEs
+// of charge stations
+func (o *OCPI) SetEvseMappingService(evseMapping services.EvseMappingService) {
+	o.evseMapping = evseMapping
+}
+
+// SetAuthorizeWithAllEmsps sets whether every eMSP is asked to authorize a token
+// whose issuer is not known
+func (o *OCPI) SetAuthorizeWithAllEmsps(authorizeWithAllEmsps bool) {
+	o.authorizeWithAllEmsps = authorizeWithAllEmsps
+}
+
 func (o *OCPI) GetVersions(context.Context) ([]Version, error) {
 	return []Version{
 		{
@@ -69,59 +139,46 @@ func (o *OCPI) GetVersion(context.Context) (VersionDetail, error) {
 				Role:       RECEIVER,
 				Url:        fmt.Sprintf("%s/ocpi/receiver/2.2/commands", o.externalUrl),
 			},
+			{
+				Identifier: "chargingprofiles",
+				Role:       RECEIVER,
+				Url:        fmt.Sprintf("%s/ocpi/2.2/receiver/chargingprofiles", o.externalUrl),
+			},
 			{
 				Identifier: "tokens",
 				Role:       RECEIVER,
 				Url:        fmt.Sprintf("%s/ocpi/receiver/2.2/tokens/", o.externalUrl),
 			},
+			{
+				Identifier: "locations",
+				Role:       SENDER,
+				Url:        fmt.Sprintf("%s/ocpi/sender/2.2/locations", o.externalUrl),
+			},
+			{
+				Identifier: "locations",
+				Role:       RECEIVER,
+				Url:        fmt.Sprintf("%s/ocpi/receiver/2.2/locations", o.externalUrl),
+			},
+			{
+				Identifier: "sessions",
+				Role:       SENDER,
+				Url:        fmt.Sprintf("%s/ocpi/sender/2.2/sessions", o.externalUrl),
+			},
+			{
+				Identifier: "cdrs",
+				Role:       SENDER,
+				Url:        fmt.Sprintf("%s/ocpi/sender/2.2/cdrs", o.externalUrl),
+			},
+			{
+				Identifier: "tariffs",
+				Role:       SENDER,
+				Url:        fmt.Sprintf("%s/ocpi/sender/2.2/tariffs", o.externalUrl),
+			},
 		},
 		Version: "2.2",
 	}, nil
 }
 
-func (o *OCPI) SetCredentials(ctx context.Context, token string, credentials Credentials) error {
-	for _, role := range credentials.Roles {
-		err := o.store.SetPartyDetails(ctx, &store.OcpiParty{
-			Role:        string(role.Role),
-			CountryCode: role.CountryCode,
-			PartyId:     role.PartyId,
-			Url:         credentials.Url,
-			Token:       credentials.Token,
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	reg, err := o.store.GetRegistrationDetails(ctx, token)
-	if err != nil {
-		return err
-	}
-
-	if reg != nil && reg.Status == store.OcpiRegistrationStatusPending {
-		// register new party
-		err = o.RegisterNewParty(ctx, credentials.Url, credentials.Token)
-		if err != nil {
-			return err
-		}
-		// delete old token
-		err := o.store.DeleteRegistrationDetails(ctx, token)
-		if err != nil {
-			return err
-		}
-	}
-
-	// store new token
-	err = o.store.SetRegistrationDetails(ctx, credentials.Token, &store.OcpiRegistration{
-		Status: store.OcpiRegistrationStatusRegistered,
-	})
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (o *OCPI) GetToken(ctx context.Context, countryCode string, partyID string, tokenUID string) (*Token, error) {
 	tok, err := o.store.LookupToken(ctx, tokenUID)
 	if err != nil {
@@ -147,7 +204,11 @@ func (o *OCPI) GetToken(ctx context.Context, countryCode string, partyID string,
 }
 
 func (o *OCPI) SetToken(ctx context.Context, token Token) error {
-	tok := &store.Token{
+	return o.store.SetToken(ctx, fromOcpiToken(token))
+}
+
+func fromOcpiToken(token Token) *store.Token {
+	return &store.Token{
 		CountryCode:  token.CountryCode,
 		PartyId:      token.PartyId,
 		Type:         string(token.Type),
@@ -160,89 +221,73 @@ func (o *OCPI) SetToken(ctx context.Context, token Token) error {
 		LanguageCode: token.Language,
 		CacheMode:    string(token.Whitelist),
 	}
-
-	return o.store.SetToken(ctx, tok)
 }
 
-func (o *OCPI) PushLocation(ctx context.Context, location Location) error {
-	parties, err := o.store.ListPartyDetailsForRole(ctx, "EMSP")
+// getReceiverUrl returns the URL of the party's receiver interface for the
+// module with the CPO's country code and party id appended.
+func (o *OCPI) getReceiverUrl(ctx context.Context, party *store.OcpiParty, module string) (string, error) {
+	endpointUrl, err := o.getReceiverEndpointUrl(ctx, party, module)
 	if err != nil {
-		return err
+		return "", err
 	}
-	for _, party := range parties {
-		err = o.pushLocationToParty(ctx, party, location)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return fmt.Sprintf("%s/%s/%s", endpointUrl, o.countryCode, o.partyId), nil
 }
 
-func (o *OCPI) pushLocationToParty(ctx context.Context, party *store.OcpiParty, location Location) error {
-	// TODO: retrieve endpoints from store, not via OCPI exchange
-	versions, err := o.getVersions(ctx, party.Url, party.Token)
-	if err != nil {
-		return err
-	}
-
-	endpointUrl, err := getEndpointUrl(versions)
-	if err != nil {
-		return err
-	}
+// getReceiverEndpointUrl returns the URL of the party's receiver interface for
+// the module.
+func (o *OCPI) getReceiverEndpointUrl(ctx context.Context, party *store.OcpiParty, module string) (string, error) {
+	return o.getModuleEndpointUrl(ctx, party, module, RECEIVER)
+}
 
-	endpoints, err := o.getEndpoints(ctx, endpointUrl, party.Token)
-	if err != nil {
-		return err
-	}
+// getSenderEndpointUrl returns the URL of the party's sender interface for the
+// module.
+func (o *OCPI) getSenderEndpointUrl(ctx context.Context, party *store.OcpiParty, module string) (string, error) {
+	return o.getModuleEndpointUrl(ctx, party, module, SENDER)
+}
 
-	locationsUrl, err := o.getLocationsUrl(endpoints)
+func (o *OCPI) getModuleEndpointUrl(ctx context.Context, party *store.OcpiParty, module string, role EndpointRole) (string, error) {
+	endpoints, err := o.getPartyEndpoints(ctx, party)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	err = o.putLocation(ctx, locationsUrl, party.CountryCode, party.PartyId, party.Token, location)
-	if err != nil {
-		return err
+	endpointUrl, ok := findEndpointUrl(endpoints, module, role)
+	if !ok {
+		return "", fmt.Errorf("no %s endpoint for %s found", module, strings.ToLower(string(role)))
 	}
-
-	return nil
+	return endpointUrl, nil
 }
 
-func (o *OCPI) getLocationsUrl(endpoints []Endpoint) (string, error) {
+func findEndpointUrl(endpoints []store.OcpiEndpoint, module string, role EndpointRole) (string, bool) {
 	for _, endpoint := range endpoints {
-		if endpoint.Identifier == "locations" && endpoint.Role == RECEIVER {
-			return fmt.Sprintf("%s/%s/%s", endpoint.Url, o.countryCode, o.partyId), nil
+		if endpoint.Identifier == module && endpoint.Role == string(role) {
+			return endpoint.Url, true
 		}
 	}
-	return "", errors.New("no locations endpoint for receiver found")
+	return "", false
 }
 
-func (o *OCPI) putLocation(ctx context.Context, url string, toCountryCode string, toPartyId string, token string, location Location) error {
-	b, err := json.Marshal(location)
-	if err != nil {
-		return err
+// getPartyEndpoints returns the module endpoints of the party: they are
+// discovered through the party's versions endpoint if they have not been
+// stored with the party.
+func (o *OCPI) getPartyEndpoints(ctx context.Context, party *store.OcpiParty) ([]store.OcpiEndpoint, error) {
+	if len(party.Endpoints) > 0 {
+		return party.Endpoints, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%s", url, location.Id), bytes.NewReader(b))
+	versions, endpoints, err := o.discoverParty(ctx, party.Url, party.Token)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	o.setRequestHeaders(ctx, req, token, toCountryCode, toPartyId)
 
-	resp, err := o.httpClient.Do(req)
+	discovered := *party
+	discovered.Versions = versions
+	discovered.Endpoints = endpoints
+	err = o.store.SetPartyDetails(ctx, &discovered)
 	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("status code: %d", resp.StatusCode)
+		return nil, err
 	}
-
-	return nil
+	return endpoints, nil
 }
 
 func (o *OCPI) setRequestHeaders(ctx context.Context, req *http.Request, token string, toCountryCode string, toPartyId string) {
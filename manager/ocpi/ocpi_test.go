@@ -49,11 +49,41 @@ func TestGetVersionDetails(t *testing.T) {
 				Role:       ocpi.RECEIVER,
 				Url:        "/ocpi/receiver/2.2/commands",
 			},
+			{
+				Identifier: "chargingprofiles",
+				Role:       ocpi.RECEIVER,
+				Url:        "/ocpi/2.2/receiver/chargingprofiles",
+			},
 			{
 				Identifier: "tokens",
 				Role:       ocpi.RECEIVER,
 				Url:        "/ocpi/receiver/2.2/tokens/",
 			},
+			{
+				Identifier: "locations",
+				Role:       ocpi.SENDER,
+				Url:        "/ocpi/sender/2.2/locations",
+			},
+			{
+				Identifier: "locations",
+				Role:       ocpi.RECEIVER,
+				Url:        "/ocpi/receiver/2.2/locations",
+			},
+			{
+				Identifier: "sessions",
+				Role:       ocpi.SENDER,
+				Url:        "/ocpi/sender/2.2/sessions",
+			},
+			{
+				Identifier: "cdrs",
+				Role:       ocpi.SENDER,
+				Url:        "/ocpi/sender/2.2/cdrs",
+			},
+			{
+				Identifier: "tariffs",
+				Role:       ocpi.SENDER,
+				Url:        "/ocpi/sender/2.2/tariffs",
+			},
 		},
 	}
 
@@ -172,7 +202,7 @@ func TestPushLocation(t *testing.T) {
 	require.NoError(t, err)
 
 	ctxWithCorrelationId := context.WithValue(context.Background(), ocpi.ContextKeyCorrelationId, "some-correlation-id")
-	err = ocpiApi.PushLocation(ctxWithCorrelationId, ocpi.Location{Id: "loc001"})
+	err = ocpiApi.PushLocation(ctxWithCorrelationId, &store.Location{Id: "loc001"})
 
 	require.NoError(t, err)
 }
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// maxPageLimit is the maximum number of objects returned in a page of a sender
+// interface
+const maxPageLimit = 100
+
+// pageParams returns the offset and limit of the requested page: the limit is
+// capped to maxPageLimit.
+func pageParams(offset, limit *int32) (int, int) {
+	pageOffset, pageLimit := 0, maxPageLimit
+	if offset != nil && *offset > 0 {
+		pageOffset = int(*offset)
+	}
+	if limit != nil && *limit > 0 && *limit < maxPageLimit {
+		pageLimit = int(*limit)
+	}
+	return pageOffset, pageLimit
+}
+
+// nextLinkRegexp matches the URL of the next page in a Link header
+var nextLinkRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// nextPageUrl returns the URL of the next page of a sender interface from the
+// Link header of a page: an empty string is returned for the last page.
+func nextPageUrl(header http.Header) string {
+	matches := nextLinkRegexp.FindStringSubmatch(header.Get("Link"))
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// parseDateParam parses an optional date_from or date_to query parameter.
+func parseDateParam(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	date, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &date, nil
+}
+
+// setPaginationHeaders sets the OCPI pagination headers for a page of a sender
+// interface. The Link header is only set when there is a next page: it keeps the
+// query parameters of the request.
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, path string, offset, limit, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("X-Limit", strconv.Itoa(limit))
+
+	if offset+limit >= total {
+		return
+	}
+	query := r.URL.Query()
+	query.Set("offset", strconv.Itoa(offset+limit))
+	query.Set("limit", strconv.Itoa(limit))
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	next := url.URL{
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+}
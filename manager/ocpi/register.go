@@ -45,6 +45,7 @@ func (o *OCPI) RegisterNewParty(ctx context.Context, url, token string) error {
 
 	err = o.store.SetRegistrationDetails(ctx, newToken, &store.OcpiRegistration{
 		Status: store.OcpiRegistrationStatusRegistered,
+		Url:    url,
 	})
 	if err != nil {
 		return err
@@ -177,19 +178,7 @@ func getCredentialsUrl(endpoints []Endpoint) (string, error) {
 }
 
 func (o *OCPI) postCredentials(ctx context.Context, url, token, newToken string) error {
-	creds := Credentials{
-		Roles: []CredentialsRole{
-			{
-				CountryCode: o.countryCode,
-				PartyId:     o.partyId,
-				Role:        "CPO",
-			},
-		},
-		Token: newToken,
-		Url:   o.externalUrl + "/ocpi/versions",
-	}
-
-	b, err := json.Marshal(creds)
+	b, err := json.Marshal(o.credentials(newToken))
 	if err != nil {
 		return err
 	}
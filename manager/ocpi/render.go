@@ -8,6 +8,10 @@ func (OcpiResponseCommandResponse) Render(http.ResponseWriter, *http.Request) er
 	return nil
 }
 
+func (OcpiResponseChargingProfileResponse) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 func (OcpiResponseListVersion) Render(http.ResponseWriter, *http.Request) error {
 	return nil
 }
@@ -16,10 +20,46 @@ func (OcpiResponseVersionDetail) Render(http.ResponseWriter, *http.Request) erro
 	return nil
 }
 
+func (OcpiResponseCredentials) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 func (OcpiResponseToken) Render(http.ResponseWriter, *http.Request) error {
 	return nil
 }
 
+func (OcpiResponseSessionList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseCDRList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseTariffList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponse) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseLocation) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseLocationList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseEvse) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (OcpiResponseConnector) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 func (Credentials) Bind(r *http.Request) error {
 	return nil
 }
@@ -31,3 +71,35 @@ func (Token) Bind(r *http.Request) error {
 func (StartSession) Bind(r *http.Request) error {
 	return nil
 }
+
+func (Location) Bind(r *http.Request) error {
+	return nil
+}
+
+func (Evse) Bind(r *http.Request) error {
+	return nil
+}
+
+func (Connector) Bind(r *http.Request) error {
+	return nil
+}
+
+func (StopSession) Bind(r *http.Request) error {
+	return nil
+}
+
+func (ReserveNow) Bind(r *http.Request) error {
+	return nil
+}
+
+func (CancelReservation) Bind(r *http.Request) error {
+	return nil
+}
+
+func (UnlockConnector) Bind(r *http.Request) error {
+	return nil
+}
+
+func (SetChargingProfile) Bind(r *http.Request) error {
+	return nil
+}
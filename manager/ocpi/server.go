@@ -3,32 +3,26 @@
 package ocpi
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-chi/render"
-	"github.com/zynka-tech/zynka-csms/manager/handlers"
-	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
 	"golang.org/x/exp/slog"
 	"k8s.io/utils/clock"
 	"net/http"
-	"regexp"
 	"strconv"
 	"time"
 )
 
 type Server struct {
-	ocpi         Api
-	clock        clock.PassiveClock
-	v16CallMaker *handlers.OcppCallMaker
+	ocpi  Api
+	clock clock.PassiveClock
 }
 
-func NewServer(ocpi Api, clock clock.PassiveClock, v16CallMaker *handlers.OcppCallMaker) (*Server, error) {
+func NewServer(ocpi Api, clock clock.PassiveClock) (*Server, error) {
 	return &Server{
-		ocpi:         ocpi,
-		clock:        clock,
-		v16CallMaker: v16CallMaker,
+		ocpi:  ocpi,
+		clock: clock,
 	}, nil
 }
 
@@ -199,27 +193,106 @@ func (s *Server) PatchClientOwnedToken(w http.ResponseWriter, r *http.Request, c
 }
 
 func (s *Server) DeleteCredentials(w http.ResponseWriter, r *http.Request, params DeleteCredentialsParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	matches := authzHeaderRegexp.FindStringSubmatch(params.Authorization)
+	if len(matches) != 2 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid authorization header")))
+		return
+	}
+
+	err := s.ocpi.DeleteCredentials(r.Context(), matches[1])
+	if err != nil {
+		slog.Error("Error deleting credentials", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+	})
 }
 
 func (s *Server) GetCredentials(w http.ResponseWriter, r *http.Request, params GetCredentialsParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	matches := authzHeaderRegexp.FindStringSubmatch(params.Authorization)
+	if len(matches) != 2 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid authorization header")))
+		return
+	}
+
+	creds, err := s.ocpi.GetCredentials(r.Context(), matches[1])
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseCredentials{
+		Data:          creds,
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+	})
 }
 
 func (s *Server) PutCredentials(w http.ResponseWriter, r *http.Request, params PutCredentialsParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	creds := new(Credentials)
+	if err := render.Bind(r, creds); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	matches := authzHeaderRegexp.FindStringSubmatch(params.Authorization)
+	if len(matches) != 2 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid authorization header")))
+		return
+	}
+
+	newCreds, err := s.ocpi.UpdateCredentials(r.Context(), matches[1], *creds)
+	if err != nil {
+		slog.Error("Error updating credentials", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseCredentials{
+		Data:          newCreds,
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+	})
 }
 
 func (s *Server) DeleteReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params DeleteReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	chargingProfileResponse, err := s.ocpi.ClearChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, params.ResponseUrl)
+	s.renderChargingProfileResponse(w, r, chargingProfileResponse, err)
 }
 
 func (s *Server) GetReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params GetReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	chargingProfileResponse, err := s.ocpi.GetActiveChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, int(params.Duration), params.ResponseUrl)
+	s.renderChargingProfileResponse(w, r, chargingProfileResponse, err)
 }
 
 func (s *Server) PutReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params PutReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	setChargingProfile := new(SetChargingProfile)
+	if err := render.Bind(r, setChargingProfile); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	chargingProfileResponse, err := s.ocpi.SetChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, *setChargingProfile)
+	s.renderChargingProfileResponse(w, r, chargingProfileResponse, err)
+}
+
+func (s *Server) renderChargingProfileResponse(w http.ResponseWriter, r *http.Request, chargingProfileResponse *ChargingProfileResponse, err error) {
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	_ = render.Render(w, r, OcpiResponseChargingProfileResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          chargingProfileResponse,
+	})
 }
 
 func (s *Server) PostGenericChargingProfileResult(w http.ResponseWriter, r *http.Request, uid string, params PostGenericChargingProfileResultParams) {
@@ -239,107 +312,332 @@ func (s *Server) GetClientOwnedCdr(w http.ResponseWriter, r *http.Request, cdrID
 }
 
 func (s *Server) PostCancelReservation(w http.ResponseWriter, r *http.Request, params PostCancelReservationParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	cancelReservation := new(CancelReservation)
+	if err := render.Bind(r, cancelReservation); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	commandResponse, err := s.ocpi.CancelReservation(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *cancelReservation)
+	s.renderCommandResponse(w, r, commandResponse, err)
 }
 
 func (s *Server) PostReserveNow(w http.ResponseWriter, r *http.Request, params PostReserveNowParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	reserveNow := new(ReserveNow)
+	if err := render.Bind(r, reserveNow); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	commandResponse, err := s.ocpi.ReserveNow(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *reserveNow)
+	s.renderCommandResponse(w, r, commandResponse, err)
 }
 
 func (s *Server) PostStartSession(w http.ResponseWriter, r *http.Request, params PostStartSessionParams) {
-	// TODO: the following code supports OCPP 1.6 only, but we need to support 2.0 as well
-	// need to store OCPP protocol version for charge points on first connection
-	// need to create two handlers, one for 1.6 and one for 2.0, and call the right one based on the protocol version
 	startSession := new(StartSession)
 	if err := render.Bind(r, startSession); err != nil {
 		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
-	var chargeStationId string
-	if startSession.EvseUid == nil || startSession.ConnectorId == nil {
-		_ = render.Render(w, r, ErrInvalidRequest(errors.New("CSMS does not support start session commands without evse_uid and connector_id")))
-		return
-	} else {
-		// TODO: this needs to become a service with a configurable pattern
-		extractedChargeStationId, err := extractChargeStationId(*startSession.EvseUid)
-		if err != nil {
-			slog.Error("error extracting charge station id", "err", err)
-			_ = render.Render(w, r, ErrInvalidRequest(err))
-			return
-		}
-		chargeStationId = extractedChargeStationId
-	}
-	// We need to store the token because the StartSession handler currently expects the idTag it receives in the store
-	err := s.ocpi.SetToken(context.Background(), startSession.Token)
-	if err != nil {
+	commandResponse, err := s.ocpi.StartSession(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *startSession)
+	s.renderCommandResponse(w, r, commandResponse, err)
+}
+
+func (s *Server) PostStopSession(w http.ResponseWriter, r *http.Request, params PostStopSessionParams) {
+	stopSession := new(StopSession)
+	if err := render.Bind(r, stopSession); err != nil {
 		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
-	connectorId, err := strconv.Atoi(*startSession.ConnectorId)
-	if err != nil {
+	commandResponse, err := s.ocpi.StopSession(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *stopSession)
+	s.renderCommandResponse(w, r, commandResponse, err)
+}
+
+func (s *Server) PostUnlockConnector(w http.ResponseWriter, r *http.Request, params PostUnlockConnectorParams) {
+	unlockConnector := new(UnlockConnector)
+	if err := render.Bind(r, unlockConnector); err != nil {
 		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
-	remoteStartTransactionReq := ocpp16.RemoteStartTransactionJson{
-		ConnectorId: &connectorId,
-		IdTag:       startSession.Token.Uid,
-	}
-	commandResponse := CommandResponse{Result: CommandResponseResultACCEPTED}
-	err = s.v16CallMaker.Send(context.Background(), chargeStationId, &remoteStartTransactionReq)
+	commandResponse, err := s.ocpi.UnlockConnector(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *unlockConnector)
+	s.renderCommandResponse(w, r, commandResponse, err)
+}
+
+func (s *Server) renderCommandResponse(w http.ResponseWriter, r *http.Request, commandResponse *CommandResponse, err error) {
 	if err != nil {
-		slog.Error("error sending mqtt message", "err", err)
-		commandResponse = CommandResponse{Result: CommandResponseResultREJECTED}
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
 	}
 	_ = render.Render(w, r, OcpiResponseCommandResponse{
 		StatusCode:    StatusSuccess,
 		StatusMessage: &StatusSuccessMessage,
 		Timestamp:     s.clock.Now().Format(time.RFC3339),
-		Data:          &commandResponse,
+		Data:          commandResponse,
 	})
 }
 
-func (s *Server) PostStopSession(w http.ResponseWriter, r *http.Request, params PostStopSessionParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
-func (s *Server) PostUnlockConnector(w http.ResponseWriter, r *http.Request, params PostUnlockConnectorParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
 func (s *Server) GetClientOwnedLocation(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, params GetClientOwnedLocationParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, err := s.ocpi.GetClientOwnedLocation(r.Context(), countryCode, partyID, locationID)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if location == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseLocation{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          location,
+	})
 }
 
 func (s *Server) PatchClientOwnedLocation(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, params PatchClientOwnedLocationParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	patch, ok := decodePatch(w, r)
+	if !ok {
+		return
+	}
+
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	if err := applyPatch(location, patch); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	s.setClientOwnedLocation(w, r, location)
 }
 
 func (s *Server) PutClientOwnedLocation(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, params PutClientOwnedLocationParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location := new(Location)
+	if err := render.Bind(r, location); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if location.CountryCode != countryCode {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("location country code mismatch")))
+		return
+	}
+	if location.PartyId != partyID {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("location party id mismatch")))
+		return
+	}
+	if location.Id != locationID {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("location id mismatch")))
+		return
+	}
+
+	s.setClientOwnedLocation(w, r, location)
 }
 
 func (s *Server) GetClientOwnedEvse(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, params GetClientOwnedEvseParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseEvse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          evse,
+	})
 }
 
 func (s *Server) PatchClientOwnedEvse(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, params PatchClientOwnedEvseParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	patch, ok := decodePatch(w, r)
+	if !ok {
+		return
+	}
+
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	if err := applyPatch(evse, patch); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	location.LastUpdated = evse.LastUpdated
+
+	s.setClientOwnedLocation(w, r, location)
 }
 
 func (s *Server) PutClientOwnedEvse(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, params PutClientOwnedEvseParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	evse := new(Evse)
+	if err := render.Bind(r, evse); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if evse.Uid != evseUID {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("evse uid mismatch")))
+		return
+	}
+
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	if existing := findEvse(location, evseUID); existing != nil {
+		*existing = *evse
+	} else {
+		evses := append(derefEvses(location.Evses), *evse)
+		location.Evses = &evses
+	}
+	location.LastUpdated = evse.LastUpdated
+
+	s.setClientOwnedLocation(w, r, location)
 }
 
 func (s *Server) GetClientOwnedConnector(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, connectorID string, params GetClientOwnedConnectorParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	connector := findConnector(evse, connectorID)
+	if connector == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseConnector{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          connector,
+	})
 }
 
 func (s *Server) PatchClientOwnedConnector(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, connectorID string, params PatchClientOwnedConnectorParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	patch, ok := decodePatch(w, r)
+	if !ok {
+		return
+	}
+
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	connector := findConnector(evse, connectorID)
+	if connector == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	if err := applyPatch(connector, patch); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	evse.LastUpdated = connector.LastUpdated
+	location.LastUpdated = connector.LastUpdated
+
+	s.setClientOwnedLocation(w, r, location)
 }
 
 func (s *Server) PutClientOwnedConnector(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, locationID string, evseUID string, connectorID string, params PutClientOwnedConnectorParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	connector := new(Connector)
+	if err := render.Bind(r, connector); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if connector.Id != connectorID {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("connector id mismatch")))
+		return
+	}
+
+	location, ok := s.lookupClientOwnedLocation(w, r, countryCode, partyID, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	if existing := findConnector(evse, connectorID); existing != nil {
+		*existing = *connector
+	} else {
+		evse.Connectors = append(evse.Connectors, *connector)
+	}
+	evse.LastUpdated = connector.LastUpdated
+	location.LastUpdated = connector.LastUpdated
+
+	s.setClientOwnedLocation(w, r, location)
+}
+
+// lookupClientOwnedLocation returns the location received from another CPO: if
+// there is no such location an error is rendered and ok is false.
+func (s *Server) lookupClientOwnedLocation(w http.ResponseWriter, r *http.Request, countryCode, partyID, locationID string) (*Location, bool) {
+	location, err := s.ocpi.GetClientOwnedLocation(r.Context(), countryCode, partyID, locationID)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return nil, false
+	}
+	if location == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return nil, false
+	}
+	return location, true
+}
+
+func (s *Server) setClientOwnedLocation(w http.ResponseWriter, r *http.Request, location *Location) {
+	err := s.ocpi.SetClientOwnedLocation(r.Context(), *location)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+	})
+}
+
+// decodePatch decodes the fields of a PATCH request: a patch must include the
+// time the object was last updated. If the patch is invalid an error is
+// rendered and ok is false.
+func decodePatch(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	var patch map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return nil, false
+	}
+	if _, ok := patch["last_updated"].(string); !ok {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("patch must include last_updated")))
+		return nil, false
+	}
+	return patch, true
+}
+
+func derefEvses(evses *[]Evse) []Evse {
+	if evses == nil {
+		return nil
+	}
+	return *evses
 }
 
 func (s *Server) GetClientOwnedSession(w http.ResponseWriter, r *http.Request, countryCode string, partyID string, sessionID string, params GetClientOwnedSessionParams) {
@@ -367,11 +665,47 @@ func (s *Server) PutClientOwnedTariff(w http.ResponseWriter, r *http.Request, co
 }
 
 func (s *Server) GetCdrsFromDataOwner(w http.ResponseWriter, r *http.Request, params GetCdrsFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	dateFrom, err := parseDateParam("date_from", params.DateFrom)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	dateTo, err := parseDateParam("date_to", params.DateTo)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	offset, limit := pageParams(params.Offset, params.Limit)
+
+	s.renderCdrs(w, r, dateFrom, dateTo, offset, limit)
 }
 
+// GetCdrPageFromDataOwner returns the page of CDRs that starts at the offset
+// given by the uid.
 func (s *Server) GetCdrPageFromDataOwner(w http.ResponseWriter, r *http.Request, uid string, params GetCdrPageFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	offset, err := strconv.Atoi(uid)
+	if err != nil || offset < 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid page: %s", uid)))
+		return
+	}
+
+	s.renderCdrs(w, r, nil, nil, offset, maxPageLimit)
+}
+
+func (s *Server) renderCdrs(w http.ResponseWriter, r *http.Request, dateFrom, dateTo *time.Time, offset, limit int) {
+	cdrs, total, err := s.ocpi.ListCdrs(r.Context(), dateFrom, dateTo, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	setPaginationHeaders(w, r, "/ocpi/sender/2.2/cdrs", offset, limit, total)
+	_ = render.Render(w, r, OcpiResponseCDRList{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          &cdrs,
+	})
 }
 
 func (s *Server) PostAsyncResponse(w http.ResponseWriter, r *http.Request, command PostAsyncResponseParamsCommand, uid string, params PostAsyncResponseParams) {
@@ -379,31 +713,163 @@ func (s *Server) PostAsyncResponse(w http.ResponseWriter, r *http.Request, comma
 }
 
 func (s *Server) GetLocationListFromDataOwner(w http.ResponseWriter, r *http.Request, params GetLocationListFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	dateFrom, err := parseDateParam("date_from", params.DateFrom)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	dateTo, err := parseDateParam("date_to", params.DateTo)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	offset, limit := pageParams(params.Offset, params.Limit)
+
+	s.renderLocations(w, r, dateFrom, dateTo, offset, limit)
 }
 
+// GetLocationPageFromDataOwner returns the page of locations that starts at the
+// offset given by the uid.
 func (s *Server) GetLocationPageFromDataOwner(w http.ResponseWriter, r *http.Request, uid string, params GetLocationPageFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	offset, err := strconv.Atoi(uid)
+	if err != nil || offset < 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid page: %s", uid)))
+		return
+	}
+
+	s.renderLocations(w, r, nil, nil, offset, maxPageLimit)
+}
+
+func (s *Server) renderLocations(w http.ResponseWriter, r *http.Request, dateFrom, dateTo *time.Time, offset, limit int) {
+	locations, total, err := s.ocpi.ListLocations(r.Context(), dateFrom, dateTo, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	setPaginationHeaders(w, r, "/ocpi/sender/2.2/locations", offset, limit, total)
+	_ = render.Render(w, r, OcpiResponseLocationList{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          &locations,
+	})
 }
 
 func (s *Server) GetLocationObjectFromDataOwner(w http.ResponseWriter, r *http.Request, locationID string, params GetLocationObjectFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, ok := s.lookupLocation(w, r, locationID)
+	if !ok {
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseLocation{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          location,
+	})
 }
 
 func (s *Server) GetEvseObjectFromDataOwner(w http.ResponseWriter, r *http.Request, locationID string, evseUID string, params GetEvseObjectFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, ok := s.lookupLocation(w, r, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseEvse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          evse,
+	})
 }
 
 func (s *Server) GetConnectorObjectFromDataOwner(w http.ResponseWriter, r *http.Request, locationID string, evseUID string, connectorID string, params GetConnectorObjectFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	location, ok := s.lookupLocation(w, r, locationID)
+	if !ok {
+		return
+	}
+	evse := findEvse(location, evseUID)
+	if evse == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+	connector := findConnector(evse, connectorID)
+	if connector == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseConnector{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          connector,
+	})
+}
+
+// lookupLocation returns the CPO's location: if there is no such location an
+// error is rendered and ok is false.
+func (s *Server) lookupLocation(w http.ResponseWriter, r *http.Request, locationID string) (*Location, bool) {
+	location, err := s.ocpi.GetLocation(r.Context(), locationID)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return nil, false
+	}
+	if location == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return nil, false
+	}
+	return location, true
 }
 
 func (s *Server) GetSessionsFromDataOwner(w http.ResponseWriter, r *http.Request, params GetSessionsFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	dateFrom, err := parseDateParam("date_from", params.DateFrom)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	dateTo, err := parseDateParam("date_to", params.DateTo)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	offset, limit := pageParams(params.Offset, params.Limit)
+
+	s.renderSessions(w, r, dateFrom, dateTo, offset, limit)
 }
 
+// GetSessionsPageFromDataOwner returns the page of sessions that starts at the
+// offset given by the uid.
 func (s *Server) GetSessionsPageFromDataOwner(w http.ResponseWriter, r *http.Request, uid string, params GetSessionsPageFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	offset, err := strconv.Atoi(uid)
+	if err != nil || offset < 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid page: %s", uid)))
+		return
+	}
+
+	s.renderSessions(w, r, nil, nil, offset, maxPageLimit)
+}
+
+func (s *Server) renderSessions(w http.ResponseWriter, r *http.Request, dateFrom, dateTo *time.Time, offset, limit int) {
+	sessions, total, err := s.ocpi.ListSessions(r.Context(), dateFrom, dateTo, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	setPaginationHeaders(w, r, "/ocpi/sender/2.2/sessions", offset, limit, total)
+	_ = render.Render(w, r, OcpiResponseSessionList{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          &sessions,
+	})
 }
 
 func (s *Server) PutChargingPreferences(w http.ResponseWriter, r *http.Request, sessionID string, params PutChargingPreferencesParams) {
@@ -411,11 +877,47 @@ func (s *Server) PutChargingPreferences(w http.ResponseWriter, r *http.Request,
 }
 
 func (s *Server) GetTariffsFromDataOwner(w http.ResponseWriter, r *http.Request, params GetTariffsFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	dateFrom, err := parseDateParam("date_from", params.DateFrom)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	dateTo, err := parseDateParam("date_to", params.DateTo)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	offset, limit := pageParams(params.Offset, params.Limit)
+
+	s.renderTariffs(w, r, dateFrom, dateTo, offset, limit)
 }
 
+// GetTariffsPageFromDataOwner returns the page of tariffs that starts at the
+// offset given by the uid.
 func (s *Server) GetTariffsPageFromDataOwner(w http.ResponseWriter, r *http.Request, uid string, params GetTariffsPageFromDataOwnerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	offset, err := strconv.Atoi(uid)
+	if err != nil || offset < 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid page: %s", uid)))
+		return
+	}
+
+	s.renderTariffs(w, r, nil, nil, offset, maxPageLimit)
+}
+
+func (s *Server) renderTariffs(w http.ResponseWriter, r *http.Request, dateFrom, dateTo *time.Time, offset, limit int) {
+	tariffs, total, err := s.ocpi.ListTariffs(r.Context(), dateFrom, dateTo, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	setPaginationHeaders(w, r, "/ocpi/sender/2.2/tariffs", offset, limit, total)
+	_ = render.Render(w, r, OcpiResponseTariffList{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          &tariffs,
+	})
 }
 
 func (s *Server) GetTokensFromDataOwner(w http.ResponseWriter, r *http.Request, params GetTokensFromDataOwnerParams) {
@@ -429,15 +931,3 @@ func (s *Server) GetTokensPageFromDataOwner(w http.ResponseWriter, r *http.Reque
 func (s *Server) PostRealTimeTokenAuthorization(w http.ResponseWriter, r *http.Request, tokenUID string, params PostRealTimeTokenAuthorizationParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
-
-func extractChargeStationId(evseId string) (string, error) {
-	pattern := `^[a-zA-Z]{5}E([a-zA-Z0-9]+)?$`
-	regex := regexp.MustCompile(pattern)
-	match := regex.FindStringSubmatch(evseId)
-	if len(match) >= 2 {
-		chargePointID := match[1]
-		return chargePointID, nil
-	} else {
-		return "", fmt.Errorf("invalid EVSE ID format, could not extract charge point ID: %s", evseId)
-	}
-}
@@ -9,12 +9,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/zynka-tech/zynka-csms/manager/handlers"
-	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	"github.com/zynka-tech/zynka-csms/manager/ocpi"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
-	"github.com/zynka-tech/zynka-csms/manager/transport"
 	"io"
 	"k8s.io/utils/clock"
 	fakeclock "k8s.io/utils/clock/testing"
@@ -33,9 +30,8 @@ func setupHandler(t *testing.T) (http.Handler, store.Engine, time.Time) {
 	require.NoError(t, err)
 
 	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
-	v16CallMaker := newNoopV16CallMaker()
 	now := time.Now().UTC()
-	server, err := ocpi.NewServer(ocpiApi, fakeclock.NewFakePassiveClock(now), v16CallMaker)
+	server, err := ocpi.NewServer(ocpiApi, fakeclock.NewFakePassiveClock(now))
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -97,11 +93,41 @@ func TestServerGetVersion(t *testing.T) {
 					Url:        "/ocpi/receiver/2.2/commands",
 					Role:       ocpi.RECEIVER,
 				},
+				{
+					Identifier: "chargingprofiles",
+					Url:        "/ocpi/2.2/receiver/chargingprofiles",
+					Role:       ocpi.RECEIVER,
+				},
 				{
 					Identifier: "tokens",
 					Url:        "/ocpi/receiver/2.2/tokens/",
 					Role:       ocpi.RECEIVER,
 				},
+				{
+					Identifier: "locations",
+					Url:        "/ocpi/sender/2.2/locations",
+					Role:       ocpi.SENDER,
+				},
+				{
+					Identifier: "locations",
+					Url:        "/ocpi/receiver/2.2/locations",
+					Role:       ocpi.RECEIVER,
+				},
+				{
+					Identifier: "sessions",
+					Url:        "/ocpi/sender/2.2/sessions",
+					Role:       ocpi.SENDER,
+				},
+				{
+					Identifier: "cdrs",
+					Url:        "/ocpi/sender/2.2/cdrs",
+					Role:       ocpi.SENDER,
+				},
+				{
+					Identifier: "tariffs",
+					Url:        "/ocpi/sender/2.2/tariffs",
+					Role:       ocpi.SENDER,
+				},
 			},
 			Version: "2.2",
 		},
@@ -276,25 +302,18 @@ func TestServerPatchClientOwnedToken(t *testing.T) {
 }
 
 func TestPostStartSession(t *testing.T) {
-	handler, engine, _ := setupHandler(t)
-
-	err := engine.SetToken(context.Background(), &store.Token{
-		CountryCode: "GB",
-		PartyId:     "TWK",
-		Type:        "RFID",
-		Uid:         "DEADBEEF",
-		ContractId:  "GBTWKTWTW000018",
-		Issuer:      "Zynka-tech",
-		Valid:       true,
-		CacheMode:   "ALWAYS",
-	})
+	callMaker := &fakeSyncCallMaker{response: `{"status":"Accepted"}`}
+	ocpiApi, _, responseUrl, results := setupCommandOcpi(t, "1.6", callMaker)
+	server, err := ocpi.NewServer(ocpiApi, fakeclock.NewFakePassiveClock(time.Now()))
 	require.NoError(t, err)
+	handler := chi.NewRouter()
+	handler.Mount("/", ocpi.Handler(server))
 
 	req := httptest.NewRequest(http.MethodPost, "/ocpi/receiver/2.2/commands/START_SESSION",
 		strings.NewReader(`{
-			"response_url": "https://example.com/ocpi/receiver/2.2/commands/START_SESSION/12345",
-			"evse_uid": "BEBECE041503001",
-			"connector_id": "2",
+			"response_url": "`+responseUrl+`",
+			"evse_uid": "GBTWKEcs001-2",
+			"connector_id": "1",
 			"token": {	
 				"type": "APP_USER",
 				"uid": "DEADBEEF",
@@ -329,11 +348,389 @@ func TestPostStartSession(t *testing.T) {
 	t.Logf("%s", string(b))
 	require.NotNilf(t, ocpiResponseCommandResponse.Data, "ocpiResponseCommandResponse.Data should not be nil")
 	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, ocpiResponseCommandResponse.Data.Result)
+	assert.Equal(t, ocpi.CommandResultResultACCEPTED, waitForCommandResult(t, results).Result)
+}
+
+func TestPostStopSessionForUnknownSession(t *testing.T) {
+	handler, _, _ := setupHandler(t)
+
+	req := newOcpiRequest(http.MethodPost, "/ocpi/receiver/2.2/commands/STOP_SESSION",
+		strings.NewReader(`{"response_url":"https://example.com/ocpi/receiver/2.2/commands/STOP_SESSION/12345","session_id":"tx001"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var ocpiResponseCommandResponse ocpi.OcpiResponseCommandResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ocpiResponseCommandResponse))
+	require.NotNil(t, ocpiResponseCommandResponse.Data)
+	assert.Equal(t, ocpi.CommandResponseResultUNKNOWNSESSION, ocpiResponseCommandResponse.Data.Result)
+}
+
+func TestPutChargingProfileForUnknownSession(t *testing.T) {
+	handler, _, _ := setupHandler(t)
+
+	req := newOcpiRequest(http.MethodPut, "/ocpi/2.2/receiver/chargingprofiles/tx001",
+		strings.NewReader(`{
+			"response_url":"https://example.com/ocpi/2.2/sender/chargingprofiles/result/12345",
+			"charging_profile":{"charging_rate_unit":"A","charging_profile_period":[{"start_period":0,"limit":16}]}
+		}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var ocpiResponseChargingProfileResponse ocpi.OcpiResponseChargingProfileResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ocpiResponseChargingProfileResponse))
+	require.NotNil(t, ocpiResponseChargingProfileResponse.Data)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultUNKNOWNSESSION, ocpiResponseChargingProfileResponse.Data.Result)
+}
+
+func TestServerGetSessions(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	for _, id := range []string{"tx001", "tx002"} {
+		err := engine.CreateTransaction(context.Background(), "cs001", id, 0, "DEADBEEF", "ISO14443",
+			[]store.MeterValue{{Timestamp: "2024-01-01T10:00:00Z"}}, 0, false)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/sender/2.2/sessions?date_from=2024-01-01T00:00:00Z&limit=1", nil)
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "TWK")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "2", resp.Header.Get("X-Total-Count"))
+	assert.Equal(t, "1", resp.Header.Get("X-Limit"))
+	assert.Equal(t, `<http://example.com/ocpi/sender/2.2/sessions?date_from=2024-01-01T00%3A00%3A00Z&limit=1&offset=1>; rel="next"`,
+		resp.Header.Get("Link"))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseSessionList
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	require.Len(t, *got.Data, 1)
+	assert.Equal(t, "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", (*got.Data)[0].Id)
+}
+
+func TestServerGetCdrs(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	lastUpdated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"cdr001", "cdr002"} {
+		err := engine.CreateCdr(context.Background(), &store.Cdr{
+			Id:            id,
+			CountryCode:   "GB",
+			PartyId:       "TWK",
+			TransactionId: "tx" + id,
+			StartDateTime: lastUpdated.Add(-time.Hour),
+			EndDateTime:   lastUpdated,
+			AuthMethod:    "AUTH_REQUEST",
+			Currency:      "EUR",
+			TotalEnergy:   7.5,
+			TotalTime:     1,
+			LastUpdated:   lastUpdated.Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/sender/2.2/cdrs?limit=1", nil)
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "TWK")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "2", resp.Header.Get("X-Total-Count"))
+	assert.Equal(t, "1", resp.Header.Get("X-Limit"))
+	assert.Equal(t, `<http://example.com/ocpi/sender/2.2/cdrs?limit=1&offset=1>; rel="next"`, resp.Header.Get("Link"))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseCDRList
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	require.Len(t, *got.Data, 1)
+	cdr := (*got.Data)[0]
+	assert.Equal(t, "cdr001", cdr.Id)
+	assert.Equal(t, "2024-01-01T12:00:00Z", cdr.EndDateTime)
+	assert.Equal(t, float32(7.5), cdr.TotalEnergy)
+	assert.Equal(t, "2024-01-01T12:00:00Z", cdr.LastUpdated)
+}
+
+func TestServerGetCdrsWithInvalidDate(t *testing.T) {
+	handler, _, _ := setupHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/sender/2.2/cdrs?date_from=yesterday", nil)
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "TWK")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestServerGetTariffs(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	lastUpdated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"tariff001", "tariff002"} {
+		err := engine.SetTariff(context.Background(), &store.Tariff{
+			Id:          id,
+			CountryCode: "GB",
+			PartyId:     "TWK",
+			Currency:    "EUR",
+			Elements: []store.TariffElement{
+				{
+					PriceComponents: []store.PriceComponent{
+						{Type: "ENERGY", Price: 0.25, StepSize: 1},
+					},
+				},
+			},
+			LastUpdated: lastUpdated.Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/sender/2.2/tariffs?date_from=2024-01-01T12:01:00Z", nil)
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "TWK")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "1", resp.Header.Get("X-Total-Count"))
+	assert.Empty(t, resp.Header.Get("Link"))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseTariffList
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	require.Len(t, *got.Data, 1)
+	tariff := (*got.Data)[0]
+	assert.Equal(t, "tariff002", tariff.Id)
+	assert.Equal(t, "EUR", tariff.Currency)
+	require.Len(t, tariff.Elements, 1)
+	assert.Equal(t, ocpi.PriceComponentTypeENERGY, tariff.Elements[0].PriceComponents[0].Type)
+	assert.Equal(t, "2024-01-01T12:01:00Z", tariff.LastUpdated)
+}
+
+func newOcpiRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "TWK")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	return req
+}
+
+func TestServerGetLocations(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	for _, id := range []string{"loc001", "loc002"} {
+		err := engine.SetLocation(context.Background(), &store.Location{
+			Id:      id,
+			Address: "F.Rooseveltlaan 3A",
+			City:    "Gent",
+			Country: "BEL",
+			Evses: &[]store.Evse{
+				{
+					Uid:    "GBTWKEcs001",
+					Status: "AVAILABLE",
+					Connectors: []store.Connector{
+						{Id: "1", Standard: "IEC_62196_T2", Format: "SOCKET", PowerType: "AC_3_PHASE"},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/sender/2.2/locations?limit=1", nil))
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "2", resp.Header.Get("X-Total-Count"))
+	assert.Equal(t, `<http://example.com/ocpi/sender/2.2/locations?limit=1&offset=1>; rel="next"`,
+		resp.Header.Get("Link"))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseLocationList
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	require.Len(t, *got.Data, 1)
+	location := (*got.Data)[0]
+	assert.Equal(t, "loc001", location.Id)
+	assert.Equal(t, "GB", location.CountryCode)
+	assert.Equal(t, "TWK", location.PartyId)
+	require.NotNil(t, location.Evses)
+	require.Len(t, *location.Evses, 1)
+	assert.Equal(t, ocpi.EvseStatusAVAILABLE, (*location.Evses)[0].Status)
+	assert.Equal(t, location.LastUpdated, (*location.Evses)[0].LastUpdated)
 }
 
-func newNoopV16CallMaker() *handlers.OcppCallMaker {
-	emitter := transport.EmitterFunc(func(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
-		return nil
+func TestServerGetLocationObjects(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	err := engine.SetLocation(context.Background(), &store.Location{
+		Id: "loc001",
+		Evses: &[]store.Evse{
+			{
+				Uid: "GBTWKEcs001",
+				Connectors: []store.Connector{
+					{Id: "1", Standard: "IEC_62196_T2", Format: "SOCKET", PowerType: "AC_3_PHASE"},
+				},
+			},
+		},
 	})
-	return ocpp16.NewCallMaker(emitter)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/sender/2.2/locations/loc001/GBTWKEcs001", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var evse ocpi.OcpiResponseEvse
+	err = json.NewDecoder(w.Result().Body).Decode(&evse)
+	require.NoError(t, err)
+	require.NotNil(t, evse.Data)
+	assert.Equal(t, "GBTWKEcs001", evse.Data.Uid)
+	assert.Equal(t, ocpi.EvseStatusUNKNOWN, evse.Data.Status)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/sender/2.2/locations/loc001/GBTWKEcs001/1", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var connector ocpi.OcpiResponseConnector
+	err = json.NewDecoder(w.Result().Body).Decode(&connector)
+	require.NoError(t, err)
+	require.NotNil(t, connector.Data)
+	assert.Equal(t, ocpi.ConnectorStandardIEC62196T2, connector.Data.Standard)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/sender/2.2/locations/loc001/GBTWKEcs002", nil))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/sender/2.2/locations/loc002", nil))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestServerPutAndPatchClientOwnedLocation(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	location := ocpi.Location{
+		Id:          "loc001",
+		CountryCode: "NL",
+		PartyId:     "ABC",
+		Address:     "Stationsplein 1",
+		City:        "Utrecht",
+		Country:     "NLD",
+		Coordinates: ocpi.GeoLocation{Latitude: "52.089444", Longitude: "5.110278"},
+		Evses: &[]ocpi.Evse{
+			{
+				Uid:    "NLABCE001",
+				Status: ocpi.EvseStatusAVAILABLE,
+				Connectors: []ocpi.Connector{
+					{
+						Id:          "1",
+						Standard:    ocpi.ConnectorStandardIEC62196T2,
+						Format:      ocpi.ConnectorFormatSOCKET,
+						PowerType:   ocpi.ConnectorPowerTypeAC3PHASE,
+						LastUpdated: "2024-01-01T10:00:00Z",
+					},
+				},
+				LastUpdated: "2024-01-01T10:00:00Z",
+			},
+		},
+		Publish:     true,
+		LastUpdated: "2024-01-01T10:00:00Z",
+	}
+	b, err := json.Marshal(location)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodPut, "/ocpi/receiver/2.2/locations/NL/ABC/loc001", bytes.NewReader(b)))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodPatch, "/ocpi/receiver/2.2/locations/NL/ABC/loc001/NLABCE001",
+		strings.NewReader(`{"status":"CHARGING","last_updated":"2024-01-01T11:00:00Z"}`)))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	got, err := engine.LookupClientOwnedLocation(context.Background(), "NL", "ABC", "loc001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Utrecht", got.City)
+	assert.Equal(t, "2024-01-01T11:00:00Z", got.LastUpdated)
+	require.NotNil(t, got.Evses)
+	require.Len(t, *got.Evses, 1)
+	assert.Equal(t, "CHARGING", (*got.Evses)[0].Status)
+	assert.Equal(t, "2024-01-01T11:00:00Z", (*got.Evses)[0].LastUpdated)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodGet, "/ocpi/receiver/2.2/locations/NL/ABC/loc001/NLABCE001/1", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var connector ocpi.OcpiResponseConnector
+	err = json.NewDecoder(w.Result().Body).Decode(&connector)
+	require.NoError(t, err)
+	require.NotNil(t, connector.Data)
+	assert.Equal(t, "1", connector.Data.Id)
+}
+
+func TestServerPutClientOwnedLocationWithMismatchedId(t *testing.T) {
+	handler, _, _ := setupHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodPut, "/ocpi/receiver/2.2/locations/NL/ABC/loc001",
+		strings.NewReader(`{"id":"loc002","country_code":"NL","party_id":"ABC","address":"a","city":"c","country":"NLD",
+		"coordinates":{"latitude":"52.089444","longitude":"5.110278"},"publish":true,"time_zone":"Europe/Amsterdam",
+		"last_updated":"2024-01-01T10:00:00Z"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestServerPatchClientOwnedLocationWithoutLastUpdated(t *testing.T) {
+	handler, _, _ := setupHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newOcpiRequest(http.MethodPatch, "/ocpi/receiver/2.2/locations/NL/ABC/loc001",
+		strings.NewReader(`{"name":"Utrecht Centraal"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 }
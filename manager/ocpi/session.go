@@ -0,0 +1,468 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// sessionCurrency is the currency of the sessions: the tariff service does not
+// have a currency of its own
+const sessionCurrency = "EUR"
+
+// evseLocation identifies the OCPI location, EVSE and connector of a charge station
+type evseLocation struct {
+	locationId  string
+	evseUid     string
+	connectorId string
+	// evseUids are the uids of all the EVSEs of the charge station at the
+	// location: it is nil if the charge station does not belong to a
+	// registered location
+	evseUids []string
+	// location, evse and connector are nil if the charge station does not
+	// belong to a registered location
+	location  *store.Location
+	evse      *store.Evse
+	connector *store.Connector
+}
+
+func (o *OCPI) PushSession(ctx context.Context, transaction *store.Transaction) error {
+	session, err := o.newSession(ctx, transaction)
+	if err != nil {
+		return err
+	}
+
+	return o.pushSessionToParties(ctx, http.MethodPut, session.Id, session)
+}
+
+func (o *OCPI) PushSessionUpdate(ctx context.Context, transaction *store.Transaction) error {
+	session, err := o.newSession(ctx, transaction)
+	if err != nil {
+		return err
+	}
+
+	return o.pushSessionToParties(ctx, http.MethodPatch, session.Id, map[string]any{
+		"kwh":              session.Kwh,
+		"charging_periods": session.ChargingPeriods,
+		"status":           session.Status,
+		"last_updated":     session.LastUpdated,
+	})
+}
+
+func (o *OCPI) ListSessions(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Session, int, error) {
+	transactions, total, err := o.store.ListTransactions(ctx, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the tokens are looked up once for the page as a driver usually has
+	// several sessions
+	tokens := make(map[string]*store.Token)
+	sessions := make([]Session, 0, len(transactions))
+	for _, transaction := range transactions {
+		evse, err := o.lookupChargeStationEvse(ctx, transaction.ChargeStationId)
+		if err != nil {
+			return nil, 0, err
+		}
+		session, err := o.toSession(ctx, transaction, evse, tokens)
+		if err != nil {
+			return nil, 0, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, total, nil
+}
+
+func (o *OCPI) newSession(ctx context.Context, transaction *store.Transaction) (*Session, error) {
+	evse, err := o.lookupChargeStationEvse(ctx, transaction.ChargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	return o.toSession(ctx, transaction, evse, nil)
+}
+
+// newSessionId returns the id of the session for the transaction. As for the CDR
+// id, the id is derived from the charge station and transaction ids as the
+// transaction ids of different charge stations may be the same.
+func newSessionId(chargeStationId, transactionId string) string {
+	name := fmt.Sprintf("urn:session:%s:%s", chargeStationId, transactionId)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+// toSession converts a transaction at the EVSE into an OCPI session. The
+// session is last updated when the transaction was last updated in the store.
+func (o *OCPI) toSession(ctx context.Context, transaction *store.Transaction, evse evseLocation, tokens map[string]*store.Token) (*Session, error) {
+	cdrToken, authMethod, err := o.cdrToken(ctx, transaction, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := newEnergyUsage(transaction.MeterValues)
+	now := o.clock.Now().UTC()
+	startDateTime, endDateTime := now, now
+	if !usage.start.IsZero() {
+		startDateTime, endDateTime = usage.start, usage.end
+	}
+	lastUpdated := endDateTime
+	if !transaction.LastUpdated.IsZero() {
+		lastUpdated = transaction.LastUpdated
+	}
+
+	session := &Session{
+		Id:              newSessionId(transaction.ChargeStationId, transaction.TransactionId),
+		CountryCode:     o.countryCode,
+		PartyId:         o.partyId,
+		StartDateTime:   startDateTime.Format(time.RFC3339),
+		Kwh:             float32(usage.totalWh / 1000),
+		CdrToken:        *cdrToken,
+		AuthMethod:      authMethod,
+		LocationId:      evse.locationId,
+		EvseUid:         evse.evseUid,
+		ConnectorId:     evse.connectorId,
+		Currency:        sessionCurrency,
+		ChargingPeriods: usage.chargingPeriods(),
+		Status:          SessionStatusACTIVE,
+		LastUpdated:     lastUpdated.Format(time.RFC3339),
+	}
+	if transaction.EndedSeqNo != 0 {
+		session.Status = SessionStatusCOMPLETED
+		endDateTime := endDateTime.Format(time.RFC3339)
+		session.EndDateTime = &endDateTime
+	}
+
+	return session, nil
+}
+
+// chargeStationEvse returns the EVSE registered for the charge station: if there
+// isn't one, the charge station id is used as the location id.
+func (o *OCPI) chargeStationEvse(chargeStationId string, evses map[string]evseLocation) evseLocation {
+	evse, ok := evses[chargeStationId]
+	if !ok {
+		evse = evseLocation{
+			locationId:  chargeStationId,
+			evseUid:     fmt.Sprintf("%s%sE%s", o.countryCode, o.partyId, chargeStationId),
+			connectorId: "1",
+		}
+	}
+	return evse
+}
+
+// cdrToken returns the token used for the transaction: tokens that were
+// received from an eMSP are whitelisted, any other token must have been
+// authorized by the CSMS. The tokens that have been looked up are recorded in
+// tokens, if it isn't nil, so that they are only looked up once.
+func (o *OCPI) cdrToken(ctx context.Context, transaction *store.Transaction, tokens map[string]*store.Token) (*CdrToken, SessionAuthMethod, error) {
+	if transaction.IdToken != "" {
+		tok, found := tokens[transaction.IdToken]
+		if !found {
+			var err error
+			tok, err = o.store.LookupToken(ctx, transaction.IdToken)
+			if err != nil {
+				return nil, "", err
+			}
+			if tokens != nil {
+				tokens[transaction.IdToken] = tok
+			}
+		}
+		if tok != nil {
+			return &CdrToken{
+				ContractId: tok.ContractId,
+				Type:       CdrTokenType(tok.Type),
+				Uid:        tok.Uid,
+			}, SessionAuthMethodWHITELIST, nil
+		}
+	}
+
+	tokenType := CdrTokenTypeOTHER
+	if transaction.TokenType == "ISO14443" || transaction.TokenType == "ISO15693" {
+		tokenType = CdrTokenTypeRFID
+	}
+	return &CdrToken{
+		ContractId: transaction.IdToken,
+		Type:       tokenType,
+		Uid:        transaction.IdToken,
+	}, SessionAuthMethodAUTHREQUEST, nil
+}
+
+// evseLocationsTTL is how long the EVSEs of the registered locations are
+// cached for: the EVSE of a charge station can only be found by mapping the EVSEs
+// of every location so they are not mapped for every session and CDR.
+const evseLocationsTTL = time.Minute
+
+// lookupChargeStationEvse returns the EVSE registered for the charge station
+// from the cached EVSEs of the registered locations, see chargeStationEvse.
+func (o *OCPI) lookupChargeStationEvse(ctx context.Context, chargeStationId string) (evseLocation, error) {
+	o.evseLocationsMutex.Lock()
+	defer o.evseLocationsMutex.Unlock()
+
+	now := o.clock.Now()
+	if o.evseLocations == nil || !now.Before(o.evseLocationsExpiry) {
+		evses, err := o.listEvseLocations(ctx)
+		if err != nil {
+			return evseLocation{}, err
+		}
+		o.evseLocations = evses
+		o.evseLocationsExpiry = now.Add(evseLocationsTTL)
+	}
+	return o.chargeStationEvse(chargeStationId, o.evseLocations), nil
+}
+
+// clearEvseLocations clears the cached EVSEs of the registered locations so
+// that a change to a location is seen by the next lookup.
+func (o *OCPI) clearEvseLocations() {
+	o.evseLocationsMutex.Lock()
+	defer o.evseLocationsMutex.Unlock()
+	o.evseLocations = nil
+}
+
+// listEvseLocations returns the EVSEs of the registered locations indexed by
+// the id of the charge station that they belong to.
+func (o *OCPI) listEvseLocations(ctx context.Context) (map[string]evseLocation, error) {
+	evses := make(map[string]evseLocation)
+	err := o.forEachLocation(ctx, func(location *store.Location) error {
+		if location.Evses == nil {
+			return nil
+		}
+		for i, evse := range *location.Evses {
+			mapping, err := o.evseMapping.MapEvse(ctx, location.Id, &evse)
+			if err != nil {
+				return err
+			}
+			if mapping == nil {
+				continue
+			}
+			chargeStationId := mapping.ChargeStationId
+			evseLoc := evseLocation{
+				locationId:  location.Id,
+				evseUid:     evse.Uid,
+				connectorId: "1",
+				evseUids:    []string{evse.Uid},
+				location:    location,
+				evse:        &(*location.Evses)[i],
+			}
+			if existing, ok := evses[chargeStationId]; ok && existing.locationId == location.Id {
+				evseLoc.evseUids = append(existing.evseUids, evse.Uid)
+			}
+			if len(evse.Connectors) > 0 {
+				evseLoc.connectorId = evse.Connectors[0].Id
+				evseLoc.connector = &evse.Connectors[0]
+			}
+			evses[chargeStationId] = evseLoc
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evses, nil
+}
+
+func (o *OCPI) pushSessionToParties(ctx context.Context, method, sessionId string, body any) error {
+	parties, err := o.store.ListPartyDetailsForRole(ctx, "EMSP")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, party := range parties {
+		sessionsUrl, err := o.getReceiverUrl(ctx, party, "sessions")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = o.sendToParty(ctx, method, fmt.Sprintf("%s/%s", sessionsUrl, sessionId), party, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pushing session to %s/%s: %w", party.CountryCode, party.PartyId, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendToParty sends the body, if any, as JSON to the party: the party must
+// accept it with a 200 or 201 status code.
+func (o *OCPI) sendToParty(ctx context.Context, method, url string, party *store.OcpiParty, body any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	o.setRequestHeaders(ctx, req, party.Token, party.CountryCode, party.PartyId)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// energyReading is a reading of the energy register of the meter in Wh
+type energyReading struct {
+	timestamp time.Time
+	wh        float64
+}
+
+// energyUsage is the energy delivered during a transaction
+type energyUsage struct {
+	// readings of the energy register ordered by time
+	readings []energyReading
+	// totalWh is the energy delivered in Wh
+	totalWh float64
+	// endTotal is true if the total was reported at the end of the transaction
+	endTotal bool
+	// start and end are the times of the first and last meter values
+	start, end time.Time
+}
+
+// newEnergyUsage determines the energy delivered from the meter values of a
+// transaction. As for the tariff service, an outlet energy reading in the
+// Transaction.End context holds the energy delivered by the whole transaction.
+// Otherwise, the energy delivered is the difference between the first and last
+// readings of the energy register.
+func newEnergyUsage(meterValues []store.MeterValue) energyUsage {
+	var usage energyUsage
+	for _, meterValue := range meterValues {
+		timestamp, err := time.Parse(time.RFC3339, meterValue.Timestamp)
+		if err != nil {
+			continue
+		}
+		timestamp = timestamp.UTC()
+		if usage.start.IsZero() || timestamp.Before(usage.start) {
+			usage.start = timestamp
+		}
+		if timestamp.After(usage.end) {
+			usage.end = timestamp
+		}
+
+		for _, sampledValue := range meterValue.SampledValues {
+			if !isEnergyRegister(sampledValue) {
+				continue
+			}
+			if sampledValue.Context != nil && *sampledValue.Context == "Transaction.End" &&
+				sampledValue.Location != nil && *sampledValue.Location == "Outlet" {
+				usage.totalWh = toWattHours(sampledValue)
+				usage.endTotal = true
+				continue
+			}
+			usage.readings = append(usage.readings, energyReading{
+				timestamp: timestamp,
+				wh:        toWattHours(sampledValue),
+			})
+		}
+	}
+
+	sort.SliceStable(usage.readings, func(i, j int) bool {
+		return usage.readings[i].timestamp.Before(usage.readings[j].timestamp)
+	})
+	if !usage.endTotal && len(usage.readings) > 1 {
+		usage.totalWh = usage.readings[len(usage.readings)-1].wh - usage.readings[0].wh
+	}
+	return usage
+}
+
+// energyPeriod is the energy delivered from the start of a period until the
+// start of the next one
+type energyPeriod struct {
+	start time.Time
+	wh    float64
+}
+
+// periods returns a period for each reading of the energy register that is
+// followed by another reading: the last period of a transaction that has ended
+// extends to the end of the transaction.
+func (u energyUsage) periods() []energyPeriod {
+	var periods []energyPeriod
+	for i := 0; i+1 < len(u.readings); i++ {
+		periods = append(periods, energyPeriod{
+			start: u.readings[i].timestamp,
+			wh:    u.readings[i+1].wh - u.readings[i].wh,
+		})
+	}
+	if u.endTotal {
+		switch len(u.readings) {
+		case 0:
+			periods = append(periods, energyPeriod{start: u.start, wh: u.totalWh})
+		default:
+			last := u.readings[len(u.readings)-1]
+			periods = append(periods, energyPeriod{
+				start: last.timestamp,
+				wh:    u.totalWh - (last.wh - u.readings[0].wh),
+			})
+		}
+	}
+	return periods
+}
+
+// chargingPeriods returns the OCPI charging periods with the energy delivered
+// in each period.
+func (u energyUsage) chargingPeriods() *[]ChargingPeriod {
+	chargingPeriods := []ChargingPeriod{}
+	for _, period := range u.periods() {
+		chargingPeriods = append(chargingPeriods, ChargingPeriod{
+			StartDateTime: period.start.Format(time.RFC3339),
+			Dimensions: []CdrDimension{
+				{
+					Type:   CdrDimensionTypeENERGY,
+					Volume: float32(period.wh / 1000),
+				},
+			},
+		})
+	}
+	return &chargingPeriods
+}
+
+// isEnergyRegister returns true if the sampled value is a reading of the total
+// active energy imported: the measurand defaults to the energy register. The
+// meter start of an OCPP 1.6 transaction is recorded with the MeterValue measurand.
+func isEnergyRegister(sampledValue store.SampledValue) bool {
+	if sampledValue.SignedDataOnly {
+		return false
+	}
+	if sampledValue.Measurand != nil && *sampledValue.Measurand != "Energy.Active.Import.Register" &&
+		*sampledValue.Measurand != "MeterValue" {
+		return false
+	}
+	if sampledValue.Phase != nil {
+		return false
+	}
+	return sampledValue.Location == nil || *sampledValue.Location == "Outlet"
+}
+
+func toWattHours(sampledValue store.SampledValue) float64 {
+	value := sampledValue.Value
+	if sampledValue.UnitOfMeasure != nil {
+		value *= math.Pow10(sampledValue.UnitOfMeasure.Multipler)
+		if sampledValue.UnitOfMeasure.Unit == "kWh" {
+			value *= 1000
+		}
+	}
+	return value
+}
@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+func makePtr[T any](t T) *T {
+	v := t
+	return &v
+}
+
+func energyMeterValue(timestamp string, wh float64) store.MeterValue {
+	return store.MeterValue{
+		Timestamp: timestamp,
+		SampledValues: []store.SampledValue{
+			{
+				Measurand:     makePtr("Energy.Active.Import.Register"),
+				Location:      makePtr("Outlet"),
+				UnitOfMeasure: &store.UnitOfMeasure{Unit: "Wh"},
+				Value:         wh,
+			},
+		},
+	}
+}
+
+// newReceiver starts an eMSP that has a receiver interface for the module
+// handled by handler.
+func newReceiver(module string, handler http.HandlerFunc) (*httptest.Server, func()) {
+	mux := http.NewServeMux()
+	receiverServer := httptest.NewServer(mux)
+	mux.HandleFunc("/ocpi/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[{"version":"2.2","url":"%s/ocpi/2.2"}], "status_code":1000}`, receiverServer.URL)))
+	})
+	mux.HandleFunc("/ocpi/2.2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{
+				"version":"2.2",
+				"endpoints":[{"identifier":"%s","role":"RECEIVER","url":"%s/ocpi/receiver/2.2/%s"}]},
+				"status_code":1000}`,
+			module, receiverServer.URL, module)))
+	})
+	mux.HandleFunc(fmt.Sprintf("/ocpi/receiver/2.2/%s", module), handler)
+	mux.HandleFunc(fmt.Sprintf("/ocpi/receiver/2.2/%s/", module), handler)
+	return receiverServer, receiverServer.Close
+}
+
+func setupEmspOcpi(t *testing.T, receiverUrl string) (ocpi.Api, store.Engine) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	err := ocpiApi.SetCredentials(context.Background(), "some-token-123", ocpi.Credentials{
+		Roles: []ocpi.CredentialsRole{
+			{
+				CountryCode: "GB",
+				PartyId:     "TWK",
+				Role:        ocpi.CredentialsRoleRoleEMSP,
+			},
+		},
+		Token: "some-token-456",
+		Url:   receiverUrl + "/ocpi/versions",
+	})
+	require.NoError(t, err)
+	return ocpiApi, engine
+}
+
+func TestPushSession(t *testing.T) {
+	var got ocpi.Session
+	receiverServer, closeServer := newReceiver("sessions", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/sessions/GB/TWK/c6e5ec42-7207-58c8-ab65-39f9f30c48a6", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.PushSession(context.Background(), &store.Transaction{
+		ChargeStationId: "cs001",
+		TransactionId:   "tx001",
+		IdToken:         "DEADBEEF",
+		TokenType:       "ISO14443",
+		MeterValues: []store.MeterValue{
+			energyMeterValue("2024-01-01T10:00:00Z", 1000),
+			energyMeterValue("2024-01-01T10:30:00Z", 6000),
+		},
+	})
+	require.NoError(t, err)
+
+	want := ocpi.Session{
+		Id:            "c6e5ec42-7207-58c8-ab65-39f9f30c48a6",
+		CountryCode:   "GB",
+		PartyId:       "TWK",
+		StartDateTime: "2024-01-01T10:00:00Z",
+		Kwh:           5,
+		CdrToken: ocpi.CdrToken{
+			ContractId: "DEADBEEF",
+			Type:       ocpi.CdrTokenTypeRFID,
+			Uid:        "DEADBEEF",
+		},
+		AuthMethod:  ocpi.SessionAuthMethodAUTHREQUEST,
+		LocationId:  "cs001",
+		EvseUid:     "GBTWKEcs001",
+		ConnectorId: "1",
+		Currency:    "EUR",
+		ChargingPeriods: &[]ocpi.ChargingPeriod{
+			{
+				StartDateTime: "2024-01-01T10:00:00Z",
+				Dimensions: []ocpi.CdrDimension{
+					{
+						Type:   ocpi.CdrDimensionTypeENERGY,
+						Volume: 5,
+					},
+				},
+			},
+		},
+		Status:      ocpi.SessionStatusACTIVE,
+		LastUpdated: "2024-01-01T10:30:00Z",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestPushSessionUpdate(t *testing.T) {
+	var got map[string]any
+	receiverServer, closeServer := newReceiver("sessions", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/sessions/GB/TWK/c6e5ec42-7207-58c8-ab65-39f9f30c48a6", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.PushSessionUpdate(context.Background(), &store.Transaction{
+		ChargeStationId: "cs001",
+		TransactionId:   "tx001",
+		IdToken:         "DEADBEEF",
+		TokenType:       "ISO14443",
+		MeterValues: []store.MeterValue{
+			energyMeterValue("2024-01-01T10:00:00Z", 1000),
+			energyMeterValue("2024-01-01T10:30:00Z", 3500),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"kwh", "charging_periods", "status", "last_updated"}, keys(got))
+	assert.Equal(t, 2.5, got["kwh"])
+	assert.Equal(t, "ACTIVE", got["status"])
+	assert.Equal(t, "2024-01-01T10:30:00Z", got["last_updated"])
+}
+
+func keys(m map[string]any) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func TestListSessions(t *testing.T) {
+	storeClock := clockTest.NewFakePassiveClock(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	engine := inmemory.NewStore(storeClock)
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	ctx := context.Background()
+
+	err := engine.SetLocation(ctx, &store.Location{
+		Id: "loc001",
+		Evses: &[]store.Evse{
+			{
+				Uid: "GBTWKEcs001",
+				Connectors: []store.Connector{
+					{Id: "2"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.SetToken(ctx, &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "DEADBEEF",
+		ContractId:  "GBTWKTWTW000018",
+		Issuer:      "Zynka-tech",
+		Valid:       true,
+		CacheMode:   "ALWAYS",
+	})
+	require.NoError(t, err)
+
+	endMeterValue := store.MeterValue{
+		Timestamp: "2024-01-01T11:00:00Z",
+		SampledValues: []store.SampledValue{
+			{
+				Context:       makePtr("Transaction.End"),
+				Measurand:     makePtr("Energy.Active.Import.Register"),
+				Location:      makePtr("Outlet"),
+				UnitOfMeasure: &store.UnitOfMeasure{Unit: "kWh"},
+				Value:         7.5,
+			},
+		},
+	}
+	err = engine.CreateTransaction(ctx, "cs001", "tx001", 0, "DEADBEEF", "ISO14443",
+		[]store.MeterValue{energyMeterValue("2024-01-01T10:00:00Z", 0)}, 0, false)
+	require.NoError(t, err)
+	storeClock.SetTime(time.Date(2024, 1, 1, 11, 0, 5, 0, time.UTC))
+	err = engine.EndTransaction(ctx, "cs001", "tx001", "DEADBEEF", "ISO14443",
+		[]store.MeterValue{endMeterValue}, 1)
+	require.NoError(t, err)
+	storeClock.SetTime(time.Date(2024, 1, 2, 10, 0, 5, 0, time.UTC))
+	err = engine.CreateTransaction(ctx, "cs002", "tx002", 0, "CAFEBABE", "eMAID",
+		[]store.MeterValue{energyMeterValue("2024-01-02T10:00:00Z", 0)}, 0, false)
+	require.NoError(t, err)
+
+	t.Run("all sessions", func(t *testing.T) {
+		sessions, total, err := ocpiApi.ListSessions(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, sessions, 2)
+
+		completed := sessions[0]
+		assert.Equal(t, "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", completed.Id)
+		assert.Equal(t, "loc001", completed.LocationId)
+		assert.Equal(t, "GBTWKEcs001", completed.EvseUid)
+		assert.Equal(t, "2", completed.ConnectorId)
+		assert.Equal(t, ocpi.SessionAuthMethodWHITELIST, completed.AuthMethod)
+		assert.Equal(t, "GBTWKTWTW000018", completed.CdrToken.ContractId)
+		assert.Equal(t, ocpi.SessionStatusCOMPLETED, completed.Status)
+		assert.Equal(t, float32(7.5), completed.Kwh)
+		require.NotNil(t, completed.EndDateTime)
+		assert.Equal(t, "2024-01-01T11:00:00Z", *completed.EndDateTime)
+		assert.Equal(t, "2024-01-01T11:00:05Z", completed.LastUpdated)
+
+		active := sessions[1]
+		assert.Equal(t, "3873e65a-b232-57b7-89ba-9670d165f77d", active.Id)
+		assert.Equal(t, "cs002", active.LocationId)
+		assert.Equal(t, ocpi.SessionAuthMethodAUTHREQUEST, active.AuthMethod)
+		assert.Equal(t, ocpi.CdrTokenTypeOTHER, active.CdrToken.Type)
+		assert.Equal(t, ocpi.SessionStatusACTIVE, active.Status)
+		assert.Nil(t, active.EndDateTime)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		dateFrom := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		sessions, total, err := ocpiApi.ListSessions(ctx, &dateFrom, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "3873e65a-b232-57b7-89ba-9670d165f77d", sessions[0].Id)
+
+		sessions, total, err = ocpiApi.ListSessions(ctx, nil, &dateFrom, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "c6e5ec42-7207-58c8-ab65-39f9f30c48a6", sessions[0].Id)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		sessions, total, err := ocpiApi.ListSessions(ctx, nil, nil, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "3873e65a-b232-57b7-89ba-9670d165f77d", sessions[0].Id)
+
+		sessions, total, err = ocpiApi.ListSessions(ctx, nil, nil, 2, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Empty(t, sessions)
+	})
+}
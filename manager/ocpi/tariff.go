@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// PushTariff sends the tariff to every eMSP that has a tariffs receiver
+// interface.
+func (o *OCPI) PushTariff(ctx context.Context, tariff *store.Tariff) error {
+	return o.pushTariffToParties(ctx, http.MethodPut, tariff.Id, toOcpiTariff(tariff))
+}
+
+// PushTariffDeletion informs every eMSP that has a tariffs receiver interface
+// that the tariff has been removed.
+func (o *OCPI) PushTariffDeletion(ctx context.Context, tariffId string) error {
+	return o.pushTariffToParties(ctx, http.MethodDelete, tariffId, nil)
+}
+
+func (o *OCPI) ListTariffs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]Tariff, int, error) {
+	tariffs, total, err := o.store.ListTariffs(ctx, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]Tariff, 0, len(tariffs))
+	for _, tariff := range tariffs {
+		result = append(result, toOcpiTariff(tariff))
+	}
+	return result, total, nil
+}
+
+func (o *OCPI) pushTariffToParties(ctx context.Context, method, tariffId string, body any) error {
+	parties, err := o.store.ListPartyDetailsForRole(ctx, "EMSP")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, party := range parties {
+		tariffsUrl, err := o.getReceiverUrl(ctx, party, "tariffs")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = o.sendToParty(ctx, method, fmt.Sprintf("%s/%s", tariffsUrl, tariffId), party, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pushing tariff to %s/%s: %w", party.CountryCode, party.PartyId, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func toOcpiTariff(tariff *store.Tariff) Tariff {
+	result := Tariff{
+		Id:          tariff.Id,
+		CountryCode: tariff.CountryCode,
+		PartyId:     tariff.PartyId,
+		Currency:    tariff.Currency,
+		Elements:    []TariffElement{},
+		LastUpdated: tariff.LastUpdated.Format(time.RFC3339),
+	}
+	if tariff.Type != "" {
+		tariffType := TariffType(tariff.Type)
+		result.Type = &tariffType
+	}
+	vat := tariffVat(tariff)
+	if tariff.MinPrice != nil {
+		result.MinPrice = toOcpiPrice(*tariff.MinPrice, vat)
+	}
+	if tariff.MaxPrice != nil {
+		result.MaxPrice = toOcpiPrice(*tariff.MaxPrice, vat)
+	}
+	if tariff.StartDateTime != nil {
+		startDateTime := tariff.StartDateTime.Format(time.RFC3339)
+		result.StartDateTime = &startDateTime
+	}
+	if tariff.EndDateTime != nil {
+		endDateTime := tariff.EndDateTime.Format(time.RFC3339)
+		result.EndDateTime = &endDateTime
+	}
+	for _, element := range tariff.Elements {
+		tariffElement := TariffElement{
+			PriceComponents: []PriceComponent{},
+		}
+		for _, component := range element.PriceComponents {
+			priceComponent := PriceComponent{
+				Type:     PriceComponentType(component.Type),
+				Price:    float32(component.Price),
+				StepSize: int32(component.StepSize),
+			}
+			if component.Vat != nil {
+				vat := float32(*component.Vat)
+				priceComponent.Vat = &vat
+			}
+			tariffElement.PriceComponents = append(tariffElement.PriceComponents, priceComponent)
+		}
+		if element.Restrictions != nil {
+			tariffElement.Restrictions = toOcpiTariffRestrictions(element.Restrictions)
+		}
+		result.Elements = append(result.Elements, tariffElement)
+	}
+	return result
+}
+
+// tariffVat returns the VAT percentage that applies to the whole tariff: this is
+// only known when every price component has the same VAT. It returns nil if the
+// VAT is not known.
+func tariffVat(tariff *store.Tariff) *float64 {
+	var vat *float64
+	for _, element := range tariff.Elements {
+		for _, component := range element.PriceComponents {
+			if component.Vat == nil || (vat != nil && *vat != *component.Vat) {
+				return nil
+			}
+			vat = component.Vat
+		}
+	}
+	return vat
+}
+
+// toOcpiPrice converts a price excluding VAT: the price including VAT is only
+// set when the VAT is known.
+func toOcpiPrice(exclVat float64, vat *float64) *Price {
+	price := &Price{ExclVat: float32(exclVat)}
+	if vat != nil {
+		inclVat := float32(exclVat * (1 + *vat/100))
+		price.InclVat = &inclVat
+	}
+	return price
+}
+
+func toOcpiTariffRestrictions(restrictions *store.TariffRestrictions) *TariffRestrictions {
+	optionalString := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	optionalFloat := func(f *float64) *float32 {
+		if f == nil {
+			return nil
+		}
+		v := float32(*f)
+		return &v
+	}
+	optionalInt := func(i *int) *int32 {
+		if i == nil {
+			return nil
+		}
+		v := int32(*i)
+		return &v
+	}
+
+	result := &TariffRestrictions{
+		StartTime:   optionalString(restrictions.StartTime),
+		EndTime:     optionalString(restrictions.EndTime),
+		StartDate:   optionalString(restrictions.StartDate),
+		EndDate:     optionalString(restrictions.EndDate),
+		MinKwh:      optionalFloat(restrictions.MinKwh),
+		MaxKwh:      optionalFloat(restrictions.MaxKwh),
+		MinPower:    optionalFloat(restrictions.MinPower),
+		MaxPower:    optionalFloat(restrictions.MaxPower),
+		MinDuration: optionalInt(restrictions.MinDuration),
+		MaxDuration: optionalInt(restrictions.MaxDuration),
+	}
+	if len(restrictions.DayOfWeek) > 0 {
+		days := make([]TariffRestrictionsDayOfWeek, 0, len(restrictions.DayOfWeek))
+		for _, day := range restrictions.DayOfWeek {
+			days = append(days, TariffRestrictionsDayOfWeek(day))
+		}
+		result.DayOfWeek = &days
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func TestPushTariff(t *testing.T) {
+	var got ocpi.Tariff
+	receiverServer, closeServer := newReceiver("tariffs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/tariffs/GB/TWK/tariff001", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	startDateTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := ocpiApi.PushTariff(context.Background(), &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Type:        "REGULAR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.25, Vat: makePtr(20.0), StepSize: 1},
+				},
+				Restrictions: &store.TariffRestrictions{
+					StartTime:   "07:00",
+					EndTime:     "19:00",
+					MaxKwh:      makePtr(20.0),
+					MinDuration: makePtr(600),
+					DayOfWeek:   []string{"MONDAY"},
+				},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "FLAT", Price: 1},
+				},
+			},
+		},
+		MaxPrice:      makePtr(50.0),
+		StartDateTime: &startDateTime,
+		LocationIds:   []string{"loc001"},
+		LastUpdated:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	tariffType := ocpi.TariffTypeREGULAR
+	want := ocpi.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Type:        &tariffType,
+		Elements: []ocpi.TariffElement{
+			{
+				PriceComponents: []ocpi.PriceComponent{
+					{Type: ocpi.PriceComponentTypeENERGY, Price: 0.25, Vat: makePtr(float32(20)), StepSize: 1},
+				},
+				Restrictions: &ocpi.TariffRestrictions{
+					StartTime:   makePtr("07:00"),
+					EndTime:     makePtr("19:00"),
+					MaxKwh:      makePtr(float32(20)),
+					MinDuration: makePtr(int32(600)),
+					DayOfWeek:   &[]ocpi.TariffRestrictionsDayOfWeek{ocpi.MONDAY},
+				},
+			},
+			{
+				PriceComponents: []ocpi.PriceComponent{
+					{Type: ocpi.PriceComponentTypeFLAT, Price: 1},
+				},
+			},
+		},
+		MaxPrice:      &ocpi.Price{ExclVat: 50},
+		StartDateTime: makePtr("2024-01-01T00:00:00Z"),
+		LastUpdated:   "2024-01-01T12:00:00Z",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestPushTariffWithVat(t *testing.T) {
+	var got ocpi.Tariff
+	receiverServer, closeServer := newReceiver("tariffs", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &got))
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.PushTariff(context.Background(), &store.Tariff{
+		Id:          "tariff001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.25, Vat: makePtr(20.0), StepSize: 1},
+					{Type: "FLAT", Price: 1, Vat: makePtr(20.0)},
+				},
+			},
+		},
+		MinPrice:    makePtr(5.0),
+		MaxPrice:    makePtr(50.0),
+		LastUpdated: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &ocpi.Price{ExclVat: 5, InclVat: makePtr(float32(6))}, got.MinPrice)
+	assert.Equal(t, &ocpi.Price{ExclVat: 50, InclVat: makePtr(float32(60))}, got.MaxPrice)
+}
+
+func TestPushTariffDeletion(t *testing.T) {
+	called := false
+	receiverServer, closeServer := newReceiver("tariffs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/ocpi/receiver/2.2/tariffs/GB/TWK/tariff001", r.URL.Path)
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.PushTariffDeletion(context.Background(), "tariff001")
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestPushTariffToEmspWithoutTariffsModule(t *testing.T) {
+	receiverServer, closeServer := newReceiver("sessions", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+	defer closeServer()
+
+	ocpiApi, _ := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.PushTariff(context.Background(), &store.Tariff{Id: "tariff001"})
+	assert.ErrorContains(t, err, "no tariffs endpoint for receiver found")
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+// SyncTokens pulls the tokens that have changed since the last synchronisation
+// from each eMSP that has a sender interface for the tokens module.
+func (o *OCPI) SyncTokens(ctx context.Context) error {
+	parties, err := o.store.ListPartyDetailsForRole(ctx, "EMSP")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, party := range parties {
+		err = o.syncPartyTokens(ctx, party)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("syncing tokens from %s/%s: %w", party.CountryCode, party.PartyId, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// syncNewPartyTokens pulls the tokens of the eMSP roles of a party that has
+// just registered. The party may not respond until the registration has
+// completed so the tokens are pulled in the background.
+func (o *OCPI) syncNewPartyTokens(credentials Credentials) {
+	for _, role := range credentials.Roles {
+		if role.Role != CredentialsRoleRoleEMSP {
+			continue
+		}
+		go func(countryCode, partyId string) {
+			ctx := context.Background()
+			party, err := o.store.GetPartyDetails(ctx, "EMSP", countryCode, partyId)
+			if err == nil && party != nil {
+				err = o.syncPartyTokens(ctx, party)
+			}
+			if err != nil {
+				slog.Warn("unable to sync tokens from ocpi party", "country_code", countryCode, "party_id", partyId, "err", err)
+			}
+		}(role.CountryCode, role.PartyId)
+	}
+}
+
+// syncPartyTokens stores the tokens that the party has changed since its
+// tokens were last synchronised and records the time of the synchronisation
+// with the party.
+func (o *OCPI) syncPartyTokens(ctx context.Context, party *store.OcpiParty) error {
+	endpoints, err := o.getPartyEndpoints(ctx, party)
+	if err != nil {
+		return err
+	}
+	tokensUrl, ok := findEndpointUrl(endpoints, "tokens", SENDER)
+	if !ok {
+		// the party does not share its tokens
+		return nil
+	}
+
+	syncedAt := o.clock.Now().UTC().Truncate(time.Second)
+	query := url.Values{}
+	if party.TokensSyncedAt != nil {
+		query.Set("date_from", party.TokensSyncedAt.Format(time.RFC3339))
+	}
+	query.Set("date_to", syncedAt.Format(time.RFC3339))
+	query.Set("offset", "0")
+	query.Set("limit", strconv.Itoa(maxPageLimit))
+
+	pageUrl := fmt.Sprintf("%s?%s", tokensUrl, query.Encode())
+	for pageUrl != "" {
+		var tokens []Token
+		tokens, pageUrl, err = o.getTokensPage(ctx, pageUrl, party)
+		if err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			err = o.store.SetToken(ctx, fromOcpiToken(token))
+			if err != nil {
+				return err
+			}
+		}
+		if len(tokens) == 0 {
+			break
+		}
+	}
+
+	// the party's endpoints may have been discovered and stored since it was
+	// read, so the stored party is updated
+	storedParty, err := o.store.GetPartyDetails(ctx, party.Role, party.CountryCode, party.PartyId)
+	if err != nil {
+		return err
+	}
+	if storedParty == nil {
+		return nil
+	}
+	storedParty.TokensSyncedAt = &syncedAt
+	return o.store.SetPartyDetails(ctx, storedParty)
+}
+
+// getTokensPage returns a page of the party's tokens and the URL of the next
+// page, if any.
+func (o *OCPI) getTokensPage(ctx context.Context, url string, party *store.OcpiParty) ([]Token, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	o.setRequestHeaders(ctx, req, party.Token, party.CountryCode, party.PartyId)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var tokensResponse OcpiResponseTokenList
+	err = json.Unmarshal(b, &tokensResponse)
+	if err != nil {
+		return nil, "", err
+	}
+	if tokensResponse.StatusCode != StatusSuccess {
+		return nil, "", fmt.Errorf("status code: %d", tokensResponse.StatusCode)
+	}
+	if tokensResponse.Data == nil {
+		return nil, "", nil
+	}
+	return *tokensResponse.Data, nextPageUrl(resp.Header), nil
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenJson(uid string) string {
+	return fmt.Sprintf(`{"country_code":"GB","party_id":"TWK","uid":"%s","type":"RFID","contract_id":"GBTWK%s",
+		"issuer":"Zynka-tech","valid":true,"whitelist":"ALLOWED","last_updated":"2024-01-01T12:00:00Z"}`, uid, uid)
+}
+
+func TestSyncTokens(t *testing.T) {
+	var mu sync.Mutex
+	var queries []map[string]string
+	senderServer, closeServer := newSender("tokens", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/ocpi/sender/2.2/tokens", r.URL.Path)
+		query := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, map[string]string{
+			"date_from": query.Get("date_from"),
+			"date_to":   query.Get("date_to"),
+			"offset":    query.Get("offset"),
+		})
+		mu.Unlock()
+
+		w.Header().Set("X-Total-Count", "2")
+		if query.Get("offset") == "0" {
+			query.Set("offset", "1")
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, r.Host, r.URL.Path, query.Encode()))
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[%s],"status_code":1000}`, tokenJson("TOKEN001"))))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[%s],"status_code":1000}`, tokenJson("TOKEN002"))))
+	})
+	defer closeServer()
+
+	// the tokens are pulled when the eMSP registers
+	ocpiApi, engine := setupEmspOcpi(t, senderServer.URL)
+	ctx := context.Background()
+	assert.Eventually(t, func() bool {
+		party, err := engine.GetPartyDetails(ctx, "EMSP", "GB", "TWK")
+		return err == nil && party != nil && party.TokensSyncedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, uid := range []string{"TOKEN001", "TOKEN002"} {
+		tok, err := engine.LookupToken(ctx, uid)
+		require.NoError(t, err)
+		require.NotNil(t, tok, uid)
+		assert.Equal(t, "GBTWK"+uid, tok.ContractId)
+		assert.Equal(t, "ALLOWED", tok.CacheMode)
+	}
+
+	party, err := engine.GetPartyDetails(ctx, "EMSP", "GB", "TWK")
+	require.NoError(t, err)
+	syncedAt := party.TokensSyncedAt.Format(time.RFC3339)
+
+	mu.Lock()
+	require.Len(t, queries, 2)
+	assert.Equal(t, map[string]string{"date_from": "", "date_to": syncedAt, "offset": "0"}, queries[0])
+	assert.Equal(t, map[string]string{"date_from": "", "date_to": syncedAt, "offset": "1"}, queries[1])
+	queries = nil
+	mu.Unlock()
+
+	// only the tokens changed since the last synchronisation are pulled
+	err = ocpiApi.SyncTokens(ctx)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, queries)
+	assert.Equal(t, syncedAt, queries[0]["date_from"])
+}
+
+func TestSyncTokensFromEmspWithoutTokensModule(t *testing.T) {
+	receiverServer, closeServer := newReceiver("sessions", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+	defer closeServer()
+
+	ocpiApi, engine := setupEmspOcpi(t, receiverServer.URL)
+
+	err := ocpiApi.SyncTokens(context.Background())
+	require.NoError(t, err)
+
+	party, err := engine.GetPartyDetails(context.Background(), "EMSP", "GB", "TWK")
+	require.NoError(t, err)
+	assert.Nil(t, party.TokensSyncedAt)
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type ClearChargingProfileResponseJsonStatus string
+
+const ClearChargingProfileResponseJsonStatusAccepted ClearChargingProfileResponseJsonStatus = "Accepted"
+const ClearChargingProfileResponseJsonStatusUnknown ClearChargingProfileResponseJsonStatus = "Unknown"
+
+type ClearChargingProfileResponseJson struct {
+	// Status corresponds to the JSON schema field "status".
+	Status ClearChargingProfileResponseJsonStatus `json:"status" yaml:"status" mapstructure:"status"`
+}
+
+func (*ClearChargingProfileResponseJson) IsResponse() {}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type GetCompositeScheduleJson struct {
+	// ChargingRateUnit corresponds to the JSON schema field "chargingRateUnit".
+	ChargingRateUnit *GetCompositeScheduleJsonChargingRateUnit `json:"chargingRateUnit,omitempty" yaml:"chargingRateUnit,omitempty" mapstructure:"chargingRateUnit,omitempty"`
+
+	// ConnectorId corresponds to the JSON schema field "connectorId".
+	ConnectorId int `json:"connectorId" yaml:"connectorId" mapstructure:"connectorId"`
+
+	// Duration corresponds to the JSON schema field "duration".
+	Duration int `json:"duration" yaml:"duration" mapstructure:"duration"`
+}
+
+func (*GetCompositeScheduleJson) IsRequest() {}
+
+type GetCompositeScheduleJsonChargingRateUnit string
+
+const GetCompositeScheduleJsonChargingRateUnitA GetCompositeScheduleJsonChargingRateUnit = "A"
+const GetCompositeScheduleJsonChargingRateUnitW GetCompositeScheduleJsonChargingRateUnit = "W"
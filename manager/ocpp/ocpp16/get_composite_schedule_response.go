@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type GetCompositeScheduleResponseJson struct {
+	// ChargingSchedule corresponds to the JSON schema field "chargingSchedule".
+	ChargingSchedule *GetCompositeScheduleResponseJsonChargingSchedule `json:"chargingSchedule,omitempty" yaml:"chargingSchedule,omitempty" mapstructure:"chargingSchedule,omitempty"`
+
+	// ConnectorId corresponds to the JSON schema field "connectorId".
+	ConnectorId *int `json:"connectorId,omitempty" yaml:"connectorId,omitempty" mapstructure:"connectorId,omitempty"`
+
+	// ScheduleStart corresponds to the JSON schema field "scheduleStart".
+	ScheduleStart *string `json:"scheduleStart,omitempty" yaml:"scheduleStart,omitempty" mapstructure:"scheduleStart,omitempty"`
+
+	// Status corresponds to the JSON schema field "status".
+	Status GetCompositeScheduleResponseJsonStatus `json:"status" yaml:"status" mapstructure:"status"`
+}
+
+func (*GetCompositeScheduleResponseJson) IsResponse() {}
+
+type GetCompositeScheduleResponseJsonChargingSchedule struct {
+	// ChargingRateUnit corresponds to the JSON schema field "chargingRateUnit".
+	ChargingRateUnit GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnit `json:"chargingRateUnit" yaml:"chargingRateUnit" mapstructure:"chargingRateUnit"`
+
+	// ChargingSchedulePeriod corresponds to the JSON schema field
+	// "chargingSchedulePeriod".
+	ChargingSchedulePeriod []GetCompositeScheduleResponseJsonChargingScheduleChargingSchedulePeriodElem `json:"chargingSchedulePeriod" yaml:"chargingSchedulePeriod" mapstructure:"chargingSchedulePeriod"`
+
+	// Duration corresponds to the JSON schema field "duration".
+	Duration *int `json:"duration,omitempty" yaml:"duration,omitempty" mapstructure:"duration,omitempty"`
+
+	// MinChargingRate corresponds to the JSON schema field "minChargingRate".
+	MinChargingRate *float64 `json:"minChargingRate,omitempty" yaml:"minChargingRate,omitempty" mapstructure:"minChargingRate,omitempty"`
+
+	// StartSchedule corresponds to the JSON schema field "startSchedule".
+	StartSchedule *string `json:"startSchedule,omitempty" yaml:"startSchedule,omitempty" mapstructure:"startSchedule,omitempty"`
+}
+
+type GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnit string
+
+const GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnitA GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnit = "A"
+const GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnitW GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnit = "W"
+
+type GetCompositeScheduleResponseJsonChargingScheduleChargingSchedulePeriodElem struct {
+	// Limit corresponds to the JSON schema field "limit".
+	Limit float64 `json:"limit" yaml:"limit" mapstructure:"limit"`
+
+	// NumberPhases corresponds to the JSON schema field "numberPhases".
+	NumberPhases *int `json:"numberPhases,omitempty" yaml:"numberPhases,omitempty" mapstructure:"numberPhases,omitempty"`
+
+	// StartPeriod corresponds to the JSON schema field "startPeriod".
+	StartPeriod int `json:"startPeriod" yaml:"startPeriod" mapstructure:"startPeriod"`
+}
+
+type GetCompositeScheduleResponseJsonStatus string
+
+const GetCompositeScheduleResponseJsonStatusAccepted GetCompositeScheduleResponseJsonStatus = "Accepted"
+const GetCompositeScheduleResponseJsonStatusRejected GetCompositeScheduleResponseJsonStatus = "Rejected"
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type SetChargingProfileResponseJsonStatus string
+
+const SetChargingProfileResponseJsonStatusAccepted SetChargingProfileResponseJsonStatus = "Accepted"
+const SetChargingProfileResponseJsonStatusNotSupported SetChargingProfileResponseJsonStatus = "NotSupported"
+const SetChargingProfileResponseJsonStatusRejected SetChargingProfileResponseJsonStatus = "Rejected"
+
+type SetChargingProfileResponseJson struct {
+	// Status corresponds to the JSON schema field "status".
+	Status SetChargingProfileResponseJsonStatus `json:"status" yaml:"status" mapstructure:"status"`
+}
+
+func (*SetChargingProfileResponseJson) IsResponse() {}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type CancelReservationRequestJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Id of the reservation to cancel.
+	//
+	ReservationId int `json:"reservationId" yaml:"reservationId" mapstructure:"reservationId"`
+}
+
+func (*CancelReservationRequestJson) IsRequest() {}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ChargingLimitSourceEnumType string
+
+const ChargingLimitSourceEnumTypeEMS ChargingLimitSourceEnumType = "EMS"
+const ChargingLimitSourceEnumTypeOther ChargingLimitSourceEnumType = "Other"
+const ChargingLimitSourceEnumTypeSO ChargingLimitSourceEnumType = "SO"
+const ChargingLimitSourceEnumTypeCSO ChargingLimitSourceEnumType = "CSO"
+
+// Charging_ Profile
+// urn:x-oca:ocpp:uid:2:233255
+// A ChargingProfile consists of ChargingSchedule, describing the amount of power
+// or current that can be delivered per time interval.
+type ChargingProfileCriterionType struct {
+	// For which charging limit sources, charging profiles SHALL be reported. If
+	// omitted, the Charging Station SHALL not filter on chargingLimitSource.
+	//
+	ChargingLimitSource []ChargingLimitSourceEnumType `json:"chargingLimitSource,omitempty" yaml:"chargingLimitSource,omitempty" mapstructure:"chargingLimitSource,omitempty"`
+
+	// List of all the chargingProfileIds requested. Any ChargingProfile that matches
+	// one of these profiles will be reported. If omitted, the Charging Station SHALL
+	// not filter on chargingProfileId. This field SHALL NOT contain more ids than set
+	// in
+	// &lt;&lt;configkey-charging-profile-entries,ChargingProfileEntries.maxLimit&gt;&gt;
+	//
+	//
+	ChargingProfileId []int `json:"chargingProfileId,omitempty" yaml:"chargingProfileId,omitempty" mapstructure:"chargingProfileId,omitempty"`
+
+	// ChargingProfilePurpose corresponds to the JSON schema field
+	// "chargingProfilePurpose".
+	ChargingProfilePurpose *ChargingProfilePurposeEnumType `json:"chargingProfilePurpose,omitempty" yaml:"chargingProfilePurpose,omitempty" mapstructure:"chargingProfilePurpose,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Charging_ Profile. Stack_ Level. Counter
+	// urn:x-oca:ocpp:uid:1:569230
+	// Value determining level in hierarchy stack of profiles. Higher values have
+	// precedence over lower values. Lowest level is 0.
+	//
+	StackLevel *int `json:"stackLevel,omitempty" yaml:"stackLevel,omitempty" mapstructure:"stackLevel,omitempty"`
+}
+
+type GetChargingProfilesRequestJson struct {
+	// ChargingProfile corresponds to the JSON schema field "chargingProfile".
+	ChargingProfile ChargingProfileCriterionType `json:"chargingProfile" yaml:"chargingProfile" mapstructure:"chargingProfile"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// For which EVSE installed charging profiles SHALL be reported. If 0, only
+	// charging profiles installed on the Charging Station itself (the grid connection)
+	// SHALL be reported. If omitted, all installed charging profiles SHALL be
+	// reported.
+	//
+	EvseId *int `json:"evseId,omitempty" yaml:"evseId,omitempty" mapstructure:"evseId,omitempty"`
+
+	// Reference identification that is to be used by the Charging Station in the
+	// &lt;&lt;reportchargingprofilesrequest, ReportChargingProfilesRequest&gt;&gt;
+	// when provided.
+	//
+	RequestId int `json:"requestId" yaml:"requestId" mapstructure:"requestId"`
+}
+
+func (*GetChargingProfilesRequestJson) IsRequest() {}
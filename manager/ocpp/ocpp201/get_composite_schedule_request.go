@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type GetCompositeScheduleRequestJson struct {
+	// ChargingRateUnit corresponds to the JSON schema field "chargingRateUnit".
+	ChargingRateUnit *ChargingRateUnitEnumType `json:"chargingRateUnit,omitempty" yaml:"chargingRateUnit,omitempty" mapstructure:"chargingRateUnit,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Length of the requested schedule in seconds.
+	//
+	//
+	Duration int `json:"duration" yaml:"duration" mapstructure:"duration"`
+
+	// The ID of the EVSE for which the schedule is requested. When evseid=0, the
+	// Charging Station will calculate the expected consumption for the grid
+	// connection.
+	//
+	EvseId int `json:"evseId" yaml:"evseId" mapstructure:"evseId"`
+}
+
+func (*GetCompositeScheduleRequestJson) IsRequest() {}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+// Charging_ Limit
+// urn:x-enexis:ecdm:uid:2:234489
+type ChargingLimitType struct {
+	// ChargingLimitSource corresponds to the JSON schema field "chargingLimitSource".
+	ChargingLimitSource ChargingLimitSourceEnumType `json:"chargingLimitSource" yaml:"chargingLimitSource" mapstructure:"chargingLimitSource"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Charging_ Limit. Is_ Grid_ Critical. Indicator
+	// urn:x-enexis:ecdm:uid:1:570847
+	// Indicates whether the charging limit is critical for the grid.
+	//
+	IsGridCritical *bool `json:"isGridCritical,omitempty" yaml:"isGridCritical,omitempty" mapstructure:"isGridCritical,omitempty"`
+}
+
+type NotifyChargingLimitRequestJson struct {
+	// ChargingLimit corresponds to the JSON schema field "chargingLimit".
+	ChargingLimit ChargingLimitType `json:"chargingLimit" yaml:"chargingLimit" mapstructure:"chargingLimit"`
+
+	// ChargingSchedule corresponds to the JSON schema field "chargingSchedule".
+	ChargingSchedule []ChargingScheduleType `json:"chargingSchedule,omitempty" yaml:"chargingSchedule,omitempty" mapstructure:"chargingSchedule,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// The charging schedule contained in this notification applies to an EVSE. evseId
+	// must be &gt; 0.
+	//
+	EvseId *int `json:"evseId,omitempty" yaml:"evseId,omitempty" mapstructure:"evseId,omitempty"`
+}
+
+func (*NotifyChargingLimitRequestJson) IsRequest() {}
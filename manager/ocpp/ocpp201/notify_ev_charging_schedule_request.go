@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type NotifyEVChargingScheduleRequestJson struct {
+	// ChargingSchedule corresponds to the JSON schema field "chargingSchedule".
+	ChargingSchedule ChargingScheduleType `json:"chargingSchedule" yaml:"chargingSchedule" mapstructure:"chargingSchedule"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// The charging schedule contained in this notification applies to an EVSE. EvseId
+	// must be &gt; 0.
+	//
+	EvseId int `json:"evseId" yaml:"evseId" mapstructure:"evseId"`
+
+	// Periods contained in the charging profile are relative to this point in time.
+	//
+	TimeBase string `json:"timeBase" yaml:"timeBase" mapstructure:"timeBase"`
+}
+
+func (*NotifyEVChargingScheduleRequestJson) IsRequest() {}
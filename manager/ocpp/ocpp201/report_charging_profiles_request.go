@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ReportChargingProfilesRequestJson struct {
+	// ChargingLimitSource corresponds to the JSON schema field "chargingLimitSource".
+	ChargingLimitSource ChargingLimitSourceEnumType `json:"chargingLimitSource" yaml:"chargingLimitSource" mapstructure:"chargingLimitSource"`
+
+	// ChargingProfile corresponds to the JSON schema field "chargingProfile".
+	ChargingProfile []ChargingProfileType `json:"chargingProfile" yaml:"chargingProfile" mapstructure:"chargingProfile"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// The evse to which the charging profile applies. If evseId = 0, the message
+	// contains an overall limit for the Charging Station.
+	//
+	EvseId int `json:"evseId" yaml:"evseId" mapstructure:"evseId"`
+
+	// Id used to match the &lt;&lt;getchargingprofilesrequest,
+	// GetChargingProfilesRequest&gt;&gt; message with the resulting
+	// ReportChargingProfilesRequest messages. When the CSMS provided a requestId in
+	// the &lt;&lt;getchargingprofilesrequest, GetChargingProfilesRequest&gt;&gt;, this
+	// field SHALL contain the same value.
+	//
+	RequestId int `json:"requestId" yaml:"requestId" mapstructure:"requestId"`
+
+	// To Be Continued. Default value when omitted: false. false indicates that there
+	// are no further messages as part of this report.
+	//
+	Tbc *bool `json:"tbc,omitempty" yaml:"tbc,omitempty" mapstructure:"tbc,omitempty"`
+}
+
+func (*ReportChargingProfilesRequestJson) IsRequest() {}
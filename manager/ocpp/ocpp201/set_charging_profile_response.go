@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ChargingProfileStatusEnumType string
+
+const ChargingProfileStatusEnumTypeAccepted ChargingProfileStatusEnumType = "Accepted"
+const ChargingProfileStatusEnumTypeRejected ChargingProfileStatusEnumType = "Rejected"
+
+type SetChargingProfileResponseJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Status corresponds to the JSON schema field "status".
+	Status ChargingProfileStatusEnumType `json:"status" yaml:"status" mapstructure:"status"`
+
+	// StatusInfo corresponds to the JSON schema field "statusInfo".
+	StatusInfo *StatusInfoType `json:"statusInfo,omitempty" yaml:"statusInfo,omitempty" mapstructure:"statusInfo,omitempty"`
+}
+
+func (*SetChargingProfileResponseJson) IsResponse() {}
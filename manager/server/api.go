@@ -7,9 +7,13 @@ import (
 	"github.com/zynka-tech/zynka-csms/manager/adminui"
 	"github.com/zynka-tech/zynka-csms/manager/api"
 	"github.com/zynka-tech/zynka-csms/manager/config"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
 	"github.com/zynka-tech/zynka-csms/manager/ocpi"
 	"github.com/zynka-tech/zynka-csms/manager/services"
 	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
 	"github.com/unrolled/secure"
 	"k8s.io/utils/clock"
 	"net/http"
@@ -22,11 +26,21 @@ import (
 	"github.com/zynka-tech/zynka-csms/manager/templates"
 )
 
-func NewApiHandler(settings config.ApiSettings, engine store.Engine, ocpi ocpi.Api, csCertProvider services.ChargeStationCertificateProvider) http.Handler {
-	apiServer, err := api.NewServer(engine, clock.RealClock{}, ocpi)
+func NewApiHandler(settings config.ApiSettings, engine store.Engine, ocpi ocpi.Api, csCertProvider services.ChargeStationCertificateProvider, emitter transport.Emitter, pendingCalls *handlers.PendingCalls, callQueue *handlers.CallQueue) http.Handler {
+	v16CallMaker := ocpp16.NewCallMaker(emitter)
+	v16CallMaker.PendingCalls = pendingCalls
+	v201CallMaker := ocpp201.NewCallMaker(emitter)
+	v201CallMaker.PendingCalls = pendingCalls
+	apiServer, err := api.NewServer(engine, clock.RealClock{}, ocpi, v16CallMaker, v201CallMaker)
 	if err != nil {
 		panic(err)
 	}
+	if settings.ConnectionStaleAfter > 0 {
+		apiServer.SetConnectionStaleAfter(settings.ConnectionStaleAfter)
+	}
+	if callQueue != nil {
+		apiServer.SetCallQueue(callQueue)
+	}
 
 	var isDevelopment bool
 	if os.Getenv("ENVIRONMENT") == "dev" {
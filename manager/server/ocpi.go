@@ -8,19 +8,16 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/cors"
-	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	"github.com/zynka-tech/zynka-csms/manager/ocpi"
 	"github.com/zynka-tech/zynka-csms/manager/store"
-	"github.com/zynka-tech/zynka-csms/manager/transport"
 	"github.com/unrolled/secure"
 	"k8s.io/utils/clock"
 	"net/http"
 	"os"
 )
 
-func NewOcpiHandler(engine store.Engine, clock clock.PassiveClock, ocpiApi ocpi.Api, emitter transport.Emitter) http.Handler {
-	v16CallMaker := ocpp16.NewCallMaker(emitter)
-	ocpiServer, err := ocpi.NewServer(ocpiApi, clock, v16CallMaker)
+func NewOcpiHandler(engine store.Engine, clock clock.PassiveClock, ocpiApi ocpi.Api) http.Handler {
+	ocpiServer, err := ocpi.NewServer(ocpiApi, clock)
 	if err != nil {
 		panic(err)
 	}
@@ -23,7 +23,7 @@ import (
 func TestSwaggerHandler(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
 	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
-	handler := NewOcpiHandler(engine, clock.RealClock{}, ocpiApi, nil)
+	handler := NewOcpiHandler(engine, clock.RealClock{}, ocpiApi)
 
 	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
 	w := httptest.NewRecorder()
@@ -59,7 +59,7 @@ func TestAPIRequestWithValidToken(t *testing.T) {
 	require.NoError(t, err)
 	clock := clockTest.NewFakePassiveClock(now)
 	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
-	handler := NewOcpiHandler(engine, clock, ocpiApi, nil)
+	handler := NewOcpiHandler(engine, clock, ocpiApi)
 
 	req := httptest.NewRequest(http.MethodGet, "/ocpi/versions", nil)
 	req.Header.Add("Authorization", fmt.Sprintf("Token %s", token))
@@ -87,7 +87,7 @@ func TestAPIRequestWithInvalidToken(t *testing.T) {
 	token := "abcdef123456"
 	engine := inmemory.NewStore(clock.RealClock{})
 	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
-	handler := NewOcpiHandler(engine, clock.RealClock{}, ocpiApi, nil)
+	handler := NewOcpiHandler(engine, clock.RealClock{}, ocpiApi)
 
 	req := httptest.NewRequest(http.MethodGet, "/ocpi/versions", nil)
 	req.Header.Add("Authorization", fmt.Sprintf("Token %s", token))
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"strconv"
+	"time"
+)
+
+// NewChargingProfileRequest returns the request that will install (or clear) the
+// profile on a charge station using the given OCPP version
+func NewChargingProfileRequest(ocppVersion string, profile *store.ChargingProfile) (ocpp.Request, error) {
+	id := profile.ChargingProfileId
+	if ocppVersion == "2.0.1" {
+		if profile.Status == store.ChargingProfileStatusClearPending {
+			return &ocpp201.ClearChargingProfileRequestJson{
+				ChargingProfileId: &id,
+			}, nil
+		}
+		return toSetChargingProfileRequest201(profile), nil
+	}
+	if profile.Status == store.ChargingProfileStatusClearPending {
+		return &ocpp16.ClearChargingProfileJson{
+			Id: &id,
+		}, nil
+	}
+	return toSetChargingProfileRequest(profile)
+}
+
+func toSetChargingProfileRequest(profile *store.ChargingProfile) (*ocpp16.SetChargingProfileJson, error) {
+	var periods []ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem
+	for _, period := range profile.ChargingSchedule.ChargingSchedulePeriods {
+		periods = append(periods, ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+			Limit:        period.Limit,
+			NumberPhases: period.NumberPhases,
+			StartPeriod:  period.StartPeriod,
+		})
+	}
+
+	csProfile := ocpp16.SetChargingProfileJsonCsChargingProfiles{
+		ChargingProfileId:      profile.ChargingProfileId,
+		ChargingProfileKind:    ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKind(profile.ChargingProfileKind),
+		ChargingProfilePurpose: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose(profile.ChargingProfilePurpose),
+		ChargingSchedule: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingSchedule{
+			ChargingRateUnit:       ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit(profile.ChargingSchedule.ChargingRateUnit),
+			ChargingSchedulePeriod: periods,
+			Duration:               profile.ChargingSchedule.Duration,
+			MinChargingRate:        profile.ChargingSchedule.MinChargingRate,
+			StartSchedule:          formatOptionalTime(profile.ChargingSchedule.StartSchedule),
+		},
+		StackLevel: profile.StackLevel,
+		ValidFrom:  formatOptionalTime(profile.ValidFrom),
+		ValidTo:    formatOptionalTime(profile.ValidTo),
+	}
+	if profile.RecurrencyKind != nil {
+		recurrencyKind := ocpp16.SetChargingProfileJsonCsChargingProfilesRecurrencyKind(*profile.RecurrencyKind)
+		csProfile.RecurrencyKind = &recurrencyKind
+	}
+	if profile.TransactionId != nil {
+		transactionId, err := strconv.Atoi(*profile.TransactionId)
+		if err != nil {
+			return nil, fmt.Errorf("transaction id %s is not an integer: %w", *profile.TransactionId, err)
+		}
+		csProfile.TransactionId = &transactionId
+	}
+
+	return &ocpp16.SetChargingProfileJson{
+		ConnectorId:        profile.ConnectorId,
+		CsChargingProfiles: csProfile,
+	}, nil
+}
+
+func toSetChargingProfileRequest201(profile *store.ChargingProfile) *ocpp201.SetChargingProfileRequestJson {
+	var periods []ocpp201.ChargingSchedulePeriodType
+	for _, period := range profile.ChargingSchedule.ChargingSchedulePeriods {
+		periods = append(periods, ocpp201.ChargingSchedulePeriodType{
+			Limit:        period.Limit,
+			NumberPhases: period.NumberPhases,
+			StartPeriod:  period.StartPeriod,
+		})
+	}
+
+	// OCPP 2.0.1 renamed the ChargePointMaxProfile purpose
+	purpose := ocpp201.ChargingProfilePurposeEnumType(profile.ChargingProfilePurpose)
+	if profile.ChargingProfilePurpose == store.ChargingProfilePurposeChargePointMaxProfile {
+		purpose = ocpp201.ChargingProfilePurposeEnumTypeChargingStationMaxProfile
+	}
+
+	chargingProfile := ocpp201.ChargingProfileType{
+		ChargingProfileKind:    ocpp201.ChargingProfileKindEnumType(profile.ChargingProfileKind),
+		ChargingProfilePurpose: purpose,
+		ChargingSchedule: []ocpp201.ChargingScheduleType{
+			{
+				ChargingRateUnit:       ocpp201.ChargingRateUnitEnumType(profile.ChargingSchedule.ChargingRateUnit),
+				ChargingSchedulePeriod: periods,
+				Duration:               profile.ChargingSchedule.Duration,
+				Id:                     profile.ChargingProfileId,
+				MinChargingRate:        profile.ChargingSchedule.MinChargingRate,
+				StartSchedule:          formatOptionalTime(profile.ChargingSchedule.StartSchedule),
+			},
+		},
+		Id:            profile.ChargingProfileId,
+		StackLevel:    profile.StackLevel,
+		TransactionId: profile.TransactionId,
+		ValidFrom:     formatOptionalTime(profile.ValidFrom),
+		ValidTo:       formatOptionalTime(profile.ValidTo),
+	}
+	if profile.RecurrencyKind != nil {
+		recurrencyKind := ocpp201.RecurrencyKindEnumType(*profile.RecurrencyKind)
+		chargingProfile.RecurrencyKind = &recurrencyKind
+	}
+
+	return &ocpp201.SetChargingProfileRequestJson{
+		EvseId:          profile.ConnectorId,
+		ChargingProfile: chargingProfile,
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
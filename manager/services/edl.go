@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// Edl is a meter reading signed by an EDL (Eichrecht data logger) meter. The
+// meter reports the reading in a signedMeterValue XML document:
+//
+//	<signedMeterValue>
+//	  <publicKey encoding="base64">...</publicKey>
+//	  <meterValueSignature encoding="base64">...</meterValueSignature>
+//	  <signatureMethod>ECDSA192SHA256</signatureMethod>
+//	  <encodingMethod>EDL</encodingMethod>
+//	  <encodedMeterValue encoding="base64">...</encodedMeterValue>
+//	</signedMeterValue>
+//
+// The signature is made over the encoded meter value.
+type Edl struct {
+	// PublicKey is the key reported by the meter: it is not used for
+	// verification
+	PublicKey         []byte
+	Signature         []byte
+	SignatureMethod   string
+	EncodedMeterValue []byte
+	MeterValue        EdlMeterValue
+}
+
+// EdlMeterValue is the reading held in the encoded meter value. The encoded
+// meter value is a fixed layout of little endian fields:
+//
+//	server id (10 bytes) | timestamp (4) | status (1) | seconds index (4) |
+//	pagination (4) | OBIS code (6) | unit (1) | scaler (1) | value (8) | ...
+//
+// Any bytes that follow the value (such as the log book and the customer id)
+// are covered by the signature but are not decoded.
+type EdlMeterValue struct {
+	ServerId     []byte
+	Timestamp    time.Time
+	Status       byte
+	SecondsIndex uint32
+	Pagination   uint32
+	Obis         []byte
+	Unit         byte
+	Scaler       int8
+	Value        uint64
+}
+
+// edlUnitWattHour is the DLMS unit code for Wh
+const edlUnitWattHour = 30
+
+const edlMeterValueLength = 39
+
+// WattHours returns the reading in Wh. It returns false if the reading is not an
+// energy reading.
+func (v EdlMeterValue) WattHours() (float64, bool) {
+	if v.Unit != edlUnitWattHour {
+		return 0, false
+	}
+	return float64(v.Value) * math.Pow10(int(v.Scaler)), true
+}
+
+type edlSignedMeterValue struct {
+	XMLName             xml.Name        `xml:"signedMeterValue"`
+	PublicKey           edlEncodedValue `xml:"publicKey"`
+	MeterValueSignature edlEncodedValue `xml:"meterValueSignature"`
+	SignatureMethod     string          `xml:"signatureMethod"`
+	EncodingMethod      string          `xml:"encodingMethod"`
+	EncodedMeterValue   edlEncodedValue `xml:"encodedMeterValue"`
+}
+
+type edlEncodedValue struct {
+	Encoding string `xml:"encoding,attr"`
+	Value    string `xml:",chardata"`
+}
+
+func (v edlEncodedValue) decode() ([]byte, error) {
+	value := strings.TrimSpace(v.Value)
+	if strings.EqualFold(v.Encoding, "hex") {
+		return hex.DecodeString(value)
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// IsEdl returns true if the signed meter data looks like an EDL signedMeterValue
+// document (it may not be valid).
+func IsEdl(data string) bool {
+	return strings.HasPrefix(strings.TrimSpace(data), "<")
+}
+
+// ParseEdl parses EDL signed meter data. The data may be base64 encoded as is
+// the case for OCPP 2.0.1.
+func ParseEdl(data string) (*Edl, error) {
+	if !IsEdl(data) {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil || !IsEdl(string(decoded)) {
+			return nil, errors.New("not EDL data")
+		}
+		data = string(decoded)
+	}
+
+	var doc edlSignedMeterValue
+	if err := xml.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal EDL signed meter value: %w", err)
+	}
+	if doc.EncodingMethod != "" && !strings.EqualFold(doc.EncodingMethod, EncodingMethodEdl) {
+		return nil, fmt.Errorf("unexpected encoding method: %s", doc.EncodingMethod)
+	}
+
+	edl := &Edl{
+		SignatureMethod: doc.SignatureMethod,
+	}
+	var err error
+	if edl.PublicKey, err = doc.PublicKey.decode(); err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	if edl.Signature, err = doc.MeterValueSignature.decode(); err != nil {
+		return nil, fmt.Errorf("decoding meter value signature: %w", err)
+	}
+	if edl.EncodedMeterValue, err = doc.EncodedMeterValue.decode(); err != nil {
+		return nil, fmt.Errorf("decoding encoded meter value: %w", err)
+	}
+	if edl.MeterValue, err = parseEdlMeterValue(edl.EncodedMeterValue); err != nil {
+		return nil, err
+	}
+	return edl, nil
+}
+
+func parseEdlMeterValue(encoded []byte) (EdlMeterValue, error) {
+	if len(encoded) < edlMeterValueLength {
+		return EdlMeterValue{}, fmt.Errorf("encoded meter value is %d bytes, expected at least %d", len(encoded), edlMeterValueLength)
+	}
+	return EdlMeterValue{
+		ServerId:     encoded[0:10],
+		Timestamp:    time.Unix(int64(binary.LittleEndian.Uint32(encoded[10:14])), 0).UTC(),
+		Status:       encoded[14],
+		SecondsIndex: binary.LittleEndian.Uint32(encoded[15:19]),
+		Pagination:   binary.LittleEndian.Uint32(encoded[19:23]),
+		Obis:         encoded[23:29],
+		Unit:         encoded[29],
+		Scaler:       int8(encoded[30]),
+		Value:        binary.LittleEndian.Uint64(encoded[31:39]),
+	}, nil
+}
+
+// edlCurves maps the EDL signature methods that can be verified to the curve of
+// the signing key.
+var edlCurves = map[string]elliptic.Curve{
+	"ECDSA192SHA256": P192(),
+	"ECDSA256SHA256": elliptic.P256(),
+}
+
+// verifyEdl verifies the EDL signed data with the meter public keys.
+func verifyEdl(data string, keys []*ecdsa.PublicKey) store.SignedMeterValueStatus {
+	edl, err := ParseEdl(data)
+	if err != nil {
+		return store.SignedMeterValueStatusInvalid
+	}
+	curve, ok := edlCurves[edl.SignatureMethod]
+	if !ok {
+		return store.SignedMeterValueStatusUnsupported
+	}
+
+	digest := sha256.Sum256(edl.EncodedMeterValue)
+	status := store.SignedMeterValueStatusNoPublicKey
+	for _, key := range keys {
+		if key.Curve != curve {
+			continue
+		}
+		if verifyEdlSignature(key, digest[:], edl.Signature) {
+			return store.SignedMeterValueStatusValid
+		}
+		status = store.SignedMeterValueStatusInvalid
+	}
+	return status
+}
+
+// verifyEdlSignature verifies a signature that is either the concatenation of
+// r and s or ASN.1 DER encoded.
+func verifyEdlSignature(key *ecdsa.PublicKey, digest, signature []byte) bool {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	if len(signature) == 2*size {
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return ecdsa.VerifyASN1(key, digest, signature)
+}
+
+var p192Once sync.Once
+var p192 *elliptic.CurveParams
+
+// P192 returns the NIST P-192 curve (secp192r1) used by EDL meters. The curve
+// is not provided by crypto/elliptic so the generic (not constant time)
+// implementation is used: this is fine as it is only used to verify signatures.
+func P192() elliptic.Curve {
+	p192Once.Do(func() {
+		p192 = &elliptic.CurveParams{Name: "P-192", BitSize: 192}
+		p192.P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffeffffffffffffffff", 16)
+		p192.N, _ = new(big.Int).SetString("ffffffffffffffffffffffff99def836146bc9b1b4d22831", 16)
+		p192.B, _ = new(big.Int).SetString("64210519e59c80e70fa7e9ab72243049feb8deecc146b9b1", 16)
+		p192.Gx, _ = new(big.Int).SetString("188da80eb03090f67cbf20eb43a18800f4ff0afd82ff1012", 16)
+		p192.Gy, _ = new(big.Int).SetString("07192b95ffc8da78631011ed6b24cdd573f977a11e794811", 16)
+	})
+	return p192
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// EvseMappingService maps OCPI EVSEs to the EVSEs of OCPP charge stations.
+type EvseMappingService interface {
+	// MapEvse returns the mapping for the EVSE of the location or nil if the EVSE
+	// is not made up of a charge station.
+	MapEvse(ctx context.Context, locationId string, evse *store.Evse) (*store.EvseMapping, error)
+	// MapEvseId returns the mapping for the eMI3 EVSE ID or nil if the EVSE ID
+	// does not identify an EVSE of a charge station.
+	MapEvseId(ctx context.Context, evseId string) (*store.EvseMapping, error)
+}
+
+// DefaultEvseMappingPatterns are the patterns used when none are configured.
+// They match the uid of an EVSE made up of a whole charge station, the CPO's
+// country code and party id followed by an "E" and the charge station id, and
+// an eMI3 EVSE ID with an optional EVSE id, e.g. "GB*TWK*Ecs001*1".
+var DefaultEvseMappingPatterns = []string{
+	`^[a-zA-Z]{5}E(?P<charge_station_id>[a-zA-Z0-9]+)$`,
+	`^[A-Z]{2}\*?[A-Z0-9]{3}\*?E(?P<charge_station_id>[a-zA-Z0-9]+)(\*(?P<evse_id>[0-9]+))?$`,
+}
+
+// PatternEvseMappingService implements EvseMappingService. An EVSE is mapped by
+// the first of the following that applies:
+//   - the mapping held in the store for the EVSE's location and uid, or for its
+//     EVSE ID
+//   - the charge station registered with the EVSE's location
+//   - the first pattern that matches the EVSE's uid, or its EVSE ID
+//
+// The patterns are regular expressions with a "charge_station_id" group and an
+// optional "evse_id" group: a pattern without an "evse_id" group maps the EVSE
+// to the whole charge station. The connectors of an EVSE mapped by a pattern
+// have the same ids as the OCPP connectors.
+type PatternEvseMappingService struct {
+	Store    store.EvseMappingStore
+	Patterns []*regexp.Regexp
+}
+
+// NewPatternEvseMappingService creates a PatternEvseMappingService with the
+// patterns: the DefaultEvseMappingPatterns are used if there are none.
+func NewPatternEvseMappingService(engine store.EvseMappingStore, patterns []string) (*PatternEvseMappingService, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultEvseMappingPatterns
+	}
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile evse mapping pattern %q: %w", pattern, err)
+		}
+		if re.SubexpIndex("charge_station_id") < 0 {
+			return nil, fmt.Errorf("evse mapping pattern %q has no charge_station_id group", pattern)
+		}
+		compiled[i] = re
+	}
+	return &PatternEvseMappingService{
+		Store:    engine,
+		Patterns: compiled,
+	}, nil
+}
+
+// NewDefaultEvseMappingService creates a PatternEvseMappingService with the
+// DefaultEvseMappingPatterns.
+func NewDefaultEvseMappingService(engine store.EvseMappingStore) *PatternEvseMappingService {
+	patterns := make([]*regexp.Regexp, len(DefaultEvseMappingPatterns))
+	for i, pattern := range DefaultEvseMappingPatterns {
+		patterns[i] = regexp.MustCompile(pattern)
+	}
+	return &PatternEvseMappingService{
+		Store:    engine,
+		Patterns: patterns,
+	}
+}
+
+func (s *PatternEvseMappingService) MapEvse(ctx context.Context, locationId string, evse *store.Evse) (*store.EvseMapping, error) {
+	mapping, err := s.Store.LookupEvseMapping(ctx, locationId, evse.Uid)
+	if err != nil || mapping != nil {
+		return mapping, err
+	}
+	if evse.EvseId != nil {
+		mapping, err = s.Store.LookupEvseMappingByEvseId(ctx, *evse.EvseId)
+		if err != nil || mapping != nil {
+			return mapping, err
+		}
+	}
+
+	if evse.ChargeStationId != "" {
+		return &store.EvseMapping{
+			LocationId:          locationId,
+			EvseUid:             evse.Uid,
+			EvseId:              evse.EvseId,
+			ChargeStationId:     evse.ChargeStationId,
+			ChargeStationEvseId: evse.ChargeStationEvseId,
+		}, nil
+	}
+
+	mapping = s.matchPatterns(evse.Uid)
+	if mapping == nil && evse.EvseId != nil {
+		mapping = s.matchPatterns(*evse.EvseId)
+	}
+	if mapping != nil {
+		mapping.LocationId = locationId
+		mapping.EvseUid = evse.Uid
+		mapping.EvseId = evse.EvseId
+	}
+	return mapping, nil
+}
+
+func (s *PatternEvseMappingService) MapEvseId(ctx context.Context, evseId string) (*store.EvseMapping, error) {
+	mapping, err := s.Store.LookupEvseMappingByEvseId(ctx, evseId)
+	if err != nil || mapping != nil {
+		return mapping, err
+	}
+
+	mapping = s.matchPatterns(evseId)
+	if mapping != nil {
+		mapping.EvseId = &evseId
+	}
+	return mapping, nil
+}
+
+// matchPatterns returns the mapping given by the first pattern that matches the
+// id or nil if no pattern matches.
+func (s *PatternEvseMappingService) matchPatterns(id string) *store.EvseMapping {
+	for _, pattern := range s.Patterns {
+		match := pattern.FindStringSubmatch(id)
+		if match == nil || match[pattern.SubexpIndex("charge_station_id")] == "" {
+			continue
+		}
+		mapping := &store.EvseMapping{
+			ChargeStationId: match[pattern.SubexpIndex("charge_station_id")],
+		}
+		if i := pattern.SubexpIndex("evse_id"); i >= 0 && match[i] != "" {
+			evseId, err := strconv.Atoi(match[i])
+			if err != nil {
+				continue
+			}
+			mapping.ChargeStationEvseId = evseId
+		}
+		return mapping
+	}
+	return nil
+}
+
+// OcppConnectorId returns the id of the OCPP connector for the OCPI connector
+// of the mapped EVSE.
+func OcppConnectorId(mapping *store.EvseMapping, connectorId string) (int, error) {
+	for _, connector := range mapping.Connectors {
+		if connector.Id == connectorId {
+			return connector.ConnectorId, nil
+		}
+	}
+	id, err := strconv.Atoi(connectorId)
+	if err != nil {
+		return 0, fmt.Errorf("connector %s of evse %s is not mapped to an ocpp connector", connectorId, mapping.EvseUid)
+	}
+	return id, nil
+}
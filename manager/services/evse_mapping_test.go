@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+)
+
+func TestMapEvseWithDefaultPatterns(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	evseMapping := services.NewDefaultEvseMappingService(engine)
+	ctx := context.Background()
+
+	mapping, err := evseMapping.MapEvse(ctx, "loc001", &store.Evse{Uid: "GBTWKEcs001"})
+	require.NoError(t, err)
+	assert.Equal(t, &store.EvseMapping{
+		LocationId:      "loc001",
+		EvseUid:         "GBTWKEcs001",
+		ChargeStationId: "cs001",
+	}, mapping)
+
+	mapping, err = evseMapping.MapEvse(ctx, "loc001", &store.Evse{Uid: "evse001", EvseId: makePtr("GB*TWK*Ecs001*2")})
+	require.NoError(t, err)
+	assert.Equal(t, &store.EvseMapping{
+		LocationId:          "loc001",
+		EvseUid:             "evse001",
+		EvseId:              makePtr("GB*TWK*Ecs001*2"),
+		ChargeStationId:     "cs001",
+		ChargeStationEvseId: 2,
+	}, mapping)
+
+	mapping, err = evseMapping.MapEvse(ctx, "loc001", &store.Evse{Uid: "evse001"})
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	mapping, err = evseMapping.MapEvse(ctx, "loc001", &store.Evse{Uid: "GBTWKE"})
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+}
+
+func TestMapEvseRegisteredWithLocation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	evseMapping := services.NewDefaultEvseMappingService(engine)
+
+	mapping, err := evseMapping.MapEvse(context.Background(), "loc001", &store.Evse{
+		Uid:                 "GBTWKEcs001",
+		ChargeStationId:     "cs002",
+		ChargeStationEvseId: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cs002", mapping.ChargeStationId)
+	assert.Equal(t, 1, mapping.ChargeStationEvseId)
+}
+
+func TestMapEvseWithStoredMapping(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	evseMapping := services.NewDefaultEvseMappingService(engine)
+	ctx := context.Background()
+
+	stored := &store.EvseMapping{
+		LocationId:          "loc001",
+		EvseUid:             "evse001",
+		EvseId:              makePtr("GB*TWK*E0001"),
+		ChargeStationId:     "cs003",
+		ChargeStationEvseId: 2,
+		Connectors:          []store.ConnectorMapping{{Id: "A", ConnectorId: 1}},
+	}
+	err := engine.SetEvseMapping(ctx, stored)
+	require.NoError(t, err)
+
+	mapping, err := evseMapping.MapEvse(ctx, "loc001", &store.Evse{
+		Uid:             "evse001",
+		ChargeStationId: "cs002",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, stored, mapping)
+
+	mapping, err = evseMapping.MapEvse(ctx, "loc002", &store.Evse{Uid: "evse002", EvseId: makePtr("GB*TWK*E0001")})
+	require.NoError(t, err)
+	assert.Equal(t, stored, mapping)
+
+	mapping, err = evseMapping.MapEvseId(ctx, "GB*TWK*E0001")
+	require.NoError(t, err)
+	assert.Equal(t, stored, mapping)
+
+	connectorId, err := services.OcppConnectorId(mapping, "A")
+	require.NoError(t, err)
+	assert.Equal(t, 1, connectorId)
+	connectorId, err = services.OcppConnectorId(mapping, "3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, connectorId)
+	_, err = services.OcppConnectorId(mapping, "B")
+	assert.Error(t, err)
+}
+
+func TestMapEvseWithConfiguredPatterns(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	evseMapping, err := services.NewPatternEvseMappingService(engine, []string{
+		`^(?P<charge_station_id>[a-z]+[0-9]+)-(?P<evse_id>[0-9]+)$`,
+	})
+	require.NoError(t, err)
+
+	mapping, err := evseMapping.MapEvse(context.Background(), "loc001", &store.Evse{Uid: "cs001-3"})
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.Equal(t, "cs001", mapping.ChargeStationId)
+	assert.Equal(t, 3, mapping.ChargeStationEvseId)
+
+	mapping, err = evseMapping.MapEvse(context.Background(), "loc001", &store.Evse{Uid: "GBTWKEcs001"})
+	require.NoError(t, err)
+	assert.Nil(t, mapping, "the default patterns must not be used when patterns are configured")
+}
+
+func TestNewPatternEvseMappingServiceWithInvalidPattern(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	_, err := services.NewPatternEvseMappingService(engine, []string{`^(`})
+	assert.Error(t, err)
+
+	_, err = services.NewPatternEvseMappingService(engine, []string{`^E([a-z0-9]+)$`})
+	assert.ErrorContains(t, err, "charge_station_id")
+}
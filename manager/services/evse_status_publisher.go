@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import "context"
+
+// EvseStatusPublisher keeps roaming partners informed about the availability of
+// the EVSEs that are made up of the charge stations.
+type EvseStatusPublisher interface {
+	// PushEvseStatus is called when a charge station reports the status of a
+	// connector: it sends the statuses of the charge station's EVSEs that have
+	// changed.
+	PushEvseStatus(ctx context.Context, chargeStationId string) error
+}
@@ -0,0 +1,386 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LoadBalancer shares the capacity of a site between the transactions that are
+// in progress on the charge stations of the site. Each transaction is limited by
+// a TxProfile that is updated whenever the allocation changes.
+type LoadBalancer interface {
+	// TransactionStarted adds the transaction to the site of the charge station
+	// and recomputes the allocations. The evseId is the connector id for OCPP 1.6
+	// and the transactionId is the id known by the charge station.
+	TransactionStarted(ctx context.Context, chargeStationId string, evseId int, transactionId string) error
+	// TransactionEnded removes the transaction from the site of the charge station
+	// and recomputes the allocations.
+	TransactionEnded(ctx context.Context, chargeStationId, transactionId string) error
+	// Rebalance recomputes the allocations for the site of the charge station.
+	Rebalance(ctx context.Context, chargeStationId string) error
+}
+
+// Site is a group of charge stations that share a grid connection. All currents
+// are in amps per phase.
+type Site struct {
+	Id                    string
+	MaxCurrent            float64
+	MinTransactionCurrent float64
+	MaxTransactionCurrent float64
+	ChargeStationIds      []string
+	// Priorities maps charge station ids to their priority: higher values are
+	// allocated capacity first. Charge stations that are not listed have priority 0.
+	Priorities map[string]int
+}
+
+// SiteLoad is a transaction that is drawing power from a site
+type SiteLoad struct {
+	ChargeStationId string
+	TransactionId   string
+	Priority        int
+	StartedAt       time.Time
+	Limit           float64
+}
+
+// AllocationStrategy decides how the capacity of a site is shared: it sets the
+// Limit of each load. A limit of 0 pauses the transaction.
+type AllocationStrategy interface {
+	Allocate(site *Site, loads []*SiteLoad)
+}
+
+// EqualShareAllocationStrategy shares the capacity equally between all the loads.
+// When there is not enough capacity for every load to get the minimum current,
+// the loads that started first are allocated.
+type EqualShareAllocationStrategy struct{}
+
+func (EqualShareAllocationStrategy) Allocate(site *Site, loads []*SiteLoad) {
+	sortByStartedAt(loads)
+	allocateEqually(site, site.MaxCurrent, loads)
+}
+
+// PriorityAllocationStrategy allocates capacity to the loads with the highest
+// priority first, sharing it equally between loads with the same priority.
+type PriorityAllocationStrategy struct{}
+
+func (PriorityAllocationStrategy) Allocate(site *Site, loads []*SiteLoad) {
+	sortByStartedAt(loads)
+	sort.SliceStable(loads, func(i, j int) bool {
+		return loads[i].Priority > loads[j].Priority
+	})
+
+	remaining := site.MaxCurrent
+	for start := 0; start < len(loads); {
+		end := start
+		for end < len(loads) && loads[end].Priority == loads[start].Priority {
+			end++
+		}
+		remaining -= allocateEqually(site, remaining, loads[start:end])
+		start = end
+	}
+}
+
+// FirstComeAllocationStrategy allocates the maximum transaction current to the
+// loads in the order that they started until the capacity is exhausted.
+type FirstComeAllocationStrategy struct{}
+
+func (FirstComeAllocationStrategy) Allocate(site *Site, loads []*SiteLoad) {
+	sortByStartedAt(loads)
+
+	remaining := site.MaxCurrent
+	for _, load := range loads {
+		load.Limit = math.Min(site.MaxTransactionCurrent, remaining)
+		if load.Limit < site.MinTransactionCurrent {
+			load.Limit = 0
+		}
+		remaining -= load.Limit
+	}
+}
+
+// allocateEqually shares the capacity between the loads, which must be sorted in
+// the order in which they should be allocated when there isn't enough capacity
+// for all of them, and returns the total allocated
+func allocateEqually(site *Site, capacity float64, loads []*SiteLoad) float64 {
+	count := len(loads)
+	if site.MinTransactionCurrent > 0 {
+		count = int(math.Min(float64(count), math.Floor(capacity/site.MinTransactionCurrent)))
+	}
+
+	var allocated float64
+	for i, load := range loads {
+		load.Limit = 0
+		if i < count {
+			load.Limit = math.Min(site.MaxTransactionCurrent, capacity/float64(count))
+			allocated += load.Limit
+		}
+	}
+	return allocated
+}
+
+func sortByStartedAt(loads []*SiteLoad) {
+	sort.SliceStable(loads, func(i, j int) bool {
+		return loads[i].StartedAt.Before(loads[j].StartedAt)
+	})
+}
+
+// SiteLoadBalancer implements LoadBalancer using the charging profile store to
+// hold the TxProfile of each transaction in progress on a site. Only the
+// TxProfiles that the load balancer created are managed: a transaction that
+// already has a TxProfile (e.g. set through the API or by an eMSP) is left
+// alone and its load is not accounted for.
+//
+// The allocations are serialised within the manager but not across managers:
+// when several managers share a store, concurrent rebalances of a site may
+// briefly send conflicting limits until the next rebalance of the site.
+type SiteLoadBalancer struct {
+	Store         store.Engine
+	Clock         clock.PassiveClock
+	Strategy      AllocationStrategy
+	Sites         []*Site
+	V16CallMaker  handlers.CallMaker
+	V201CallMaker handlers.CallMaker
+	// RetryAfter is the delay before the charging profile sync resends a profile
+	// that the charge station has not responded to
+	RetryAfter time.Duration
+
+	mu sync.Mutex
+}
+
+func (l *SiteLoadBalancer) TransactionStarted(ctx context.Context, chargeStationId string, evseId int, transactionId string) error {
+	site := l.findSite(chargeStationId)
+	if site == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	profiles, err := l.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return fmt.Errorf("lookup charging profiles for %s: %w", chargeStationId, err)
+	}
+
+	var existing []*store.ChargingProfile
+	if profiles != nil {
+		existing = profiles.ChargingProfiles
+	}
+
+	if profile := findTransactionProfile(existing, transactionId); profile != nil {
+		if !profile.LoadBalanced {
+			slog.Info("transaction has a charging profile that is not load balanced",
+				slog.String("chargeStationId", chargeStationId), slog.String("transactionId", transactionId),
+				slog.Int("chargingProfileId", profile.ChargingProfileId))
+		}
+	} else {
+		now := l.Clock.Now().UTC()
+		err = l.Store.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{
+				{
+					ChargingProfileId:      nextChargingProfileId(existing),
+					ConnectorId:            evseId,
+					TransactionId:          &transactionId,
+					ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+					ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+					ChargingSchedule: store.ChargingSchedule{
+						StartSchedule:    &now,
+						ChargingRateUnit: store.ChargingRateUnitA,
+						// a negative limit ensures that the profile is sent
+						// whatever the allocation turns out to be
+						ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+							{StartPeriod: 0, Limit: -1},
+						},
+					},
+					Status:       store.ChargingProfileStatusPending,
+					LoadBalanced: true,
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("store charging profile for %s: %w", chargeStationId, err)
+		}
+	}
+
+	return l.rebalance(ctx, site)
+}
+
+func (l *SiteLoadBalancer) TransactionEnded(ctx context.Context, chargeStationId, transactionId string) error {
+	site := l.findSite(chargeStationId)
+	if site == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	profiles, err := l.Store.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+	if err != nil {
+		return fmt.Errorf("lookup charging profiles for %s: %w", chargeStationId, err)
+	}
+
+	// the charge station discards a TxProfile when the transaction ends so
+	// there is no need to clear it
+	if profiles != nil {
+		profile := findTransactionProfile(profiles.ChargingProfiles, transactionId)
+		if profile != nil && profile.LoadBalanced {
+			err = l.Store.DeleteChargeStationChargingProfile(ctx, chargeStationId, profile.ChargingProfileId)
+			if err != nil {
+				return fmt.Errorf("delete charging profile for %s: %w", chargeStationId, err)
+			}
+		}
+	}
+
+	return l.rebalance(ctx, site)
+}
+
+func (l *SiteLoadBalancer) Rebalance(ctx context.Context, chargeStationId string) error {
+	site := l.findSite(chargeStationId)
+	if site == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rebalance(ctx, site)
+}
+
+func (l *SiteLoadBalancer) rebalance(ctx context.Context, site *Site) error {
+	var loads []*SiteLoad
+	profilesByLoad := make(map[*SiteLoad]*store.ChargingProfile)
+	for _, csId := range site.ChargeStationIds {
+		profiles, err := l.Store.LookupChargeStationChargingProfiles(ctx, csId)
+		if err != nil {
+			return fmt.Errorf("lookup charging profiles for %s: %w", csId, err)
+		}
+		if profiles == nil {
+			continue
+		}
+		for _, profile := range profiles.ChargingProfiles {
+			if !profile.LoadBalanced ||
+				profile.ChargingProfilePurpose != store.ChargingProfilePurposeTxProfile ||
+				profile.TransactionId == nil ||
+				profile.Status == store.ChargingProfileStatusClearPending {
+				continue
+			}
+			load := &SiteLoad{
+				ChargeStationId: csId,
+				TransactionId:   *profile.TransactionId,
+				Priority:        site.Priorities[csId],
+			}
+			if profile.ChargingSchedule.StartSchedule != nil {
+				load.StartedAt = *profile.ChargingSchedule.StartSchedule
+			}
+			loads = append(loads, load)
+			profilesByLoad[load] = profile
+		}
+	}
+
+	l.Strategy.Allocate(site, loads)
+
+	for _, load := range loads {
+		profile := profilesByLoad[load]
+		if currentLimit(profile) == load.Limit {
+			continue
+		}
+
+		slog.Info("updating transaction allocation", slog.String("siteId", site.Id),
+			slog.String("chargeStationId", load.ChargeStationId),
+			slog.String("transactionId", load.TransactionId),
+			slog.Float64("limit", load.Limit))
+
+		profile.ChargingSchedule.ChargingRateUnit = store.ChargingRateUnitA
+		profile.ChargingSchedule.ChargingSchedulePeriods = []store.ChargingSchedulePeriod{
+			{StartPeriod: 0, Limit: load.Limit},
+		}
+		profile.Status = store.ChargingProfileStatusPending
+		profile.SendAfter = l.Clock.Now().Add(l.RetryAfter)
+
+		err := l.Store.UpdateChargeStationChargingProfiles(ctx, load.ChargeStationId, &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{profile},
+		})
+		if err != nil {
+			return fmt.Errorf("update charging profile for %s: %w", load.ChargeStationId, err)
+		}
+
+		// a failure to send is not fatal: the charging profile sync will resend
+		// the pending profile once the retry delay has passed
+		err = l.send(ctx, load.ChargeStationId, profile)
+		if err != nil {
+			slog.Error("send transaction allocation", slog.String("err", err.Error()),
+				slog.String("chargeStationId", load.ChargeStationId),
+				slog.Int("chargingProfileId", profile.ChargingProfileId))
+		}
+	}
+
+	return nil
+}
+
+func (l *SiteLoadBalancer) send(ctx context.Context, chargeStationId string, profile *store.ChargingProfile) error {
+	details, err := l.Store.LookupChargeStationRuntimeDetails(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+	if details == nil {
+		return fmt.Errorf("no runtime details for %s", chargeStationId)
+	}
+
+	callMaker := l.V16CallMaker
+	if details.OcppVersion == "2.0.1" {
+		callMaker = l.V201CallMaker
+	}
+
+	req, err := NewChargingProfileRequest(details.OcppVersion, profile)
+	if err != nil {
+		return err
+	}
+
+	return callMaker.Send(ctx, chargeStationId, req)
+}
+
+func (l *SiteLoadBalancer) findSite(chargeStationId string) *Site {
+	for _, site := range l.Sites {
+		for _, csId := range site.ChargeStationIds {
+			if csId == chargeStationId {
+				return site
+			}
+		}
+	}
+	return nil
+}
+
+func currentLimit(profile *store.ChargingProfile) float64 {
+	if len(profile.ChargingSchedule.ChargingSchedulePeriods) != 1 ||
+		profile.ChargingSchedule.ChargingRateUnit != store.ChargingRateUnitA {
+		return -1
+	}
+	return profile.ChargingSchedule.ChargingSchedulePeriods[0].Limit
+}
+
+func findTransactionProfile(profiles []*store.ChargingProfile, transactionId string) *store.ChargingProfile {
+	for _, profile := range profiles {
+		if profile.ChargingProfilePurpose == store.ChargingProfilePurposeTxProfile &&
+			profile.TransactionId != nil && *profile.TransactionId == transactionId {
+			return profile
+		}
+	}
+	return nil
+}
+
+func nextChargingProfileId(profiles []*store.ChargingProfile) int {
+	id := 1
+	for _, profile := range profiles {
+		if profile.ChargingProfileId >= id {
+			id = profile.ChargingProfileId + 1
+		}
+	}
+	return id
+}
@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+type recordingCallMaker struct {
+	requests map[string][]ocpp.Request
+}
+
+func (r *recordingCallMaker) Send(_ context.Context, chargeStationId string, request ocpp.Request) error {
+	if r.requests == nil {
+		r.requests = make(map[string][]ocpp.Request)
+	}
+	r.requests[chargeStationId] = append(r.requests[chargeStationId], request)
+	return nil
+}
+
+func newSiteLoads(starts ...int) []*services.SiteLoad {
+	base := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	var loads []*services.SiteLoad
+	for i, start := range starts {
+		loads = append(loads, &services.SiteLoad{
+			ChargeStationId: "cs001",
+			TransactionId:   string(rune('a' + i)),
+			StartedAt:       base.Add(time.Duration(start) * time.Minute),
+		})
+	}
+	return loads
+}
+
+func limits(loads []*services.SiteLoad) map[string]float64 {
+	result := make(map[string]float64)
+	for _, load := range loads {
+		result[load.TransactionId] = load.Limit
+	}
+	return result
+}
+
+func TestEqualShareAllocationStrategy(t *testing.T) {
+	site := &services.Site{
+		MaxCurrent:            60,
+		MinTransactionCurrent: 6,
+		MaxTransactionCurrent: 32,
+	}
+
+	t.Run("capped at max transaction current", func(t *testing.T) {
+		loads := newSiteLoads(0)
+		services.EqualShareAllocationStrategy{}.Allocate(site, loads)
+		assert.Equal(t, map[string]float64{"a": 32}, limits(loads))
+	})
+
+	t.Run("shared equally", func(t *testing.T) {
+		loads := newSiteLoads(0, 1, 2)
+		services.EqualShareAllocationStrategy{}.Allocate(site, loads)
+		assert.Equal(t, map[string]float64{"a": 20, "b": 20, "c": 20}, limits(loads))
+	})
+
+	t.Run("latest paused when below minimum", func(t *testing.T) {
+		loads := newSiteLoads(3, 0, 1, 2, 4, 5, 6, 7, 8, 9, 10)
+		services.EqualShareAllocationStrategy{}.Allocate(site, loads)
+		got := limits(loads)
+		assert.Equal(t, 0.0, got["k"])
+		assert.Equal(t, 6.0, got["a"])
+		assert.Equal(t, 6.0, got["j"])
+	})
+}
+
+func TestPriorityAllocationStrategy(t *testing.T) {
+	site := &services.Site{
+		MaxCurrent:            50,
+		MinTransactionCurrent: 6,
+		MaxTransactionCurrent: 32,
+	}
+
+	loads := newSiteLoads(0, 1, 2)
+	loads[2].Priority = 1
+
+	services.PriorityAllocationStrategy{}.Allocate(site, loads)
+
+	assert.Equal(t, map[string]float64{"a": 9, "b": 9, "c": 32}, limits(loads))
+}
+
+func TestFirstComeAllocationStrategy(t *testing.T) {
+	site := &services.Site{
+		MaxCurrent:            70,
+		MinTransactionCurrent: 6,
+		MaxTransactionCurrent: 32,
+	}
+
+	loads := newSiteLoads(2, 0, 1, 3)
+
+	services.FirstComeAllocationStrategy{}.Allocate(site, loads)
+
+	assert.Equal(t, map[string]float64{"a": 6, "b": 32, "c": 32, "d": 0}, limits(loads))
+}
+
+func TestSiteLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{OcppVersion: "1.6"})
+	require.NoError(t, err)
+	err = engine.SetChargeStationRuntimeDetails(ctx, "cs002", &store.ChargeStationRuntimeDetails{OcppVersion: "2.0.1"})
+	require.NoError(t, err)
+
+	v16CallMaker := &recordingCallMaker{}
+	v201CallMaker := &recordingCallMaker{}
+	loadBalancer := &services.SiteLoadBalancer{
+		Store:    engine,
+		Clock:    clock,
+		Strategy: services.EqualShareAllocationStrategy{},
+		Sites: []*services.Site{
+			{
+				Id:                    "depot",
+				MaxCurrent:            40,
+				MinTransactionCurrent: 6,
+				MaxTransactionCurrent: 32,
+				ChargeStationIds:      []string{"cs001", "cs002"},
+			},
+		},
+		V16CallMaker:  v16CallMaker,
+		V201CallMaker: v201CallMaker,
+		RetryAfter:    2 * time.Minute,
+	}
+
+	err = loadBalancer.TransactionStarted(ctx, "cs001", 1, "1234")
+	require.NoError(t, err)
+
+	require.Len(t, v16CallMaker.requests["cs001"], 1)
+	req16 := v16CallMaker.requests["cs001"][0].(*ocpp16.SetChargingProfileJson)
+	assert.Equal(t, 1, req16.ConnectorId)
+	assert.Equal(t, makePtr(1234), req16.CsChargingProfiles.TransactionId)
+	assert.Equal(t, 32.0, req16.CsChargingProfiles.ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+
+	clock.SetTime(now.Add(time.Minute))
+	err = loadBalancer.TransactionStarted(ctx, "cs002", 2, "tx002")
+	require.NoError(t, err)
+
+	require.Len(t, v16CallMaker.requests["cs001"], 2)
+	req16 = v16CallMaker.requests["cs001"][1].(*ocpp16.SetChargingProfileJson)
+	assert.Equal(t, 20.0, req16.CsChargingProfiles.ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+
+	require.Len(t, v201CallMaker.requests["cs002"], 1)
+	req201 := v201CallMaker.requests["cs002"][0].(*ocpp201.SetChargingProfileRequestJson)
+	assert.Equal(t, 2, req201.EvseId)
+	assert.Equal(t, makePtr("tx002"), req201.ChargingProfile.TransactionId)
+	assert.Equal(t, ocpp201.ChargingProfilePurposeEnumTypeTxProfile, req201.ChargingProfile.ChargingProfilePurpose)
+	assert.Equal(t, 20.0, req201.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod[0].Limit)
+
+	// rebalancing without any change does not resend the profiles
+	err = loadBalancer.Rebalance(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Len(t, v16CallMaker.requests["cs001"], 2)
+	assert.Len(t, v201CallMaker.requests["cs002"], 1)
+
+	err = loadBalancer.TransactionEnded(ctx, "cs001", "1234")
+	require.NoError(t, err)
+
+	assert.Len(t, v16CallMaker.requests["cs001"], 2)
+	require.Len(t, v201CallMaker.requests["cs002"], 2)
+	req201 = v201CallMaker.requests["cs002"][1].(*ocpp201.SetChargingProfileRequestJson)
+	assert.Equal(t, 32.0, req201.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod[0].Limit)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, profiles)
+
+	profiles, err = engine.LookupChargeStationChargingProfiles(ctx, "cs002")
+	require.NoError(t, err)
+	require.NotNil(t, profiles)
+	require.Len(t, profiles.ChargingProfiles, 1)
+	assert.Equal(t, store.ChargingProfileStatusPending, profiles.ChargingProfiles[0].Status)
+	assert.Equal(t, now.Add(3*time.Minute), profiles.ChargingProfiles[0].SendAfter)
+}
+
+func TestSiteLoadBalancerIgnoresChargeStationsOutsideSites(t *testing.T) {
+	ctx := context.Background()
+	clock := clockTest.NewFakePassiveClock(time.Now())
+	engine := inmemory.NewStore(clock)
+
+	v16CallMaker := &recordingCallMaker{}
+	loadBalancer := &services.SiteLoadBalancer{
+		Store:        engine,
+		Clock:        clock,
+		Strategy:     services.EqualShareAllocationStrategy{},
+		V16CallMaker: v16CallMaker,
+	}
+
+	err := loadBalancer.TransactionStarted(ctx, "cs001", 1, "1234")
+	require.NoError(t, err)
+
+	assert.Len(t, v16CallMaker.requests, 0)
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, profiles)
+}
+
+func TestSiteLoadBalancerOnlyManagesItsOwnProfiles(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{OcppVersion: "1.6"})
+	require.NoError(t, err)
+
+	userProfile := &store.ChargingProfile{
+		ChargingProfileId:      7,
+		ConnectorId:            2,
+		TransactionId:          makePtr("5678"),
+		ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+		ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+		ChargingSchedule: store.ChargingSchedule{
+			ChargingRateUnit: store.ChargingRateUnitA,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 10},
+				{StartPeriod: 3600, Limit: 16},
+			},
+		},
+		Status: store.ChargingProfileStatusAccepted,
+	}
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{userProfile},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &recordingCallMaker{}
+	loadBalancer := &services.SiteLoadBalancer{
+		Store:    engine,
+		Clock:    clock,
+		Strategy: services.EqualShareAllocationStrategy{},
+		Sites: []*services.Site{
+			{
+				Id:                    "depot",
+				MaxCurrent:            40,
+				MinTransactionCurrent: 6,
+				MaxTransactionCurrent: 32,
+				ChargeStationIds:      []string{"cs001"},
+			},
+		},
+		V16CallMaker: v16CallMaker,
+	}
+
+	// the transaction already has a profile so the load balancer doesn't add one
+	err = loadBalancer.TransactionStarted(ctx, "cs001", 2, "5678")
+	require.NoError(t, err)
+	assert.Len(t, v16CallMaker.requests["cs001"], 0)
+
+	err = loadBalancer.TransactionStarted(ctx, "cs001", 1, "1234")
+	require.NoError(t, err)
+	require.Len(t, v16CallMaker.requests["cs001"], 1)
+	req16 := v16CallMaker.requests["cs001"][0].(*ocpp16.SetChargingProfileJson)
+	assert.Equal(t, makePtr(1234), req16.CsChargingProfiles.TransactionId)
+
+	err = loadBalancer.TransactionEnded(ctx, "cs001", "5678")
+	require.NoError(t, err)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, profiles)
+	require.Len(t, profiles.ChargingProfiles, 2)
+	assert.Equal(t, userProfile, profiles.ChargingProfiles[0])
+	assert.True(t, profiles.ChargingProfiles[1].LoadBalanced)
+}
@@ -0,0 +1,451 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// OcpiTariffService implements TariffService by pricing transactions with the
+// OCPI tariffs held in the store. The tariff for a transaction is the one
+// assigned to the group of the token used for the transaction, else the one
+// assigned to the EVSE, else the one assigned to the location and otherwise
+// the default tariff. The most recently updated tariff wins if more than one
+// tariff is assigned at the same level. EVSEs are mapped to charge stations by
+// the EvseMapping service or, if there is none, by a PatternEvseMappingService
+// with the default patterns.
+type OcpiTariffService struct {
+	Store       store.Engine
+	EvseMapping EvseMappingService
+}
+
+// TransactionPrice is the cost of a transaction in the currency of the tariff
+type TransactionPrice struct {
+	TariffId string
+	Currency string
+	ExclVat  float64
+	InclVat  float64
+}
+
+func (s OcpiTariffService) CalculateCost(ctx context.Context, transaction *store.Transaction) (float64, error) {
+	price, err := s.PriceTransaction(ctx, transaction)
+	if err != nil {
+		return 0, err
+	}
+	return price.InclVat, nil
+}
+
+// PriceTransaction selects the tariff for the transaction and evaluates it
+// against the transaction's meter values.
+func (s OcpiTariffService) PriceTransaction(ctx context.Context, transaction *store.Transaction) (*TransactionPrice, error) {
+	if transaction == nil {
+		return nil, errors.New("no transaction provided")
+	}
+	usage, err := newChargingUsage(transaction.MeterValues)
+	if err != nil {
+		return nil, err
+	}
+	tariff, err := s.FindTariff(ctx, transaction, usage.start)
+	if err != nil {
+		return nil, err
+	}
+	if tariff == nil {
+		return nil, fmt.Errorf("no tariff for transaction %s", transaction.TransactionId)
+	}
+	return priceUsage(tariff, usage), nil
+}
+
+// FindTariff returns the tariff that applies to a transaction started at the
+// given time: it returns nil if there is no such tariff.
+func (s OcpiTariffService) FindTariff(ctx context.Context, transaction *store.Transaction, startedAt time.Time) (*store.Tariff, error) {
+	var groupId string
+	if transaction.IdToken != "" {
+		tok, err := s.Store.LookupToken(ctx, transaction.IdToken)
+		if err != nil {
+			return nil, fmt.Errorf("lookup token %s: %w", transaction.IdToken, err)
+		}
+		if tok != nil && tok.GroupId != nil {
+			groupId = *tok.GroupId
+		}
+	}
+	locationId, evseUid, err := s.findChargeStationEvse(ctx, transaction.ChargeStationId)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		tokenGroupLevel = iota
+		evseLevel
+		locationLevel
+		defaultLevel
+		unassigned
+	)
+	level := func(tariff *store.Tariff) int {
+		switch {
+		case groupId != "" && slices.Contains(tariff.TokenGroupIds, groupId):
+			return tokenGroupLevel
+		case evseUid != "" && slices.Contains(tariff.EvseUids, evseUid):
+			return evseLevel
+		case locationId != "" && slices.Contains(tariff.LocationIds, locationId):
+			return locationLevel
+		case len(tariff.TokenGroupIds) == 0 && len(tariff.EvseUids) == 0 && len(tariff.LocationIds) == 0:
+			return defaultLevel
+		}
+		return unassigned
+	}
+
+	const pageSize = 100
+	var selected *store.Tariff
+	selectedLevel := unassigned
+	for offset := 0; ; offset += pageSize {
+		tariffs, total, err := s.Store.ListTariffs(ctx, nil, nil, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("list tariffs: %w", err)
+		}
+		// tariffs are listed by last updated so a later tariff replaces an
+		// earlier one at the same level
+		for _, tariff := range tariffs {
+			if !tariffValidAt(tariff, startedAt) {
+				continue
+			}
+			if l := level(tariff); l != unassigned && l <= selectedLevel {
+				selected, selectedLevel = tariff, l
+			}
+		}
+		if offset+pageSize >= total {
+			return selected, nil
+		}
+	}
+}
+
+func tariffValidAt(tariff *store.Tariff, t time.Time) bool {
+	if tariff.StartDateTime != nil && t.Before(*tariff.StartDateTime) {
+		return false
+	}
+	return tariff.EndDateTime == nil || t.Before(*tariff.EndDateTime)
+}
+
+// findChargeStationEvse returns the location id and EVSE uid of the charge
+// station: they are empty if the charge station is not part of a location.
+func (s OcpiTariffService) findChargeStationEvse(ctx context.Context, chargeStationId string) (string, string, error) {
+	evseMapping := s.EvseMapping
+	if evseMapping == nil {
+		evseMapping = NewDefaultEvseMappingService(s.Store)
+	}
+
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		locations, total, err := s.Store.ListLocations(ctx, nil, nil, offset, pageSize)
+		if err != nil {
+			return "", "", fmt.Errorf("list locations: %w", err)
+		}
+		for _, location := range locations {
+			if location.Evses == nil {
+				continue
+			}
+			for _, evse := range *location.Evses {
+				mapping, err := evseMapping.MapEvse(ctx, location.Id, &evse)
+				if err != nil {
+					return "", "", fmt.Errorf("map evse %s: %w", evse.Uid, err)
+				}
+				if mapping != nil && mapping.ChargeStationId == chargeStationId {
+					return location.Id, evse.Uid, nil
+				}
+			}
+		}
+		if offset+pageSize >= total {
+			return "", "", nil
+		}
+	}
+}
+
+// chargingPeriod is the time between two meter values of a transaction
+type chargingPeriod struct {
+	start    time.Time
+	duration time.Duration
+	// energyBefore is the energy delivered before the start of the period in Wh
+	energyBefore float64
+	// energy is the energy delivered in the period in Wh
+	energy float64
+}
+
+// charging returns true if energy was delivered in the period: the vehicle is
+// parked otherwise
+func (p chargingPeriod) charging() bool {
+	return p.energy > 0
+}
+
+// power returns the average power delivered in the period in kW
+func (p chargingPeriod) power() float64 {
+	if p.duration <= 0 {
+		return 0
+	}
+	return p.energy / p.duration.Hours() / 1000
+}
+
+type chargingUsage struct {
+	start   time.Time
+	periods []chargingPeriod
+}
+
+// newChargingUsage splits a transaction into periods between its meter values.
+// As for BasicKwhTariffService, an outlet energy reading in the Transaction.End
+// context holds the energy delivered by the whole transaction. Otherwise, the
+// energy delivered is derived from the readings of the energy register.
+func newChargingUsage(meterValues []store.MeterValue) (chargingUsage, error) {
+	type reading struct {
+		timestamp time.Time
+		wh        float64
+		register  bool
+	}
+	var readings []reading
+	var endTotal *reading
+	for _, meterValue := range meterValues {
+		timestamp, err := time.Parse(time.RFC3339, meterValue.Timestamp)
+		if err != nil {
+			continue
+		}
+		r := reading{timestamp: timestamp.UTC()}
+		for _, sampledValue := range meterValue.SampledValues {
+			if !isEnergyRegisterReading(sampledValue) {
+				continue
+			}
+			if sampledValue.Context != nil && *sampledValue.Context == "Transaction.End" &&
+				sampledValue.Location != nil && *sampledValue.Location == "Outlet" {
+				endTotal = &reading{timestamp: r.timestamp, wh: wattHours(sampledValue)}
+				continue
+			}
+			r.wh = wattHours(sampledValue)
+			r.register = true
+		}
+		readings = append(readings, r)
+	}
+	if len(readings) == 0 {
+		return chargingUsage{}, errors.New("no meter values found in transaction")
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].timestamp.Before(readings[j].timestamp)
+	})
+
+	// energy holds the energy delivered since the start of the transaction at
+	// each reading: readings without an energy register value carry the
+	// previous value forward
+	energy := make([]float64, len(readings))
+	var first *float64
+	var previous float64
+	for i, r := range readings {
+		if r.register {
+			if first == nil {
+				first = &readings[i].wh
+			}
+			previous = r.wh - *first
+		}
+		energy[i] = previous
+	}
+	if endTotal != nil {
+		last := len(readings) - 1
+		for i := range readings {
+			if !readings[i].timestamp.Before(endTotal.timestamp) {
+				last = i
+				break
+			}
+		}
+		readings = append(readings[:last], reading{timestamp: endTotal.timestamp})
+		energy = append(energy[:last], endTotal.wh)
+	}
+
+	usage := chargingUsage{start: readings[0].timestamp}
+	for i := 0; i+1 < len(readings); i++ {
+		usage.periods = append(usage.periods, chargingPeriod{
+			start:        readings[i].timestamp,
+			duration:     readings[i+1].timestamp.Sub(readings[i].timestamp),
+			energyBefore: energy[i],
+			energy:       energy[i+1] - energy[i],
+		})
+	}
+	return usage, nil
+}
+
+// isEnergyRegisterReading returns true if the sampled value is a reading of the
+// total active energy imported at the outlet. The meter start of an OCPP 1.6
+// transaction is recorded with the MeterValue measurand.
+func isEnergyRegisterReading(sampledValue store.SampledValue) bool {
+	if sampledValue.SignedDataOnly {
+		return false
+	}
+	if sampledValue.Measurand != nil && *sampledValue.Measurand != "Energy.Active.Import.Register" &&
+		*sampledValue.Measurand != "MeterValue" {
+		return false
+	}
+	if sampledValue.Phase != nil {
+		return false
+	}
+	return sampledValue.Location == nil || *sampledValue.Location == "Outlet"
+}
+
+func wattHours(sampledValue store.SampledValue) float64 {
+	value := sampledValue.Value
+	if sampledValue.UnitOfMeasure != nil {
+		value *= math.Pow10(sampledValue.UnitOfMeasure.Multipler)
+		if sampledValue.UnitOfMeasure.Unit == "kWh" {
+			value *= 1000
+		}
+	}
+	return value
+}
+
+// componentKey identifies a price component of a tariff
+type componentKey struct {
+	element, component int
+}
+
+// priceUsage evaluates the tariff against the usage. Restrictions are evaluated
+// in UTC at the start of each period: the first element with a component for a
+// dimension whose restrictions match prices that dimension for the period. The
+// step size is applied to the total volume priced by each component.
+func priceUsage(tariff *store.Tariff, usage chargingUsage) *TransactionPrice {
+	volumes := make(map[componentKey]float64)
+
+	if key, ok := findComponent(tariff, "FLAT", chargingPeriod{start: usage.start}, 0); ok {
+		volumes[key] = 1
+	}
+	for _, period := range usage.periods {
+		if period.duration <= 0 {
+			continue
+		}
+		elapsed := period.start.Sub(usage.start)
+		if key, ok := findComponent(tariff, "ENERGY", period, elapsed); ok {
+			volumes[key] += period.energy
+		}
+		timeType := "PARKING_TIME"
+		if period.charging() {
+			timeType = "TIME"
+		}
+		if key, ok := findComponent(tariff, timeType, period, elapsed); ok {
+			volumes[key] += period.duration.Seconds()
+		}
+	}
+
+	price := &TransactionPrice{
+		TariffId: tariff.Id,
+		Currency: tariff.Currency,
+	}
+	for key, volume := range volumes {
+		component := tariff.Elements[key.element].PriceComponents[key.component]
+		if component.StepSize > 0 {
+			volume = math.Ceil(volume/float64(component.StepSize)) * float64(component.StepSize)
+		}
+		var cost float64
+		switch component.Type {
+		case "ENERGY":
+			cost = component.Price * volume / 1000
+		case "TIME", "PARKING_TIME":
+			cost = component.Price * volume / 3600
+		default:
+			cost = component.Price * volume
+		}
+		price.ExclVat += cost
+		if component.Vat != nil {
+			cost *= 1 + *component.Vat/100
+		}
+		price.InclVat += cost
+	}
+
+	limit := func(total float64) {
+		if price.ExclVat > 0 {
+			price.InclVat *= total / price.ExclVat
+		} else {
+			price.InclVat = total
+		}
+		price.ExclVat = total
+	}
+	if tariff.MinPrice != nil && price.ExclVat < *tariff.MinPrice {
+		limit(*tariff.MinPrice)
+	}
+	if tariff.MaxPrice != nil && price.ExclVat > *tariff.MaxPrice {
+		limit(*tariff.MaxPrice)
+	}
+	return price
+}
+
+func findComponent(tariff *store.Tariff, dimension string, period chargingPeriod, elapsed time.Duration) (componentKey, bool) {
+	for i, element := range tariff.Elements {
+		for j, component := range element.PriceComponents {
+			if component.Type != dimension {
+				continue
+			}
+			if element.Restrictions == nil || restrictionsMatch(element.Restrictions, period, elapsed) {
+				return componentKey{element: i, component: j}, true
+			}
+			break
+		}
+	}
+	return componentKey{}, false
+}
+
+func restrictionsMatch(restrictions *store.TariffRestrictions, period chargingPeriod, elapsed time.Duration) bool {
+	timeOfDay := period.start.Format("15:04")
+	switch {
+	case restrictions.StartTime != "" && restrictions.EndTime != "" && restrictions.EndTime < restrictions.StartTime:
+		// the restriction spans midnight
+		if timeOfDay < restrictions.StartTime && timeOfDay >= restrictions.EndTime {
+			return false
+		}
+	default:
+		if restrictions.StartTime != "" && timeOfDay < restrictions.StartTime {
+			return false
+		}
+		if restrictions.EndTime != "" && timeOfDay >= restrictions.EndTime {
+			return false
+		}
+	}
+
+	date := period.start.Format(time.DateOnly)
+	if restrictions.StartDate != "" && date < restrictions.StartDate {
+		return false
+	}
+	if restrictions.EndDate != "" && date >= restrictions.EndDate {
+		return false
+	}
+
+	kwh := period.energyBefore / 1000
+	if restrictions.MinKwh != nil && kwh < *restrictions.MinKwh {
+		return false
+	}
+	if restrictions.MaxKwh != nil && kwh >= *restrictions.MaxKwh {
+		return false
+	}
+
+	power := period.power()
+	if restrictions.MinPower != nil && power < *restrictions.MinPower {
+		return false
+	}
+	if restrictions.MaxPower != nil && power >= *restrictions.MaxPower {
+		return false
+	}
+
+	seconds := int(elapsed.Seconds())
+	if restrictions.MinDuration != nil && seconds < *restrictions.MinDuration {
+		return false
+	}
+	if restrictions.MaxDuration != nil && seconds >= *restrictions.MaxDuration {
+		return false
+	}
+
+	if len(restrictions.DayOfWeek) > 0 {
+		day := strings.ToUpper(period.start.Weekday().String())
+		if !slices.Contains(restrictions.DayOfWeek, day) {
+			return false
+		}
+	}
+	return true
+}
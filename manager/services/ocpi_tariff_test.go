@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+)
+
+func registerReading(timestamp string, wh float64) store.MeterValue {
+	return store.MeterValue{
+		Timestamp: timestamp,
+		SampledValues: []store.SampledValue{
+			{
+				Measurand:     makePtr("Energy.Active.Import.Register"),
+				UnitOfMeasure: &store.UnitOfMeasure{Unit: "Wh"},
+				Value:         wh,
+			},
+		},
+	}
+}
+
+func priceTransaction(t *testing.T, tariff *store.Tariff, meterValues ...store.MeterValue) *services.TransactionPrice {
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetTariff(context.Background(), tariff)
+	require.NoError(t, err)
+
+	tariffService := services.OcpiTariffService{Store: engine}
+	price, err := tariffService.PriceTransaction(context.Background(), &store.Transaction{
+		ChargeStationId: "cs001",
+		TransactionId:   "tx001",
+		MeterValues:     meterValues,
+	})
+	require.NoError(t, err)
+	return price
+}
+
+func TestOcpiTariffServicePricesTimeOfDayAndParking(t *testing.T) {
+	tariff := &store.Tariff{
+		Id:       "tariff001",
+		Currency: "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.30, Vat: makePtr(20.0)},
+				},
+				Restrictions: &store.TariffRestrictions{StartTime: "07:00", EndTime: "19:00"},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.20},
+					{Type: "PARKING_TIME", Price: 6},
+				},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "FLAT", Price: 1},
+				},
+			},
+		},
+	}
+
+	price := priceTransaction(t, tariff,
+		registerReading("2024-01-01T18:00:00Z", 0),
+		registerReading("2024-01-01T18:30:00Z", 5000),
+		registerReading("2024-01-01T19:00:00Z", 9000),
+		registerReading("2024-01-01T19:30:00Z", 9000))
+
+	assert.Equal(t, "tariff001", price.TariffId)
+	assert.Equal(t, "EUR", price.Currency)
+	// 9 kWh during the day, half an hour parked and the flat fee
+	assert.InDelta(t, 2.7+3+1, price.ExclVat, 0.0001)
+	assert.InDelta(t, 2.7*1.2+3+1, price.InclVat, 0.0001)
+}
+
+func TestOcpiTariffServicePricesEnergyStepsAndKwhRestrictions(t *testing.T) {
+	tariff := &store.Tariff{
+		Id:       "tariff001",
+		Currency: "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.40, StepSize: 1},
+				},
+				Restrictions: &store.TariffRestrictions{MaxKwh: makePtr(4.0)},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.20, StepSize: 500},
+				},
+			},
+		},
+	}
+
+	price := priceTransaction(t, tariff,
+		registerReading("2024-01-01T10:00:00Z", 1000),
+		registerReading("2024-01-01T10:30:00Z", 5000),
+		registerReading("2024-01-01T11:00:00Z", 9200))
+
+	// the first 4 kWh are expensive, the remaining 4.2 kWh are billed as 4.5 kWh
+	assert.InDelta(t, 1.6+0.9, price.ExclVat, 0.0001)
+	assert.InDelta(t, 1.6+0.9, price.InclVat, 0.0001)
+}
+
+func TestOcpiTariffServicePricesTransactionEndEnergy(t *testing.T) {
+	tariff := &store.Tariff{
+		Id:       "tariff001",
+		Currency: "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.55},
+					{Type: "TIME", Price: 1},
+				},
+			},
+		},
+	}
+
+	price := priceTransaction(t, tariff,
+		store.MeterValue{
+			Timestamp: "2024-01-01T10:00:00Z",
+			SampledValues: []store.SampledValue{
+				{
+					Context:   makePtr("Transaction.Begin"),
+					Measurand: makePtr("MeterValue"),
+					Location:  makePtr("Outlet"),
+					Value:     1000,
+				},
+			},
+		},
+		store.MeterValue{
+			Timestamp: "2024-01-01T11:00:00Z",
+			SampledValues: []store.SampledValue{
+				{
+					Context:   makePtr("Transaction.End"),
+					Measurand: makePtr("Energy.Active.Import.Register"),
+					Location:  makePtr("Outlet"),
+					Value:     7500,
+				},
+			},
+		})
+
+	assert.InDelta(t, 4.125+1, price.ExclVat, 0.0001)
+}
+
+func TestOcpiTariffServicePricesDayOfWeekDurationAndPower(t *testing.T) {
+	tariff := &store.Tariff{
+		Id:       "tariff001",
+		Currency: "EUR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "TIME", Price: 10},
+				},
+				Restrictions: &store.TariffRestrictions{DayOfWeek: []string{"SATURDAY", "SUNDAY"}},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "TIME", Price: 4},
+				},
+				Restrictions: &store.TariffRestrictions{MinPower: makePtr(20.0)},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "TIME", Price: 2},
+				},
+				Restrictions: &store.TariffRestrictions{MinDuration: makePtr(3600)},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "TIME", Price: 1},
+				},
+			},
+		},
+	}
+
+	// 2024-01-01 is a Monday
+	price := priceTransaction(t, tariff,
+		registerReading("2024-01-01T10:00:00Z", 0),
+		registerReading("2024-01-01T10:30:00Z", 11000),
+		registerReading("2024-01-01T11:00:00Z", 12000),
+		registerReading("2024-01-01T11:30:00Z", 13000))
+
+	// half an hour at 22 kW, half an hour at 2 kW and half an hour after the
+	// first hour
+	assert.InDelta(t, 2+0.5+1, price.ExclVat, 0.0001)
+}
+
+func TestOcpiTariffServiceLimitsPrice(t *testing.T) {
+	tariff := &store.Tariff{
+		Id:       "tariff001",
+		Currency: "EUR",
+		MinPrice: makePtr(2.0),
+		MaxPrice: makePtr(5.0),
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 1, Vat: makePtr(10.0)},
+				},
+			},
+		},
+	}
+
+	price := priceTransaction(t, tariff,
+		registerReading("2024-01-01T10:00:00Z", 0),
+		registerReading("2024-01-01T11:00:00Z", 1000))
+	assert.InDelta(t, 2, price.ExclVat, 0.0001)
+	assert.InDelta(t, 2.2, price.InclVat, 0.0001)
+
+	price = priceTransaction(t, tariff,
+		registerReading("2024-01-01T10:00:00Z", 0),
+		registerReading("2024-01-01T11:00:00Z", 10000))
+	assert.InDelta(t, 5, price.ExclVat, 0.0001)
+	assert.InDelta(t, 5.5, price.InclVat, 0.0001)
+}
+
+func TestOcpiTariffServiceFindsAssignedTariff(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	tariffService := services.OcpiTariffService{Store: engine}
+
+	err := engine.SetLocation(ctx, &store.Location{
+		Id: "loc001",
+		Evses: &[]store.Evse{
+			{Uid: "GBTWKEcs001"},
+			{Uid: "GBTWKEcs002"},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.SetToken(ctx, &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "DEADBEEF",
+		ContractId:  "GBTWKTWTW000018",
+		GroupId:     makePtr("fleet"),
+		Valid:       true,
+	})
+	require.NoError(t, err)
+
+	lastUpdated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, tariff := range []*store.Tariff{
+		{Id: "default"},
+		{Id: "location", LocationIds: []string{"loc001"}},
+		{Id: "evse", EvseUids: []string{"GBTWKEcs002"}},
+		{Id: "fleet", TokenGroupIds: []string{"fleet"}},
+		{Id: "expired", EvseUids: []string{"GBTWKEcs001"}, EndDateTime: &expired},
+		{Id: "other", LocationIds: []string{"loc002"}},
+	} {
+		tariff.LastUpdated = lastUpdated
+		err = engine.SetTariff(ctx, tariff)
+		require.NoError(t, err)
+	}
+
+	startedAt := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	tariffId := func(chargeStationId, idToken string) string {
+		tariff, err := tariffService.FindTariff(ctx, &store.Transaction{
+			ChargeStationId: chargeStationId,
+			IdToken:         idToken,
+		}, startedAt)
+		require.NoError(t, err)
+		require.NotNil(t, tariff)
+		return tariff.Id
+	}
+
+	assert.Equal(t, "fleet", tariffId("cs001", "DEADBEEF"))
+	assert.Equal(t, "evse", tariffId("cs002", "CAFEBABE"))
+	assert.Equal(t, "location", tariffId("cs001", "CAFEBABE"))
+	assert.Equal(t, "default", tariffId("cs003", ""))
+}
+
+func TestOcpiTariffServiceErrorsWithoutTariff(t *testing.T) {
+	tariffService := services.OcpiTariffService{Store: inmemory.NewStore(clock.RealClock{})}
+
+	cost, err := tariffService.CalculateCost(context.Background(), &store.Transaction{
+		ChargeStationId: "cs001",
+		TransactionId:   "tx001",
+		MeterValues: []store.MeterValue{
+			registerReading("2024-01-01T10:00:00Z", 0),
+		},
+	})
+	assert.ErrorContains(t, err, "no tariff for transaction tx001")
+	assert.Equal(t, 0.0, cost)
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+// ErrPublishQueueFull is returned when a push is queued whilst the queue of
+// the worker that would send it is full.
+var ErrPublishQueueFull = errors.New("publish queue is full")
+
+// PublishQueue sends the pushes to roaming partners in the background so that
+// a roaming partner that is slow, or cannot be reached, does not hold up the
+// handling of the charge station's messages. Each push is bounded by the
+// timeout. The pushes for the same charge station are sent by the same worker
+// in the order that they were queued.
+type PublishQueue struct {
+	timeout time.Duration
+	workers []chan publishTask
+}
+
+type publishTask struct {
+	ctx             context.Context
+	chargeStationId string
+	name            string
+	publish         func(context.Context) error
+}
+
+// NewPublishQueue starts the workers: each worker queues up to size pushes.
+func NewPublishQueue(workers, size int, timeout time.Duration) *PublishQueue {
+	q := &PublishQueue{
+		timeout: timeout,
+		workers: make([]chan publishTask, workers),
+	}
+	for i := range q.workers {
+		q.workers[i] = make(chan publishTask, size)
+		go q.run(q.workers[i])
+	}
+	return q
+}
+
+// Enqueue queues the push for the charge station. The push is sent with a
+// context that carries the values of ctx but is not cancelled with it. It
+// returns ErrPublishQueueFull, rather than waiting, if the queue is full.
+func (q *PublishQueue) Enqueue(ctx context.Context, chargeStationId, name string, publish func(context.Context) error) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(chargeStationId))
+	worker := q.workers[h.Sum32()%uint32(len(q.workers))]
+
+	select {
+	case worker <- publishTask{
+		ctx:             context.WithoutCancel(ctx),
+		chargeStationId: chargeStationId,
+		name:            name,
+		publish:         publish,
+	}:
+		return nil
+	default:
+		return ErrPublishQueueFull
+	}
+}
+
+func (q *PublishQueue) run(tasks <-chan publishTask) {
+	for task := range tasks {
+		ctx, cancel := context.WithTimeout(task.ctx, q.timeout)
+		err := task.publish(ctx)
+		cancel()
+		if err != nil {
+			slog.Error("publishing to roaming partners", slog.String("push", task.name),
+				slog.String("chargeStationId", task.chargeStationId), slog.String("err", err.Error()))
+		}
+	}
+}
+
+// AsyncSessionPublisher is a SessionPublisher that queues the sessions and CDRs
+// to be sent by the Publisher: the errors of the Publisher are logged.
+type AsyncSessionPublisher struct {
+	Publisher SessionPublisher
+	Queue     *PublishQueue
+}
+
+func (p AsyncSessionPublisher) PushSession(ctx context.Context, transaction *store.Transaction) error {
+	return p.Queue.Enqueue(ctx, transaction.ChargeStationId, "session", func(ctx context.Context) error {
+		return p.Publisher.PushSession(ctx, transaction)
+	})
+}
+
+func (p AsyncSessionPublisher) PushSessionUpdate(ctx context.Context, transaction *store.Transaction) error {
+	return p.Queue.Enqueue(ctx, transaction.ChargeStationId, "session update", func(ctx context.Context) error {
+		return p.Publisher.PushSessionUpdate(ctx, transaction)
+	})
+}
+
+func (p AsyncSessionPublisher) PushCdr(ctx context.Context, transaction *store.Transaction) error {
+	return p.Queue.Enqueue(ctx, transaction.ChargeStationId, "cdr", func(ctx context.Context) error {
+		return p.Publisher.PushCdr(ctx, transaction)
+	})
+}
+
+// AsyncEvseStatusPublisher is an EvseStatusPublisher that queues the EVSE
+// statuses to be sent by the Publisher: the errors of the Publisher are logged.
+type AsyncEvseStatusPublisher struct {
+	Publisher EvseStatusPublisher
+	Queue     *PublishQueue
+}
+
+func (p AsyncEvseStatusPublisher) PushEvseStatus(ctx context.Context, chargeStationId string) error {
+	return p.Queue.Enqueue(ctx, chargeStationId, "evse status", func(ctx context.Context) error {
+		return p.Publisher.PushEvseStatus(ctx, chargeStationId)
+	})
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"testing"
+	"time"
+)
+
+type blockingSessionPublisher struct {
+	pushed  chan string
+	release chan struct{}
+}
+
+func (p *blockingSessionPublisher) push(ctx context.Context, name string) error {
+	select {
+	case <-p.release:
+		p.pushed <- name
+		return nil
+	case <-ctx.Done():
+		p.pushed <- name + " timed out"
+		return ctx.Err()
+	}
+}
+
+func (p *blockingSessionPublisher) PushSession(ctx context.Context, transaction *store.Transaction) error {
+	return p.push(ctx, "session "+transaction.TransactionId)
+}
+
+func (p *blockingSessionPublisher) PushSessionUpdate(ctx context.Context, transaction *store.Transaction) error {
+	return p.push(ctx, "update "+transaction.TransactionId)
+}
+
+func (p *blockingSessionPublisher) PushCdr(ctx context.Context, transaction *store.Transaction) error {
+	return p.push(ctx, "cdr "+transaction.TransactionId)
+}
+
+func receivePushes(t *testing.T, pushed <-chan string, count int) []string {
+	var names []string
+	for i := 0; i < count; i++ {
+		select {
+		case name := <-pushed:
+			names = append(names, name)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for push")
+		}
+	}
+	return names
+}
+
+func TestAsyncSessionPublisherDoesNotWaitForThePush(t *testing.T) {
+	publisher := &blockingSessionPublisher{
+		pushed:  make(chan string, 10),
+		release: make(chan struct{}),
+	}
+	asyncPublisher := services.AsyncSessionPublisher{
+		Publisher: publisher,
+		Queue:     services.NewPublishQueue(2, 10, time.Minute),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transaction := &store.Transaction{ChargeStationId: "cs001", TransactionId: "1234"}
+	require.NoError(t, asyncPublisher.PushSession(ctx, transaction))
+	require.NoError(t, asyncPublisher.PushSessionUpdate(ctx, transaction))
+	require.NoError(t, asyncPublisher.PushSessionUpdate(ctx, transaction))
+	require.NoError(t, asyncPublisher.PushCdr(ctx, transaction))
+	// the pushes are not cancelled when the message has been handled
+	cancel()
+
+	close(publisher.release)
+	assert.Equal(t, []string{"session 1234", "update 1234", "update 1234", "cdr 1234"}, receivePushes(t, publisher.pushed, 4))
+}
+
+func TestPublishQueueAbandonsPushAfterTimeout(t *testing.T) {
+	publisher := &blockingSessionPublisher{
+		pushed:  make(chan string, 10),
+		release: make(chan struct{}),
+	}
+	asyncPublisher := services.AsyncSessionPublisher{
+		Publisher: publisher,
+		Queue:     services.NewPublishQueue(1, 10, 50*time.Millisecond),
+	}
+
+	transaction := &store.Transaction{ChargeStationId: "cs001", TransactionId: "1234"}
+	require.NoError(t, asyncPublisher.PushSession(context.Background(), transaction))
+	require.NoError(t, asyncPublisher.PushSessionUpdate(context.Background(), transaction))
+
+	assert.Equal(t, []string{"session 1234 timed out", "update 1234 timed out"}, receivePushes(t, publisher.pushed, 2))
+}
+
+func TestPublishQueueRejectsPushWhenFull(t *testing.T) {
+	queue := services.NewPublishQueue(1, 1, time.Minute)
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 2)
+	push := func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	require.NoError(t, queue.Enqueue(context.Background(), "cs001", "test", push))
+	<-started
+	require.NoError(t, queue.Enqueue(context.Background(), "cs001", "test", push))
+	err := queue.Enqueue(context.Background(), "cs001", "test", push)
+	assert.True(t, errors.Is(err, services.ErrPublishQueueFull))
+}
+
+type blockingEvseStatusPublisher struct {
+	blockingSessionPublisher
+}
+
+func (p *blockingEvseStatusPublisher) PushEvseStatus(ctx context.Context, chargeStationId string) error {
+	return p.push(ctx, "evse status "+chargeStationId)
+}
+
+func TestAsyncEvseStatusPublisherDoesNotWaitForThePush(t *testing.T) {
+	publisher := &blockingEvseStatusPublisher{
+		blockingSessionPublisher{
+			pushed:  make(chan string, 10),
+			release: make(chan struct{}),
+		},
+	}
+	queue := services.NewPublishQueue(2, 10, time.Minute)
+	asyncPublisher := services.AsyncEvseStatusPublisher{Publisher: publisher, Queue: queue}
+	asyncSessionPublisher := services.AsyncSessionPublisher{Publisher: publisher, Queue: queue}
+
+	transaction := &store.Transaction{ChargeStationId: "cs001", TransactionId: "1234"}
+	require.NoError(t, asyncPublisher.PushEvseStatus(context.Background(), "cs001"))
+	require.NoError(t, asyncSessionPublisher.PushSession(context.Background(), transaction))
+	require.NoError(t, asyncPublisher.PushEvseStatus(context.Background(), "cs001"))
+
+	close(publisher.release)
+	assert.Equal(t, []string{"evse status cs001", "session 1234", "evse status cs001"}, receivePushes(t, publisher.pushed, 3))
+}
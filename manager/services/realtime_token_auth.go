@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
+)
+
+// TokenAuthorizer authorizes tokens in real-time with the roaming partners that
+// issue them.
+type TokenAuthorizer interface {
+	// AuthorizeToken asks the issuer of the token whether it can be used at the
+	// charge station. The known token is nil if the token has not been received
+	// from its issuer. A nil authorization is returned if no issuer knows the
+	// token and an error is returned if the issuer cannot be reached.
+	AuthorizeToken(ctx context.Context, chargeStationId string, token ocpp201.IdTokenType, knownToken *store.Token) (*TokenAuthorization, error)
+}
+
+// TokenAuthorization is the result of a real-time authorization
+type TokenAuthorization struct {
+	Status ocpp201.AuthorizationStatusEnumType
+	// Token is the token as known by its issuer
+	Token *store.Token
+}
+
+// DefaultRealTimeAuthTimeout is how long the issuer of a token is given to
+// authorize it: the charge station is waiting for the authorization.
+const DefaultRealTimeAuthTimeout = 5 * time.Second
+
+// DefaultRejectedTokenTtl is how long a token that was not accepted by its
+// issuer is remembered for.
+const DefaultRejectedTokenTtl = time.Minute
+
+// RealTimeTokenAuthService authorizes tokens with their issuers when the tokens
+// are not known locally or their whitelist type requires it:
+//
+//   - ALWAYS and ALLOWED tokens are authorized locally
+//   - ALLOWED_OFFLINE tokens are authorized in real-time and locally if the
+//     issuer cannot be reached
+//   - NEVER tokens are only authorized in real-time
+//
+// The tokens returned by the issuers are stored so that they are known locally
+// from then on. The tokens that the issuers did not accept are remembered for
+// the RejectedTokenTtl so that the issuers are not asked again each time the
+// token is presented.
+type RealTimeTokenAuthService struct {
+	// TokenAuthService authorizes the tokens locally
+	TokenAuthService TokenAuthService
+	TokenStore       store.TokenStore
+	TokenAuthorizer  TokenAuthorizer
+	Clock            clock.PassiveClock
+	// Timeout bounds the real-time authorization, defaults to
+	// DefaultRealTimeAuthTimeout
+	Timeout time.Duration
+	// FallbackStatus is the status of a token that cannot be authorized locally
+	// when its issuer cannot be reached within the Timeout, defaults to Unknown
+	FallbackStatus ocpp201.AuthorizationStatusEnumType
+	// RejectedTokenTtl defaults to DefaultRejectedTokenTtl
+	RejectedTokenTtl time.Duration
+
+	mu       sync.Mutex
+	rejected map[rejectedTokenKey]rejectedToken
+}
+
+type rejectedTokenKey struct {
+	tokenType ocpp201.IdTokenEnumType
+	idToken   string
+}
+
+type rejectedToken struct {
+	tokenInfo ocpp201.IdTokenInfoType
+	expiry    time.Time
+}
+
+func (r *RealTimeTokenAuthService) Authorize(ctx context.Context, chargeStationId string, token ocpp201.IdTokenType) ocpp201.IdTokenInfoType {
+	switch token.Type {
+	case ocpp201.IdTokenEnumTypeNoAuthorization, ocpp201.IdTokenEnumTypeCentral, ocpp201.IdTokenEnumTypeLocal:
+		return r.TokenAuthService.Authorize(ctx, chargeStationId, token)
+	}
+
+	span := trace.SpanFromContext(ctx)
+
+	foundToken, err := r.TokenStore.LookupToken(ctx, token.IdToken)
+	if err != nil {
+		span.RecordError(err)
+		return r.TokenAuthService.Authorize(ctx, chargeStationId, token)
+	}
+
+	if foundToken != nil && foundToken.CacheMode != "ALLOWED_OFFLINE" && foundToken.CacheMode != "NEVER" {
+		return r.TokenAuthService.Authorize(ctx, chargeStationId, token)
+	}
+
+	key := rejectedTokenKey{tokenType: token.Type, idToken: token.IdToken}
+	if tokenInfo, ok := r.lookupRejected(key); ok {
+		span.SetAttributes(
+			attribute.Bool("token_auth.rejected_cache", true),
+			attribute.String("token_auth.status", string(tokenInfo.Status)))
+		return tokenInfo
+	}
+
+	span.SetAttributes(attribute.Bool("token_auth.real_time", true))
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = DefaultRealTimeAuthTimeout
+	}
+	authCtx, cancel := context.WithTimeout(ctx, timeout)
+	authorization, err := r.TokenAuthorizer.AuthorizeToken(authCtx, chargeStationId, token, foundToken)
+	cancel()
+	if err != nil {
+		span.RecordError(err)
+	}
+	if authorization == nil {
+		if err != nil {
+			// the issuer cannot be reached
+			if foundToken != nil && foundToken.CacheMode == "ALLOWED_OFFLINE" {
+				return r.TokenAuthService.Authorize(ctx, chargeStationId, token)
+			}
+			status := r.FallbackStatus
+			if status == "" {
+				status = ocpp201.AuthorizationStatusEnumTypeUnknown
+			}
+			span.SetAttributes(
+				attribute.String("token_auth.status", string(status)))
+			return ocpp201.IdTokenInfoType{
+				Status: status,
+			}
+		}
+		span.SetAttributes(
+			attribute.String("token_auth.status", string(ocpp201.AuthorizationStatusEnumTypeUnknown)))
+		tokenInfo := ocpp201.IdTokenInfoType{
+			Status: ocpp201.AuthorizationStatusEnumTypeUnknown,
+		}
+		r.storeRejected(key, tokenInfo)
+		return tokenInfo
+	}
+
+	if authorization.Token != nil {
+		foundToken = authorization.Token
+		err = r.TokenStore.SetToken(ctx, foundToken)
+		if err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	var tokenInfo *ocpp201.IdTokenInfoType
+	if foundToken != nil {
+		tokenInfo = newIdTokenInfo(ctx, r.Clock, authorization.Status, foundToken)
+	} else {
+		tokenInfo = &ocpp201.IdTokenInfoType{
+			Status: authorization.Status,
+		}
+	}
+	if tokenInfo.Status != ocpp201.AuthorizationStatusEnumTypeAccepted {
+		r.storeRejected(key, *tokenInfo)
+	}
+
+	span.SetAttributes(
+		attribute.String("token_auth.status", string(tokenInfo.Status)))
+	return *tokenInfo
+}
+
+// lookupRejected returns the authorization of a token that was rejected by its
+// issuer within the RejectedTokenTtl.
+func (r *RealTimeTokenAuthService) lookupRejected(key rejectedTokenKey) (ocpp201.IdTokenInfoType, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rejected, ok := r.rejected[key]
+	if !ok {
+		return ocpp201.IdTokenInfoType{}, false
+	}
+	if !r.Clock.Now().Before(rejected.expiry) {
+		delete(r.rejected, key)
+		return ocpp201.IdTokenInfoType{}, false
+	}
+	return rejected.tokenInfo, true
+}
+
+// storeRejected remembers the authorization of a token that was rejected by its
+// issuer: the tokens that have expired are forgotten at the same time.
+func (r *RealTimeTokenAuthService) storeRejected(key rejectedTokenKey, tokenInfo ocpp201.IdTokenInfoType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.Clock.Now()
+	if r.rejected == nil {
+		r.rejected = make(map[rejectedTokenKey]rejectedToken)
+	}
+	for k, rejected := range r.rejected {
+		if !now.Before(rejected.expiry) {
+			delete(r.rejected, k)
+		}
+	}
+	ttl := r.RejectedTokenTtl
+	if ttl == 0 {
+		ttl = DefaultRejectedTokenTtl
+	}
+	r.rejected[key] = rejectedToken{
+		tokenInfo: tokenInfo,
+		expiry:    now.Add(ttl),
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+type fakeTokenAuthorizer struct {
+	authorization *services.TokenAuthorization
+	err           error
+	called        bool
+	knownToken    *store.Token
+}
+
+func (f *fakeTokenAuthorizer) AuthorizeToken(_ context.Context, _ string, _ ocpp201.IdTokenType, knownToken *store.Token) (*services.TokenAuthorization, error) {
+	f.called = true
+	f.knownToken = knownToken
+	return f.authorization, f.err
+}
+
+func setupRealTimeTokenAuthService(t *testing.T, cacheMode string, authorizer *fakeTokenAuthorizer) (*services.RealTimeTokenAuthService, store.Engine, time.Time) {
+	now := time.Now()
+	clock := fakeclock.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	if cacheMode != "" {
+		err := engine.SetToken(context.Background(), &store.Token{
+			CountryCode: "GB",
+			PartyId:     "TWK",
+			Type:        "RFID",
+			Uid:         "MYRFIDCARD",
+			ContractId:  "GBTWK012345678V",
+			Issuer:      "Zynka-tech",
+			Valid:       true,
+			CacheMode:   cacheMode,
+		})
+		require.NoError(t, err)
+	}
+
+	return &services.RealTimeTokenAuthService{
+		TokenAuthService: &services.OcppTokenAuthService{
+			TokenStore: engine,
+			Clock:      clock,
+		},
+		TokenStore:      engine,
+		TokenAuthorizer: authorizer,
+		Clock:           clock,
+	}, engine, now
+}
+
+var rfidCard = ocpp201.IdTokenType{
+	Type:    ocpp201.IdTokenEnumTypeISO14443,
+	IdToken: "MYRFIDCARD",
+}
+
+func TestRealTimeTokenAuthServiceAuthorizesAllowedTokenLocally(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{err: errors.New("unexpected call")}
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "ALLOWED", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeAccepted, tokenInfo.Status)
+	assert.False(t, authorizer.called)
+}
+
+func TestRealTimeTokenAuthServiceAuthorizesUnknownToken(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{
+		authorization: &services.TokenAuthorization{
+			Status: ocpp201.AuthorizationStatusEnumTypeAccepted,
+			Token: &store.Token{
+				CountryCode: "GB",
+				PartyId:     "TWK",
+				Type:        "RFID",
+				Uid:         "MYRFIDCARD",
+				ContractId:  "GBTWK012345678V",
+				Issuer:      "Zynka-tech",
+				Valid:       true,
+				CacheMode:   "ALLOWED",
+			},
+		},
+	}
+	tokenAuthService, engine, _ := setupRealTimeTokenAuthService(t, "", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.IdTokenInfoType{
+		Status: ocpp201.AuthorizationStatusEnumTypeAccepted,
+	}, tokenInfo)
+	assert.True(t, authorizer.called)
+	assert.Nil(t, authorizer.knownToken)
+
+	tok, err := engine.LookupToken(context.Background(), "MYRFIDCARD")
+	require.NoError(t, err)
+	require.NotNil(t, tok)
+	assert.Equal(t, "ALLOWED", tok.CacheMode)
+}
+
+func TestRealTimeTokenAuthServiceRejectsTokenUnknownToIssuers(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{}
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeUnknown, tokenInfo.Status)
+	assert.True(t, authorizer.called)
+}
+
+func TestRealTimeTokenAuthServiceAuthorizesNeverWhitelistedToken(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{
+		authorization: &services.TokenAuthorization{
+			Status: ocpp201.AuthorizationStatusEnumTypeNoCredit,
+		},
+	}
+	tokenAuthService, _, now := setupRealTimeTokenAuthService(t, "NEVER", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	expiryTime := now.Format(time.RFC3339)
+	assert.Equal(t, ocpp201.IdTokenInfoType{
+		Status:              ocpp201.AuthorizationStatusEnumTypeNoCredit,
+		CacheExpiryDateTime: &expiryTime,
+	}, tokenInfo)
+	require.NotNil(t, authorizer.knownToken)
+	assert.Equal(t, "MYRFIDCARD", authorizer.knownToken.Uid)
+}
+
+func TestRealTimeTokenAuthServiceRejectsNeverWhitelistedTokenWhenIssuerUnreachable(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{err: errors.New("connection refused")}
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "NEVER", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeUnknown, tokenInfo.Status)
+}
+
+func TestRealTimeTokenAuthServiceAuthorizesAllowedOfflineTokenLocallyWhenIssuerUnreachable(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{err: errors.New("connection refused")}
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "ALLOWED_OFFLINE", authorizer)
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeAccepted, tokenInfo.Status)
+	assert.True(t, authorizer.called)
+}
+
+// slowTokenAuthorizer is an issuer that does not answer before the
+// authorization is abandoned
+type slowTokenAuthorizer struct{}
+
+func (slowTokenAuthorizer) AuthorizeToken(ctx context.Context, _ string, _ ocpp201.IdTokenType, _ *store.Token) (*services.TokenAuthorization, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRealTimeTokenAuthServiceReturnsFallbackStatusWhenIssuerTimesOut(t *testing.T) {
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "NEVER", nil)
+	tokenAuthService.TokenAuthorizer = slowTokenAuthorizer{}
+	tokenAuthService.Timeout = 10 * time.Millisecond
+	tokenAuthService.FallbackStatus = ocpp201.AuthorizationStatusEnumTypeAccepted
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeAccepted, tokenInfo.Status)
+}
+
+func TestRealTimeTokenAuthServiceRemembersRejectedToken(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{}
+	tokenAuthService, _, now := setupRealTimeTokenAuthService(t, "", authorizer)
+	clock := fakeclock.NewFakePassiveClock(now)
+	tokenAuthService.Clock = clock
+
+	tokenInfo := tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeUnknown, tokenInfo.Status)
+	assert.True(t, authorizer.called)
+
+	authorizer.called = false
+	tokenInfo = tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeUnknown, tokenInfo.Status)
+	assert.False(t, authorizer.called)
+
+	clock.SetTime(now.Add(services.DefaultRejectedTokenTtl))
+	tokenInfo = tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+	assert.Equal(t, ocpp201.AuthorizationStatusEnumTypeUnknown, tokenInfo.Status)
+	assert.True(t, authorizer.called)
+}
+
+func TestRealTimeTokenAuthServiceDoesNotRememberTokenWhenIssuerUnreachable(t *testing.T) {
+	authorizer := &fakeTokenAuthorizer{err: errors.New("connection refused")}
+	tokenAuthService, _, _ := setupRealTimeTokenAuthService(t, "NEVER", authorizer)
+
+	tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+	authorizer.called = false
+	tokenAuthService.Authorize(context.Background(), "cs001", rfidCard)
+
+	assert.True(t, authorizer.called)
+}
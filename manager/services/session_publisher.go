@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// SessionPublisher keeps roaming partners informed about the charging sessions
+// that take place on the charge stations.
+type SessionPublisher interface {
+	// PushSession sends the complete session for the transaction. It is called
+	// when a transaction starts or ends.
+	PushSession(ctx context.Context, transaction *store.Transaction) error
+	// PushSessionUpdate sends the parts of the session that change while the
+	// transaction is in progress (energy, charging periods and status).
+	PushSessionUpdate(ctx context.Context, transaction *store.Transaction) error
+	// PushCdr creates the charge detail record for a transaction that has ended
+	// and sends it to the owner of the token used for the transaction.
+	PushCdr(ctx context.Context, transaction *store.Transaction) error
+}
@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	EncodingMethodOcmf = "OCMF"
+	EncodingMethodEdl  = "EDL"
+)
+
+// SignedMeterValueService verifies the signed meter values reported by charge
+// stations so that readings can be shown to be unaltered (as required by the
+// German Eichrecht).
+type SignedMeterValueService interface {
+	// VerifyMeterValues verifies each signed sampled value in meterValues and
+	// records the result in the status of the signed meter value.
+	VerifyMeterValues(ctx context.Context, chargeStationId string, meterValues []store.MeterValue) error
+}
+
+// BasicSignedMeterValueService verifies signed meter values against the meter
+// public keys registered for the charge station. A signed meter value is valid
+// if it can be verified with any of the keys. OCMF signed with ECDSA over the
+// NIST P-256 or P-384 curves and EDL signed with ECDSA over the NIST P-192 or
+// P-256 curves can be verified: other signed data is recorded with status
+// Unsupported.
+type BasicSignedMeterValueService struct {
+	Store store.ChargeStationMeterPublicKeyStore
+}
+
+func (s BasicSignedMeterValueService) VerifyMeterValues(ctx context.Context, chargeStationId string, meterValues []store.MeterValue) error {
+	var keys []*ecdsa.PublicKey
+	keysLoaded := false
+	for i := range meterValues {
+		for j := range meterValues[i].SampledValues {
+			signed := meterValues[i].SampledValues[j].SignedMeterValue
+			if signed == nil {
+				continue
+			}
+			if !keysLoaded {
+				var err error
+				keys, err = s.loadPublicKeys(ctx, chargeStationId)
+				if err != nil {
+					return err
+				}
+				keysLoaded = true
+			}
+			signed.Status = verifySignedMeterValue(signed, keys)
+		}
+	}
+	return nil
+}
+
+func (s BasicSignedMeterValueService) loadPublicKeys(ctx context.Context, chargeStationId string) ([]*ecdsa.PublicKey, error) {
+	meterKeys, err := s.Store.ListChargeStationMeterPublicKeys(ctx, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list meter public keys: %w", err)
+	}
+	var keys []*ecdsa.PublicKey
+	for _, meterKey := range meterKeys {
+		key, err := ParseMeterPublicKey(meterKey.PublicKey)
+		if err != nil {
+			slog.Warn("ignoring meter public key", slog.String("chargeStationId", chargeStationId),
+				slog.Int("evseId", meterKey.EvseId), slog.String("err", err.Error()))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParseMeterPublicKey parses a hex encoded ECDSA public key: either a DER
+// SubjectPublicKeyInfo or an uncompressed curve point (as reported by EDL
+// meters). NIST P-192 keys are parsed here as crypto/x509 doesn't support them.
+func ParseMeterPublicKey(publicKey string) (*ecdsa.PublicKey, error) {
+	der, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	if point, ok := uncompressedPoint(der); ok {
+		return point, nil
+	}
+	if p192Key, ok := parseP192PublicKey(der); ok {
+		return p192Key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an ECDSA key")
+	}
+	return ecdsaKey, nil
+}
+
+// pointCurves maps the length of an uncompressed curve point to its curve
+var pointCurves = map[int]elliptic.Curve{
+	49: P192(),
+	65: elliptic.P256(),
+	97: elliptic.P384(),
+}
+
+func uncompressedPoint(data []byte) (*ecdsa.PublicKey, bool) {
+	curve, ok := pointCurves[len(data)]
+	if !ok || data[0] != 4 {
+		return nil, false
+	}
+	x, y := elliptic.Unmarshal(curve, data)
+	if x == nil {
+		return nil, false
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, true
+}
+
+var (
+	oidPublicKeyEcdsa = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP192 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1}
+)
+
+// parseP192PublicKey parses a DER SubjectPublicKeyInfo holding an ECDSA P-192 key
+func parseP192PublicKey(der []byte) (*ecdsa.PublicKey, bool) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) != 0 {
+		return nil, false
+	}
+	var namedCurve asn1.ObjectIdentifier
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyEcdsa) {
+		return nil, false
+	}
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &namedCurve); err != nil ||
+		!namedCurve.Equal(oidNamedCurveP192) {
+		return nil, false
+	}
+	return uncompressedPoint(spki.PublicKey.RightAlign())
+}
+
+func verifySignedMeterValue(signed *store.SignedMeterValue, keys []*ecdsa.PublicKey) store.SignedMeterValueStatus {
+	if strings.EqualFold(signed.EncodingMethod, EncodingMethodEdl) {
+		return verifyEdl(signed.SignedMeterData, keys)
+	}
+	if !strings.EqualFold(signed.EncodingMethod, EncodingMethodOcmf) {
+		return store.SignedMeterValueStatusUnsupported
+	}
+
+	ocmf, err := ParseOcmf(signed.SignedMeterData)
+	if err != nil {
+		return store.SignedMeterValueStatusInvalid
+	}
+	curve, ok := ocmfCurves[ocmf.Signature.Algorithm]
+	if !ok || ocmf.Signature.MimeType != "application/x-der" {
+		return store.SignedMeterValueStatusUnsupported
+	}
+	signature, err := ocmf.Signature.decode()
+	if err != nil {
+		return store.SignedMeterValueStatusInvalid
+	}
+
+	digest := sha256.Sum256(ocmf.RawPayload)
+	status := store.SignedMeterValueStatusNoPublicKey
+	for _, key := range keys {
+		if key.Curve != curve {
+			continue
+		}
+		if ecdsa.VerifyASN1(key, digest[:], signature) {
+			return store.SignedMeterValueStatusValid
+		}
+		status = store.SignedMeterValueStatusInvalid
+	}
+	return status
+}
+
+// ocmfCurves maps the OCMF signature algorithms that can be verified to the
+// curve of the signing key. All the OCMF algorithms use SHA-256.
+var ocmfCurves = map[string]elliptic.Curve{
+	"ECDSA-secp256r1-SHA256": elliptic.P256(),
+	"ECDSA-secp384r1-SHA256": elliptic.P384(),
+}
+
+// Ocmf is a meter reading in the Open Charge Metering Format, which is made up
+// of a payload section and a signature section: OCMF|{payload}|{signature}.
+type Ocmf struct {
+	Payload   OcmfPayload
+	Signature OcmfSignature
+	// RawPayload is the payload section as signed by the meter
+	RawPayload []byte
+}
+
+type OcmfPayload struct {
+	FormatVersion         string        `json:"FV"`
+	GatewayIdentification string        `json:"GI"`
+	GatewaySerial         string        `json:"GS"`
+	Pagination            string        `json:"PG"`
+	MeterVendor           string        `json:"MV"`
+	MeterModel            string        `json:"MM"`
+	MeterSerial           string        `json:"MS"`
+	IdentificationStatus  bool          `json:"IS"`
+	IdentificationType    string        `json:"IT"`
+	IdentificationData    string        `json:"ID"`
+	ChargePointIdType     string        `json:"CT"`
+	ChargePointId         string        `json:"CI"`
+	Readings              []OcmfReading `json:"RD"`
+}
+
+type OcmfReading struct {
+	Time            string  `json:"TM"`
+	TransactionType string  `json:"TX"`
+	Value           float64 `json:"RV"`
+	Identifier      string  `json:"RI"`
+	Unit            string  `json:"RU"`
+	Status          string  `json:"ST"`
+}
+
+// WattHours returns the reading in Wh. It returns false if the reading is not an
+// energy reading.
+func (r OcmfReading) WattHours() (float64, bool) {
+	switch r.Unit {
+	case "kWh":
+		return r.Value * 1000, true
+	case "Wh":
+		return r.Value, true
+	default:
+		return 0, false
+	}
+}
+
+type OcmfSignature struct {
+	Algorithm string `json:"SA"`
+	Encoding  string `json:"SE"`
+	MimeType  string `json:"SM"`
+	Data      string `json:"SD"`
+}
+
+func (s OcmfSignature) decode() ([]byte, error) {
+	if s.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(s.Data)
+	}
+	return hex.DecodeString(s.Data)
+}
+
+// ParseOcmf parses OCMF signed meter data. The data may be base64 encoded as is
+// the case for OCPP 2.0.1.
+func ParseOcmf(data string) (*Ocmf, error) {
+	if !strings.HasPrefix(data, "OCMF|") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil || !strings.HasPrefix(string(decoded), "OCMF|") {
+			return nil, errors.New("not OCMF data")
+		}
+		data = string(decoded)
+	}
+
+	sections := strings.TrimPrefix(data, "OCMF|")
+	sep := strings.LastIndex(sections, "|")
+	if sep < 0 {
+		return nil, errors.New("missing OCMF signature section")
+	}
+
+	ocmf := &Ocmf{
+		RawPayload: []byte(sections[:sep]),
+		// defaults defined by the OCMF specification
+		Signature: OcmfSignature{
+			Algorithm: "ECDSA-secp256r1-SHA256",
+			Encoding:  "hex",
+			MimeType:  "application/x-der",
+		},
+	}
+	if err := json.Unmarshal(ocmf.RawPayload, &ocmf.Payload); err != nil {
+		return nil, fmt.Errorf("unmarshal OCMF payload: %w", err)
+	}
+	if err := json.Unmarshal([]byte(sections[sep+1:]), &ocmf.Signature); err != nil {
+		return nil, fmt.Errorf("unmarshal OCMF signature: %w", err)
+	}
+	return ocmf, nil
+}
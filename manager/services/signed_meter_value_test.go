@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+)
+
+const ocmfPayload = `{"FV":"1.0","GI":"ABL SBC-301","MS":"ABC123","IS":true,"IT":"ISO14443","ID":"1F2D3A4B","RD":[{"TM":"2023-06-15T15:05:00,000+0100 S","TX":"B","RV":12.345,"RI":"1-b:1.8.0","RU":"kWh","ST":"G"},{"TM":"2023-06-15T16:05:00,000+0100 S","TX":"E","RV":22.5,"RI":"1-b:1.8.0","RU":"kWh","ST":"G"}]}`
+
+func generateMeterKey(t *testing.T, curve elliptic.Curve) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, hex.EncodeToString(der)
+}
+
+func signOcmf(t *testing.T, key *ecdsa.PrivateKey, algorithm, payload string) string {
+	digest := sha256.Sum256([]byte(payload))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return fmt.Sprintf(`OCMF|%s|{"SA":"%s","SD":"%s"}`, payload, algorithm, hex.EncodeToString(signature))
+}
+
+func TestParseOcmf(t *testing.T) {
+	key, _ := generateMeterKey(t, elliptic.P256())
+	data := signOcmf(t, key, "ECDSA-secp256r1-SHA256", ocmfPayload)
+
+	for name, encoded := range map[string]string{
+		"plain":  data,
+		"base64": base64.StdEncoding.EncodeToString([]byte(data)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ocmf, err := services.ParseOcmf(encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte(ocmfPayload), ocmf.RawPayload)
+			assert.Equal(t, "ABC123", ocmf.Payload.MeterSerial)
+			assert.Equal(t, "1F2D3A4B", ocmf.Payload.IdentificationData)
+			require.Len(t, ocmf.Payload.Readings, 2)
+			wh, ok := ocmf.Payload.Readings[1].WattHours()
+			assert.True(t, ok)
+			assert.Equal(t, 22500.0, wh)
+			assert.Equal(t, "ECDSA-secp256r1-SHA256", ocmf.Signature.Algorithm)
+			assert.Equal(t, "hex", ocmf.Signature.Encoding)
+			assert.Equal(t, "application/x-der", ocmf.Signature.MimeType)
+		})
+	}
+}
+
+func TestParseOcmfRejectsInvalidData(t *testing.T) {
+	for name, data := range map[string]string{
+		"not ocmf":          "DEADBEEF",
+		"no signature":      "OCMF|{}",
+		"invalid payload":   `OCMF|{"RD":1}|{}`,
+		"invalid signature": `OCMF|{}|{"SA":1}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := services.ParseOcmf(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// generateP192MeterKey returns a P-192 key and its public key as a hex encoded
+// uncompressed point: crypto/x509 can't marshal P-192 keys.
+func generateP192MeterKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(services.P192(), rand.Reader)
+	require.NoError(t, err)
+	point := elliptic.Marshal(key.Curve, key.X, key.Y)
+	return key, hex.EncodeToString(point)
+}
+
+// encodeEdlMeterValue returns an EDL encoded meter value for a reading in Wh
+func encodeEdlMeterValue(wh uint64) []byte {
+	encoded := make([]byte, 41)
+	copy(encoded[0:10], "0901454D48")
+	binary.LittleEndian.PutUint32(encoded[10:14], 1686841500)
+	encoded[14] = 0x08
+	binary.LittleEndian.PutUint32(encoded[15:19], 3600)
+	binary.LittleEndian.PutUint32(encoded[19:23], 7)
+	copy(encoded[23:29], []byte{1, 0, 1, 8, 0, 255})
+	encoded[29] = 30
+	encoded[30] = 0xff
+	binary.LittleEndian.PutUint64(encoded[31:39], wh*10)
+	return encoded
+}
+
+func signEdl(t *testing.T, key *ecdsa.PrivateKey, signatureMethod string, encoded []byte, raw bool) string {
+	digest := sha256.Sum256(encoded)
+	var signature []byte
+	if raw {
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+	} else {
+		var err error
+		signature, err = ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+	}
+	publicKey := elliptic.Marshal(key.Curve, key.X, key.Y)
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<signedMeterValue>
+  <publicKey encoding="base64">%s</publicKey>
+  <meterValueSignature encoding="base64">%s</meterValueSignature>
+  <signatureMethod>%s</signatureMethod>
+  <encodingMethod>EDL</encodingMethod>
+  <encodedMeterValue encoding="base64">%s</encodedMeterValue>
+</signedMeterValue>`,
+		base64.StdEncoding.EncodeToString(publicKey),
+		base64.StdEncoding.EncodeToString(signature),
+		signatureMethod,
+		base64.StdEncoding.EncodeToString(encoded))
+}
+
+func TestParseEdl(t *testing.T) {
+	key, _ := generateP192MeterKey(t)
+	data := signEdl(t, key, "ECDSA192SHA256", encodeEdlMeterValue(22500), true)
+
+	for name, encoded := range map[string]string{
+		"plain":  data,
+		"base64": base64.StdEncoding.EncodeToString([]byte(data)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			edl, err := services.ParseEdl(encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, "ECDSA192SHA256", edl.SignatureMethod)
+			assert.Len(t, edl.Signature, 48)
+			assert.Equal(t, encodeEdlMeterValue(22500), edl.EncodedMeterValue)
+			assert.Equal(t, []byte("0901454D48"), edl.MeterValue.ServerId)
+			assert.Equal(t, time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC), edl.MeterValue.Timestamp)
+			assert.Equal(t, uint32(7), edl.MeterValue.Pagination)
+			wh, ok := edl.MeterValue.WattHours()
+			assert.True(t, ok)
+			assert.Equal(t, 22500.0, wh)
+		})
+	}
+}
+
+func TestParseEdlRejectsInvalidData(t *testing.T) {
+	for name, data := range map[string]string{
+		"not edl":               "DEADBEEF",
+		"not xml":               "<signedMeterValue>",
+		"wrong encoding method": `<signedMeterValue><encodingMethod>OCMF</encodingMethod></signedMeterValue>`,
+		"short meter value":     `<signedMeterValue><encodedMeterValue>AAAA</encodedMeterValue></signedMeterValue>`,
+		"invalid base64":        `<signedMeterValue><encodedMeterValue>!!</encodedMeterValue></signedMeterValue>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := services.ParseEdl(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseMeterPublicKey(t *testing.T) {
+	p192Key, p192Point := generateP192MeterKey(t)
+	p256Key, p256PublicKey := generateMeterKey(t, elliptic.P256())
+
+	// crypto/x509 can't marshal a P-192 key so the SubjectPublicKeyInfo is built here
+	params, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1})
+	require.NoError(t, err)
+	point, err := hex.DecodeString(p192Point)
+	require.NoError(t, err)
+	p192Der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		publicKey string
+		want      *ecdsa.PublicKey
+	}{
+		"P-256 subject public key info": {publicKey: p256PublicKey, want: &p256Key.PublicKey},
+		"P-192 subject public key info": {publicKey: hex.EncodeToString(p192Der), want: &p192Key.PublicKey},
+		"P-192 point":                   {publicKey: p192Point, want: &p192Key.PublicKey},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := services.ParseMeterPublicKey(tc.publicKey)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want.Curve.Params().Name, got.Curve.Params().Name)
+			assert.Equal(t, 0, tc.want.X.Cmp(got.X))
+			assert.Equal(t, 0, tc.want.Y.Cmp(got.Y))
+		})
+	}
+
+	_, err = services.ParseMeterPublicKey("DEADBEEF")
+	assert.Error(t, err)
+}
+
+func TestBasicSignedMeterValueService(t *testing.T) {
+	p256Key, p256PublicKey := generateMeterKey(t, elliptic.P256())
+	p384Key, p384PublicKey := generateMeterKey(t, elliptic.P384())
+	_, otherPublicKey := generateMeterKey(t, elliptic.P256())
+
+	signedData := signOcmf(t, p256Key, "ECDSA-secp256r1-SHA256", ocmfPayload)
+
+	p192Key, p192PublicKey := generateP192MeterKey(t)
+	edlData := signEdl(t, p192Key, "ECDSA192SHA256", encodeEdlMeterValue(22500), true)
+
+	tests := map[string]struct {
+		publicKeys     []string
+		encodingMethod string
+		data           string
+		want           store.SignedMeterValueStatus
+	}{
+		"valid": {
+			publicKeys:     []string{otherPublicKey, p256PublicKey},
+			encodingMethod: "OCMF",
+			data:           signedData,
+			want:           store.SignedMeterValueStatusValid,
+		},
+		"valid base64 encoded": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "OCMF",
+			data:           base64.StdEncoding.EncodeToString([]byte(signedData)),
+			want:           store.SignedMeterValueStatusValid,
+		},
+		"valid P-384": {
+			publicKeys:     []string{p384PublicKey},
+			encodingMethod: "OCMF",
+			data:           signOcmf(t, p384Key, "ECDSA-secp384r1-SHA256", ocmfPayload),
+			want:           store.SignedMeterValueStatusValid,
+		},
+		"tampered payload": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "OCMF",
+			data:           strings.Replace(signedData, `"RV":22.5`, `"RV":32.5`, 1),
+			want:           store.SignedMeterValueStatusInvalid,
+		},
+		"wrong key": {
+			publicKeys:     []string{otherPublicKey},
+			encodingMethod: "OCMF",
+			data:           signedData,
+			want:           store.SignedMeterValueStatusInvalid,
+		},
+		"no public key": {
+			encodingMethod: "OCMF",
+			data:           signedData,
+			want:           store.SignedMeterValueStatusNoPublicKey,
+		},
+		"no public key for curve": {
+			publicKeys:     []string{p384PublicKey},
+			encodingMethod: "OCMF",
+			data:           signedData,
+			want:           store.SignedMeterValueStatusNoPublicKey,
+		},
+		"unparseable": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "OCMF",
+			data:           "OCMF|not json",
+			want:           store.SignedMeterValueStatusInvalid,
+		},
+		"unsupported algorithm": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "OCMF",
+			data:           signOcmf(t, p256Key, "ECDSA-brainpool256r1-SHA256", ocmfPayload),
+			want:           store.SignedMeterValueStatusUnsupported,
+		},
+		"edl": {
+			publicKeys:     []string{p256PublicKey, p192PublicKey},
+			encodingMethod: "EDL",
+			data:           edlData,
+			want:           store.SignedMeterValueStatusValid,
+		},
+		"edl P-256 with DER signature": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "EDL",
+			data:           signEdl(t, p256Key, "ECDSA256SHA256", encodeEdlMeterValue(22500), false),
+			want:           store.SignedMeterValueStatusValid,
+		},
+		"edl tampered meter value": {
+			publicKeys:     []string{p192PublicKey},
+			encodingMethod: "EDL",
+			data: strings.Replace(edlData,
+				base64.StdEncoding.EncodeToString(encodeEdlMeterValue(22500)),
+				base64.StdEncoding.EncodeToString(encodeEdlMeterValue(32500)), 1),
+			want: store.SignedMeterValueStatusInvalid,
+		},
+		"edl no public key": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "EDL",
+			data:           edlData,
+			want:           store.SignedMeterValueStatusNoPublicKey,
+		},
+		"edl unparseable": {
+			publicKeys:     []string{p192PublicKey},
+			encodingMethod: "EDL",
+			data:           "DEADBEEF",
+			want:           store.SignedMeterValueStatusInvalid,
+		},
+		"unknown encoding method": {
+			publicKeys:     []string{p256PublicKey},
+			encodingMethod: "ALFEN",
+			data:           "DEADBEEF",
+			want:           store.SignedMeterValueStatusUnsupported,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			engine := inmemory.NewStore(clock.RealClock{})
+			for i, publicKey := range tc.publicKeys {
+				err := engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{
+					EvseId:    i + 1,
+					PublicKey: publicKey,
+				})
+				require.NoError(t, err)
+			}
+
+			meterValues := []store.MeterValue{
+				{
+					Timestamp: "2023-06-15T16:05:00+01:00",
+					SampledValues: []store.SampledValue{
+						{Value: 100},
+						{
+							Value: 22500,
+							SignedMeterValue: &store.SignedMeterValue{
+								SignedMeterData: tc.data,
+								EncodingMethod:  tc.encodingMethod,
+								Status:          store.SignedMeterValueStatusUnverified,
+							},
+						},
+					},
+				},
+			}
+
+			service := services.BasicSignedMeterValueService{Store: engine}
+			err := service.VerifyMeterValues(ctx, "cs001", meterValues)
+			require.NoError(t, err)
+
+			assert.Nil(t, meterValues[0].SampledValues[0].SignedMeterValue)
+			assert.Equal(t, tc.want, meterValues[0].SampledValues[1].SignedMeterValue.Status)
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -12,12 +13,12 @@ import (
 )
 
 type TariffService interface {
-	CalculateCost(transaction *store.Transaction) (float64, error)
+	CalculateCost(ctx context.Context, transaction *store.Transaction) (float64, error)
 }
 
 type BasicKwhTariffService struct{}
 
-func (BasicKwhTariffService) CalculateCost(transaction *store.Transaction) (float64, error) {
+func (BasicKwhTariffService) CalculateCost(_ context.Context, transaction *store.Transaction) (float64, error) {
 	var cost float64
 
 	if transaction == nil {
@@ -55,7 +56,7 @@ func findMostRecentOutletEnergyReading(transaction *store.Transaction) (float64,
 		for _, sv := range mv.SampledValues {
 			if sv.Context != nil && *sv.Context == "Transaction.End" &&
 				sv.Measurand != nil && *sv.Measurand == "Energy.Active.Import.Register" &&
-				sv.Location != nil && *sv.Location == "Outlet" {
+				sv.Location != nil && *sv.Location == "Outlet" && !sv.SignedDataOnly {
 				totalWh = sv.Value
 				found = true
 			}
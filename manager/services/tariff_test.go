@@ -3,6 +3,7 @@
 package services_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -33,14 +34,14 @@ func TestBasicKwhTariffServiceCanCalculateCost(t *testing.T) {
 		},
 	}
 	tariffService := services.BasicKwhTariffService{}
-	cost, err := tariffService.CalculateCost(transaction)
+	cost, err := tariffService.CalculateCost(context.Background(), transaction)
 	assert.NoError(t, err)
 	assert.Equal(t, 0.055, cost)
 }
 
 func TestBasicKwhTariffServiceErrorsWithNilTransaction(t *testing.T) {
 	tariffService := services.BasicKwhTariffService{}
-	cost, err := tariffService.CalculateCost(nil)
+	cost, err := tariffService.CalculateCost(context.Background(), nil)
 	assert.ErrorContains(t, err, "no transaction provided")
 	var zero float64
 	assert.Equal(t, zero, cost)
@@ -49,7 +50,7 @@ func TestBasicKwhTariffServiceErrorsWithNilTransaction(t *testing.T) {
 func TestBasicKwhTariffServiceErrorsWhenNoKwhReading(t *testing.T) {
 	transaction := &store.Transaction{}
 	tariffService := services.BasicKwhTariffService{}
-	cost, err := tariffService.CalculateCost(transaction)
+	cost, err := tariffService.CalculateCost(context.Background(), transaction)
 	assert.ErrorContains(t, err, "no output energy reading found in transaction")
 	var zero float64
 	assert.Equal(t, zero, cost)
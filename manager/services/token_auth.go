@@ -13,7 +13,7 @@ import (
 )
 
 type TokenAuthService interface {
-	Authorize(ctx context.Context, token ocpp201.IdTokenType) ocpp201.IdTokenInfoType
+	Authorize(ctx context.Context, chargeStationId string, token ocpp201.IdTokenType) ocpp201.IdTokenInfoType
 }
 
 type OcppTokenAuthService struct {
@@ -21,7 +21,7 @@ type OcppTokenAuthService struct {
 	Clock      clock.PassiveClock
 }
 
-func (o *OcppTokenAuthService) Authorize(ctx context.Context, token ocpp201.IdTokenType) ocpp201.IdTokenInfoType {
+func (o *OcppTokenAuthService) Authorize(ctx context.Context, _ string, token ocpp201.IdTokenType) ocpp201.IdTokenInfoType {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
 		attribute.String("token_auth.id", token.IdToken),
@@ -61,28 +61,7 @@ func (o *OcppTokenAuthService) Authorize(ctx context.Context, token ocpp201.IdTo
 				status = ocpp201.AuthorizationStatusEnumTypeAccepted
 			}
 
-			// if the cache mode is never, prevent the charge station
-			// from caching the token by setting its expiry time to now
-			var cacheExpiryTime *string
-			if foundToken.CacheMode == "NEVER" {
-				expiryTime := o.Clock.Now().Format(time.RFC3339)
-				cacheExpiryTime = &expiryTime
-			}
-
-			var groupIdToken *ocpp201.IdTokenType
-			if foundToken.GroupId != nil {
-				groupIdToken = &ocpp201.IdTokenType{
-					Type:    ocpp201.IdTokenEnumTypeCentral,
-					IdToken: *foundToken.GroupId,
-				}
-				span.SetAttributes(attribute.String("token_auth.group_id", *foundToken.GroupId))
-			}
-
-			tokenInfo = &ocpp201.IdTokenInfoType{
-				Status:              status,
-				GroupIdToken:        groupIdToken,
-				CacheExpiryDateTime: cacheExpiryTime,
-			}
+			tokenInfo = newIdTokenInfo(ctx, o.Clock, status, foundToken)
 		}
 	}
 
@@ -90,3 +69,31 @@ func (o *OcppTokenAuthService) Authorize(ctx context.Context, token ocpp201.IdTo
 		attribute.String("token_auth.status", string(tokenInfo.Status)))
 	return *tokenInfo
 }
+
+// newIdTokenInfo returns the token info for the token with the status
+func newIdTokenInfo(ctx context.Context, clock clock.PassiveClock, status ocpp201.AuthorizationStatusEnumType, token *store.Token) *ocpp201.IdTokenInfoType {
+	span := trace.SpanFromContext(ctx)
+
+	// if the cache mode is never, prevent the charge station
+	// from caching the token by setting its expiry time to now
+	var cacheExpiryTime *string
+	if token.CacheMode == "NEVER" {
+		expiryTime := clock.Now().Format(time.RFC3339)
+		cacheExpiryTime = &expiryTime
+	}
+
+	var groupIdToken *ocpp201.IdTokenType
+	if token.GroupId != nil {
+		groupIdToken = &ocpp201.IdTokenType{
+			Type:    ocpp201.IdTokenEnumTypeCentral,
+			IdToken: *token.GroupId,
+		}
+		span.SetAttributes(attribute.String("token_auth.group_id", *token.GroupId))
+	}
+
+	return &ocpp201.IdTokenInfoType{
+		Status:              status,
+		GroupIdToken:        groupIdToken,
+		CacheExpiryDateTime: cacheExpiryTime,
+	}
+}
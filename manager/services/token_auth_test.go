@@ -34,7 +34,7 @@ func TestOcppTokenAuthServiceAcceptsNoAuthorization(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeNoAuthorization,
 			IdToken: "",
 		})
@@ -69,7 +69,7 @@ func TestOcppTokenAuthServiceAcceptsCentral(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeCentral,
 			IdToken: "SomeToken",
 		})
@@ -104,7 +104,7 @@ func TestOcppTokenAuthServiceRejectsLocalAuth(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeLocal,
 			IdToken: "some-local-id",
 		})
@@ -139,7 +139,7 @@ func TestOcppTokenAuthServiceReturnsUnknownIfNoTokenRegistered(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeISO14443,
 			IdToken: "DEADBEEF",
 		})
@@ -187,7 +187,7 @@ func TestOcppTokenAuthServiceReturnsInvalidIfTokenIsInvalid(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeISO14443,
 			IdToken: "DEADBEEF",
 		})
@@ -235,7 +235,7 @@ func TestOcppTokenAuthServiceReturnsAcceptedIfTokenIsValid(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeISO14443,
 			IdToken: "DEADBEEF",
 		})
@@ -283,7 +283,7 @@ func TestOcppTokenAuthServiceIncludesGroupIdIfConfigured(t *testing.T) {
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeISO14443,
 			IdToken: "DEADBEEF",
 		})
@@ -336,7 +336,7 @@ func TestOcppTokenAuthServiceSetsExpiryTimeToNowIfCacheModeIsNever(t *testing.T)
 		ctx, span := tracer.Start(ctx, "test")
 		defer span.End()
 
-		tokenInfo := tokenAuthService.Authorize(ctx, ocpp201.IdTokenType{
+		tokenInfo := tokenAuthService.Authorize(ctx, "cs001", ocpp201.IdTokenType{
 			Type:    ocpp201.IdTokenEnumTypeISO14443,
 			IdToken: "DEADBEEF",
 		})
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetQueuedCall(_ context.Context, call *store.QueuedCall) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		calls := make(map[string]*store.QueuedCall)
+		if _, err := get(tx, queuedCallBucket, call.ChargeStationId, &calls); err != nil {
+			return err
+		}
+		calls[call.MessageId] = call
+		return put(tx, queuedCallBucket, call.ChargeStationId, calls)
+	})
+	if err != nil {
+		return fmt.Errorf("setting queued call %s/%s: %w", call.ChargeStationId, call.MessageId, err)
+	}
+	return nil
+}
+
+// lookupQueuedCalls returns the calls queued for the charge station indexed by
+// message id. The calls for a charge station are stored together so the queue
+// can be read in a single get.
+func (s *Store) lookupQueuedCalls(chargeStationId string) (map[string]*store.QueuedCall, error) {
+	var calls map[string]*store.QueuedCall
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		_, err = get(tx, queuedCallBucket, chargeStationId, &calls)
+		return
+	})
+	return calls, err
+}
+
+func (s *Store) LookupQueuedCall(_ context.Context, chargeStationId, messageId string) (*store.QueuedCall, error) {
+	calls, err := s.lookupQueuedCalls(chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return calls[messageId], nil
+}
+
+func (s *Store) ListQueuedCalls(_ context.Context, chargeStationId string) ([]*store.QueuedCall, error) {
+	set, err := s.lookupQueuedCalls(chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list queued calls %s: %w", chargeStationId, err)
+	}
+	calls := make([]*store.QueuedCall, 0, len(set))
+	for _, call := range set {
+		calls = append(calls, call)
+	}
+	store.SortQueuedCalls(calls)
+	return calls, nil
+}
+
+func (s *Store) DeleteQueuedCall(_ context.Context, chargeStationId, messageId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		calls := make(map[string]*store.QueuedCall)
+		found, err := get(tx, queuedCallBucket, chargeStationId, &calls)
+		if err != nil || !found {
+			return err
+		}
+		delete(calls, messageId)
+		if len(calls) == 0 {
+			return del(tx, queuedCallBucket, chargeStationId)
+		}
+		return put(tx, queuedCallBucket, chargeStationId, calls)
+	})
+	if err != nil {
+		return fmt.Errorf("deleting queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return nil
+}
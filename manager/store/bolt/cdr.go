@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+// lastUpdatedKeyPrefix returns the prefix of the index keys for the values last
+// updated at or after timestamp. The timestamp is zero-padded so the keys sort by
+// time.
+func lastUpdatedKeyPrefix(timestamp time.Time) string {
+	return fmt.Sprintf("%020d", timestamp.UnixNano())
+}
+
+// lastUpdatedKey returns the key of the value with id in a last updated index
+func lastUpdatedKey(timestamp time.Time, id string) string {
+	return fmt.Sprintf("%s:%s", lastUpdatedKeyPrefix(timestamp), id)
+}
+
+// pageByLastUpdated returns a page of the ids in the last updated index bucket
+// that were last updated in the interval [dateFrom, dateTo) along with the
+// total number of ids in the interval.
+func pageByLastUpdated(tx *bbolt.Tx, bucket string, dateFrom, dateTo *time.Time, offset, limit int) ([]string, int) {
+	var ids []string
+	total := 0
+	c := tx.Bucket([]byte(bucket)).Cursor()
+	k, v := c.First()
+	if dateFrom != nil {
+		k, v = c.Seek([]byte(lastUpdatedKeyPrefix(*dateFrom)))
+	}
+	var end []byte
+	if dateTo != nil {
+		end = []byte(lastUpdatedKeyPrefix(*dateTo))
+	}
+	for ; k != nil && (end == nil || bytes.Compare(k, end) < 0); k, v = c.Next() {
+		total++
+		if total <= offset || len(ids) >= limit {
+			continue
+		}
+		ids = append(ids, string(v))
+	}
+	return ids, total
+}
+
+func (s *Store) CreateCdr(_ context.Context, cdr *store.Cdr) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(cdrBucket)).Get([]byte(cdr.Id)) != nil {
+			return nil
+		}
+		if err := put(tx, cdrBucket, cdr.Id, cdr); err != nil {
+			return err
+		}
+		key := lastUpdatedKey(cdr.LastUpdated, cdr.Id)
+		return tx.Bucket([]byte(cdrLastUpdatedBucket)).Put([]byte(key), []byte(cdr.Id))
+	})
+	if err != nil {
+		return fmt.Errorf("create cdr %s: %w", cdr.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupCdr(_ context.Context, id string) (*store.Cdr, error) {
+	var cdr store.Cdr
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, cdrBucket, id, &cdr)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup cdr %s: %w", id, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &cdr, nil
+}
+
+func (s *Store) ListCdrs(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Cdr, int, error) {
+	cdrs := make([]*store.Cdr, 0)
+	var total int
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var ids []string
+		ids, total = pageByLastUpdated(tx, cdrLastUpdatedBucket, dateFrom, dateTo, offset, limit)
+		for _, id := range ids {
+			var cdr store.Cdr
+			found, err := get(tx, cdrBucket, id, &cdr)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("no cdr for index %s", id)
+			}
+			cdrs = append(cdrs, &cdr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list cdrs: %w", err)
+	}
+	return cdrs, total, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetCertificate(_ context.Context, pemCertificate string) error {
+	certificateHash, err := getPEMCertificateHash(pemCertificate)
+	if err != nil {
+		return err
+	}
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(certificateBucket)).Put([]byte(certificateHash), []byte(pemCertificate))
+	})
+	if err != nil {
+		return fmt.Errorf("setting certificate %s: %w", certificateHash, err)
+	}
+	return nil
+}
+
+func getPEMCertificateHash(pemCertificate string) (string, error) {
+	var cert *x509.Certificate
+	block, _ := pem.Decode([]byte(pemCertificate))
+	if block != nil {
+		if block.Type == "CERTIFICATE" {
+			var err error
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return "", err
+			}
+		} else {
+			return "", fmt.Errorf("pem block does not contain certificate, but %s", block.Type)
+		}
+	} else {
+		return "", fmt.Errorf("pem block not found")
+	}
+
+	hash := sha256.Sum256(cert.Raw)
+	b64Hash := base64.RawURLEncoding.EncodeToString(hash[:])
+	return b64Hash, nil
+}
+
+func (s *Store) LookupCertificate(_ context.Context, certificateHash string) (string, error) {
+	var pemCertificate string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		pemCertificate = string(tx.Bucket([]byte(certificateBucket)).Get([]byte(certificateHash)))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("lookup certificate %s: %w", certificateHash, err)
+	}
+	return pemCertificate, nil
+}
+
+func (s *Store) DeleteCertificate(_ context.Context, certificateHash string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, certificateBucket, certificateHash)
+	})
+	if err != nil {
+		return fmt.Errorf("delete certificate %s: %w", certificateHash, err)
+	}
+	return nil
+}
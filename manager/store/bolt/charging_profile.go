@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func mapChargingProfiles(profiles map[int]*store.ChargingProfile) []*store.ChargingProfile {
+	ids := make([]int, 0, len(profiles))
+	for id := range profiles {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var result []*store.ChargingProfile
+	for _, id := range ids {
+		result = append(result, profiles[id])
+	}
+	return result
+}
+
+func (s *Store) UpdateChargeStationChargingProfiles(_ context.Context, chargeStationId string, profiles *store.ChargeStationChargingProfiles) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[int]*store.ChargingProfile)
+		if _, err := get(tx, chargeStationChargingProfilesBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		for _, p := range profiles.ChargingProfiles {
+			set[p.ChargingProfileId] = p
+		}
+		return put(tx, chargeStationChargingProfilesBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationChargingProfiles(_ context.Context, chargeStationId string) (*store.ChargeStationChargingProfiles, error) {
+	var profiles map[int]*store.ChargingProfile
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		_, err = get(tx, chargeStationChargingProfilesBucket, chargeStationId, &profiles)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return &store.ChargeStationChargingProfiles{
+		ChargeStationId:  chargeStationId,
+		ChargingProfiles: mapChargingProfiles(profiles),
+	}, nil
+}
+
+func (s *Store) ListChargeStationChargingProfiles(_ context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationChargingProfiles, error) {
+	var chargingProfiles []*store.ChargeStationChargingProfiles
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listAfter(tx, chargeStationChargingProfilesBucket, previousCsId, pageSize, func(k, v []byte) error {
+			var profiles map[int]*store.ChargingProfile
+			if err := json.Unmarshal(v, &profiles); err != nil {
+				return fmt.Errorf("map charge station charging profiles %s: %w", k, err)
+			}
+			chargingProfiles = append(chargingProfiles, &store.ChargeStationChargingProfiles{
+				ChargeStationId:  string(k),
+				ChargingProfiles: mapChargingProfiles(profiles),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station charging profiles: %w", err)
+	}
+	return chargingProfiles, nil
+}
+
+func (s *Store) DeleteChargeStationChargingProfile(_ context.Context, chargeStationId string, chargingProfileId int) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[int]*store.ChargingProfile)
+		if _, err := get(tx, chargeStationChargingProfilesBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		delete(set, chargingProfileId)
+		if len(set) == 0 {
+			return del(tx, chargeStationChargingProfilesBucket, chargeStationId)
+		}
+		return put(tx, chargeStationChargingProfilesBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("delete charge station charging profile %s/%d: %w", chargeStationId, chargingProfileId, err)
+	}
+	return nil
+}
+
+func compositeScheduleKey(chargeStationId string, connectorId int) string {
+	return fmt.Sprintf("%s:%d", chargeStationId, connectorId)
+}
+
+func (s *Store) SetChargeStationCompositeSchedule(_ context.Context, chargeStationId string, schedule *store.CompositeSchedule) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, chargeStationCompositeScheduleBucket, compositeScheduleKey(chargeStationId, schedule.ConnectorId), schedule)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station composite schedule %s/%d: %w", chargeStationId, schedule.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationCompositeSchedule(_ context.Context, chargeStationId string, connectorId int) (*store.CompositeSchedule, error) {
+	var schedule store.CompositeSchedule
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationCompositeScheduleBucket, compositeScheduleKey(chargeStationId, connectorId), &schedule)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station composite schedule %s/%d: %w", chargeStationId, connectorId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &schedule, nil
+}
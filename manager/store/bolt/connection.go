@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetChargeStationConnection(_ context.Context, chargeStationId string, connection *store.ChargeStationConnection) error {
+	c := *connection
+	c.ChargeStationId = chargeStationId
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, chargeStationConnectionBucket, chargeStationId, &c)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station connection %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnection(_ context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	var connection store.ChargeStationConnection
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationConnectionBucket, chargeStationId, &connection)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station connection %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &connection, nil
+}
+
+func (s *Store) ListChargeStationConnections(_ context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	connections := make([]*store.ChargeStationConnection, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listOffset(tx, chargeStationConnectionBucket, offset, limit, func(k, v []byte) error {
+			var connection store.ChargeStationConnection
+			if err := json.Unmarshal(v, &connection); err != nil {
+				return fmt.Errorf("map charge station connection %s: %w", k, err)
+			}
+			connections = append(connections, &connection)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station connections: %w", err)
+	}
+	return connections, nil
+}
+
+func (s *Store) SetChargeStationLastSeen(_ context.Context, chargeStationId string, lastSeen time.Time) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		connection := store.ChargeStationConnection{ChargeStationId: chargeStationId}
+		if _, err := get(tx, chargeStationConnectionBucket, chargeStationId, &connection); err != nil {
+			return err
+		}
+		connection.LastSeen = lastSeen
+		return put(tx, chargeStationConnectionBucket, chargeStationId, &connection)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station last seen %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) SetChargeStationDisconnected(_ context.Context, chargeStationId, connectionId string, disconnectedAt time.Time) (bool, error) {
+	var disconnected bool
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var connection store.ChargeStationConnection
+		found, err := get(tx, chargeStationConnectionBucket, chargeStationId, &connection)
+		if err != nil {
+			return err
+		}
+		if !found || connection.ConnectionId != connectionId {
+			return nil
+		}
+		connection.Connected = false
+		connection.DisconnectedAt = &disconnectedAt
+		if disconnectedAt.After(connection.LastSeen) {
+			connection.LastSeen = disconnectedAt
+		}
+		disconnected = true
+		return put(tx, chargeStationConnectionBucket, chargeStationId, &connection)
+	})
+	if err != nil {
+		return false, fmt.Errorf("setting charge station disconnected %s: %w", chargeStationId, err)
+	}
+	return disconnected, nil
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func connectorStatusKey(evseId, connectorId int) string {
+	return fmt.Sprintf("%d:%d", evseId, connectorId)
+}
+
+func (s *Store) SetChargeStationConnectorStatus(_ context.Context, chargeStationId string, status *store.ConnectorStatus) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[string]*store.ConnectorStatus)
+		if _, err := get(tx, chargeStationConnectorStatusBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		set[connectorStatusKey(status.EvseId, status.ConnectorId)] = status
+		return put(tx, chargeStationConnectorStatusBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station connector status %s/%d/%d: %w", chargeStationId, status.EvseId, status.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) lookupChargeStationConnectorStatuses(chargeStationId string) (map[string]*store.ConnectorStatus, error) {
+	var set map[string]*store.ConnectorStatus
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		_, err = get(tx, chargeStationConnectorStatusBucket, chargeStationId, &set)
+		return
+	})
+	return set, err
+}
+
+func (s *Store) LookupChargeStationConnectorStatus(_ context.Context, chargeStationId string, evseId, connectorId int) (*store.ConnectorStatus, error) {
+	set, err := s.lookupChargeStationConnectorStatuses(chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station connector status %s/%d/%d: %w", chargeStationId, evseId, connectorId, err)
+	}
+	return set[connectorStatusKey(evseId, connectorId)], nil
+}
+
+func (s *Store) ListChargeStationConnectorStatuses(_ context.Context, chargeStationId string) ([]*store.ConnectorStatus, error) {
+	set, err := s.lookupChargeStationConnectorStatuses(chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station connector statuses %s: %w", chargeStationId, err)
+	}
+	var statuses []*store.ConnectorStatus
+	for _, status := range set {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].EvseId != statuses[j].EvseId {
+			return statuses[i].EvseId < statuses[j].EvseId
+		}
+		return statuses[i].ConnectorId < statuses[j].ConnectorId
+	})
+	return statuses, nil
+}
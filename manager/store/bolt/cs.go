@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetChargeStationAuth(_ context.Context, chargeStationId string, auth *store.ChargeStationAuth) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, chargeStationAuthBucket, chargeStationId, auth)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station auth %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationAuth(_ context.Context, chargeStationId string) (*store.ChargeStationAuth, error) {
+	var auth store.ChargeStationAuth
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationAuthBucket, chargeStationId, &auth)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &auth, nil
+}
+
+func (s *Store) UpdateChargeStationSettings(_ context.Context, chargeStationId string, settings *store.ChargeStationSettings) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[string]*store.ChargeStationSetting)
+		if _, err := get(tx, chargeStationSettingsBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		for k, v := range settings.Settings {
+			set[k] = v
+		}
+		return put(tx, chargeStationSettingsBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station settings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationSettings(_ context.Context, chargeStationId string) (*store.ChargeStationSettings, error) {
+	var settings map[string]*store.ChargeStationSetting
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationSettingsBucket, chargeStationId, &settings)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station settings %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &store.ChargeStationSettings{
+		ChargeStationId: chargeStationId,
+		Settings:        settings,
+	}, nil
+}
+
+func (s *Store) ListChargeStationSettings(_ context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationSettings, error) {
+	var chargeStationSettings []*store.ChargeStationSettings
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listAfter(tx, chargeStationSettingsBucket, previousCsId, pageSize, func(k, v []byte) error {
+			var settings map[string]*store.ChargeStationSetting
+			if err := json.Unmarshal(v, &settings); err != nil {
+				return fmt.Errorf("map charge station settings %s: %w", k, err)
+			}
+			chargeStationSettings = append(chargeStationSettings, &store.ChargeStationSettings{
+				ChargeStationId: string(k),
+				Settings:        settings,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station settings: %w", err)
+	}
+	return chargeStationSettings, nil
+}
+
+func (s *Store) DeleteChargeStationSettings(_ context.Context, chargeStationId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, chargeStationSettingsBucket, chargeStationId)
+	})
+	if err != nil {
+		return fmt.Errorf("delete charge station settings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func mapChargeStationInstallCertificates(certificates map[string]*store.ChargeStationInstallCertificate) []*store.ChargeStationInstallCertificate {
+	ids := make([]string, 0, len(certificates))
+	for id := range certificates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var certs []*store.ChargeStationInstallCertificate
+	for _, id := range ids {
+		certs = append(certs, certificates[id])
+	}
+	return certs
+}
+
+func (s *Store) UpdateChargeStationInstallCertificates(_ context.Context, chargeStationId string, certificates *store.ChargeStationInstallCertificates) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[string]*store.ChargeStationInstallCertificate)
+		if _, err := get(tx, chargeStationInstallCertificatesBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		for _, c := range certificates.Certificates {
+			set[c.CertificateId] = c
+		}
+		return put(tx, chargeStationInstallCertificatesBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station install certificates %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationInstallCertificates(_ context.Context, chargeStationId string) (*store.ChargeStationInstallCertificates, error) {
+	var certificates map[string]*store.ChargeStationInstallCertificate
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationInstallCertificatesBucket, chargeStationId, &certificates)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station install certificates %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &store.ChargeStationInstallCertificates{
+		ChargeStationId: chargeStationId,
+		Certificates:    mapChargeStationInstallCertificates(certificates),
+	}, nil
+}
+
+func (s *Store) ListChargeStationInstallCertificates(_ context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationInstallCertificates, error) {
+	var installCerts []*store.ChargeStationInstallCertificates
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listAfter(tx, chargeStationInstallCertificatesBucket, previousCsId, pageSize, func(k, v []byte) error {
+			var certificates map[string]*store.ChargeStationInstallCertificate
+			if err := json.Unmarshal(v, &certificates); err != nil {
+				return fmt.Errorf("map charge station install certificates %s: %w", k, err)
+			}
+			installCerts = append(installCerts, &store.ChargeStationInstallCertificates{
+				ChargeStationId: string(k),
+				Certificates:    mapChargeStationInstallCertificates(certificates),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station install certificates: %w", err)
+	}
+	return installCerts, nil
+}
+
+func (s *Store) SetChargeStationRuntimeDetails(_ context.Context, chargeStationId string, details *store.ChargeStationRuntimeDetails) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, chargeStationRuntimeDetailsBucket, chargeStationId, details)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station runtime details %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationRuntimeDetails(_ context.Context, chargeStationId string) (*store.ChargeStationRuntimeDetails, error) {
+	var details store.ChargeStationRuntimeDetails
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationRuntimeDetailsBucket, chargeStationId, &details)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station runtime details %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &details, nil
+}
+
+func (s *Store) SetChargeStationTriggerMessage(_ context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, chargeStationTriggerMessageBucket, chargeStationId, &store.ChargeStationTriggerMessage{
+			ChargeStationId: chargeStationId,
+			TriggerMessage:  triggerMessage.TriggerMessage,
+			TriggerStatus:   triggerMessage.TriggerStatus,
+			SendAfter:       triggerMessage.SendAfter,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station trigger message %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteChargeStationTriggerMessage(_ context.Context, chargeStationId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, chargeStationTriggerMessageBucket, chargeStationId)
+	})
+	if err != nil {
+		return fmt.Errorf("delete charge station trigger message %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationTriggerMessage(_ context.Context, chargeStationId string) (*store.ChargeStationTriggerMessage, error) {
+	var triggerMessage store.ChargeStationTriggerMessage
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, chargeStationTriggerMessageBucket, chargeStationId, &triggerMessage)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station trigger message %s: %w", chargeStationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &triggerMessage, nil
+}
+
+func (s *Store) ListChargeStationTriggerMessages(_ context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationTriggerMessage, error) {
+	var triggerMessages []*store.ChargeStationTriggerMessage
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listAfter(tx, chargeStationTriggerMessageBucket, previousCsId, pageSize, func(k, v []byte) error {
+			var triggerMessage store.ChargeStationTriggerMessage
+			if err := json.Unmarshal(v, &triggerMessage); err != nil {
+				return fmt.Errorf("map charge station trigger message %s: %w", k, err)
+			}
+			triggerMessages = append(triggerMessages, &triggerMessage)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station trigger messages: %w", err)
+	}
+	return triggerMessages, nil
+}
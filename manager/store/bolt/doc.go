@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bolt provides an implementation of store.Engine using an embedded
+// bbolt database file. It is intended for single node deployments that do not
+// have access to a database server.
+package bolt
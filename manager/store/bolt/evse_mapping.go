@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func evseMappingKey(locationId, evseUid string) string {
+	return fmt.Sprintf("%s:%s", locationId, evseUid)
+}
+
+func (s *Store) SetEvseMapping(_ context.Context, mapping *store.EvseMapping) error {
+	key := evseMappingKey(mapping.LocationId, mapping.EvseUid)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if err := deleteEvseMappingEvseId(tx, key); err != nil {
+			return err
+		}
+		if mapping.EvseId != nil {
+			if err := put(tx, evseMappingEvseIdBucket, *mapping.EvseId, key); err != nil {
+				return err
+			}
+		}
+		return put(tx, evseMappingBucket, key, mapping)
+	})
+	if err != nil {
+		return fmt.Errorf("set evse mapping %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupEvseMapping(_ context.Context, locationId, evseUid string) (*store.EvseMapping, error) {
+	key := evseMappingKey(locationId, evseUid)
+	var mapping store.EvseMapping
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, evseMappingBucket, key, &mapping)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup evse mapping %s: %w", key, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &mapping, nil
+}
+
+func (s *Store) LookupEvseMappingByEvseId(_ context.Context, evseId string) (*store.EvseMapping, error) {
+	var mapping store.EvseMapping
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var key string
+		ok, err := get(tx, evseMappingEvseIdBucket, evseId, &key)
+		if err != nil || !ok {
+			return err
+		}
+		found, err = get(tx, evseMappingBucket, key, &mapping)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup evse mapping for evse id %s: %w", evseId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &mapping, nil
+}
+
+func (s *Store) ListEvseMappingsForChargeStation(_ context.Context, chargeStationId string) ([]*store.EvseMapping, error) {
+	var mappings []*store.EvseMapping
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(evseMappingBucket)).ForEach(func(k, v []byte) error {
+			var mapping store.EvseMapping
+			if err := json.Unmarshal(v, &mapping); err != nil {
+				return fmt.Errorf("map evse mapping %s: %w", k, err)
+			}
+			if mapping.ChargeStationId == chargeStationId {
+				mappings = append(mappings, &mapping)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list evse mappings for %s: %w", chargeStationId, err)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].LocationId != mappings[j].LocationId {
+			return mappings[i].LocationId < mappings[j].LocationId
+		}
+		return mappings[i].EvseUid < mappings[j].EvseUid
+	})
+	return mappings, nil
+}
+
+func (s *Store) DeleteEvseMapping(_ context.Context, locationId, evseUid string) error {
+	key := evseMappingKey(locationId, evseUid)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if err := deleteEvseMappingEvseId(tx, key); err != nil {
+			return err
+		}
+		return del(tx, evseMappingBucket, key)
+	})
+	if err != nil {
+		return fmt.Errorf("delete evse mapping %s: %w", key, err)
+	}
+	return nil
+}
+
+// deleteEvseMappingEvseId removes the index entry for the EVSE ID of the
+// mapping stored for key, if any.
+func deleteEvseMappingEvseId(tx *bbolt.Tx, key string) error {
+	var existing store.EvseMapping
+	found, err := get(tx, evseMappingBucket, key, &existing)
+	if err != nil || !found || existing.EvseId == nil {
+		return err
+	}
+	return del(tx, evseMappingEvseIdBucket, *existing.EvseId)
+}
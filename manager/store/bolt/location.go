@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetLocation(_ context.Context, location *store.Location) error {
+	loc := *location
+	loc.LastUpdated = s.clock.Now().UTC().Format("2006-01-02T15:04:05Z")
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, locationBucket, loc.Id, &loc)
+	})
+	if err != nil {
+		return fmt.Errorf("setting location %s: %w", location.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupLocation(_ context.Context, locationId string) (*store.Location, error) {
+	var loc store.Location
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, locationBucket, locationId, &loc)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup location %s: %w", locationId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &loc, nil
+}
+
+func (s *Store) ListLocations(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Location, int, error) {
+	locations := make([]*store.Location, 0)
+	var total int
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(locationBucket)).ForEach(func(k, v []byte) error {
+			var loc store.Location
+			if err := json.Unmarshal(v, &loc); err != nil {
+				return fmt.Errorf("map location %s: %w", k, err)
+			}
+			lastUpdated, err := time.Parse(time.RFC3339, loc.LastUpdated)
+			if err != nil {
+				return fmt.Errorf("parse last updated of location %s: %w", k, err)
+			}
+			if dateFrom != nil && lastUpdated.Before(*dateFrom) {
+				return nil
+			}
+			if dateTo != nil && !lastUpdated.Before(*dateTo) {
+				return nil
+			}
+			if total >= offset && total < offset+limit {
+				locations = append(locations, &loc)
+			}
+			total++
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list locations: %w", err)
+	}
+	return locations, total, nil
+}
+
+func clientOwnedLocationKey(countryCode, partyId, locationId string) string {
+	return fmt.Sprintf("%s:%s:%s", countryCode, partyId, locationId)
+}
+
+func (s *Store) SetClientOwnedLocation(_ context.Context, location *store.Location) error {
+	key := clientOwnedLocationKey(location.CountryCode, location.PartyId, location.Id)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, clientOwnedLocationBucket, key, location)
+	})
+	if err != nil {
+		return fmt.Errorf("setting client owned location %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupClientOwnedLocation(_ context.Context, countryCode, partyId, locationId string) (*store.Location, error) {
+	key := clientOwnedLocationKey(countryCode, partyId, locationId)
+	var loc store.Location
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, clientOwnedLocationBucket, key, &loc)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup client owned location %s: %w", key, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &loc, nil
+}
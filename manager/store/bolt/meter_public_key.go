@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetChargeStationMeterPublicKey(_ context.Context, chargeStationId string, key *store.MeterPublicKey) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[int]*store.MeterPublicKey)
+		if _, err := get(tx, chargeStationMeterPublicKeyBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		set[key.EvseId] = key
+		return put(tx, chargeStationMeterPublicKeyBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station meter public key %s/%d: %w", chargeStationId, key.EvseId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterPublicKeys(_ context.Context, chargeStationId string) ([]*store.MeterPublicKey, error) {
+	var set map[int]*store.MeterPublicKey
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		_, err = get(tx, chargeStationMeterPublicKeyBucket, chargeStationId, &set)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station meter public keys %s: %w", chargeStationId, err)
+	}
+	var keys []*store.MeterPublicKey
+	for _, key := range set {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].EvseId < keys[j].EvseId
+	})
+	return keys, nil
+}
+
+func (s *Store) DeleteChargeStationMeterPublicKey(_ context.Context, chargeStationId string, evseId int) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		set := make(map[int]*store.MeterPublicKey)
+		if _, err := get(tx, chargeStationMeterPublicKeyBucket, chargeStationId, &set); err != nil {
+			return err
+		}
+		delete(set, evseId)
+		if len(set) == 0 {
+			return del(tx, chargeStationMeterPublicKeyBucket, chargeStationId)
+		}
+		return put(tx, chargeStationMeterPublicKeyBucket, chargeStationId, set)
+	})
+	if err != nil {
+		return fmt.Errorf("delete charge station meter public key %s/%d: %w", chargeStationId, evseId, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+// meterReadingKeyPrefix returns the prefix of the keys for the readings of a
+// charge station taken at or after timestamp. The timestamp is zero-padded so
+// the keys sort by time.
+func meterReadingKeyPrefix(chargeStationId string, timestamp time.Time) string {
+	return fmt.Sprintf("%s:%020d", chargeStationId, timestamp.UnixNano())
+}
+
+func (s *Store) AddChargeStationMeterReadings(_ context.Context, chargeStationId string, readings []*store.MeterReading) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(chargeStationMeterReadingBucket))
+		for _, reading := range readings {
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			key := fmt.Sprintf("%s:%010d:%020d", meterReadingKeyPrefix(chargeStationId, reading.Timestamp), reading.ConnectorId, seq)
+			if err = put(tx, chargeStationMeterReadingBucket, key, reading); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("add charge station meter readings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterReadings(_ context.Context, chargeStationId string, from, to time.Time) ([]*store.MeterReading, error) {
+	var readings []*store.MeterReading
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(chargeStationMeterReadingBucket)).Cursor()
+		end := []byte(meterReadingKeyPrefix(chargeStationId, to))
+		for k, v := c.Seek([]byte(meterReadingKeyPrefix(chargeStationId, from))); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+			var reading store.MeterReading
+			if err := json.Unmarshal(v, &reading); err != nil {
+				return fmt.Errorf("unmarshal %s: %w", k, err)
+			}
+			readings = append(readings, &reading)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list charge station meter readings %s: %w", chargeStationId, err)
+	}
+	return readings, nil
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetRegistrationDetails(_ context.Context, token string, registration *store.OcpiRegistration) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, ocpiRegistrationBucket, token, registration)
+	})
+	if err != nil {
+		return fmt.Errorf("setting registration: %s: %w", token, err)
+	}
+	return nil
+}
+
+func (s *Store) GetRegistrationDetails(_ context.Context, token string) (*store.OcpiRegistration, error) {
+	var registration store.OcpiRegistration
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, ocpiRegistrationBucket, token, &registration)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup registration %s: %w", token, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &registration, nil
+}
+
+func (s *Store) DeleteRegistrationDetails(_ context.Context, token string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, ocpiRegistrationBucket, token)
+	})
+	if err != nil {
+		return fmt.Errorf("delete registration %s: %w", token, err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteRegistrationDetailsForUrl(_ context.Context, url string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var tokens []string
+		err := tx.Bucket([]byte(ocpiRegistrationBucket)).ForEach(func(k, v []byte) error {
+			var registration store.OcpiRegistration
+			if err := json.Unmarshal(v, &registration); err != nil {
+				return fmt.Errorf("map registration %s: %w", k, err)
+			}
+			if registration.Url == url {
+				tokens = append(tokens, string(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			if err := del(tx, ocpiRegistrationBucket, token); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete registrations for %s: %w", url, err)
+	}
+	return nil
+}
+
+func partyKey(role, countryCode, partyId string) string {
+	return fmt.Sprintf("%s/%s:%s", role, countryCode, partyId)
+}
+
+func (s *Store) SetPartyDetails(_ context.Context, partyDetails *store.OcpiParty) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, ocpiPartyBucket, partyKey(partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId), partyDetails)
+	})
+	if err != nil {
+		return fmt.Errorf("setting party %s/%s:%s: %w", partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId, err)
+	}
+	return nil
+}
+
+func (s *Store) GetPartyDetails(_ context.Context, role, countryCode, partyId string) (*store.OcpiParty, error) {
+	var party store.OcpiParty
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, ocpiPartyBucket, partyKey(role, countryCode, partyId), &party)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup party details %s/%s:%s: %w", role, countryCode, partyId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &party, nil
+}
+
+func (s *Store) ListPartyDetailsForRole(_ context.Context, role string) ([]*store.OcpiParty, error) {
+	parties := make([]*store.OcpiParty, 0)
+	prefix := []byte(role + "/")
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(ocpiPartyBucket)).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var party store.OcpiParty
+			if err := json.Unmarshal(v, &party); err != nil {
+				return fmt.Errorf("map ocpiParty %s: %w", k, err)
+			}
+			parties = append(parties, &party)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list parties for role %s: %w", role, err)
+	}
+	return parties, nil
+}
+
+func (s *Store) ListPartyDetails(_ context.Context) ([]*store.OcpiParty, error) {
+	parties := make([]*store.OcpiParty, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(ocpiPartyBucket)).ForEach(func(k, v []byte) error {
+			var party store.OcpiParty
+			if err := json.Unmarshal(v, &party); err != nil {
+				return fmt.Errorf("map ocpiParty %s: %w", k, err)
+			}
+			parties = append(parties, &party)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list parties: %w", err)
+	}
+	return parties, nil
+}
+
+func (s *Store) DeletePartyDetails(_ context.Context, role, countryCode, partyId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, ocpiPartyBucket, partyKey(role, countryCode, partyId))
+	})
+	if err != nil {
+		return fmt.Errorf("delete party %s/%s:%s: %w", role, countryCode, partyId, err)
+	}
+	return nil
+}
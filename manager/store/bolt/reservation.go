@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func reservationKey(countryCode, partyId, id string) string {
+	return fmt.Sprintf("%s:%s:%s", countryCode, partyId, id)
+}
+
+func (s *Store) SetReservation(_ context.Context, reservation *store.Reservation) error {
+	key := reservationKey(reservation.CountryCode, reservation.PartyId, reservation.Id)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, reservationBucket, key, reservation)
+	})
+	if err != nil {
+		return fmt.Errorf("set reservation %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupReservation(_ context.Context, countryCode, partyId, id string) (*store.Reservation, error) {
+	key := reservationKey(countryCode, partyId, id)
+	var reservation store.Reservation
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, reservationBucket, key, &reservation)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup reservation %s: %w", key, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &reservation, nil
+}
+
+func (s *Store) DeleteReservation(_ context.Context, countryCode, partyId, id string) error {
+	key := reservationKey(countryCode, partyId, id)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return del(tx, reservationBucket, key)
+	})
+	if err != nil {
+		return fmt.Errorf("delete reservation %s: %w", key, err)
+	}
+	return nil
+}
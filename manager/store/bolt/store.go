@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+	"k8s.io/utils/clock"
+)
+
+const (
+	chargeStationAuthBucket                = "ChargeStation"
+	chargeStationSettingsBucket            = "ChargeStationSettings"
+	chargeStationInstallCertificatesBucket = "ChargeStationInstallCertificates"
+	chargeStationRuntimeDetailsBucket      = "ChargeStationRuntimeDetails"
+	chargeStationTriggerMessageBucket      = "ChargeStationTriggerMessage"
+	chargeStationChargingProfilesBucket    = "ChargeStationChargingProfiles"
+	chargeStationCompositeScheduleBucket   = "ChargeStationCompositeSchedule"
+	chargeStationConnectorStatusBucket     = "ChargeStationConnectorStatus"
+	chargeStationConnectionBucket          = "ChargeStationConnection"
+	queuedCallBucket                       = "QueuedCall"
+	chargeStationMeterReadingBucket        = "ChargeStationMeterReading"
+	chargeStationMeterPublicKeyBucket      = "ChargeStationMeterPublicKey"
+	tokenBucket                            = "Token"
+	transactionBucket                      = "Transaction"
+	transactionLastUpdatedBucket           = "TransactionLastUpdated"
+	certificateBucket                      = "Certificate"
+	ocpiRegistrationBucket                 = "OcpiRegistration"
+	ocpiPartyBucket                        = "OcpiParty"
+	locationBucket                         = "Location"
+	clientOwnedLocationBucket              = "ClientOwnedLocation"
+	cdrBucket                              = "Cdr"
+	cdrLastUpdatedBucket                   = "CdrLastUpdated"
+	tariffBucket                           = "Tariff"
+	tariffLastUpdatedBucket                = "TariffLastUpdated"
+	reservationBucket                      = "Reservation"
+	evseMappingBucket                      = "EvseMapping"
+	evseMappingEvseIdBucket                = "EvseMappingEvseId"
+)
+
+var buckets = []string{
+	chargeStationAuthBucket,
+	chargeStationSettingsBucket,
+	chargeStationInstallCertificatesBucket,
+	chargeStationRuntimeDetailsBucket,
+	chargeStationTriggerMessageBucket,
+	chargeStationChargingProfilesBucket,
+	chargeStationCompositeScheduleBucket,
+	chargeStationConnectorStatusBucket,
+	chargeStationConnectionBucket,
+	queuedCallBucket,
+	chargeStationMeterReadingBucket,
+	chargeStationMeterPublicKeyBucket,
+	tokenBucket,
+	transactionBucket,
+	transactionLastUpdatedBucket,
+	certificateBucket,
+	ocpiRegistrationBucket,
+	ocpiPartyBucket,
+	locationBucket,
+	clientOwnedLocationBucket,
+	cdrBucket,
+	cdrLastUpdatedBucket,
+	tariffBucket,
+	tariffLastUpdatedBucket,
+	reservationBucket,
+	evseMappingBucket,
+	evseMappingEvseIdBucket,
+}
+
+// Store is an implementation of the store.Engine interface backed by a single
+// bbolt database file. bbolt allows many concurrent read transactions and a
+// single write transaction at a time, so all read-modify-write operations are
+// performed within one write transaction. The database file is locked while it
+// is open so the store cannot be shared by >1 manager instances.
+type Store struct {
+	db    *bbolt.DB
+	clock clock.PassiveClock
+}
+
+// NewStore opens (creating it if necessary) the database file at path.
+func NewStore(path string, clock clock.PassiveClock) (store.Engine, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt database %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range buckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
+				return fmt.Errorf("create bucket %s: %w", bucket, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Store{
+		db:    db,
+		clock: clock,
+	}, nil
+}
+
+// Close releases the lock on the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// get unmarshals the value stored for key in bucket into v. It returns false
+// if there is no value for the key.
+func get(tx *bbolt.Tx, bucket, key string, v any) (bool, error) {
+	data := tx.Bucket([]byte(bucket)).Get([]byte(key))
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("unmarshal %s/%s: %w", bucket, key, err)
+	}
+	return true, nil
+}
+
+// put marshals v and stores it for key in bucket.
+func put(tx *bbolt.Tx, bucket, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s/%s: %w", bucket, key, err)
+	}
+	return tx.Bucket([]byte(bucket)).Put([]byte(key), data)
+}
+
+// del removes the value stored for key in bucket.
+func del(tx *bbolt.Tx, bucket, key string) error {
+	return tx.Bucket([]byte(bucket)).Delete([]byte(key))
+}
+
+// listAfter calls fn for up to pageSize entries of bucket with keys that sort after
+// previousKey. Keys are visited in byte order.
+func listAfter(tx *bbolt.Tx, bucket, previousKey string, pageSize int, fn func(k, v []byte) error) error {
+	c := tx.Bucket([]byte(bucket)).Cursor()
+	var k, v []byte
+	if previousKey == "" {
+		k, v = c.First()
+	} else {
+		k, v = c.Seek([]byte(previousKey))
+		if k != nil && string(k) == previousKey {
+			k, v = c.Next()
+		}
+	}
+	for count := 0; k != nil && count < pageSize; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+		count++
+	}
+	return nil
+}
+
+// listOffset calls fn for up to limit entries of bucket after skipping the first
+// offset entries. Keys are visited in byte order.
+func listOffset(tx *bbolt.Tx, bucket string, offset, limit int, fn func(k, v []byte) error) error {
+	c := tx.Bucket([]byte(bucket)).Cursor()
+	count := 0
+	for k, v := c.First(); k != nil && count < offset+limit; k, v = c.Next() {
+		if count >= offset {
+			if err := fn(k, v); err != nil {
+				return err
+			}
+		}
+		count++
+	}
+	return nil
+}
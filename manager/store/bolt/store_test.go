@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/bolt"
+	"k8s.io/utils/clock"
+)
+
+func newStore(t *testing.T, clock clock.PassiveClock) (store.Engine, error) {
+	engine, err := bolt.NewStore(filepath.Join(t.TempDir(), "csms.db"), clock)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() {
+		_ = engine.(*bolt.Store).Close()
+	})
+	return engine, nil
+}
+
+func TestNewStore(t *testing.T) {
+	engine, err := newStore(t, clock.RealClock{})
+	require.NoError(t, err)
+	assert.NotNil(t, engine)
+}
+
+func TestStorePersistsAcrossRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "csms.db")
+
+	engine, err := bolt.NewStore(path, clock.RealClock{})
+	require.NoError(t, err)
+
+	err = engine.SetRegistrationDetails(ctx, "123", &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusRegistered,
+	})
+	require.NoError(t, err)
+	require.NoError(t, engine.(*bolt.Store).Close())
+
+	engine, err = bolt.NewStore(path, clock.RealClock{})
+	require.NoError(t, err)
+	defer func() {
+		_ = engine.(*bolt.Store).Close()
+	}()
+
+	got, err := engine.GetRegistrationDetails(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, &store.OcpiRegistration{Status: store.OcpiRegistrationStatusRegistered}, got)
+}
+
+func TestConcurrentTransactionUpdates(t *testing.T) {
+	ctx := context.Background()
+
+	engine, err := newStore(t, clock.RealClock{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := engine.UpdateTransaction(ctx, "cs001", "1234", []store.MeterValue{
+				{Timestamp: fmt.Sprintf("2024-01-01T00:00:%02dZ", i)},
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	got, err := engine.FindTransaction(ctx, "cs001", "1234")
+	require.NoError(t, err)
+	assert.Equal(t, 20, got.UpdatedSeqNoCount)
+	assert.Len(t, got.MeterValues, 20)
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/storetest"
+	"k8s.io/utils/clock"
+)
+
+func TestStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, clock clock.PassiveClock) store.Engine {
+		engine, err := newStore(t, clock)
+		require.NoError(t, err)
+		return engine
+	})
+}
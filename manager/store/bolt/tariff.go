@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetTariff(_ context.Context, tariff *store.Tariff) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if err := deleteTariff(tx, tariff.Id); err != nil {
+			return err
+		}
+		if err := put(tx, tariffBucket, tariff.Id, tariff); err != nil {
+			return err
+		}
+		key := lastUpdatedKey(tariff.LastUpdated, tariff.Id)
+		return tx.Bucket([]byte(tariffLastUpdatedBucket)).Put([]byte(key), []byte(tariff.Id))
+	})
+	if err != nil {
+		return fmt.Errorf("set tariff %s: %w", tariff.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupTariff(_ context.Context, id string) (*store.Tariff, error) {
+	var tariff store.Tariff
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, tariffBucket, id, &tariff)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup tariff %s: %w", id, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &tariff, nil
+}
+
+func (s *Store) ListTariffs(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Tariff, int, error) {
+	tariffs := make([]*store.Tariff, 0)
+	var total int
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var ids []string
+		ids, total = pageByLastUpdated(tx, tariffLastUpdatedBucket, dateFrom, dateTo, offset, limit)
+		for _, id := range ids {
+			var tariff store.Tariff
+			found, err := get(tx, tariffBucket, id, &tariff)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("no tariff for index %s", id)
+			}
+			tariffs = append(tariffs, &tariff)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list tariffs: %w", err)
+	}
+	return tariffs, total, nil
+}
+
+func (s *Store) DeleteTariff(_ context.Context, id string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return deleteTariff(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("delete tariff %s: %w", id, err)
+	}
+	return nil
+}
+
+// deleteTariff removes the tariff with id and its last updated index entry
+func deleteTariff(tx *bbolt.Tx, id string) error {
+	var existing store.Tariff
+	found, err := get(tx, tariffBucket, id, &existing)
+	if err != nil || !found {
+		return err
+	}
+	key := lastUpdatedKey(existing.LastUpdated, id)
+	if err = del(tx, tariffLastUpdatedBucket, key); err != nil {
+		return err
+	}
+	return del(tx, tariffBucket, id)
+}
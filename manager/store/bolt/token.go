@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func (s *Store) SetToken(_ context.Context, token *store.Token) error {
+	tok := *token
+	tok.LastUpdated = s.clock.Now().UTC().Format(time.RFC3339)
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return put(tx, tokenBucket, tok.Uid, &tok)
+	})
+	if err != nil {
+		return fmt.Errorf("setting token: %s: %w", token.Uid, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupToken(_ context.Context, tokenUid string) (*store.Token, error) {
+	var tok store.Token
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, tokenBucket, tokenUid, &tok)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup token %s: %w", tokenUid, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &tok, nil
+}
+
+func (s *Store) ListTokens(_ context.Context, offset int, limit int) ([]*store.Token, error) {
+	tokens := make([]*store.Token, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return listOffset(tx, tokenBucket, offset, limit, func(k, v []byte) error {
+			var tok store.Token
+			if err := json.Unmarshal(v, &tok); err != nil {
+				return fmt.Errorf("map token %s: %w", k, err)
+			}
+			tokens = append(tokens, &tok)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func transactionKey(chargeStationId, transactionId string) string {
+	return fmt.Sprintf("%s:%s", chargeStationId, transactionId)
+}
+
+// modifyTransaction applies fn to the transaction identified by chargeStationId
+// and transactionId within a single write transaction. fn is passed nil if the
+// transaction does not yet exist and returns the transaction to store. The last
+// updated time of the transaction and its last updated index entry are
+// maintained here.
+func (s *Store) modifyTransaction(chargeStationId, transactionId string, fn func(transaction *store.Transaction) *store.Transaction) error {
+	key := transactionKey(chargeStationId, transactionId)
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		var transaction store.Transaction
+		found, err := get(tx, transactionBucket, key, &transaction)
+		if err != nil {
+			return err
+		}
+		var updated *store.Transaction
+		if found {
+			if err = del(tx, transactionLastUpdatedBucket, lastUpdatedKey(transaction.LastUpdated, key)); err != nil {
+				return err
+			}
+			updated = fn(&transaction)
+		} else {
+			updated = fn(nil)
+		}
+		updated.LastUpdated = s.clock.Now().UTC()
+		if err = put(tx, transactionBucket, key, updated); err != nil {
+			return err
+		}
+		index := lastUpdatedKey(updated.LastUpdated, key)
+		return tx.Bucket([]byte(transactionLastUpdatedBucket)).Put([]byte(index), []byte(key))
+	})
+}
+
+func (s *Store) CreateTransaction(_ context.Context, chargeStationId, transactionId string, evseId int, idToken, tokenType string, meterValues []store.MeterValue, seqNo int, offline bool) error {
+	err := s.modifyTransaction(chargeStationId, transactionId, func(transaction *store.Transaction) *store.Transaction {
+		if transaction == nil {
+			return &store.Transaction{
+				ChargeStationId: chargeStationId,
+				TransactionId:   transactionId,
+				EvseId:          evseId,
+				IdToken:         idToken,
+				TokenType:       tokenType,
+				MeterValues:     meterValues,
+				StartSeqNo:      seqNo,
+				Offline:         offline,
+			}
+		}
+		if evseId != 0 {
+			transaction.EvseId = evseId
+		}
+		transaction.IdToken = idToken
+		transaction.TokenType = tokenType
+		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
+		transaction.StartSeqNo = seqNo
+		transaction.Offline = offline
+		return transaction
+	})
+	if err != nil {
+		return fmt.Errorf("create transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func (s *Store) UpdateTransaction(_ context.Context, chargeStationId, transactionId string, meterValues []store.MeterValue) error {
+	err := s.modifyTransaction(chargeStationId, transactionId, func(transaction *store.Transaction) *store.Transaction {
+		if transaction == nil {
+			return &store.Transaction{
+				ChargeStationId:   chargeStationId,
+				TransactionId:     transactionId,
+				MeterValues:       meterValues,
+				UpdatedSeqNoCount: 1,
+			}
+		}
+		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
+		transaction.UpdatedSeqNoCount++
+		return transaction
+	})
+	if err != nil {
+		return fmt.Errorf("update transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func (s *Store) EndTransaction(_ context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValues []store.MeterValue, seqNo int) error {
+	err := s.modifyTransaction(chargeStationId, transactionId, func(transaction *store.Transaction) *store.Transaction {
+		if transaction == nil {
+			return &store.Transaction{
+				ChargeStationId: chargeStationId,
+				TransactionId:   transactionId,
+				IdToken:         idToken,
+				TokenType:       tokenType,
+				MeterValues:     meterValues,
+				EndedSeqNo:      seqNo,
+			}
+		}
+		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
+		transaction.EndedSeqNo = seqNo
+		return transaction
+	})
+	if err != nil {
+		return fmt.Errorf("end transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func (s *Store) FindTransaction(_ context.Context, chargeStationId, transactionId string) (*store.Transaction, error) {
+	var transaction store.Transaction
+	var found bool
+	err := s.db.View(func(tx *bbolt.Tx) (err error) {
+		found, err = get(tx, transactionBucket, transactionKey(chargeStationId, transactionId), &transaction)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &transaction, nil
+}
+
+// FindOngoingTransaction scans the transactions of the charge station, which
+// share the charge station id as a key prefix.
+func (s *Store) FindOngoingTransaction(_ context.Context, chargeStationId string, evseId int) (*store.Transaction, error) {
+	var ongoing *store.Transaction
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(transactionKey(chargeStationId, ""))
+		c := tx.Bucket([]byte(transactionBucket)).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var transaction store.Transaction
+			if err := json.Unmarshal(v, &transaction); err != nil {
+				return fmt.Errorf("map transaction %s: %w", k, err)
+			}
+			if transaction.EvseId != evseId || transaction.EndedSeqNo != 0 {
+				continue
+			}
+			if ongoing == nil || transaction.LastUpdated.After(ongoing.LastUpdated) {
+				ongoing = &transaction
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup ongoing transaction %s/%d: %w", chargeStationId, evseId, err)
+	}
+	return ongoing, nil
+}
+
+func (s *Store) Transactions(_ context.Context) ([]*store.Transaction, error) {
+	transactions := make([]*store.Transaction, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(transactionBucket)).ForEach(func(k, v []byte) error {
+			var transaction store.Transaction
+			if err := json.Unmarshal(v, &transaction); err != nil {
+				return fmt.Errorf("map transaction %s: %w", k, err)
+			}
+			transactions = append(transactions, &transaction)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (s *Store) ListTransactions(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Transaction, int, error) {
+	transactions := make([]*store.Transaction, 0)
+	var total int
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var keys []string
+		keys, total = pageByLastUpdated(tx, transactionLastUpdatedBucket, dateFrom, dateTo, offset, limit)
+		for _, key := range keys {
+			var transaction store.Transaction
+			found, err := get(tx, transactionBucket, key, &transaction)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("no transaction for index %s", key)
+			}
+			transactions = append(transactions, &transaction)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list transactions: %w", err)
+	}
+	return transactions, total, nil
+}
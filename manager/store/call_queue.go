@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+type QueuedCallState string
+
+const (
+	// QueuedCallStatePending is a call waiting to be sent to the charge station
+	QueuedCallStatePending QueuedCallState = "Pending"
+	// QueuedCallStateInFlight is a call that has been sent to the charge station
+	// and is waiting for a response
+	QueuedCallStateInFlight QueuedCallState = "InFlight"
+)
+
+// QueuedCall is an OCPP call made by the CSMS that is waiting to be delivered
+// to a charge station.
+type QueuedCall struct {
+	ChargeStationId string
+	MessageId       string
+	OcppVersion     string
+	Action          string
+	// RequestPayload is the JSON encoded OCPP request
+	RequestPayload string
+	// Priority orders the calls for a charge station: calls with a higher
+	// priority are delivered first
+	Priority int
+	State    QueuedCallState
+	// Attempts is the number of times the call has been sent to the charge station
+	Attempts      int
+	QueuedAt      time.Time
+	ExpiresAt     time.Time
+	LastAttemptAt *time.Time
+}
+
+type CallQueueStore interface {
+	SetQueuedCall(ctx context.Context, call *QueuedCall) error
+	LookupQueuedCall(ctx context.Context, chargeStationId, messageId string) (*QueuedCall, error)
+	// ListQueuedCalls returns the calls queued for the charge station in the
+	// order they are to be delivered: highest priority first and then in the
+	// order they were queued
+	ListQueuedCalls(ctx context.Context, chargeStationId string) ([]*QueuedCall, error)
+	DeleteQueuedCall(ctx context.Context, chargeStationId, messageId string) error
+}
+
+// SortQueuedCalls sorts calls into the order they are to be delivered.
+func SortQueuedCalls(calls []*QueuedCall) {
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].Priority != calls[j].Priority {
+			return calls[i].Priority > calls[j].Priority
+		}
+		if !calls[i].QueuedAt.Equal(calls[j].QueuedAt) {
+			return calls[i].QueuedAt.Before(calls[j].QueuedAt)
+		}
+		return calls[i].MessageId < calls[j].MessageId
+	})
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Cdr is the charge detail record of a completed transaction. CDRs are sent to
+// roaming partners and cannot be changed once they have been created.
+type Cdr struct {
+	Id              string
+	CountryCode     string
+	PartyId         string
+	ChargeStationId string
+	TransactionId   string
+	StartDateTime   time.Time
+	EndDateTime     time.Time
+	AuthMethod      string
+	Token           CdrToken
+	Location        CdrLocation
+	Currency        string
+	ChargingPeriods []CdrChargingPeriod
+	SignedData      *CdrSignedData
+	// TotalCost is the cost of the transaction in Currency excluding VAT
+	TotalCost float64
+	// TotalCostInclVat is the cost of the transaction in Currency including VAT
+	TotalCostInclVat float64
+	// TotalEnergy is the energy delivered in kWh
+	TotalEnergy float64
+	// TotalTime is the duration of the transaction in hours
+	TotalTime   float64
+	LastUpdated time.Time
+}
+
+type CdrToken struct {
+	Uid        string
+	Type       string
+	ContractId string
+}
+
+// CdrLocation is the location of the EVSE and connector at the time of the
+// transaction
+type CdrLocation struct {
+	Id                 string
+	Name               string
+	Address            string
+	City               string
+	PostalCode         string
+	Country            string
+	Coordinates        GeoLocation
+	EvseUid            string
+	EvseId             string
+	ConnectorId        string
+	ConnectorStandard  string
+	ConnectorFormat    string
+	ConnectorPowerType string
+}
+
+type CdrChargingPeriod struct {
+	StartDateTime time.Time
+	Dimensions    []CdrDimension
+}
+
+type CdrDimension struct {
+	Type   string
+	Volume float64
+}
+
+// CdrSignedData holds the signed meter values of the transaction so that the
+// energy delivered can be verified by the driver
+type CdrSignedData struct {
+	EncodingMethod string
+	PublicKey      string
+	SignedValues   []CdrSignedValue
+}
+
+type CdrSignedValue struct {
+	Nature     string
+	PlainData  string
+	SignedData string
+}
+
+type CdrStore interface {
+	// CreateCdr stores a new CDR. A CDR with the same id as an existing CDR is
+	// ignored: CDRs are immutable.
+	CreateCdr(ctx context.Context, cdr *Cdr) error
+	LookupCdr(ctx context.Context, id string) (*Cdr, error)
+	// ListCdrs returns a page of the CDRs last updated in the interval
+	// [dateFrom, dateTo) ordered by last updated and id, along with the total
+	// number of CDRs in the interval. A nil date leaves that end of the
+	// interval open.
+	ListCdrs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*Cdr, int, error)
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+type ChargingProfilePurpose string
+
+var (
+	ChargingProfilePurposeChargePointMaxProfile ChargingProfilePurpose = "ChargePointMaxProfile"
+	ChargingProfilePurposeTxDefaultProfile      ChargingProfilePurpose = "TxDefaultProfile"
+	ChargingProfilePurposeTxProfile             ChargingProfilePurpose = "TxProfile"
+)
+
+type ChargingProfileKind string
+
+var (
+	ChargingProfileKindAbsolute  ChargingProfileKind = "Absolute"
+	ChargingProfileKindRecurring ChargingProfileKind = "Recurring"
+	ChargingProfileKindRelative  ChargingProfileKind = "Relative"
+)
+
+type RecurrencyKind string
+
+var (
+	RecurrencyKindDaily  RecurrencyKind = "Daily"
+	RecurrencyKindWeekly RecurrencyKind = "Weekly"
+)
+
+type ChargingRateUnit string
+
+var (
+	ChargingRateUnitA ChargingRateUnit = "A"
+	ChargingRateUnitW ChargingRateUnit = "W"
+)
+
+type ChargingProfileStatus string
+
+var (
+	ChargingProfileStatusPending      ChargingProfileStatus = "Pending"
+	ChargingProfileStatusAccepted     ChargingProfileStatus = "Accepted"
+	ChargingProfileStatusRejected     ChargingProfileStatus = "Rejected"
+	ChargingProfileStatusNotSupported ChargingProfileStatus = "NotSupported"
+	// ChargingProfileStatusClearPending marks a profile that should be removed from
+	// the charge station. The profile is deleted once the charge station confirms.
+	ChargingProfileStatusClearPending ChargingProfileStatus = "ClearPending"
+)
+
+type ChargingSchedulePeriod struct {
+	StartPeriod  int
+	Limit        float64
+	NumberPhases *int
+}
+
+type ChargingSchedule struct {
+	Duration                *int
+	StartSchedule           *time.Time
+	ChargingRateUnit        ChargingRateUnit
+	ChargingSchedulePeriods []ChargingSchedulePeriod
+	MinChargingRate         *float64
+}
+
+// ChargingProfile is a charging profile that should be installed on a charge station.
+// ConnectorId is 0 when the profile applies to the whole charge station. For
+// OCPP 2.0.1 charge stations ConnectorId holds the EVSE id.
+type ChargingProfile struct {
+	ChargingProfileId      int
+	ConnectorId            int
+	TransactionId          *string
+	StackLevel             int
+	ChargingProfilePurpose ChargingProfilePurpose
+	ChargingProfileKind    ChargingProfileKind
+	RecurrencyKind         *RecurrencyKind
+	ValidFrom              *time.Time
+	ValidTo                *time.Time
+	ChargingSchedule       ChargingSchedule
+	Status                 ChargingProfileStatus
+	SendAfter              time.Time
+	// LoadBalanced marks a TxProfile created by the load balancer: the load
+	// balancer only changes the profiles that it created
+	LoadBalanced bool
+}
+
+type ChargeStationChargingProfiles struct {
+	ChargeStationId  string
+	ChargingProfiles []*ChargingProfile
+}
+
+// CompositeSchedule is the schedule most recently reported by the charge station
+// for a connector (or EVSE for OCPP 2.0.1) as the result of combining all the
+// installed charging profiles.
+type CompositeSchedule struct {
+	ConnectorId      int
+	ScheduleStart    *time.Time
+	ChargingSchedule *ChargingSchedule
+	RetrievedAt      time.Time
+}
+
+type ChargeStationChargingProfilesStore interface {
+	UpdateChargeStationChargingProfiles(ctx context.Context, chargeStationId string, profiles *ChargeStationChargingProfiles) error
+	LookupChargeStationChargingProfiles(ctx context.Context, chargeStationId string) (*ChargeStationChargingProfiles, error)
+	ListChargeStationChargingProfiles(ctx context.Context, pageSize int, previousChargeStationId string) ([]*ChargeStationChargingProfiles, error)
+	DeleteChargeStationChargingProfile(ctx context.Context, chargeStationId string, chargingProfileId int) error
+	SetChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, schedule *CompositeSchedule) error
+	LookupChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, connectorId int) (*CompositeSchedule, error)
+}
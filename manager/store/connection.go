@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ChargeStationConnection is the state of a charge station's websocket
+// connection to a gateway, as reported by the gateway.
+type ChargeStationConnection struct {
+	ChargeStationId string
+	// Connected is true from when the gateway reports the charge station has
+	// connected until it reports the charge station has disconnected
+	Connected bool
+	// GatewayId identifies the gateway instance the charge station is, or
+	// was last, connected to
+	GatewayId string
+	// ConnectionId identifies the websocket connection
+	ConnectionId    string
+	Protocol        string
+	RemoteAddr      string
+	SecurityProfile SecurityProfile
+	// CertificateHash is the base64url encoded SHA-256 hash of the client
+	// certificate, if the charge station presented one
+	CertificateHash string
+	ConnectedAt     time.Time
+	DisconnectedAt  *time.Time
+	// LastSeen is the time a message was last received from the charge station
+	LastSeen time.Time
+}
+
+type ChargeStationConnectionStore interface {
+	SetChargeStationConnection(ctx context.Context, chargeStationId string, connection *ChargeStationConnection) error
+	LookupChargeStationConnection(ctx context.Context, chargeStationId string) (*ChargeStationConnection, error)
+	ListChargeStationConnections(ctx context.Context, offset, limit int) ([]*ChargeStationConnection, error)
+	// SetChargeStationLastSeen updates the LastSeen time of the charge station's
+	// connection, creating a connection that is not Connected if the charge
+	// station does not have one
+	SetChargeStationLastSeen(ctx context.Context, chargeStationId string, lastSeen time.Time) error
+	// SetChargeStationDisconnected marks the charge station's connection as not
+	// Connected at disconnectedAt if it is still the connection identified by
+	// connectionId. It returns false, without changing anything, if the charge
+	// station has no connection or has since reconnected.
+	SetChargeStationDisconnected(ctx context.Context, chargeStationId, connectionId string, disconnectedAt time.Time) (bool, error)
+}
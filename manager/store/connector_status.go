@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ConnectorStatus is the status most recently reported by a charge station for
+// a connector. OCPP 1.6 charge stations have no EVSEs so EvseId is set to the
+// connector id. EvseId and ConnectorId are both 0 for the status of the whole
+// charge station.
+type ConnectorStatus struct {
+	EvseId      int
+	ConnectorId int
+	// Status is the OCPP connector status, e.g. Available, Charging (1.6) or
+	// Occupied (2.0.1)
+	Status string
+	// ErrorCode, Info, VendorId and VendorErrorCode are only reported by OCPP 1.6
+	// charge stations
+	ErrorCode       *string
+	Info            *string
+	VendorId        *string
+	VendorErrorCode *string
+	// Timestamp is the time the status was reported, or the time the status was
+	// received if the charge station does not report a timestamp
+	Timestamp time.Time
+}
+
+type ChargeStationConnectorStatusStore interface {
+	SetChargeStationConnectorStatus(ctx context.Context, chargeStationId string, status *ConnectorStatus) error
+	LookupChargeStationConnectorStatus(ctx context.Context, chargeStationId string, evseId, connectorId int) (*ConnectorStatus, error)
+	ListChargeStationConnectorStatuses(ctx context.Context, chargeStationId string) ([]*ConnectorStatus, error)
+}
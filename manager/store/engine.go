@@ -8,9 +8,19 @@ type Engine interface {
 	ChargeStationRuntimeDetailsStore
 	ChargeStationInstallCertificatesStore
 	ChargeStationTriggerMessageStore
+	ChargeStationChargingProfilesStore
+	ChargeStationConnectorStatusStore
+	ChargeStationConnectionStore
+	CallQueueStore
+	ChargeStationMeterReadingStore
+	ChargeStationMeterPublicKeyStore
 	TokenStore
 	TransactionStore
 	CertificateStore
 	OcpiStore
 	LocationStore
+	CdrStore
+	TariffStore
+	ReservationStore
+	EvseMappingStore
 }
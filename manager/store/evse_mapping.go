@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import "context"
+
+// EvseMapping maps an OCPI EVSE to the EVSE of an OCPP charge station. The OCPI
+// EVSE is identified by its location id and uid and, optionally, by its eMI3
+// EVSE ID.
+type EvseMapping struct {
+	LocationId string
+	EvseUid    string
+	EvseId     *string
+	// ChargeStationId and ChargeStationEvseId identify the EVSE of the charge
+	// station: the ChargeStationEvseId is 0 if the OCPI EVSE is the whole charge
+	// station.
+	ChargeStationId     string
+	ChargeStationEvseId int
+	// Connectors maps the ids of the OCPI connectors to the ids of the OCPP
+	// connectors: an OCPI connector that is not mapped has the same id as the
+	// OCPP connector.
+	Connectors []ConnectorMapping
+}
+
+type ConnectorMapping struct {
+	Id          string
+	ConnectorId int
+}
+
+type EvseMappingStore interface {
+	SetEvseMapping(ctx context.Context, mapping *EvseMapping) error
+	LookupEvseMapping(ctx context.Context, locationId, evseUid string) (*EvseMapping, error)
+	LookupEvseMappingByEvseId(ctx context.Context, evseId string) (*EvseMapping, error)
+	// ListEvseMappingsForChargeStation returns the mappings to the EVSEs of the
+	// charge station ordered by location id and EVSE uid
+	ListEvseMappingsForChargeStation(ctx context.Context, chargeStationId string) ([]*EvseMapping, error)
+	DeleteEvseMapping(ctx context.Context, locationId, evseUid string) error
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type queuedCall struct {
+	ChargeStationId string     `firestore:"cs"`
+	MessageId       string     `firestore:"id"`
+	OcppVersion     string     `firestore:"v"`
+	Action          string     `firestore:"a"`
+	RequestPayload  string     `firestore:"req"`
+	Priority        int        `firestore:"p"`
+	State           string     `firestore:"s"`
+	Attempts        int        `firestore:"n"`
+	QueuedAt        time.Time  `firestore:"qa"`
+	ExpiresAt       time.Time  `firestore:"ea"`
+	LastAttemptAt   *time.Time `firestore:"la"`
+}
+
+func (c *queuedCall) toStore() *store.QueuedCall {
+	call := &store.QueuedCall{
+		ChargeStationId: c.ChargeStationId,
+		MessageId:       c.MessageId,
+		OcppVersion:     c.OcppVersion,
+		Action:          c.Action,
+		RequestPayload:  c.RequestPayload,
+		Priority:        c.Priority,
+		State:           store.QueuedCallState(c.State),
+		Attempts:        c.Attempts,
+		QueuedAt:        c.QueuedAt.UTC(),
+		ExpiresAt:       c.ExpiresAt.UTC(),
+	}
+	if c.LastAttemptAt != nil {
+		lastAttemptAt := c.LastAttemptAt.UTC()
+		call.LastAttemptAt = &lastAttemptAt
+	}
+	return call
+}
+
+func (s *Store) queuedCallRef(chargeStationId, messageId string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("QueuedCall/%s:%s", chargeStationId, messageId))
+}
+
+func (s *Store) SetQueuedCall(ctx context.Context, call *store.QueuedCall) error {
+	_, err := s.queuedCallRef(call.ChargeStationId, call.MessageId).Set(ctx, &queuedCall{
+		ChargeStationId: call.ChargeStationId,
+		MessageId:       call.MessageId,
+		OcppVersion:     call.OcppVersion,
+		Action:          call.Action,
+		RequestPayload:  call.RequestPayload,
+		Priority:        call.Priority,
+		State:           string(call.State),
+		Attempts:        call.Attempts,
+		QueuedAt:        call.QueuedAt,
+		ExpiresAt:       call.ExpiresAt,
+		LastAttemptAt:   call.LastAttemptAt,
+	})
+	if err != nil {
+		return fmt.Errorf("setting queued call %s/%s: %w", call.ChargeStationId, call.MessageId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupQueuedCall(ctx context.Context, chargeStationId, messageId string) (*store.QueuedCall, error) {
+	snap, err := s.queuedCallRef(chargeStationId, messageId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	var callData queuedCall
+	if err = snap.DataTo(&callData); err != nil {
+		return nil, fmt.Errorf("map queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return callData.toStore(), nil
+}
+
+func (s *Store) ListQueuedCalls(ctx context.Context, chargeStationId string) ([]*store.QueuedCall, error) {
+	snaps, err := s.client.Collection("QueuedCall").Where("cs", "==", chargeStationId).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list queued calls %s: %w", chargeStationId, err)
+	}
+	calls := make([]*store.QueuedCall, 0, len(snaps))
+	for _, snap := range snaps {
+		var callData queuedCall
+		if err = snap.DataTo(&callData); err != nil {
+			return nil, fmt.Errorf("map queued call %s: %w", chargeStationId, err)
+		}
+		calls = append(calls, callData.toStore())
+	}
+	store.SortQueuedCalls(calls)
+	return calls, nil
+}
+
+func (s *Store) DeleteQueuedCall(ctx context.Context, chargeStationId, messageId string) error {
+	_, err := s.queuedCallRef(chargeStationId, messageId).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return nil
+}
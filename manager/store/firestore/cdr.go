@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Store) CreateCdr(ctx context.Context, cdr *store.Cdr) error {
+	cdrRef := s.client.Doc(fmt.Sprintf("Cdr/%s", cdr.Id))
+	_, err := cdrRef.Create(ctx, cdr)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return nil
+		}
+		return fmt.Errorf("create cdr %s: %w", cdr.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupCdr(ctx context.Context, id string) (*store.Cdr, error) {
+	cdrRef := s.client.Doc(fmt.Sprintf("Cdr/%s", id))
+	snap, err := cdrRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup cdr %s: %w", id, err)
+	}
+	var cdr store.Cdr
+	if err = snap.DataTo(&cdr); err != nil {
+		return nil, fmt.Errorf("map cdr %s: %w", id, err)
+	}
+	return utcCdr(&cdr), nil
+}
+
+func (s *Store) ListCdrs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Cdr, int, error) {
+	query := s.client.Collection("Cdr").Query
+	if dateFrom != nil {
+		query = query.Where("LastUpdated", ">=", *dateFrom)
+	}
+	if dateTo != nil {
+		query = query.Where("LastUpdated", "<", *dateTo)
+	}
+
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count cdrs: %w", err)
+	}
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, 0, fmt.Errorf("count cdrs: unexpected result %T", result["total"])
+	}
+
+	snaps, err := query.OrderBy("LastUpdated", firestore.Asc).OrderBy("Id", firestore.Asc).
+		Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list cdrs: %w", err)
+	}
+	cdrs := make([]*store.Cdr, 0)
+	for _, snap := range snaps {
+		var cdr store.Cdr
+		if err = snap.DataTo(&cdr); err != nil {
+			return nil, 0, fmt.Errorf("map cdr: %w", err)
+		}
+		cdrs = append(cdrs, utcCdr(&cdr))
+	}
+	return cdrs, int(count.GetIntegerValue()), nil
+}
+
+// utcCdr converts the times of the CDR to UTC: firestore returns times in the
+// local time zone.
+func utcCdr(cdr *store.Cdr) *store.Cdr {
+	cdr.StartDateTime = cdr.StartDateTime.UTC()
+	cdr.EndDateTime = cdr.EndDateTime.UTC()
+	cdr.LastUpdated = cdr.LastUpdated.UTC()
+	for i := range cdr.ChargingPeriods {
+		cdr.ChargingPeriods[i].StartDateTime = cdr.ChargingPeriods[i].StartDateTime.UTC()
+	}
+	return cdr
+}
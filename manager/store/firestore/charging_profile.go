@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type chargingProfile struct {
+	ConnectorId            int                    `firestore:"c"`
+	TransactionId          *string                `firestore:"tx"`
+	StackLevel             int                    `firestore:"l"`
+	ChargingProfilePurpose string                 `firestore:"p"`
+	ChargingProfileKind    string                 `firestore:"k"`
+	RecurrencyKind         *string                `firestore:"r"`
+	ValidFrom              *time.Time             `firestore:"vf"`
+	ValidTo                *time.Time             `firestore:"vt"`
+	ChargingSchedule       store.ChargingSchedule `firestore:"cs"`
+	Status                 string                 `firestore:"s"`
+	SendAfter              time.Time              `firestore:"u"`
+	LoadBalanced           bool                   `firestore:"lb"`
+}
+
+func mapChargingProfiles(profiles map[string]*chargingProfile) ([]*store.ChargingProfile, error) {
+	var result []*store.ChargingProfile
+	for id, p := range profiles {
+		profileId, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid charging profile id %s: %w", id, err)
+		}
+		result = append(result, &store.ChargingProfile{
+			ChargingProfileId:      profileId,
+			ConnectorId:            p.ConnectorId,
+			TransactionId:          p.TransactionId,
+			StackLevel:             p.StackLevel,
+			ChargingProfilePurpose: store.ChargingProfilePurpose(p.ChargingProfilePurpose),
+			ChargingProfileKind:    store.ChargingProfileKind(p.ChargingProfileKind),
+			RecurrencyKind:         (*store.RecurrencyKind)(p.RecurrencyKind),
+			ValidFrom:              p.ValidFrom,
+			ValidTo:                p.ValidTo,
+			ChargingSchedule:       p.ChargingSchedule,
+			Status:                 store.ChargingProfileStatus(p.Status),
+			SendAfter:              p.SendAfter,
+			LoadBalanced:           p.LoadBalanced,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ChargingProfileId < result[j].ChargingProfileId
+	})
+	return result, nil
+}
+
+func (s *Store) UpdateChargeStationChargingProfiles(ctx context.Context, chargeStationId string, profiles *store.ChargeStationChargingProfiles) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationChargingProfiles/%s", chargeStationId))
+	var set = make(map[string]*chargingProfile)
+	for _, p := range profiles.ChargingProfiles {
+		set[strconv.Itoa(p.ChargingProfileId)] = &chargingProfile{
+			ConnectorId:            p.ConnectorId,
+			TransactionId:          p.TransactionId,
+			StackLevel:             p.StackLevel,
+			ChargingProfilePurpose: string(p.ChargingProfilePurpose),
+			ChargingProfileKind:    string(p.ChargingProfileKind),
+			RecurrencyKind:         (*string)(p.RecurrencyKind),
+			ValidFrom:              p.ValidFrom,
+			ValidTo:                p.ValidTo,
+			ChargingSchedule:       p.ChargingSchedule,
+			Status:                 string(p.Status),
+			SendAfter:              p.SendAfter,
+			LoadBalanced:           p.LoadBalanced,
+		}
+	}
+	_, err := csRef.Set(ctx, set, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("update charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationChargingProfiles(ctx context.Context, chargeStationId string) (*store.ChargeStationChargingProfiles, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationChargingProfiles/%s", chargeStationId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	var csData map[string]*chargingProfile
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	if len(csData) == 0 {
+		return nil, nil
+	}
+	profiles, err := mapChargingProfiles(csData)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	return &store.ChargeStationChargingProfiles{
+		ChargeStationId:  chargeStationId,
+		ChargingProfiles: profiles,
+	}, nil
+}
+
+func (s *Store) ListChargeStationChargingProfiles(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationChargingProfiles, error) {
+	var chargingProfiles []*store.ChargeStationChargingProfiles
+	var docIt *firestore.DocumentIterator
+	if previousCsId == "" {
+		docIt = s.client.Collection("ChargeStationChargingProfiles").OrderBy(firestore.DocumentID, firestore.Asc).
+			Limit(pageSize).Documents(ctx)
+	} else {
+		docIt = s.client.Collection("ChargeStationChargingProfiles").OrderBy(firestore.DocumentID, firestore.Asc).
+			StartAfter(previousCsId).Limit(pageSize).Documents(ctx)
+	}
+	snaps, err := docIt.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station charging profiles: %w", err)
+	}
+	for _, snap := range snaps {
+		var csData map[string]*chargingProfile
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station charging profiles: %w", err)
+		}
+		profiles, err := mapChargingProfiles(csData)
+		if err != nil {
+			return nil, fmt.Errorf("map charge station charging profiles: %w", err)
+		}
+		chargingProfiles = append(chargingProfiles, &store.ChargeStationChargingProfiles{
+			ChargeStationId:  snap.Ref.ID,
+			ChargingProfiles: profiles,
+		})
+	}
+	return chargingProfiles, nil
+}
+
+func (s *Store) DeleteChargeStationChargingProfile(ctx context.Context, chargeStationId string, chargingProfileId int) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationChargingProfiles/%s", chargeStationId))
+	_, err := csRef.Update(ctx, []firestore.Update{
+		{FieldPath: firestore.FieldPath{strconv.Itoa(chargingProfileId)}, Value: firestore.Delete},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("delete charge station charging profile %s/%d: %w", chargeStationId, chargingProfileId, err)
+	}
+	return nil
+}
+
+type compositeSchedule struct {
+	ConnectorId      int                     `firestore:"c"`
+	ScheduleStart    *time.Time              `firestore:"st"`
+	ChargingSchedule *store.ChargingSchedule `firestore:"cs"`
+	RetrievedAt      time.Time               `firestore:"r"`
+}
+
+func (s *Store) SetChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, schedule *store.CompositeSchedule) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationCompositeSchedule/%s:%d", chargeStationId, schedule.ConnectorId))
+	_, err := csRef.Set(ctx, &compositeSchedule{
+		ConnectorId:      schedule.ConnectorId,
+		ScheduleStart:    schedule.ScheduleStart,
+		ChargingSchedule: schedule.ChargingSchedule,
+		RetrievedAt:      schedule.RetrievedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station composite schedule %s/%d: %w", chargeStationId, schedule.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, connectorId int) (*store.CompositeSchedule, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationCompositeSchedule/%s:%d", chargeStationId, connectorId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station composite schedule %s/%d: %w", chargeStationId, connectorId, err)
+	}
+	var csData compositeSchedule
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station composite schedule %s/%d: %w", chargeStationId, connectorId, err)
+	}
+	return &store.CompositeSchedule{
+		ConnectorId:      csData.ConnectorId,
+		ScheduleStart:    csData.ScheduleStart,
+		ChargingSchedule: csData.ChargingSchedule,
+		RetrievedAt:      csData.RetrievedAt,
+	}, nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type connection struct {
+	ChargeStationId string     `firestore:"cs"`
+	Connected       bool       `firestore:"c"`
+	GatewayId       string     `firestore:"g"`
+	ConnectionId    string     `firestore:"id"`
+	Protocol        string     `firestore:"p"`
+	RemoteAddr      string     `firestore:"ra"`
+	SecurityProfile int        `firestore:"prof"`
+	CertificateHash string     `firestore:"ch"`
+	ConnectedAt     time.Time  `firestore:"ca"`
+	DisconnectedAt  *time.Time `firestore:"da"`
+	LastSeen        time.Time  `firestore:"ls"`
+}
+
+func (c *connection) toStore() *store.ChargeStationConnection {
+	conn := &store.ChargeStationConnection{
+		ChargeStationId: c.ChargeStationId,
+		Connected:       c.Connected,
+		GatewayId:       c.GatewayId,
+		ConnectionId:    c.ConnectionId,
+		Protocol:        c.Protocol,
+		RemoteAddr:      c.RemoteAddr,
+		SecurityProfile: store.SecurityProfile(c.SecurityProfile),
+		CertificateHash: c.CertificateHash,
+		ConnectedAt:     c.ConnectedAt.UTC(),
+		LastSeen:        c.LastSeen.UTC(),
+	}
+	if c.DisconnectedAt != nil {
+		disconnectedAt := c.DisconnectedAt.UTC()
+		conn.DisconnectedAt = &disconnectedAt
+	}
+	return conn
+}
+
+func (s *Store) connectionRef(chargeStationId string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("ChargeStationConnection/%s", chargeStationId))
+}
+
+func (s *Store) SetChargeStationConnection(ctx context.Context, chargeStationId string, conn *store.ChargeStationConnection) error {
+	_, err := s.connectionRef(chargeStationId).Set(ctx, &connection{
+		ChargeStationId: chargeStationId,
+		Connected:       conn.Connected,
+		GatewayId:       conn.GatewayId,
+		ConnectionId:    conn.ConnectionId,
+		Protocol:        conn.Protocol,
+		RemoteAddr:      conn.RemoteAddr,
+		SecurityProfile: int(conn.SecurityProfile),
+		CertificateHash: conn.CertificateHash,
+		ConnectedAt:     conn.ConnectedAt,
+		DisconnectedAt:  conn.DisconnectedAt,
+		LastSeen:        conn.LastSeen,
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station connection %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnection(ctx context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	snap, err := s.connectionRef(chargeStationId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station connection %s: %w", chargeStationId, err)
+	}
+	var connData connection
+	if err = snap.DataTo(&connData); err != nil {
+		return nil, fmt.Errorf("map charge station connection %s: %w", chargeStationId, err)
+	}
+	return connData.toStore(), nil
+}
+
+func (s *Store) ListChargeStationConnections(ctx context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	connections := make([]*store.ChargeStationConnection, 0)
+	iter := s.client.Collection("ChargeStationConnection").OrderBy("cs", firestore.Asc).Offset(offset).Limit(limit).Documents(ctx)
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("next charge station connection: %w", err)
+		}
+		var connData connection
+		if err = snap.DataTo(&connData); err != nil {
+			return nil, fmt.Errorf("map charge station connection %s: %w", snap.Ref.ID, err)
+		}
+		connections = append(connections, connData.toStore())
+	}
+	return connections, nil
+}
+
+func (s *Store) SetChargeStationLastSeen(ctx context.Context, chargeStationId string, lastSeen time.Time) error {
+	_, err := s.connectionRef(chargeStationId).Set(ctx, map[string]any{
+		"cs": chargeStationId,
+		"ls": lastSeen,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("setting charge station last seen %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) SetChargeStationDisconnected(ctx context.Context, chargeStationId, connectionId string, disconnectedAt time.Time) (bool, error) {
+	var disconnected bool
+	ref := s.connectionRef(chargeStationId)
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		disconnected = false
+		snap, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+		var connData connection
+		if err = snap.DataTo(&connData); err != nil {
+			return err
+		}
+		if connData.ConnectionId != connectionId {
+			return nil
+		}
+		connData.Connected = false
+		connData.DisconnectedAt = &disconnectedAt
+		if disconnectedAt.After(connData.LastSeen) {
+			connData.LastSeen = disconnectedAt
+		}
+		disconnected = true
+		return tx.Set(ref, &connData)
+	})
+	if err != nil {
+		return false, fmt.Errorf("setting charge station disconnected %s: %w", chargeStationId, err)
+	}
+	return disconnected, nil
+}
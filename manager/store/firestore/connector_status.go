@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type connectorStatus struct {
+	ChargeStationId string    `firestore:"cs"`
+	EvseId          int       `firestore:"e"`
+	ConnectorId     int       `firestore:"c"`
+	Status          string    `firestore:"s"`
+	ErrorCode       *string   `firestore:"ec"`
+	Info            *string   `firestore:"i"`
+	VendorId        *string   `firestore:"v"`
+	VendorErrorCode *string   `firestore:"vec"`
+	Timestamp       time.Time `firestore:"t"`
+}
+
+func (c *connectorStatus) toStore() *store.ConnectorStatus {
+	return &store.ConnectorStatus{
+		EvseId:          c.EvseId,
+		ConnectorId:     c.ConnectorId,
+		Status:          c.Status,
+		ErrorCode:       c.ErrorCode,
+		Info:            c.Info,
+		VendorId:        c.VendorId,
+		VendorErrorCode: c.VendorErrorCode,
+		Timestamp:       c.Timestamp.UTC(),
+	}
+}
+
+func (s *Store) connectorStatusRef(chargeStationId string, evseId, connectorId int) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("ChargeStationConnectorStatus/%s:%d:%d", chargeStationId, evseId, connectorId))
+}
+
+func (s *Store) SetChargeStationConnectorStatus(ctx context.Context, chargeStationId string, connStatus *store.ConnectorStatus) error {
+	_, err := s.connectorStatusRef(chargeStationId, connStatus.EvseId, connStatus.ConnectorId).Set(ctx, &connectorStatus{
+		ChargeStationId: chargeStationId,
+		EvseId:          connStatus.EvseId,
+		ConnectorId:     connStatus.ConnectorId,
+		Status:          connStatus.Status,
+		ErrorCode:       connStatus.ErrorCode,
+		Info:            connStatus.Info,
+		VendorId:        connStatus.VendorId,
+		VendorErrorCode: connStatus.VendorErrorCode,
+		Timestamp:       connStatus.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("setting charge station connector status %s/%d/%d: %w", chargeStationId, connStatus.EvseId, connStatus.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnectorStatus(ctx context.Context, chargeStationId string, evseId, connectorId int) (*store.ConnectorStatus, error) {
+	snap, err := s.connectorStatusRef(chargeStationId, evseId, connectorId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station connector status %s/%d/%d: %w", chargeStationId, evseId, connectorId, err)
+	}
+	var csData connectorStatus
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station connector status %s/%d/%d: %w", chargeStationId, evseId, connectorId, err)
+	}
+	return csData.toStore(), nil
+}
+
+func (s *Store) ListChargeStationConnectorStatuses(ctx context.Context, chargeStationId string) ([]*store.ConnectorStatus, error) {
+	snaps, err := s.client.Collection("ChargeStationConnectorStatus").Where("cs", "==", chargeStationId).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station connector statuses %s: %w", chargeStationId, err)
+	}
+	var statuses []*store.ConnectorStatus
+	for _, snap := range snaps {
+		var csData connectorStatus
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station connector status %s: %w", chargeStationId, err)
+		}
+		statuses = append(statuses, csData.toStore())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].EvseId != statuses[j].EvseId {
+			return statuses[i].EvseId < statuses[j].EvseId
+		}
+		return statuses[i].ConnectorId < statuses[j].ConnectorId
+	})
+	return statuses, nil
+}
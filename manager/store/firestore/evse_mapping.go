@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Store) evseMappingRef(locationId, evseUid string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("EvseMapping/%s:%s", locationId, evseUid))
+}
+
+func (s *Store) SetEvseMapping(ctx context.Context, mapping *store.EvseMapping) error {
+	_, err := s.evseMappingRef(mapping.LocationId, mapping.EvseUid).Set(ctx, mapping)
+	if err != nil {
+		return fmt.Errorf("set evse mapping %s/%s: %w", mapping.LocationId, mapping.EvseUid, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupEvseMapping(ctx context.Context, locationId, evseUid string) (*store.EvseMapping, error) {
+	snap, err := s.evseMappingRef(locationId, evseUid).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup evse mapping %s/%s: %w", locationId, evseUid, err)
+	}
+	var mapping store.EvseMapping
+	if err = snap.DataTo(&mapping); err != nil {
+		return nil, fmt.Errorf("map evse mapping %s/%s: %w", locationId, evseUid, err)
+	}
+	return &mapping, nil
+}
+
+func (s *Store) LookupEvseMappingByEvseId(ctx context.Context, evseId string) (*store.EvseMapping, error) {
+	iter := s.client.Collection("EvseMapping").Where("EvseId", "==", evseId).Limit(1).Documents(ctx)
+	defer iter.Stop()
+	snap, err := iter.Next()
+	if err == iterator.Done {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup evse mapping for evse id %s: %w", evseId, err)
+	}
+	var mapping store.EvseMapping
+	if err = snap.DataTo(&mapping); err != nil {
+		return nil, fmt.Errorf("map evse mapping for evse id %s: %w", evseId, err)
+	}
+	return &mapping, nil
+}
+
+func (s *Store) ListEvseMappingsForChargeStation(ctx context.Context, chargeStationId string) ([]*store.EvseMapping, error) {
+	iter := s.client.Collection("EvseMapping").Where("ChargeStationId", "==", chargeStationId).Documents(ctx)
+	defer iter.Stop()
+	var mappings []*store.EvseMapping
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list evse mappings for %s: %w", chargeStationId, err)
+		}
+		var mapping store.EvseMapping
+		if err = snap.DataTo(&mapping); err != nil {
+			return nil, fmt.Errorf("map evse mapping %s: %w", snap.Ref.ID, err)
+		}
+		mappings = append(mappings, &mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].LocationId != mappings[j].LocationId {
+			return mappings[i].LocationId < mappings[j].LocationId
+		}
+		return mappings[i].EvseUid < mappings[j].EvseUid
+	})
+	return mappings, nil
+}
+
+func (s *Store) DeleteEvseMapping(ctx context.Context, locationId, evseUid string) error {
+	_, err := s.evseMappingRef(locationId, evseUid).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete evse mapping %s/%s: %w", locationId, evseUid, err)
+	}
+	return nil
+}
@@ -4,17 +4,24 @@ package firestore
 
 import (
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"context"
 	"fmt"
 	"github.com/zynka-tech/zynka-csms/manager/store"
-	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
+// lastUpdatedFormat is the format of the LastUpdated of a location: it sorts in
+// the same order as the times it represents.
+const lastUpdatedFormat = "2006-01-02T15:04:05Z"
+
 func (s *Store) SetLocation(ctx context.Context, loc *store.Location) error {
+	location := *loc
+	location.LastUpdated = s.clock.Now().UTC().Format(lastUpdatedFormat)
 	locationRef := s.client.Doc(fmt.Sprintf("Location/%s", loc.Id))
-	_, err := locationRef.Set(ctx, loc)
+	_, err := locationRef.Set(ctx, &location)
 	if err != nil {
 		return fmt.Errorf("setting location %s: %w", loc.Id, err)
 	}
@@ -34,30 +41,65 @@ func (s *Store) LookupLocation(ctx context.Context, locationId string) (*store.L
 	if err = snap.DataTo(&location); err != nil {
 		return nil, fmt.Errorf("lookup location %s: %w", locationId, err)
 	}
-	location.LastUpdated = snap.UpdateTime.Format("2006-01-02T15:04:05Z")
 	return &location, nil
 }
 
-func (s *Store) ListLocations(context context.Context, offset int, limit int) ([]*store.Location, error) {
-	var locations []*store.Location
-	iter := s.client.Collection("Location").OrderBy("Id", firestore.Asc).Offset(offset).Limit(limit).Documents(context)
-	for {
-		snap, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("next location: %w", err)
-		}
+func (s *Store) ListLocations(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Location, int, error) {
+	query := s.client.Collection("Location").Query
+	if dateFrom != nil {
+		query = query.Where("LastUpdated", ">=", dateFrom.UTC().Format(lastUpdatedFormat))
+	}
+	if dateTo != nil {
+		query = query.Where("LastUpdated", "<", dateTo.UTC().Format(lastUpdatedFormat))
+	}
+
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count locations: %w", err)
+	}
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, 0, fmt.Errorf("count locations: unexpected result %T", result["total"])
+	}
+
+	snaps, err := query.OrderBy("Id", firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list locations: %w", err)
+	}
+	locations := make([]*store.Location, 0)
+	for _, snap := range snaps {
 		var loc store.Location
 		if err = snap.DataTo(&loc); err != nil {
-			return nil, fmt.Errorf("map location: %w", err)
+			return nil, 0, fmt.Errorf("map location: %w", err)
 		}
-		loc.LastUpdated = snap.UpdateTime.Format("2006-01-02T15:04:05Z")
 		locations = append(locations, &loc)
 	}
-	if locations == nil {
-		locations = make([]*store.Location, 0)
+	return locations, int(count.GetIntegerValue()), nil
+}
+
+func (s *Store) clientOwnedLocationRef(countryCode, partyId, locationId string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("ClientOwnedLocation/%s:%s:%s", countryCode, partyId, locationId))
+}
+
+func (s *Store) SetClientOwnedLocation(ctx context.Context, loc *store.Location) error {
+	_, err := s.clientOwnedLocationRef(loc.CountryCode, loc.PartyId, loc.Id).Set(ctx, loc)
+	if err != nil {
+		return fmt.Errorf("setting client owned location %s/%s/%s: %w", loc.CountryCode, loc.PartyId, loc.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupClientOwnedLocation(ctx context.Context, countryCode, partyId, locationId string) (*store.Location, error) {
+	snap, err := s.clientOwnedLocationRef(countryCode, partyId, locationId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup client owned location %s/%s/%s: %w", countryCode, partyId, locationId, err)
 	}
-	return locations, nil
+	var location store.Location
+	if err = snap.DataTo(&location); err != nil {
+		return nil, fmt.Errorf("map client owned location %s/%s/%s: %w", countryCode, partyId, locationId, err)
+	}
+	return &location, nil
 }
@@ -77,9 +77,10 @@ func TestListLocations(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	got, err := locationStore.ListLocations(ctx, 0, 10)
+	got, total, err := locationStore.ListLocations(ctx, nil, nil, 0, 10)
 	require.NoError(t, err)
 
+	assert.Equal(t, 20, total)
 	assert.Equal(t, 10, len(got))
 	for i, loc := range got {
 		loc.LastUpdated = ""
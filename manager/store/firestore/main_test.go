@@ -79,6 +79,7 @@ func cleanupAllCollections(t *testing.T, gcloudProject string) {
 	cleanupCollection(t, gcloudProject, "ChargeStationSettings")
 	cleanupCollection(t, gcloudProject, "ChargeStationInstallCertificates")
 	cleanupCollection(t, gcloudProject, "ChargeStationRuntimeDetails")
+	cleanupCollection(t, gcloudProject, "ChargeStationTriggerMessage")
 	cleanupCollection(t, gcloudProject, "Location")
 	cleanupCollection(t, gcloudProject, "OcpiParty")
 	cleanupCollection(t, gcloudProject, "OcpiRegistration")
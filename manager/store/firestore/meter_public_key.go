@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Store) SetChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, key *store.MeterPublicKey) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationMeterPublicKey/%s", chargeStationId))
+	_, err := csRef.Set(ctx, map[string]string{
+		strconv.Itoa(key.EvseId): key.PublicKey,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("setting charge station meter public key %s/%d: %w", chargeStationId, key.EvseId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterPublicKeys(ctx context.Context, chargeStationId string) ([]*store.MeterPublicKey, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationMeterPublicKey/%s", chargeStationId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list charge station meter public keys %s: %w", chargeStationId, err)
+	}
+	var csData map[string]string
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station meter public keys %s: %w", chargeStationId, err)
+	}
+	var keys []*store.MeterPublicKey
+	for id, publicKey := range csData {
+		evseId, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid evse id %s: %w", id, err)
+		}
+		keys = append(keys, &store.MeterPublicKey{
+			EvseId:    evseId,
+			PublicKey: publicKey,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].EvseId < keys[j].EvseId
+	})
+	return keys, nil
+}
+
+func (s *Store) DeleteChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, evseId int) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationMeterPublicKey/%s", chargeStationId))
+	_, err := csRef.Update(ctx, []firestore.Update{
+		{FieldPath: firestore.FieldPath{strconv.Itoa(evseId)}, Value: firestore.Delete},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("delete charge station meter public key %s/%d: %w", chargeStationId, evseId, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+type meterReading struct {
+	ConnectorId   int                  `firestore:"c"`
+	Timestamp     time.Time            `firestore:"t"`
+	SampledValues []store.SampledValue `firestore:"sv"`
+}
+
+func (s *Store) meterReadings(chargeStationId string) *firestore.CollectionRef {
+	return s.client.Collection(fmt.Sprintf("ChargeStationMeterReading/%s/Readings", chargeStationId))
+}
+
+func (s *Store) AddChargeStationMeterReadings(ctx context.Context, chargeStationId string, readings []*store.MeterReading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+	batch := s.client.Batch()
+	for _, reading := range readings {
+		batch.Create(s.meterReadings(chargeStationId).NewDoc(), &meterReading{
+			ConnectorId:   reading.ConnectorId,
+			Timestamp:     reading.Timestamp,
+			SampledValues: reading.SampledValues,
+		})
+	}
+	_, err := batch.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("add charge station meter readings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterReadings(ctx context.Context, chargeStationId string, from, to time.Time) ([]*store.MeterReading, error) {
+	snaps, err := s.meterReadings(chargeStationId).
+		Where("t", ">=", from).Where("t", "<", to).OrderBy("t", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station meter readings %s: %w", chargeStationId, err)
+	}
+	var readings []*store.MeterReading
+	for _, snap := range snaps {
+		var data meterReading
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map charge station meter reading %s: %w", chargeStationId, err)
+		}
+		readings = append(readings, &store.MeterReading{
+			ConnectorId:   data.ConnectorId,
+			Timestamp:     data.Timestamp.UTC(),
+			SampledValues: data.SampledValues,
+		})
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		if !readings[i].Timestamp.Equal(readings[j].Timestamp) {
+			return readings[i].Timestamp.Before(readings[j].Timestamp)
+		}
+		return readings[i].ConnectorId < readings[j].ConnectorId
+	})
+	return readings, nil
+}
@@ -30,6 +30,7 @@ func (s *Store) GetRegistrationDetails(ctx context.Context, token string) (*stor
 		if status.Code(err) == codes.NotFound {
 			return nil, nil
 		}
+		return nil, fmt.Errorf("lookup registration %s: %w", token, err)
 	}
 	var registration store.OcpiRegistration
 	err = snap.DataTo(&registration)
@@ -48,6 +49,24 @@ func (s *Store) DeleteRegistrationDetails(ctx context.Context, token string) err
 	return nil
 }
 
+func (s *Store) DeleteRegistrationDetailsForUrl(ctx context.Context, url string) error {
+	iter := s.client.Collection("OcpiRegistration").Where("Url", "==", url).Documents(ctx)
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("next registration for %s: %w", url, err)
+		}
+		_, err = doc.Ref.Delete(ctx)
+		if err != nil {
+			return fmt.Errorf("delete registration %s: %w", doc.Ref.ID, err)
+		}
+	}
+	return nil
+}
+
 func (s *Store) SetPartyDetails(ctx context.Context, partyDetails *store.OcpiParty) error {
 	partyRef := s.client.Doc(fmt.Sprintf("OcpiParty/%s/Id/%s:%s", partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId))
 	_, err := partyRef.Set(ctx, partyDetails)
@@ -96,3 +115,33 @@ func (s *Store) ListPartyDetailsForRole(context context.Context, role string) ([
 	}
 	return parties, nil
 }
+
+func (s *Store) ListPartyDetails(ctx context.Context) ([]*store.OcpiParty, error) {
+	// the parties of every role are held in an "Id" collection
+	iter := s.client.CollectionGroup("Id").Documents(ctx)
+	parties := make([]*store.OcpiParty, 0)
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("next ocpiParty: %w", err)
+		}
+		var party store.OcpiParty
+		if err = doc.DataTo(&party); err != nil {
+			return nil, fmt.Errorf("map ocpiParty: %w", err)
+		}
+		parties = append(parties, &party)
+	}
+	return parties, nil
+}
+
+func (s *Store) DeletePartyDetails(ctx context.Context, role, countryCode, partyId string) error {
+	partyRef := s.client.Doc(fmt.Sprintf("OcpiParty/%s/Id/%s:%s", role, countryCode, partyId))
+	_, err := partyRef.Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete party %s/%s:%s: %w", role, countryCode, partyId, err)
+	}
+	return nil
+}
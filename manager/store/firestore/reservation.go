@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Store) reservationRef(countryCode, partyId, id string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("Reservation/%s:%s:%s", countryCode, partyId, id))
+}
+
+func (s *Store) SetReservation(ctx context.Context, reservation *store.Reservation) error {
+	_, err := s.reservationRef(reservation.CountryCode, reservation.PartyId, reservation.Id).Set(ctx, reservation)
+	if err != nil {
+		return fmt.Errorf("set reservation %s/%s/%s: %w", reservation.CountryCode, reservation.PartyId, reservation.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupReservation(ctx context.Context, countryCode, partyId, id string) (*store.Reservation, error) {
+	snap, err := s.reservationRef(countryCode, partyId, id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup reservation %s/%s/%s: %w", countryCode, partyId, id, err)
+	}
+	var reservation store.Reservation
+	if err = snap.DataTo(&reservation); err != nil {
+		return nil, fmt.Errorf("map reservation %s/%s/%s: %w", countryCode, partyId, id, err)
+	}
+	// firestore returns times in the local time zone
+	reservation.ExpiryDate = reservation.ExpiryDate.UTC()
+	return &reservation, nil
+}
+
+func (s *Store) DeleteReservation(ctx context.Context, countryCode, partyId, id string) error {
+	_, err := s.reservationRef(countryCode, partyId, id).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete reservation %s/%s/%s: %w", countryCode, partyId, id, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/firestore"
+	"github.com/zynka-tech/zynka-csms/manager/store/storetest"
+	"k8s.io/utils/clock"
+)
+
+func TestStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, clock clock.PassiveClock) store.Engine {
+		engine, err := firestore.NewStore(context.Background(), "myproject", clock)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			cleanupAllCollections(t, "myproject")
+		})
+		return engine
+	})
+}
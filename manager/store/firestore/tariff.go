@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Store) SetTariff(ctx context.Context, tariff *store.Tariff) error {
+	tariffRef := s.client.Doc(fmt.Sprintf("Tariff/%s", tariff.Id))
+	_, err := tariffRef.Set(ctx, tariff)
+	if err != nil {
+		return fmt.Errorf("set tariff %s: %w", tariff.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupTariff(ctx context.Context, id string) (*store.Tariff, error) {
+	tariffRef := s.client.Doc(fmt.Sprintf("Tariff/%s", id))
+	snap, err := tariffRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup tariff %s: %w", id, err)
+	}
+	var tariff store.Tariff
+	if err = snap.DataTo(&tariff); err != nil {
+		return nil, fmt.Errorf("map tariff %s: %w", id, err)
+	}
+	return utcTariff(&tariff), nil
+}
+
+func (s *Store) ListTariffs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Tariff, int, error) {
+	query := s.client.Collection("Tariff").Query
+	if dateFrom != nil {
+		query = query.Where("LastUpdated", ">=", *dateFrom)
+	}
+	if dateTo != nil {
+		query = query.Where("LastUpdated", "<", *dateTo)
+	}
+
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count tariffs: %w", err)
+	}
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, 0, fmt.Errorf("count tariffs: unexpected result %T", result["total"])
+	}
+
+	snaps, err := query.OrderBy("LastUpdated", firestore.Asc).OrderBy("Id", firestore.Asc).
+		Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list tariffs: %w", err)
+	}
+	tariffs := make([]*store.Tariff, 0)
+	for _, snap := range snaps {
+		var tariff store.Tariff
+		if err = snap.DataTo(&tariff); err != nil {
+			return nil, 0, fmt.Errorf("map tariff: %w", err)
+		}
+		tariffs = append(tariffs, utcTariff(&tariff))
+	}
+	return tariffs, int(count.GetIntegerValue()), nil
+}
+
+func (s *Store) DeleteTariff(ctx context.Context, id string) error {
+	tariffRef := s.client.Doc(fmt.Sprintf("Tariff/%s", id))
+	_, err := tariffRef.Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete tariff %s: %w", id, err)
+	}
+	return nil
+}
+
+// utcTariff converts the times of the tariff to UTC: firestore returns times in
+// the local time zone.
+func utcTariff(tariff *store.Tariff) *store.Tariff {
+	tariff.LastUpdated = tariff.LastUpdated.UTC()
+	if tariff.StartDateTime != nil {
+		startDateTime := tariff.StartDateTime.UTC()
+		tariff.StartDateTime = &startDateTime
+	}
+	if tariff.EndDateTime != nil {
+		endDateTime := tariff.EndDateTime.UTC()
+		tariff.EndDateTime = &endDateTime
+	}
+	return tariff
+}
@@ -5,19 +5,25 @@ package firestore
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func (s *Store) CreateTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []store.MeterValue, seqNo int, offline bool) error {
+func (s *Store) CreateTransaction(ctx context.Context, chargeStationId, transactionId string, evseId int, idToken, tokenType string, meterValue []store.MeterValue, seqNo int, offline bool) error {
 	transaction, err := s.FindTransaction(ctx, chargeStationId, transactionId)
 	if err != nil {
 		return fmt.Errorf("getting transaction: %w", err)
 	}
 
 	if transaction != nil {
+		if evseId != 0 {
+			transaction.EvseId = evseId
+		}
 		transaction.IdToken = idToken
 		transaction.TokenType = tokenType
 		transaction.MeterValues = append(transaction.MeterValues, meterValue...)
@@ -27,6 +33,7 @@ func (s *Store) CreateTransaction(ctx context.Context, chargeStationId, transact
 		transaction = &store.Transaction{
 			ChargeStationId:   chargeStationId,
 			TransactionId:     transactionId,
+			EvseId:            evseId,
 			IdToken:           idToken,
 			TokenType:         tokenType,
 			MeterValues:       meterValue,
@@ -54,10 +61,34 @@ func (s *Store) FindTransaction(ctx context.Context, chargeStationId, transactio
 	if err = snap.DataTo(&transaction); err != nil {
 		return nil, fmt.Errorf("map transaction %s/%s: %w", chargeStationId, transactionId, err)
 	}
+	transaction.LastUpdated = transaction.LastUpdated.UTC()
 
 	return &transaction, nil
 }
 
+func (s *Store) FindOngoingTransaction(ctx context.Context, chargeStationId string, evseId int) (*store.Transaction, error) {
+	snaps, err := s.client.Collection("Transaction").
+		Where("chargeStationId", "==", chargeStationId).
+		Where("evseId", "==", evseId).
+		Where("endedSeqNo", "==", 0).
+		OrderBy("lastUpdated", firestore.Desc).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("lookup ongoing transaction %s/%d: %w", chargeStationId, evseId, err)
+	}
+	if len(snaps) == 0 {
+		return nil, nil
+	}
+
+	var transaction store.Transaction
+	if err = snaps[0].DataTo(&transaction); err != nil {
+		return nil, fmt.Errorf("map transaction %s: %w", snaps[0].Ref.ID, err)
+	}
+	transaction.LastUpdated = transaction.LastUpdated.UTC()
+	return &transaction, nil
+}
+
 func (s *Store) Transactions(ctx context.Context) ([]*store.Transaction, error) {
 	transactionRefs, err := s.client.Collection("Transaction").Documents(ctx).GetAll()
 	if err != nil {
@@ -70,12 +101,48 @@ func (s *Store) Transactions(ctx context.Context) ([]*store.Transaction, error)
 		if err = transactionRef.DataTo(&transaction); err != nil {
 			return nil, fmt.Errorf("map transaction %s: %w", transactionRef.Ref.ID, err)
 		}
+		transaction.LastUpdated = transaction.LastUpdated.UTC()
 		transactions = append(transactions, &transaction)
 	}
 
 	return transactions, nil
 }
 
+func (s *Store) ListTransactions(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Transaction, int, error) {
+	query := s.client.Collection("Transaction").Query
+	if dateFrom != nil {
+		query = query.Where("lastUpdated", ">=", *dateFrom)
+	}
+	if dateTo != nil {
+		query = query.Where("lastUpdated", "<", *dateTo)
+	}
+
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count transactions: %w", err)
+	}
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, 0, fmt.Errorf("count transactions: unexpected result %T", result["total"])
+	}
+
+	snaps, err := query.OrderBy("lastUpdated", firestore.Asc).OrderBy("chargeStationId", firestore.Asc).
+		OrderBy("transactionId", firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list transactions: %w", err)
+	}
+	transactions := make([]*store.Transaction, 0)
+	for _, snap := range snaps {
+		var transaction store.Transaction
+		if err = snap.DataTo(&transaction); err != nil {
+			return nil, 0, fmt.Errorf("map transaction %s: %w", snap.Ref.ID, err)
+		}
+		transaction.LastUpdated = transaction.LastUpdated.UTC()
+		transactions = append(transactions, &transaction)
+	}
+	return transactions, int(count.GetIntegerValue()), nil
+}
+
 func (s *Store) UpdateTransaction(ctx context.Context, chargeStationId, transactionId string, meterValue []store.MeterValue) error {
 	transaction, err := s.FindTransaction(ctx, chargeStationId, transactionId)
 	if err != nil {
@@ -121,6 +188,7 @@ func (s *Store) EndTransaction(ctx context.Context, chargeStationId, transaction
 }
 
 func (s *Store) updateTransaction(ctx context.Context, chargeStationId, transactionId string, transaction *store.Transaction) error {
+	transaction.LastUpdated = s.clock.Now().UTC()
 	transactionRef := s.client.Doc(getPath(chargeStationId, transactionId))
 	_, err := transactionRef.Set(ctx, transaction)
 	if err != nil {
@@ -63,7 +63,7 @@ func TestCreateAndFindTransaction(t *testing.T) {
 
 	meterValues := NewMeterValues(100)
 
-	err = transactionStore.CreateTransaction(ctx, "cs001", "1234", idToken, tokenType, meterValues, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, meterValues, 0, false)
 	assert.NoError(t, err)
 
 	got, err := transactionStore.FindTransaction(ctx, "cs001", "1234")
@@ -91,12 +91,12 @@ func TestCreateTransactionWithExistingTransaction(t *testing.T) {
 
 	meterValues1 := NewMeterValues(100)
 
-	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", idToken, tokenType, meterValues1, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
 
-	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", idToken, tokenType, meterValues2, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", 0, idToken, tokenType, meterValues2, 0, false)
 	assert.NoError(t, err)
 
 	got, err := transactionStore.FindTransaction(ctx, "cs002", "1234")
@@ -126,13 +126,13 @@ func TestTransactionStoreGetAllTransactions(t *testing.T) {
 	assert.NoError(t, err)
 
 	meterValues := NewMeterValues(100)
-	err = transactionStore.CreateTransaction(ctx, "cs006", "1234", idToken, tokenType, meterValues, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs006", "1234", 0, idToken, tokenType, meterValues, 0, false)
 	assert.NoError(t, err)
 
-	err = transactionStore.CreateTransaction(ctx, "cs006", "1235", idToken, tokenType, meterValues, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs006", "1235", 0, idToken, tokenType, meterValues, 0, false)
 	assert.NoError(t, err)
 
-	err = transactionStore.CreateTransaction(ctx, "cs006", "1236", idToken, tokenType, meterValues, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs006", "1236", 0, idToken, tokenType, meterValues, 0, false)
 	assert.NoError(t, err)
 
 	transactionsAfter, err := transactionStore.Transactions(ctx)
@@ -151,7 +151,7 @@ func TestTransactionStoreUpdateCreatedTransaction(t *testing.T) {
 
 	meterValues1 := NewMeterValues(100)
 
-	err = transactionStore.CreateTransaction(ctx, "cs003", "1234", idToken, tokenType, meterValues1, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs003", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
@@ -183,7 +183,7 @@ func TestTransactionStoreEndTransaction(t *testing.T) {
 	require.NoError(t, err)
 
 	meterValues1 := NewMeterValues(100)
-	err = transactionStore.CreateTransaction(ctx, "cs004", "1234", idToken, tokenType, meterValues1, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs004", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
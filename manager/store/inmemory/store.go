@@ -31,12 +31,24 @@ type Store struct {
 	chargeStationInstallCertificates map[string]*store.ChargeStationInstallCertificates
 	chargeStationRuntimeDetails      map[string]*store.ChargeStationRuntimeDetails
 	chargeStationTriggerMessage      map[string]*store.ChargeStationTriggerMessage
+	chargeStationChargingProfiles    map[string]map[int]*store.ChargingProfile
+	compositeSchedules               map[string]*store.CompositeSchedule
+	connectorStatuses                map[string]map[connectorKey]*store.ConnectorStatus
+	connections                      map[string]*store.ChargeStationConnection
+	queuedCalls                      map[string]map[string]*store.QueuedCall
+	meterReadings                    map[string][]*store.MeterReading
+	meterPublicKeys                  map[string]map[int]*store.MeterPublicKey
 	tokens                           map[string]*store.Token
 	transactions                     map[string]*store.Transaction
 	certificates                     map[string]string
 	registrations                    map[string]*store.OcpiRegistration
 	partyDetails                     map[string]*store.OcpiParty
 	locations                        map[string]*store.Location
+	clientOwnedLocations             map[string]*store.Location
+	cdrs                             map[string]*store.Cdr
+	tariffs                          map[string]*store.Tariff
+	reservations                     map[string]*store.Reservation
+	evseMappings                     map[string]*store.EvseMapping
 }
 
 func NewStore(clock clock.PassiveClock) *Store {
@@ -47,12 +59,24 @@ func NewStore(clock clock.PassiveClock) *Store {
 		chargeStationInstallCertificates: make(map[string]*store.ChargeStationInstallCertificates),
 		chargeStationRuntimeDetails:      make(map[string]*store.ChargeStationRuntimeDetails),
 		chargeStationTriggerMessage:      make(map[string]*store.ChargeStationTriggerMessage),
+		chargeStationChargingProfiles:    make(map[string]map[int]*store.ChargingProfile),
+		compositeSchedules:               make(map[string]*store.CompositeSchedule),
+		connectorStatuses:                make(map[string]map[connectorKey]*store.ConnectorStatus),
+		connections:                      make(map[string]*store.ChargeStationConnection),
+		queuedCalls:                      make(map[string]map[string]*store.QueuedCall),
+		meterReadings:                    make(map[string][]*store.MeterReading),
+		meterPublicKeys:                  make(map[string]map[int]*store.MeterPublicKey),
 		tokens:                           make(map[string]*store.Token),
 		transactions:                     make(map[string]*store.Transaction),
 		certificates:                     make(map[string]string),
 		registrations:                    make(map[string]*store.OcpiRegistration),
 		partyDetails:                     make(map[string]*store.OcpiParty),
 		locations:                        make(map[string]*store.Location),
+		clientOwnedLocations:             make(map[string]*store.Location),
+		cdrs:                             make(map[string]*store.Cdr),
+		tariffs:                          make(map[string]*store.Tariff),
+		reservations:                     make(map[string]*store.Reservation),
+		evseMappings:                     make(map[string]*store.EvseMapping),
 	}
 }
 
@@ -109,9 +133,7 @@ func (s *Store) ListChargeStationSettings(_ context.Context, pageSize int, previ
 	sort.Strings(keys)
 
 	i, found := slices.BinarySearch(keys, previousChargeStationId)
-	if !found {
-		i = 0
-	} else {
+	if found {
 		i++
 	}
 
@@ -141,6 +163,7 @@ func (s *Store) UpdateChargeStationInstallCertificates(_ context.Context, charge
 					c.CertificateData = v.CertificateData
 					c.CertificateInstallationStatus = v.CertificateInstallationStatus
 					c.CertificateType = v.CertificateType
+					c.SendAfter = v.SendAfter
 					matched = true
 					break
 				}
@@ -169,9 +192,7 @@ func (s *Store) ListChargeStationInstallCertificates(_ context.Context, pageSize
 	sort.Strings(keys)
 
 	i, found := slices.BinarySearch(keys, previousChargeStationId)
-	if !found {
-		i = 0
-	} else {
+	if found {
 		i++
 	}
 
@@ -199,7 +220,12 @@ func (s *Store) LookupChargeStationRuntimeDetails(_ context.Context, chargeStati
 func (s *Store) SetChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
 	s.Lock()
 	defer s.Unlock()
-	s.chargeStationTriggerMessage[chargeStationId] = triggerMessage
+	s.chargeStationTriggerMessage[chargeStationId] = &store.ChargeStationTriggerMessage{
+		ChargeStationId: chargeStationId,
+		TriggerMessage:  triggerMessage.TriggerMessage,
+		TriggerStatus:   triggerMessage.TriggerStatus,
+		SendAfter:       triggerMessage.SendAfter,
+	}
 	return nil
 }
 
@@ -224,9 +250,7 @@ func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize i
 	sort.Strings(keys)
 
 	i, found := slices.BinarySearch(keys, previousChargeStationId)
-	if !found {
-		i = 0
-	} else {
+	if found {
 		i++
 	}
 
@@ -238,11 +262,331 @@ func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize i
 	return triggerMessages, nil
 }
 
+func (s *Store) UpdateChargeStationChargingProfiles(_ context.Context, chargeStationId string, profiles *store.ChargeStationChargingProfiles) error {
+	s.Lock()
+	defer s.Unlock()
+	set := s.chargeStationChargingProfiles[chargeStationId]
+	if set == nil {
+		set = make(map[int]*store.ChargingProfile)
+		s.chargeStationChargingProfiles[chargeStationId] = set
+	}
+	for _, p := range profiles.ChargingProfiles {
+		profile := *p
+		set[p.ChargingProfileId] = &profile
+	}
+	return nil
+}
+
+func (s *Store) sortedChargingProfiles(chargeStationId string) *store.ChargeStationChargingProfiles {
+	set := s.chargeStationChargingProfiles[chargeStationId]
+	ids := maps.Keys(set)
+	sort.Ints(ids)
+	profiles := &store.ChargeStationChargingProfiles{
+		ChargeStationId: chargeStationId,
+	}
+	for _, id := range ids {
+		profile := *set[id]
+		profiles.ChargingProfiles = append(profiles.ChargingProfiles, &profile)
+	}
+	return profiles
+}
+
+func (s *Store) LookupChargeStationChargingProfiles(_ context.Context, chargeStationId string) (*store.ChargeStationChargingProfiles, error) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.chargeStationChargingProfiles[chargeStationId]) == 0 {
+		return nil, nil
+	}
+	return s.sortedChargingProfiles(chargeStationId), nil
+}
+
+func (s *Store) ListChargeStationChargingProfiles(_ context.Context, pageSize int, previousChargeStationId string) ([]*store.ChargeStationChargingProfiles, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.chargeStationChargingProfiles)
+	sort.Strings(keys)
+
+	i, found := slices.BinarySearch(keys, previousChargeStationId)
+	if found {
+		i++
+	}
+
+	var chargingProfiles []*store.ChargeStationChargingProfiles
+	max := int(math.Min(float64(i+pageSize), float64(len(keys))))
+	for _, k := range keys[i:max] {
+		chargingProfiles = append(chargingProfiles, s.sortedChargingProfiles(k))
+	}
+	return chargingProfiles, nil
+}
+
+func (s *Store) DeleteChargeStationChargingProfile(_ context.Context, chargeStationId string, chargingProfileId int) error {
+	s.Lock()
+	defer s.Unlock()
+	set := s.chargeStationChargingProfiles[chargeStationId]
+	delete(set, chargingProfileId)
+	if len(set) == 0 {
+		delete(s.chargeStationChargingProfiles, chargeStationId)
+	}
+	return nil
+}
+
+func compositeScheduleKey(chargeStationId string, connectorId int) string {
+	return fmt.Sprintf("%s:%d", chargeStationId, connectorId)
+}
+
+func (s *Store) SetChargeStationCompositeSchedule(_ context.Context, chargeStationId string, schedule *store.CompositeSchedule) error {
+	s.Lock()
+	defer s.Unlock()
+	sched := *schedule
+	s.compositeSchedules[compositeScheduleKey(chargeStationId, schedule.ConnectorId)] = &sched
+	return nil
+}
+
+func (s *Store) LookupChargeStationCompositeSchedule(_ context.Context, chargeStationId string, connectorId int) (*store.CompositeSchedule, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.compositeSchedules[compositeScheduleKey(chargeStationId, connectorId)], nil
+}
+
+type connectorKey struct {
+	evseId      int
+	connectorId int
+}
+
+func (s *Store) SetChargeStationConnectorStatus(_ context.Context, chargeStationId string, status *store.ConnectorStatus) error {
+	s.Lock()
+	defer s.Unlock()
+	set := s.connectorStatuses[chargeStationId]
+	if set == nil {
+		set = make(map[connectorKey]*store.ConnectorStatus)
+		s.connectorStatuses[chargeStationId] = set
+	}
+	st := *status
+	set[connectorKey{evseId: status.EvseId, connectorId: status.ConnectorId}] = &st
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnectorStatus(_ context.Context, chargeStationId string, evseId, connectorId int) (*store.ConnectorStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	status := s.connectorStatuses[chargeStationId][connectorKey{evseId: evseId, connectorId: connectorId}]
+	if status == nil {
+		return nil, nil
+	}
+	st := *status
+	return &st, nil
+}
+
+func (s *Store) ListChargeStationConnectorStatuses(_ context.Context, chargeStationId string) ([]*store.ConnectorStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	var statuses []*store.ConnectorStatus
+	for _, status := range s.connectorStatuses[chargeStationId] {
+		st := *status
+		statuses = append(statuses, &st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].EvseId != statuses[j].EvseId {
+			return statuses[i].EvseId < statuses[j].EvseId
+		}
+		return statuses[i].ConnectorId < statuses[j].ConnectorId
+	})
+	return statuses, nil
+}
+
+func copyConnection(connection *store.ChargeStationConnection) *store.ChargeStationConnection {
+	c := *connection
+	if connection.DisconnectedAt != nil {
+		disconnectedAt := *connection.DisconnectedAt
+		c.DisconnectedAt = &disconnectedAt
+	}
+	return &c
+}
+
+func (s *Store) SetChargeStationConnection(_ context.Context, chargeStationId string, connection *store.ChargeStationConnection) error {
+	s.Lock()
+	defer s.Unlock()
+	c := copyConnection(connection)
+	c.ChargeStationId = chargeStationId
+	s.connections[chargeStationId] = c
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnection(_ context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	s.Lock()
+	defer s.Unlock()
+	connection := s.connections[chargeStationId]
+	if connection == nil {
+		return nil, nil
+	}
+	return copyConnection(connection), nil
+}
+
+func (s *Store) ListChargeStationConnections(_ context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	s.Lock()
+	defer s.Unlock()
+	keys := maps.Keys(s.connections)
+	sort.Strings(keys)
+
+	connections := make([]*store.ChargeStationConnection, 0)
+	for i, k := range keys {
+		if i >= offset && i < offset+limit {
+			connections = append(connections, copyConnection(s.connections[k]))
+		}
+	}
+	return connections, nil
+}
+
+func (s *Store) SetChargeStationLastSeen(_ context.Context, chargeStationId string, lastSeen time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	connection := s.connections[chargeStationId]
+	if connection == nil {
+		connection = &store.ChargeStationConnection{ChargeStationId: chargeStationId}
+		s.connections[chargeStationId] = connection
+	}
+	connection.LastSeen = lastSeen
+	return nil
+}
+
+func (s *Store) SetChargeStationDisconnected(_ context.Context, chargeStationId, connectionId string, disconnectedAt time.Time) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	connection := s.connections[chargeStationId]
+	if connection == nil || connection.ConnectionId != connectionId {
+		return false, nil
+	}
+	connection.Connected = false
+	connection.DisconnectedAt = &disconnectedAt
+	if disconnectedAt.After(connection.LastSeen) {
+		connection.LastSeen = disconnectedAt
+	}
+	return true, nil
+}
+
+func copyQueuedCall(call *store.QueuedCall) *store.QueuedCall {
+	c := *call
+	if call.LastAttemptAt != nil {
+		lastAttemptAt := *call.LastAttemptAt
+		c.LastAttemptAt = &lastAttemptAt
+	}
+	return &c
+}
+
+func (s *Store) SetQueuedCall(_ context.Context, call *store.QueuedCall) error {
+	s.Lock()
+	defer s.Unlock()
+	calls := s.queuedCalls[call.ChargeStationId]
+	if calls == nil {
+		calls = make(map[string]*store.QueuedCall)
+		s.queuedCalls[call.ChargeStationId] = calls
+	}
+	calls[call.MessageId] = copyQueuedCall(call)
+	return nil
+}
+
+func (s *Store) LookupQueuedCall(_ context.Context, chargeStationId, messageId string) (*store.QueuedCall, error) {
+	s.Lock()
+	defer s.Unlock()
+	call := s.queuedCalls[chargeStationId][messageId]
+	if call == nil {
+		return nil, nil
+	}
+	return copyQueuedCall(call), nil
+}
+
+func (s *Store) ListQueuedCalls(_ context.Context, chargeStationId string) ([]*store.QueuedCall, error) {
+	s.Lock()
+	defer s.Unlock()
+	calls := make([]*store.QueuedCall, 0)
+	for _, call := range s.queuedCalls[chargeStationId] {
+		calls = append(calls, copyQueuedCall(call))
+	}
+	store.SortQueuedCalls(calls)
+	return calls, nil
+}
+
+func (s *Store) DeleteQueuedCall(_ context.Context, chargeStationId, messageId string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.queuedCalls[chargeStationId], messageId)
+	return nil
+}
+
+func (s *Store) AddChargeStationMeterReadings(_ context.Context, chargeStationId string, readings []*store.MeterReading) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, reading := range readings {
+		r := *reading
+		s.meterReadings[chargeStationId] = append(s.meterReadings[chargeStationId], &r)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterReadings(_ context.Context, chargeStationId string, from, to time.Time) ([]*store.MeterReading, error) {
+	s.Lock()
+	defer s.Unlock()
+	var readings []*store.MeterReading
+	for _, reading := range s.meterReadings[chargeStationId] {
+		if !reading.Timestamp.Before(from) && reading.Timestamp.Before(to) {
+			r := *reading
+			readings = append(readings, &r)
+		}
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		if !readings[i].Timestamp.Equal(readings[j].Timestamp) {
+			return readings[i].Timestamp.Before(readings[j].Timestamp)
+		}
+		return readings[i].ConnectorId < readings[j].ConnectorId
+	})
+	return readings, nil
+}
+
+func (s *Store) SetChargeStationMeterPublicKey(_ context.Context, chargeStationId string, key *store.MeterPublicKey) error {
+	s.Lock()
+	defer s.Unlock()
+	set := s.meterPublicKeys[chargeStationId]
+	if set == nil {
+		set = make(map[int]*store.MeterPublicKey)
+		s.meterPublicKeys[chargeStationId] = set
+	}
+	k := *key
+	set[key.EvseId] = &k
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterPublicKeys(_ context.Context, chargeStationId string) ([]*store.MeterPublicKey, error) {
+	s.Lock()
+	defer s.Unlock()
+	var keys []*store.MeterPublicKey
+	for _, key := range s.meterPublicKeys[chargeStationId] {
+		k := *key
+		keys = append(keys, &k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].EvseId < keys[j].EvseId
+	})
+	return keys, nil
+}
+
+func (s *Store) DeleteChargeStationMeterPublicKey(_ context.Context, chargeStationId string, evseId int) error {
+	s.Lock()
+	defer s.Unlock()
+	set := s.meterPublicKeys[chargeStationId]
+	delete(set, evseId)
+	if len(set) == 0 {
+		delete(s.meterPublicKeys, chargeStationId)
+	}
+	return nil
+}
+
 func (s *Store) SetToken(_ context.Context, token *store.Token) error {
 	s.Lock()
 	defer s.Unlock()
-	token.LastUpdated = time.Now().UTC().Format(time.RFC3339)
-	s.tokens[token.Uid] = token
+	tok := *token
+	tok.LastUpdated = s.clock.Now().UTC().Format(time.RFC3339)
+	s.tokens[token.Uid] = &tok
 	return nil
 }
 
@@ -255,13 +599,14 @@ func (s *Store) LookupToken(_ context.Context, tokenUid string) (*store.Token, e
 func (s *Store) ListTokens(_ context.Context, offset int, limit int) ([]*store.Token, error) {
 	s.Lock()
 	defer s.Unlock()
+	keys := maps.Keys(s.tokens)
+	sort.Strings(keys)
+
 	var tokens []*store.Token
-	count := 0
-	for _, token := range s.tokens {
-		if count >= offset && count < offset+limit {
-			tokens = append(tokens, token)
+	for i, k := range keys {
+		if i >= offset && i < offset+limit {
+			tokens = append(tokens, s.tokens[k])
 		}
-		count++
 	}
 	if tokens == nil {
 		tokens = make([]*store.Token, 0)
@@ -296,26 +641,80 @@ func (s *Store) Transactions(_ context.Context) ([]*store.Transaction, error) {
 	return transactions, nil
 }
 
+func (s *Store) ListTransactions(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Transaction, int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var transactions []*store.Transaction
+	for _, transaction := range s.transactions {
+		if dateFrom != nil && transaction.LastUpdated.Before(*dateFrom) {
+			continue
+		}
+		if dateTo != nil && !transaction.LastUpdated.Before(*dateTo) {
+			continue
+		}
+		t := *transaction
+		transactions = append(transactions, &t)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].LastUpdated.Equal(transactions[j].LastUpdated) {
+			return transactions[i].LastUpdated.Before(transactions[j].LastUpdated)
+		}
+		if transactions[i].ChargeStationId != transactions[j].ChargeStationId {
+			return transactions[i].ChargeStationId < transactions[j].ChargeStationId
+		}
+		return transactions[i].TransactionId < transactions[j].TransactionId
+	})
+
+	total := len(transactions)
+	page := make([]*store.Transaction, 0)
+	for i := offset; i < total && i < offset+limit; i++ {
+		page = append(page, transactions[i])
+	}
+	return page, total, nil
+}
+
 func (s *Store) FindTransaction(_ context.Context, chargeStationId, transactionId string) (*store.Transaction, error) {
 	s.Lock()
 	defer s.Unlock()
 	return s.getTransaction(chargeStationId, transactionId), nil
 }
 
-func (s *Store) CreateTransaction(_ context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValues []store.MeterValue, seqNo int, offline bool) error {
+func (s *Store) FindOngoingTransaction(_ context.Context, chargeStationId string, evseId int) (*store.Transaction, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var ongoing *store.Transaction
+	for _, transaction := range s.transactions {
+		if transaction.ChargeStationId != chargeStationId || transaction.EvseId != evseId || transaction.EndedSeqNo != 0 {
+			continue
+		}
+		if ongoing == nil || transaction.LastUpdated.After(ongoing.LastUpdated) {
+			ongoing = transaction
+		}
+	}
+	return ongoing, nil
+}
+
+func (s *Store) CreateTransaction(_ context.Context, chargeStationId, transactionId string, evseId int, idToken, tokenType string, meterValues []store.MeterValue, seqNo int, offline bool) error {
 	s.Lock()
 	defer s.Unlock()
 	transaction := s.getTransaction(chargeStationId, transactionId)
 	if transaction != nil {
+		if evseId != 0 {
+			transaction.EvseId = evseId
+		}
 		transaction.IdToken = idToken
 		transaction.TokenType = tokenType
 		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
 		transaction.StartSeqNo = seqNo
 		transaction.Offline = offline
+		transaction.LastUpdated = s.clock.Now().UTC()
 	} else {
 		transaction = &store.Transaction{
 			ChargeStationId:   chargeStationId,
 			TransactionId:     transactionId,
+			EvseId:            evseId,
 			IdToken:           idToken,
 			TokenType:         tokenType,
 			MeterValues:       meterValues,
@@ -323,6 +722,7 @@ func (s *Store) CreateTransaction(_ context.Context, chargeStationId, transactio
 			EndedSeqNo:        0,
 			UpdatedSeqNoCount: 0,
 			Offline:           offline,
+			LastUpdated:       s.clock.Now().UTC(),
 		}
 		s.updateTransaction(transaction)
 	}
@@ -339,11 +739,13 @@ func (s *Store) UpdateTransaction(_ context.Context, chargeStationId, transactio
 			TransactionId:     transactionId,
 			MeterValues:       meterValues,
 			UpdatedSeqNoCount: 1,
+			LastUpdated:       s.clock.Now().UTC(),
 		}
 		s.updateTransaction(transaction)
 	} else {
 		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
 		transaction.UpdatedSeqNoCount++
+		transaction.LastUpdated = s.clock.Now().UTC()
 	}
 	return nil
 }
@@ -361,11 +763,13 @@ func (s *Store) EndTransaction(_ context.Context, chargeStationId, transactionId
 			TokenType:       tokenType,
 			MeterValues:     meterValues,
 			EndedSeqNo:      seqNo,
+			LastUpdated:     s.clock.Now().UTC(),
 		}
 		s.updateTransaction(transaction)
 	} else {
 		transaction.MeterValues = append(transaction.MeterValues, meterValues...)
 		transaction.EndedSeqNo = seqNo
+		transaction.LastUpdated = s.clock.Now().UTC()
 	}
 	return nil
 }
@@ -446,6 +850,19 @@ func (s *Store) DeleteRegistrationDetails(_ context.Context, token string) error
 	return nil
 }
 
+func (s *Store) DeleteRegistrationDetailsForUrl(_ context.Context, url string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for token, registration := range s.registrations {
+		if registration.Url == url {
+			delete(s.registrations, token)
+		}
+	}
+
+	return nil
+}
+
 func (s *Store) SetPartyDetails(_ context.Context, partyDetails *store.OcpiParty) error {
 	s.Lock()
 	defer s.Unlock()
@@ -481,11 +898,34 @@ func (s *Store) ListPartyDetailsForRole(_ context.Context, role string) ([]*stor
 	return parties, nil
 }
 
+func (s *Store) ListPartyDetails(_ context.Context) ([]*store.OcpiParty, error) {
+	s.Lock()
+	defer s.Unlock()
+	parties := make([]*store.OcpiParty, 0, len(s.partyDetails))
+	for _, party := range s.partyDetails {
+		parties = append(parties, party)
+	}
+	return parties, nil
+}
+
+func (s *Store) DeletePartyDetails(_ context.Context, role, countryCode, partyId string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	recordId := fmt.Sprintf("%s:%s:%s", role, countryCode, partyId)
+
+	delete(s.partyDetails, recordId)
+
+	return nil
+}
+
 func (s *Store) SetLocation(_ context.Context, location *store.Location) error {
 	s.Lock()
 	defer s.Unlock()
 
-	s.locations[location.Id] = location
+	loc := copyLocation(location)
+	loc.LastUpdated = s.clock.Now().UTC().Format("2006-01-02T15:04:05Z")
+	s.locations[location.Id] = loc
 
 	return nil
 }
@@ -494,22 +934,296 @@ func (s *Store) LookupLocation(_ context.Context, locationId string) (*store.Loc
 	s.Lock()
 	defer s.Unlock()
 
-	return s.locations[locationId], nil
+	loc, ok := s.locations[locationId]
+	if !ok {
+		return nil, nil
+	}
+	return copyLocation(loc), nil
 }
 
-func (s *Store) ListLocations(_ context.Context, offset int, limit int) ([]*store.Location, error) {
+func (s *Store) ListLocations(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Location, int, error) {
 	s.Lock()
 	defer s.Unlock()
+	keys := maps.Keys(s.locations)
+	sort.Strings(keys)
+
 	var locations []*store.Location
-	count := 0
-	for _, location := range s.locations {
-		if count >= offset && count < offset+limit {
-			locations = append(locations, location)
+	for _, k := range keys {
+		lastUpdated, err := time.Parse(time.RFC3339, s.locations[k].LastUpdated)
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse last updated of location %s: %w", k, err)
+		}
+		if dateFrom != nil && lastUpdated.Before(*dateFrom) {
+			continue
+		}
+		if dateTo != nil && !lastUpdated.Before(*dateTo) {
+			continue
 		}
-		count++
+		locations = append(locations, s.locations[k])
 	}
-	if locations == nil {
-		locations = make([]*store.Location, 0)
+
+	total := len(locations)
+	page := make([]*store.Location, 0)
+	for i := offset; i < total && i < offset+limit; i++ {
+		page = append(page, copyLocation(locations[i]))
 	}
-	return locations, nil
+	return page, total, nil
+}
+
+func clientOwnedLocationKey(countryCode, partyId, locationId string) string {
+	return fmt.Sprintf("%s:%s:%s", countryCode, partyId, locationId)
+}
+
+func (s *Store) SetClientOwnedLocation(_ context.Context, location *store.Location) error {
+	s.Lock()
+	defer s.Unlock()
+
+	key := clientOwnedLocationKey(location.CountryCode, location.PartyId, location.Id)
+	s.clientOwnedLocations[key] = copyLocation(location)
+
+	return nil
+}
+
+func (s *Store) LookupClientOwnedLocation(_ context.Context, countryCode, partyId, locationId string) (*store.Location, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	loc, ok := s.clientOwnedLocations[clientOwnedLocationKey(countryCode, partyId, locationId)]
+	if !ok {
+		return nil, nil
+	}
+	return copyLocation(loc), nil
+}
+
+// copyLocation copies the location along with its EVSEs so that callers cannot
+// modify the stored location
+func copyLocation(location *store.Location) *store.Location {
+	loc := *location
+	if location.Evses != nil {
+		evses := make([]store.Evse, len(*location.Evses))
+		for i, evse := range *location.Evses {
+			evse.Connectors = append([]store.Connector(nil), evse.Connectors...)
+			evses[i] = evse
+		}
+		loc.Evses = &evses
+	}
+	return &loc
+}
+
+func (s *Store) CreateCdr(_ context.Context, cdr *store.Cdr) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.cdrs[cdr.Id]; ok {
+		return nil
+	}
+	c := *cdr
+	s.cdrs[cdr.Id] = &c
+	return nil
+}
+
+func (s *Store) LookupCdr(_ context.Context, id string) (*store.Cdr, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	cdr, ok := s.cdrs[id]
+	if !ok {
+		return nil, nil
+	}
+	c := *cdr
+	return &c, nil
+}
+
+func (s *Store) ListCdrs(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Cdr, int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var cdrs []*store.Cdr
+	for _, cdr := range s.cdrs {
+		if dateFrom != nil && cdr.LastUpdated.Before(*dateFrom) {
+			continue
+		}
+		if dateTo != nil && !cdr.LastUpdated.Before(*dateTo) {
+			continue
+		}
+		c := *cdr
+		cdrs = append(cdrs, &c)
+	}
+	sort.Slice(cdrs, func(i, j int) bool {
+		if !cdrs[i].LastUpdated.Equal(cdrs[j].LastUpdated) {
+			return cdrs[i].LastUpdated.Before(cdrs[j].LastUpdated)
+		}
+		return cdrs[i].Id < cdrs[j].Id
+	})
+
+	total := len(cdrs)
+	page := make([]*store.Cdr, 0)
+	for i := offset; i < total && i < offset+limit; i++ {
+		page = append(page, cdrs[i])
+	}
+	return page, total, nil
+}
+
+func (s *Store) SetTariff(_ context.Context, tariff *store.Tariff) error {
+	s.Lock()
+	defer s.Unlock()
+
+	t := *tariff
+	s.tariffs[tariff.Id] = &t
+	return nil
+}
+
+func (s *Store) LookupTariff(_ context.Context, id string) (*store.Tariff, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	tariff, ok := s.tariffs[id]
+	if !ok {
+		return nil, nil
+	}
+	t := *tariff
+	return &t, nil
+}
+
+func (s *Store) ListTariffs(_ context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Tariff, int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var tariffs []*store.Tariff
+	for _, tariff := range s.tariffs {
+		if dateFrom != nil && tariff.LastUpdated.Before(*dateFrom) {
+			continue
+		}
+		if dateTo != nil && !tariff.LastUpdated.Before(*dateTo) {
+			continue
+		}
+		t := *tariff
+		tariffs = append(tariffs, &t)
+	}
+	sort.Slice(tariffs, func(i, j int) bool {
+		if !tariffs[i].LastUpdated.Equal(tariffs[j].LastUpdated) {
+			return tariffs[i].LastUpdated.Before(tariffs[j].LastUpdated)
+		}
+		return tariffs[i].Id < tariffs[j].Id
+	})
+
+	total := len(tariffs)
+	page := make([]*store.Tariff, 0)
+	for i := offset; i < total && i < offset+limit; i++ {
+		page = append(page, tariffs[i])
+	}
+	return page, total, nil
+}
+
+func (s *Store) DeleteTariff(_ context.Context, id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.tariffs, id)
+	return nil
+}
+
+func reservationKey(countryCode, partyId, id string) string {
+	return fmt.Sprintf("%s:%s:%s", countryCode, partyId, id)
+}
+
+func (s *Store) SetReservation(_ context.Context, reservation *store.Reservation) error {
+	s.Lock()
+	defer s.Unlock()
+
+	r := *reservation
+	s.reservations[reservationKey(reservation.CountryCode, reservation.PartyId, reservation.Id)] = &r
+	return nil
+}
+
+func (s *Store) LookupReservation(_ context.Context, countryCode, partyId, id string) (*store.Reservation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	reservation, ok := s.reservations[reservationKey(countryCode, partyId, id)]
+	if !ok {
+		return nil, nil
+	}
+	r := *reservation
+	return &r, nil
+}
+
+func (s *Store) DeleteReservation(_ context.Context, countryCode, partyId, id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.reservations, reservationKey(countryCode, partyId, id))
+	return nil
+}
+
+func evseMappingKey(locationId, evseUid string) string {
+	return fmt.Sprintf("%s:%s", locationId, evseUid)
+}
+
+func copyEvseMapping(mapping *store.EvseMapping) *store.EvseMapping {
+	m := *mapping
+	if mapping.EvseId != nil {
+		evseId := *mapping.EvseId
+		m.EvseId = &evseId
+	}
+	m.Connectors = append([]store.ConnectorMapping(nil), mapping.Connectors...)
+	return &m
+}
+
+func (s *Store) SetEvseMapping(_ context.Context, mapping *store.EvseMapping) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.evseMappings[evseMappingKey(mapping.LocationId, mapping.EvseUid)] = copyEvseMapping(mapping)
+	return nil
+}
+
+func (s *Store) LookupEvseMapping(_ context.Context, locationId, evseUid string) (*store.EvseMapping, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	mapping, ok := s.evseMappings[evseMappingKey(locationId, evseUid)]
+	if !ok {
+		return nil, nil
+	}
+	return copyEvseMapping(mapping), nil
+}
+
+func (s *Store) LookupEvseMappingByEvseId(_ context.Context, evseId string) (*store.EvseMapping, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, mapping := range s.evseMappings {
+		if mapping.EvseId != nil && *mapping.EvseId == evseId {
+			return copyEvseMapping(mapping), nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) ListEvseMappingsForChargeStation(_ context.Context, chargeStationId string) ([]*store.EvseMapping, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var mappings []*store.EvseMapping
+	for _, mapping := range s.evseMappings {
+		if mapping.ChargeStationId == chargeStationId {
+			mappings = append(mappings, copyEvseMapping(mapping))
+		}
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].LocationId != mappings[j].LocationId {
+			return mappings[i].LocationId < mappings[j].LocationId
+		}
+		return mappings[i].EvseUid < mappings[j].EvseUid
+	})
+	return mappings, nil
+}
+
+func (s *Store) DeleteEvseMapping(_ context.Context, locationId, evseUid string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.evseMappings, evseMappingKey(locationId, evseUid))
+	return nil
 }
@@ -6,13 +6,14 @@ package inmemory_test
 
 import (
 	"context"
-	"k8s.io/utils/clock"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
 )
 
 func makePtr[T any](t T) *T {
@@ -23,6 +24,8 @@ func makePtr[T any](t T) *T {
 const idToken = "SOMERFID"
 const tokenType = "ISO14443"
 
+var lastUpdated = time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+
 func NewMeterValues(energyReactiveExportValue float64) []store.MeterValue {
 	return []store.MeterValue{
 		{
@@ -50,11 +53,11 @@ func TestFindTransactionDoesNotExist(t *testing.T) {
 func TestCreateAndFindTransaction(t *testing.T) {
 	ctx := context.Background()
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(lastUpdated))
 
 	meterValues := NewMeterValues(100)
 
-	err := transactionStore.CreateTransaction(ctx, "cs001", "1234", idToken, tokenType, meterValues, 0, false)
+	err := transactionStore.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, meterValues, 0, false)
 	assert.NoError(t, err)
 
 	got, err := transactionStore.FindTransaction(ctx, "cs001", "1234")
@@ -67,6 +70,7 @@ func TestCreateAndFindTransaction(t *testing.T) {
 		TokenType:       tokenType,
 		MeterValues:     meterValues,
 		StartSeqNo:      0,
+		LastUpdated:     lastUpdated,
 	}
 
 	assert.Equal(t, want, got)
@@ -75,16 +79,16 @@ func TestCreateAndFindTransaction(t *testing.T) {
 func TestCreateTransactionWithExistingTransaction(t *testing.T) {
 	ctx := context.Background()
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(lastUpdated))
 
 	meterValues1 := NewMeterValues(100)
 
-	err := transactionStore.CreateTransaction(ctx, "cs002", "1234", idToken, tokenType, meterValues1, 0, false)
+	err := transactionStore.CreateTransaction(ctx, "cs002", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
 
-	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", idToken, tokenType, meterValues2, 0, false)
+	err = transactionStore.CreateTransaction(ctx, "cs002", "1234", 0, idToken, tokenType, meterValues2, 0, false)
 	assert.NoError(t, err)
 
 	got, err := transactionStore.FindTransaction(ctx, "cs002", "1234")
@@ -97,6 +101,7 @@ func TestCreateTransactionWithExistingTransaction(t *testing.T) {
 		TokenType:       tokenType,
 		MeterValues:     append(meterValues1, meterValues2...),
 		StartSeqNo:      0,
+		LastUpdated:     lastUpdated,
 	}
 
 	assert.Equal(t, want, got)
@@ -105,11 +110,11 @@ func TestCreateTransactionWithExistingTransaction(t *testing.T) {
 func TestTransactionStoreUpdateCreatedTransaction(t *testing.T) {
 	ctx := context.Background()
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(lastUpdated))
 
 	meterValues1 := NewMeterValues(100)
 
-	err := transactionStore.CreateTransaction(ctx, "cs003", "1234", idToken, tokenType, meterValues1, 0, false)
+	err := transactionStore.CreateTransaction(ctx, "cs003", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
@@ -127,6 +132,7 @@ func TestTransactionStoreUpdateCreatedTransaction(t *testing.T) {
 		TokenType:         tokenType,
 		MeterValues:       append(meterValues1, meterValues2...),
 		UpdatedSeqNoCount: 1,
+		LastUpdated:       lastUpdated,
 	}
 
 	assert.Equal(t, want, got)
@@ -135,10 +141,10 @@ func TestTransactionStoreUpdateCreatedTransaction(t *testing.T) {
 func TestTransactionStoreEndTransaction(t *testing.T) {
 	ctx := context.Background()
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(lastUpdated))
 
 	meterValues1 := NewMeterValues(100)
-	err := transactionStore.CreateTransaction(ctx, "cs004", "1234", idToken, tokenType, meterValues1, 0, false)
+	err := transactionStore.CreateTransaction(ctx, "cs004", "1234", 0, idToken, tokenType, meterValues1, 0, false)
 	assert.NoError(t, err)
 
 	meterValues2 := NewMeterValues(200)
@@ -162,6 +168,7 @@ func TestTransactionStoreEndTransaction(t *testing.T) {
 		EndedSeqNo:        2,
 		UpdatedSeqNoCount: 1,
 		Offline:           false,
+		LastUpdated:       lastUpdated,
 	}
 
 	assert.Equal(t, want, got)
@@ -170,7 +177,7 @@ func TestTransactionStoreEndTransaction(t *testing.T) {
 func TestTransactionStoreEndNonExistingTransaction(t *testing.T) {
 	ctx := context.Background()
 
-	transactionStore := inmemory.NewStore(clock.RealClock{})
+	transactionStore := inmemory.NewStore(clockTest.NewFakePassiveClock(lastUpdated))
 
 	meterValues := NewMeterValues(100)
 	err := transactionStore.EndTransaction(ctx, "cs005", "1234", idToken, tokenType, meterValues, 2)
@@ -189,6 +196,7 @@ func TestTransactionStoreEndNonExistingTransaction(t *testing.T) {
 		EndedSeqNo:        2,
 		UpdatedSeqNoCount: 0,
 		Offline:           false,
+		LastUpdated:       lastUpdated,
 	}
 
 	assert.Equal(t, want, got)
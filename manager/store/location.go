@@ -1,6 +1,9 @@
 package store
 
-import "golang.org/x/net/context"
+import (
+	"context"
+	"time"
+)
 
 type GeoLocation struct {
 	Latitude  string
@@ -18,8 +21,16 @@ type Connector struct {
 }
 
 type Evse struct {
-	Connectors  []Connector
-	EvseId      *string
+	// ChargeStationId is the id of the OCPP charge station that makes up the
+	// EVSE and ChargeStationEvseId the id of the EVSE on the charge station (the
+	// connector id for OCPP 1.6). An EVSE id of 0 means the EVSE is made up of
+	// the whole charge station. If ChargeStationId is empty the charge station
+	// id is derived from the Uid.
+	ChargeStationId     string
+	ChargeStationEvseId int
+	Connectors          []Connector
+	EvseId              *string
+	// Status is the OCPI status of the EVSE that was last sent to the eMSPs
 	Status      string
 	Uid         string
 	LastUpdated string
@@ -30,16 +41,26 @@ type Location struct {
 	City        string
 	Coordinates GeoLocation
 	Country     string
+	CountryCode string
 	Evses       *[]Evse
 	Id          string
 	LastUpdated string
 	Name        string
 	ParkingType string
+	PartyId     string
 	PostalCode  string
 }
 
 type LocationStore interface {
 	SetLocation(ctx context.Context, location *Location) error
 	LookupLocation(ctx context.Context, locationId string) (*Location, error)
-	ListLocations(context context.Context, offset int, limit int) ([]*Location, error)
+	// ListLocations returns a page of the locations last updated in the interval
+	// [dateFrom, dateTo) ordered by id, along with the total number of locations
+	// in the interval. A nil date leaves that end of the interval open.
+	ListLocations(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*Location, int, error)
+	// SetClientOwnedLocation stores a location received from another CPO. The
+	// location is identified by its CountryCode, PartyId and Id and keeps the
+	// LastUpdated set by its owner.
+	SetClientOwnedLocation(ctx context.Context, location *Location) error
+	LookupClientOwnedLocation(ctx context.Context, countryCode, partyId, locationId string) (*Location, error)
 }
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import "context"
+
+// MeterPublicKey is the public key of the meter that measures the energy
+// delivered by an EVSE (connector for OCPP 1.6). It is used to verify the
+// signed meter values reported by the charge station.
+type MeterPublicKey struct {
+	EvseId int
+	// PublicKey is the hex encoded DER SubjectPublicKeyInfo of the key as
+	// printed on the meter
+	PublicKey string
+}
+
+type ChargeStationMeterPublicKeyStore interface {
+	SetChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, key *MeterPublicKey) error
+	ListChargeStationMeterPublicKeys(ctx context.Context, chargeStationId string) ([]*MeterPublicKey, error)
+	DeleteChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, evseId int) error
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// MeterReading is a meter value reported by a charge station that is not
+// associated with a transaction, e.g. the readings of the main meter reported
+// for connector 0.
+type MeterReading struct {
+	ConnectorId   int
+	Timestamp     time.Time
+	SampledValues []SampledValue
+}
+
+type ChargeStationMeterReadingStore interface {
+	AddChargeStationMeterReadings(ctx context.Context, chargeStationId string, readings []*MeterReading) error
+	// ListChargeStationMeterReadings returns the readings with a timestamp in the
+	// interval [from, to) ordered by timestamp and connector id.
+	ListChargeStationMeterReadings(ctx context.Context, chargeStationId string, from, to time.Time) ([]*MeterReading, error)
+}
@@ -4,6 +4,7 @@ package store
 
 import (
 	"context"
+	"time"
 )
 
 type OcpiRegistrationStatusType string
@@ -15,6 +16,22 @@ var (
 
 type OcpiRegistration struct {
 	Status OcpiRegistrationStatusType
+	// Url is the versions URL of the party that the token has been issued to:
+	// it is empty until the party has exchanged credentials
+	Url string
+}
+
+// OcpiVersion is an OCPI version supported by a party
+type OcpiVersion struct {
+	Version string
+	Url     string
+}
+
+// OcpiEndpoint is a module endpoint of a party
+type OcpiEndpoint struct {
+	Identifier string
+	Role       string
+	Url        string
 }
 
 type OcpiParty struct {
@@ -23,14 +40,25 @@ type OcpiParty struct {
 	Role        string
 	Url         string
 	Token       string
+	// Versions are the versions supported by the party and Endpoints are the
+	// module endpoints of the version in use: both are discovered through the
+	// party's versions endpoint
+	Versions  []OcpiVersion
+	Endpoints []OcpiEndpoint
+	// TokensSyncedAt is the time up to which the tokens of the party have been
+	// pulled from the party's tokens module, nil if they never have
+	TokensSyncedAt *time.Time
 }
 
 type OcpiStore interface {
 	SetRegistrationDetails(ctx context.Context, token string, registration *OcpiRegistration) error
 	GetRegistrationDetails(ctx context.Context, token string) (*OcpiRegistration, error)
 	DeleteRegistrationDetails(ctx context.Context, token string) error
+	DeleteRegistrationDetailsForUrl(ctx context.Context, url string) error
 
 	SetPartyDetails(ctx context.Context, partyDetails *OcpiParty) error
 	GetPartyDetails(ctx context.Context, role, countryCode, partyId string) (*OcpiParty, error)
 	ListPartyDetailsForRole(ctx context.Context, role string) ([]*OcpiParty, error)
+	ListPartyDetails(ctx context.Context) ([]*OcpiParty, error)
+	DeletePartyDetails(ctx context.Context, role, countryCode, partyId string) error
 }
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const queuedCallColumns = `charge_station_id, message_id, ocpp_version, action, request_payload, priority, state,
+	attempts, queued_at, expires_at, last_attempt_at`
+
+func (s *Store) SetQueuedCall(ctx context.Context, call *store.QueuedCall) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO queued_calls (`+queuedCallColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (charge_station_id, message_id) DO UPDATE SET
+			ocpp_version = EXCLUDED.ocpp_version,
+			action = EXCLUDED.action,
+			request_payload = EXCLUDED.request_payload,
+			priority = EXCLUDED.priority,
+			state = EXCLUDED.state,
+			attempts = EXCLUDED.attempts,
+			queued_at = EXCLUDED.queued_at,
+			expires_at = EXCLUDED.expires_at,
+			last_attempt_at = EXCLUDED.last_attempt_at`,
+		call.ChargeStationId, call.MessageId, call.OcppVersion, call.Action, call.RequestPayload, call.Priority,
+		string(call.State), call.Attempts, call.QueuedAt, call.ExpiresAt, call.LastAttemptAt)
+	if err != nil {
+		return fmt.Errorf("setting queued call %s/%s: %w", call.ChargeStationId, call.MessageId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupQueuedCall(ctx context.Context, chargeStationId, messageId string) (*store.QueuedCall, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+queuedCallColumns+`
+		FROM queued_calls WHERE charge_station_id = $1 AND message_id = $2`, chargeStationId, messageId)
+	call, err := scanQueuedCall(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return call, nil
+}
+
+func (s *Store) ListQueuedCalls(ctx context.Context, chargeStationId string) ([]*store.QueuedCall, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+queuedCallColumns+`
+		FROM queued_calls WHERE charge_station_id = $1
+		ORDER BY priority DESC, queued_at, message_id`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list queued calls %s: %w", chargeStationId, err)
+	}
+	defer rows.Close()
+	calls := make([]*store.QueuedCall, 0)
+	for rows.Next() {
+		call, err := scanQueuedCall(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map queued call %s: %w", chargeStationId, err)
+		}
+		calls = append(calls, call)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list queued calls %s: %w", chargeStationId, err)
+	}
+	return calls, nil
+}
+
+func (s *Store) DeleteQueuedCall(ctx context.Context, chargeStationId, messageId string) error {
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM queued_calls
+		WHERE charge_station_id = $1 AND message_id = $2`, chargeStationId, messageId)
+	if err != nil {
+		return fmt.Errorf("deleting queued call %s/%s: %w", chargeStationId, messageId, err)
+	}
+	return nil
+}
+
+func scanQueuedCall(row pgx.Row) (*store.QueuedCall, error) {
+	var call store.QueuedCall
+	var state string
+	var lastAttemptAt *time.Time
+	err := row.Scan(&call.ChargeStationId, &call.MessageId, &call.OcppVersion, &call.Action, &call.RequestPayload,
+		&call.Priority, &state, &call.Attempts, &call.QueuedAt, &call.ExpiresAt, &lastAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	call.State = store.QueuedCallState(state)
+	call.QueuedAt = call.QueuedAt.UTC()
+	call.ExpiresAt = call.ExpiresAt.UTC()
+	if lastAttemptAt != nil {
+		t := lastAttemptAt.UTC()
+		call.LastAttemptAt = &t
+	}
+	return &call, nil
+}
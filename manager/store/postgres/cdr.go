@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// cdrIntervalCondition selects the CDRs last updated in the interval given by
+// the first two query parameters: a NULL parameter leaves the interval open.
+const cdrIntervalCondition = `($1::TIMESTAMPTZ IS NULL OR last_updated >= $1) AND ($2::TIMESTAMPTZ IS NULL OR last_updated < $2)`
+
+func (s *Store) CreateCdr(ctx context.Context, cdr *store.Cdr) error {
+	data, err := json.Marshal(cdr)
+	if err != nil {
+		return fmt.Errorf("marshal cdr %s: %w", cdr.Id, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO cdrs (id, charge_station_id, transaction_id, last_updated, cdr)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING`,
+		cdr.Id, cdr.ChargeStationId, cdr.TransactionId, cdr.LastUpdated, data)
+	if err != nil {
+		return fmt.Errorf("create cdr %s: %w", cdr.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupCdr(ctx context.Context, id string) (*store.Cdr, error) {
+	var data []byte
+	err := s.pool.QueryRow(ctx, `SELECT cdr FROM cdrs WHERE id = $1`, id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup cdr %s: %w", id, err)
+	}
+	var cdr store.Cdr
+	if err = json.Unmarshal(data, &cdr); err != nil {
+		return nil, fmt.Errorf("unmarshal cdr %s: %w", id, err)
+	}
+	return &cdr, nil
+}
+
+func (s *Store) ListCdrs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Cdr, int, error) {
+	var total int
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM cdrs WHERE `+cdrIntervalCondition, dateFrom, dateTo).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count cdrs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT cdr FROM cdrs WHERE `+cdrIntervalCondition+`
+		ORDER BY last_updated, id OFFSET $3 LIMIT $4`, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list cdrs: %w", err)
+	}
+	defer rows.Close()
+	cdrs := make([]*store.Cdr, 0)
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, 0, fmt.Errorf("map cdr: %w", err)
+		}
+		var cdr store.Cdr
+		if err = json.Unmarshal(data, &cdr); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal cdr: %w", err)
+		}
+		cdrs = append(cdrs, &cdr)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list cdrs: %w", err)
+	}
+	return cdrs, total, nil
+}
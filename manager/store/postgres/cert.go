@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Store) SetCertificate(ctx context.Context, pemCertificate string) error {
+	certificateHash, err := getPEMCertificateHash(pemCertificate)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO certificates (certificate_hash, pem_certificate)
+		VALUES ($1, $2)
+		ON CONFLICT (certificate_hash) DO UPDATE SET pem_certificate = EXCLUDED.pem_certificate`,
+		certificateHash, pemCertificate)
+	if err != nil {
+		return fmt.Errorf("setting certificate %s: %w", certificateHash, err)
+	}
+	return nil
+}
+
+func getPEMCertificateHash(pemCertificate string) (string, error) {
+	var cert *x509.Certificate
+	block, _ := pem.Decode([]byte(pemCertificate))
+	if block != nil {
+		if block.Type == "CERTIFICATE" {
+			var err error
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return "", err
+			}
+		} else {
+			return "", fmt.Errorf("pem block does not contain certificate, but %s", block.Type)
+		}
+	} else {
+		return "", fmt.Errorf("pem block not found")
+	}
+
+	hash := sha256.Sum256(cert.Raw)
+	b64Hash := base64.RawURLEncoding.EncodeToString(hash[:])
+	return b64Hash, nil
+}
+
+func (s *Store) LookupCertificate(ctx context.Context, certificateHash string) (string, error) {
+	var pemCertificate string
+	err := s.pool.QueryRow(ctx, "SELECT pem_certificate FROM certificates WHERE certificate_hash = $1", certificateHash).
+		Scan(&pemCertificate)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("lookup certificate %s: %w", certificateHash, err)
+	}
+	return pemCertificate, nil
+}
+
+func (s *Store) DeleteCertificate(ctx context.Context, certificateHash string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM certificates WHERE certificate_hash = $1", certificateHash)
+	if err != nil {
+		return fmt.Errorf("delete certificate %s: %w", certificateHash, err)
+	}
+	return nil
+}
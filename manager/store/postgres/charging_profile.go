@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const chargingProfileColumns = `charge_station_id, charging_profile_id, connector_id, transaction_id, stack_level,
+	charging_profile_purpose, charging_profile_kind, recurrency_kind, valid_from, valid_to, charging_schedule,
+	status, send_after, load_balanced`
+
+func (s *Store) UpdateChargeStationChargingProfiles(ctx context.Context, chargeStationId string, profiles *store.ChargeStationChargingProfiles) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		for _, p := range profiles.ChargingProfiles {
+			schedule, err := json.Marshal(p.ChargingSchedule)
+			if err != nil {
+				return fmt.Errorf("marshal charging schedule %d: %w", p.ChargingProfileId, err)
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO charge_station_charging_profiles (`+chargingProfileColumns+`)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+				ON CONFLICT (charge_station_id, charging_profile_id) DO UPDATE SET
+					connector_id = EXCLUDED.connector_id,
+					transaction_id = EXCLUDED.transaction_id,
+					stack_level = EXCLUDED.stack_level,
+					charging_profile_purpose = EXCLUDED.charging_profile_purpose,
+					charging_profile_kind = EXCLUDED.charging_profile_kind,
+					recurrency_kind = EXCLUDED.recurrency_kind,
+					valid_from = EXCLUDED.valid_from,
+					valid_to = EXCLUDED.valid_to,
+					charging_schedule = EXCLUDED.charging_schedule,
+					status = EXCLUDED.status,
+					send_after = EXCLUDED.send_after,
+					load_balanced = EXCLUDED.load_balanced`,
+				chargeStationId, p.ChargingProfileId, p.ConnectorId, p.TransactionId, p.StackLevel,
+				string(p.ChargingProfilePurpose), string(p.ChargingProfileKind), (*string)(p.RecurrencyKind),
+				p.ValidFrom, p.ValidTo, schedule, string(p.Status), p.SendAfter, p.LoadBalanced)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationChargingProfiles(ctx context.Context, chargeStationId string) (*store.ChargeStationChargingProfiles, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+chargingProfileColumns+`
+		FROM charge_station_charging_profiles WHERE charge_station_id = $1
+		ORDER BY charging_profile_id`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	profiles, err := collectChargeStationChargingProfiles(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station charging profiles %s: %w", chargeStationId, err)
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return profiles[0], nil
+}
+
+func (s *Store) ListChargeStationChargingProfiles(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationChargingProfiles, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+chargingProfileColumns+`
+		FROM charge_station_charging_profiles
+		WHERE charge_station_id IN (
+			SELECT DISTINCT charge_station_id FROM charge_station_charging_profiles
+			WHERE charge_station_id > $1
+			ORDER BY charge_station_id
+			LIMIT $2)
+		ORDER BY charge_station_id, charging_profile_id`, previousCsId, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station charging profiles: %w", err)
+	}
+	profiles, err := collectChargeStationChargingProfiles(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station charging profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (s *Store) DeleteChargeStationChargingProfile(ctx context.Context, chargeStationId string, chargingProfileId int) error {
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM charge_station_charging_profiles
+		WHERE charge_station_id = $1 AND charging_profile_id = $2`, chargeStationId, chargingProfileId)
+	if err != nil {
+		return fmt.Errorf("delete charge station charging profile %s/%d: %w", chargeStationId, chargingProfileId, err)
+	}
+	return nil
+}
+
+// collectChargeStationChargingProfiles groups the charging profile rows by charge
+// station. The rows must be ordered by charge station id.
+func collectChargeStationChargingProfiles(rows pgx.Rows) ([]*store.ChargeStationChargingProfiles, error) {
+	defer rows.Close()
+	var result []*store.ChargeStationChargingProfiles
+	var current *store.ChargeStationChargingProfiles
+	for rows.Next() {
+		var csId, purpose, kind, status string
+		var recurrencyKind *string
+		var validFrom, validTo *time.Time
+		var schedule []byte
+		var sendAfter time.Time
+		var p store.ChargingProfile
+		err := rows.Scan(&csId, &p.ChargingProfileId, &p.ConnectorId, &p.TransactionId, &p.StackLevel,
+			&purpose, &kind, &recurrencyKind, &validFrom, &validTo, &schedule, &status, &sendAfter, &p.LoadBalanced)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(schedule, &p.ChargingSchedule); err != nil {
+			return nil, fmt.Errorf("unmarshal charging schedule: %w", err)
+		}
+		p.ChargingProfilePurpose = store.ChargingProfilePurpose(purpose)
+		p.ChargingProfileKind = store.ChargingProfileKind(kind)
+		p.RecurrencyKind = (*store.RecurrencyKind)(recurrencyKind)
+		p.ValidFrom = utcPtr(validFrom)
+		p.ValidTo = utcPtr(validTo)
+		p.Status = store.ChargingProfileStatus(status)
+		p.SendAfter = sendAfter.UTC()
+		if current == nil || current.ChargeStationId != csId {
+			current = &store.ChargeStationChargingProfiles{
+				ChargeStationId: csId,
+			}
+			result = append(result, current)
+		}
+		current.ChargingProfiles = append(current.ChargingProfiles, &p)
+	}
+	return result, rows.Err()
+}
+
+func (s *Store) SetChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, schedule *store.CompositeSchedule) error {
+	var chargingSchedule []byte
+	if schedule.ChargingSchedule != nil {
+		var err error
+		chargingSchedule, err = json.Marshal(schedule.ChargingSchedule)
+		if err != nil {
+			return fmt.Errorf("marshal composite schedule %s/%d: %w", chargeStationId, schedule.ConnectorId, err)
+		}
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_composite_schedules (charge_station_id, connector_id, schedule_start, charging_schedule, retrieved_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (charge_station_id, connector_id) DO UPDATE SET
+			schedule_start = EXCLUDED.schedule_start,
+			charging_schedule = EXCLUDED.charging_schedule,
+			retrieved_at = EXCLUDED.retrieved_at`,
+		chargeStationId, schedule.ConnectorId, schedule.ScheduleStart, chargingSchedule, schedule.RetrievedAt)
+	if err != nil {
+		return fmt.Errorf("setting charge station composite schedule %s/%d: %w", chargeStationId, schedule.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationCompositeSchedule(ctx context.Context, chargeStationId string, connectorId int) (*store.CompositeSchedule, error) {
+	var schedule store.CompositeSchedule
+	var scheduleStart *time.Time
+	var chargingSchedule []byte
+	var retrievedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		SELECT connector_id, schedule_start, charging_schedule, retrieved_at
+		FROM charge_station_composite_schedules WHERE charge_station_id = $1 AND connector_id = $2`,
+		chargeStationId, connectorId).
+		Scan(&schedule.ConnectorId, &scheduleStart, &chargingSchedule, &retrievedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station composite schedule %s/%d: %w", chargeStationId, connectorId, err)
+	}
+	if chargingSchedule != nil {
+		schedule.ChargingSchedule = new(store.ChargingSchedule)
+		if err = json.Unmarshal(chargingSchedule, schedule.ChargingSchedule); err != nil {
+			return nil, fmt.Errorf("unmarshal composite schedule %s/%d: %w", chargeStationId, connectorId, err)
+		}
+	}
+	schedule.ScheduleStart = utcPtr(scheduleStart)
+	schedule.RetrievedAt = retrievedAt.UTC()
+	return &schedule, nil
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
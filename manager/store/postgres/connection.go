@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const connectionColumns = `charge_station_id, connected, gateway_id, connection_id, protocol, remote_addr,
+	security_profile, certificate_hash, connected_at, disconnected_at, last_seen`
+
+func (s *Store) SetChargeStationConnection(ctx context.Context, chargeStationId string, connection *store.ChargeStationConnection) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_connections (`+connectionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (charge_station_id) DO UPDATE SET
+			connected = EXCLUDED.connected,
+			gateway_id = EXCLUDED.gateway_id,
+			connection_id = EXCLUDED.connection_id,
+			protocol = EXCLUDED.protocol,
+			remote_addr = EXCLUDED.remote_addr,
+			security_profile = EXCLUDED.security_profile,
+			certificate_hash = EXCLUDED.certificate_hash,
+			connected_at = EXCLUDED.connected_at,
+			disconnected_at = EXCLUDED.disconnected_at,
+			last_seen = EXCLUDED.last_seen`,
+		chargeStationId, connection.Connected, connection.GatewayId, connection.ConnectionId, connection.Protocol,
+		connection.RemoteAddr, int16(connection.SecurityProfile), connection.CertificateHash,
+		nullTime(connection.ConnectedAt), connection.DisconnectedAt, nullTime(connection.LastSeen))
+	if err != nil {
+		return fmt.Errorf("setting charge station connection %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnection(ctx context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+connectionColumns+`
+		FROM charge_station_connections WHERE charge_station_id = $1`, chargeStationId)
+	connection, err := scanConnection(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station connection %s: %w", chargeStationId, err)
+	}
+	return connection, nil
+}
+
+func (s *Store) ListChargeStationConnections(ctx context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+connectionColumns+`
+		FROM charge_station_connections ORDER BY charge_station_id OFFSET $1 LIMIT $2`, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station connections: %w", err)
+	}
+	defer rows.Close()
+	connections := make([]*store.ChargeStationConnection, 0)
+	for rows.Next() {
+		connection, err := scanConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map charge station connection: %w", err)
+		}
+		connections = append(connections, connection)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list charge station connections: %w", err)
+	}
+	return connections, nil
+}
+
+func (s *Store) SetChargeStationLastSeen(ctx context.Context, chargeStationId string, lastSeen time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_connections (charge_station_id, last_seen)
+		VALUES ($1, $2)
+		ON CONFLICT (charge_station_id) DO UPDATE SET last_seen = EXCLUDED.last_seen`,
+		chargeStationId, lastSeen)
+	if err != nil {
+		return fmt.Errorf("setting charge station last seen %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) SetChargeStationDisconnected(ctx context.Context, chargeStationId, connectionId string, disconnectedAt time.Time) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE charge_station_connections SET
+			connected = false,
+			disconnected_at = $3,
+			last_seen = GREATEST(last_seen, $3)
+		WHERE charge_station_id = $1 AND connection_id = $2`,
+		chargeStationId, connectionId, disconnectedAt)
+	if err != nil {
+		return false, fmt.Errorf("setting charge station disconnected %s: %w", chargeStationId, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// nullTime stores an unset time as NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func scanConnection(row pgx.Row) (*store.ChargeStationConnection, error) {
+	var connection store.ChargeStationConnection
+	var securityProfile int16
+	var connectedAt, disconnectedAt, lastSeen *time.Time
+	err := row.Scan(&connection.ChargeStationId, &connection.Connected, &connection.GatewayId, &connection.ConnectionId,
+		&connection.Protocol, &connection.RemoteAddr, &securityProfile, &connection.CertificateHash,
+		&connectedAt, &disconnectedAt, &lastSeen)
+	if err != nil {
+		return nil, err
+	}
+	connection.SecurityProfile = store.SecurityProfile(securityProfile)
+	if connectedAt != nil {
+		connection.ConnectedAt = connectedAt.UTC()
+	}
+	if disconnectedAt != nil {
+		t := disconnectedAt.UTC()
+		connection.DisconnectedAt = &t
+	}
+	if lastSeen != nil {
+		connection.LastSeen = lastSeen.UTC()
+	}
+	return &connection, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const connectorStatusColumns = `evse_id, connector_id, status, error_code, info, vendor_id, vendor_error_code, timestamp`
+
+func (s *Store) SetChargeStationConnectorStatus(ctx context.Context, chargeStationId string, status *store.ConnectorStatus) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_connector_statuses (charge_station_id, `+connectorStatusColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (charge_station_id, evse_id, connector_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			error_code = EXCLUDED.error_code,
+			info = EXCLUDED.info,
+			vendor_id = EXCLUDED.vendor_id,
+			vendor_error_code = EXCLUDED.vendor_error_code,
+			timestamp = EXCLUDED.timestamp`,
+		chargeStationId, status.EvseId, status.ConnectorId, status.Status, status.ErrorCode, status.Info,
+		status.VendorId, status.VendorErrorCode, status.Timestamp)
+	if err != nil {
+		return fmt.Errorf("setting charge station connector status %s/%d/%d: %w", chargeStationId, status.EvseId, status.ConnectorId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnectorStatus(ctx context.Context, chargeStationId string, evseId, connectorId int) (*store.ConnectorStatus, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+connectorStatusColumns+`
+		FROM charge_station_connector_statuses
+		WHERE charge_station_id = $1 AND evse_id = $2 AND connector_id = $3`,
+		chargeStationId, evseId, connectorId)
+	status, err := scanConnectorStatus(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station connector status %s/%d/%d: %w", chargeStationId, evseId, connectorId, err)
+	}
+	return status, nil
+}
+
+func (s *Store) ListChargeStationConnectorStatuses(ctx context.Context, chargeStationId string) ([]*store.ConnectorStatus, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+connectorStatusColumns+`
+		FROM charge_station_connector_statuses WHERE charge_station_id = $1
+		ORDER BY evse_id, connector_id`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station connector statuses %s: %w", chargeStationId, err)
+	}
+	defer rows.Close()
+	var statuses []*store.ConnectorStatus
+	for rows.Next() {
+		status, err := scanConnectorStatus(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map charge station connector status %s: %w", chargeStationId, err)
+		}
+		statuses = append(statuses, status)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list charge station connector statuses %s: %w", chargeStationId, err)
+	}
+	return statuses, nil
+}
+
+func scanConnectorStatus(row pgx.Row) (*store.ConnectorStatus, error) {
+	var status store.ConnectorStatus
+	var timestamp time.Time
+	err := row.Scan(&status.EvseId, &status.ConnectorId, &status.Status, &status.ErrorCode, &status.Info,
+		&status.VendorId, &status.VendorErrorCode, &timestamp)
+	if err != nil {
+		return nil, err
+	}
+	status.Timestamp = timestamp.UTC()
+	return &status, nil
+}
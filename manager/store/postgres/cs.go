@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Store) SetChargeStationAuth(ctx context.Context, chargeStationId string, auth *store.ChargeStationAuth) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_auth (charge_station_id, security_profile, base64_sha256_password, invalid_username_allowed)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (charge_station_id) DO UPDATE SET
+			security_profile = EXCLUDED.security_profile,
+			base64_sha256_password = EXCLUDED.base64_sha256_password,
+			invalid_username_allowed = EXCLUDED.invalid_username_allowed`,
+		chargeStationId, int16(auth.SecurityProfile), auth.Base64SHA256Password, auth.InvalidUsernameAllowed)
+	if err != nil {
+		return fmt.Errorf("setting charge station auth %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationAuth(ctx context.Context, chargeStationId string) (*store.ChargeStationAuth, error) {
+	var auth store.ChargeStationAuth
+	var securityProfile int16
+	err := s.pool.QueryRow(ctx, `
+		SELECT security_profile, base64_sha256_password, invalid_username_allowed
+		FROM charge_station_auth WHERE charge_station_id = $1`, chargeStationId).
+		Scan(&securityProfile, &auth.Base64SHA256Password, &auth.InvalidUsernameAllowed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station %s: %w", chargeStationId, err)
+	}
+	auth.SecurityProfile = store.SecurityProfile(securityProfile)
+	return &auth, nil
+}
+
+func (s *Store) UpdateChargeStationSettings(ctx context.Context, chargeStationId string, settings *store.ChargeStationSettings) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		for name, setting := range settings.Settings {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO charge_station_settings (charge_station_id, name, value, status, send_after)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (charge_station_id, name) DO UPDATE SET
+					value = EXCLUDED.value,
+					status = EXCLUDED.status,
+					send_after = EXCLUDED.send_after`,
+				chargeStationId, name, setting.Value, string(setting.Status), setting.SendAfter)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station settings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationSettings(ctx context.Context, chargeStationId string) (*store.ChargeStationSettings, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT charge_station_id, name, value, status, send_after
+		FROM charge_station_settings WHERE charge_station_id = $1`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station settings %s: %w", chargeStationId, err)
+	}
+	settings, err := collectChargeStationSettings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station settings %s: %w", chargeStationId, err)
+	}
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	return settings[0], nil
+}
+
+func (s *Store) ListChargeStationSettings(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationSettings, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT charge_station_id, name, value, status, send_after
+		FROM charge_station_settings
+		WHERE charge_station_id IN (
+			SELECT DISTINCT charge_station_id FROM charge_station_settings
+			WHERE charge_station_id > $1
+			ORDER BY charge_station_id
+			LIMIT $2)
+		ORDER BY charge_station_id, name`, previousCsId, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station settings: %w", err)
+	}
+	settings, err := collectChargeStationSettings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *Store) DeleteChargeStationSettings(ctx context.Context, chargeStationId string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM charge_station_settings WHERE charge_station_id = $1", chargeStationId)
+	if err != nil {
+		return fmt.Errorf("delete charge station settings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+// collectChargeStationSettings groups the setting rows by charge station. The
+// rows must be ordered by charge station id.
+func collectChargeStationSettings(rows pgx.Rows) ([]*store.ChargeStationSettings, error) {
+	defer rows.Close()
+	var result []*store.ChargeStationSettings
+	var current *store.ChargeStationSettings
+	for rows.Next() {
+		var csId, name, value, status string
+		var sendAfter time.Time
+		if err := rows.Scan(&csId, &name, &value, &status, &sendAfter); err != nil {
+			return nil, err
+		}
+		if current == nil || current.ChargeStationId != csId {
+			current = &store.ChargeStationSettings{
+				ChargeStationId: csId,
+				Settings:        make(map[string]*store.ChargeStationSetting),
+			}
+			result = append(result, current)
+		}
+		current.Settings[name] = &store.ChargeStationSetting{
+			Value:     value,
+			Status:    store.ChargeStationSettingStatus(status),
+			SendAfter: sendAfter.UTC(),
+		}
+	}
+	return result, rows.Err()
+}
+
+func (s *Store) UpdateChargeStationInstallCertificates(ctx context.Context, chargeStationId string, certificates *store.ChargeStationInstallCertificates) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		for _, c := range certificates.Certificates {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO charge_station_install_certificates (charge_station_id, certificate_id, certificate_type, certificate_data, status, send_after)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (charge_station_id, certificate_id) DO UPDATE SET
+					certificate_type = EXCLUDED.certificate_type,
+					certificate_data = EXCLUDED.certificate_data,
+					status = EXCLUDED.status,
+					send_after = EXCLUDED.send_after`,
+				chargeStationId, c.CertificateId, string(c.CertificateType), c.CertificateData,
+				string(c.CertificateInstallationStatus), c.SendAfter)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update charge station install certificates %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationInstallCertificates(ctx context.Context, chargeStationId string) (*store.ChargeStationInstallCertificates, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT charge_station_id, certificate_id, certificate_type, certificate_data, status, send_after
+		FROM charge_station_install_certificates WHERE charge_station_id = $1
+		ORDER BY certificate_id`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station install certificates %s: %w", chargeStationId, err)
+	}
+	certs, err := collectChargeStationInstallCertificates(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station install certificates %s: %w", chargeStationId, err)
+	}
+	if len(certs) == 0 {
+		return nil, nil
+	}
+	return certs[0], nil
+}
+
+func (s *Store) ListChargeStationInstallCertificates(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationInstallCertificates, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT charge_station_id, certificate_id, certificate_type, certificate_data, status, send_after
+		FROM charge_station_install_certificates
+		WHERE charge_station_id IN (
+			SELECT DISTINCT charge_station_id FROM charge_station_install_certificates
+			WHERE charge_station_id > $1
+			ORDER BY charge_station_id
+			LIMIT $2)
+		ORDER BY charge_station_id, certificate_id`, previousCsId, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station install certificates: %w", err)
+	}
+	certs, err := collectChargeStationInstallCertificates(rows)
+	if err != nil {
+		return nil, fmt.Errorf("map charge station install certificates: %w", err)
+	}
+	return certs, nil
+}
+
+// collectChargeStationInstallCertificates groups the certificate rows by charge
+// station. The rows must be ordered by charge station id.
+func collectChargeStationInstallCertificates(rows pgx.Rows) ([]*store.ChargeStationInstallCertificates, error) {
+	defer rows.Close()
+	var result []*store.ChargeStationInstallCertificates
+	var current *store.ChargeStationInstallCertificates
+	for rows.Next() {
+		var csId, certId, certType, certData, status string
+		var sendAfter time.Time
+		if err := rows.Scan(&csId, &certId, &certType, &certData, &status, &sendAfter); err != nil {
+			return nil, err
+		}
+		if current == nil || current.ChargeStationId != csId {
+			current = &store.ChargeStationInstallCertificates{
+				ChargeStationId: csId,
+			}
+			result = append(result, current)
+		}
+		current.Certificates = append(current.Certificates, &store.ChargeStationInstallCertificate{
+			CertificateType:               store.CertificateType(certType),
+			CertificateId:                 certId,
+			CertificateData:               certData,
+			CertificateInstallationStatus: store.CertificateInstallationStatus(status),
+			SendAfter:                     sendAfter.UTC(),
+		})
+	}
+	return result, rows.Err()
+}
+
+func (s *Store) SetChargeStationRuntimeDetails(ctx context.Context, chargeStationId string, details *store.ChargeStationRuntimeDetails) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_runtime_details (charge_station_id, ocpp_version)
+		VALUES ($1, $2)
+		ON CONFLICT (charge_station_id) DO UPDATE SET ocpp_version = EXCLUDED.ocpp_version`,
+		chargeStationId, details.OcppVersion)
+	if err != nil {
+		return fmt.Errorf("setting charge station runtime details %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationRuntimeDetails(ctx context.Context, chargeStationId string) (*store.ChargeStationRuntimeDetails, error) {
+	var details store.ChargeStationRuntimeDetails
+	err := s.pool.QueryRow(ctx, `
+		SELECT ocpp_version FROM charge_station_runtime_details WHERE charge_station_id = $1`, chargeStationId).
+		Scan(&details.OcppVersion)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station runtime details %s: %w", chargeStationId, err)
+	}
+	return &details, nil
+}
+
+func (s *Store) SetChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_trigger_messages (charge_station_id, trigger_message, trigger_status, send_after)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (charge_station_id) DO UPDATE SET
+			trigger_message = EXCLUDED.trigger_message,
+			trigger_status = EXCLUDED.trigger_status,
+			send_after = EXCLUDED.send_after`,
+		chargeStationId, string(triggerMessage.TriggerMessage), string(triggerMessage.TriggerStatus), triggerMessage.SendAfter)
+	if err != nil {
+		return fmt.Errorf("setting charge station trigger message %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM charge_station_trigger_messages WHERE charge_station_id = $1", chargeStationId)
+	if err != nil {
+		return fmt.Errorf("delete charge station trigger message %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargeStationTriggerMessage(ctx context.Context, chargeStationId string) (*store.ChargeStationTriggerMessage, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT charge_station_id, trigger_message, trigger_status, send_after
+		FROM charge_station_trigger_messages WHERE charge_station_id = $1`, chargeStationId)
+	triggerMessage, err := scanChargeStationTriggerMessage(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station trigger message %s: %w", chargeStationId, err)
+	}
+	return triggerMessage, nil
+}
+
+func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationTriggerMessage, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT charge_station_id, trigger_message, trigger_status, send_after
+		FROM charge_station_trigger_messages
+		WHERE charge_station_id > $1
+		ORDER BY charge_station_id
+		LIMIT $2`, previousCsId, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station trigger messages: %w", err)
+	}
+	defer rows.Close()
+	var triggerMessages []*store.ChargeStationTriggerMessage
+	for rows.Next() {
+		triggerMessage, err := scanChargeStationTriggerMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map charge station trigger message: %w", err)
+		}
+		triggerMessages = append(triggerMessages, triggerMessage)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list charge station trigger messages: %w", err)
+	}
+	return triggerMessages, nil
+}
+
+func scanChargeStationTriggerMessage(row pgx.Row) (*store.ChargeStationTriggerMessage, error) {
+	var triggerMessage store.ChargeStationTriggerMessage
+	var sendAfter time.Time
+	err := row.Scan(&triggerMessage.ChargeStationId, &triggerMessage.TriggerMessage, &triggerMessage.TriggerStatus, &sendAfter)
+	if err != nil {
+		return nil, err
+	}
+	triggerMessage.SendAfter = sendAfter.UTC()
+	return &triggerMessage, nil
+}
@@ -0,0 +1,4 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package postgres provides an implementation of store.Engine using PostgreSQL.
+package postgres
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const evseMappingColumns = `location_id, evse_uid, evse_id, charge_station_id, charge_station_evse_id, connectors`
+
+func (s *Store) SetEvseMapping(ctx context.Context, mapping *store.EvseMapping) error {
+	connectors := mapping.Connectors
+	if connectors == nil {
+		connectors = []store.ConnectorMapping{}
+	}
+	connectorsJson, err := json.Marshal(connectors)
+	if err != nil {
+		return fmt.Errorf("marshal connectors for evse mapping %s/%s: %w", mapping.LocationId, mapping.EvseUid, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO evse_mappings (`+evseMappingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (location_id, evse_uid) DO UPDATE SET
+			evse_id = EXCLUDED.evse_id,
+			charge_station_id = EXCLUDED.charge_station_id,
+			charge_station_evse_id = EXCLUDED.charge_station_evse_id,
+			connectors = EXCLUDED.connectors`,
+		mapping.LocationId, mapping.EvseUid, mapping.EvseId, mapping.ChargeStationId, mapping.ChargeStationEvseId, connectorsJson)
+	if err != nil {
+		return fmt.Errorf("set evse mapping %s/%s: %w", mapping.LocationId, mapping.EvseUid, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupEvseMapping(ctx context.Context, locationId, evseUid string) (*store.EvseMapping, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+evseMappingColumns+` FROM evse_mappings WHERE location_id = $1 AND evse_uid = $2`,
+		locationId, evseUid)
+	mapping, err := scanEvseMapping(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup evse mapping %s/%s: %w", locationId, evseUid, err)
+	}
+	return mapping, nil
+}
+
+func (s *Store) LookupEvseMappingByEvseId(ctx context.Context, evseId string) (*store.EvseMapping, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+evseMappingColumns+` FROM evse_mappings WHERE evse_id = $1`, evseId)
+	mapping, err := scanEvseMapping(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup evse mapping for evse id %s: %w", evseId, err)
+	}
+	return mapping, nil
+}
+
+func (s *Store) ListEvseMappingsForChargeStation(ctx context.Context, chargeStationId string) ([]*store.EvseMapping, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+evseMappingColumns+` FROM evse_mappings WHERE charge_station_id = $1
+		ORDER BY location_id, evse_uid`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list evse mappings for %s: %w", chargeStationId, err)
+	}
+	defer rows.Close()
+
+	var mappings []*store.EvseMapping
+	for rows.Next() {
+		mapping, err := scanEvseMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list evse mappings for %s: %w", chargeStationId, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list evse mappings for %s: %w", chargeStationId, err)
+	}
+	return mappings, nil
+}
+
+func (s *Store) DeleteEvseMapping(ctx context.Context, locationId, evseUid string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM evse_mappings WHERE location_id = $1 AND evse_uid = $2`, locationId, evseUid)
+	if err != nil {
+		return fmt.Errorf("delete evse mapping %s/%s: %w", locationId, evseUid, err)
+	}
+	return nil
+}
+
+func scanEvseMapping(row pgx.Row) (*store.EvseMapping, error) {
+	var mapping store.EvseMapping
+	var connectors []byte
+	err := row.Scan(&mapping.LocationId, &mapping.EvseUid, &mapping.EvseId, &mapping.ChargeStationId,
+		&mapping.ChargeStationEvseId, &connectors)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(connectors, &mapping.Connectors); err != nil {
+		return nil, fmt.Errorf("unmarshal connectors: %w", err)
+	}
+	if len(mapping.Connectors) == 0 {
+		mapping.Connectors = nil
+	}
+	return &mapping, nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const locationColumns = `id, country_code, party_id, address, city, latitude, longitude, country, evses, name, parking_type, postal_code, last_updated`
+
+// locationIntervalCondition selects the locations last updated in the interval
+// given by the first two query parameters: a NULL parameter leaves the interval
+// open.
+const locationIntervalCondition = `($1::TIMESTAMPTZ IS NULL OR last_updated >= $1) AND ($2::TIMESTAMPTZ IS NULL OR last_updated < $2)`
+
+func (s *Store) SetLocation(ctx context.Context, loc *store.Location) error {
+	var evses []byte
+	if loc.Evses != nil {
+		var err error
+		evses, err = json.Marshal(loc.Evses)
+		if err != nil {
+			return fmt.Errorf("marshal evses for location %s: %w", loc.Id, err)
+		}
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO locations (`+locationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (id) DO UPDATE SET
+			country_code = EXCLUDED.country_code,
+			party_id = EXCLUDED.party_id,
+			address = EXCLUDED.address,
+			city = EXCLUDED.city,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			country = EXCLUDED.country,
+			evses = EXCLUDED.evses,
+			name = EXCLUDED.name,
+			parking_type = EXCLUDED.parking_type,
+			postal_code = EXCLUDED.postal_code,
+			last_updated = EXCLUDED.last_updated`,
+		loc.Id, loc.CountryCode, loc.PartyId, loc.Address, loc.City, loc.Coordinates.Latitude, loc.Coordinates.Longitude, loc.Country,
+		evses, loc.Name, loc.ParkingType, loc.PostalCode, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("setting location %s: %w", loc.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupLocation(ctx context.Context, locationId string) (*store.Location, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+locationColumns+` FROM locations WHERE id = $1`, locationId)
+	loc, err := scanLocation(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup location %s: %w", locationId, err)
+	}
+	return loc, nil
+}
+
+func (s *Store) ListLocations(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Location, int, error) {
+	var total int
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM locations WHERE `+locationIntervalCondition, dateFrom, dateTo).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count locations: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+locationColumns+` FROM locations WHERE `+locationIntervalCondition+`
+		ORDER BY id OFFSET $3 LIMIT $4`, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list locations: %w", err)
+	}
+	defer rows.Close()
+	locations := make([]*store.Location, 0)
+	for rows.Next() {
+		loc, err := scanLocation(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("map location: %w", err)
+		}
+		locations = append(locations, loc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list locations: %w", err)
+	}
+	return locations, total, nil
+}
+
+func (s *Store) SetClientOwnedLocation(ctx context.Context, loc *store.Location) error {
+	data, err := json.Marshal(loc)
+	if err != nil {
+		return fmt.Errorf("marshal client owned location %s/%s/%s: %w", loc.CountryCode, loc.PartyId, loc.Id, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO client_owned_locations (country_code, party_id, id, location)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (country_code, party_id, id) DO UPDATE SET location = EXCLUDED.location`,
+		loc.CountryCode, loc.PartyId, loc.Id, data)
+	if err != nil {
+		return fmt.Errorf("setting client owned location %s/%s/%s: %w", loc.CountryCode, loc.PartyId, loc.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupClientOwnedLocation(ctx context.Context, countryCode, partyId, locationId string) (*store.Location, error) {
+	var data []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT location FROM client_owned_locations WHERE country_code = $1 AND party_id = $2 AND id = $3`,
+		countryCode, partyId, locationId).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup client owned location %s/%s/%s: %w", countryCode, partyId, locationId, err)
+	}
+	var loc store.Location
+	if err = json.Unmarshal(data, &loc); err != nil {
+		return nil, fmt.Errorf("unmarshal client owned location %s/%s/%s: %w", countryCode, partyId, locationId, err)
+	}
+	return &loc, nil
+}
+
+func scanLocation(row pgx.Row) (*store.Location, error) {
+	var loc store.Location
+	var evses []byte
+	var lastUpdated time.Time
+	err := row.Scan(&loc.Id, &loc.CountryCode, &loc.PartyId, &loc.Address, &loc.City, &loc.Coordinates.Latitude, &loc.Coordinates.Longitude,
+		&loc.Country, &evses, &loc.Name, &loc.ParkingType, &loc.PostalCode, &lastUpdated)
+	if err != nil {
+		return nil, err
+	}
+	if evses != nil {
+		if err = json.Unmarshal(evses, &loc.Evses); err != nil {
+			return nil, fmt.Errorf("unmarshal evses: %w", err)
+		}
+	}
+	loc.LastUpdated = lastUpdated.UTC().Format("2006-01-02T15:04:05Z")
+	return &loc, nil
+}
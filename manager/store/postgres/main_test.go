@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var connectionString string
+
+func setup() func() {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image: "postgres:16-alpine",
+		Env: map[string]string{
+			"POSTGRES_USER":     "csms",
+			"POSTGRES_PASSWORD": "csms",
+			"POSTGRES_DB":       "csms",
+		},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor: wait.ForAll(
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+			wait.ForExposedPort(),
+		).WithDeadline(time.Minute),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		log.Println("Failed to wait for started container")
+		log.Fatal(err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "5432/tcp", "")
+	if err != nil {
+		log.Println("Container did not expose endpoint")
+		log.Fatal(err)
+	}
+
+	connectionString = fmt.Sprintf("postgres://csms:csms@%s/csms?sslmode=disable", endpoint)
+
+	return func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Fatalf("failed to terminate container: %s", err.Error())
+		}
+	}
+}
+
+func TestMain(m *testing.M) {
+	teardown := setup()
+	exitVal := m.Run()
+	teardown()
+
+	os.Exit(exitVal)
+}
+
+func cleanupAllTables(t *testing.T) {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, connectionString)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	_, err = conn.Exec(ctx, `TRUNCATE
+		cdrs,
+		certificates,
+		charge_station_auth,
+		charge_station_charging_profiles,
+		charge_station_composite_schedules,
+		charge_station_connections,
+		charge_station_connector_statuses,
+		charge_station_settings,
+		charge_station_install_certificates,
+		charge_station_meter_public_keys,
+		charge_station_meter_readings,
+		charge_station_runtime_details,
+		charge_station_trigger_messages,
+		client_owned_locations,
+		evse_mappings,
+		locations,
+		ocpi_parties,
+		ocpi_registrations,
+		queued_calls,
+		reservations,
+		tariffs,
+		tokens,
+		transactions`)
+	require.NoError(t, err)
+}
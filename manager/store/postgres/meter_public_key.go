@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Store) SetChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, key *store.MeterPublicKey) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO charge_station_meter_public_keys (charge_station_id, evse_id, public_key)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (charge_station_id, evse_id) DO UPDATE SET
+			public_key = EXCLUDED.public_key`,
+		chargeStationId, key.EvseId, key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("setting charge station meter public key %s/%d: %w", chargeStationId, key.EvseId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterPublicKeys(ctx context.Context, chargeStationId string) ([]*store.MeterPublicKey, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT evse_id, public_key
+		FROM charge_station_meter_public_keys WHERE charge_station_id = $1
+		ORDER BY evse_id`, chargeStationId)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station meter public keys %s: %w", chargeStationId, err)
+	}
+	defer rows.Close()
+	var keys []*store.MeterPublicKey
+	for rows.Next() {
+		var key store.MeterPublicKey
+		if err = rows.Scan(&key.EvseId, &key.PublicKey); err != nil {
+			return nil, fmt.Errorf("map charge station meter public key %s: %w", chargeStationId, err)
+		}
+		keys = append(keys, &key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list charge station meter public keys %s: %w", chargeStationId, err)
+	}
+	return keys, nil
+}
+
+func (s *Store) DeleteChargeStationMeterPublicKey(ctx context.Context, chargeStationId string, evseId int) error {
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM charge_station_meter_public_keys
+		WHERE charge_station_id = $1 AND evse_id = $2`, chargeStationId, evseId)
+	if err != nil {
+		return fmt.Errorf("delete charge station meter public key %s/%d: %w", chargeStationId, evseId, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Store) AddChargeStationMeterReadings(ctx context.Context, chargeStationId string, readings []*store.MeterReading) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		for _, reading := range readings {
+			sampledValues, err := json.Marshal(reading.SampledValues)
+			if err != nil {
+				return fmt.Errorf("marshal sampled values: %w", err)
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO charge_station_meter_readings (charge_station_id, connector_id, timestamp, sampled_values)
+				VALUES ($1, $2, $3, $4)`,
+				chargeStationId, reading.ConnectorId, reading.Timestamp, sampledValues)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("add charge station meter readings %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationMeterReadings(ctx context.Context, chargeStationId string, from, to time.Time) ([]*store.MeterReading, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT connector_id, timestamp, sampled_values
+		FROM charge_station_meter_readings
+		WHERE charge_station_id = $1 AND timestamp >= $2 AND timestamp < $3
+		ORDER BY timestamp, connector_id, id`, chargeStationId, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list charge station meter readings %s: %w", chargeStationId, err)
+	}
+	defer rows.Close()
+	var readings []*store.MeterReading
+	for rows.Next() {
+		var reading store.MeterReading
+		var timestamp time.Time
+		var sampledValues []byte
+		if err = rows.Scan(&reading.ConnectorId, &timestamp, &sampledValues); err != nil {
+			return nil, fmt.Errorf("map charge station meter reading %s: %w", chargeStationId, err)
+		}
+		if err = json.Unmarshal(sampledValues, &reading.SampledValues); err != nil {
+			return nil, fmt.Errorf("unmarshal sampled values %s: %w", chargeStationId, err)
+		}
+		reading.Timestamp = timestamp.UTC()
+		readings = append(readings, &reading)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list charge station meter readings %s: %w", chargeStationId, err)
+	}
+	return readings, nil
+}
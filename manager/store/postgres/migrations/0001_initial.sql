@@ -0,0 +1,111 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_auth
+(
+    charge_station_id        TEXT PRIMARY KEY,
+    security_profile         SMALLINT NOT NULL,
+    base64_sha256_password   TEXT     NOT NULL DEFAULT '',
+    invalid_username_allowed BOOLEAN  NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE charge_station_settings
+(
+    charge_station_id TEXT        NOT NULL,
+    name              TEXT        NOT NULL,
+    value             TEXT        NOT NULL,
+    status            TEXT        NOT NULL,
+    send_after        TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (charge_station_id, name)
+);
+
+CREATE TABLE charge_station_install_certificates
+(
+    charge_station_id TEXT        NOT NULL,
+    certificate_id    TEXT        NOT NULL,
+    certificate_type  TEXT        NOT NULL,
+    certificate_data  TEXT        NOT NULL,
+    status            TEXT        NOT NULL,
+    send_after        TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (charge_station_id, certificate_id)
+);
+
+CREATE TABLE charge_station_runtime_details
+(
+    charge_station_id TEXT PRIMARY KEY,
+    ocpp_version      TEXT NOT NULL
+);
+
+CREATE TABLE charge_station_trigger_messages
+(
+    charge_station_id TEXT PRIMARY KEY,
+    trigger_message   TEXT        NOT NULL,
+    trigger_status    TEXT        NOT NULL,
+    send_after        TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE tokens
+(
+    uid           TEXT PRIMARY KEY,
+    country_code  TEXT        NOT NULL,
+    party_id      TEXT        NOT NULL,
+    type          TEXT        NOT NULL,
+    contract_id   TEXT        NOT NULL,
+    visual_number TEXT,
+    issuer        TEXT        NOT NULL,
+    group_id      TEXT,
+    valid         BOOLEAN     NOT NULL,
+    language_code TEXT,
+    cache_mode    TEXT        NOT NULL,
+    last_updated  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE transactions
+(
+    charge_station_id    TEXT    NOT NULL,
+    transaction_id       TEXT    NOT NULL,
+    id_token             TEXT    NOT NULL DEFAULT '',
+    token_type           TEXT    NOT NULL DEFAULT '',
+    meter_values         JSONB   NOT NULL DEFAULT '[]',
+    start_seq_no         INTEGER NOT NULL DEFAULT 0,
+    ended_seq_no         INTEGER NOT NULL DEFAULT 0,
+    updated_seq_no_count INTEGER NOT NULL DEFAULT 0,
+    offline              BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (charge_station_id, transaction_id)
+);
+
+CREATE TABLE certificates
+(
+    certificate_hash TEXT PRIMARY KEY,
+    pem_certificate  TEXT NOT NULL
+);
+
+CREATE TABLE ocpi_registrations
+(
+    token  TEXT PRIMARY KEY,
+    status TEXT NOT NULL
+);
+
+CREATE TABLE ocpi_parties
+(
+    role         TEXT NOT NULL,
+    country_code TEXT NOT NULL,
+    party_id     TEXT NOT NULL,
+    url          TEXT NOT NULL,
+    token        TEXT NOT NULL,
+    PRIMARY KEY (role, country_code, party_id)
+);
+
+CREATE TABLE locations
+(
+    id           TEXT PRIMARY KEY,
+    address      TEXT        NOT NULL,
+    city         TEXT        NOT NULL,
+    latitude     TEXT        NOT NULL,
+    longitude    TEXT        NOT NULL,
+    country      TEXT        NOT NULL,
+    evses        JSONB,
+    name         TEXT        NOT NULL,
+    parking_type TEXT        NOT NULL,
+    postal_code  TEXT        NOT NULL,
+    last_updated TIMESTAMPTZ NOT NULL
+);
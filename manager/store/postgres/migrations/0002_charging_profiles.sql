@@ -0,0 +1,29 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_charging_profiles
+(
+    charge_station_id        TEXT        NOT NULL,
+    charging_profile_id      INTEGER     NOT NULL,
+    connector_id             INTEGER     NOT NULL,
+    transaction_id           TEXT,
+    stack_level              INTEGER     NOT NULL,
+    charging_profile_purpose TEXT        NOT NULL,
+    charging_profile_kind    TEXT        NOT NULL,
+    recurrency_kind          TEXT,
+    valid_from               TIMESTAMPTZ,
+    valid_to                 TIMESTAMPTZ,
+    charging_schedule        JSONB       NOT NULL,
+    status                   TEXT        NOT NULL,
+    send_after               TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (charge_station_id, charging_profile_id)
+);
+
+CREATE TABLE charge_station_composite_schedules
+(
+    charge_station_id TEXT        NOT NULL,
+    connector_id      INTEGER     NOT NULL,
+    schedule_start    TIMESTAMPTZ,
+    charging_schedule JSONB,
+    retrieved_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (charge_station_id, connector_id)
+);
@@ -0,0 +1,15 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_connector_statuses
+(
+    charge_station_id TEXT        NOT NULL,
+    evse_id           INTEGER     NOT NULL,
+    connector_id      INTEGER     NOT NULL,
+    status            TEXT        NOT NULL,
+    error_code        TEXT,
+    info              TEXT,
+    vendor_id         TEXT,
+    vendor_error_code TEXT,
+    timestamp         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (charge_station_id, evse_id, connector_id)
+);
@@ -0,0 +1,13 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_meter_readings
+(
+    id                BIGSERIAL PRIMARY KEY,
+    charge_station_id TEXT        NOT NULL,
+    connector_id      INTEGER     NOT NULL,
+    timestamp         TIMESTAMPTZ NOT NULL,
+    sampled_values    JSONB       NOT NULL
+);
+
+CREATE INDEX charge_station_meter_readings_timestamp_idx
+    ON charge_station_meter_readings (charge_station_id, timestamp);
@@ -0,0 +1,9 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_meter_public_keys
+(
+    charge_station_id TEXT    NOT NULL,
+    evse_id           INTEGER NOT NULL,
+    public_key        TEXT    NOT NULL,
+    PRIMARY KEY (charge_station_id, evse_id)
+);
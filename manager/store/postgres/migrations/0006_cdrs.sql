@@ -0,0 +1,12 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE cdrs
+(
+    id                TEXT PRIMARY KEY,
+    charge_station_id TEXT        NOT NULL,
+    transaction_id    TEXT        NOT NULL,
+    last_updated      TIMESTAMPTZ NOT NULL,
+    cdr               JSONB       NOT NULL
+);
+
+CREATE INDEX cdrs_last_updated_idx ON cdrs (last_updated, id);
@@ -0,0 +1,10 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE tariffs
+(
+    id           TEXT PRIMARY KEY,
+    last_updated TIMESTAMPTZ NOT NULL,
+    tariff       JSONB       NOT NULL
+);
+
+CREATE INDEX tariffs_last_updated_idx ON tariffs (last_updated, id);
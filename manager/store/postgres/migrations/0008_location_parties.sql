@@ -0,0 +1,16 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE locations
+    ADD COLUMN country_code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN party_id     TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX locations_last_updated_idx ON locations (last_updated);
+
+CREATE TABLE client_owned_locations
+(
+    country_code TEXT  NOT NULL,
+    party_id     TEXT  NOT NULL,
+    id           TEXT  NOT NULL,
+    location     JSONB NOT NULL,
+    PRIMARY KEY (country_code, party_id, id)
+);
@@ -0,0 +1,12 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE reservations
+(
+    country_code        TEXT        NOT NULL,
+    party_id            TEXT        NOT NULL,
+    id                  TEXT        NOT NULL,
+    charge_station_id   TEXT        NOT NULL,
+    ocpp_reservation_id INTEGER     NOT NULL,
+    expiry_date         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (country_code, party_id, id)
+);
@@ -0,0 +1,12 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE evse_mappings
+(
+    location_id            TEXT    NOT NULL,
+    evse_uid               TEXT    NOT NULL,
+    evse_id                TEXT UNIQUE,
+    charge_station_id      TEXT    NOT NULL,
+    charge_station_evse_id INTEGER NOT NULL,
+    connectors             JSONB   NOT NULL,
+    PRIMARY KEY (location_id, evse_uid)
+);
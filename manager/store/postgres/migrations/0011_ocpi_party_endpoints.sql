@@ -0,0 +1,10 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE ocpi_registrations
+    ADD COLUMN url TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX ocpi_registrations_url_idx ON ocpi_registrations (url);
+
+ALTER TABLE ocpi_parties
+    ADD COLUMN versions  JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN endpoints JSONB NOT NULL DEFAULT '[]';
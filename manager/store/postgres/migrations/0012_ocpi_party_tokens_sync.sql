@@ -0,0 +1,4 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE ocpi_parties
+    ADD COLUMN tokens_synced_at TIMESTAMPTZ;
@@ -0,0 +1,16 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE charge_station_connections
+(
+    charge_station_id TEXT PRIMARY KEY,
+    connected         BOOLEAN  NOT NULL DEFAULT FALSE,
+    gateway_id        TEXT     NOT NULL DEFAULT '',
+    connection_id     TEXT     NOT NULL DEFAULT '',
+    protocol          TEXT     NOT NULL DEFAULT '',
+    remote_addr       TEXT     NOT NULL DEFAULT '',
+    security_profile  SMALLINT NOT NULL DEFAULT 0,
+    certificate_hash  TEXT     NOT NULL DEFAULT '',
+    connected_at      TIMESTAMPTZ,
+    disconnected_at   TIMESTAMPTZ,
+    last_seen         TIMESTAMPTZ
+);
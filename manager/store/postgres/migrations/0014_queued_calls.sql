@@ -0,0 +1,17 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE TABLE queued_calls
+(
+    charge_station_id TEXT        NOT NULL,
+    message_id        TEXT        NOT NULL,
+    ocpp_version      TEXT        NOT NULL,
+    action            TEXT        NOT NULL,
+    request_payload   TEXT        NOT NULL,
+    priority          INTEGER     NOT NULL DEFAULT 0,
+    state             TEXT        NOT NULL,
+    attempts          INTEGER     NOT NULL DEFAULT 0,
+    queued_at         TIMESTAMPTZ NOT NULL,
+    expires_at        TIMESTAMPTZ NOT NULL,
+    last_attempt_at   TIMESTAMPTZ,
+    PRIMARY KEY (charge_station_id, message_id)
+);
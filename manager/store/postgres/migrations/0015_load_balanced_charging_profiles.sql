@@ -0,0 +1,4 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE charge_station_charging_profiles
+    ADD COLUMN load_balanced BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE transactions
+    ADD COLUMN last_updated TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX transactions_last_updated ON transactions (last_updated, charge_station_id, transaction_id);
@@ -0,0 +1,6 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+ALTER TABLE transactions
+    ADD COLUMN evse_id INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX transactions_ongoing ON transactions (charge_station_id, evse_id) WHERE ended_seq_no = 0;
@@ -0,0 +1,3 @@
+-- SPDX-License-Identifier: Apache-2.0
+
+CREATE INDEX evse_mappings_charge_station_id ON evse_mappings (charge_station_id);
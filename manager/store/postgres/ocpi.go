@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+)
+
+const partyColumns = `role, country_code, party_id, url, token, versions, endpoints, tokens_synced_at`
+
+func (s *Store) SetRegistrationDetails(ctx context.Context, token string, registration *store.OcpiRegistration) error {
+	slog.Info("setting registration", "token", token, "status", registration.Status)
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO ocpi_registrations (token, status, url)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token) DO UPDATE SET
+			status = EXCLUDED.status,
+			url = EXCLUDED.url`,
+		token, string(registration.Status), registration.Url)
+	if err != nil {
+		return fmt.Errorf("setting registration: %s: %w", token, err)
+	}
+	return nil
+}
+
+func (s *Store) GetRegistrationDetails(ctx context.Context, token string) (*store.OcpiRegistration, error) {
+	var status, url string
+	err := s.pool.QueryRow(ctx, "SELECT status, url FROM ocpi_registrations WHERE token = $1", token).Scan(&status, &url)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup registration %s: %w", token, err)
+	}
+	return &store.OcpiRegistration{
+		Status: store.OcpiRegistrationStatusType(status),
+		Url:    url,
+	}, nil
+}
+
+func (s *Store) DeleteRegistrationDetails(ctx context.Context, token string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM ocpi_registrations WHERE token = $1", token)
+	if err != nil {
+		return fmt.Errorf("delete registration %s: %w", token, err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteRegistrationDetailsForUrl(ctx context.Context, url string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM ocpi_registrations WHERE url = $1", url)
+	if err != nil {
+		return fmt.Errorf("delete registrations for %s: %w", url, err)
+	}
+	return nil
+}
+
+func (s *Store) SetPartyDetails(ctx context.Context, partyDetails *store.OcpiParty) error {
+	versions, err := json.Marshal(partyDetails.Versions)
+	if err != nil {
+		return fmt.Errorf("marshal versions for party %s/%s:%s: %w", partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId, err)
+	}
+	endpoints, err := json.Marshal(partyDetails.Endpoints)
+	if err != nil {
+		return fmt.Errorf("marshal endpoints for party %s/%s:%s: %w", partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO ocpi_parties (`+partyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (role, country_code, party_id) DO UPDATE SET
+			url = EXCLUDED.url,
+			token = EXCLUDED.token,
+			versions = EXCLUDED.versions,
+			endpoints = EXCLUDED.endpoints,
+			tokens_synced_at = EXCLUDED.tokens_synced_at`,
+		partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId, partyDetails.Url, partyDetails.Token,
+		versions, endpoints, partyDetails.TokensSyncedAt)
+	if err != nil {
+		return fmt.Errorf("setting party %s/%s:%s: %w", partyDetails.Role, partyDetails.CountryCode, partyDetails.PartyId, err)
+	}
+	return nil
+}
+
+func (s *Store) GetPartyDetails(ctx context.Context, role, countryCode, partyId string) (*store.OcpiParty, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+partyColumns+`
+		FROM ocpi_parties WHERE role = $1 AND country_code = $2 AND party_id = $3`, role, countryCode, partyId)
+	party, err := scanParty(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup party details %s/%s:%s: %w", role, countryCode, partyId, err)
+	}
+	return party, nil
+}
+
+func (s *Store) ListPartyDetailsForRole(ctx context.Context, role string) ([]*store.OcpiParty, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+partyColumns+`
+		FROM ocpi_parties WHERE role = $1
+		ORDER BY country_code, party_id`, role)
+	if err != nil {
+		return nil, fmt.Errorf("list parties for role %s: %w", role, err)
+	}
+	parties, err := scanParties(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list parties for role %s: %w", role, err)
+	}
+	return parties, nil
+}
+
+func (s *Store) ListPartyDetails(ctx context.Context) ([]*store.OcpiParty, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+partyColumns+`
+		FROM ocpi_parties
+		ORDER BY role, country_code, party_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list parties: %w", err)
+	}
+	parties, err := scanParties(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list parties: %w", err)
+	}
+	return parties, nil
+}
+
+func (s *Store) DeletePartyDetails(ctx context.Context, role, countryCode, partyId string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM ocpi_parties WHERE role = $1 AND country_code = $2 AND party_id = $3",
+		role, countryCode, partyId)
+	if err != nil {
+		return fmt.Errorf("delete party %s/%s:%s: %w", role, countryCode, partyId, err)
+	}
+	return nil
+}
+
+func scanParties(rows pgx.Rows) ([]*store.OcpiParty, error) {
+	defer rows.Close()
+	parties := make([]*store.OcpiParty, 0)
+	for rows.Next() {
+		party, err := scanParty(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map ocpiParty: %w", err)
+		}
+		parties = append(parties, party)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+func scanParty(row pgx.Row) (*store.OcpiParty, error) {
+	var party store.OcpiParty
+	var versions, endpoints []byte
+	var tokensSyncedAt *time.Time
+	err := row.Scan(&party.Role, &party.CountryCode, &party.PartyId, &party.Url, &party.Token, &versions, &endpoints, &tokensSyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tokensSyncedAt != nil {
+		utc := tokensSyncedAt.UTC()
+		party.TokensSyncedAt = &utc
+	}
+	if err = json.Unmarshal(versions, &party.Versions); err != nil {
+		return nil, fmt.Errorf("unmarshal versions: %w", err)
+	}
+	if len(party.Versions) == 0 {
+		party.Versions = nil
+	}
+	if err = json.Unmarshal(endpoints, &party.Endpoints); err != nil {
+		return nil, fmt.Errorf("unmarshal endpoints: %w", err)
+	}
+	if len(party.Endpoints) == 0 {
+		party.Endpoints = nil
+	}
+	return &party, nil
+}
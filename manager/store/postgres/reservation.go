@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Store) SetReservation(ctx context.Context, reservation *store.Reservation) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO reservations (country_code, party_id, id, charge_station_id, ocpp_reservation_id, expiry_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (country_code, party_id, id) DO UPDATE SET
+			charge_station_id = EXCLUDED.charge_station_id,
+			ocpp_reservation_id = EXCLUDED.ocpp_reservation_id,
+			expiry_date = EXCLUDED.expiry_date`,
+		reservation.CountryCode, reservation.PartyId, reservation.Id, reservation.ChargeStationId,
+		reservation.OcppReservationId, reservation.ExpiryDate)
+	if err != nil {
+		return fmt.Errorf("set reservation %s/%s/%s: %w", reservation.CountryCode, reservation.PartyId, reservation.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupReservation(ctx context.Context, countryCode, partyId, id string) (*store.Reservation, error) {
+	reservation := store.Reservation{
+		CountryCode: countryCode,
+		PartyId:     partyId,
+		Id:          id,
+	}
+	err := s.pool.QueryRow(ctx, `
+		SELECT charge_station_id, ocpp_reservation_id, expiry_date FROM reservations
+		WHERE country_code = $1 AND party_id = $2 AND id = $3`, countryCode, partyId, id).
+		Scan(&reservation.ChargeStationId, &reservation.OcppReservationId, &reservation.ExpiryDate)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup reservation %s/%s/%s: %w", countryCode, partyId, id, err)
+	}
+	reservation.ExpiryDate = reservation.ExpiryDate.UTC()
+	return &reservation, nil
+}
+
+func (s *Store) DeleteReservation(ctx context.Context, countryCode, partyId, id string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM reservations WHERE country_code = $1 AND party_id = $2 AND id = $3`,
+		countryCode, partyId, id)
+	if err != nil {
+		return fmt.Errorf("delete reservation %s/%s/%s: %w", countryCode, partyId, id, err)
+	}
+	return nil
+}
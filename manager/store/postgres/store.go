@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockId is the key of the advisory lock that serializes schema
+// migrations when several manager instances start at the same time.
+const migrationLockId = 7356174101
+
+type Store struct {
+	pool  *pgxpool.Pool
+	clock clock.PassiveClock
+}
+
+// NewStore connects to the PostgreSQL database identified by the connection
+// string and applies any outstanding schema migrations.
+func NewStore(ctx context.Context, connectionString string, clock clock.PassiveClock) (store.Engine, error) {
+	pool, err := pgxpool.New(ctx, connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("create postgres connection pool: %w", err)
+	}
+
+	err = migrate(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("migrate postgres schema: %w", err)
+	}
+
+	return &Store{
+		pool:  pool,
+		clock: clock,
+	}, nil
+}
+
+// Close releases all the connections held by the store.
+func (s *Store) Close() {
+	s.pool.Close()
+}
+
+type migration struct {
+	version int
+	name    string
+}
+
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockId)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockId)
+		if err != nil {
+			slog.Warn("release migration lock", "err", err)
+		}
+	}()
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	var current int
+	err = conn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	pending, err := listMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if m.version <= current {
+			continue
+		}
+		sql, err := fs.ReadFile(migrations, "migrations/"+m.name)
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", m.name, err)
+		}
+
+		slog.Info("applying postgres migration", "version", m.version, "name", m.name)
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("apply migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// listMigrations returns the embedded migrations ordered by version. Migration
+// files are named <version>_<description>.sql.
+func listMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	var result []migration
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s: %w", entry.Name(), err)
+		}
+		result = append(result, migration{version: version, name: entry.Name()})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].version < result[j].version
+	})
+
+	return result, nil
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store/postgres"
+	"k8s.io/utils/clock"
+)
+
+func TestNewStore(t *testing.T) {
+	store, err := postgres.NewStore(context.Background(), connectionString, clock.RealClock{})
+	require.NoError(t, err)
+	assert.NotNil(t, store)
+}
+
+func TestNewStoreIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := postgres.NewStore(ctx, connectionString, clock.RealClock{})
+	require.NoError(t, err)
+
+	store, err := postgres.NewStore(ctx, connectionString, clock.RealClock{})
+	require.NoError(t, err)
+	assert.NotNil(t, store)
+}
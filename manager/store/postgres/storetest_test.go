@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/postgres"
+	"github.com/zynka-tech/zynka-csms/manager/store/storetest"
+	"k8s.io/utils/clock"
+)
+
+func TestStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, clock clock.PassiveClock) store.Engine {
+		engine, err := postgres.NewStore(context.Background(), connectionString, clock)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			cleanupAllTables(t)
+			engine.(*postgres.Store).Close()
+		})
+		return engine
+	})
+}
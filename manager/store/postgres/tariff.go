@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+// tariffIntervalCondition selects the tariffs last updated in the interval given
+// by the first two query parameters: a NULL parameter leaves the interval open.
+const tariffIntervalCondition = `($1::TIMESTAMPTZ IS NULL OR last_updated >= $1) AND ($2::TIMESTAMPTZ IS NULL OR last_updated < $2)`
+
+func (s *Store) SetTariff(ctx context.Context, tariff *store.Tariff) error {
+	data, err := json.Marshal(tariff)
+	if err != nil {
+		return fmt.Errorf("marshal tariff %s: %w", tariff.Id, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO tariffs (id, last_updated, tariff)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET last_updated = EXCLUDED.last_updated, tariff = EXCLUDED.tariff`,
+		tariff.Id, tariff.LastUpdated, data)
+	if err != nil {
+		return fmt.Errorf("set tariff %s: %w", tariff.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupTariff(ctx context.Context, id string) (*store.Tariff, error) {
+	var data []byte
+	err := s.pool.QueryRow(ctx, `SELECT tariff FROM tariffs WHERE id = $1`, id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup tariff %s: %w", id, err)
+	}
+	var tariff store.Tariff
+	if err = json.Unmarshal(data, &tariff); err != nil {
+		return nil, fmt.Errorf("unmarshal tariff %s: %w", id, err)
+	}
+	return &tariff, nil
+}
+
+func (s *Store) ListTariffs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Tariff, int, error) {
+	var total int
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM tariffs WHERE `+tariffIntervalCondition, dateFrom, dateTo).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count tariffs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT tariff FROM tariffs WHERE `+tariffIntervalCondition+`
+		ORDER BY last_updated, id OFFSET $3 LIMIT $4`, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list tariffs: %w", err)
+	}
+	defer rows.Close()
+	tariffs := make([]*store.Tariff, 0)
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, 0, fmt.Errorf("map tariff: %w", err)
+		}
+		var tariff store.Tariff
+		if err = json.Unmarshal(data, &tariff); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal tariff: %w", err)
+		}
+		tariffs = append(tariffs, &tariff)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list tariffs: %w", err)
+	}
+	return tariffs, total, nil
+}
+
+func (s *Store) DeleteTariff(ctx context.Context, id string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM tariffs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete tariff %s: %w", id, err)
+	}
+	return nil
+}
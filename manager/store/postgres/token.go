@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+func (s *Store) SetToken(ctx context.Context, tok *store.Token) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO tokens (uid, country_code, party_id, type, contract_id, visual_number, issuer, group_id, valid, language_code, cache_mode, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (uid) DO UPDATE SET
+			country_code = EXCLUDED.country_code,
+			party_id = EXCLUDED.party_id,
+			type = EXCLUDED.type,
+			contract_id = EXCLUDED.contract_id,
+			visual_number = EXCLUDED.visual_number,
+			issuer = EXCLUDED.issuer,
+			group_id = EXCLUDED.group_id,
+			valid = EXCLUDED.valid,
+			language_code = EXCLUDED.language_code,
+			cache_mode = EXCLUDED.cache_mode,
+			last_updated = EXCLUDED.last_updated`,
+		tok.Uid, tok.CountryCode, tok.PartyId, tok.Type, tok.ContractId, tok.VisualNumber, tok.Issuer,
+		tok.GroupId, tok.Valid, tok.LanguageCode, tok.CacheMode, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("setting token: %s: %w", tok.Uid, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupToken(ctx context.Context, tokenUid string) (*store.Token, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT uid, country_code, party_id, type, contract_id, visual_number, issuer, group_id, valid, language_code, cache_mode, last_updated
+		FROM tokens WHERE uid = $1`, tokenUid)
+	tok, err := scanToken(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup token %s: %w", tokenUid, err)
+	}
+	return tok, nil
+}
+
+func (s *Store) ListTokens(context context.Context, offset int, limit int) ([]*store.Token, error) {
+	rows, err := s.pool.Query(context, `
+		SELECT uid, country_code, party_id, type, contract_id, visual_number, issuer, group_id, valid, language_code, cache_mode, last_updated
+		FROM tokens ORDER BY uid OFFSET $1 LIMIT $2`, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	defer rows.Close()
+	tokens := make([]*store.Token, 0)
+	for rows.Next() {
+		tok, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map token: %w", err)
+		}
+		tokens = append(tokens, tok)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func scanToken(row pgx.Row) (*store.Token, error) {
+	var tok store.Token
+	var lastUpdated time.Time
+	err := row.Scan(&tok.Uid, &tok.CountryCode, &tok.PartyId, &tok.Type, &tok.ContractId, &tok.VisualNumber,
+		&tok.Issuer, &tok.GroupId, &tok.Valid, &tok.LanguageCode, &tok.CacheMode, &lastUpdated)
+	if err != nil {
+		return nil, err
+	}
+	tok.LastUpdated = lastUpdated.UTC().Format(time.RFC3339)
+	return &tok, nil
+}
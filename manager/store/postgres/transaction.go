@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+)
+
+const transactionColumns = `charge_station_id, transaction_id, evse_id, id_token, token_type, meter_values,
+	start_seq_no, ended_seq_no, updated_seq_no_count, offline, last_updated`
+
+func (s *Store) CreateTransaction(ctx context.Context, chargeStationId, transactionId string, evseId int, idToken, tokenType string, meterValue []store.MeterValue, seqNo int, offline bool) error {
+	meterValues, err := marshalMeterValues(meterValue)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO transactions (charge_station_id, transaction_id, evse_id, id_token, token_type, meter_values, start_seq_no, offline, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (charge_station_id, transaction_id) DO UPDATE SET
+			evse_id = CASE WHEN EXCLUDED.evse_id <> 0 THEN EXCLUDED.evse_id ELSE transactions.evse_id END,
+			id_token = EXCLUDED.id_token,
+			token_type = EXCLUDED.token_type,
+			meter_values = transactions.meter_values || EXCLUDED.meter_values,
+			start_seq_no = EXCLUDED.start_seq_no,
+			offline = EXCLUDED.offline,
+			last_updated = EXCLUDED.last_updated`,
+		chargeStationId, transactionId, evseId, idToken, tokenType, meterValues, seqNo, offline, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("create transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func (s *Store) FindTransaction(ctx context.Context, chargeStationId, transactionId string) (*store.Transaction, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+transactionColumns+`
+		FROM transactions WHERE charge_station_id = $1 AND transaction_id = $2`, chargeStationId, transactionId)
+	transaction, err := scanTransaction(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return transaction, nil
+}
+
+func (s *Store) FindOngoingTransaction(ctx context.Context, chargeStationId string, evseId int) (*store.Transaction, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+transactionColumns+`
+		FROM transactions WHERE charge_station_id = $1 AND evse_id = $2 AND ended_seq_no = 0
+		ORDER BY last_updated DESC LIMIT 1`, chargeStationId, evseId)
+	transaction, err := scanTransaction(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup ongoing transaction %s/%d: %w", chargeStationId, evseId, err)
+	}
+	return transaction, nil
+}
+
+func (s *Store) Transactions(ctx context.Context) ([]*store.Transaction, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+transactionColumns+`
+		FROM transactions ORDER BY charge_station_id, transaction_id`)
+	if err != nil {
+		return nil, fmt.Errorf("getting transactions: %w", err)
+	}
+	defer rows.Close()
+	transactions := make([]*store.Transaction, 0)
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("map transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func (s *Store) ListTransactions(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*store.Transaction, int, error) {
+	var total int
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM transactions WHERE `+cdrIntervalCondition, dateFrom, dateTo).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count transactions: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT `+transactionColumns+`
+		FROM transactions WHERE `+cdrIntervalCondition+`
+		ORDER BY last_updated, charge_station_id, transaction_id OFFSET $3 LIMIT $4`, dateFrom, dateTo, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list transactions: %w", err)
+	}
+	defer rows.Close()
+	transactions := make([]*store.Transaction, 0)
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("map transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list transactions: %w", err)
+	}
+	return transactions, total, nil
+}
+
+func (s *Store) UpdateTransaction(ctx context.Context, chargeStationId, transactionId string, meterValue []store.MeterValue) error {
+	meterValues, err := marshalMeterValues(meterValue)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO transactions (charge_station_id, transaction_id, meter_values, updated_seq_no_count, last_updated)
+		VALUES ($1, $2, $3, 1, $4)
+		ON CONFLICT (charge_station_id, transaction_id) DO UPDATE SET
+			meter_values = transactions.meter_values || EXCLUDED.meter_values,
+			updated_seq_no_count = transactions.updated_seq_no_count + 1,
+			last_updated = EXCLUDED.last_updated`,
+		chargeStationId, transactionId, meterValues, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("update transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func (s *Store) EndTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []store.MeterValue, seqNo int) error {
+	meterValues, err := marshalMeterValues(meterValue)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO transactions (charge_station_id, transaction_id, id_token, token_type, meter_values, ended_seq_no, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (charge_station_id, transaction_id) DO UPDATE SET
+			meter_values = transactions.meter_values || EXCLUDED.meter_values,
+			ended_seq_no = EXCLUDED.ended_seq_no,
+			last_updated = EXCLUDED.last_updated`,
+		chargeStationId, transactionId, idToken, tokenType, meterValues, seqNo, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("end transaction %s/%s: %w", chargeStationId, transactionId, err)
+	}
+	return nil
+}
+
+func marshalMeterValues(meterValues []store.MeterValue) ([]byte, error) {
+	if meterValues == nil {
+		meterValues = []store.MeterValue{}
+	}
+	data, err := json.Marshal(meterValues)
+	if err != nil {
+		return nil, fmt.Errorf("marshal meter values: %w", err)
+	}
+	return data, nil
+}
+
+func scanTransaction(row pgx.Row) (*store.Transaction, error) {
+	var transaction store.Transaction
+	var meterValues []byte
+	err := row.Scan(&transaction.ChargeStationId, &transaction.TransactionId, &transaction.EvseId, &transaction.IdToken, &transaction.TokenType,
+		&meterValues, &transaction.StartSeqNo, &transaction.EndedSeqNo, &transaction.UpdatedSeqNoCount, &transaction.Offline,
+		&transaction.LastUpdated)
+	if err != nil {
+		return nil, err
+	}
+	transaction.LastUpdated = transaction.LastUpdated.UTC()
+	if err = json.Unmarshal(meterValues, &transaction.MeterValues); err != nil {
+		return nil, fmt.Errorf("unmarshal meter values: %w", err)
+	}
+	if len(transaction.MeterValues) == 0 {
+		transaction.MeterValues = nil
+	}
+	return &transaction, nil
+}
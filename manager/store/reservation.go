@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Reservation is an EVSE reservation made by an eMSP with an OCPI RESERVE_NOW
+// command. The reservation is identified by the eMSP's country code, party id
+// and reservation id. The charge station identifies the reservation by the
+// OcppReservationId.
+type Reservation struct {
+	CountryCode       string
+	PartyId           string
+	Id                string
+	ChargeStationId   string
+	OcppReservationId int
+	ExpiryDate        time.Time
+}
+
+type ReservationStore interface {
+	SetReservation(ctx context.Context, reservation *Reservation) error
+	LookupReservation(ctx context.Context, countryCode, partyId, id string) (*Reservation, error)
+	DeleteReservation(ctx context.Context, countryCode, partyId, id string) error
+}
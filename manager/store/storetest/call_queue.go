@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunCallQueueTests checks the store.CallQueueStore behaviour.
+func RunCallQueueTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		lastAttemptAt := now.Add(time.Minute)
+		want := &store.QueuedCall{
+			ChargeStationId: "cs001",
+			MessageId:       "msg-1",
+			OcppVersion:     "ocpp1.6",
+			Action:          "Reset",
+			RequestPayload:  `{"type":"Soft"}`,
+			Priority:        10,
+			State:           store.QueuedCallStateInFlight,
+			Attempts:        1,
+			QueuedAt:        now,
+			ExpiresAt:       now.Add(time.Hour),
+			LastAttemptAt:   &lastAttemptAt,
+		}
+		err := engine.SetQueuedCall(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup missing call", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		got, err := engine.LookupQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set replaces existing call", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		call := &store.QueuedCall{
+			ChargeStationId: "cs001",
+			MessageId:       "msg-1",
+			OcppVersion:     "ocpp1.6",
+			Action:          "Reset",
+			RequestPayload:  `{"type":"Soft"}`,
+			State:           store.QueuedCallStatePending,
+			QueuedAt:        now,
+			ExpiresAt:       now.Add(time.Hour),
+		}
+		err := engine.SetQueuedCall(ctx, call)
+		require.NoError(t, err)
+
+		lastAttemptAt := now.Add(time.Minute)
+		call.State = store.QueuedCallStateInFlight
+		call.Attempts = 1
+		call.LastAttemptAt = &lastAttemptAt
+		err = engine.SetQueuedCall(ctx, call)
+		require.NoError(t, err)
+
+		got, err := engine.LookupQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+		assert.Equal(t, call, got)
+	})
+
+	t.Run("list in delivery order", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		calls := []*store.QueuedCall{
+			{ChargeStationId: "cs001", MessageId: "msg-1", Priority: 0, QueuedAt: now},
+			{ChargeStationId: "cs001", MessageId: "msg-2", Priority: 10, QueuedAt: now.Add(2 * time.Second)},
+			{ChargeStationId: "cs001", MessageId: "msg-3", Priority: 10, QueuedAt: now.Add(time.Second)},
+			{ChargeStationId: "cs001", MessageId: "msg-4", Priority: 0, QueuedAt: now},
+			{ChargeStationId: "cs002", MessageId: "msg-5", Priority: 20, QueuedAt: now},
+		}
+		for _, call := range calls {
+			call.OcppVersion = "ocpp2.0.1"
+			call.Action = "TriggerMessage"
+			call.RequestPayload = `{"requestedMessage":"Heartbeat"}`
+			call.State = store.QueuedCallStatePending
+			call.ExpiresAt = now.Add(time.Hour)
+			err := engine.SetQueuedCall(ctx, call)
+			require.NoError(t, err)
+		}
+
+		got, err := engine.ListQueuedCalls(ctx, "cs001")
+		require.NoError(t, err)
+		var gotIds []string
+		for _, call := range got {
+			gotIds = append(gotIds, call.MessageId)
+		}
+		assert.Equal(t, []string{"msg-3", "msg-2", "msg-1", "msg-4"}, gotIds)
+
+		got, err = engine.ListQueuedCalls(ctx, "cs003")
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		for _, messageId := range []string{"msg-1", "msg-2"} {
+			err := engine.SetQueuedCall(ctx, &store.QueuedCall{
+				ChargeStationId: "cs001",
+				MessageId:       messageId,
+				OcppVersion:     "ocpp1.6",
+				Action:          "ClearCache",
+				RequestPayload:  `{}`,
+				State:           store.QueuedCallStatePending,
+				QueuedAt:        now,
+				ExpiresAt:       now.Add(time.Hour),
+			})
+			require.NoError(t, err)
+		}
+
+		err := engine.DeleteQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+		// deleting a call that is not queued is not an error
+		err = engine.DeleteQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+
+		got, err := engine.LookupQueuedCall(ctx, "cs001", "msg-1")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		list, err := engine.ListQueuedCalls(ctx, "cs001")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "msg-2", list[0].MessageId)
+
+		err = engine.DeleteQueuedCall(ctx, "cs001", "msg-2")
+		require.NoError(t, err)
+		err = engine.DeleteQueuedCall(ctx, "cs002", "msg-1")
+		require.NoError(t, err)
+	})
+}
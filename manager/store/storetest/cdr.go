@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+func newCdr(id string, lastUpdated time.Time) *store.Cdr {
+	return &store.Cdr{
+		Id:              id,
+		CountryCode:     "GB",
+		PartyId:         "TWK",
+		ChargeStationId: "cs001",
+		TransactionId:   "tx-" + id,
+		StartDateTime:   lastUpdated.Add(-time.Hour),
+		EndDateTime:     lastUpdated,
+		AuthMethod:      "WHITELIST",
+		Token: store.CdrToken{
+			Uid:        "DEADBEEF",
+			Type:       "RFID",
+			ContractId: "GBTWK012345678V",
+		},
+		Location: store.CdrLocation{
+			Id:          "loc001",
+			Address:     "F.Rooseveltlaan 3A",
+			City:        "Gent",
+			PostalCode:  "9000",
+			Country:     "BEL",
+			Coordinates: store.GeoLocation{Latitude: "51.047599", Longitude: "3.729944"},
+			EvseUid:     "GBTWKEcs001",
+			EvseId:      "GB*TWK*Ecs001",
+			ConnectorId: "1",
+		},
+		Currency: "EUR",
+		ChargingPeriods: []store.CdrChargingPeriod{
+			{
+				StartDateTime: lastUpdated.Add(-time.Hour),
+				Dimensions:    []store.CdrDimension{{Type: "ENERGY", Volume: 7.5}},
+			},
+		},
+		SignedData: &store.CdrSignedData{
+			EncodingMethod: "OCMF",
+			SignedValues: []store.CdrSignedValue{
+				{Nature: "Start", SignedData: "OCMF|{}|{}"},
+			},
+		},
+		TotalCost:        4.125,
+		TotalCostInclVat: 4.99,
+		TotalEnergy:      7.5,
+		TotalTime:        1,
+		LastUpdated:      lastUpdated,
+	}
+}
+
+// RunCdrTests checks the store.CdrStore behaviour.
+func RunCdrTests(t *testing.T, factory EngineFactory) {
+	lastUpdated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("create and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.CreateCdr(ctx, newCdr("cdr001", lastUpdated))
+		require.NoError(t, err)
+
+		got, err := engine.LookupCdr(ctx, "cdr001")
+		require.NoError(t, err)
+		assert.Equal(t, newCdr("cdr001", lastUpdated), got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupCdr(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("create does not replace", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.CreateCdr(ctx, newCdr("cdr001", lastUpdated))
+		require.NoError(t, err)
+		replacement := newCdr("cdr001", lastUpdated.Add(time.Hour))
+		replacement.TotalCost = 100
+		err = engine.CreateCdr(ctx, replacement)
+		require.NoError(t, err)
+
+		got, err := engine.LookupCdr(ctx, "cdr001")
+		require.NoError(t, err)
+		assert.Equal(t, newCdr("cdr001", lastUpdated), got)
+
+		cdrs, total, err := engine.ListCdrs(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, cdrs, 1)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for i, id := range []string{"cdr003", "cdr001", "cdr002", "cdr004"} {
+			err := engine.CreateCdr(ctx, newCdr(id, lastUpdated.Add(time.Duration(i)*time.Hour)))
+			require.NoError(t, err)
+		}
+
+		ids := func(cdrs []*store.Cdr) []string {
+			var ids []string
+			for _, cdr := range cdrs {
+				ids = append(ids, cdr.Id)
+			}
+			return ids
+		}
+
+		got, total, err := engine.ListCdrs(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Equal(t, []string{"cdr003", "cdr001", "cdr002", "cdr004"}, ids(got))
+
+		got, total, err = engine.ListCdrs(ctx, nil, nil, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Equal(t, []string{"cdr001", "cdr002"}, ids(got))
+
+		dateFrom := lastUpdated.Add(time.Hour)
+		dateTo := lastUpdated.Add(3 * time.Hour)
+		got, total, err = engine.ListCdrs(ctx, &dateFrom, &dateTo, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"cdr001", "cdr002"}, ids(got))
+
+		got, total, err = engine.ListCdrs(ctx, &dateTo, nil, 5, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/clock"
+)
+
+// RunCertificateTests checks the store.CertificateStore behaviour.
+func RunCertificateTests(t *testing.T, factory EngineFactory) {
+	t.Run("set, lookup and delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		cert := generateCertificate(t)
+		pemCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+		err := engine.SetCertificate(ctx, pemCertificate)
+		require.NoError(t, err)
+
+		hash := sha256.Sum256(cert.Raw)
+		b64Hash := base64.RawURLEncoding.EncodeToString(hash[:])
+
+		got, err := engine.LookupCertificate(ctx, b64Hash)
+		require.NoError(t, err)
+		assert.Equal(t, pemCertificate, got)
+
+		err = engine.DeleteCertificate(ctx, b64Hash)
+		require.NoError(t, err)
+
+		got, err = engine.LookupCertificate(ctx, b64Hash)
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("set rejects invalid pem", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetCertificate(context.Background(), "not a certificate")
+		assert.Error(t, err)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupCertificate(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("delete unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.DeleteCertificate(context.Background(), "unknown")
+		assert.NoError(t, err)
+	})
+}
+
+func generateCertificate(t *testing.T) *x509.Certificate {
+	keyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	notBefore := time.Now()
+	notAfter := notBefore.Add(24 * time.Hour)
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Zynka-tech"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &keyPair.PublicKey, keyPair)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(derBytes)
+	require.NoError(t, err)
+
+	return cert
+}
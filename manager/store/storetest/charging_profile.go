@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunChargeStationChargingProfilesTests checks the store.ChargeStationChargingProfilesStore behaviour.
+func RunChargeStationChargingProfilesTests(t *testing.T, factory EngineFactory) {
+	t.Run("update and lookup new profiles", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := []*store.ChargingProfile{
+			newChargingProfile(1, store.ChargingProfileStatusPending, now),
+			newRecurringChargingProfile(2, now),
+		}
+		err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+			ChargingProfiles: want,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "cs001", got.ChargeStationId)
+		assert.Equal(t, want, got.ChargingProfiles)
+	})
+
+	t.Run("update merges with existing profiles", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{
+				newChargingProfile(3, store.ChargingProfileStatusPending, now),
+				newChargingProfile(1, store.ChargingProfileStatusPending, now),
+			},
+		})
+		require.NoError(t, err)
+
+		later := now.Add(time.Minute)
+		err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{
+				newChargingProfile(3, store.ChargingProfileStatusAccepted, later),
+				newChargingProfile(2, store.ChargingProfileStatusPending, later),
+			},
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, []*store.ChargingProfile{
+			newChargingProfile(1, store.ChargingProfileStatusPending, now),
+			newChargingProfile(2, store.ChargingProfileStatusPending, later),
+			newChargingProfile(3, store.ChargingProfileStatusAccepted, later),
+		}, got.ChargingProfiles)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationChargingProfiles(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+			ChargingProfiles: []*store.ChargingProfile{
+				newChargingProfile(1, store.ChargingProfileStatusAccepted, now),
+				newChargingProfile(2, store.ChargingProfileStatusAccepted, now),
+			},
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteChargeStationChargingProfile(ctx, "cs001", 1)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, []*store.ChargingProfile{
+			newChargingProfile(2, store.ChargingProfileStatusAccepted, now),
+		}, got.ChargingProfiles)
+
+		err = engine.DeleteChargeStationChargingProfile(ctx, "cs001", 2)
+		require.NoError(t, err)
+
+		got, err = engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.DeleteChargeStationChargingProfile(context.Background(), "not-created", 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("list returns pages", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		for i := 0; i < 25; i++ {
+			err := engine.UpdateChargeStationChargingProfiles(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationChargingProfiles{
+				ChargingProfiles: []*store.ChargingProfile{
+					newChargingProfile(1, store.ChargingProfileStatusPending, now),
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		var csIds []string
+		previousCsId := ""
+		for _, wantLen := range []int{10, 10, 5, 0} {
+			page, err := engine.ListChargeStationChargingProfiles(ctx, 10, previousCsId)
+			require.NoError(t, err)
+			require.Len(t, page, wantLen)
+			for _, got := range page {
+				csIds = append(csIds, got.ChargeStationId)
+				assert.Equal(t, []*store.ChargingProfile{
+					newChargingProfile(1, store.ChargingProfileStatusPending, now),
+				}, got.ChargingProfiles)
+			}
+			if len(page) > 0 {
+				previousCsId = page[len(page)-1].ChargeStationId
+			}
+		}
+
+		assert.Equal(t, expectedChargeStationIds(0, 25), csIds)
+	})
+
+	t.Run("set and lookup composite schedule", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := &store.CompositeSchedule{
+			ConnectorId:   1,
+			ScheduleStart: &now,
+			ChargingSchedule: &store.ChargingSchedule{
+				Duration:         makePtr(3600),
+				ChargingRateUnit: store.ChargingRateUnitW,
+				ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+					{StartPeriod: 0, Limit: 11000},
+					{StartPeriod: 1800, Limit: 7400, NumberPhases: makePtr(1)},
+				},
+			},
+			RetrievedAt: now,
+		}
+		err := engine.SetChargeStationCompositeSchedule(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		later := now.Add(time.Minute)
+		replacement := &store.CompositeSchedule{
+			ConnectorId: 1,
+			RetrievedAt: later,
+		}
+		err = engine.SetChargeStationCompositeSchedule(ctx, "cs001", replacement)
+		require.NoError(t, err)
+
+		got, err = engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 1)
+		require.NoError(t, err)
+		assert.Equal(t, replacement, got)
+	})
+
+	t.Run("lookup unknown composite schedule", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationCompositeSchedule(ctx, "cs001", &store.CompositeSchedule{
+			ConnectorId: 1,
+			RetrievedAt: now,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationCompositeSchedule(ctx, "cs001", 2)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func newChargingProfile(id int, status store.ChargingProfileStatus, sendAfter time.Time) *store.ChargingProfile {
+	return &store.ChargingProfile{
+		ChargingProfileId:      id,
+		ConnectorId:            0,
+		StackLevel:             id,
+		ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+		ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+		ChargingSchedule: store.ChargingSchedule{
+			ChargingRateUnit: store.ChargingRateUnitA,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 16},
+			},
+		},
+		Status:    status,
+		SendAfter: sendAfter,
+	}
+}
+
+func newRecurringChargingProfile(id int, now time.Time) *store.ChargingProfile {
+	validTo := now.Add(30 * 24 * time.Hour)
+	return &store.ChargingProfile{
+		ChargingProfileId:      id,
+		ConnectorId:            1,
+		TransactionId:          makePtr("1234"),
+		StackLevel:             2,
+		ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+		ChargingProfileKind:    store.ChargingProfileKindRecurring,
+		RecurrencyKind:         makePtr(store.RecurrencyKindDaily),
+		ValidFrom:              &now,
+		ValidTo:                &validTo,
+		ChargingSchedule: store.ChargingSchedule{
+			Duration:         makePtr(86400),
+			StartSchedule:    &now,
+			ChargingRateUnit: store.ChargingRateUnitW,
+			ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 11000, NumberPhases: makePtr(3)},
+				{StartPeriod: 28800, Limit: 3700.5, NumberPhases: makePtr(1)},
+			},
+			MinChargingRate: makePtr(1400.0),
+		},
+		Status:       store.ChargingProfileStatusAccepted,
+		SendAfter:    now,
+		LoadBalanced: true,
+	}
+}
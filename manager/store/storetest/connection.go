@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunChargeStationConnectionTests checks the store.ChargeStationConnectionStore behaviour.
+func RunChargeStationConnectionTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			Connected:       true,
+			GatewayId:       "gateway-1",
+			ConnectionId:    "0123456789abcdef",
+			Protocol:        "ocpp2.0.1",
+			RemoteAddr:      "192.0.2.1:54321",
+			SecurityProfile: store.TLSWithClientSideCertificates,
+			CertificateHash: "h5jJmf6mAEAVo1tTWNM7ebbC2Ka8Z2b2jKSWrl1jS5g",
+			ConnectedAt:     now,
+			LastSeen:        now.Add(time.Minute),
+		}
+		err := engine.SetChargeStationConnection(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set replaces existing connection", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+			Connected:    true,
+			GatewayId:    "gateway-1",
+			ConnectionId: "0123456789abcdef",
+			Protocol:     "ocpp1.6",
+			ConnectedAt:  now,
+			LastSeen:     now,
+		})
+		require.NoError(t, err)
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			Connected:       false,
+			GatewayId:       "gateway-1",
+			ConnectionId:    "0123456789abcdef",
+			Protocol:        "ocpp1.6",
+			ConnectedAt:     now,
+			DisconnectedAt:  makePtr(now.Add(time.Hour)),
+			LastSeen:        now.Add(time.Minute),
+		}
+		err = engine.SetChargeStationConnection(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		got, err := engine.LookupChargeStationConnection(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set last seen updates existing connection", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+			Connected:    true,
+			GatewayId:    "gateway-1",
+			ConnectionId: "0123456789abcdef",
+			Protocol:     "ocpp1.6",
+			ConnectedAt:  now,
+			LastSeen:     now,
+		})
+		require.NoError(t, err)
+
+		err = engine.SetChargeStationLastSeen(ctx, "cs001", now.Add(5*time.Minute))
+		require.NoError(t, err)
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			Connected:       true,
+			GatewayId:       "gateway-1",
+			ConnectionId:    "0123456789abcdef",
+			Protocol:        "ocpp1.6",
+			ConnectedAt:     now,
+			LastSeen:        now.Add(5 * time.Minute),
+		}
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set last seen creates connection", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationLastSeen(ctx, "cs001", now)
+		require.NoError(t, err)
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			LastSeen:        now,
+		}
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set disconnected updates current connection", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+			Connected:    true,
+			GatewayId:    "gateway-1",
+			ConnectionId: "0123456789abcdef",
+			Protocol:     "ocpp1.6",
+			ConnectedAt:  now,
+			LastSeen:     now,
+		})
+		require.NoError(t, err)
+
+		disconnected, err := engine.SetChargeStationDisconnected(ctx, "cs001", "0123456789abcdef", now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, disconnected)
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			Connected:       false,
+			GatewayId:       "gateway-1",
+			ConnectionId:    "0123456789abcdef",
+			Protocol:        "ocpp1.6",
+			ConnectedAt:     now,
+			DisconnectedAt:  makePtr(now.Add(time.Hour)),
+			LastSeen:        now.Add(time.Hour),
+		}
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set disconnected ignores previous connection", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := &store.ChargeStationConnection{
+			ChargeStationId: "cs001",
+			Connected:       true,
+			GatewayId:       "gateway-2",
+			ConnectionId:    "fedcba9876543210",
+			Protocol:        "ocpp1.6",
+			ConnectedAt:     now,
+			LastSeen:        now,
+		}
+		err := engine.SetChargeStationConnection(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		disconnected, err := engine.SetChargeStationDisconnected(ctx, "cs001", "0123456789abcdef", now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.False(t, disconnected)
+
+		got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		disconnected, err = engine.SetChargeStationDisconnected(ctx, "cs002", "0123456789abcdef", now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.False(t, disconnected)
+
+		got, err = engine.LookupChargeStationConnection(ctx, "cs002")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list with no connections", func(t *testing.T) {
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		got, err := engine.ListChargeStationConnections(context.Background(), 0, 10)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Len(t, got, 0)
+	})
+
+	t.Run("list returns connections ordered by charge station id", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		// insert out of order to check that the results are sorted
+		for _, i := range []int{7, 3, 0, 5, 1, 9, 2, 6, 4, 8} {
+			err := engine.SetChargeStationLastSeen(ctx, fmt.Sprintf("cs%03d", i), now)
+			require.NoError(t, err)
+		}
+
+		got, err := engine.ListChargeStationConnections(ctx, 0, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cs000", "cs001", "cs002", "cs003", "cs004"}, connectionChargeStationIds(got))
+
+		got, err = engine.ListChargeStationConnections(ctx, 8, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cs008", "cs009"}, connectionChargeStationIds(got))
+	})
+}
+
+func connectionChargeStationIds(connections []*store.ChargeStationConnection) []string {
+	ids := make([]string, len(connections))
+	for i, connection := range connections {
+		ids[i] = connection.ChargeStationId
+	}
+	return ids
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunChargeStationConnectorStatusTests checks the store.ChargeStationConnectorStatusStore behaviour.
+func RunChargeStationConnectorStatusTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := &store.ConnectorStatus{
+			EvseId:          1,
+			ConnectorId:     1,
+			Status:          "Faulted",
+			ErrorCode:       makePtr("GroundFailure"),
+			Info:            makePtr("ground fault detected"),
+			VendorId:        makePtr("vendor"),
+			VendorErrorCode: makePtr("E42"),
+			Timestamp:       now,
+		}
+		err := engine.SetChargeStationConnectorStatus(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set replaces existing status", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{
+			EvseId:      1,
+			ConnectorId: 1,
+			Status:      "Faulted",
+			ErrorCode:   makePtr("GroundFailure"),
+			Timestamp:   now,
+		})
+		require.NoError(t, err)
+
+		want := &store.ConnectorStatus{
+			EvseId:      1,
+			ConnectorId: 1,
+			Status:      "Available",
+			Timestamp:   now.Add(time.Minute),
+		}
+		err = engine.SetChargeStationConnectorStatus(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{
+			EvseId:      1,
+			ConnectorId: 1,
+			Status:      "Available",
+			Timestamp:   now,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationConnectorStatus(ctx, "cs001", 1, 2)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = engine.LookupChargeStationConnectorStatus(ctx, "not-created", 1, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list ordered by evse and connector", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		for _, key := range [][2]int{{2, 1}, {1, 2}, {0, 0}, {1, 1}} {
+			err := engine.SetChargeStationConnectorStatus(ctx, "cs001", &store.ConnectorStatus{
+				EvseId:      key[0],
+				ConnectorId: key[1],
+				Status:      "Available",
+				Timestamp:   now,
+			})
+			require.NoError(t, err)
+		}
+		err := engine.SetChargeStationConnectorStatus(ctx, "cs002", &store.ConnectorStatus{
+			EvseId:      1,
+			ConnectorId: 1,
+			Status:      "Occupied",
+			Timestamp:   now,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.ListChargeStationConnectorStatuses(ctx, "cs001")
+		require.NoError(t, err)
+		var keys [][2]int
+		for _, status := range got {
+			assert.Equal(t, "Available", status.Status)
+			keys = append(keys, [2]int{status.EvseId, status.ConnectorId})
+		}
+		assert.Equal(t, [][2]int{{0, 0}, {1, 1}, {1, 2}, {2, 1}}, keys)
+	})
+
+	t.Run("list unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.ListChargeStationConnectorStatuses(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,523 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunChargeStationAuthTests checks the store.ChargeStationAuthStore behaviour.
+func RunChargeStationAuthTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		want := &store.ChargeStationAuth{
+			SecurityProfile:        store.TLSWithClientSideCertificates,
+			Base64SHA256Password:   "DEADBEEF",
+			InvalidUsernameAllowed: true,
+		}
+
+		err := engine.SetChargeStationAuth(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationAuth(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("set replaces existing", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetChargeStationAuth(ctx, "cs001", &store.ChargeStationAuth{
+			SecurityProfile:      store.UnsecuredTransportWithBasicAuth,
+			Base64SHA256Password: "DEADBEEF",
+		})
+		require.NoError(t, err)
+
+		want := &store.ChargeStationAuth{
+			SecurityProfile: store.TLSWithClientSideCertificates,
+		}
+		err = engine.SetChargeStationAuth(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationAuth(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationAuth(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+// RunChargeStationSettingsTests checks the store.ChargeStationSettingsStore behaviour.
+func RunChargeStationSettingsTests(t *testing.T, factory EngineFactory) {
+	t.Run("update and lookup new settings", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := &store.ChargeStationSettings{
+			ChargeStationId: "cs001",
+			Settings: map[string]*store.ChargeStationSetting{
+				"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+				"baz": {Value: "qux", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+			},
+		}
+
+		err := engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+			Settings: map[string]*store.ChargeStationSetting{
+				"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+				"baz": {Value: "qux", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+			},
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationSettings(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("update merges with existing settings", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+			Settings: map[string]*store.ChargeStationSetting{
+				"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+				"baz": {Value: "qux", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+			},
+		})
+		require.NoError(t, err)
+
+		later := now.Add(time.Minute)
+		err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+			Settings: map[string]*store.ChargeStationSetting{
+				"baz": {Value: "quux", Status: store.ChargeStationSettingStatusRebootRequired, SendAfter: later},
+			},
+		})
+		require.NoError(t, err)
+
+		want := map[string]*store.ChargeStationSetting{
+			"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+			"baz": {Value: "quux", Status: store.ChargeStationSettingStatusRebootRequired, SendAfter: later},
+		}
+
+		got, err := engine.LookupChargeStationSettings(ctx, "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "cs001", got.ChargeStationId)
+		assert.Equal(t, want, got.Settings)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationSettings(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+			Settings: map[string]*store.ChargeStationSetting{
+				"foo": {Value: "bar", Status: store.ChargeStationSettingStatusAccepted},
+			},
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteChargeStationSettings(ctx, "cs001")
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationSettings(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list returns pages", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := map[string]*store.ChargeStationSetting{
+			"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+		}
+		for i := 0; i < 25; i++ {
+			err := engine.UpdateChargeStationSettings(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationSettings{
+				Settings: map[string]*store.ChargeStationSetting{
+					"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: now},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		var csIds []string
+		previousCsId := ""
+		for _, wantLen := range []int{10, 10, 5, 0} {
+			page, err := engine.ListChargeStationSettings(ctx, 10, previousCsId)
+			require.NoError(t, err)
+			require.Len(t, page, wantLen)
+			for _, got := range page {
+				csIds = append(csIds, got.ChargeStationId)
+				assert.Equal(t, want, got.Settings)
+			}
+			if len(page) > 0 {
+				previousCsId = page[len(page)-1].ChargeStationId
+			}
+		}
+
+		assert.Equal(t, expectedChargeStationIds(0, 25), csIds)
+	})
+
+	t.Run("list continues after unknown previous charge station", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for i := 0; i < 10; i++ {
+			err := engine.UpdateChargeStationSettings(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationSettings{
+				Settings: map[string]*store.ChargeStationSetting{
+					"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		page, err := engine.ListChargeStationSettings(ctx, 3, "cs004a")
+		require.NoError(t, err)
+		assert.Equal(t, expectedChargeStationIds(5, 8), settingsChargeStationIds(page))
+	})
+}
+
+// RunChargeStationInstallCertificatesTests checks the store.ChargeStationInstallCertificatesStore behaviour.
+func RunChargeStationInstallCertificatesTests(t *testing.T, factory EngineFactory) {
+	t.Run("update merges with existing certificates", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.UpdateChargeStationInstallCertificates(ctx, "cs001", &store.ChargeStationInstallCertificates{
+			Certificates: []*store.ChargeStationInstallCertificate{
+				{
+					CertificateType:               store.CertificateTypeChargeStation,
+					CertificateId:                 "csms001",
+					CertificateData:               "csms-pem-data",
+					CertificateInstallationStatus: store.CertificateInstallationPending,
+					SendAfter:                     now,
+				},
+				{
+					CertificateType:               store.CertificateTypeV2G,
+					CertificateId:                 "v2g001",
+					CertificateData:               "v2g-pem-data",
+					CertificateInstallationStatus: store.CertificateInstallationAccepted,
+					SendAfter:                     now,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		later := now.Add(time.Minute)
+		err = engine.UpdateChargeStationInstallCertificates(ctx, "cs001", &store.ChargeStationInstallCertificates{
+			Certificates: []*store.ChargeStationInstallCertificate{
+				{
+					CertificateType:               store.CertificateTypeChargeStation,
+					CertificateId:                 "csms001",
+					CertificateData:               "csms-pem-data",
+					CertificateInstallationStatus: store.CertificateInstallationRejected,
+					SendAfter:                     later,
+				},
+				{
+					CertificateType:               store.CertificateTypeEVCC,
+					CertificateId:                 "evcc001",
+					CertificateData:               "evcc-pem-data",
+					CertificateInstallationStatus: store.CertificateInstallationPending,
+					SendAfter:                     later,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationInstallCertificates(ctx, "cs001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "cs001", got.ChargeStationId)
+		assert.ElementsMatch(t, []*store.ChargeStationInstallCertificate{
+			{
+				CertificateType:               store.CertificateTypeChargeStation,
+				CertificateId:                 "csms001",
+				CertificateData:               "csms-pem-data",
+				CertificateInstallationStatus: store.CertificateInstallationRejected,
+				SendAfter:                     later,
+			},
+			{
+				CertificateType:               store.CertificateTypeV2G,
+				CertificateId:                 "v2g001",
+				CertificateData:               "v2g-pem-data",
+				CertificateInstallationStatus: store.CertificateInstallationAccepted,
+				SendAfter:                     now,
+			},
+			{
+				CertificateType:               store.CertificateTypeEVCC,
+				CertificateId:                 "evcc001",
+				CertificateData:               "evcc-pem-data",
+				CertificateInstallationStatus: store.CertificateInstallationPending,
+				SendAfter:                     later,
+			},
+		}, got.Certificates)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationInstallCertificates(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list returns pages", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		want := []*store.ChargeStationInstallCertificate{
+			{
+				CertificateType:               store.CertificateTypeV2G,
+				CertificateId:                 "v2g001",
+				CertificateData:               "v2g-pem-data",
+				CertificateInstallationStatus: store.CertificateInstallationPending,
+				SendAfter:                     now,
+			},
+		}
+		for i := 0; i < 25; i++ {
+			err := engine.UpdateChargeStationInstallCertificates(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationInstallCertificates{
+				Certificates: []*store.ChargeStationInstallCertificate{
+					{
+						CertificateType:               store.CertificateTypeV2G,
+						CertificateId:                 "v2g001",
+						CertificateData:               "v2g-pem-data",
+						CertificateInstallationStatus: store.CertificateInstallationPending,
+						SendAfter:                     now,
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		var csIds []string
+		previousCsId := ""
+		for _, wantLen := range []int{10, 10, 5, 0} {
+			page, err := engine.ListChargeStationInstallCertificates(ctx, 10, previousCsId)
+			require.NoError(t, err)
+			require.Len(t, page, wantLen)
+			for _, got := range page {
+				csIds = append(csIds, got.ChargeStationId)
+				assert.Equal(t, want, got.Certificates)
+			}
+			if len(page) > 0 {
+				previousCsId = page[len(page)-1].ChargeStationId
+			}
+		}
+
+		assert.Equal(t, expectedChargeStationIds(0, 25), csIds)
+	})
+
+	t.Run("list continues after unknown previous charge station", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for i := 0; i < 10; i++ {
+			err := engine.UpdateChargeStationInstallCertificates(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationInstallCertificates{
+				Certificates: []*store.ChargeStationInstallCertificate{
+					{
+						CertificateType:               store.CertificateTypeV2G,
+						CertificateId:                 "v2g001",
+						CertificateData:               "v2g-pem-data",
+						CertificateInstallationStatus: store.CertificateInstallationPending,
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		page, err := engine.ListChargeStationInstallCertificates(ctx, 3, "cs004a")
+		require.NoError(t, err)
+		var csIds []string
+		for _, certs := range page {
+			csIds = append(csIds, certs.ChargeStationId)
+		}
+		assert.Equal(t, expectedChargeStationIds(5, 8), csIds)
+	})
+}
+
+// RunChargeStationRuntimeDetailsTests checks the store.ChargeStationRuntimeDetailsStore behaviour.
+func RunChargeStationRuntimeDetailsTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		want := &store.ChargeStationRuntimeDetails{
+			OcppVersion: "1.6",
+		}
+
+		err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationRuntimeDetails(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationRuntimeDetails(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+// RunChargeStationTriggerMessageTests checks the store.ChargeStationTriggerMessageStore behaviour.
+func RunChargeStationTriggerMessageTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.SetChargeStationTriggerMessage(ctx, "cs001", &store.ChargeStationTriggerMessage{
+			TriggerMessage: store.TriggerMessageBootNotification,
+			TriggerStatus:  store.TriggerStatusPending,
+			SendAfter:      now,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationTriggerMessage(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, &store.ChargeStationTriggerMessage{
+			ChargeStationId: "cs001",
+			TriggerMessage:  store.TriggerMessageBootNotification,
+			TriggerStatus:   store.TriggerStatusPending,
+			SendAfter:       now,
+		}, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupChargeStationTriggerMessage(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetChargeStationTriggerMessage(ctx, "cs001", &store.ChargeStationTriggerMessage{
+			TriggerMessage: store.TriggerMessageHeartbeat,
+			TriggerStatus:  store.TriggerStatusPending,
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteChargeStationTriggerMessage(ctx, "cs001")
+		require.NoError(t, err)
+
+		got, err := engine.LookupChargeStationTriggerMessage(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list returns pages", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		for i := 0; i < 25; i++ {
+			err := engine.SetChargeStationTriggerMessage(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationTriggerMessage{
+				TriggerMessage: store.TriggerMessageStatusNotification,
+				TriggerStatus:  store.TriggerStatusPending,
+				SendAfter:      now,
+			})
+			require.NoError(t, err)
+		}
+
+		var csIds []string
+		previousCsId := ""
+		for _, wantLen := range []int{10, 10, 5, 0} {
+			page, err := engine.ListChargeStationTriggerMessages(ctx, 10, previousCsId)
+			require.NoError(t, err)
+			require.Len(t, page, wantLen)
+			for _, got := range page {
+				csIds = append(csIds, got.ChargeStationId)
+				assert.Equal(t, store.TriggerMessageStatusNotification, got.TriggerMessage)
+				assert.Equal(t, store.TriggerStatusPending, got.TriggerStatus)
+				assert.Equal(t, now, got.SendAfter)
+			}
+			if len(page) > 0 {
+				previousCsId = page[len(page)-1].ChargeStationId
+			}
+		}
+
+		assert.Equal(t, expectedChargeStationIds(0, 25), csIds)
+	})
+
+	t.Run("list continues after unknown previous charge station", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for i := 0; i < 10; i++ {
+			err := engine.SetChargeStationTriggerMessage(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationTriggerMessage{
+				TriggerMessage: store.TriggerMessageStatusNotification,
+				TriggerStatus:  store.TriggerStatusPending,
+			})
+			require.NoError(t, err)
+		}
+
+		page, err := engine.ListChargeStationTriggerMessages(ctx, 3, "cs004a")
+		require.NoError(t, err)
+		var csIds []string
+		for _, msg := range page {
+			csIds = append(csIds, msg.ChargeStationId)
+		}
+		assert.Equal(t, expectedChargeStationIds(5, 8), csIds)
+	})
+}
+
+// fixedTime returns a time with a precision that all stores are able to
+// represent without loss.
+func fixedTime() time.Time {
+	return time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+}
+
+func expectedChargeStationIds(from, to int) []string {
+	var csIds []string
+	for i := from; i < to; i++ {
+		csIds = append(csIds, fmt.Sprintf("cs%03d", i))
+	}
+	return csIds
+}
+
+func settingsChargeStationIds(settings []*store.ChargeStationSettings) []string {
+	var csIds []string
+	for _, s := range settings {
+		csIds = append(csIds, s.ChargeStationId)
+	}
+	return csIds
+}
@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storetest provides a behavioural test suite that can be run against
+// any implementation of store.Engine to check that it honours the semantics
+// expected by the rest of the manager.
+package storetest
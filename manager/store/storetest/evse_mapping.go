@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// RunEvseMappingTests checks the store.EvseMappingStore behaviour.
+func RunEvseMappingTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		evseId := "GB*TWK*E12345*1"
+		want := &store.EvseMapping{
+			LocationId:          "loc001",
+			EvseUid:             "evse001",
+			EvseId:              &evseId,
+			ChargeStationId:     "cs001",
+			ChargeStationEvseId: 1,
+			Connectors: []store.ConnectorMapping{
+				{Id: "A", ConnectorId: 1},
+				{Id: "B", ConnectorId: 2},
+			},
+		}
+		err := engine.SetEvseMapping(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupEvseMapping(ctx, "loc001", "evse001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		got, err = engine.LookupEvseMappingByEvseId(ctx, evseId)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		got, err = engine.LookupEvseMapping(ctx, "loc002", "evse001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = engine.LookupEvseMappingByEvseId(ctx, "GB*TWK*E12345*2")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		oldEvseId := "GB*TWK*E12345*1"
+		err := engine.SetEvseMapping(ctx, &store.EvseMapping{
+			LocationId:      "loc001",
+			EvseUid:         "evse001",
+			EvseId:          &oldEvseId,
+			ChargeStationId: "cs001",
+		})
+		require.NoError(t, err)
+
+		want := &store.EvseMapping{
+			LocationId:          "loc001",
+			EvseUid:             "evse001",
+			ChargeStationId:     "cs002",
+			ChargeStationEvseId: 2,
+		}
+		err = engine.SetEvseMapping(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupEvseMapping(ctx, "loc001", "evse001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		got, err = engine.LookupEvseMappingByEvseId(ctx, oldEvseId)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list for charge station", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for _, mapping := range []*store.EvseMapping{
+			{LocationId: "loc002", EvseUid: "evse001", ChargeStationId: "cs001", ChargeStationEvseId: 2},
+			{LocationId: "loc001", EvseUid: "evse002", ChargeStationId: "cs002", ChargeStationEvseId: 1},
+			{LocationId: "loc001", EvseUid: "evse001", ChargeStationId: "cs001", ChargeStationEvseId: 1},
+		} {
+			err := engine.SetEvseMapping(ctx, mapping)
+			require.NoError(t, err)
+		}
+
+		got, err := engine.ListEvseMappingsForChargeStation(ctx, "cs001")
+		require.NoError(t, err)
+		want := []*store.EvseMapping{
+			{LocationId: "loc001", EvseUid: "evse001", ChargeStationId: "cs001", ChargeStationEvseId: 1},
+			{LocationId: "loc002", EvseUid: "evse001", ChargeStationId: "cs001", ChargeStationEvseId: 2},
+		}
+		assert.Equal(t, want, got)
+
+		got, err = engine.ListEvseMappingsForChargeStation(ctx, "cs003")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		evseId := "GB*TWK*E12345*1"
+		err := engine.SetEvseMapping(ctx, &store.EvseMapping{
+			LocationId:      "loc001",
+			EvseUid:         "evse001",
+			EvseId:          &evseId,
+			ChargeStationId: "cs001",
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteEvseMapping(ctx, "loc001", "evse001")
+		require.NoError(t, err)
+
+		got, err := engine.LookupEvseMapping(ctx, "loc001", "evse001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = engine.LookupEvseMappingByEvseId(ctx, evseId)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+// RunLocationTests checks the store.LocationStore behaviour.
+func RunLocationTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetLocation(ctx, newLocation("loc001"))
+		require.NoError(t, err)
+
+		got, err := engine.LookupLocation(ctx, "loc001")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Regexp(t, lastUpdatedPattern, got.LastUpdated)
+		got.LastUpdated = ""
+		assert.Equal(t, newLocation("loc001"), got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupLocation(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list with no locations", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, total, err := engine.ListLocations(context.Background(), nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.NotNil(t, got)
+		assert.Len(t, got, 0)
+	})
+
+	t.Run("list returns locations ordered by id", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for _, i := range []int{4, 0, 8, 2, 6, 1, 9, 3, 7, 5} {
+			err := engine.SetLocation(ctx, newLocation(fmt.Sprintf("loc%03d", i)))
+			require.NoError(t, err)
+		}
+
+		got, total, err := engine.ListLocations(ctx, nil, nil, 3, 5)
+		require.NoError(t, err)
+		assert.Equal(t, 10, total)
+		var ids []string
+		for _, loc := range got {
+			assert.Regexp(t, lastUpdatedPattern, loc.LastUpdated)
+			ids = append(ids, loc.Id)
+		}
+		assert.Equal(t, []string{"loc003", "loc004", "loc005", "loc006", "loc007"}, ids)
+	})
+
+	t.Run("list locations last updated in interval", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+		clk := clockTest.NewFakePassiveClock(now)
+		engine := factory(t, clk)
+
+		for i, id := range []string{"loc003", "loc001", "loc002"} {
+			clk.SetTime(now.Add(time.Duration(i) * time.Hour))
+			err := engine.SetLocation(ctx, newLocation(id))
+			require.NoError(t, err)
+		}
+
+		dateFrom := now.Add(time.Hour)
+		got, total, err := engine.ListLocations(ctx, &dateFrom, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, got, 2)
+		assert.Equal(t, "loc001", got[0].Id)
+		assert.Equal(t, "loc002", got[1].Id)
+
+		got, total, err = engine.ListLocations(ctx, nil, &dateFrom, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, got, 1)
+		assert.Equal(t, "loc003", got[0].Id)
+	})
+
+	t.Run("set and lookup client owned location", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		want := newLocation("loc001")
+		want.CountryCode = "NL"
+		want.PartyId = "ABC"
+		want.LastUpdated = "2024-03-15T10:30:00Z"
+		err := engine.SetClientOwnedLocation(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupClientOwnedLocation(ctx, "NL", "ABC", "loc001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		got, err = engine.LookupClientOwnedLocation(ctx, "NL", "XYZ", "loc001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = engine.LookupLocation(ctx, "loc001")
+		require.NoError(t, err)
+		assert.Nil(t, got, "client owned locations must not be mixed with the CPO's locations")
+	})
+}
+
+func newLocation(id string) *store.Location {
+	return &store.Location{
+		Address: "F.Rooseveltlaan 3A",
+		City:    "Gent",
+		Coordinates: store.GeoLocation{
+			Latitude:  "51.047599",
+			Longitude: "3.729944",
+		},
+		Country:     "BEL",
+		CountryCode: "BE",
+		Evses: &[]store.Evse{
+			{
+				ChargeStationId:     "cs" + id,
+				ChargeStationEvseId: 1,
+				Connectors: []store.Connector{
+					{
+						Format:      "SOCKET",
+						Id:          "1",
+						MaxAmperage: 32,
+						MaxVoltage:  230,
+						PowerType:   "AC_3_PHASE",
+						Standard:    "IEC_62196_T2",
+						LastUpdated: "2024-03-15T10:30:00Z",
+					},
+				},
+				EvseId:      makePtr("BE*ZYN*E" + id),
+				Status:      "AVAILABLE",
+				Uid:         id + "E1",
+				LastUpdated: "2024-03-15T10:30:00Z",
+			},
+		},
+		Id:          id,
+		Name:        "Gent Zuid",
+		ParkingType: "ON_STREET",
+		PartyId:     "ZYN",
+		PostalCode:  "9000",
+	}
+}
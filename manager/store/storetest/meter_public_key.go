@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// RunChargeStationMeterPublicKeyTests checks the store.ChargeStationMeterPublicKeyStore behaviour.
+func RunChargeStationMeterPublicKeyTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and list", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 2, PublicKey: "beef"})
+		require.NoError(t, err)
+		err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 1, PublicKey: "dead"})
+		require.NoError(t, err)
+		err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 2, PublicKey: "cafe"})
+		require.NoError(t, err)
+		err = engine.SetChargeStationMeterPublicKey(ctx, "cs002", &store.MeterPublicKey{EvseId: 1, PublicKey: "f00d"})
+		require.NoError(t, err)
+
+		got, err := engine.ListChargeStationMeterPublicKeys(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, []*store.MeterPublicKey{
+			{EvseId: 1, PublicKey: "dead"},
+			{EvseId: 2, PublicKey: "cafe"},
+		}, got)
+	})
+
+	t.Run("list unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.ListChargeStationMeterPublicKeys(context.Background(), "not-created")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 1, PublicKey: "dead"})
+		require.NoError(t, err)
+		err = engine.SetChargeStationMeterPublicKey(ctx, "cs001", &store.MeterPublicKey{EvseId: 2, PublicKey: "beef"})
+		require.NoError(t, err)
+
+		err = engine.DeleteChargeStationMeterPublicKey(ctx, "cs001", 1)
+		require.NoError(t, err)
+
+		got, err := engine.ListChargeStationMeterPublicKeys(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Equal(t, []*store.MeterPublicKey{{EvseId: 2, PublicKey: "beef"}}, got)
+
+		err = engine.DeleteChargeStationMeterPublicKey(ctx, "cs001", 2)
+		require.NoError(t, err)
+
+		got, err = engine.ListChargeStationMeterPublicKeys(ctx, "cs001")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("delete unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.DeleteChargeStationMeterPublicKey(context.Background(), "not-created", 1)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+func newMeterReading(connectorId int, timestamp time.Time, value float64) *store.MeterReading {
+	return &store.MeterReading{
+		ConnectorId: connectorId,
+		Timestamp:   timestamp,
+		SampledValues: []store.SampledValue{
+			{
+				Measurand: makePtr("Energy.Active.Import.Register"),
+				UnitOfMeasure: &store.UnitOfMeasure{
+					Unit: "Wh",
+				},
+				Value: value,
+			},
+		},
+	}
+}
+
+// RunChargeStationMeterReadingTests checks the store.ChargeStationMeterReadingStore behaviour.
+func RunChargeStationMeterReadingTests(t *testing.T, factory EngineFactory) {
+	t.Run("add and list", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.AddChargeStationMeterReadings(ctx, "cs001", []*store.MeterReading{
+			newMeterReading(1, now.Add(time.Minute), 200),
+			newMeterReading(0, now, 1000),
+		})
+		require.NoError(t, err)
+		err = engine.AddChargeStationMeterReadings(ctx, "cs001", []*store.MeterReading{
+			newMeterReading(0, now.Add(time.Minute), 1100),
+		})
+		require.NoError(t, err)
+		err = engine.AddChargeStationMeterReadings(ctx, "cs002", []*store.MeterReading{
+			newMeterReading(0, now, 5000),
+		})
+		require.NoError(t, err)
+
+		got, err := engine.ListChargeStationMeterReadings(ctx, "cs001", now, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []*store.MeterReading{
+			newMeterReading(0, now, 1000),
+			newMeterReading(0, now.Add(time.Minute), 1100),
+			newMeterReading(1, now.Add(time.Minute), 200),
+		}, got)
+	})
+
+	t.Run("list within interval", func(t *testing.T) {
+		ctx := context.Background()
+		now := fixedTime()
+		engine := factory(t, clockTest.NewFakePassiveClock(now))
+
+		err := engine.AddChargeStationMeterReadings(ctx, "cs001", []*store.MeterReading{
+			newMeterReading(0, now.Add(-time.Minute), 900),
+			newMeterReading(0, now, 1000),
+			newMeterReading(0, now.Add(time.Minute), 1100),
+			newMeterReading(0, now.Add(2*time.Minute), 1200),
+		})
+		require.NoError(t, err)
+
+		got, err := engine.ListChargeStationMeterReadings(ctx, "cs001", now, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, []*store.MeterReading{
+			newMeterReading(0, now, 1000),
+			newMeterReading(0, now.Add(time.Minute), 1100),
+		}, got)
+	})
+
+	t.Run("list unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		now := time.Now()
+		got, err := engine.ListChargeStationMeterReadings(context.Background(), "not-created", now.Add(-time.Hour), now)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// RunOcpiTests checks the store.OcpiStore behaviour.
+func RunOcpiTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and get registration", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetRegistrationDetails(ctx, "abcdef123456", &store.OcpiRegistration{
+			Status: store.OcpiRegistrationStatusPending,
+		})
+		require.NoError(t, err)
+		err = engine.SetRegistrationDetails(ctx, "abcdef123456", &store.OcpiRegistration{
+			Status: store.OcpiRegistrationStatusRegistered,
+		})
+		require.NoError(t, err)
+
+		got, err := engine.GetRegistrationDetails(ctx, "abcdef123456")
+		require.NoError(t, err)
+		assert.Equal(t, &store.OcpiRegistration{Status: store.OcpiRegistrationStatusRegistered}, got)
+	})
+
+	t.Run("get unknown registration", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.GetRegistrationDetails(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete registration", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetRegistrationDetails(ctx, "abcdef123456", &store.OcpiRegistration{
+			Status: store.OcpiRegistrationStatusRegistered,
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteRegistrationDetails(ctx, "abcdef123456")
+		require.NoError(t, err)
+
+		got, err := engine.GetRegistrationDetails(ctx, "abcdef123456")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("delete registrations for url", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for token, url := range map[string]string{
+			"abcdef123456": "https://example.com/ocpi/versions",
+			"ghijkl123456": "https://example.com/ocpi/versions",
+			"mnopqr123456": "https://example.org/ocpi/versions",
+		} {
+			err := engine.SetRegistrationDetails(ctx, token, &store.OcpiRegistration{
+				Status: store.OcpiRegistrationStatusRegistered,
+				Url:    url,
+			})
+			require.NoError(t, err)
+		}
+
+		err := engine.DeleteRegistrationDetailsForUrl(ctx, "https://example.com/ocpi/versions")
+		require.NoError(t, err)
+
+		for _, token := range []string{"abcdef123456", "ghijkl123456"} {
+			got, err := engine.GetRegistrationDetails(ctx, token)
+			require.NoError(t, err)
+			assert.Nil(t, got)
+		}
+		got, err := engine.GetRegistrationDetails(ctx, "mnopqr123456")
+		require.NoError(t, err)
+		assert.Equal(t, &store.OcpiRegistration{
+			Status: store.OcpiRegistrationStatusRegistered,
+			Url:    "https://example.org/ocpi/versions",
+		}, got)
+	})
+
+	t.Run("set and get party", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetPartyDetails(ctx, newParty("CPO", "GB", "TWK", "abcdef"))
+		require.NoError(t, err)
+		err = engine.SetPartyDetails(ctx, newParty("CPO", "GB", "TWK", "123456"))
+		require.NoError(t, err)
+
+		got, err := engine.GetPartyDetails(ctx, "CPO", "GB", "TWK")
+		require.NoError(t, err)
+		assert.Equal(t, newParty("CPO", "GB", "TWK", "123456"), got)
+	})
+
+	t.Run("set and get party with endpoints", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		party := newParty("EMSP", "GB", "TWK", "abcdef")
+		party.Versions = []store.OcpiVersion{
+			{Version: "2.2", Url: "https://example.com/ocpi/2.2"},
+		}
+		party.Endpoints = []store.OcpiEndpoint{
+			{Identifier: "credentials", Role: "RECEIVER", Url: "https://example.com/ocpi/2.2/credentials"},
+			{Identifier: "locations", Role: "RECEIVER", Url: "https://example.com/ocpi/receiver/2.2/locations"},
+		}
+		err := engine.SetPartyDetails(ctx, party)
+		require.NoError(t, err)
+
+		got, err := engine.GetPartyDetails(ctx, "EMSP", "GB", "TWK")
+		require.NoError(t, err)
+		assert.Equal(t, party, got)
+	})
+
+	t.Run("set and get party with tokens sync time", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		party := newParty("EMSP", "GB", "TWK", "abcdef")
+		syncedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		party.TokensSyncedAt = &syncedAt
+		err := engine.SetPartyDetails(ctx, party)
+		require.NoError(t, err)
+
+		got, err := engine.GetPartyDetails(ctx, "EMSP", "GB", "TWK")
+		require.NoError(t, err)
+		assert.Equal(t, party, got)
+	})
+
+	t.Run("delete party", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetPartyDetails(ctx, newParty("EMSP", "GB", "TWK", "abcdef"))
+		require.NoError(t, err)
+
+		err = engine.DeletePartyDetails(ctx, "EMSP", "GB", "TWK")
+		require.NoError(t, err)
+
+		got, err := engine.GetPartyDetails(ctx, "EMSP", "GB", "TWK")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("get unknown party", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.GetPartyDetails(context.Background(), "CPO", "GB", "XXX")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list parties for role", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.ListPartyDetailsForRole(ctx, "EMSP")
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Len(t, got, 0)
+
+		for _, party := range []*store.OcpiParty{
+			newParty("EMSP", "GB", "TWK", "abc"),
+			newParty("EMSP", "NL", "ABC", "def"),
+			newParty("CPO", "GB", "ZYN", "ghi"),
+		} {
+			err := engine.SetPartyDetails(ctx, party)
+			require.NoError(t, err)
+		}
+
+		got, err = engine.ListPartyDetailsForRole(ctx, "EMSP")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*store.OcpiParty{
+			newParty("EMSP", "GB", "TWK", "abc"),
+			newParty("EMSP", "NL", "ABC", "def"),
+		}, got)
+	})
+
+	t.Run("list parties", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.ListPartyDetails(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Len(t, got, 0)
+
+		for _, party := range []*store.OcpiParty{
+			newParty("EMSP", "GB", "TWK", "abc"),
+			newParty("CPO", "GB", "ZYN", "ghi"),
+		} {
+			err := engine.SetPartyDetails(ctx, party)
+			require.NoError(t, err)
+		}
+
+		got, err = engine.ListPartyDetails(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*store.OcpiParty{
+			newParty("EMSP", "GB", "TWK", "abc"),
+			newParty("CPO", "GB", "ZYN", "ghi"),
+		}, got)
+	})
+}
+
+func newParty(role, countryCode, partyId, token string) *store.OcpiParty {
+	return &store.OcpiParty{
+		Role:        role,
+		CountryCode: countryCode,
+		PartyId:     partyId,
+		Url:         "https://example.com/ocpi/versions",
+		Token:       token,
+	}
+}
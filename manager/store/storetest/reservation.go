@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// RunReservationTests checks the store.ReservationStore behaviour.
+func RunReservationTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		want := &store.Reservation{
+			CountryCode:       "GB",
+			PartyId:           "TWK",
+			Id:                "res001",
+			ChargeStationId:   "cs001",
+			OcppReservationId: 42,
+			ExpiryDate:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+		err := engine.SetReservation(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupReservation(ctx, "GB", "TWK", "res001")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		got, err = engine.LookupReservation(ctx, "NL", "TWK", "res001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		reservation := &store.Reservation{
+			CountryCode:       "GB",
+			PartyId:           "TWK",
+			Id:                "res001",
+			ChargeStationId:   "cs001",
+			OcppReservationId: 42,
+			ExpiryDate:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+		err := engine.SetReservation(ctx, reservation)
+		require.NoError(t, err)
+
+		reservation.ChargeStationId = "cs002"
+		reservation.ExpiryDate = time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+		err = engine.SetReservation(ctx, reservation)
+		require.NoError(t, err)
+
+		got, err := engine.LookupReservation(ctx, "GB", "TWK", "res001")
+		require.NoError(t, err)
+		assert.Equal(t, reservation, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetReservation(ctx, &store.Reservation{
+			CountryCode:       "GB",
+			PartyId:           "TWK",
+			Id:                "res001",
+			ChargeStationId:   "cs001",
+			OcppReservationId: 42,
+			ExpiryDate:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+
+		err = engine.DeleteReservation(ctx, "GB", "TWK", "res001")
+		require.NoError(t, err)
+
+		got, err := engine.LookupReservation(ctx, "GB", "TWK", "res001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		err = engine.DeleteReservation(ctx, "GB", "TWK", "res001")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// EngineFactory creates an empty store.Engine for a single test. The factory is
+// responsible for removing any data written by the test, typically by
+// registering a cleanup function with t.Cleanup.
+type EngineFactory func(t *testing.T, clock clock.PassiveClock) store.Engine
+
+// Run runs the full store.Engine suite against engines created by factory.
+func Run(t *testing.T, factory EngineFactory) {
+	t.Run("ChargeStationAuth", func(t *testing.T) {
+		RunChargeStationAuthTests(t, factory)
+	})
+	t.Run("ChargeStationSettings", func(t *testing.T) {
+		RunChargeStationSettingsTests(t, factory)
+	})
+	t.Run("ChargeStationInstallCertificates", func(t *testing.T) {
+		RunChargeStationInstallCertificatesTests(t, factory)
+	})
+	t.Run("ChargeStationRuntimeDetails", func(t *testing.T) {
+		RunChargeStationRuntimeDetailsTests(t, factory)
+	})
+	t.Run("ChargeStationTriggerMessages", func(t *testing.T) {
+		RunChargeStationTriggerMessageTests(t, factory)
+	})
+	t.Run("ChargeStationChargingProfiles", func(t *testing.T) {
+		RunChargeStationChargingProfilesTests(t, factory)
+	})
+	t.Run("ChargeStationConnectorStatus", func(t *testing.T) {
+		RunChargeStationConnectorStatusTests(t, factory)
+	})
+	t.Run("ChargeStationConnections", func(t *testing.T) {
+		RunChargeStationConnectionTests(t, factory)
+	})
+	t.Run("CallQueue", func(t *testing.T) {
+		RunCallQueueTests(t, factory)
+	})
+	t.Run("ChargeStationMeterReadings", func(t *testing.T) {
+		RunChargeStationMeterReadingTests(t, factory)
+	})
+	t.Run("ChargeStationMeterPublicKeys", func(t *testing.T) {
+		RunChargeStationMeterPublicKeyTests(t, factory)
+	})
+	t.Run("Tokens", func(t *testing.T) {
+		RunTokenTests(t, factory)
+	})
+	t.Run("Transactions", func(t *testing.T) {
+		RunTransactionTests(t, factory)
+	})
+	t.Run("Certificates", func(t *testing.T) {
+		RunCertificateTests(t, factory)
+	})
+	t.Run("Ocpi", func(t *testing.T) {
+		RunOcpiTests(t, factory)
+	})
+	t.Run("Locations", func(t *testing.T) {
+		RunLocationTests(t, factory)
+	})
+	t.Run("Cdrs", func(t *testing.T) {
+		RunCdrTests(t, factory)
+	})
+	t.Run("Tariffs", func(t *testing.T) {
+		RunTariffTests(t, factory)
+	})
+	t.Run("Reservations", func(t *testing.T) {
+		RunReservationTests(t, factory)
+	})
+	t.Run("EvseMappings", func(t *testing.T) {
+		RunEvseMappingTests(t, factory)
+	})
+}
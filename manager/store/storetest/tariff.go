@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+func newTariff(id string, lastUpdated time.Time) *store.Tariff {
+	vat := 21.0
+	maxKwh := 20.0
+	minDuration := 3600
+	startDateTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &store.Tariff{
+		Id:          id,
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Currency:    "EUR",
+		Type:        "REGULAR",
+		Elements: []store.TariffElement{
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "ENERGY", Price: 0.25, Vat: &vat, StepSize: 1},
+				},
+				Restrictions: &store.TariffRestrictions{
+					StartTime: "07:00",
+					EndTime:   "19:00",
+					MaxKwh:    &maxKwh,
+					DayOfWeek: []string{"MONDAY", "TUESDAY"},
+				},
+			},
+			{
+				PriceComponents: []store.PriceComponent{
+					{Type: "PARKING_TIME", Price: 2, StepSize: 300},
+				},
+				Restrictions: &store.TariffRestrictions{
+					MinDuration: &minDuration,
+				},
+			},
+		},
+		StartDateTime: &startDateTime,
+		LocationIds:   []string{"loc001"},
+		EvseUids:      []string{"GBTWKEcs001"},
+		LastUpdated:   lastUpdated,
+	}
+}
+
+// RunTariffTests checks the store.TariffStore behaviour.
+func RunTariffTests(t *testing.T, factory EngineFactory) {
+	lastUpdated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetTariff(ctx, newTariff("tariff001", lastUpdated))
+		require.NoError(t, err)
+
+		got, err := engine.LookupTariff(ctx, "tariff001")
+		require.NoError(t, err)
+		assert.Equal(t, newTariff("tariff001", lastUpdated), got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupTariff(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set replaces", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetTariff(ctx, newTariff("tariff001", lastUpdated))
+		require.NoError(t, err)
+		replacement := newTariff("tariff001", lastUpdated.Add(time.Hour))
+		replacement.Currency = "GBP"
+		err = engine.SetTariff(ctx, replacement)
+		require.NoError(t, err)
+
+		got, err := engine.LookupTariff(ctx, "tariff001")
+		require.NoError(t, err)
+		assert.Equal(t, replacement, got)
+
+		tariffs, total, err := engine.ListTariffs(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, tariffs, 1)
+		assert.Equal(t, "GBP", tariffs[0].Currency)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetTariff(ctx, newTariff("tariff001", lastUpdated))
+		require.NoError(t, err)
+		err = engine.DeleteTariff(ctx, "tariff001")
+		require.NoError(t, err)
+
+		got, err := engine.LookupTariff(ctx, "tariff001")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		tariffs, total, err := engine.ListTariffs(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, tariffs)
+	})
+
+	t.Run("delete unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.DeleteTariff(context.Background(), "unknown")
+		require.NoError(t, err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		for i, id := range []string{"tariff003", "tariff001", "tariff002", "tariff004"} {
+			err := engine.SetTariff(ctx, newTariff(id, lastUpdated.Add(time.Duration(i)*time.Hour)))
+			require.NoError(t, err)
+		}
+
+		ids := func(tariffs []*store.Tariff) []string {
+			var ids []string
+			for _, tariff := range tariffs {
+				ids = append(ids, tariff.Id)
+			}
+			return ids
+		}
+
+		got, total, err := engine.ListTariffs(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Equal(t, []string{"tariff003", "tariff001", "tariff002", "tariff004"}, ids(got))
+
+		got, total, err = engine.ListTariffs(ctx, nil, nil, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Equal(t, []string{"tariff001", "tariff002"}, ids(got))
+
+		dateFrom := lastUpdated.Add(time.Hour)
+		dateTo := lastUpdated.Add(3 * time.Hour)
+		got, total, err = engine.ListTariffs(ctx, &dateFrom, &dateTo, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"tariff001", "tariff002"}, ids(got))
+
+		got, total, err = engine.ListTariffs(ctx, &dateTo, nil, 5, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+const lastUpdatedPattern = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`
+
+// RunTokenTests checks the store.TokenStore behaviour.
+func RunTokenTests(t *testing.T, factory EngineFactory) {
+	t.Run("set and lookup", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetToken(ctx, newToken("12345678"))
+		require.NoError(t, err)
+
+		got, err := engine.LookupToken(ctx, "12345678")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Regexp(t, lastUpdatedPattern, got.LastUpdated)
+		got.LastUpdated = ""
+		assert.Equal(t, newToken("12345678"), got)
+	})
+
+	t.Run("set replaces existing", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		err := engine.SetToken(ctx, newToken("12345678"))
+		require.NoError(t, err)
+
+		want := newToken("12345678")
+		want.Valid = false
+		want.GroupId = makePtr("group-1")
+		err = engine.SetToken(ctx, want)
+		require.NoError(t, err)
+
+		got, err := engine.LookupToken(ctx, "12345678")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		got.LastUpdated = ""
+		want = newToken("12345678")
+		want.Valid = false
+		want.GroupId = makePtr("group-1")
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("lookup unknown", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.LookupToken(context.Background(), "unknown-rfid")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list with no tokens", func(t *testing.T) {
+		engine := factory(t, clock.RealClock{})
+
+		got, err := engine.ListTokens(context.Background(), 0, 10)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Len(t, got, 0)
+	})
+
+	t.Run("list returns tokens ordered by uid", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clock.RealClock{})
+
+		// insert out of order to check that the results are sorted
+		for _, i := range []int{7, 3, 19, 0, 12, 5, 1, 18, 9, 15, 2, 11, 6, 17, 4, 13, 8, 16, 10, 14} {
+			err := engine.SetToken(ctx, newToken(fmt.Sprintf("123456%02d", i)))
+			require.NoError(t, err)
+		}
+
+		got, err := engine.ListTokens(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expectedTokenUids(0, 10), tokenUids(got))
+
+		got, err = engine.ListTokens(ctx, 5, 20)
+		require.NoError(t, err)
+		assert.Equal(t, expectedTokenUids(5, 20), tokenUids(got))
+
+		for _, tok := range got {
+			assert.Regexp(t, lastUpdatedPattern, tok.LastUpdated)
+		}
+	})
+}
+
+func newToken(uid string) *store.Token {
+	return &store.Token{
+		CountryCode:  "GB",
+		PartyId:      "TWK",
+		Type:         "RFID",
+		Uid:          uid,
+		ContractId:   "GBTWK012345678V",
+		VisualNumber: makePtr("GB-TWK-012345678-V"),
+		Issuer:       "Zynka",
+		Valid:        true,
+		LanguageCode: makePtr("en"),
+		CacheMode:    store.CacheModeAllowed,
+	}
+}
+
+func expectedTokenUids(from, to int) []string {
+	var uids []string
+	for i := from; i < to; i++ {
+		uids = append(uids, fmt.Sprintf("123456%02d", i))
+	}
+	return uids
+}
+
+func tokenUids(tokens []*store.Token) []string {
+	var uids []string
+	for _, tok := range tokens {
+		uids = append(uids, tok.Uid)
+	}
+	return uids
+}
+
+func makePtr[T any](t T) *T {
+	v := t
+	return &v
+}
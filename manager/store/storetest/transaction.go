@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package storetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	clockTest "k8s.io/utils/clock/testing"
+)
+
+const (
+	idToken   = "SOMERFID"
+	tokenType = "ISO14443"
+)
+
+// RunTransactionTests checks the store.TransactionStore behaviour.
+func RunTransactionTests(t *testing.T, factory EngineFactory) {
+	t.Run("find unknown", func(t *testing.T) {
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		got, err := engine.FindTransaction(context.Background(), "cs001", "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("create and find", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, newMeterValues("2024-03-15T10:30:00Z", 100), 1, true)
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		assert.Equal(t, &store.Transaction{
+			ChargeStationId: "cs001",
+			TransactionId:   "1234",
+			IdToken:         idToken,
+			TokenType:       tokenType,
+			MeterValues:     newMeterValues("2024-03-15T10:30:00Z", 100),
+			StartSeqNo:      1,
+			Offline:         true,
+			LastUpdated:     fixedTime(),
+		}, got)
+	})
+
+	t.Run("create and find with signed meter value", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		meterValues := newMeterValues("2024-03-15T10:30:00Z", 100)
+		meterValues[0].SampledValues[0].SignedMeterValue = &store.SignedMeterValue{
+			SignedMeterData: "OCMF|{}|{}",
+			EncodingMethod:  "OCMF",
+			SigningMethod:   "ECDSA-secp256r1-SHA256",
+			Status:          store.SignedMeterValueStatusValid,
+		}
+		err := engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, meterValues, 0, false)
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, meterValues, got.MeterValues)
+	})
+
+	t.Run("update appends meter values", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, newMeterValues("2024-03-15T10:30:00Z", 100), 0, false)
+		require.NoError(t, err)
+		err = engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:31:00Z", 200))
+		require.NoError(t, err)
+		err = engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:32:00Z", 300))
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, 2, got.UpdatedSeqNoCount)
+		assert.Equal(t, concatMeterValues(
+			newMeterValues("2024-03-15T10:30:00Z", 100),
+			newMeterValues("2024-03-15T10:31:00Z", 200),
+			newMeterValues("2024-03-15T10:32:00Z", 300),
+		), got.MeterValues)
+	})
+
+	t.Run("update creates unknown transaction", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:31:00Z", 200))
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		assert.Equal(t, &store.Transaction{
+			ChargeStationId:   "cs001",
+			TransactionId:     "1234",
+			MeterValues:       newMeterValues("2024-03-15T10:31:00Z", 200),
+			UpdatedSeqNoCount: 1,
+			LastUpdated:       fixedTime(),
+		}, got)
+	})
+
+	t.Run("end records ended sequence number", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, newMeterValues("2024-03-15T10:30:00Z", 100), 0, false)
+		require.NoError(t, err)
+		err = engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:31:00Z", 200))
+		require.NoError(t, err)
+		err = engine.EndTransaction(ctx, "cs001", "1234", idToken, tokenType, newMeterValues("2024-03-15T10:32:00Z", 300), 2)
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		assert.Equal(t, &store.Transaction{
+			ChargeStationId: "cs001",
+			TransactionId:   "1234",
+			IdToken:         idToken,
+			TokenType:       tokenType,
+			MeterValues: concatMeterValues(
+				newMeterValues("2024-03-15T10:30:00Z", 100),
+				newMeterValues("2024-03-15T10:31:00Z", 200),
+				newMeterValues("2024-03-15T10:32:00Z", 300),
+			),
+			StartSeqNo:        0,
+			EndedSeqNo:        2,
+			UpdatedSeqNoCount: 1,
+			LastUpdated:       fixedTime(),
+		}, got)
+	})
+
+	t.Run("end creates unknown transaction", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.EndTransaction(ctx, "cs001", "1234", idToken, tokenType, newMeterValues("2024-03-15T10:32:00Z", 300), 3)
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		assert.Equal(t, &store.Transaction{
+			ChargeStationId: "cs001",
+			TransactionId:   "1234",
+			IdToken:         idToken,
+			TokenType:       tokenType,
+			MeterValues:     newMeterValues("2024-03-15T10:32:00Z", 300),
+			EndedSeqNo:      3,
+			LastUpdated:     fixedTime(),
+		}, got)
+	})
+
+	t.Run("create after update keeps meter values", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		err := engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:31:00Z", 200))
+		require.NoError(t, err)
+		err = engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, newMeterValues("2024-03-15T10:30:00Z", 100), 0, true)
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		assert.Equal(t, &store.Transaction{
+			ChargeStationId: "cs001",
+			TransactionId:   "1234",
+			IdToken:         idToken,
+			TokenType:       tokenType,
+			MeterValues: concatMeterValues(
+				newMeterValues("2024-03-15T10:31:00Z", 200),
+				newMeterValues("2024-03-15T10:30:00Z", 100),
+			),
+			UpdatedSeqNoCount: 1,
+			Offline:           true,
+			LastUpdated:       fixedTime(),
+		}, got)
+	})
+
+	t.Run("list all transactions", func(t *testing.T) {
+		ctx := context.Background()
+		engine := factory(t, clockTest.NewFakePassiveClock(fixedTime()))
+
+		got, err := engine.Transactions(ctx)
+		require.NoError(t, err)
+		assert.Len(t, got, 0)
+
+		err = engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+		err = engine.CreateTransaction(ctx, "cs002", "1234", 0, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+
+		got, err = engine.Transactions(ctx)
+		require.NoError(t, err)
+		var ids []string
+		for _, transaction := range got {
+			ids = append(ids, transaction.ChargeStationId+"/"+transaction.TransactionId)
+		}
+		assert.ElementsMatch(t, []string{"cs001/1234", "cs002/1234"}, ids)
+	})
+
+	t.Run("update sets last updated", func(t *testing.T) {
+		ctx := context.Background()
+		clock := clockTest.NewFakePassiveClock(fixedTime())
+		engine := factory(t, clock)
+
+		err := engine.CreateTransaction(ctx, "cs001", "1234", 0, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+		clock.SetTime(fixedTime().Add(time.Minute))
+		err = engine.UpdateTransaction(ctx, "cs001", "1234", newMeterValues("2024-03-15T10:31:00Z", 200))
+		require.NoError(t, err)
+
+		got, err := engine.FindTransaction(ctx, "cs001", "1234")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, fixedTime().Add(time.Minute), got.LastUpdated)
+
+		clock.SetTime(fixedTime().Add(2 * time.Minute))
+		err = engine.EndTransaction(ctx, "cs001", "1234", idToken, tokenType, nil, 1)
+		require.NoError(t, err)
+
+		transactions, total, err := engine.ListTransactions(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, fixedTime().Add(2*time.Minute), transactions[0].LastUpdated)
+	})
+
+	t.Run("find ongoing transaction", func(t *testing.T) {
+		ctx := context.Background()
+		clock := clockTest.NewFakePassiveClock(fixedTime())
+		engine := factory(t, clock)
+
+		err := engine.CreateTransaction(ctx, "cs001", "1", 1, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+		err = engine.EndTransaction(ctx, "cs001", "1", idToken, tokenType, nil, 1)
+		require.NoError(t, err)
+		clock.SetTime(fixedTime().Add(time.Minute))
+		err = engine.CreateTransaction(ctx, "cs001", "2", 1, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+		err = engine.CreateTransaction(ctx, "cs001", "3", 2, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+		err = engine.CreateTransaction(ctx, "cs0010", "4", 3, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+
+		got, err := engine.FindOngoingTransaction(ctx, "cs001", 1)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "2", got.TransactionId)
+		assert.Equal(t, 1, got.EvseId)
+
+		got, err = engine.FindOngoingTransaction(ctx, "cs001", 2)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "3", got.TransactionId)
+
+		got, err = engine.FindOngoingTransaction(ctx, "cs001", 3)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list transactions", func(t *testing.T) {
+		ctx := context.Background()
+		clock := clockTest.NewFakePassiveClock(fixedTime())
+		engine := factory(t, clock)
+
+		for i, id := range []string{"cs003/1", "cs001/2", "cs002/1", "cs001/1"} {
+			clock.SetTime(fixedTime().Add(time.Duration(i) * time.Hour))
+			chargeStationId, transactionId, _ := strings.Cut(id, "/")
+			err := engine.CreateTransaction(ctx, chargeStationId, transactionId, 0, idToken, tokenType, nil, 0, false)
+			require.NoError(t, err)
+		}
+		clock.SetTime(fixedTime().Add(3 * time.Hour))
+		err := engine.CreateTransaction(ctx, "cs000", "1", 0, idToken, tokenType, nil, 0, false)
+		require.NoError(t, err)
+
+		ids := func(transactions []*store.Transaction) []string {
+			var ids []string
+			for _, transaction := range transactions {
+				ids = append(ids, transaction.ChargeStationId+"/"+transaction.TransactionId)
+			}
+			return ids
+		}
+
+		got, total, err := engine.ListTransactions(ctx, nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Equal(t, []string{"cs003/1", "cs001/2", "cs002/1", "cs000/1", "cs001/1"}, ids(got))
+
+		got, total, err = engine.ListTransactions(ctx, nil, nil, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Equal(t, []string{"cs001/2", "cs002/1"}, ids(got))
+
+		dateFrom := fixedTime().Add(time.Hour)
+		dateTo := fixedTime().Add(3 * time.Hour)
+		got, total, err = engine.ListTransactions(ctx, &dateFrom, &dateTo, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"cs001/2", "cs002/1"}, ids(got))
+
+		got, total, err = engine.ListTransactions(ctx, &dateTo, nil, 5, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
+
+func newMeterValues(timestamp string, value float64) []store.MeterValue {
+	return []store.MeterValue{
+		{
+			Timestamp: timestamp,
+			SampledValues: []store.SampledValue{
+				{
+					Measurand: makePtr("Energy.Active.Import.Register"),
+					Location:  makePtr("Outlet"),
+					UnitOfMeasure: &store.UnitOfMeasure{
+						Unit:      "Wh",
+						Multipler: 1,
+					},
+					Value: value,
+				},
+			},
+		},
+	}
+}
+
+func concatMeterValues(meterValues ...[]store.MeterValue) []store.MeterValue {
+	var result []store.MeterValue
+	for _, mv := range meterValues {
+		result = append(result, mv...)
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Tariff is an OCPI tariff offered by the CPO. A tariff applies to the
+// transactions of the tokens in one of the TokenGroupIds, or else to the
+// transactions at one of the EvseUids or LocationIds. A tariff without any
+// assignments is the default tariff.
+type Tariff struct {
+	Id          string
+	CountryCode string
+	PartyId     string
+	Currency    string
+	Type        string
+	Elements    []TariffElement
+	// MinPrice and MaxPrice limit the total cost of a transaction excluding VAT
+	MinPrice *float64
+	MaxPrice *float64
+	// StartDateTime and EndDateTime bound the period in which the tariff is valid
+	StartDateTime *time.Time
+	EndDateTime   *time.Time
+	LocationIds   []string
+	EvseUids      []string
+	TokenGroupIds []string
+	LastUpdated   time.Time
+}
+
+type TariffElement struct {
+	PriceComponents []PriceComponent
+	Restrictions    *TariffRestrictions
+}
+
+// PriceComponent is the price of a dimension: ENERGY, TIME, PARKING_TIME or
+// FLAT. Energy is priced per kWh and times per hour. The StepSize is in Wh for
+// energy and in seconds for times.
+type PriceComponent struct {
+	Type     string
+	Price    float64
+	Vat      *float64
+	StepSize int
+}
+
+// TariffRestrictions limit when a tariff element applies. Times of day are in
+// the format 15:04 and dates in the format 2006-01-02. Energy is in kWh, power
+// in kW and durations in seconds.
+type TariffRestrictions struct {
+	StartTime   string
+	EndTime     string
+	StartDate   string
+	EndDate     string
+	MinKwh      *float64
+	MaxKwh      *float64
+	MinPower    *float64
+	MaxPower    *float64
+	MinDuration *int
+	MaxDuration *int
+	DayOfWeek   []string
+}
+
+type TariffStore interface {
+	SetTariff(ctx context.Context, tariff *Tariff) error
+	LookupTariff(ctx context.Context, id string) (*Tariff, error)
+	// ListTariffs returns a page of the tariffs last updated in the interval
+	// [dateFrom, dateTo) ordered by last updated and id, along with the total
+	// number of tariffs in the interval. A nil date leaves that end of the
+	// interval open.
+	ListTariffs(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*Tariff, int, error)
+	DeleteTariff(ctx context.Context, id string) error
+}
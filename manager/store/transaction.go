@@ -2,7 +2,10 @@
 
 package store
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Transaction struct {
 	ChargeStationId   string       `firestore:"chargeStationId"`
@@ -14,6 +17,12 @@ type Transaction struct {
 	EndedSeqNo        int          `firestore:"endedSeqNo"`
 	UpdatedSeqNoCount int          `firestore:"updatedSeqNoCount"`
 	Offline           bool         `firestore:"offline"`
+	// LastUpdated is set by the store when the transaction is created, updated
+	// or ended
+	LastUpdated time.Time `firestore:"lastUpdated"`
+	// EvseId is the EVSE (the connector for OCPP 1.6) that the transaction was
+	// started on: it is 0 if the EVSE is not known
+	EvseId int `firestore:"evseId"`
 }
 
 type MeterValue struct {
@@ -22,12 +31,36 @@ type MeterValue struct {
 }
 
 type SampledValue struct {
-	Context       *string        `firestore:"context"`
-	Location      *string        `firestore:"location"`
-	Measurand     *string        `firestore:"measurand"`
-	Phase         *string        `firestore:"phase"`
-	UnitOfMeasure *UnitOfMeasure `firestore:"unitOfMeasure"`
-	Value         float64        `firestore:"value"`
+	Context          *string           `firestore:"context"`
+	Location         *string           `firestore:"location"`
+	Measurand        *string           `firestore:"measurand"`
+	Phase            *string           `firestore:"phase"`
+	UnitOfMeasure    *UnitOfMeasure    `firestore:"unitOfMeasure"`
+	Value            float64           `firestore:"value"`
+	SignedMeterValue *SignedMeterValue `firestore:"signedMeterValue"`
+	// SignedDataOnly is set when the sampled value only holds signed data that
+	// could not be decoded: Value is not a reading
+	SignedDataOnly bool `firestore:"signedDataOnly"`
+}
+
+type SignedMeterValueStatus string
+
+var (
+	SignedMeterValueStatusUnverified  SignedMeterValueStatus = "Unverified"
+	SignedMeterValueStatusValid       SignedMeterValueStatus = "Valid"
+	SignedMeterValueStatusInvalid     SignedMeterValueStatus = "Invalid"
+	SignedMeterValueStatusNoPublicKey SignedMeterValueStatus = "NoPublicKey"
+	SignedMeterValueStatusUnsupported SignedMeterValueStatus = "Unsupported"
+)
+
+// SignedMeterValue is the signed data reported by the meter for a sampled value
+// along with the result of verifying the signature. SignedMeterData is stored
+// exactly as reported by the charge station.
+type SignedMeterValue struct {
+	SignedMeterData string                 `firestore:"signedMeterData"`
+	EncodingMethod  string                 `firestore:"encodingMethod"`
+	SigningMethod   string                 `firestore:"signingMethod"`
+	Status          SignedMeterValueStatus `firestore:"status"`
 }
 
 type UnitOfMeasure struct {
@@ -37,8 +70,17 @@ type UnitOfMeasure struct {
 
 type TransactionStore interface {
 	Transactions(ctx context.Context) ([]*Transaction, error)
+	// ListTransactions returns a page of the transactions last updated in the
+	// interval [dateFrom, dateTo) ordered by last updated, charge station id and
+	// transaction id, along with the total number of transactions in the
+	// interval. A nil date leaves that end of the interval open.
+	ListTransactions(ctx context.Context, dateFrom, dateTo *time.Time, offset, limit int) ([]*Transaction, int, error)
 	FindTransaction(ctx context.Context, chargeStationId, transactionId string) (*Transaction, error)
-	CreateTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []MeterValue, seqNo int, offline bool) error
+	// FindOngoingTransaction returns the transaction that has not ended on the
+	// EVSE of the charge station, or nil if there isn't one. The most recently
+	// updated transaction is returned if there is more than one.
+	FindOngoingTransaction(ctx context.Context, chargeStationId string, evseId int) (*Transaction, error)
+	CreateTransaction(ctx context.Context, chargeStationId, transactionId string, evseId int, idToken, tokenType string, meterValue []MeterValue, seqNo int, offline bool) error
 	UpdateTransaction(ctx context.Context, chargeStationId, transactionId string, meterValue []MeterValue) error
 	EndTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []MeterValue, seqNo int) error
 }
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/services"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+func SyncChargingProfiles(ctx context.Context, engine store.Engine, clock clock.PassiveClock, v16CallMaker, v201CallMaker handlers.CallMaker, runEvery, retryAfter time.Duration) {
+	var previousChargeStationId string
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync charging profiles")
+			return
+		case <-time.After(runEvery):
+			slog.Info("checking for pending charge station charging profiles")
+			chargingProfiles, err := engine.ListChargeStationChargingProfiles(ctx, 50, previousChargeStationId)
+			if err != nil {
+				slog.Error("list charge station charging profiles", slog.String("err", err.Error()))
+				continue
+			}
+			if len(chargingProfiles) > 0 {
+				previousChargeStationId = chargingProfiles[len(chargingProfiles)-1].ChargeStationId
+			} else {
+				previousChargeStationId = ""
+			}
+			pendingChargingProfiles := filterPendingChargingProfiles(chargingProfiles)
+			for _, pendingChargingProfile := range pendingChargingProfiles {
+				csId := pendingChargingProfile.ChargeStationId
+				details, err := engine.LookupChargeStationRuntimeDetails(ctx, csId)
+				if err != nil {
+					slog.Error("lookup charge station runtime details", slog.String("err", err.Error()),
+						slog.String("chargeStationId", csId))
+					continue
+				}
+				if details == nil || (details.OcppVersion != "1.6" && details.OcppVersion != "2.0.1") {
+					continue
+				}
+
+				for _, profile := range pendingChargingProfile.ChargingProfiles {
+					if !isPendingChargingProfile(profile) || !clock.Now().After(profile.SendAfter) {
+						continue
+					}
+					slog.Info("updating charge station charging profile", slog.String("chargeStationId", csId),
+						slog.Int("chargingProfileId", profile.ChargingProfileId),
+						slog.String("status", string(profile.Status)),
+						slog.String("OcppVersion", details.OcppVersion))
+					// the charge station did not respond when the profile was last sent
+					retry := !profile.SendAfter.IsZero()
+					profile.SendAfter = clock.Now().Add(retryAfter)
+					err = engine.UpdateChargeStationChargingProfiles(ctx, csId, &store.ChargeStationChargingProfiles{
+						ChargingProfiles: []*store.ChargingProfile{
+							profile,
+						},
+					})
+					if err != nil {
+						slog.Error("update charge station charging profiles", slog.String("err", err.Error()))
+						continue
+					}
+
+					callMaker := v16CallMaker
+					if details.OcppVersion == "2.0.1" {
+						callMaker = v201CallMaker
+						if retry && profile.Status == store.ChargingProfileStatusPending {
+							// ask whether the profile was installed before sending it again: the
+							// profile is accepted when it is reported and is sent again if not
+							err = callMaker.Send(ctx, csId, &ocpp201.GetChargingProfilesRequestJson{
+								RequestId: profile.ChargingProfileId,
+								ChargingProfile: ocpp201.ChargingProfileCriterionType{
+									ChargingProfileId: []int{profile.ChargingProfileId},
+								},
+							})
+							if err != nil {
+								slog.Error("send get charging profiles request", slog.String("err", err.Error()),
+									slog.String("chargeStationId", csId), slog.Int("chargingProfileId", profile.ChargingProfileId))
+							}
+							continue
+						}
+					}
+					req, err := services.NewChargingProfileRequest(details.OcppVersion, profile)
+					if err != nil {
+						// the profile can never be sent so there is no point retrying it
+						slog.Error("convert charging profile", slog.String("err", err.Error()),
+							slog.String("chargeStationId", csId), slog.Int("chargingProfileId", profile.ChargingProfileId))
+						profile.Status = store.ChargingProfileStatusRejected
+						err = engine.UpdateChargeStationChargingProfiles(ctx, csId, &store.ChargeStationChargingProfiles{
+							ChargingProfiles: []*store.ChargingProfile{
+								profile,
+							},
+						})
+						if err != nil {
+							slog.Error("update charge station charging profiles", slog.String("err", err.Error()))
+						}
+						continue
+					}
+					err = callMaker.Send(ctx, csId, req)
+					if err != nil {
+						slog.Error("send charging profile request", slog.String("err", err.Error()),
+							slog.String("chargeStationId", csId), slog.Int("chargingProfileId", profile.ChargingProfileId))
+					}
+				}
+			}
+		}
+	}
+}
+
+func isPendingChargingProfile(profile *store.ChargingProfile) bool {
+	return profile.Status == store.ChargingProfileStatusPending ||
+		profile.Status == store.ChargingProfileStatusClearPending
+}
+
+func filterPendingChargingProfiles(chargingProfiles []*store.ChargeStationChargingProfiles) []*store.ChargeStationChargingProfiles {
+	var pendingChargingProfiles []*store.ChargeStationChargingProfiles
+	for _, chargingProfile := range chargingProfiles {
+		for _, profile := range chargingProfile.ChargingProfiles {
+			if isPendingChargingProfile(profile) {
+				pendingChargingProfiles = append(pendingChargingProfiles, chargingProfile)
+				break
+			}
+		}
+	}
+	return pendingChargingProfiles
+}
@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp16"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/store"
+	"github.com/zynka-tech/zynka-csms/manager/store/inmemory"
+	"github.com/zynka-tech/zynka-csms/manager/sync"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func updateV16ChargingProfile(ctx context.Context, engine store.Engine, chargeStationId string, request ocpp.Request) error {
+	switch r := request.(type) {
+	case *ocpp16.SetChargingProfileJson:
+		profiles, err := engine.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+		if err != nil {
+			return err
+		}
+		for _, profile := range profiles.ChargingProfiles {
+			if profile.ChargingProfileId == r.CsChargingProfiles.ChargingProfileId {
+				profile.Status = store.ChargingProfileStatusAccepted
+				return engine.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+					ChargingProfiles: []*store.ChargingProfile{profile},
+				})
+			}
+		}
+	case *ocpp16.ClearChargingProfileJson:
+		return engine.DeleteChargeStationChargingProfile(ctx, chargeStationId, *r.Id)
+	}
+	return nil
+}
+
+func updateV201ChargingProfile(ctx context.Context, engine store.Engine, chargeStationId string, request ocpp.Request) error {
+	switch r := request.(type) {
+	case *ocpp201.SetChargingProfileRequestJson:
+		profiles, err := engine.LookupChargeStationChargingProfiles(ctx, chargeStationId)
+		if err != nil {
+			return err
+		}
+		for _, profile := range profiles.ChargingProfiles {
+			if profile.ChargingProfileId == r.ChargingProfile.Id {
+				profile.Status = store.ChargingProfileStatusAccepted
+				return engine.UpdateChargeStationChargingProfiles(ctx, chargeStationId, &store.ChargeStationChargingProfiles{
+					ChargingProfiles: []*store.ChargingProfile{profile},
+				})
+			}
+		}
+	case *ocpp201.ClearChargingProfileRequestJson:
+		return engine.DeleteChargeStationChargingProfile(ctx, chargeStationId, *r.ChargingProfileId)
+	}
+	return nil
+}
+
+func TestSyncV16ChargingProfiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	validFrom := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	transactionId := "42"
+	duration := 3600
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ConnectorId:            1,
+				TransactionId:          &transactionId,
+				StackLevel:             2,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				ValidFrom:              &validFrom,
+				ChargingSchedule: store.ChargingSchedule{
+					Duration:         &duration,
+					ChargingRateUnit: store.ChargingRateUnitA,
+					ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+						{StartPeriod: 0, Limit: 16},
+					},
+				},
+				Status: store.ChargingProfileStatusPending,
+			},
+			{
+				ChargingProfileId:      2,
+				ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusClearPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updateV16ChargingProfile}
+	v201CallMaker := &mockCallMaker{engine: engine}
+	sync.SyncChargingProfiles(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, 100*time.Millisecond, 500*time.Millisecond)
+
+	require.Len(t, v16CallMaker.callEvents, 2)
+	for _, event := range v16CallMaker.callEvents {
+		assert.Equal(t, "cs001", event.chargeStationId)
+	}
+
+	wantTransactionId := 42
+	wantValidFrom := "2023-06-15T15:00:00Z"
+	assert.Equal(t, &ocpp16.SetChargingProfileJson{
+		ConnectorId: 1,
+		CsChargingProfiles: ocpp16.SetChargingProfileJsonCsChargingProfiles{
+			ChargingProfileId:      1,
+			ChargingProfileKind:    ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKindAbsolute,
+			ChargingProfilePurpose: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile,
+			ChargingSchedule: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingSchedule{
+				ChargingRateUnit: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnitA,
+				ChargingSchedulePeriod: []ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+					{StartPeriod: 0, Limit: 16},
+				},
+				Duration: &duration,
+			},
+			StackLevel:    2,
+			TransactionId: &wantTransactionId,
+			ValidFrom:     &wantValidFrom,
+		},
+	}, v16CallMaker.callEvents[0].request)
+
+	wantId := 2
+	assert.Equal(t, &ocpp16.ClearChargingProfileJson{
+		Id: &wantId,
+	}, v16CallMaker.callEvents[1].request)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, profiles.ChargingProfiles, 1)
+	assert.Equal(t, store.ChargingProfileStatusAccepted, profiles.ChargingProfiles[0].Status)
+
+	assert.Len(t, v201CallMaker.callEvents, 0)
+}
+
+func TestSyncV201ChargingProfiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs002", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+
+	transactionId := "tx001"
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs002", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeChargePointMaxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				ChargingSchedule: store.ChargingSchedule{
+					ChargingRateUnit: store.ChargingRateUnitW,
+					ChargingSchedulePeriods: []store.ChargingSchedulePeriod{
+						{StartPeriod: 0, Limit: 22000},
+					},
+				},
+				Status: store.ChargingProfileStatusPending,
+			},
+			{
+				ChargingProfileId:      2,
+				ConnectorId:            1,
+				TransactionId:          &transactionId,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusClearPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &mockCallMaker{engine: engine}
+	v201CallMaker := &mockCallMaker{engine: engine, updateFn: updateV201ChargingProfile}
+	sync.SyncChargingProfiles(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, 100*time.Millisecond, 500*time.Millisecond)
+
+	assert.Len(t, v16CallMaker.callEvents, 0)
+	require.Len(t, v201CallMaker.callEvents, 2)
+
+	assert.Equal(t, &ocpp201.SetChargingProfileRequestJson{
+		EvseId: 0,
+		ChargingProfile: ocpp201.ChargingProfileType{
+			ChargingProfileKind:    ocpp201.ChargingProfileKindEnumTypeAbsolute,
+			ChargingProfilePurpose: ocpp201.ChargingProfilePurposeEnumTypeChargingStationMaxProfile,
+			ChargingSchedule: []ocpp201.ChargingScheduleType{
+				{
+					ChargingRateUnit: ocpp201.ChargingRateUnitEnumTypeW,
+					ChargingSchedulePeriod: []ocpp201.ChargingSchedulePeriodType{
+						{StartPeriod: 0, Limit: 22000},
+					},
+					Id: 1,
+				},
+			},
+			Id: 1,
+		},
+	}, v201CallMaker.callEvents[0].request)
+
+	wantId := 2
+	assert.Equal(t, &ocpp201.ClearChargingProfileRequestJson{
+		ChargingProfileId: &wantId,
+	}, v201CallMaker.callEvents[1].request)
+
+	profiles, err := engine.LookupChargeStationChargingProfiles(ctx, "cs002")
+	require.NoError(t, err)
+	require.Len(t, profiles.ChargingProfiles, 1)
+	assert.Equal(t, store.ChargingProfileStatusAccepted, profiles.ChargingProfiles[0].Status)
+}
+
+func TestSyncV16ChargingProfilesRetryAfterDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	updater := updateWithNoResponse{}
+	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updater.update}
+	sync.SyncChargingProfiles(ctx, engine, clock.RealClock{}, v16CallMaker, &mockCallMaker{}, 100*time.Millisecond, 400*time.Millisecond)
+
+	require.Equal(t, 3, len(updater.updateAttempts))
+	assert.True(t, updater.updateAttempts[1].After(updater.updateAttempts[0].Add(400*time.Millisecond)))
+	assert.True(t, updater.updateAttempts[2].After(updater.updateAttempts[1].Add(400*time.Millisecond)))
+}
+
+func TestSyncV16ChargingProfilesRejectsInvalidTransactionId(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	transactionId := "not-a-transaction"
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ConnectorId:            1,
+				TransactionId:          &transactionId,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &mockCallMaker{engine: engine}
+	sync.SyncChargingProfiles(ctx, engine, clock.RealClock{}, v16CallMaker, &mockCallMaker{}, 100*time.Millisecond, 100*time.Millisecond)
+
+	assert.Empty(t, v16CallMaker.callEvents)
+	profiles, err := engine.LookupChargeStationChargingProfiles(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.Len(t, profiles.ChargingProfiles, 1)
+	assert.Equal(t, store.ChargingProfileStatusRejected, profiles.ChargingProfiles[0].Status)
+}
+
+func TestSyncV201ChargingProfilesAsksForProfileWithNoResponse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+
+	err = engine.UpdateChargeStationChargingProfiles(ctx, "cs001", &store.ChargeStationChargingProfiles{
+		ChargingProfiles: []*store.ChargingProfile{
+			{
+				ChargingProfileId:      1,
+				ChargingProfilePurpose: store.ChargingProfilePurposeTxDefaultProfile,
+				ChargingProfileKind:    store.ChargingProfileKindAbsolute,
+				Status:                 store.ChargingProfileStatusPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	updater := updateWithNoResponse{}
+	v201CallMaker := &mockCallMaker{engine: engine, updateFn: updater.update}
+	sync.SyncChargingProfiles(ctx, engine, clock.RealClock{}, &mockCallMaker{}, v201CallMaker, 100*time.Millisecond, 400*time.Millisecond)
+
+	require.Len(t, v201CallMaker.callEvents, 2)
+	assert.IsType(t, &ocpp201.SetChargingProfileRequestJson{}, v201CallMaker.callEvents[0].request)
+	want := &ocpp201.GetChargingProfilesRequestJson{
+		RequestId: 1,
+		ChargingProfile: ocpp201.ChargingProfileCriterionType{
+			ChargingProfileId: []int{1},
+		},
+	}
+	assert.Equal(t, want, v201CallMaker.callEvents[1].request)
+}
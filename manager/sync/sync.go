@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp16"
 	"github.com/zynka-tech/zynka-csms/manager/handlers/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
 	"github.com/zynka-tech/zynka-csms/manager/store"
 	"github.com/zynka-tech/zynka-csms/manager/transport"
 	"go.opentelemetry.io/otel/trace"
@@ -13,7 +14,7 @@ import (
 	"time"
 )
 
-func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tracer, emitter transport.Emitter) {
+func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tracer, emitter transport.Emitter, ocpiApi ocpi.Api, tokensSyncInterval time.Duration) {
 	v16SyncCallMaker := ocpp16.NewCallMaker(emitter)
 	dataTransferCallMaker := ocpp16.NewDataTransferCallMaker(emitter)
 	v201SyncCallMaker := ocpp201.NewCallMaker(emitter)
@@ -32,6 +33,13 @@ func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tra
 		v201SyncCallMaker,
 		1*time.Minute,
 		2*time.Minute)
+	go SyncChargingProfiles(context.Background(),
+		storageEngine,
+		clock,
+		v16SyncCallMaker,
+		v201SyncCallMaker,
+		1*time.Minute,
+		2*time.Minute)
 	go SyncTriggers(context.Background(),
 		tracer,
 		storageEngine,
@@ -41,4 +49,9 @@ func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tra
 		v201SyncCallMaker,
 		1*time.Minute,
 		2*time.Minute)
+	if ocpiApi != nil {
+		go SyncTokens(context.Background(),
+			ocpiApi,
+			tokensSyncInterval)
+	}
 }
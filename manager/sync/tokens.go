@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/manager/ocpi"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+func SyncTokens(ctx context.Context, ocpiApi ocpi.Api, runEvery time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync tokens")
+			return
+		case <-time.After(runEvery):
+			slog.Info("pulling tokens from ocpi parties")
+			err := ocpiApi.SyncTokens(ctx)
+			if err != nil {
+				slog.Error("sync tokens", slog.String("err", err.Error()))
+			}
+		}
+	}
+}
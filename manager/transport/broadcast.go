@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import "context"
+
+// Broadcaster is implemented by an Emitter that can send a message to all the
+// manager instances rather than to the gateway. It is used to deliver the
+// response to a call to the manager instance that made the call when several
+// manager instances share the messages received from the gateway.
+type Broadcaster interface {
+	// Broadcast sends a message, received from the charge station identified by the
+	// chargeStationId using a specific ocppVersion, to all the manager instances.
+	Broadcast(ctx context.Context, ocppVersion OcppVersion, chargeStationId string, message *Message) error
+}
+
+// BroadcastListener is implemented by a Listener that can receive the messages
+// sent by a Broadcaster.
+type BroadcastListener interface {
+	// ConnectBroadcast establishes a connection to the broker and subscribes to
+	// receive the messages broadcast for all charge stations (for a specific
+	// OcppVersion). Every connected BroadcastListener receives each message.
+	ConnectBroadcast(ctx context.Context, ocppVersion OcppVersion, handler MessageHandler) (Connection, error)
+}
@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inprocess provides support for exchanging messages with a
+// gateway that runs in the same process as the manager, without a broker
+package inprocess
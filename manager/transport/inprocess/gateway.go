@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inprocess
+
+import (
+	"context"
+	"github.com/zynka-tech/zynka-csms/gateway/ocpp"
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+	gwtransport "github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+)
+
+// Gateway is the gateway side of a Transport: it implements the gateway's
+// transport.Emitter and transport.Listener so it can be given to the
+// gateway's websocket handler.
+type Gateway struct {
+	t *Transport
+}
+
+// Gateway returns the side of the Transport that is used by the gateway.
+func (t *Transport) Gateway() *Gateway {
+	return &Gateway{t: t}
+}
+
+// Emit delivers the message from the charge station to the manager.
+func (g *Gateway) Emit(ctx context.Context, ocppVersion gwtransport.OcppVersion, chargeStationId string, message *pipe.GatewayMessage) error {
+	return g.t.receive(ctx, ocppVersion, chargeStationId, toManagerMessage(message))
+}
+
+// Connect delivers the messages from the manager for the charge station to the
+// handler. A charge station that reconnects replaces the handler of its
+// previous connection.
+func (g *Gateway) Connect(_ context.Context, ocppVersion gwtransport.OcppVersion, chargeStationId string, handler gwtransport.MessageHandler) (gwtransport.Connection, error) {
+	key := routeKey(string(ocppVersion), &chargeStationId)
+	r := &gatewayRoute{handler: handler}
+
+	g.t.mu.Lock()
+	defer g.t.mu.Unlock()
+	g.t.gatewayRoutes[key] = r
+
+	return &connection{disconnect: func() {
+		g.t.mu.Lock()
+		defer g.t.mu.Unlock()
+		if g.t.gatewayRoutes[key] == r {
+			delete(g.t.gatewayRoutes, key)
+		}
+	}}, nil
+}
+
+func toManagerMessage(msg *pipe.GatewayMessage) *transport.Message {
+	return &transport.Message{
+		MessageType:      transport.MessageType(msg.MessageType),
+		Action:           msg.Action,
+		MessageId:        msg.MessageId,
+		RequestPayload:   msg.RequestPayload,
+		ResponsePayload:  msg.ResponsePayload,
+		ErrorCode:        transport.ErrorCode(msg.ErrorCode),
+		ErrorDescription: msg.ErrorDescription,
+		State:            msg.State,
+		Event:            toManagerEvent(msg.Event),
+	}
+}
+
+func toManagerEvent(event *pipe.ConnectionEvent) *transport.ConnectionEvent {
+	if event == nil {
+		return nil
+	}
+	return &transport.ConnectionEvent{
+		Type:            transport.ConnectionEventType(event.Type),
+		GatewayId:       event.GatewayId,
+		ConnectionId:    event.ConnectionId,
+		Protocol:        event.Protocol,
+		RemoteAddr:      event.RemoteAddr,
+		SecurityProfile: event.SecurityProfile,
+		CertificateHash: event.CertificateHash,
+		Timestamp:       event.Timestamp,
+	}
+}
+
+func toGatewayMessage(ctx context.Context, msg *transport.Message) *pipe.GatewayMessage {
+	return &pipe.GatewayMessage{
+		Context:          ctx,
+		MessageType:      ocpp.MessageType(msg.MessageType),
+		Action:           msg.Action,
+		MessageId:        msg.MessageId,
+		RequestPayload:   msg.RequestPayload,
+		ResponsePayload:  msg.ResponsePayload,
+		ErrorCode:        ocpp.ErrorCode(msg.ErrorCode),
+		ErrorDescription: msg.ErrorDescription,
+		State:            msg.State,
+	}
+}
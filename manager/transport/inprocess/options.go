@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inprocess
+
+import "go.opentelemetry.io/otel/trace"
+
+type Opt func(t *Transport)
+
+func WithOtelTracer(tracer trace.Tracer) Opt {
+	return func(t *Transport) {
+		t.tracer = tracer
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inprocess
+
+import (
+	"context"
+	"fmt"
+	gwtransport "github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/exp/slog"
+	"strings"
+	"sync"
+)
+
+// Transport is an in-memory implementation of transport.Emitter and
+// transport.Listener that exchanges messages with a gateway running in the
+// same process. The gateway side of the Transport is returned by Gateway.
+//
+// Messages are delivered synchronously to the handler that is connected for
+// the OCPP version and charge station. As with a broker, messages for a charge
+// station that is not connected are discarded.
+type Transport struct {
+	tracer trace.Tracer
+
+	mu sync.Mutex
+	// managerRoutes holds the manager's handlers keyed by OCPP version, for
+	// all charge stations, or by OCPP version and charge station id
+	managerRoutes map[string]*managerRoute
+	// gatewayRoutes holds the handlers of the charge stations connected to
+	// the gateway keyed by OCPP version and charge station id
+	gatewayRoutes map[string]*gatewayRoute
+}
+
+type managerRoute struct {
+	handler transport.MessageHandler
+}
+
+type gatewayRoute struct {
+	handler gwtransport.MessageHandler
+}
+
+func NewTransport(opts ...Opt) *Transport {
+	t := &Transport{
+		managerRoutes: make(map[string]*managerRoute),
+		gatewayRoutes: make(map[string]*gatewayRoute),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.tracer == nil {
+		t.tracer = noop.NewTracerProvider().Tracer("")
+	}
+	return t
+}
+
+func routeKey(ocppVersion string, chargeStationId *string) string {
+	if chargeStationId == nil {
+		return ocppVersion
+	}
+	return ocppVersion + "/" + *chargeStationId
+}
+
+// Emit delivers the message to the handler of the charge station connected to
+// the gateway.
+func (t *Transport) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	t.mu.Lock()
+	r := t.gatewayRoutes[routeKey(string(ocppVersion), &chargeStationId)]
+	t.mu.Unlock()
+	if r == nil {
+		slog.Warn("discarding message for charge station that is not connected",
+			"chargeStationId", chargeStationId, "ocppVersion", ocppVersion, "action", message.Action)
+		return nil
+	}
+
+	r.handler.Handle(ctx, chargeStationId, toGatewayMessage(ctx, message))
+	return nil
+}
+
+// Connect delivers the messages from either a specific charge station or all
+// charge stations to the handler. A later connection for the same charge
+// stations replaces the earlier one.
+func (t *Transport) Connect(_ context.Context, ocppVersion transport.OcppVersion, chargeStationId *string, handler transport.MessageHandler) (transport.Connection, error) {
+	key := routeKey(string(ocppVersion), chargeStationId)
+	r := &managerRoute{handler: handler}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.managerRoutes[key] = r
+
+	return &connection{disconnect: func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.managerRoutes[key] == r {
+			delete(t.managerRoutes, key)
+		}
+	}}, nil
+}
+
+// receive delivers a message from the gateway to the manager's handlers
+func (t *Transport) receive(ctx context.Context, ocppVersion gwtransport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	t.mu.Lock()
+	routes := make([]*managerRoute, 0, 2)
+	for _, key := range []string{routeKey(string(ocppVersion), nil), routeKey(string(ocppVersion), &chargeStationId)} {
+		if r := t.managerRoutes[key]; r != nil {
+			routes = append(routes, r)
+		}
+	}
+	t.mu.Unlock()
+	if len(routes) == 0 {
+		return fmt.Errorf("no handler connected for %s", ocppVersion)
+	}
+
+	version, _ := strings.CutPrefix(string(ocppVersion), "ocpp")
+	newCtx, span := t.tracer.Start(ctx,
+		fmt.Sprintf("%s receive", ocppVersion),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingOperationKey.String("receive"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+			attribute.String("ocpp.version", version),
+			attribute.String(fmt.Sprintf("%s.action", message.MessageType), message.Action),
+		))
+	defer span.End()
+
+	if message.MessageType == transport.MessageTypeCallError {
+		span.SetAttributes(
+			attribute.String(fmt.Sprintf("%s.code", message.MessageType), string(message.ErrorCode)),
+			attribute.String(fmt.Sprintf("%s.description", message.MessageType), message.ErrorDescription))
+	}
+
+	for _, r := range routes {
+		r.handler.Handle(newCtx, chargeStationId, message)
+	}
+	return nil
+}
+
+type connection struct {
+	once       sync.Once
+	disconnect func()
+}
+
+func (c *connection) Disconnect(context.Context) error {
+	c.once.Do(c.disconnect)
+	return nil
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inprocess_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/gateway/ocpp"
+	"github.com/zynka-tech/zynka-csms/gateway/pipe"
+	gwtransport "github.com/zynka-tech/zynka-csms/gateway/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/inprocess"
+	"testing"
+)
+
+func TestGatewayMessageIsDeliveredToManager(t *testing.T) {
+	ctx := context.Background()
+	tr := inprocess.NewTransport()
+
+	var gotCsId string
+	var got *transport.Message
+	conn, err := tr.Connect(ctx, transport.OcppVersion201, nil, transport.MessageHandlerFunc(func(_ context.Context, chargeStationId string, message *transport.Message) {
+		gotCsId = chargeStationId
+		got = message
+	}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(ctx)
+	}()
+
+	err = tr.Gateway().Emit(ctx, gwtransport.OcppVersion201, "cs001", &pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "Heartbeat",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "cs001", gotCsId)
+	require.NotNil(t, got)
+	assert.Equal(t, transport.MessageTypeCall, got.MessageType)
+	assert.Equal(t, "Heartbeat", got.Action)
+	assert.Equal(t, "1234", got.MessageId)
+	assert.JSONEq(t, `{}`, string(got.RequestPayload))
+}
+
+func TestGatewayMessageForOtherVersionIsNotDelivered(t *testing.T) {
+	ctx := context.Background()
+	tr := inprocess.NewTransport()
+
+	called := false
+	_, err := tr.Connect(ctx, transport.OcppVersion16, nil, transport.MessageHandlerFunc(func(context.Context, string, *transport.Message) {
+		called = true
+	}))
+	require.NoError(t, err)
+
+	err = tr.Gateway().Emit(ctx, gwtransport.OcppVersion201, "cs001", &pipe.GatewayMessage{
+		MessageType: ocpp.MessageTypeCall,
+		Action:      "Heartbeat",
+		MessageId:   "1234",
+	})
+	assert.Error(t, err)
+	assert.False(t, called)
+}
+
+func TestManagerMessageIsDeliveredToChargeStation(t *testing.T) {
+	ctx := context.Background()
+	tr := inprocess.NewTransport()
+
+	var got *pipe.GatewayMessage
+	conn, err := tr.Gateway().Connect(ctx, gwtransport.OcppVersion16, "cs001", gwtransport.MessageHandlerFunc(func(_ context.Context, _ string, message *pipe.GatewayMessage) {
+		got = message
+	}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(ctx)
+	}()
+
+	err = tr.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:      transport.MessageTypeCallError,
+		Action:           "Heartbeat",
+		MessageId:        "1234",
+		ErrorCode:        transport.ErrorInternalError,
+		ErrorDescription: "failed",
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, ocpp.MessageTypeCallError, got.MessageType)
+	assert.Equal(t, "Heartbeat", got.Action)
+	assert.Equal(t, "1234", got.MessageId)
+	assert.Equal(t, ocpp.ErrorInternalError, got.ErrorCode)
+	assert.Equal(t, "failed", got.ErrorDescription)
+	assert.Equal(t, ctx, got.Context)
+}
+
+func TestManagerMessageIsDiscardedAfterChargeStationDisconnects(t *testing.T) {
+	ctx := context.Background()
+	tr := inprocess.NewTransport()
+
+	called := false
+	conn, err := tr.Gateway().Connect(ctx, gwtransport.OcppVersion16, "cs001", gwtransport.MessageHandlerFunc(func(context.Context, string, *pipe.GatewayMessage) {
+		called = true
+	}))
+	require.NoError(t, err)
+	require.NoError(t, conn.Disconnect(ctx))
+
+	err = tr.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Reset",
+		MessageId:   "1234",
+	})
+	require.NoError(t, err)
+	assert.False(t, called)
+}
+
+func TestReconnectedChargeStationIsNotDisconnectedByEarlierConnection(t *testing.T) {
+	ctx := context.Background()
+	tr := inprocess.NewTransport()
+
+	first, err := tr.Gateway().Connect(ctx, gwtransport.OcppVersion16, "cs001", gwtransport.MessageHandlerFunc(func(context.Context, string, *pipe.GatewayMessage) {}))
+	require.NoError(t, err)
+
+	called := false
+	_, err = tr.Gateway().Connect(ctx, gwtransport.OcppVersion16, "cs001", gwtransport.MessageHandlerFunc(func(context.Context, string, *pipe.GatewayMessage) {
+		called = true
+	}))
+	require.NoError(t, err)
+	require.NoError(t, first.Disconnect(ctx))
+
+	err = tr.Emit(ctx, transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Reset",
+		MessageId:   "1234",
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
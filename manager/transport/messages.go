@@ -36,6 +36,9 @@ type Message struct {
 	ErrorCode        ErrorCode       `json:"error_code,omitempty"`
 	ErrorDescription string          `json:"error_description,omitempty"`
 	State            json.RawMessage `json:"state,omitempty"`
+	// Event is set, instead of the OCPP fields, when the gateway reports a
+	// change to the charge station's connection
+	Event *ConnectionEvent `json:"event,omitempty"`
 }
 
 func NewErrorMessage(action, messageId string, code ErrorCode, err error) *Message {
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/mqtt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCallResultIsDeliveredToTheManagerThatMadeTheCall(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// start the broker
+	broker, clientUrl := mqtt.NewBroker(t)
+	defer func() {
+		err := broker.Close()
+		assert.NoError(t, err)
+	}()
+	err := broker.Serve()
+	require.NoError(t, err)
+
+	// start two manager instances that share the messages from the gateway
+	var callMakers []handlers.OcppCallMaker
+	for i := 0; i < 2; i++ {
+		emitter := mqtt.NewEmitter(mqtt.WithMqttBrokerUrl[mqtt.Emitter](clientUrl))
+		pendingCalls := handlers.NewBroadcastPendingCalls(emitter.(transport.Broadcaster))
+		listener := mqtt.NewListener(mqtt.WithMqttBrokerUrl[mqtt.Listener](clientUrl))
+
+		conn, err := listener.Connect(ctx, transport.OcppVersion201, nil,
+			transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+				pendingCalls.Deliver(ctx, transport.OcppVersion201, chargeStationId, msg)
+			}))
+		require.NoError(t, err)
+		defer func() {
+			err := conn.Disconnect(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		broadcastConn, err := listener.ConnectBroadcast(ctx, transport.OcppVersion201, pendingCalls)
+		require.NoError(t, err)
+		defer func() {
+			err := broadcastConn.Disconnect(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		callMakers = append(callMakers, handlers.OcppCallMaker{
+			Emitter:     emitter,
+			OcppVersion: transport.OcppVersion201,
+			Actions: map[reflect.Type]string{
+				reflect.TypeOf(&ocpp201.ResetRequestJson{}): "Reset",
+			},
+			PendingCalls: pendingCalls,
+		})
+	}
+
+	// the gateway responds to each call
+	router := paho.NewStandardRouter()
+	router.RegisterHandler("cs/out/ocpp2.0.1/cs001", func(mqttMsg *paho.Publish) {
+		var msg transport.Message
+		err := json.Unmarshal(mqttMsg.Payload, &msg)
+		if !assert.NoError(t, err) {
+			return
+		}
+		publishMessage(t, ctx, broker, transport.Message{
+			MessageType:     transport.MessageTypeCallResult,
+			Action:          msg.Action,
+			MessageId:       msg.MessageId,
+			RequestPayload:  msg.RequestPayload,
+			ResponsePayload: []byte(`{"status":"Accepted"}`),
+		})
+	})
+	gatewayConn := listenForMessageSentByManager(t, ctx, clientUrl, router)
+	defer func() {
+		err := gatewayConn.Disconnect(context.Background())
+		assert.NoError(t, err)
+	}()
+
+	// the responses are shared between the instances so, without the
+	// broadcast, some of the calls made by the first instance would not
+	// receive their response
+	for i := 0; i < 10; i++ {
+		callCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		var resp ocpp201.ResetResponseJson
+		err := callMakers[0].Call(callCtx, "cs001", &ocpp201.ResetRequestJson{Type: ocpp201.ResetEnumTypeImmediate}, &resp)
+		cancel()
+		require.NoError(t, err)
+		assert.Equal(t, ocpp201.ResetStatusEnumTypeAccepted, resp.Status)
+	}
+}
@@ -46,14 +46,24 @@ func NewEmitter(opts ...Opt[Emitter]) transport.Emitter {
 }
 
 func (e *Emitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
-	topic := fmt.Sprintf("%s/out/%s/%s", e.mqttPrefix, ocppVersion, chargeStationId)
+	return e.publish(ctx, "out", ocppVersion, chargeStationId, message)
+}
+
+// Broadcast publishes the message on the topic <prefix>/results/<ocpp-version>/<cs-id>,
+// which every manager instance subscribes to using Listener.ConnectBroadcast.
+func (e *Emitter) Broadcast(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	return e.publish(ctx, "results", ocppVersion, chargeStationId, message)
+}
+
+func (e *Emitter) publish(ctx context.Context, direction string, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	topic := fmt.Sprintf("%s/%s/%s/%s", e.mqttPrefix, direction, ocppVersion, chargeStationId)
 	payload, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("marshalling response of type %s: %v", message.Action, err)
 	}
 
 	newCtx, span := e.tracer.Start(ctx,
-		fmt.Sprintf("%s/out/%s/# publish", e.mqttPrefix, ocppVersion),
+		fmt.Sprintf("%s/%s/%s/# publish", e.mqttPrefix, direction, ocppVersion),
 		trace.WithSpanKind(trace.SpanKindProducer),
 		trace.WithAttributes(
 			semconv.MessagingSystem("mqtt"),
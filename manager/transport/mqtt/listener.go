@@ -66,6 +66,22 @@ func ensureListenerDefaults(l *Listener) {
 }
 
 func (l *Listener) Connect(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId *string, handler transport.MessageHandler) (transport.Connection, error) {
+	var topic string
+	if chargeStationId != nil {
+		topic = fmt.Sprintf("%s/in/%s/%s", l.mqttPrefix, ocppVersion, *chargeStationId)
+	} else {
+		topic = fmt.Sprintf("$share/%s/%s/in/%s/#", l.mqttGroup, l.mqttPrefix, ocppVersion)
+	}
+	return l.connect(ctx, ocppVersion, topic, handler)
+}
+
+// ConnectBroadcast subscribes to the messages published by Emitter.Broadcast.
+// The subscription is not shared so every manager instance receives them.
+func (l *Listener) ConnectBroadcast(ctx context.Context, ocppVersion transport.OcppVersion, handler transport.MessageHandler) (transport.Connection, error) {
+	return l.connect(ctx, ocppVersion, fmt.Sprintf("%s/results/%s/#", l.mqttPrefix, ocppVersion), handler)
+}
+
+func (l *Listener) connect(ctx context.Context, ocppVersion transport.OcppVersion, topic string, handler transport.MessageHandler) (transport.Connection, error) {
 	var err error
 
 	ctx, cancel := context.WithTimeout(ctx, l.mqttConnectTimeout)
@@ -75,13 +91,6 @@ func (l *Listener) Connect(ctx context.Context, ocppVersion transport.OcppVersio
 
 	readyCh := make(chan struct{})
 
-	var topic string
-	if chargeStationId != nil {
-		topic = fmt.Sprintf("%s/in/%s/%s", l.mqttPrefix, ocppVersion, *chargeStationId)
-	} else {
-		topic = fmt.Sprintf("$share/%s/%s/in/%s/#", l.mqttGroup, l.mqttPrefix, ocppVersion)
-	}
-
 	conn := new(connection)
 	mqttRouter := paho.NewStandardRouter()
 	conn.mqttConn, err = autopaho.NewConnection(context.Background(), autopaho.ClientConfig{
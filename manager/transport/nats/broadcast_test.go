@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/handlers"
+	"github.com/zynka-tech/zynka-csms/manager/ocpp/ocpp201"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/nats"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCallResultIsDeliveredToTheManagerThatMadeTheCall(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	// start two manager instances that share the messages from the gateway
+	var callMakers []handlers.OcppCallMaker
+	for i := 0; i < 2; i++ {
+		emitter := nats.NewEmitter(nats.WithNatsUrl[nats.Emitter](clientUrl))
+		pendingCalls := handlers.NewBroadcastPendingCalls(emitter.(transport.Broadcaster))
+		listener := nats.NewListener(nats.WithNatsUrl[nats.Listener](clientUrl))
+
+		conn, err := listener.Connect(ctx, transport.OcppVersion201, nil,
+			transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+				pendingCalls.Deliver(ctx, transport.OcppVersion201, chargeStationId, msg)
+			}))
+		require.NoError(t, err)
+		defer func() {
+			err := conn.Disconnect(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		broadcastConn, err := listener.ConnectBroadcast(ctx, transport.OcppVersion201, pendingCalls)
+		require.NoError(t, err)
+		defer func() {
+			err := broadcastConn.Disconnect(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		callMakers = append(callMakers, handlers.OcppCallMaker{
+			Emitter:     emitter,
+			OcppVersion: transport.OcppVersion201,
+			Actions: map[reflect.Type]string{
+				reflect.TypeOf(&ocpp201.ResetRequestJson{}): "Reset",
+			},
+			PendingCalls: pendingCalls,
+		})
+	}
+
+	// the gateway responds to each call
+	gatewayConn, err := natsclient.Connect(clientUrl.String())
+	require.NoError(t, err)
+	defer gatewayConn.Close()
+	js, err := jetstream.New(gatewayConn)
+	require.NoError(t, err)
+	_, err = gatewayConn.Subscribe("cs.out.ocpp201.cs001", func(natsMsg *natsclient.Msg) {
+		var msg transport.Message
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		if !assert.NoError(t, err) {
+			return
+		}
+		result, err := json.Marshal(transport.Message{
+			MessageType:     transport.MessageTypeCallResult,
+			Action:          msg.Action,
+			MessageId:       msg.MessageId,
+			RequestPayload:  msg.RequestPayload,
+			ResponsePayload: []byte(`{"status":"Accepted"}`),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = js.Publish(ctx, "cs.in.ocpp201.cs001", result)
+		assert.NoError(t, err)
+	})
+	require.NoError(t, err)
+	require.NoError(t, gatewayConn.Flush())
+
+	// the responses are shared between the instances so, without the
+	// broadcast, some of the calls made by the first instance would not
+	// receive their response
+	for i := 0; i < 10; i++ {
+		callCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		var resp ocpp201.ResetResponseJson
+		err := callMakers[0].Call(callCtx, "cs001", &ocpp201.ResetRequestJson{Type: ocpp201.ResetEnumTypeImmediate}, &resp)
+		cancel()
+		require.NoError(t, err)
+		assert.Equal(t, ocpp201.ResetStatusEnumTypeAccepted, resp.Status)
+	}
+}
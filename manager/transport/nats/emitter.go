@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"sync"
+)
+
+// Emitter is an implementation of transport.Emitter that uses NATS
+// JetStream as the transport.
+//
+// Messages are published on a subject that is composed of a number of
+// elements: <prefix>.out.<ocpp-version>.<cs-id>, where the ocpp-version
+// has its periods removed, e.g. ocpp201. The messages are retained by the
+// <prefix>_out stream, which the Emitter creates if it does not exist.
+// If not configured the default prefix is `cs`.
+//
+// The Emitter defaults to connecting to a server on 127.0.0.1:4222.
+type Emitter struct {
+	sync.Mutex
+	connectionDetails
+	tracer trace.Tracer
+	conn   *nats.Conn
+	js     jetstream.JetStream
+}
+
+func NewEmitter(opts ...Opt[Emitter]) transport.Emitter {
+	e := new(Emitter)
+	for _, opt := range opts {
+		opt(e)
+	}
+	ensureEmitterDefaults(e)
+	return e
+}
+
+func (e *Emitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	subj, err := subject(e.natsPrefix, "out", ocppVersion, chargeStationId)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling response of type %s: %v", message.Action, err)
+	}
+
+	newCtx, span := e.tracer.Start(ctx,
+		fmt.Sprintf("%s publish", getSubjectPattern(subj)),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(payload)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+			attribute.String(getActionName(message), message.Action),
+		))
+	defer span.End()
+
+	header := nats.Header{}
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(header))
+
+	err = e.ensureConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %v", err)
+	}
+
+	_, err = e.js.PublishMsg(newCtx, &nats.Msg{
+		Subject: subj,
+		Data:    payload,
+		Header:  header,
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %v", subj, err)
+	}
+	return nil
+}
+
+// Broadcast publishes the message on the subject <prefix>.results.<ocpp-version>.<cs-id>,
+// which every manager instance subscribes to using Listener.ConnectBroadcast.
+// The message is not retained by a stream: it is only delivered to the
+// instances that are connected.
+func (e *Emitter) Broadcast(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	subj, err := subject(e.natsPrefix, "results", ocppVersion, chargeStationId)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling response of type %s: %v", message.Action, err)
+	}
+
+	newCtx, span := e.tracer.Start(ctx,
+		fmt.Sprintf("%s publish", getSubjectPattern(subj)),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(payload)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+			attribute.String(getActionName(message), message.Action),
+		))
+	defer span.End()
+
+	header := nats.Header{}
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(header))
+
+	err = e.ensureConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %v", err)
+	}
+
+	err = e.conn.PublishMsg(&nats.Msg{
+		Subject: subj,
+		Data:    payload,
+		Header:  header,
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %v", subj, err)
+	}
+	return nil
+}
+
+func getActionName(msg *transport.Message) string {
+	switch msg.MessageType {
+	case transport.MessageTypeCall:
+		return "call.action"
+	case transport.MessageTypeCallResult:
+		return "call_result.action"
+	default:
+		return "call_error.action"
+	}
+}
+
+func ensureEmitterDefaults(e *Emitter) {
+	ensureConnectionDefaults(&e.connectionDetails)
+	if e.tracer == nil {
+		e.tracer = noop.NewTracerProvider().Tracer("")
+	}
+}
+
+func (e *Emitter) ensureConnection(ctx context.Context) error {
+	e.Lock()
+	defer e.Unlock()
+	if e.conn == nil {
+		conn, js, err := e.connect("manager-emit")
+		if err != nil {
+			return err
+		}
+
+		_, err = e.ensureStream(ctx, js, "out")
+		if err != nil {
+			conn.Close()
+			return err
+		}
+
+		e.conn = conn
+		e.js = js
+	}
+	return nil
+}
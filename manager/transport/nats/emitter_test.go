@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/nats"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestEmitterSendsOcpp201Message(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	emitter := nats.NewEmitter(
+		nats.WithNatsUrl[nats.Emitter](clientUrl),
+		nats.WithNatsPrefix[nats.Emitter]("cs"))
+
+	msg := transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"requestedMessage":"Heartbeat"}`),
+	}
+	err := emitter.Emit(ctx, transport.OcppVersion201, "cs001", &msg)
+	require.NoError(t, err)
+
+	natsMsg := readMessageSentByManager(t, ctx, clientUrl)
+	assert.Equal(t, "cs.out.ocpp201.cs001", natsMsg.Subject())
+	var got transport.Message
+	err = json.Unmarshal(natsMsg.Data(), &got)
+	require.NoError(t, err, "payload is not the expected message type")
+	assert.Equal(t, transport.MessageTypeCall, got.MessageType)
+	assert.Equal(t, "TriggerMessage", got.Action)
+	assert.Equal(t, "1234", got.MessageId)
+	assert.JSONEq(t, `{"requestedMessage":"Heartbeat"}`, string(got.RequestPayload))
+}
+
+func TestEmitterSendsOcpp16Message(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	emitter := nats.NewEmitter(nats.WithNatsUrl[nats.Emitter](clientUrl))
+
+	msg := transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"requestedMessage":"Heartbeat"}`),
+	}
+	err := emitter.Emit(ctx, transport.OcppVersion16, "cs001", &msg)
+	require.NoError(t, err)
+
+	natsMsg := readMessageSentByManager(t, ctx, clientUrl)
+	assert.Equal(t, "cs.out.ocpp16.cs001", natsMsg.Subject())
+}
+
+func TestEmitterEscapesChargeStationIdInSubject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	emitter := nats.NewEmitter(nats.WithNatsUrl[nats.Emitter](clientUrl))
+
+	err := emitter.Emit(ctx, transport.OcppVersion201, "cs.>%1", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "TriggerMessage",
+		MessageId:   "1234",
+	})
+	require.NoError(t, err)
+
+	natsMsg := readMessageSentByManager(t, ctx, clientUrl)
+	assert.Equal(t, "cs.out.ocpp201.cs%2E%3E%251", natsMsg.Subject())
+}
+
+func TestEmitterRejectsEmptyChargeStationId(t *testing.T) {
+	_, clientUrl := nats.NewServer(t)
+
+	emitter := nats.NewEmitter(nats.WithNatsUrl[nats.Emitter](clientUrl))
+
+	err := emitter.Emit(context.Background(), transport.OcppVersion201, "", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "TriggerMessage",
+		MessageId:   "1234",
+	})
+	assert.ErrorContains(t, err, "cannot be empty")
+}
+
+func TestEmitterAddsTraceHeaders(t *testing.T) {
+	tracer, _ := testutil.GetTracer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	emitter := nats.NewEmitter(
+		nats.WithNatsUrl[nats.Emitter](clientUrl),
+		nats.WithOtelTracer[nats.Emitter](tracer))
+
+	err := emitter.Emit(ctx, transport.OcppVersion201, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"requestedMessage":"Heartbeat"}`),
+	})
+	require.NoError(t, err)
+
+	natsMsg := readMessageSentByManager(t, ctx, clientUrl)
+	assert.NotEmpty(t, natsMsg.Headers().Get("Traceparent"))
+}
+
+func readMessageSentByManager(t *testing.T, ctx context.Context, clientUrl *url.URL) jetstream.Msg {
+	conn, err := natsclient.Connect(clientUrl.String())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+
+	consumer, err := js.OrderedConsumer(ctx, "cs_out", jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+
+	msg, err := consumer.Next(jetstream.FetchMaxWait(5 * time.Second))
+	require.NoError(t, err)
+
+	return msg
+}
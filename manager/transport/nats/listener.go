@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/exp/slog"
+	"strings"
+)
+
+// Listener is an implementation of transport.Listener that uses NATS
+// JetStream as the transport.
+//
+// Messages are consumed from the <prefix>_in stream, which the Listener
+// creates if it does not exist. When listening to all charge stations the
+// Listener uses a durable consumer, named after the group and the OCPP
+// version, that is shared by all the Listeners in the group: each message is
+// delivered to one of them and messages published whilst no Listener is
+// connected are delivered once one connects.
+type Listener struct {
+	connectionDetails
+	natsGroup string
+	tracer    trace.Tracer
+}
+
+func NewListener(opts ...Opt[Listener]) *Listener {
+	l := new(Listener)
+	for _, opt := range opts {
+		opt(l)
+	}
+	ensureListenerDefaults(l)
+	return l
+}
+
+func ensureListenerDefaults(l *Listener) {
+	ensureConnectionDefaults(&l.connectionDetails)
+	if l.natsGroup == "" {
+		l.natsGroup = "manager"
+	}
+	if l.tracer == nil {
+		l.tracer = noop.NewTracerProvider().Tracer("")
+	}
+}
+
+func (l *Listener) Connect(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId *string, handler transport.MessageHandler) (transport.Connection, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.natsConnectTimeout)
+	defer cancel()
+
+	natsConn, js, err := l.connect(l.natsGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &connection{natsConn: natsConn}
+	consumer, ack, err := l.consumer(ctx, js, ocppVersion, chargeStationId)
+	if err != nil {
+		_ = conn.Disconnect(ctx)
+		return nil, err
+	}
+
+	conn.consumeCtx, err = consumer.Consume(func(natsMsg jetstream.Msg) {
+		l.handle(ocppVersion, natsMsg.Subject(), natsMsg.Data(), natsMsg.Headers(), handler)
+		if ack {
+			err := natsMsg.Ack()
+			if err != nil {
+				slog.Warn("failed to acknowledge message", "subject", natsMsg.Subject(), "error", err)
+			}
+		}
+	})
+	if err != nil {
+		_ = conn.Disconnect(ctx)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ConnectBroadcast subscribes to the messages published by Emitter.Broadcast.
+// The subscription is not part of a queue group so every manager instance
+// receives them.
+func (l *Listener) ConnectBroadcast(ctx context.Context, ocppVersion transport.OcppVersion, handler transport.MessageHandler) (transport.Connection, error) {
+	natsConn, _, err := l.connect(l.natsGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &connection{natsConn: natsConn}
+	subj := fmt.Sprintf("%s.results.%s.*", l.natsPrefix, subjectVersion(ocppVersion))
+	_, err = natsConn.Subscribe(subj, func(natsMsg *nats.Msg) {
+		l.handle(ocppVersion, natsMsg.Subject, natsMsg.Data, natsMsg.Header, handler)
+	})
+	if err == nil {
+		// make sure the server has processed the subscription before returning
+		err = natsConn.FlushWithContext(ctx)
+	}
+	if err != nil {
+		_ = conn.Disconnect(ctx)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// consumer returns the consumer for the charge station's messages and whether
+// the messages must be acknowledged: messages for a specific charge station are
+// delivered to this Listener alone from when it connects.
+func (l *Listener) consumer(ctx context.Context, js jetstream.JetStream, ocppVersion transport.OcppVersion, chargeStationId *string) (jetstream.Consumer, bool, error) {
+	stream, err := l.ensureStream(ctx, js, "in")
+	if err != nil {
+		return nil, false, err
+	}
+
+	if chargeStationId != nil {
+		subj, err := subject(l.natsPrefix, "in", ocppVersion, *chargeStationId)
+		if err != nil {
+			return nil, false, err
+		}
+		consumer, err := stream.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{
+			FilterSubjects: []string{subj},
+			DeliverPolicy:  jetstream.DeliverNewPolicy,
+		})
+		return consumer, false, err
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       fmt.Sprintf("%s-%s", l.natsGroup, subjectVersion(ocppVersion)),
+		FilterSubject: fmt.Sprintf("%s.in.%s.>", l.natsPrefix, subjectVersion(ocppVersion)),
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+	})
+	return consumer, true, err
+}
+
+func (l *Listener) handle(ocppVersion transport.OcppVersion, subj string, data []byte, headers nats.Header, handler transport.MessageHandler) {
+	// extract trace id
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(headers))
+
+	// create span
+	newCtx, span := l.tracer.Start(ctx,
+		fmt.Sprintf("%s receive", getSubjectPattern(subj)),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingConsumerID(l.natsGroup),
+			semconv.MessagingMessagePayloadSizeBytes(len(data)),
+			semconv.MessagingOperationKey.String("receive"),
+		))
+	defer span.End()
+
+	// determine charge station id
+	chargeStationId := chargeStationIdFromSubject(subj)
+
+	// unmarshal the message
+	var msg transport.Message
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "unable to unmarshal message")
+		slog.Warn("unable to unmarshal message", "err", err)
+		return
+	}
+
+	// add additional span attributes
+	version, _ := strings.CutPrefix(string(ocppVersion), "ocpp")
+	span.SetAttributes(
+		attribute.String("csId", chargeStationId),
+		attribute.String("ocpp.version", version),
+		attribute.String(fmt.Sprintf("%s.action", msg.MessageType), msg.Action),
+		semconv.MessagingMessageConversationID(msg.MessageId),
+	)
+
+	if msg.MessageType == transport.MessageTypeCallError {
+		span.SetAttributes(
+			attribute.String(fmt.Sprintf("%s.code", msg.MessageType), string(msg.ErrorCode)),
+			attribute.String(fmt.Sprintf("%s.description", msg.MessageType), msg.ErrorDescription))
+	}
+
+	// execute the handler
+	handler.Handle(newCtx, chargeStationId, &msg)
+}
+
+type connection struct {
+	natsConn   *nats.Conn
+	consumeCtx jetstream.ConsumeContext
+}
+
+func (c *connection) Disconnect(ctx context.Context) error {
+	if c.consumeCtx != nil {
+		c.consumeCtx.Stop()
+		c.consumeCtx = nil
+	}
+	if c.natsConn != nil {
+		c.natsConn.Close()
+		c.natsConn = nil
+	}
+	return nil
+}
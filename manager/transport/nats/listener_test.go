@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/testutil"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"github.com/zynka-tech/zynka-csms/manager/transport/nats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestListenerProcessesMessagesReceivedFromTheServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	receivedMsgCh := make(chan *transport.Message, 1)
+	handler := func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+		assert.Equal(t, "cs001", chargeStationId)
+		receivedMsgCh <- msg
+	}
+
+	listener := nats.NewListener(nats.WithNatsUrl[nats.Listener](clientUrl))
+	conn, err := listener.Connect(ctx, transport.OcppVersion201, nil, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+
+	publishMessage(t, ctx, clientUrl, "cs.in.ocpp201.cs001", transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Test",
+		MessageId:      "my-message-id",
+		RequestPayload: json.RawMessage(`{"someKey":"someValue"}`),
+	})
+
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case msg := <-receivedMsgCh:
+		assert.Equal(t, "Test", msg.Action)
+		assert.Equal(t, "my-message-id", msg.MessageId)
+	}
+}
+
+func TestListenerUnescapesChargeStationIdFromSubject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	receivedCh := make(chan string, 1)
+	handler := func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+		receivedCh <- chargeStationId
+	}
+
+	listener := nats.NewListener(nats.WithNatsUrl[nats.Listener](clientUrl))
+	chargeStationId := "cs.>%1"
+	conn, err := listener.Connect(ctx, transport.OcppVersion201, &chargeStationId, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+
+	publishMessage(t, ctx, clientUrl, "cs.in.ocpp201.cs%2E%3E%251", transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Test",
+		MessageId:      "my-message-id",
+		RequestPayload: json.RawMessage(`{}`),
+	})
+
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case received := <-receivedCh:
+		assert.Equal(t, "cs.>%1", received)
+	}
+}
+
+func TestListenerDeliversMessagesPublishedWhilstDisconnected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	receivedMsgCh := make(chan *transport.Message, 1)
+	handler := func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+		receivedMsgCh <- msg
+	}
+
+	// the first connection creates the durable consumer
+	listener := nats.NewListener(nats.WithNatsUrl[nats.Listener](clientUrl))
+	conn, err := listener.Connect(ctx, transport.OcppVersion201, nil, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	err = conn.Disconnect(ctx)
+	require.NoError(t, err)
+
+	publishMessage(t, ctx, clientUrl, "cs.in.ocpp201.cs001", transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Test",
+		MessageId:   "my-message-id",
+	})
+
+	conn, err = listener.Connect(ctx, transport.OcppVersion201, nil, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case msg := <-receivedMsgCh:
+		assert.Equal(t, "my-message-id", msg.MessageId)
+	}
+}
+
+func TestListenerForChargeStationOnlyReceivesItsMessages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, clientUrl := nats.NewServer(t)
+
+	receivedMsgCh := make(chan string, 2)
+	handler := func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+		receivedMsgCh <- chargeStationId
+	}
+
+	chargeStationId := "cs002"
+	listener := nats.NewListener(nats.WithNatsUrl[nats.Listener](clientUrl))
+	conn, err := listener.Connect(ctx, transport.OcppVersion16, &chargeStationId, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+
+	msg := transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Test",
+		MessageId:   "my-message-id",
+	}
+	publishMessage(t, ctx, clientUrl, "cs.in.ocpp16.cs001", msg)
+	publishMessage(t, ctx, clientUrl, "cs.in.ocpp16.cs002", msg)
+
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case got := <-receivedMsgCh:
+		assert.Equal(t, "cs002", got)
+	}
+}
+
+func TestListenerAddsTraceInformation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tracer, exporter := testutil.GetTracer()
+
+	_, clientUrl := nats.NewServer(t)
+
+	receivedMsgCh := make(chan struct{})
+	handler := func(ctx context.Context, chargeStationId string, msg *transport.Message) {
+		receivedMsgCh <- struct{}{}
+	}
+
+	listener := nats.NewListener(
+		nats.WithNatsUrl[nats.Listener](clientUrl),
+		nats.WithOtelTracer[nats.Listener](tracer))
+	conn, err := listener.Connect(ctx, transport.OcppVersion201, nil, transport.MessageHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		err := conn.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+
+	newCtx, span := tracer.Start(ctx, "test span")
+	defer span.End()
+	publishMessage(t, newCtx, clientUrl, "cs.in.ocpp201.cs001", transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Test",
+		MessageId:      "my-message-id",
+		RequestPayload: json.RawMessage(`{"someKey":"someValue"}`),
+	})
+
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case <-receivedMsgCh:
+		// the span ends once the handler returns
+		require.Eventually(t, func() bool {
+			return len(exporter.GetSpans()) > 0
+		}, time.Second, 10*time.Millisecond)
+		assert.True(t, exporter.GetSpans()[0].Parent.HasTraceID())
+		testutil.AssertSpan(t, &exporter.GetSpans()[0], "cs.in.ocpp201.* receive", map[string]any{
+			"messaging.system":                     "nats",
+			"messaging.operation":                  "receive",
+			"messaging.message.payload_size_bytes": 81,
+			"messaging.consumer.id":                "manager",
+			"messaging.message.conversation_id":    "my-message-id",
+			"ocpp.version":                         "2.0.1",
+			"csId":                                 "cs001",
+			"call.action":                          "Test",
+		})
+	}
+}
+
+func publishMessage(t *testing.T, ctx context.Context, clientUrl *url.URL, subject string, msg transport.Message) {
+	msgBytes, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	header := natsclient.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+
+	conn, err := natsclient.Connect(clientUrl.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+
+	_, err = js.PublishMsg(ctx, &natsclient.Msg{
+		Subject: subject,
+		Data:    msgBytes,
+		Header:  header,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"net/url"
+	"time"
+)
+
+type connectionDetails struct {
+	natsUrls              []*url.URL
+	natsPrefix            string
+	natsConnectTimeout    time.Duration
+	natsConnectRetryDelay time.Duration
+	natsStreamMaxAge      time.Duration
+}
+
+type Opt[T any] func(h *T)
+
+func WithNatsUrl[T Emitter | Listener](natsUrl *url.URL) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.natsUrls = append(x.natsUrls, natsUrl)
+		case *Listener:
+			x.natsUrls = append(x.natsUrls, natsUrl)
+		}
+	}
+}
+
+func WithNatsUrls[T Emitter | Listener](natsUrls []*url.URL) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.natsUrls = natsUrls
+		case *Listener:
+			x.natsUrls = natsUrls
+		}
+	}
+}
+
+func WithNatsPrefix[T Emitter | Listener](natsPrefix string) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.natsPrefix = natsPrefix
+		case *Listener:
+			x.natsPrefix = natsPrefix
+		}
+	}
+}
+
+func WithNatsConnectSettings[T Emitter | Listener](natsConnectTimeout, natsConnectRetryDelay time.Duration) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.natsConnectTimeout = natsConnectTimeout
+			x.natsConnectRetryDelay = natsConnectRetryDelay
+		case *Listener:
+			x.natsConnectTimeout = natsConnectTimeout
+			x.natsConnectRetryDelay = natsConnectRetryDelay
+		}
+	}
+}
+
+// WithNatsStreamMaxAge sets how long messages are retained by the streams
+// that the Emitter or Listener creates.
+func WithNatsStreamMaxAge[T Emitter | Listener](natsStreamMaxAge time.Duration) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.natsStreamMaxAge = natsStreamMaxAge
+		case *Listener:
+			x.natsStreamMaxAge = natsStreamMaxAge
+		}
+	}
+}
+
+func WithOtelTracer[T Emitter | Listener](tracer trace.Tracer) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Emitter:
+			x.tracer = tracer
+		case *Listener:
+			x.tracer = tracer
+		}
+	}
+}
+
+func WithNatsGroup[T Listener](natsGroup string) Opt[T] {
+	return func(h *T) {
+		switch x := any(h).(type) {
+		case *Listener:
+			x.natsGroup = natsGroup
+		}
+	}
+}
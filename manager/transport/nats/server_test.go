@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	natsclient "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zynka-tech/zynka-csms/manager/transport/nats"
+	"testing"
+)
+
+func TestNewServer(t *testing.T) {
+	srv, addr := nats.NewServer(t)
+	assert.True(t, srv.JetStreamEnabled())
+
+	conn, err := natsclient.Connect(addr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.True(t, conn.IsConnected())
+}
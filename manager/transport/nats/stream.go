@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/zynka-tech/zynka-csms/manager/transport"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// connect establishes a connection to the NATS servers: the connection will
+// reconnect indefinitely if it is subsequently lost.
+func (c connectionDetails) connect(name string) (*nats.Conn, jetstream.JetStream, error) {
+	servers := make([]string, len(c.natsUrls))
+	for i, u := range c.natsUrls {
+		servers[i] = u.String()
+	}
+
+	conn, err := nats.Connect(strings.Join(servers, ","),
+		nats.Name(name),
+		nats.Timeout(c.natsConnectTimeout),
+		nats.ReconnectWait(c.natsConnectRetryDelay),
+		nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, js, nil
+}
+
+// ensureStream creates the stream that holds the messages sent in a direction
+// (either "in" or "out") if it does not already exist. An existing stream is
+// left unchanged so its configuration can be managed outside the CSMS.
+func (c connectionDetails) ensureStream(ctx context.Context, js jetstream.JetStream, direction string) (jetstream.Stream, error) {
+	name := fmt.Sprintf("%s_%s", c.natsPrefix, direction)
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     name,
+		Subjects: []string{fmt.Sprintf("%s.%s.>", c.natsPrefix, direction)},
+		MaxAge:   c.natsStreamMaxAge,
+	})
+	if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		return js.Stream(ctx, name)
+	}
+	return stream, err
+}
+
+// subjectVersion returns the OCPP version as a subject token: subject tokens
+// are separated by periods so they are removed from the version.
+func subjectVersion(ocppVersion transport.OcppVersion) string {
+	return strings.ReplaceAll(string(ocppVersion), ".", "")
+}
+
+func subject(prefix, direction string, ocppVersion transport.OcppVersion, chargeStationId string) (string, error) {
+	if chargeStationId == "" {
+		return "", errors.New("charge station id cannot be empty")
+	}
+	return fmt.Sprintf("%s.%s.%s.%s", prefix, direction, subjectVersion(ocppVersion), escapeSubjectToken(chargeStationId)), nil
+}
+
+// subjectEscapedChars are the characters that cannot be used in a subject
+// token along with the escape character
+const subjectEscapedChars = "%.*> \t\r\n"
+
+// escapeSubjectToken escapes a charge station id for use as a subject token:
+// the characters that cannot be used in a token are percent encoded, as is
+// done by the gateway.
+func escapeSubjectToken(chargeStationId string) string {
+	var b strings.Builder
+	for i := 0; i < len(chargeStationId); i++ {
+		c := chargeStationId[i]
+		if strings.IndexByte(subjectEscapedChars, c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// chargeStationIdFromSubject returns the charge station id from the last token
+// of the subject
+func chargeStationIdFromSubject(subj string) string {
+	token := subj[strings.LastIndex(subj, ".")+1:]
+	chargeStationId, err := url.PathUnescape(token)
+	if err != nil {
+		return token
+	}
+	return chargeStationId
+}
+
+func getSubjectPattern(subject string) string {
+	parts := strings.Split(subject, ".")
+	parts[len(parts)-1] = "*"
+	return strings.Join(parts, ".")
+}
+
+func ensureConnectionDefaults(c *connectionDetails) {
+	if c.natsUrls == nil {
+		u, err := url.Parse("nats://127.0.0.1:4222")
+		if err != nil {
+			panic(err)
+		}
+		c.natsUrls = []*url.URL{u}
+	}
+	if c.natsPrefix == "" {
+		c.natsPrefix = "cs"
+	}
+	if c.natsConnectTimeout == 0 {
+		c.natsConnectTimeout = 10 * time.Second
+	}
+	if c.natsConnectRetryDelay == 0 {
+		c.natsConnectRetryDelay = 1 * time.Second
+	}
+	if c.natsStreamMaxAge == 0 {
+		c.natsStreamMaxAge = 1 * time.Hour
+	}
+}